@@ -848,6 +848,15 @@ func reportCrash(c context.Context, build *Build, req *dashapi.Crash) (*Bug, err
 		}
 	}
 
+	var reproSkel, dupCandidates []string
+	if save && len(req.ReproSyz) != 0 && len(bug.ReproSkeleton) == 0 {
+		reproSkel = reproSkeleton(req.ReproSyz)
+		dupCandidates, err = findDupCandidates(c, bug, reproSkel)
+		if err != nil {
+			log.Errorf(c, "%q: failed to find dup candidates: %v", bug.Title, err)
+		}
+	}
+
 	tx := func(c context.Context) error {
 		bug = new(Bug)
 		if err := db.Get(c, bugKey, bug); err != nil {
@@ -867,12 +876,20 @@ func reportCrash(c context.Context, build *Build, req *dashapi.Crash) (*Bug, err
 		if bug.HeadReproLevel < reproLevel {
 			bug.HeadReproLevel = reproLevel
 		}
+		if bug.Severity < req.Severity {
+			bug.Severity = req.Severity
+		}
 		if len(req.Report) != 0 {
 			bug.HasReport = true
 		}
 		if calculateSubsystems {
 			bug.SetAutoSubsystems(c, newSubsystems, now, getNsConfig(c, ns).Subsystems.Revision)
 		}
+		if len(reproSkel) != 0 && len(bug.ReproSkeleton) == 0 {
+			bug.ReproSkeleton = reproSkel
+			bug.ReproCallsHash = reproCallsHash(reproSkel)
+			bug.DupCandidates = dupCandidates
+		}
 		bug.increaseCrashStats(now)
 		bug.HappenedOn = mergeString(bug.HappenedOn, build.Manager)
 		// Migration of older entities (for new bugs Title is always in MergedTitles).
@@ -890,6 +907,9 @@ func reportCrash(c context.Context, build *Build, req *dashapi.Crash) (*Bug, err
 	if save {
 		purgeOldCrashes(c, bug, bugKey)
 	}
+	if err := linkDupCandidates(c, bug, dupCandidates); err != nil {
+		log.Errorf(c, "%q: failed to link dup candidates: %v", bug.Title, err)
+	}
 	return bug, nil
 }
 
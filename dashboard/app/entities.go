@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/syzkaller/dashboard/dashapi"
 	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/subsystem"
 	db "google.golang.org/appengine/v2/datastore"
 )
@@ -96,8 +97,10 @@ type Bug struct {
 	NumRepro     int64
 	// ReproLevel is the best ever found repro level for this bug.
 	// HeadReproLevel is best known repro level that still works on the HEAD commit.
-	ReproLevel      dashapi.ReproLevel
-	HeadReproLevel  dashapi.ReproLevel `datastore:"HeadReproLevel"`
+	ReproLevel     dashapi.ReproLevel
+	HeadReproLevel dashapi.ReproLevel `datastore:"HeadReproLevel"`
+	// Severity is the max estimated severity of the bug crashes.
+	Severity        crash.Severity
 	BisectCause     BisectStatus
 	BisectFix       BisectStatus
 	HasReport       bool
@@ -129,6 +132,12 @@ type Bug struct {
 	// FixCandidateJob holds the key of the latest successful cross-tree fix bisection job.
 	FixCandidateJob string
 	ReproAttempts   []BugReproAttempt
+	// ReproSkeleton is the skeleton of the first syz repro of the bug (see reproSkeleton).
+	ReproSkeleton []string `datastore:",noindex"`
+	// ReproCallsHash is the hash of the set of calls of the skeleton, used to look up dup candidates.
+	ReproCallsHash string
+	// DupCandidates are key hashes of open bugs with similar repros (likely duplicates).
+	DupCandidates []string `datastore:",noindex"`
 }
 
 type BugTreeTestInfo struct {
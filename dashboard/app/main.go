@@ -25,6 +25,7 @@ import (
 	"github.com/google/syzkaller/pkg/email"
 	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/html"
+	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/subsystem"
 	"github.com/google/syzkaller/pkg/vcs"
 	"golang.org/x/sync/errgroup"
@@ -354,6 +355,7 @@ type uiBug struct {
 	ReportedTime   time.Time
 	ClosedTime     time.Time
 	ReproLevel     dashapi.ReproLevel
+	Severity       crash.Severity
 	ReportingIndex int
 	Status         string
 	Link           string
@@ -367,6 +369,8 @@ type uiBug struct {
 	Labels         []*uiBugLabel
 	Discussions    DiscussionSummary
 	ID             string
+	// Number of open bugs with similar repros (likely duplicates).
+	DupCandidates int
 }
 
 type uiBugLabel struct {
@@ -385,6 +389,7 @@ type uiCrash struct {
 	ReproSyzLink    string
 	ReproCLink      string
 	ReproIsRevoked  bool
+	ReproIsFlaky    bool
 	ReproLogLink    string
 	MachineInfoLink string
 	Assets          []*uiAsset
@@ -432,18 +437,26 @@ type userBugFilter struct {
 	OnlyManager string // show bugs that happened ONLY on the manager
 	Labels      []string
 	NoSubsystem bool
+	Severity    crash.Severity // show bugs with at least this severity
 }
 
 func MakeBugFilter(r *http.Request) (*userBugFilter, error) {
 	if err := r.ParseForm(); err != nil {
 		return nil, err
 	}
-	return &userBugFilter{
+	filter := &userBugFilter{
 		NoSubsystem: r.FormValue("no_subsystem") != "",
 		Manager:     r.FormValue("manager"),
 		OnlyManager: r.FormValue("only_manager"),
 		Labels:      r.Form["label"],
-	}, nil
+	}
+	if name := r.FormValue("severity"); name != "" {
+		var ok bool
+		if filter.Severity, ok = crash.ParseSeverity(name); !ok {
+			return nil, fmt.Errorf("unknown severity %q", name)
+		}
+	}
+	return filter, nil
 }
 
 func (filter *userBugFilter) MatchManagerName(name string) bool {
@@ -474,6 +487,9 @@ func (filter *userBugFilter) MatchBug(bug *Bug) bool {
 	if filter.NoSubsystem && len(bug.LabelValues(SubsystemLabel)) > 0 {
 		return false
 	}
+	if bug.Severity < filter.Severity {
+		return false
+	}
 	for _, rawLabel := range filter.Labels {
 		label, value := splitLabel(rawLabel)
 		if !bug.HasLabel(label, value) {
@@ -496,7 +512,8 @@ func (filter *userBugFilter) Any() bool {
 	if filter == nil {
 		return false
 	}
-	return len(filter.Labels) > 0 || filter.OnlyManager != "" || filter.Manager != "" || filter.NoSubsystem
+	return len(filter.Labels) > 0 || filter.OnlyManager != "" || filter.Manager != "" || filter.NoSubsystem ||
+		filter.Severity != crash.UnknownSeverity
 }
 
 // handleMain serves main page.
@@ -1069,6 +1086,18 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 			Value: treeTestJobs,
 		})
 	}
+	dupCandidates, err := loadDupCandidatesUI(c, r, bug, state)
+	if err != nil {
+		return err
+	}
+	if len(dupCandidates.Bugs) > 0 {
+		sections = append(sections, &uiCollapsible{
+			Title: fmt.Sprintf("Possible duplicates (%d)", len(dupCandidates.Bugs)),
+			Show:  true,
+			Type:  sectionBugList,
+			Value: dupCandidates,
+		})
+	}
 	similar, err := loadSimilarBugsUI(c, r, bug, state)
 	if err != nil {
 		return err
@@ -1803,6 +1832,32 @@ func loadSimilarBugsUI(c context.Context, r *http.Request, bug *Bug, state *Repo
 	return group, nil
 }
 
+// loadDupCandidatesUI returns open bugs with repros similar to the bug's repro.
+func loadDupCandidatesUI(c context.Context, r *http.Request, bug *Bug, state *ReportingState) (*uiBugGroup, error) {
+	candidates, err := loadDupCandidates(c, bug)
+	if err != nil {
+		return nil, err
+	}
+	managers, err := CachedManagerList(c, bug.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	accessLevel := accessLevel(c, r)
+	var results []*uiBug
+	for _, candidate := range candidates {
+		if accessLevel < candidate.sanitizeAccess(c, accessLevel) {
+			continue
+		}
+		results = append(results, createUIBug(c, candidate, state, managers))
+	}
+	group := &uiBugGroup{
+		Now:        timeNow(c),
+		ShowStatus: true,
+		Bugs:       results,
+	}
+	return group, nil
+}
+
 func closedBugStatus(bug *Bug, bugReporting *BugReporting) string {
 	status := ""
 	switch bug.Status {
@@ -1877,6 +1932,7 @@ func createUIBug(c context.Context, bug *Bug, state *ReportingState, managers []
 		ReportedTime:   reported,
 		ClosedTime:     bug.Closed,
 		ReproLevel:     bug.ReproLevel,
+		Severity:       bug.Severity,
 		ReportingIndex: reportingIdx,
 		Status:         status,
 		Link:           bugExtLink(c, bug),
@@ -1886,6 +1942,7 @@ func createUIBug(c context.Context, bug *Bug, state *ReportingState, managers []
 		LastActivity:   bug.LastActivity,
 		Discussions:    bug.discussionSummary(),
 		ID:             bug.keyHash(c),
+		DupCandidates:  len(bug.DupCandidates),
 	}
 	for _, entry := range bug.Labels {
 		uiBug.Labels = append(uiBug.Labels, makeBugLabelUI(c, bug, entry))
@@ -2015,6 +2072,7 @@ func makeUICrash(c context.Context, crash *Crash, build *Build) *uiCrash {
 		ReproCLink:      textLink(textReproC, crash.ReproC),
 		ReproLogLink:    textLink(textReproLog, crash.ReproLog),
 		ReproIsRevoked:  crash.ReproIsRevoked,
+		ReproIsFlaky:    dashapi.CrashFlags(crash.Flags)&dashapi.CrashFlakyRepro > 0,
 		MachineInfoLink: textLink(textMachineInfo, crash.MachineInfo),
 		Assets:          makeUIAssets(build, crash, true),
 	}
@@ -0,0 +1,195 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"google.golang.org/appengine/v2"
+	db "google.golang.org/appengine/v2/datastore"
+	"google.golang.org/appengine/v2/log"
+)
+
+// Duplicate detection by repro similarity: when a bug gets its first syz repro, the repro
+// skeleton (calls with their argument structure, but without concrete values) is compared
+// with skeletons of the other open bugs in the namespace. Bugs with similar repros are
+// suggested as likely duplicates on the bug page and in the bug lists (incl. moderation).
+// Only bugs with the same set of calls in the repro are compared (looked up by ReproCallsHash),
+// and the suggestion is recorded on both bugs.
+// Nothing is done automatically, the suggestions only simplify manual dup triage.
+
+const (
+	// Minimal similarity (Jaccard index of the skeletons) to suggest a bug as a duplicate.
+	dupSimilarityThreshold = 0.7
+	maxDupCandidates       = 5
+	// Repros with fewer calls are too generic to be compared.
+	minDupSkeletonCalls = 2
+)
+
+var (
+	skeletonCallRe     = regexp.MustCompile(`^(?:r[0-9]+ = )?([a-zA-Z0-9_$]+)\((.*)\)`)
+	skeletonStringRe   = regexp.MustCompile(`(?:'|")(?:[^'"\\]|\\.)*(?:'|")`)
+	skeletonResourceRe = regexp.MustCompile(`\br[0-9]+\b`)
+	skeletonNumberRe   = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9]+)\b`)
+)
+
+// reproSkeleton returns the skeleton of a syz repro: names of all calls and
+// calls with normalized arguments (numbers, strings and resources are replaced with N, S and R).
+func reproSkeleton(repro []byte) []string {
+	seen := make(map[string]bool)
+	calls := 0
+	for _, line := range strings.Split(string(repro), "\n") {
+		match := skeletonCallRe.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		calls++
+		args := skeletonStringRe.ReplaceAllString(match[2], "S")
+		args = skeletonResourceRe.ReplaceAllString(args, "R")
+		args = skeletonNumberRe.ReplaceAllString(args, "N")
+		seen[match[1]] = true
+		seen[match[1]+"("+args+")"] = true
+	}
+	if calls < minDupSkeletonCalls {
+		return nil
+	}
+	var ret []string
+	for elem := range seen {
+		ret = append(ret, elem)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// reproCallsHash returns the hash of the set of calls of the skeleton.
+func reproCallsHash(skeleton []string) string {
+	var calls []string
+	for _, elem := range skeleton {
+		if !strings.Contains(elem, "(") {
+			calls = append(calls, elem)
+		}
+	}
+	return hash.String([]byte(strings.Join(calls, " ")))
+}
+
+func skeletonSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool)
+	for _, elem := range a {
+		set[elem] = true
+	}
+	common := 0
+	for _, elem := range b {
+		if set[elem] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// findDupCandidates returns key hashes of the open bugs in the namespace with repros
+// similar to the skeleton, the most similar first.
+func findDupCandidates(c context.Context, bug *Bug, skeleton []string) ([]string, error) {
+	if len(skeleton) == 0 {
+		return nil, nil
+	}
+	var bugs []*Bug
+	_, err := db.NewQuery("Bug").
+		Filter("Namespace=", bug.Namespace).
+		Filter("Status=", BugStatusOpen).
+		Filter("ReproCallsHash=", reproCallsHash(skeleton)).
+		GetAll(c, &bugs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open bugs: %w", err)
+	}
+	type candidate struct {
+		id         string
+		similarity float64
+	}
+	var candidates []candidate
+	self := bug.keyHash(c)
+	for _, other := range bugs {
+		id := other.keyHash(c)
+		if id == self {
+			continue
+		}
+		similarity := skeletonSimilarity(skeleton, other.ReproSkeleton)
+		if similarity >= dupSimilarityThreshold {
+			candidates = append(candidates, candidate{id, similarity})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+	var ret []string
+	for i := 0; i < len(candidates) && i < maxDupCandidates; i++ {
+		ret = append(ret, candidates[i].id)
+	}
+	return ret, nil
+}
+
+// linkDupCandidates adds the bug to the dup candidates of its candidates,
+// so that the suggestion is visible on both bugs.
+func linkDupCandidates(c context.Context, bug *Bug, candidates []string) error {
+	self := bug.keyHash(c)
+	for _, id := range candidates {
+		tx := func(c context.Context) error {
+			candidate := new(Bug)
+			key := db.NewKey(c, "Bug", id, 0, nil)
+			if err := db.Get(c, key, candidate); err != nil {
+				return fmt.Errorf("failed to get bug: %w", err)
+			}
+			if len(candidate.DupCandidates) >= maxDupCandidates ||
+				stringInList(candidate.DupCandidates, self) {
+				return nil
+			}
+			candidate.DupCandidates = append(candidate.DupCandidates, self)
+			if _, err := db.Put(c, key, candidate); err != nil {
+				return fmt.Errorf("failed to put bug: %w", err)
+			}
+			return nil
+		}
+		if err := db.RunInTransaction(c, tx, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDupCandidates loads the suggested duplicates of the bug that are still open.
+func loadDupCandidates(c context.Context, bug *Bug) ([]*Bug, error) {
+	if len(bug.DupCandidates) == 0 {
+		return nil, nil
+	}
+	var keys []*db.Key
+	for _, id := range bug.DupCandidates {
+		keys = append(keys, db.NewKey(c, "Bug", id, 0, nil))
+	}
+	bugs := make([]*Bug, len(keys))
+	err := db.GetMulti(c, keys, bugs)
+	var errs appengine.MultiError
+	if err != nil && !errors.As(err, &errs) {
+		return nil, fmt.Errorf("failed to load dup candidates: %w", err)
+	}
+	var ret []*Bug
+	for i, candidate := range bugs {
+		// Some of the bugs may be deleted, just skip them.
+		if errs != nil && errs[i] != nil {
+			log.Warningf(c, "failed to load dup candidate %v of %q: %v", keys[i], bug.Title, errs[i])
+			continue
+		}
+		if candidate.Status == BugStatusOpen {
+			ret = append(ret, candidate)
+		}
+	}
+	return ret, nil
+}
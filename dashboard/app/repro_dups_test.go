@@ -0,0 +1,81 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDupRepro1 = `# {Threaded:false Repeat:false}
+r0 = socket$inet6_tcp(0xa, 0x1, 0x0)
+setsockopt$inet6_tcp_int(r0, 0x6, 0x13, &(0x7f0000000000)=0x1, 0x4)
+connect$inet6(r0, &(0x7f0000000040)={0xa, 0x4e20, 0x0, @loopback}, 0x1c)
+`
+	// Same calls and argument structure as testDupRepro1, but different values.
+	testDupRepro2 = `r1 = socket$inet6_tcp(0xa, 0x1, 0x0)
+setsockopt$inet6_tcp_int(r1, 0x6, 0x1f, &(0x7f0000000100)=0x5, 0x4)
+connect$inet6(r1, &(0x7f0000000200)={0xa, 0x4e21, 0x2, @loopback}, 0x1c)
+`
+	testDupRepro3 = `r0 = openat$kvm(0xffffffffffffff9c, &(0x7f0000000000)='/dev/kvm\x00', 0x0, 0x0)
+r1 = ioctl$KVM_CREATE_VM(r0, 0xae01, 0x0)
+ioctl$KVM_CREATE_VCPU(r1, 0xae41, 0x0)
+`
+)
+
+func TestReproSkeleton(t *testing.T) {
+	skel1 := reproSkeleton([]byte(testDupRepro1))
+	skel2 := reproSkeleton([]byte(testDupRepro2))
+	skel3 := reproSkeleton([]byte(testDupRepro3))
+	assert.Equal(t, []string{
+		"connect$inet6",
+		"connect$inet6(R, &(N)={N, N, N, @loopback}, N)",
+		"setsockopt$inet6_tcp_int",
+		"setsockopt$inet6_tcp_int(R, N, N, &(N)=N, N)",
+		"socket$inet6_tcp",
+		"socket$inet6_tcp(N, N, N)",
+	}, skel1)
+	assert.Contains(t, skel3, "openat$kvm(N, &(N)=S, N, N)")
+	assert.Equal(t, reproCallsHash(skel1), reproCallsHash(skel2))
+	assert.NotEqual(t, reproCallsHash(skel1), reproCallsHash(skel3))
+	assert.Equal(t, 1.0, skeletonSimilarity(skel1, skel2))
+	assert.Equal(t, 0.0, skeletonSimilarity(skel1, skel3))
+	assert.Equal(t, 0.0, skeletonSimilarity(skel1, nil))
+	// Single-call repros are too generic.
+	assert.Nil(t, reproSkeleton([]byte("syncfs(0x1)")))
+}
+
+func TestReproDupCandidates(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+	crash1 := testCrash(build, 1)
+	crash1.ReproSyz = []byte(testDupRepro1)
+	c.client.ReportCrash(crash1)
+	crash2 := testCrash(build, 2)
+	crash2.ReproSyz = []byte(testDupRepro2)
+	c.client.ReportCrash(crash2)
+	crash3 := testCrash(build, 3)
+	crash3.ReproSyz = []byte(testDupRepro3)
+	c.client.ReportCrash(crash3)
+
+	bugs := make(map[string]*Bug)
+	for _, rep := range c.client.pollBugs(3) {
+		bug, _, _ := c.loadBug(rep.ID)
+		bugs[bug.Title] = bug
+	}
+	// The suggestion is linked from both bugs.
+	c.expectEQ(bugs["title1"].DupCandidates, []string{bugs["title2"].keyHash(c.ctx)})
+	c.expectEQ(bugs["title2"].DupCandidates, []string{bugs["title1"].keyHash(c.ctx)})
+	c.expectEQ(len(bugs["title3"].DupCandidates), 0)
+
+	reply, err := c.AuthGET(AccessAdmin, "/bug?extid="+bugs["title2"].Reporting[0].ID)
+	c.expectOK(err)
+	c.expectTrue(bytes.Contains(reply, []byte("Possible duplicates (1)")))
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/auth"
+	"github.com/google/syzkaller/pkg/report/crash"
 )
 
 type Dashboard struct {
@@ -298,6 +299,8 @@ type CrashFlags int64
 
 const (
 	CrashUnderStrace CrashFlags = 1 << iota
+	// The reproducer triggered the crash only in some of the reruns.
+	CrashFlakyRepro
 )
 
 // Crash describes a single kernel crash (potentially with repro).
@@ -312,6 +315,7 @@ type Crash struct {
 	Log         []byte
 	Flags       CrashFlags
 	Report      []byte
+	Severity    crash.Severity // estimated security impact (see report.EstimateSeverity)
 	MachineInfo []byte
 	Assets      []NewAsset
 	GuiltyFiles []string
@@ -483,6 +487,8 @@ const (
 	KernelImage        AssetType = "kernel_image"
 	HTMLCoverageReport AssetType = "html_coverage_report"
 	MountInRepro       AssetType = "mount_in_repro"
+	ReproSyz           AssetType = "repro_syz"
+	ReproC             AssetType = "repro_c"
 )
 
 type BisectResult struct {
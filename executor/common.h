@@ -86,6 +86,11 @@ static unsigned long long procid;
 static __thread int clone_ongoing;
 static __thread int skip_segv;
 static __thread jmp_buf segv_env;
+#if SYZ_EXECUTOR && SYZ_EXECUTOR_USES_FORK_SERVER
+// The top-level executor process does not execute programs itself,
+// so any fault in it is a bug in the executor.
+static int executor_main_pid;
+#endif
 
 static void segv_handler(int sig, siginfo_t* info, void* ctx)
 {
@@ -124,6 +129,15 @@ static void segv_handler(int sig, siginfo_t* info, void* ctx)
 		debug("SIGSEGV on %p, skipping\n", (void*)addr);
 		_longjmp(segv_env, 1);
 	}
+#if SYZ_EXECUTOR && SYZ_EXECUTOR_USES_FORK_SERVER
+	if (!skip && info->si_code > 0 && getpid() == executor_main_pid) {
+		// Restore the default action and re-execute the faulting instruction,
+		// so that the executor crash produces a core dump.
+		debug("SIGSEGV on %p in the executor\n", (void*)addr);
+		signal(sig, SIG_DFL);
+		return;
+	}
+#endif
 	debug("SIGSEGV on %p, exiting\n", (void*)addr);
 	doexit(sig);
 }
@@ -139,6 +153,9 @@ static void install_segv_handler(void)
 	sa.sa_handler = SIG_IGN;
 	syscall(SYS_rt_sigaction, 0x20, &sa, NULL, 8);
 	syscall(SYS_rt_sigaction, 0x21, &sa, NULL, 8);
+#endif
+#if SYZ_EXECUTOR && SYZ_EXECUTOR_USES_FORK_SERVER
+	executor_main_pid = getpid();
 #endif
 	memset(&sa, 0, sizeof(sa));
 	sa.sa_sigaction = segv_handler;
@@ -176,11 +193,85 @@ static void kill_and_wait(int pid, int* status)
 #endif
 #endif
 
+#if !GOOS_linux
+#if SYZ_EXECUTOR || SYZ_PROC_ROLES
+#include <errno.h>
+
+// Process roles are supported only on linux, other OSes execute the call in the test process.
+struct role_ctx {
+	intptr_t res;
+	int err;
+};
+
+static int role_fork(struct role_ctx* ctx, int role)
+{
+	return 0;
+}
+
+static void role_exit(struct role_ctx* ctx, intptr_t res, int err)
+{
+	ctx->res = res;
+	ctx->err = err;
+}
+
+static intptr_t role_wait(struct role_ctx* ctx)
+{
+	errno = ctx->err;
+	return ctx->res;
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_TIME_JUMPS
+// Time jumps are supported only on linux, other OSes execute the call without changing the clocks.
+struct time_jump_ctx {
+	int jump;
+};
+
+static void time_jump_enter(struct time_jump_ctx* ctx, int jump)
+{
+	ctx->jump = jump;
+}
+
+static void time_jump_leave(struct time_jump_ctx* ctx)
+{
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_COMPAT_SYSCALLS
+#include <errno.h>
+
+// The compat syscall entry is supported only on linux.
+static intptr_t compat_syscall(long nr, intptr_t a0, intptr_t a1, intptr_t a2, intptr_t a3, intptr_t a4, intptr_t a5)
+{
+	errno = ENOSYS;
+	return -1;
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_URING_SYSCALLS
+#include <errno.h>
+
+// io_uring is supported only on linux.
+static intptr_t uring_syscall(long nr, intptr_t a0, intptr_t a1, intptr_t a2, intptr_t a3, intptr_t a4, intptr_t a5)
+{
+	errno = ENOSYS;
+	return -1;
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_SUSPEND
+// Suspend/resume cycles are supported only on linux.
+static void suspend_resume(int mode)
+{
+}
+#endif
+#endif
+
 #if !GOOS_windows
 #if SYZ_EXECUTOR || SYZ_THREADED || SYZ_REPEAT && SYZ_EXECUTOR_USES_FORK_SERVER || \
     __NR_syz_usb_connect || __NR_syz_usb_connect_ath9k || __NR_syz_sleep_ms ||     \
     __NR_syz_usb_control_io || __NR_syz_usb_ep_read || __NR_syz_usb_ep_write ||    \
-    __NR_syz_usb_disconnect
+    __NR_syz_usb_disconnect || __NR_syz_emit_external
 static void sleep_ms(uint64 ms)
 {
 	usleep(ms * 1000);
@@ -792,6 +883,12 @@ int main(void)
 #if SYZ_SWAP
 	setup_swap();
 #endif
+#if SYZ_HUGEPAGES
+	setup_hugepages();
+#endif
+#if SYZ_PMEM
+	setup_pmem();
+#endif
 #if SYZ_HANDLE_SEGV
 	install_segv_handler();
 #endif
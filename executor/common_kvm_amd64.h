@@ -265,6 +265,50 @@ struct kvm_opt {
 #define KVM_SETUP_VIRT86 (1 << 4)
 #define KVM_SETUP_SMM (1 << 5)
 #define KVM_SETUP_VM (1 << 6)
+#define KVM_SETUP_SVM (1 << 7)
+
+static void setup_vmcb_segment(char* vmcb, uint64 off, uint16 sel, uint16 attrib, uint32 limit, uint64 base)
+{
+	*(uint16*)(vmcb + off) = sel;
+	*(uint16*)(vmcb + off + 2) = attrib;
+	*(uint32*)(vmcb + off + 4) = limit;
+	*(uint64*)(vmcb + off + 8) = base;
+}
+
+// setup_vmcb fills VMCB for a nested guest that passes VMRUN consistency checks
+// and runs the user code in 64-bit mode. CR0/CR3/CR4/EFER are copied by kvm_asm64_init_svm.
+static void setup_vmcb(char* host_mem)
+{
+	char* vmcb = host_mem + ADDR_VAR_VMCB;
+	memset(vmcb, 0, 4 << 10);
+	memset(host_mem + ADDR_VAR_VM_HSAVE, 0, 4 << 10);
+
+	// Intercept HLT and SHUTDOWN, so that L1 gets control back when the user code finishes.
+	*(uint32*)(vmcb + VMCB_INTERCEPT_MISC1) = (1 << 24) | (1u << 31);
+	// Intercept VMRUN (mandatory) and VMMCALL.
+	*(uint32*)(vmcb + VMCB_INTERCEPT_MISC2) = (1 << 0) | (1 << 1);
+	*(uint32*)(vmcb + VMCB_GUEST_ASID) = 1;
+
+	// Attributes are descriptor bits 40-47 and 52-55.
+	const uint16 attrib_cs = 0xa9b, attrib_ds = 0xc93, attrib_tss = 0x8b;
+	setup_vmcb_segment(vmcb, VMCB_SAVE_ES, SEL_DS64, attrib_ds, 0xffffffff, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_CS, SEL_CS64, attrib_cs, 0xffffffff, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_SS, SEL_DS64, attrib_ds, 0xffffffff, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_DS, SEL_DS64, attrib_ds, 0xffffffff, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_FS, SEL_DS64, attrib_ds, 0xffffffff, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_GS, SEL_DS64, attrib_ds, 0xffffffff, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_GDTR, 0, 0, 0x1fff, ADDR_GDT);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_LDTR, 0, 0, 0, 0);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_IDTR, 0, 0, 0x1fff, ADDR_VAR_IDT);
+	setup_vmcb_segment(vmcb, VMCB_SAVE_TR, SEL_TSS64, attrib_tss, 0x1fff, ADDR_VAR_TSS64);
+
+	*(uint64*)(vmcb + VMCB_SAVE_DR7) = 0x400;
+	*(uint64*)(vmcb + VMCB_SAVE_DR6) = 0xffff0ff0;
+	*(uint64*)(vmcb + VMCB_SAVE_RFLAGS) = 1 << 1;
+	*(uint64*)(vmcb + VMCB_SAVE_RIP) = ADDR_VAR_USER_CODE;
+	*(uint64*)(vmcb + VMCB_SAVE_RSP) = ADDR_STACK0;
+	*(uint64*)(vmcb + VMCB_SAVE_G_PAT) = 0x0007040600070406ULL;
+}
 
 // syz_kvm_setup_cpu(fd fd_kvmvm, cpufd fd_kvmcpu, usermem vma[24], text ptr[in, array[kvm_text, 1]], ntext len[text], flags flags[kvm_setup_flags], opts ptr[in, array[kvm_setup_opt, 0:2]], nopt len[opts])
 static volatile long syz_kvm_setup_cpu(volatile long a0, volatile long a1, volatile long a2, volatile long a3, volatile long a4, volatile long a5, volatile long a6, volatile long a7)
@@ -613,6 +657,11 @@ static volatile long syz_kvm_setup_cpu(volatile long a0, volatile long a1, volat
 
 			text_prefix = kvm_asm64_init_vm;
 			text_prefix_size = sizeof(kvm_asm64_init_vm) - 1;
+		} else if (flags & KVM_SETUP_SVM) {
+			setup_vmcb(host_mem);
+
+			text_prefix = kvm_asm64_init_svm;
+			text_prefix_size = sizeof(kvm_asm64_init_svm) - 1;
 		} else if (flags & KVM_SETUP_CPL3) {
 			text_prefix = kvm_asm64_cpl3;
 			text_prefix_size = sizeof(kvm_asm64_cpl3) - 1;
@@ -16,6 +16,51 @@ struct kvm_opt {
 	uint64 val;
 };
 
+struct kvm_smccc_call {
+	uint64 conduit;
+	uint64 func;
+	uint64 args[3];
+};
+
+#define KVM_SMCCC_MAX_CALLS 8
+// Guest physical address that is not backed by memory, accesses to it exit to the host with KVM_EXIT_MMIO.
+#define KVM_ARM64_EXIT_ADDR 0xdead0000
+
+// Emits instructions that load 64-bit val into register xN.
+static int kvm_arm64_emit_mov64(uint32* insns, int reg, uint64 val)
+{
+	for (int hw = 0; hw < 4; hw++) {
+		uint32 imm16 = (val >> (hw * 16)) & 0xffff;
+		// MOVZ for the first halfword, MOVK for the rest.
+		uint32 op = hw == 0 ? 0xd2800000 : 0xf2800000;
+		insns[hw] = op | (hw << 21) | (imm16 << 5) | reg;
+	}
+	return 4;
+}
+
+// Generates guest code that makes the given SMCCC calls (HVC or SMC depending on conduit),
+// and then exits to the host with a write to KVM_ARM64_EXIT_ADDR.
+static void kvm_arm64_emit_smccc_calls(uint32* insns, const struct kvm_smccc_call* calls, uintptr_t ncalls)
+{
+	const uint32 hvc0 = 0xd4000002;
+	const uint32 smc0 = 0xd4000003;
+	const uint32 str_x0_x0 = 0xf9000000;
+	const uint32 b_self = 0x14000000;
+	if (ncalls > KVM_SMCCC_MAX_CALLS)
+		ncalls = KVM_SMCCC_MAX_CALLS;
+	int n = 0;
+	for (uintptr_t i = 0; i < ncalls; i++) {
+		n += kvm_arm64_emit_mov64(&insns[n], 0, calls[i].func);
+		for (int arg = 0; arg < 3; arg++)
+			n += kvm_arm64_emit_mov64(&insns[n], arg + 1, calls[i].args[arg]);
+		insns[n++] = calls[i].conduit ? smc0 : hvc0;
+	}
+	n += kvm_arm64_emit_mov64(&insns[n], 0, KVM_ARM64_EXIT_ADDR);
+	insns[n++] = str_x0_x0;
+	// Don't run into the rest of the memory if the host resumes the guest after the exit.
+	insns[n++] = b_self;
+}
+
 // syz_kvm_setup_cpu(fd fd_kvmvm, cpufd fd_kvmcpu, usermem vma[24], text ptr[in, array[kvm_text, 1]], ntext len[text], flags flags[kvm_setup_flags], opts ptr[in, array[kvm_setup_opt, 0:2]], nopt len[opts])
 static volatile long syz_kvm_setup_cpu(volatile long a0, volatile long a1, volatile long a2, volatile long a3, volatile long a4, volatile long a5, volatile long a6, volatile long a7)
 {
@@ -39,7 +84,6 @@ static volatile long syz_kvm_setup_cpu(volatile long a0, volatile long a1, volat
 	int text_type = text_array_ptr[0].typ;
 	const void* text = text_array_ptr[0].text;
 	int text_size = text_array_ptr[0].size;
-	(void)text_type;
 	(void)opt_array_ptr;
 
 	uint32 features = 0;
@@ -69,9 +113,18 @@ static volatile long syz_kvm_setup_cpu(volatile long a0, volatile long a1, volat
 	// Queries KVM for preferred CPU target type.
 	ioctl(vmfd, KVM_ARM_PREFERRED_TARGET, &init);
 	init.features[0] = features;
+	// PSCI 0.2+ function IDs are handled by KVM only if the vcpu is created with PSCI 0.2 support.
+	if (text_type == 1)
+		init.features[0] |= 1 << KVM_ARM_VCPU_PSCI_0_2;
 	// Use the modified struct kvm_vcpu_init to initialize the virtual CPU.
 	ioctl(cpufd, KVM_ARM_VCPU_INIT, &init);
 
+	if (text_type == 1) {
+		// Text is an array of SMCCC calls (and size is the number of calls),
+		// generate guest code that issues them. The guest starts at address 0.
+		kvm_arm64_emit_smccc_calls((uint32*)host_mem, (const struct kvm_smccc_call*)text, text_size);
+		return 0;
+	}
 	if (text_size > 1000)
 		text_size = 1000;
 	memcpy(host_mem, text, text_size);
@@ -74,7 +74,7 @@ static int event_timedwait(event_t* ev, uint64 timeout)
     SYZ_SANDBOX_SETUID || SYZ_SANDBOX_NAMESPACE || SYZ_SANDBOX_ANDROID ||               \
     SYZ_FAULT || SYZ_LEAK || SYZ_BINFMT_MISC || SYZ_SYSCTL ||                           \
     ((__NR_syz_usb_connect || __NR_syz_usb_connect_ath9k) && USB_DEBUG) ||              \
-    __NR_syz_usbip_server_init
+    __NR_syz_usbip_server_init || SYZ_SUSPEND
 #include <errno.h>
 #include <fcntl.h>
 #include <stdarg.h>
@@ -109,7 +109,8 @@ static bool write_file(const char* file, const char* what, ...)
 #endif
 
 #if SYZ_EXECUTOR || SYZ_NET_DEVICES || SYZ_NET_INJECTION || SYZ_DEVLINK_PCI || SYZ_WIFI || SYZ_802154 || \
-    __NR_syz_genetlink_get_family_id || __NR_syz_80211_inject_frame || __NR_syz_80211_join_ibss || SYZ_NIC_VF
+    __NR_syz_genetlink_get_family_id || __NR_syz_80211_inject_frame || __NR_syz_80211_join_ibss || SYZ_NIC_VF || \
+    __NR_syz_wireguard_pair
 #include <arpa/inet.h>
 #include <errno.h>
 #include <net/if.h>
@@ -158,7 +159,7 @@ static void netlink_attr(struct nlmsg* nlmsg, int typ,
 	nlmsg->pos += NLMSG_ALIGN(attr->nla_len);
 }
 
-#if SYZ_EXECUTOR || SYZ_NET_DEVICES || SYZ_802154
+#if SYZ_EXECUTOR || SYZ_NET_DEVICES || SYZ_802154 || __NR_syz_wireguard_pair
 static void netlink_nest(struct nlmsg* nlmsg, int typ)
 {
 	struct nlattr* attr = (struct nlattr*)nlmsg->pos;
@@ -173,7 +174,7 @@ static void netlink_done(struct nlmsg* nlmsg)
 	attr->nla_len = nlmsg->pos - (char*)attr;
 }
 
-#if SYZ_EXECUTOR || SYZ_NIC_VF
+#if SYZ_EXECUTOR || (SYZ_NIC_VF && (SYZ_NET_DEVICES || SYZ_802154))
 #include <ifaddrs.h>
 #include <linux/ethtool.h>
 #include <linux/sockios.h>
@@ -369,7 +370,11 @@ static int netlink_next_msg(struct nlmsg* nlmsg, unsigned int offset,
 }
 #endif
 
-#if SYZ_EXECUTOR || SYZ_NET_DEVICES || SYZ_802154
+#if SYZ_EXECUTOR || SYZ_NET_DEVICES || SYZ_802154 || __NR_syz_wireguard_pair
+// If set, devices created with netlink_add_device_impl get consecutive ifindexes starting from this value
+// (see initialize_netdevices). Failed devices consume their indexes as well.
+static int netdev_next_ifindex;
+
 static void netlink_add_device_impl(struct nlmsg* nlmsg, const char* type,
 				    const char* name, bool up)
 {
@@ -377,6 +382,8 @@ static void netlink_add_device_impl(struct nlmsg* nlmsg, const char* type,
 	memset(&hdr, 0, sizeof(hdr));
 	if (up)
 		hdr.ifi_flags = hdr.ifi_change = IFF_UP;
+	if (netdev_next_ifindex)
+		hdr.ifi_index = netdev_next_ifindex++;
 	netlink_init(nlmsg, RTM_NEWLINK, NLM_F_EXCL | NLM_F_CREATE, &hdr, sizeof(hdr));
 	if (name)
 		netlink_attr(nlmsg, IFLA_IFNAME, name, strlen(name));
@@ -403,6 +410,8 @@ static void netlink_add_veth(struct nlmsg* nlmsg, int sock, const char* name,
 	netlink_add_device_impl(nlmsg, "veth", name, false);
 	netlink_nest(nlmsg, IFLA_INFO_DATA);
 	netlink_nest(nlmsg, VETH_INFO_PEER);
+	if (netdev_next_ifindex)
+		((struct ifinfomsg*)nlmsg->pos)->ifi_index = netdev_next_ifindex++;
 	nlmsg->pos += sizeof(struct ifinfomsg);
 	netlink_attr(nlmsg, IFLA_IFNAME, peer, strlen(peer));
 	netlink_done(nlmsg);
@@ -736,7 +745,8 @@ static void initialize_tun(void)
 }
 #endif
 
-#if SYZ_EXECUTOR || __NR_syz_init_net_socket || SYZ_DEVLINK_PCI || __NR_syz_socket_connect_nvme_tcp
+#if SYZ_EXECUTOR || __NR_syz_init_net_socket || SYZ_DEVLINK_PCI || __NR_syz_socket_connect_nvme_tcp || \
+    __NR_syz_emit_external
 const int kInitNetNsFd = 201; // see kMaxFd
 #endif
 
@@ -1168,40 +1178,7 @@ static int runcmdline(char* cmdline)
 }
 #endif
 
-#if SYZ_EXECUTOR || SYZ_NET_DEVICES
-#include <arpa/inet.h>
-#include <errno.h>
-#include <fcntl.h>
-#include <net/if.h>
-#include <net/if_arp.h>
-#include <stdarg.h>
-#include <stdbool.h>
-#include <sys/ioctl.h>
-#include <sys/stat.h>
-#include <sys/uio.h>
-
-#include <linux/if_ether.h>
-#include <linux/if_tun.h>
-#include <linux/ip.h>
-#include <linux/tcp.h>
-
-// Addresses are chosen to be in the same subnet as tun addresses.
-#define DEV_IPV4 "172.20.20.%d"
-#define DEV_IPV6 "fe80::%02x"
-#define DEV_MAC 0x00aaaaaaaaaa
-
-static void netdevsim_add(unsigned int addr, unsigned int port_count)
-{
-	// These devices are sticky and are not deleted on net namespace destruction.
-	// So try to delete the previous version of the device.
-	write_file("/sys/bus/netdevsim/del_device", "%u", addr);
-	if (write_file("/sys/bus/netdevsim/new_device", "%u %u", addr, port_count)) {
-		char buf[32];
-		snprintf(buf, sizeof(buf), "netdevsim%d", addr);
-		initialize_devlink_ports("netdevsim", buf, "netdevsim");
-	}
-}
-
+#if SYZ_EXECUTOR || SYZ_NET_DEVICES || __NR_syz_wireguard_pair
 #define WG_GENL_NAME "wireguard"
 enum wg_cmd {
 	WG_CMD_GET_DEVICE,
@@ -1237,6 +1214,41 @@ enum wgallowedip_attribute {
 	WGALLOWEDIP_A_IPADDR,
 	WGALLOWEDIP_A_CIDR_MASK,
 };
+#endif
+
+#if SYZ_EXECUTOR || SYZ_NET_DEVICES
+#include <arpa/inet.h>
+#include <errno.h>
+#include <fcntl.h>
+#include <net/if.h>
+#include <net/if_arp.h>
+#include <stdarg.h>
+#include <stdbool.h>
+#include <sys/ioctl.h>
+#include <sys/stat.h>
+#include <sys/uio.h>
+
+#include <linux/if_ether.h>
+#include <linux/if_tun.h>
+#include <linux/ip.h>
+#include <linux/tcp.h>
+
+// Addresses are chosen to be in the same subnet as tun addresses.
+#define DEV_IPV4 "172.20.20.%d"
+#define DEV_IPV6 "fe80::%02x"
+#define DEV_MAC 0x00aaaaaaaaaa
+
+static void netdevsim_add(unsigned int addr, unsigned int port_count)
+{
+	// These devices are sticky and are not deleted on net namespace destruction.
+	// So try to delete the previous version of the device.
+	write_file("/sys/bus/netdevsim/del_device", "%u", addr);
+	if (write_file("/sys/bus/netdevsim/new_device", "%u %u", addr, port_count)) {
+		char buf[32];
+		snprintf(buf, sizeof(buf), "netdevsim%d", addr);
+		initialize_devlink_ports("netdevsim", buf, "netdevsim");
+	}
+}
 
 static void netlink_wireguard_setup(void)
 {
@@ -1475,6 +1487,76 @@ static void netlink_nicvf_setup(void)
 }
 #endif // SYZ_NIC_VF
 
+const int kNetDevIfindexBase = 100;
+
+#if SYZ_EXECUTOR
+#define NET_CAPTURE_DEV "syz_capture"
+
+static bool net_capture_enabled(void)
+{
+	const char* procs = getenv("SYZKALLER_NET_CAPTURE_PROCS");
+	if (procs == NULL || procs[0] == 0)
+		return true;
+	for (const char* pos = procs; *pos;) {
+		char* end = NULL;
+		long proc = strtol(pos, &end, 10);
+		if (end == pos)
+			break;
+		if (proc == (long)procid)
+			return true;
+		pos = *end == ',' ? end + 1 : end;
+	}
+	return false;
+}
+
+// Bridges the test net namespace to the capture interface in the init net namespace
+// (SYZKALLER_NET_CAPTURE env var, SYZKALLER_NET_CAPTURE_PROCS optionally limits the procs),
+// so that the namespace traffic can be captured outside of the machine. A veth pair connects
+// syz_capture device (a port of bridge0) in the test namespace with syz_capture<procid> device
+// in the init namespace, which is a port of the syz_capture bridge together with the capture interface.
+// MAC address of syz_capture is deterministic and identifies the proc in the capture.
+static void initialize_net_capture(int sock)
+{
+	const char* iface = getenv("SYZKALLER_NET_CAPTURE");
+	if (iface == NULL || iface[0] == 0 || !net_capture_enabled())
+		return;
+	char port[IFNAMSIZ], peer[IFNAMSIZ];
+	snprintf(port, sizeof(port), "syz_capture%d", (int)procid);
+	snprintf(peer, sizeof(peer), "syz_capns%d", (int)procid);
+	int netns = open("/proc/self/ns/net", O_RDONLY);
+	if (netns == -1)
+		fail("open(/proc/self/ns/net) failed");
+	if (setns(kInitNetNsFd, 0))
+		fail("set_ns(init_netns_fd) failed");
+	int init_sock = socket(AF_NETLINK, SOCK_RAW, NETLINK_ROUTE);
+	if (init_sock == -1)
+		fail("socket(AF_NETLINK) failed");
+	// The bridge and the capture interface may be already set up by other procs.
+	netlink_add_device(&nlmsg, init_sock, "bridge", NET_CAPTURE_DEV);
+	netlink_device_change(&nlmsg, init_sock, NET_CAPTURE_DEV, true, 0, 0, 0, NULL);
+	netlink_device_change(&nlmsg, init_sock, iface, true, NET_CAPTURE_DEV, 0, 0, NULL);
+	netlink_add_veth(&nlmsg, init_sock, port, peer);
+	netlink_device_change(&nlmsg, init_sock, port, true, NET_CAPTURE_DEV, 0, 0, NULL);
+	// Move the other end to the test namespace.
+	struct ifinfomsg hdr;
+	memset(&hdr, 0, sizeof(hdr));
+	hdr.ifi_index = if_nametoindex(peer);
+	netlink_init(&nlmsg, RTM_NEWLINK, 0, &hdr, sizeof(hdr));
+	netlink_attr(&nlmsg, IFLA_IFNAME, NET_CAPTURE_DEV, strlen(NET_CAPTURE_DEV));
+	netlink_attr(&nlmsg, IFLA_NET_NS_FD, &netns, sizeof(netns));
+	if (netlink_send(&nlmsg, init_sock) < 0) {
+		debug("netlink: moving %s to test netns: %s\n", peer, strerror(errno));
+	}
+	close(init_sock);
+	if (setns(netns, 0))
+		fail("set_ns(this_netns_fd) failed");
+	close(netns);
+	uint64 macaddr = DEV_MAC + ((0xc0ull + procid) << 40);
+	netlink_device_change(&nlmsg, sock, NET_CAPTURE_DEV, true, "bridge0", &macaddr, ETH_ALEN, NULL);
+	debug("bridged net namespace to %s\n", iface);
+}
+#endif
+
 // We test in a separate namespace, which does not have any network devices initially (even lo).
 // Create/up as many as we can.
 static void initialize_netdevices(void)
@@ -1598,6 +1680,10 @@ static void initialize_netdevices(void)
 	int sock = socket(AF_NETLINK, SOCK_RAW, NETLINK_ROUTE);
 	if (sock == -1)
 		fail("socket(AF_NETLINK) failed");
+	// Assign fixed ifindexes to the devices, so that interface numbering in the namespace
+	// does not depend on the device types supported by the kernel (failed devices keep their indexes).
+	// Fallback tunnel devices (sit0, gre0, etc) are created by the kernel with lower indexes.
+	netdev_next_ifindex = kNetDevIfindexBase;
 	unsigned i;
 	for (i = 0; i < sizeof(devtypes) / sizeof(devtypes[0]); i++)
 		netlink_add_device(&nlmsg, sock, devtypes[i].type, devtypes[i].dev);
@@ -1657,6 +1743,7 @@ static void initialize_netdevices(void)
 		fail("geneve1 inet_pton failed");
 	netlink_add_geneve(&nlmsg, sock, "geneve0", 0, &geneve_addr4, 0);
 	netlink_add_geneve(&nlmsg, sock, "geneve1", 1, 0, &geneve_addr6);
+	netdev_next_ifindex = 0;
 
 	netdevsim_add((int)procid, 4); // Number of port is in sync with value in sys/linux/socket_netlink_generic_devlink.txt
 
@@ -1679,6 +1766,9 @@ static void initialize_netdevices(void)
 		uint64 macaddr = DEV_MAC + ((i + 10ull) << 40);
 		netlink_device_change(&nlmsg, sock, devices[i].name, true, 0, &macaddr, devices[i].macsize, NULL);
 	}
+#if SYZ_EXECUTOR
+	initialize_net_capture(sock);
+#endif
 	close(sock);
 }
 
@@ -1814,7 +1904,7 @@ static long syz_emit_ethernet(volatile long a0, volatile long a1, volatile long
 }
 #endif
 
-#if SYZ_EXECUTOR || __NR_syz_io_uring_submit || __NR_syz_io_uring_complete || __NR_syz_io_uring_setup
+#if SYZ_EXECUTOR || __NR_syz_io_uring_submit || __NR_syz_io_uring_complete || __NR_syz_io_uring_setup || SYZ_URING_SYSCALLS
 
 #define SIZEOF_IO_URING_SQE 64
 #define SIZEOF_IO_URING_CQE 16
@@ -1841,7 +1931,7 @@ static long syz_emit_ethernet(volatile long a0, volatile long a1, volatile long
 #define CQ_FLAGS_OFFSET 280
 #define CQ_CQES_OFFSET 320
 
-#if SYZ_EXECUTOR || __NR_syz_io_uring_complete
+#if SYZ_EXECUTOR || __NR_syz_io_uring_complete || SYZ_URING_SYSCALLS
 
 // From linux/io_uring.h
 struct io_uring_cqe {
@@ -1850,6 +1940,10 @@ struct io_uring_cqe {
 	uint32 flags;
 };
 
+#endif
+
+#if SYZ_EXECUTOR || __NR_syz_io_uring_complete
+
 static long syz_io_uring_complete(volatile long a0)
 {
 	// syzlang: syz_io_uring_complete(ring_ptr ring_ptr)
@@ -1888,7 +1982,7 @@ static long syz_io_uring_complete(volatile long a0)
 
 #endif
 
-#if SYZ_EXECUTOR || __NR_syz_io_uring_setup
+#if SYZ_EXECUTOR || __NR_syz_io_uring_setup || SYZ_URING_SYSCALLS
 
 struct io_sqring_offsets {
 	uint32 head;
@@ -1929,6 +2023,10 @@ struct io_uring_params {
 #define IORING_SETUP_SQE128 (1U << 10)
 #define IORING_SETUP_CQE32 (1U << 11)
 
+#endif
+
+#if SYZ_EXECUTOR || __NR_syz_io_uring_setup
+
 #include <sys/mman.h>
 #include <unistd.h>
 
@@ -2009,6 +2107,302 @@ static long syz_io_uring_submit(volatile long a0, volatile long a1, volatile lon
 
 #endif
 
+#if SYZ_EXECUTOR || SYZ_URING_SYSCALLS
+#include <errno.h>
+#include <stdbool.h>
+#include <string.h>
+#include <sys/mman.h>
+#include <sys/syscall.h>
+#include <unistd.h>
+
+#define IORING_ENTER_GETEVENTS (1U << 0)
+#define IORING_FSYNC_DATASYNC (1U << 0)
+
+// From linux/io_uring.h, unions are represented by the first field.
+struct uring_sqe {
+	uint8 opcode;
+	uint8 flags;
+	uint16 ioprio;
+	int fd;
+	uint64 off;
+	uint64 addr;
+	uint32 len;
+	uint32 op_flags;
+	uint64 user_data;
+	uint16 buf_index;
+	uint16 personality;
+	int splice_fd_in;
+	uint64 addr3;
+	uint64 pad;
+};
+
+// Values of enum io_uring_op.
+enum {
+	URING_OP_READV = 1,
+	URING_OP_WRITEV = 2,
+	URING_OP_FSYNC = 3,
+	URING_OP_SYNC_FILE_RANGE = 8,
+	URING_OP_SENDMSG = 9,
+	URING_OP_RECVMSG = 10,
+	URING_OP_ACCEPT = 13,
+	URING_OP_CONNECT = 16,
+	URING_OP_FALLOCATE = 17,
+	URING_OP_OPENAT = 18,
+	URING_OP_CLOSE = 19,
+	URING_OP_STATX = 21,
+	URING_OP_READ = 22,
+	URING_OP_WRITE = 23,
+	URING_OP_FADVISE = 24,
+	URING_OP_MADVISE = 25,
+	URING_OP_OPENAT2 = 28,
+	URING_OP_EPOLL_CTL = 29,
+	URING_OP_SPLICE = 30,
+	URING_OP_TEE = 33,
+	URING_OP_SHUTDOWN = 34,
+	URING_OP_RENAMEAT = 35,
+	URING_OP_UNLINKAT = 36,
+	URING_OP_MKDIRAT = 37,
+	URING_OP_SYMLINKAT = 38,
+	URING_OP_LINKAT = 39,
+	URING_OP_FSETXATTR = 41,
+	URING_OP_SETXATTR = 42,
+	URING_OP_FGETXATTR = 43,
+	URING_OP_GETXATTR = 44,
+	URING_OP_SOCKET = 45,
+	URING_OP_FTRUNCATE = 55,
+	URING_OP_BIND = 56,
+	URING_OP_LISTEN = 57,
+};
+
+// Same as io_uring_prep_rw in liburing.
+static void uring_prep_rw(struct uring_sqe* sqe, int op, intptr_t fd, intptr_t addr, intptr_t len, intptr_t off)
+{
+	sqe->opcode = op;
+	sqe->fd = fd;
+	sqe->addr = addr;
+	sqe->len = len;
+	sqe->off = off;
+}
+
+// Splice offsets are passed by pointer to the syscall, but by value (-1 for none) to io_uring.
+static uint64 uring_splice_off(intptr_t ptr)
+{
+	return ptr ? *(uint64*)ptr : (uint64)-1;
+}
+
+// uring_prep fills in sqe with the io_uring operation equivalent to the syscall nr,
+// the arguments are laid out as liburing io_uring_prep_* helpers do.
+// Keep the list of syscalls in sync with uringCalls in sys/linux/init.go.
+// On 32-bit arches 64-bit syscall arguments are split across registers, so only 64-bit arches are supported.
+static bool uring_prep(struct uring_sqe* sqe, long nr, intptr_t a0, intptr_t a1, intptr_t a2, intptr_t a3, intptr_t a4, intptr_t a5)
+{
+#if GOARCH_amd64 || GOARCH_arm64 || GOARCH_ppc64le || GOARCH_mips64le || GOARCH_s390x || GOARCH_riscv64
+	switch (nr) {
+	case __NR_read:
+		uring_prep_rw(sqe, URING_OP_READ, a0, a1, a2, -1);
+		return true;
+	case __NR_write:
+		uring_prep_rw(sqe, URING_OP_WRITE, a0, a1, a2, -1);
+		return true;
+	case __NR_pread64:
+		uring_prep_rw(sqe, URING_OP_READ, a0, a1, a2, a3);
+		return true;
+	case __NR_pwrite64:
+		uring_prep_rw(sqe, URING_OP_WRITE, a0, a1, a2, a3);
+		return true;
+	case __NR_readv:
+		uring_prep_rw(sqe, URING_OP_READV, a0, a1, a2, -1);
+		return true;
+	case __NR_writev:
+		uring_prep_rw(sqe, URING_OP_WRITEV, a0, a1, a2, -1);
+		return true;
+	case __NR_preadv:
+	case __NR_preadv2:
+		uring_prep_rw(sqe, URING_OP_READV, a0, a1, a2, a3);
+		sqe->op_flags = nr == __NR_preadv2 ? a5 : 0;
+		return true;
+	case __NR_pwritev:
+	case __NR_pwritev2:
+		uring_prep_rw(sqe, URING_OP_WRITEV, a0, a1, a2, a3);
+		sqe->op_flags = nr == __NR_pwritev2 ? a5 : 0;
+		return true;
+	case __NR_fsync:
+	case __NR_fdatasync:
+		uring_prep_rw(sqe, URING_OP_FSYNC, a0, 0, 0, 0);
+		sqe->op_flags = nr == __NR_fdatasync ? IORING_FSYNC_DATASYNC : 0;
+		return true;
+	case __NR_sync_file_range:
+		uring_prep_rw(sqe, URING_OP_SYNC_FILE_RANGE, a0, 0, a2, a1);
+		sqe->op_flags = a3;
+		return true;
+	case __NR_fallocate:
+		uring_prep_rw(sqe, URING_OP_FALLOCATE, a0, a3, a1, a2);
+		return true;
+	case __NR_fadvise64:
+		uring_prep_rw(sqe, URING_OP_FADVISE, a0, 0, a2, a1);
+		sqe->op_flags = a3;
+		return true;
+	case __NR_ftruncate:
+		uring_prep_rw(sqe, URING_OP_FTRUNCATE, a0, 0, 0, a1);
+		return true;
+	case __NR_madvise:
+		uring_prep_rw(sqe, URING_OP_MADVISE, -1, a0, a1, 0);
+		sqe->op_flags = a2;
+		return true;
+	case __NR_openat:
+		uring_prep_rw(sqe, URING_OP_OPENAT, a0, a1, a3, 0);
+		sqe->op_flags = a2;
+		return true;
+#ifdef __NR_openat2
+	case __NR_openat2:
+		uring_prep_rw(sqe, URING_OP_OPENAT2, a0, a1, a3, a2);
+		return true;
+#endif
+	case __NR_close:
+		uring_prep_rw(sqe, URING_OP_CLOSE, a0, 0, 0, 0);
+		return true;
+	case __NR_statx:
+		uring_prep_rw(sqe, URING_OP_STATX, a0, a1, a3, a4);
+		sqe->op_flags = a2;
+		return true;
+#ifdef __NR_renameat
+	case __NR_renameat:
+		uring_prep_rw(sqe, URING_OP_RENAMEAT, a0, a1, a2, a3);
+		return true;
+#endif
+	case __NR_renameat2:
+		uring_prep_rw(sqe, URING_OP_RENAMEAT, a0, a1, a2, a3);
+		sqe->op_flags = a4;
+		return true;
+	case __NR_unlinkat:
+		uring_prep_rw(sqe, URING_OP_UNLINKAT, a0, a1, 0, 0);
+		sqe->op_flags = a2;
+		return true;
+	case __NR_mkdirat:
+		uring_prep_rw(sqe, URING_OP_MKDIRAT, a0, a1, a2, 0);
+		return true;
+	case __NR_symlinkat:
+		uring_prep_rw(sqe, URING_OP_SYMLINKAT, a1, a0, 0, a2);
+		return true;
+	case __NR_linkat:
+		uring_prep_rw(sqe, URING_OP_LINKAT, a0, a1, a2, a3);
+		sqe->op_flags = a4;
+		return true;
+	case __NR_setxattr:
+		uring_prep_rw(sqe, URING_OP_SETXATTR, 0, a1, a3, a2);
+		sqe->addr3 = a0;
+		sqe->op_flags = a4;
+		return true;
+	case __NR_fsetxattr:
+		uring_prep_rw(sqe, URING_OP_FSETXATTR, a0, a1, a3, a2);
+		sqe->op_flags = a4;
+		return true;
+	case __NR_getxattr:
+		uring_prep_rw(sqe, URING_OP_GETXATTR, 0, a1, a3, a2);
+		sqe->addr3 = a0;
+		return true;
+	case __NR_fgetxattr:
+		uring_prep_rw(sqe, URING_OP_FGETXATTR, a0, a1, a3, a2);
+		return true;
+	case __NR_splice:
+		uring_prep_rw(sqe, URING_OP_SPLICE, a2, uring_splice_off(a1), a4, uring_splice_off(a3));
+		sqe->splice_fd_in = a0;
+		sqe->op_flags = a5;
+		return true;
+	case __NR_tee:
+		uring_prep_rw(sqe, URING_OP_TEE, a1, 0, a2, 0);
+		sqe->splice_fd_in = a0;
+		sqe->op_flags = a3;
+		return true;
+	case __NR_epoll_ctl:
+		uring_prep_rw(sqe, URING_OP_EPOLL_CTL, a0, a3, a1, a2);
+		return true;
+	case __NR_socket:
+		uring_prep_rw(sqe, URING_OP_SOCKET, a0, 0, a2, a1);
+		return true;
+	case __NR_bind:
+		uring_prep_rw(sqe, URING_OP_BIND, a0, a1, 0, a2);
+		return true;
+	case __NR_listen:
+		uring_prep_rw(sqe, URING_OP_LISTEN, a0, 0, a1, 0);
+		return true;
+	case __NR_connect:
+		uring_prep_rw(sqe, URING_OP_CONNECT, a0, a1, 0, a2);
+		return true;
+	case __NR_accept:
+	case __NR_accept4:
+		uring_prep_rw(sqe, URING_OP_ACCEPT, a0, a1, 0, a2);
+		sqe->op_flags = nr == __NR_accept4 ? a3 : 0;
+		return true;
+	case __NR_sendmsg:
+		uring_prep_rw(sqe, URING_OP_SENDMSG, a0, a1, 1, 0);
+		sqe->op_flags = a2;
+		return true;
+	case __NR_recvmsg:
+		uring_prep_rw(sqe, URING_OP_RECVMSG, a0, a1, 1, 0);
+		sqe->op_flags = a2;
+		return true;
+	case __NR_shutdown:
+		uring_prep_rw(sqe, URING_OP_SHUTDOWN, a0, 0, a1, 0);
+		return true;
+	}
+#endif
+	return false;
+}
+
+// Uring syscalls (see prog.CallProps.Uring) are submitted as the equivalent IORING_OP_* operations,
+// so that the async submission paths of the same kernel functionality are exercised.
+// Every call uses a new single-entry ring and waits for the completion, so the result and errno
+// are the same as for the syscall. Like syz_io_uring_setup, this assumes IORING_FEAT_SINGLE_MMAP.
+// Calls fail with ENOSYS if there is no equivalent operation.
+static intptr_t uring_syscall(long nr, intptr_t a0, intptr_t a1, intptr_t a2, intptr_t a3, intptr_t a4, intptr_t a5)
+{
+	struct uring_sqe sqe;
+	memset(&sqe, 0, sizeof(sqe));
+	if (!uring_prep(&sqe, nr, a0, a1, a2, a3, a4, a5)) {
+		errno = ENOSYS;
+		return -1;
+	}
+	struct io_uring_params params;
+	memset(&params, 0, sizeof(params));
+	int ring = syscall(__NR_io_uring_setup, 1, &params);
+	if (ring < 0)
+		return -1;
+	uint32 sq_ring_sz = params.sq_off.array + params.sq_entries * sizeof(uint32);
+	uint32 cq_ring_sz = params.cq_off.cqes + params.cq_entries * SIZEOF_IO_URING_CQE;
+	uint32 ring_sz = sq_ring_sz > cq_ring_sz ? sq_ring_sz : cq_ring_sz;
+	char* rings = (char*)mmap(0, ring_sz, PROT_READ | PROT_WRITE, MAP_SHARED | MAP_POPULATE, ring, IORING_OFF_SQ_RING);
+	char* sqes = (char*)mmap(0, SIZEOF_IO_URING_SQE, PROT_READ | PROT_WRITE, MAP_SHARED | MAP_POPULATE, ring, IORING_OFF_SQES);
+	intptr_t res = -1;
+	int err = 0;
+	if (rings == MAP_FAILED || sqes == MAP_FAILED) {
+		err = errno;
+	} else {
+		memcpy(sqes, &sqe, sizeof(sqe));
+		*(uint32*)(rings + params.sq_off.array) = 0;
+		__atomic_store_n((uint32*)(rings + params.sq_off.tail), 1, __ATOMIC_RELEASE);
+		if (syscall(__NR_io_uring_enter, ring, 1, 1, IORING_ENTER_GETEVENTS, 0, 0) < 0) {
+			err = errno;
+		} else if (__atomic_load_n((uint32*)(rings + params.cq_off.tail), __ATOMIC_ACQUIRE) == 0) {
+			err = EAGAIN;
+		} else {
+			int cqe_res = (int)((struct io_uring_cqe*)(rings + params.cq_off.cqes))->res;
+			if (cqe_res < 0)
+				err = -cqe_res;
+			else
+				res = cqe_res;
+		}
+	}
+	if (rings != MAP_FAILED)
+		munmap(rings, ring_sz);
+	if (sqes != MAP_FAILED)
+		munmap(sqes, SIZEOF_IO_URING_SQE);
+	close(ring);
+	errno = err;
+	return res;
+}
+#endif
+
 #endif
 
 #if SYZ_EXECUTOR || __NR_syz_usbip_server_init
@@ -2458,6 +2852,44 @@ static long syz_open_pts(volatile long a0, volatile long a1)
 }
 #endif
 
+#if SYZ_EXECUTOR || __NR_syz_landlock_path
+#include <fcntl.h>
+#include <sys/stat.h>
+#include <sys/types.h>
+#include <unistd.h>
+
+// syz_landlock_path creates a small file hierarchy in the current dir (if it does not exist yet)
+// and opens one of its nodes. The fd is used as a parent fd for landlock path beneath rules,
+// so that the rules refer to the paths accessed by the other landlock-related calls.
+static long syz_landlock_path(volatile long a0, volatile long a1)
+{
+	// syz_landlock_path(node int32[0:7], flags flags[landlock_path_open_flags]) fd_landlock_path
+	static const char* const nodes[] = {
+	    "./landlock",
+	    "./landlock/file",
+	    "./landlock/fifo",
+	    "./landlock/dir",
+	    "./landlock/dir/file",
+	    "./landlock/dir/link",
+	    "./landlock/dir/sub",
+	    "./landlock/dir/sub/file",
+	};
+	mkdir("./landlock", 0777);
+	mkdir("./landlock/dir", 0777);
+	mkdir("./landlock/dir/sub", 0777);
+	const char* files[] = {"./landlock/file", "./landlock/dir/file", "./landlock/dir/sub/file"};
+	for (unsigned i = 0; i < sizeof(files) / sizeof(files[0]); i++) {
+		int fd = open(files[i], O_WRONLY | O_CREAT | O_CLOEXEC, 0777);
+		if (fd != -1)
+			close(fd);
+	}
+	mkfifo("./landlock/fifo", 0777);
+	symlink("../file", "./landlock/dir/link");
+	// Fifos block on open without O_NONBLOCK.
+	return open(nodes[(unsigned long)a0 % (sizeof(nodes) / sizeof(nodes[0]))], O_RDONLY | O_NONBLOCK | (int)a1);
+}
+#endif
+
 #if SYZ_EXECUTOR || __NR_syz_init_net_socket
 #if SYZ_EXECUTOR || SYZ_SANDBOX_NONE || SYZ_SANDBOX_SETUID || SYZ_SANDBOX_NAMESPACE || SYZ_SANDBOX_ANDROID
 #include <fcntl.h>
@@ -2541,6 +2973,80 @@ static long syz_socket_connect_nvme_tcp()
 #endif
 #endif
 
+#if SYZ_EXECUTOR || __NR_syz_emit_external
+#include <arpa/inet.h>
+#include <errno.h>
+#include <fcntl.h>
+#include <netinet/in.h>
+#include <sched.h>
+#include <stdlib.h>
+#include <sys/socket.h>
+#include <unistd.h>
+
+const int kExternalNetDefaultRate = 100;
+
+// syz_emit_external sends a packet to an external network target (a real device under test).
+// The target address is passed in the SYZKALLER_EXTERNAL_NET env var (set by syz-fuzzer from the manager
+// config, or manually when running C reproducers). SYZKALLER_EXTERNAL_NET_RATE limits the number of packets
+// per second sent by a single test process. The socket is created in the init net namespace,
+// since the test net namespace is not connected to the outside world.
+static long syz_emit_external(volatile long proto, volatile long port, volatile long payload, volatile long len)
+{
+	const char* addr_str = getenv("SYZKALLER_EXTERNAL_NET");
+	if (addr_str == NULL) {
+		errno = ENODEV;
+		return -1;
+	}
+	struct sockaddr_in addr;
+	memset(&addr, 0, sizeof(addr));
+	addr.sin_family = AF_INET;
+	if (inet_pton(AF_INET, addr_str, &addr.sin_addr) != 1) {
+		errno = EINVAL;
+		return -1;
+	}
+	int type = SOCK_RAW;
+	if (proto == IPPROTO_UDP) {
+		type = SOCK_DGRAM;
+		addr.sin_port = htobe16(port);
+	} else if (proto != IPPROTO_ICMP && proto != IPPROTO_SCTP) {
+		errno = EINVAL;
+		return -1;
+	}
+	int rate = kExternalNetDefaultRate;
+	const char* rate_str = getenv("SYZKALLER_EXTERNAL_NET_RATE");
+	if (rate_str != NULL && atoi(rate_str) > 0)
+		rate = atoi(rate_str);
+	// Test processes are restarted frequently, so instead of tracking send times
+	// we conservatively wait for the inter-packet interval before every send.
+	sleep_ms(1000 / rate);
+
+	int netns = open("/proc/self/ns/net", O_RDONLY);
+	if (netns == -1)
+		return netns;
+	if (setns(kInitNetNsFd, 0)) {
+		close(netns);
+		return -1;
+	}
+	int sock = syscall(__NR_socket, AF_INET, type, type == SOCK_RAW ? proto : 0);
+	int err = errno;
+	if (setns(netns, 0)) {
+		// The operation may fail if the fd is closed by
+		// a syscall from another thread.
+		exitf("setns(netns) failed");
+	}
+	close(netns);
+	if (sock == -1) {
+		errno = err;
+		return -1;
+	}
+	long res = syscall(__NR_sendto, sock, payload, len, MSG_DONTWAIT, &addr, sizeof(addr));
+	err = errno;
+	close(sock);
+	errno = err;
+	return res;
+}
+#endif
+
 #if SYZ_EXECUTOR || SYZ_VHCI_INJECTION
 #include <errno.h>
 #include <fcntl.h>
@@ -2940,16 +3446,271 @@ static long syz_genetlink_get_family_id(volatile long name, volatile long sock_a
 }
 #endif
 
-#if SYZ_EXECUTOR || __NR_syz_mount_image || __NR_syz_read_part_table
-#include "common_zlib.h"
+#if SYZ_EXECUTOR || __NR_syz_wireguard_pair
 #include <errno.h>
-#include <fcntl.h>
-#include <linux/loop.h>
-#include <stdbool.h>
-#include <sys/ioctl.h>
-#include <sys/stat.h>
-#include <sys/types.h>
-
+#include <netinet/in.h>
+#include <sys/socket.h>
+#include <unistd.h>
+
+#define WG_PAIR_PORT 20100
+
+// Private key of one side of the wireguard pair.
+// Curve25519 private keys are clamped by the kernel, so any 32 bytes are a valid key.
+// Keys are generated deterministically so that programs are reproducible.
+static void wg_pair_private_key(uint8* key, int id, int side)
+{
+	uint64 x = 0x9e3779b97f4a7c15ull * (2 * id + side + 1);
+	for (int i = 0; i < 32; i++) {
+		x ^= x << 13;
+		x ^= x >> 7;
+		x ^= x << 17;
+		key[i] = x;
+	}
+}
+
+static int wg_pair_send(struct nlmsg* nlmsg, int sock, const char* what, const char* name)
+{
+	int err = netlink_send_ext(nlmsg, sock, 0, NULL, false);
+	if (err < 0) {
+		debug("syz_wireguard_pair: %s %s failed: %s\n", what, name, strerror(errno));
+	}
+	return err;
+}
+
+static int wg_pair_add_device(struct nlmsg* nlmsg, int sock, const char* name, const char* addr)
+{
+	struct ifinfomsg hdr;
+	memset(&hdr, 0, sizeof(hdr));
+	hdr.ifi_index = if_nametoindex(name);
+	if (hdr.ifi_index) {
+		netlink_init(nlmsg, RTM_DELLINK, 0, &hdr, sizeof(hdr));
+		wg_pair_send(nlmsg, sock, "delete device", name);
+	}
+	netlink_add_device_impl(nlmsg, "wireguard", name, false);
+	netlink_done(nlmsg);
+	if (wg_pair_send(nlmsg, sock, "add device", name) < 0)
+		return -1;
+	hdr.ifi_index = if_nametoindex(name);
+	hdr.ifi_flags = hdr.ifi_change = IFF_UP;
+	netlink_init(nlmsg, RTM_NEWLINK, 0, &hdr, sizeof(hdr));
+	if (wg_pair_send(nlmsg, sock, "device up", name) < 0)
+		return -1;
+	struct ifaddrmsg addrhdr;
+	memset(&addrhdr, 0, sizeof(addrhdr));
+	addrhdr.ifa_family = AF_INET;
+	addrhdr.ifa_prefixlen = 24;
+	addrhdr.ifa_scope = RT_SCOPE_UNIVERSE;
+	addrhdr.ifa_index = hdr.ifi_index;
+	struct in_addr in_addr;
+	inet_pton(AF_INET, addr, &in_addr);
+	netlink_init(nlmsg, RTM_NEWADDR, NLM_F_CREATE | NLM_F_REPLACE, &addrhdr, sizeof(addrhdr));
+	netlink_attr(nlmsg, IFA_LOCAL, &in_addr, sizeof(in_addr));
+	netlink_attr(nlmsg, IFA_ADDRESS, &in_addr, sizeof(in_addr));
+	return wg_pair_send(nlmsg, sock, "add address", name);
+}
+
+// Queries the device public key and whether the handshake with the (only) peer has completed.
+static int wg_pair_get_device(struct nlmsg* nlmsg, int sock, int family, const char* name,
+			      uint8* public_key, bool* handshake)
+{
+	struct genlmsghdr genlhdr;
+	memset(&genlhdr, 0, sizeof(genlhdr));
+	genlhdr.cmd = WG_CMD_GET_DEVICE;
+	genlhdr.version = 1;
+	netlink_init(nlmsg, family, NLM_F_DUMP, &genlhdr, sizeof(genlhdr));
+	netlink_attr(nlmsg, WGDEVICE_A_IFNAME, name, strlen(name) + 1);
+	int n = 0;
+	if (netlink_send_ext(nlmsg, sock, family, &n, false) < 0 || n == 0) {
+		debug("syz_wireguard_pair: get device %s failed: %s\n", name, strerror(errno));
+		return -1;
+	}
+	bool got_key = false, done = false;
+	*handshake = false;
+	struct nlmsghdr* hdr = (struct nlmsghdr*)nlmsg->buf;
+	for (; NLMSG_OK(hdr, n); hdr = NLMSG_NEXT(hdr, n)) {
+		if (hdr->nlmsg_type == NLMSG_DONE) {
+			done = true;
+			continue;
+		}
+		if (hdr->nlmsg_type != family)
+			continue;
+		char* end = (char*)hdr + hdr->nlmsg_len;
+		struct nlattr* attr = (struct nlattr*)((char*)NLMSG_DATA(hdr) + NLMSG_ALIGN(sizeof(genlhdr)));
+		for (; (char*)(attr + 1) <= end; attr = (struct nlattr*)((char*)attr + NLA_ALIGN(attr->nla_len))) {
+			if (attr->nla_len < sizeof(*attr))
+				break;
+			int typ = attr->nla_type & NLA_TYPE_MASK;
+			if (typ == WGDEVICE_A_PUBLIC_KEY && attr->nla_len == sizeof(*attr) + 32) {
+				memcpy(public_key, attr + 1, 32);
+				got_key = true;
+			}
+			if (typ != WGDEVICE_A_PEERS)
+				continue;
+			// Peers are nested as WGDEVICE_A_PEERS -> peer -> attributes.
+			char* peers_end = (char*)attr + attr->nla_len;
+			struct nlattr* peer = attr + 1;
+			for (; (char*)(peer + 1) <= peers_end; peer = (struct nlattr*)((char*)peer + NLA_ALIGN(peer->nla_len))) {
+				if (peer->nla_len < sizeof(*peer))
+					break;
+				char* peer_end = (char*)peer + peer->nla_len;
+				struct nlattr* pattr = peer + 1;
+				for (; (char*)(pattr + 1) <= peer_end; pattr = (struct nlattr*)((char*)pattr + NLA_ALIGN(pattr->nla_len))) {
+					if (pattr->nla_len < sizeof(*pattr))
+						break;
+					if ((pattr->nla_type & NLA_TYPE_MASK) != WGPEER_A_LAST_HANDSHAKE_TIME)
+						continue;
+					for (unsigned i = sizeof(*pattr); i < pattr->nla_len; i++) {
+						if (((char*)pattr)[i])
+							*handshake = true;
+					}
+				}
+			}
+		}
+	}
+	// Dumps are terminated with NLMSG_DONE which may come in a separate datagram.
+	if (!done)
+		recv(sock, nlmsg->buf, sizeof(nlmsg->buf), 0);
+	return got_key ? 0 : -1;
+}
+
+static int wg_pair_set_device(struct nlmsg* nlmsg, int sock, int family, const char* name,
+			      const uint8* private_key, uint16 port, const uint8* peer_key,
+			      uint16 peer_port, struct in_addr subnet)
+{
+	struct genlmsghdr genlhdr;
+	memset(&genlhdr, 0, sizeof(genlhdr));
+	genlhdr.cmd = WG_CMD_SET_DEVICE;
+	genlhdr.version = 1;
+	const uint16 af_inet = AF_INET;
+	const uint8 cidr = 23;
+	struct sockaddr_in endpoint;
+	memset(&endpoint, 0, sizeof(endpoint));
+	endpoint.sin_family = AF_INET;
+	endpoint.sin_port = htons(peer_port);
+	endpoint.sin_addr.s_addr = htonl(INADDR_LOOPBACK);
+	netlink_init(nlmsg, family, 0, &genlhdr, sizeof(genlhdr));
+	netlink_attr(nlmsg, WGDEVICE_A_IFNAME, name, strlen(name) + 1);
+	netlink_attr(nlmsg, WGDEVICE_A_PRIVATE_KEY, private_key, 32);
+	netlink_attr(nlmsg, WGDEVICE_A_LISTEN_PORT, &port, 2);
+	if (peer_key) {
+		netlink_nest(nlmsg, NLA_F_NESTED | WGDEVICE_A_PEERS);
+		netlink_nest(nlmsg, NLA_F_NESTED | 0);
+		netlink_attr(nlmsg, WGPEER_A_PUBLIC_KEY, peer_key, 32);
+		netlink_attr(nlmsg, WGPEER_A_ENDPOINT, &endpoint, sizeof(endpoint));
+		netlink_nest(nlmsg, NLA_F_NESTED | WGPEER_A_ALLOWEDIPS);
+		netlink_nest(nlmsg, NLA_F_NESTED | 0);
+		netlink_attr(nlmsg, WGALLOWEDIP_A_FAMILY, &af_inet, 2);
+		netlink_attr(nlmsg, WGALLOWEDIP_A_IPADDR, &subnet, sizeof(subnet));
+		netlink_attr(nlmsg, WGALLOWEDIP_A_CIDR_MASK, &cidr, 1);
+		netlink_done(nlmsg);
+		netlink_done(nlmsg);
+		netlink_done(nlmsg);
+		netlink_done(nlmsg);
+	}
+	return wg_pair_send(nlmsg, sock, "set device", name);
+}
+
+// syz_wireguard_pair creates a pair of wireguard devices wgpIDa and wgpIDb
+// that are peers of each other over loopback, waits for the handshake between them
+// and returns an UDP socket connected through the tunnel, so that packets sent over it
+// are encrypted by wgpIDa and decrypted by wgpIDb.
+// Device wgpIDa has address 172.30.(2*ID).1/24, wgpIDb has 172.30.(2*ID+1).1/24.
+static long syz_wireguard_pair(volatile long a0)
+{
+	int id = (int)a0;
+	if (id < 0 || id > 3) {
+		debug("syz_wireguard_pair: bad id %d\n", id);
+		return -1;
+	}
+	char names[2][IFNAMSIZ];
+	char addrs[2][32];
+	uint8 private_keys[2][32];
+	uint8 public_keys[2][32];
+	uint16 ports[2];
+	for (int side = 0; side < 2; side++) {
+		snprintf(names[side], sizeof(names[side]), "wgp%d%c", id, 'a' + side);
+		snprintf(addrs[side], sizeof(addrs[side]), "172.30.%d.1", 2 * id + side);
+		wg_pair_private_key(private_keys[side], id, side);
+		ports[side] = WG_PAIR_PORT + 2 * id + side;
+	}
+	struct in_addr subnet;
+	subnet.s_addr = htonl((172 << 24) | (30 << 16) | ((2 * id) << 8));
+
+	struct nlmsg tmp_msg;
+	int rtsock = socket(AF_NETLINK, SOCK_RAW, NETLINK_ROUTE);
+	if (rtsock < 0) {
+		debug("syz_wireguard_pair: socket(NETLINK_ROUTE) failed: %d\n", errno);
+		return -1;
+	}
+	int gensock = socket(AF_NETLINK, SOCK_RAW, NETLINK_GENERIC);
+	if (gensock < 0) {
+		debug("syz_wireguard_pair: socket(NETLINK_GENERIC) failed: %d\n", errno);
+		close(rtsock);
+		return -1;
+	}
+	int fd = -1;
+	bool handshake = false;
+	int family = netlink_query_family_id(&tmp_msg, gensock, WG_GENL_NAME, false);
+	if (family < 0)
+		goto error;
+	// Set private keys first and let the kernel derive the public keys,
+	// then configure the devices as peers of each other.
+	for (int side = 0; side < 2; side++) {
+		if (wg_pair_add_device(&tmp_msg, rtsock, names[side], addrs[side]) < 0 ||
+		    wg_pair_set_device(&tmp_msg, gensock, family, names[side], private_keys[side],
+				       ports[side], NULL, 0, subnet) < 0 ||
+		    wg_pair_get_device(&tmp_msg, gensock, family, names[side], public_keys[side], &handshake) < 0)
+			goto error;
+	}
+	for (int side = 0; side < 2; side++) {
+		if (wg_pair_set_device(&tmp_msg, gensock, family, names[side], private_keys[side],
+				       ports[side], public_keys[1 - side], ports[1 - side], subnet) < 0)
+			goto error;
+	}
+	fd = socket(AF_INET, SOCK_DGRAM, 0);
+	if (fd < 0) {
+		debug("syz_wireguard_pair: socket(AF_INET) failed: %d\n", errno);
+		goto error;
+	}
+	struct sockaddr_in dst;
+	memset(&dst, 0, sizeof(dst));
+	dst.sin_family = AF_INET;
+	dst.sin_port = htons(WG_PAIR_PORT);
+	dst.sin_addr.s_addr = htonl(ntohl(subnet.s_addr) | 2);
+	if (connect(fd, (struct sockaddr*)&dst, sizeof(dst))) {
+		debug("syz_wireguard_pair: connect failed: %d\n", errno);
+		close(fd);
+		fd = -1;
+		goto error;
+	}
+	// The first packet initiates the handshake, wait for it to complete.
+	for (int i = 0; i < 20 && !handshake; i++) {
+		if (i % 5 == 0)
+			send(fd, "", 0, 0);
+		usleep(10 * 1000);
+		uint8 key[32];
+		if (wg_pair_get_device(&tmp_msg, gensock, family, names[0], key, &handshake) < 0)
+			break;
+	}
+	debug("syz_wireguard_pair: handshake %s\n", handshake ? "completed" : "timed out");
+
+error:
+	close(gensock);
+	close(rtsock);
+	return fd;
+}
+#endif
+
+#if SYZ_EXECUTOR || __NR_syz_mount_image || __NR_syz_read_part_table
+#include "common_zlib.h"
+#include <errno.h>
+#include <fcntl.h>
+#include <linux/loop.h>
+#include <stdbool.h>
+#include <sys/ioctl.h>
+#include <sys/stat.h>
+#include <sys/types.h>
+
 // Setup the loop device needed for mounting a filesystem image. Takes care of
 // creating and initializing the underlying file backing the loop device and
 // returns the fds to the file and device.
@@ -3775,15 +4536,26 @@ static void setup_cgroups()
 	// or not enabled configs), then all will fail.
 	const char* unified_controllers[] = {"+cpu", "+io", "+pids"};
 	const char* net_controllers[] = {"net", "net_prio", "devices", "blkio", "freezer"};
+	// Memory must be the last one, it's removed if it's bound to cgroup2.
 	const char* cpu_controllers[] = {"cpuset", "cpuacct", "hugetlb", "rlimit", "memory"};
+	int cpu_count = sizeof(cpu_controllers) / sizeof(cpu_controllers[0]);
 	if (mkdir("/syzcgroup", 0777)) {
 		// Can happen due to e.g. read-only file system (EROFS).
 		debug("mkdir(/syzcgroup) failed: %d\n", errno);
 		return;
 	}
 	mount_cgroups2(unified_controllers, sizeof(unified_controllers) / sizeof(unified_controllers[0]));
+	// Memory is preferably bound to cgroup2 so that each proc can be isolated with memory.max.
+	// If that's not possible (e.g. cgroup2 is not supported or memory is already bound
+	// to a v1 hierarchy), memory is bound to the v1 cpu hierarchy and setup_cgroups_loop
+	// uses memory.limit_in_bytes instead.
+	if (write_file("/syzcgroup/unified/cgroup.subtree_control", "+memory")) {
+		cpu_count--;
+	} else {
+		debug("memory cgroup2 controller is not available, using cgroup v1: %d\n", errno);
+	}
 	mount_cgroups("/syzcgroup/net", net_controllers, sizeof(net_controllers) / sizeof(net_controllers[0]));
-	mount_cgroups("/syzcgroup/cpu", cpu_controllers, sizeof(cpu_controllers) / sizeof(cpu_controllers[0]));
+	mount_cgroups("/syzcgroup/cpu", cpu_controllers, cpu_count);
 	write_file("/syzcgroup/cpu/cgroup.clone_children", "1");
 	write_file("/syzcgroup/cpu/cpuset.memory_pressure_enabled", "1");
 }
@@ -3798,24 +4570,10 @@ static void setup_cgroups_loop()
 	int pid = getpid();
 	char file[128];
 	char cgroupdir[64];
-	snprintf(cgroupdir, sizeof(cgroupdir), "/syzcgroup/unified/syz%llu", procid);
-	if (mkdir(cgroupdir, 0777)) {
-		debug("mkdir(%s) failed: %d\n", cgroupdir, errno);
-	}
 	// Restrict number of pids per test process to prevent fork bombs.
 	// We have up to 16 threads + main process + loop.
 	// 32 pids should be enough for everyone.
-	snprintf(file, sizeof(file), "%s/pids.max", cgroupdir);
-	write_file(file, "32");
-	// Setup some v1 groups to make things more interesting.
-	snprintf(file, sizeof(file), "%s/cgroup.procs", cgroupdir);
-	write_file(file, "%d", pid);
-	snprintf(cgroupdir, sizeof(cgroupdir), "/syzcgroup/cpu/syz%llu", procid);
-	if (mkdir(cgroupdir, 0777)) {
-		debug("mkdir(%s) failed: %d\n", cgroupdir, errno);
-	}
-	snprintf(file, sizeof(file), "%s/cgroup.procs", cgroupdir);
-	write_file(file, "%d", pid);
+	unsigned long long pids_limit = 32;
 	// Restrict memory consumption.
 	// We have some syscalls that inherently consume lots of memory,
 	// e.g. mounting some filesystem images requires at least 128MB
@@ -3826,11 +4584,41 @@ static void setup_cgroups_loop()
 	// so that we kill the process, but all of its memory is in quarantine
 	// and is still accounted against memcg. As the result memcg won't
 	// allow to allocate any memory in the parent and in the new test process.
-	// The current limit of 300MB supports up to 9.6GB RAM (quarantine is 1/32).
-	snprintf(file, sizeof(file), "%s/memory.soft_limit_in_bytes", cgroupdir);
-	write_file(file, "%d", 299 << 20);
-	snprintf(file, sizeof(file), "%s/memory.limit_in_bytes", cgroupdir);
-	write_file(file, "%d", 300 << 20);
+	// The default limit of 300MB supports up to 9.6GB RAM (quarantine is 1/32).
+	// Since the test process has oom_score_adj=1000, memcg OOM kills it rather than
+	// the loop process, so a runaway program does not take down the whole executor.
+	unsigned long long memory_limit = 300;
+#if SYZ_EXECUTOR
+	if (cgroups_pids_limit)
+		pids_limit = cgroups_pids_limit;
+	if (cgroups_memory_limit)
+		memory_limit = cgroups_memory_limit;
+#endif
+	snprintf(cgroupdir, sizeof(cgroupdir), "/syzcgroup/unified/syz%llu", procid);
+	if (mkdir(cgroupdir, 0777)) {
+		debug("mkdir(%s) failed: %d\n", cgroupdir, errno);
+	}
+	snprintf(file, sizeof(file), "%s/pids.max", cgroupdir);
+	write_file(file, "%llu", pids_limit);
+	snprintf(file, sizeof(file), "%s/memory.max", cgroupdir);
+	bool memory_v2 = write_file(file, "%llu", memory_limit << 20);
+	snprintf(file, sizeof(file), "%s/cgroup.procs", cgroupdir);
+	write_file(file, "%d", pid);
+	// Setup some v1 groups to make things more interesting.
+	snprintf(cgroupdir, sizeof(cgroupdir), "/syzcgroup/cpu/syz%llu", procid);
+	if (mkdir(cgroupdir, 0777)) {
+		debug("mkdir(%s) failed: %d\n", cgroupdir, errno);
+	}
+	if (!memory_v2) {
+		// The memory controller is bound to the v1 cpu hierarchy (see setup_cgroups).
+		debug("memory.max is not available, using cgroup v1 memory limits\n");
+		snprintf(file, sizeof(file), "%s/memory.soft_limit_in_bytes", cgroupdir);
+		write_file(file, "%llu", (memory_limit - 1) << 20);
+		snprintf(file, sizeof(file), "%s/memory.limit_in_bytes", cgroupdir);
+		write_file(file, "%llu", memory_limit << 20);
+	}
+	snprintf(file, sizeof(file), "%s/cgroup.procs", cgroupdir);
+	write_file(file, "%d", pid);
 	snprintf(cgroupdir, sizeof(cgroupdir), "/syzcgroup/net/syz%llu", procid);
 	if (mkdir(cgroupdir, 0777)) {
 		debug("mkdir(%s) failed: %d\n", cgroupdir, errno);
@@ -3941,7 +4729,8 @@ static void sandbox_common()
 	prctl(PR_SET_PDEATHSIG, SIGKILL, 0, 0, 0);
 	setsid();
 
-#if SYZ_EXECUTOR || __NR_syz_init_net_socket || SYZ_DEVLINK_PCI || __NR_syz_socket_connect_nvme_tcp
+#if SYZ_EXECUTOR || __NR_syz_init_net_socket || SYZ_DEVLINK_PCI || __NR_syz_socket_connect_nvme_tcp || \
+    __NR_syz_emit_external
 	int netns = open("/proc/self/ns/net", O_RDONLY);
 	if (netns == -1)
 		fail("open(/proc/self/ns/net) failed");
@@ -4177,16 +4966,59 @@ static int real_uid;
 static int real_gid;
 __attribute__((aligned(64 << 10))) static char sandbox_stack[1 << 20];
 
+#if SYZ_EXECUTOR || SYZ_MULTI_PROC
+// Namespaces are rotated across procs so that different procs exercise different setups.
+// Odd procs run test processes in an additional nested user and pid namespace,
+// this helps to catch bugs in translation of ids/pids across namespace levels.
+// Each proc also gets own time namespace with different clock offsets,
+// this helps to catch bugs in time namespace handling and
+// code that wrongly mixes clocks from different namespaces.
+// C reproducers do this only in multi-proc mode, where the rotation is meaningful.
+static void rotate_namespaces()
+{
+	if (procid % 2) {
+		if (unshare(CLONE_NEWUSER | CLONE_NEWPID)) {
+			debug("unshare(CLONE_NEWUSER | CLONE_NEWPID): %d\n", errno);
+		} else {
+			write_file("/proc/self/setgroups", "deny");
+			if (!write_file("/proc/self/uid_map", "0 0 1\n"))
+				fail("write of nested /proc/self/uid_map failed");
+			if (!write_file("/proc/self/gid_map", "0 0 1\n"))
+				fail("write of nested /proc/self/gid_map failed");
+			// CLONE_NEWPID takes effect for the first child of the current process,
+			// so the rest of the sandbox runs in the "init" process of the nested namespace.
+			prctl(PR_SET_PDEATHSIG, SIGKILL, 0, 0, 0);
+			int pid = fork();
+			if (pid != 0)
+				doexit(wait_for_loop(pid));
+		}
+	}
+	// The time namespace applies only to children, i.e. to test processes.
+	// Time namespaces are not present on older kernels, ignore errors.
+	unsigned long long time_offset = 24 * 60 * 60 * (procid + 1);
+	if (unshare(0x80)) {
+		debug("unshare(CLONE_NEWTIME): %d\n", errno);
+	} else if (!write_file("/proc/self/timens_offsets", "monotonic %llu 0\nboottime %llu 0\n",
+				time_offset, 2 * time_offset)) {
+		debug("write of /proc/self/timens_offsets failed: %d\n", errno);
+	}
+}
+#endif
+
 static int namespace_sandbox_proc(void* arg)
 {
-	sandbox_common();
-
+	// The maps are written before anything else, nested namespaces
+	// can be created only if our ids are mapped in the current namespace.
 	// /proc/self/setgroups is not present on some systems, ignore error.
 	write_file("/proc/self/setgroups", "deny");
 	if (!write_file("/proc/self/uid_map", "0 %d 1\n", real_uid))
 		fail("write of /proc/self/uid_map failed");
 	if (!write_file("/proc/self/gid_map", "0 %d 1\n", real_gid))
 		fail("write of /proc/self/gid_map failed");
+#if SYZ_EXECUTOR || SYZ_MULTI_PROC
+	rotate_namespaces();
+#endif
+	sandbox_common();
 
 #if SYZ_EXECUTOR || SYZ_NET_DEVICES
 	initialize_netdevices_init();
@@ -4694,6 +5526,297 @@ static int fault_injected(int fail_fd)
 }
 #endif
 
+#if SYZ_EXECUTOR || SYZ_PROC_ROLES
+#include <errno.h>
+#include <grp.h>
+#include <poll.h>
+#include <signal.h>
+#include <sys/syscall.h>
+#include <sys/types.h>
+#include <sys/wait.h>
+#include <unistd.h>
+
+// Process roles (see prog.RoleChild/RoleUnprivileged) run a call in a forked child process.
+// The child passes the call result back over a pipe.
+// Memory written by the call stays in the child, so role calls have no copyout
+// (see prog.CallProps.ProcRole).
+const int kRoleUnprivileged = 2;
+// The child is killed if it does not finish the call within the timeout.
+const int kRoleTimeoutMs = 5000;
+
+struct role_ctx {
+	int pid;
+	int pipe[2];
+	intptr_t res;
+	int err;
+};
+
+// role_fork returns 0 in the child that must execute the call and finish with role_exit,
+// and the child pid in the parent that must collect the result with role_wait.
+static int role_fork(struct role_ctx* ctx, int role)
+{
+	ctx->res = -1;
+	ctx->err = EINVAL;
+	if (pipe(ctx->pipe))
+		fail("role: pipe failed");
+	ctx->pid = fork();
+	if (ctx->pid < 0)
+		fail("role: fork failed");
+	if (ctx->pid != 0) {
+		close(ctx->pipe[1]);
+		return ctx->pid;
+	}
+	close(ctx->pipe[0]);
+	if (role == kRoleUnprivileged) {
+		const int nobody = 65534;
+		// If we fail to drop privileges (e.g. nobody is not mapped in the user namespace),
+		// the call result stays -1/EINVAL.
+		if (setgroups(0, NULL) || syscall(SYS_setresgid, nobody, nobody, nobody) ||
+		    syscall(SYS_setresuid, nobody, nobody, nobody))
+			_exit(1);
+	}
+	return 0;
+}
+
+static void role_exit(struct role_ctx* ctx, intptr_t res, int err)
+{
+	ctx->res = res;
+	ctx->err = err;
+	intptr_t buf[2] = {res, err};
+	if (write(ctx->pipe[1], buf, sizeof(buf))) {
+	}
+	_exit(0);
+}
+
+static intptr_t role_wait(struct role_ctx* ctx)
+{
+	struct pollfd pfd = {};
+	pfd.fd = ctx->pipe[0];
+	pfd.events = POLLIN;
+	intptr_t buf[2];
+	if (poll(&pfd, 1, kRoleTimeoutMs) == 1 &&
+	    read(ctx->pipe[0], buf, sizeof(buf)) == (ssize_t)sizeof(buf)) {
+		ctx->res = buf[0];
+		ctx->err = buf[1];
+	} else {
+		// The call is blocked (or the child died), the result stays -1/EINVAL.
+		kill(ctx->pid, SIGKILL);
+	}
+	close(ctx->pipe[0]);
+	int status = 0;
+	while (waitpid(ctx->pid, &status, __WALL) != ctx->pid) {
+		if (errno != EINTR)
+			break;
+	}
+	errno = ctx->err;
+	return ctx->res;
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_TIME_JUMPS
+#include <errno.h>
+#include <fcntl.h>
+#include <sched.h>
+#include <string.h>
+#include <sys/mman.h>
+#include <time.h>
+#include <unistd.h>
+
+// Time jumps (see prog.TimeJump*) change the clocks right before a call.
+// CLOCK_REALTIME is VM-wide, so its jumps would be visible to all procs while they last.
+// For this reason they are done only with a single proc (the executor gets the RealtimeJumps
+// exec flag, C reproducers don't contain them with several procs). They are reverted
+// by time_jump_leave right after the call. If the test process is killed or hangs
+// in the call, the fork server reverts them in time_jump_reset.
+// The time namespace jump must be done in a single-threaded child process (see role_fork),
+// it moves CLOCK_MONOTONIC and CLOCK_BOOTTIME only for the process.
+// Failures are ignored (e.g. no CAP_SYS_TIME), the call is executed with the unchanged clocks then.
+struct time_jump_ctx {
+	int jump;
+	time_t delta;
+};
+
+// Sum of the CLOCK_REALTIME deltas of this proc that are not reverted yet.
+// The memory is shared between the fork server and test processes.
+static time_t* time_jump_pending;
+
+static void time_jump_setup()
+{
+	void* mem = mmap(NULL, sizeof(*time_jump_pending), PROT_READ | PROT_WRITE, MAP_SHARED | MAP_ANONYMOUS, -1, 0);
+	if (mem == MAP_FAILED) {
+		debug("time_jump_setup: mmap failed: %d\n", errno);
+		return;
+	}
+	time_jump_pending = (time_t*)mem;
+}
+
+static void time_jump_shift(time_t delta)
+{
+	struct timespec ts;
+	if (clock_gettime(CLOCK_REALTIME, &ts))
+		return;
+	ts.tv_sec -= delta;
+	clock_settime(CLOCK_REALTIME, &ts);
+}
+
+// Reverts CLOCK_REALTIME jumps left by the previous test process.
+static void time_jump_reset()
+{
+	if (!time_jump_pending)
+		return;
+	time_t delta = __atomic_exchange_n(time_jump_pending, 0, __ATOMIC_RELAXED);
+	if (delta == 0)
+		return;
+	debug("time_jump_reset: reverting %ld sec\n", (long)delta);
+	time_jump_shift(delta);
+}
+
+static void time_jump_enter(struct time_jump_ctx* ctx, int jump)
+{
+	ctx->jump = jump;
+	ctx->delta = 0;
+	if (jump == 4) {
+		// CLONE_NEWTIME is not defined in older headers.
+		if (unshare(0x80))
+			return;
+		const char* offsets = "monotonic 86400 0\nboottime 86400 0\n";
+		int fd = open("/proc/self/timens_offsets", O_WRONLY | O_CLOEXEC);
+		if (fd == -1)
+			return;
+		if (write(fd, offsets, strlen(offsets))) {
+		}
+		close(fd);
+		// Offsets apply only to processes in the namespace, but unshare moves only future children there.
+		fd = open("/proc/self/ns/time_for_children", O_RDONLY | O_CLOEXEC);
+		if (fd == -1)
+			return;
+		if (setns(fd, 0x80)) {
+		}
+		close(fd);
+		return;
+	}
+	struct timespec now;
+	if (clock_gettime(CLOCK_REALTIME, &now))
+		return;
+	struct timespec ts = now;
+	switch (jump) {
+	case 1:
+		ts.tv_sec += 3600;
+		break;
+	case 2:
+		ts.tv_sec -= 3600;
+		break;
+	case 3:
+		ts.tv_sec = 0x7fffffff - 1;
+		break;
+	default:
+		return;
+	}
+	if (clock_settime(CLOCK_REALTIME, &ts))
+		return;
+	ctx->delta = ts.tv_sec - now.tv_sec;
+	if (time_jump_pending)
+		__atomic_fetch_add(time_jump_pending, ctx->delta, __ATOMIC_RELAXED);
+}
+
+static void time_jump_leave(struct time_jump_ctx* ctx)
+{
+	if (ctx->delta == 0)
+		return;
+	int err = errno;
+	time_jump_shift(ctx->delta);
+	if (time_jump_pending)
+		__atomic_fetch_sub(time_jump_pending, ctx->delta, __ATOMIC_RELAXED);
+	errno = err;
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_COMPAT_SYSCALLS
+#include <errno.h>
+#include <stdbool.h>
+#include <stdint.h>
+#include <sys/wait.h>
+#include <unistd.h>
+
+// Compat syscalls (see prog.CallProps.Compat) are executed via the 32-bit syscall entry.
+// On x86_64 int $0x80 is available to 64-bit processes if the kernel has CONFIG_IA32_EMULATION,
+// arguments are truncated to 32 bits (our data region fits into 32 bits).
+// Without the emulation int $0x80 raises SIGSEGV, so availability is checked once in a child process.
+// Calls fail with ENOSYS if the entry is not available.
+#if GOARCH_amd64
+static long compat_syscall_raw(long nr, intptr_t a0, intptr_t a1, intptr_t a2, intptr_t a3, intptr_t a4, intptr_t a5)
+{
+	// The 6th argument is passed in rbp, which we can't mark as clobbered.
+	// It's saved in memory addressed by a callee-saved register (int $0x80 does not preserve r8-r11
+	// on older kernels), and explicit register variables ensure the operands are not in rbp.
+	intptr_t saved_rbp;
+	register intptr_t arg5 asm("r12") = a5;
+	register intptr_t* save asm("r13") = &saved_rbp;
+	long res;
+	asm volatile("mov %%rbp, (%[save])\n"
+		     "mov %[arg5], %%rbp\n"
+		     "int $0x80\n"
+		     "mov (%[save]), %%rbp\n"
+		     : "=a"(res)
+		     : "a"(nr), "b"(a0), "c"(a1), "d"(a2), "S"(a3), "D"(a4), [arg5] "r"(arg5), [save] "r"(save)
+		     : "memory", "r8", "r9", "r10", "r11");
+	return res;
+}
+
+static bool compat_syscall_available()
+{
+	static int available = -1;
+	if (available != -1)
+		return available;
+	int pid = fork();
+	if (pid == 0) {
+		const long compat_nr_getpid = 20;
+		compat_syscall_raw(compat_nr_getpid, 0, 0, 0, 0, 0, 0);
+		_exit(0);
+	}
+	int status = 0;
+	available = pid > 0 && waitpid(pid, &status, 0) == pid && WIFEXITED(status) && WEXITSTATUS(status) == 0;
+	return available;
+}
+#endif
+
+static intptr_t compat_syscall(long nr, intptr_t a0, intptr_t a1, intptr_t a2, intptr_t a3, intptr_t a4, intptr_t a5)
+{
+#if GOARCH_amd64
+	if (nr != 0 && compat_syscall_available()) {
+		// The result is a 32-bit value.
+		int res = (int)compat_syscall_raw(nr, a0, a1, a2, a3, a4, a5);
+		if (res < 0 && res >= -4095) {
+			errno = -res;
+			return -1;
+		}
+		return (unsigned int)res;
+	}
+#endif
+	errno = ENOSYS;
+	return -1;
+}
+#endif
+
+#if SYZ_EXECUTOR || SYZ_SUSPEND
+#include <errno.h>
+
+// Suspend/resume cycles (see prog.Suspend*) are done right before a call, concurrently executing
+// calls race with the suspend and resume of devices. The machine is woken up by the RTC alarm
+// (there is nobody to press the power button). The write to /sys/power/state returns after resume.
+// Failures are ignored (e.g. no RTC or the state is not supported), the call is executed without the cycle then.
+static void suspend_resume(int mode)
+{
+	const char* state = mode == 1 ? "freeze" : "mem";
+	int err = errno;
+	// Reset the previous alarm, otherwise the new one can't be set.
+	write_file("/sys/class/rtc/rtc0/wakealarm", "0");
+	if (write_file("/sys/class/rtc/rtc0/wakealarm", "+2"))
+		write_file("/sys/power/state", state);
+	errno = err;
+}
+#endif
+
 #if (SYZ_EXECUTOR || SYZ_REPEAT) && SYZ_EXECUTOR_USES_FORK_SERVER
 #include <dirent.h>
 #include <errno.h>
@@ -4751,7 +5874,7 @@ static void kill_and_wait(int pid, int* status)
 }
 #endif
 
-#if (SYZ_EXECUTOR || SYZ_REPEAT && (SYZ_CGROUPS || SYZ_NET_RESET)) && SYZ_EXECUTOR_USES_FORK_SERVER
+#if (SYZ_EXECUTOR || SYZ_REPEAT && (SYZ_CGROUPS || SYZ_NET_RESET || SYZ_TIME_JUMPS)) && SYZ_EXECUTOR_USES_FORK_SERVER
 #include <fcntl.h>
 #include <sys/ioctl.h>
 #include <sys/stat.h>
@@ -4767,10 +5890,13 @@ static void setup_loop()
 #if SYZ_EXECUTOR || SYZ_NET_RESET
 	checkpoint_net_namespace();
 #endif
+#if SYZ_EXECUTOR || SYZ_TIME_JUMPS
+	time_jump_setup();
+#endif
 }
 #endif
 
-#if (SYZ_EXECUTOR || SYZ_REPEAT && (SYZ_NET_RESET || __NR_syz_mount_image || __NR_syz_read_part_table)) && SYZ_EXECUTOR_USES_FORK_SERVER
+#if (SYZ_EXECUTOR || SYZ_REPEAT && (SYZ_NET_RESET || SYZ_TIME_JUMPS || __NR_syz_mount_image || __NR_syz_read_part_table)) && SYZ_EXECUTOR_USES_FORK_SERVER
 #define SYZ_HAVE_RESET_LOOP 1
 static void reset_loop()
 {
@@ -4786,6 +5912,9 @@ static void reset_loop()
 #if SYZ_EXECUTOR || SYZ_NET_RESET
 	reset_net_namespace();
 #endif
+#if SYZ_EXECUTOR || SYZ_TIME_JUMPS
+	time_jump_reset();
+#endif
 }
 #endif
 
@@ -5717,6 +6846,62 @@ static void setup_swap()
 
 #endif
 
+#if SYZ_EXECUTOR || SYZ_HUGEPAGES
+#include <errno.h>
+#include <sys/mount.h>
+#include <sys/stat.h>
+
+#define HUGEPAGES_DIR "/dev/hugepages"
+#define HUGEPAGES_POOL 64 // 128 MB with 2 MB pages.
+
+static void setup_hugepages()
+{
+	// Default images have an empty hugetlb pool, so MAP_HUGETLB/MFD_HUGETLB/SHM_HUGETLB
+	// allocations fail with ENOMEM and hugetlb code is not exercised at all.
+	if (!write_file("/proc/sys/vm/nr_hugepages", "%d", HUGEPAGES_POOL))
+		fail("write(/proc/sys/vm/nr_hugepages) failed (CONFIG_HUGETLBFS is not enabled?)");
+	// Surplus pages are allocated on demand if there is enough free memory.
+	write_file("/proc/sys/vm/nr_overcommit_hugepages", "%d", HUGEPAGES_POOL);
+	// Make THP used for all anonymous and shmem mappings, so that khugepaged,
+	// collapse and split paths are reached without explicit madvise.
+	// These knobs don't exist w/o CONFIG_TRANSPARENT_HUGEPAGE, so we ignore errors.
+	write_file("/sys/kernel/mm/transparent_hugepage/enabled", "always");
+	write_file("/sys/kernel/mm/transparent_hugepage/defrag", "defer+madvise");
+	write_file("/sys/kernel/mm/transparent_hugepage/shmem_enabled", "always");
+	write_file("/sys/kernel/mm/transparent_hugepage/khugepaged/scan_sleep_millisecs", "100");
+	// The call must be idempotent, so EBUSY (already mounted) is fine.
+	mkdir(HUGEPAGES_DIR, 0777);
+	if (mount("hugetlbfs", HUGEPAGES_DIR, "hugetlbfs", 0, "mode=0777") && errno != EBUSY)
+		failmsg("mount of hugetlbfs failed", "dir: %s", HUGEPAGES_DIR);
+}
+
+#endif
+
+#if SYZ_EXECUTOR || SYZ_PMEM
+#include <sys/stat.h>
+
+#define PMEM_DEV "/dev/pmem0"
+
+// Persistent memory is emulated by the kernel in a RAM region reserved with
+// memmap=nn[KMG]!ss[KMG] kernel command line argument (e.g. memmap=256M!4G),
+// this requires CONFIG_X86_PMEM_LEGACY, CONFIG_LIBNVDIMM, CONFIG_BLK_DEV_PMEM and CONFIG_FS_DAX.
+// The region is not usable w/o the argument, so we can only check that it was given.
+static void setup_pmem()
+{
+	struct stat st;
+	if (stat(PMEM_DEV, &st) || !S_ISBLK(st.st_mode))
+		failmsg("pmem device does not exist", "dev: %s (boot with memmap=nn[KMG]!ss[KMG])", PMEM_DEV);
+	char buf[16] = {};
+	int fd = open("/sys/block/pmem0/queue/dax", O_RDONLY);
+	if (fd == -1 || read(fd, buf, sizeof(buf) - 1) <= 0 || buf[0] != '1')
+		fail("pmem device does not support DAX (CONFIG_FS_DAX is not enabled?)");
+	close(fd);
+	// Let the sandboxed processes open the device.
+	chmod(PMEM_DEV, 0666);
+}
+
+#endif
+
 #if SYZ_EXECUTOR || __NR_syz_pidfd_open
 #include <sys/syscall.h>
 
@@ -5734,3 +6919,329 @@ static long syz_pidfd_open(volatile long pid, volatile long flags)
 }
 
 #endif
+
+#if SYZ_EXECUTOR || __NR_syz_xen_hypercall
+#include <errno.h>
+#include <fcntl.h>
+#include <string.h>
+#include <sys/ioctl.h>
+#include <sys/mman.h>
+
+// Matches struct privcmd_hypercall from include/uapi/xen/privcmd.h,
+// the header is not available in all toolchains.
+struct xen_privcmd_hypercall {
+	uint64 op;
+	uint64 arg[5];
+};
+
+#ifndef IOCTL_PRIVCMD_HYPERCALL
+#define IOCTL_PRIVCMD_HYPERCALL _IOC(_IOC_NONE, 'P', 0, sizeof(struct xen_privcmd_hypercall))
+#endif
+
+// syz_xen_hypercall(op, slot intptr[0:2], a0 intptr, a1 intptr, a2 intptr, buf ptr[inout, array[int8]], len bytesize[buf])
+// Xen accesses hypercall arguments through guest virtual addresses and fails the hypercall
+// with EFAULT if the memory is not present, so buf is copied into hypercall-safe memory
+// provided by the privcmd-buf driver (/dev/xen/hypercall) for the duration of the call.
+static long syz_xen_hypercall(volatile long op, volatile long slot, volatile long a0, volatile long a1,
+			      volatile long a2, volatile long buf, volatile long len)
+{
+	if (slot < 0 || slot > 2 || len < 0)
+		return -1;
+	int privcmd = open("/dev/xen/privcmd", O_RDWR);
+	if (privcmd == -1)
+		return -1;
+	long res = -1;
+	int hfd = open("/dev/xen/hypercall", O_RDWR);
+	if (hfd == -1) {
+		close(privcmd);
+		return -1;
+	}
+	const long page_size = 4 << 10;
+	long size = (len + page_size - 1) / page_size * page_size;
+	if (size == 0)
+		size = page_size;
+	void* hbuf = mmap(NULL, size, PROT_READ | PROT_WRITE, MAP_SHARED, hfd, 0);
+	if (hbuf != MAP_FAILED) {
+		if (len)
+			memcpy(hbuf, (void*)buf, len);
+		struct xen_privcmd_hypercall call = {};
+		call.op = op;
+		call.arg[0] = a0;
+		call.arg[1] = a1;
+		call.arg[2] = a2;
+		call.arg[slot] = (uint64)(long)hbuf;
+		res = ioctl(privcmd, IOCTL_PRIVCMD_HYPERCALL, &call);
+		int err = errno;
+		if (len)
+			memcpy((void*)buf, hbuf, len);
+		munmap(hbuf, size);
+		errno = err;
+	}
+	int err = errno;
+	close(hfd);
+	close(privcmd);
+	errno = err;
+	return res;
+}
+
+#endif
+
+#if SYZ_EXECUTOR || __NR_syz_binder_peer
+#include <errno.h>
+#include <fcntl.h>
+#include <linux/android/binder.h>
+#include <signal.h>
+#include <string.h>
+#include <sys/ioctl.h>
+#include <sys/mman.h>
+#include <sys/prctl.h>
+#include <sys/types.h>
+#include <sys/wait.h>
+#include <unistd.h>
+
+#define BINDER_PEER_MAP_SIZE (128 << 10)
+#define BINDER_PEER_MAX_OBJECTS 16
+
+// From drivers/staging/android/uapi/ashmem.h, the header is not exported.
+#ifndef ASHMEM_GET_SIZE
+#define ASHMEM_GET_SIZE _IO(0x77, 4)
+#endif
+
+struct binder_peer_cmd {
+	uint32 cmd;
+	struct binder_transaction_data tr;
+	binder_size_t buffers_size;
+} __attribute__((packed));
+
+static void binder_peer_write(int fd, const void* data, size_t size)
+{
+	struct binder_write_read bwr = {};
+	bwr.write_size = size;
+	bwr.write_buffer = (binder_uintptr_t)(long)data;
+	ioctl(fd, BINDER_WRITE_READ, &bwr);
+}
+
+static void binder_peer_write_cmd(int fd, uint32 cmd, const void* arg, size_t size)
+{
+	char buf[64];
+	memcpy(buf, &cmd, sizeof(cmd));
+	memcpy(buf + sizeof(cmd), arg, size);
+	binder_peer_write(fd, buf, sizeof(cmd) + size);
+}
+
+// Maps and touches a received ashmem region (fds of other types fail the ioctl).
+static void binder_peer_touch_fd(int fd)
+{
+	int size = ioctl(fd, ASHMEM_GET_SIZE, 0);
+	if (size <= 0)
+		return;
+	void* p = mmap(NULL, size, PROT_READ | PROT_WRITE, MAP_SHARED, fd, 0);
+	if (p == MAP_FAILED)
+		return;
+	*(volatile char*)p = *(volatile char*)p + 1;
+	munmap(p, size);
+}
+
+// Serves a transaction received from the fuzzed process.
+// Received references are acquired, sent a one-way transaction and released,
+// received ashmem fds are mapped. Then the transaction data is echoed back in the reply,
+// so that all objects are translated across the processes once again.
+static void binder_peer_transaction(int fd, struct binder_transaction_data* tr)
+{
+	binder_size_t* offsets = (binder_size_t*)(long)tr->data.ptr.offsets;
+	char* data = (char*)(long)tr->data.ptr.buffer;
+	size_t nobjects = tr->offsets_size / sizeof(binder_size_t);
+	int fds[BINDER_PEER_MAX_OBJECTS];
+	int nfds = 0;
+	binder_size_t buffers_size = 0;
+	for (size_t i = 0; i < nobjects && i < BINDER_PEER_MAX_OBJECTS; i++) {
+		if (offsets[i] + sizeof(struct binder_object_header) > tr->data_size)
+			break;
+		struct binder_object_header* hdr = (struct binder_object_header*)(data + offsets[i]);
+		switch (hdr->type) {
+		case BINDER_TYPE_HANDLE:
+		case BINDER_TYPE_WEAK_HANDLE: {
+			uint32 handle = ((struct flat_binder_object*)hdr)->handle;
+			binder_peer_write_cmd(fd, BC_ACQUIRE, &handle, sizeof(handle));
+			struct binder_peer_cmd cmd = {};
+			cmd.cmd = BC_TRANSACTION;
+			cmd.tr.target.handle = handle;
+			cmd.tr.flags = TF_ONE_WAY;
+			binder_peer_write(fd, &cmd, sizeof(cmd.cmd) + sizeof(cmd.tr));
+			binder_peer_write_cmd(fd, BC_RELEASE, &handle, sizeof(handle));
+			break;
+		}
+		case BINDER_TYPE_FD: {
+			int rfd = ((struct binder_fd_object*)hdr)->fd;
+			binder_peer_touch_fd(rfd);
+			fds[nfds++] = rfd;
+			break;
+		}
+		case BINDER_TYPE_PTR:
+			buffers_size += (((struct binder_buffer_object*)hdr)->length + 7) & ~7ull;
+			break;
+		}
+	}
+	if (!(tr->flags & TF_ONE_WAY)) {
+		struct binder_peer_cmd cmd = {};
+		cmd.cmd = BC_REPLY_SG;
+		cmd.tr = *tr;
+		cmd.tr.target.ptr = 0;
+		cmd.tr.cookie = 0;
+		cmd.tr.flags &= TF_ACCEPT_FDS;
+		cmd.buffers_size = buffers_size;
+		binder_peer_write(fd, &cmd, sizeof(cmd));
+	}
+	binder_peer_write_cmd(fd, BC_FREE_BUFFER, &tr->data.ptr.buffer, sizeof(tr->data.ptr.buffer));
+	for (int i = 0; i < nfds; i++)
+		close(fds[i]);
+}
+
+static void binder_peer_loop(int fd)
+{
+	uint32 buf[512];
+	for (;;) {
+		struct binder_write_read bwr = {};
+		bwr.read_size = sizeof(buf);
+		bwr.read_buffer = (binder_uintptr_t)(long)buf;
+		if (ioctl(fd, BINDER_WRITE_READ, &bwr)) {
+			if (errno == EINTR || errno == EAGAIN)
+				continue;
+			return;
+		}
+		char* pos = (char*)buf;
+		char* end = pos + bwr.read_consumed;
+		while (pos + sizeof(uint32) <= end) {
+			uint32 cmd = *(uint32*)pos;
+			pos += sizeof(cmd);
+			// All BR_* commands encode the size of their payload.
+			if (pos + _IOC_SIZE(cmd) > end)
+				break;
+			switch (cmd) {
+			case BR_TRANSACTION:
+				binder_peer_transaction(fd, (struct binder_transaction_data*)pos);
+				break;
+			case BR_REPLY:
+				binder_peer_write_cmd(fd, BC_FREE_BUFFER, &((struct binder_transaction_data*)pos)->data.ptr.buffer,
+						      sizeof(binder_uintptr_t));
+				break;
+			case BR_INCREFS:
+				binder_peer_write_cmd(fd, BC_INCREFS_DONE, pos, sizeof(struct binder_ptr_cookie));
+				break;
+			case BR_ACQUIRE:
+				binder_peer_write_cmd(fd, BC_ACQUIRE_DONE, pos, sizeof(struct binder_ptr_cookie));
+				break;
+			case BR_DEAD_BINDER:
+				binder_peer_write_cmd(fd, BC_DEAD_BINDER_DONE, pos, sizeof(binder_uintptr_t));
+				break;
+			}
+			pos += _IOC_SIZE(cmd);
+		}
+	}
+}
+
+static int binder_peer_open(const char* dev)
+{
+	int fd = open(dev, O_RDWR);
+	if (fd == -1)
+		return -1;
+	if (mmap(NULL, BINDER_PEER_MAP_SIZE, PROT_READ, MAP_SHARED, fd, 0) == MAP_FAILED) {
+		close(fd);
+		return -1;
+	}
+	return fd;
+}
+
+// syz_binder_peer(dev ptr[in, string]) fd_binder
+// Single-process binder programs can't reach the interesting paths that involve
+// cross-process reference counting and object translation. This call forks a peer process
+// that becomes the context manager (handle 0) of the binder device and serves transactions
+// sent to it (see binder_peer_transaction) until the test process exits.
+// Returns a binder fd (already mmaped) of the test process.
+static long syz_binder_peer(volatile long a0)
+{
+	const char* dev = (const char*)a0;
+	int pipefd[2];
+	if (pipe(pipefd))
+		return -1;
+	int pid = fork();
+	if (pid < 0) {
+		close(pipefd[0]);
+		close(pipefd[1]);
+		return -1;
+	}
+	if (pid == 0) {
+		prctl(PR_SET_PDEATHSIG, SIGKILL, 0, 0, 0);
+		close(pipefd[0]);
+		int err = 0;
+		int fd = binder_peer_open(dev);
+		if (fd == -1 || ioctl(fd, BINDER_SET_CONTEXT_MGR, 0))
+			err = errno;
+		if (write(pipefd[1], &err, sizeof(err)) != sizeof(err) || err)
+			doexit(1);
+		close(pipefd[1]);
+		// Only looper threads receive transactions sent to the process nodes.
+		uint32 cmd = BC_ENTER_LOOPER;
+		binder_peer_write(fd, &cmd, sizeof(cmd));
+		binder_peer_loop(fd);
+		doexit(0);
+	}
+	close(pipefd[1]);
+	int err = EINVAL;
+	if (read(pipefd[0], &err, sizeof(err)) != sizeof(err))
+		err = EINVAL;
+	close(pipefd[0]);
+	if (err) {
+		kill(pid, SIGKILL);
+		waitpid(pid, NULL, 0);
+		errno = err;
+		return -1;
+	}
+	return binder_peer_open(dev);
+}
+
+#endif
+
+#if SYZ_EXECUTOR || __NR_syz_tee_open_session
+#include <errno.h>
+#include <linux/tee.h>
+#include <string.h>
+#include <sys/ioctl.h>
+
+#define TEE_SESSION_MAX_PARAMS 4
+
+// Opens a TEE session with the TA identified by uuid. Unlike plain TEE_IOC_OPEN_SESSION,
+// it fails if the TEE itself rejected the session (arg.ret != 0), so that the returned
+// session id is always valid. Output params are copied back in any case.
+static long syz_tee_open_session(volatile long fd, volatile long uuid, volatile long login,
+				 volatile long params, volatile long nparams)
+{
+	uint64 buf[(sizeof(struct tee_ioctl_open_session_arg) +
+		    TEE_SESSION_MAX_PARAMS * sizeof(struct tee_ioctl_param)) /
+		   sizeof(uint64)];
+	struct tee_ioctl_open_session_arg* arg = (struct tee_ioctl_open_session_arg*)buf;
+	if (nparams < 0 || nparams > TEE_SESSION_MAX_PARAMS) {
+		errno = EINVAL;
+		return -1;
+	}
+	memset(buf, 0, sizeof(buf));
+	memcpy(arg->uuid, (void*)uuid, TEE_IOCTL_UUID_LEN);
+	arg->clnt_login = login;
+	arg->num_params = nparams;
+	size_t params_size = nparams * sizeof(struct tee_ioctl_param);
+	memcpy(arg->params, (void*)params, params_size);
+	struct tee_ioctl_buf_data data;
+	data.buf_ptr = (uint64)arg;
+	data.buf_len = sizeof(*arg) + params_size;
+	if (ioctl(fd, TEE_IOC_OPEN_SESSION, &data))
+		return -1;
+	memcpy((void*)params, arg->params, params_size);
+	if (arg->ret) {
+		debug("syz_tee_open_session: ret=0x%x origin=%u\n", arg->ret, arg->ret_origin);
+		errno = EINVAL;
+		return -1;
+	}
+	return arg->session;
+}
+
+#endif
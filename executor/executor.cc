@@ -166,6 +166,10 @@ static bool flag_net_injection;
 static bool flag_net_devices;
 static bool flag_net_reset;
 static bool flag_cgroups;
+#if GOOS_linux
+static uint64 cgroups_memory_limit;
+static uint64 cgroups_pids_limit;
+#endif
 static bool flag_close_fds;
 static bool flag_devlink_pci;
 static bool flag_nic_vf;
@@ -178,6 +182,9 @@ static bool flag_collect_signal;
 static bool flag_dedup_cover;
 static bool flag_threaded;
 static bool flag_coverage_filter;
+static bool flag_collect_races;
+static bool flag_collect_warnings;
+static bool flag_realtime_jumps;
 
 // If true, then executor should write the comparisons data to fuzzer.
 static bool flag_comparisons;
@@ -218,6 +225,10 @@ const uint64 binary_format_stroct = 4;
 const uint64 no_copyout = -1;
 
 static int running;
+// Number of calls being executed and number of calls started so far, used to detect
+// whether a call ran concurrently with other calls (updated from all threads).
+static int calls_in_flight;
+static uint32 calls_started;
 uint32 completed;
 bool is_kernel_64_bit = true;
 
@@ -237,6 +248,8 @@ struct call_t {
 	int sys_nr;
 	call_attrs_t attrs;
 	syscall_t call;
+	// Syscall number of the compat entry (see compat_syscall), 0 if there is none.
+	int compat_nr;
 };
 
 struct cover_t {
@@ -276,6 +289,8 @@ struct thread_t {
 	intptr_t res;
 	uint32 reserrno;
 	bool fault_injected;
+	bool kernel_warned;
+	bool race_candidate;
 	cover_t cov;
 	bool soft_fail_state;
 };
@@ -302,6 +317,8 @@ struct handshake_req {
 	uint64 flags; // env flags
 	uint64 pid;
 	uint64 sandbox_arg;
+	uint64 memory_limit; // per-proc cgroup memory limit in MB, 0 means default
+	uint64 pids_limit; // per-proc cgroup pids limit, 0 means default
 };
 
 struct handshake_reply {
@@ -330,6 +347,8 @@ const uint32 call_flag_executed = 1 << 0;
 const uint32 call_flag_finished = 1 << 1;
 const uint32 call_flag_blocked = 1 << 2;
 const uint32 call_flag_fault_injected = 1 << 3;
+const uint32 call_flag_kernel_warning = 1 << 4;
+const uint32 call_flag_race_candidate = 1 << 5;
 
 struct call_reply {
 	execute_reply header;
@@ -410,6 +429,18 @@ static void setup_features(char** enable, int n);
 #error "unknown OS"
 #endif
 
+#if !GOOS_linux
+static uint64 kernel_warn_count()
+{
+	return 0;
+}
+
+static uint64 kcsan_race_count()
+{
+	return 0;
+}
+#endif
+
 #include "cov_filter.h"
 
 #include "test.h"
@@ -644,6 +675,10 @@ void receive_handshake()
 #endif
 	parse_env_flags(req.flags);
 	procid = req.pid;
+#if GOOS_linux
+	cgroups_memory_limit = req.memory_limit;
+	cgroups_pids_limit = req.pids_limit;
+#endif
 }
 
 void reply_handshake()
@@ -677,6 +712,9 @@ void receive_execute()
 	flag_comparisons = req.exec_flags & (1 << 3);
 	flag_threaded = req.exec_flags & (1 << 4);
 	flag_coverage_filter = req.exec_flags & (1 << 5);
+	flag_collect_races = req.exec_flags & (1 << 6);
+	flag_collect_warnings = req.exec_flags & (1 << 7);
+	flag_realtime_jumps = req.exec_flags & (1 << 8);
 
 	debug("[%llums] exec opts: procid=%llu threaded=%d cover=%d comps=%d dedup=%d signal=%d"
 	      " timeouts=%llu/%llu/%llu prog=%llu filter=%d\n",
@@ -1118,7 +1156,9 @@ void write_call_output(thread_t* th, bool finished)
 	if (finished) {
 		reserrno = th->res != -1 ? 0 : th->reserrno;
 		call_flags |= call_flag_finished |
-			      (th->fault_injected ? call_flag_fault_injected : 0);
+			      (th->fault_injected ? call_flag_fault_injected : 0) |
+			      (th->kernel_warned ? call_flag_kernel_warning : 0) |
+			      (th->race_candidate ? call_flag_race_candidate : 0);
 	}
 #if SYZ_EXECUTOR_USES_SHMEM
 	write_output(kOutMagic);
@@ -1250,6 +1290,18 @@ void* worker_thread(void* arg)
 	return 0;
 }
 
+// execute_call_syscall executes the call via the compat syscall entry if the call has the compat property,
+// or submits it via io_uring if the call has the uring property.
+static intptr_t execute_call_syscall(thread_t* th, const call_t* call)
+{
+	intptr_t* a = th->args;
+	if (th->call_props.compat)
+		return compat_syscall(call->compat_nr, a[0], a[1], a[2], a[3], a[4], a[5]);
+	if (th->call_props.uring)
+		return uring_syscall(call->sys_nr, a[0], a[1], a[2], a[3], a[4], a[5]);
+	return execute_syscall(call, th->args);
+}
+
 void execute_call(thread_t* th)
 {
 	const call_t* call = &syscalls[th->call_num];
@@ -1262,8 +1314,18 @@ void execute_call(thread_t* th)
 	}
 	debug(")\n");
 
+	// The cycle is done before fault injection and coverage collection,
+	// so that it does not consume the fault and does not pollute the coverage.
+	if (th->call_props.suspend != 0)
+		suspend_resume(th->call_props.suspend);
 	int fail_fd = -1;
 	th->soft_fail_state = false;
+	// The time namespace jump is done in a child process (see time_jump_enter).
+	int call_role = th->call_props.role;
+	if (call_role == 0 && th->call_props.time_jump == 4)
+		call_role = 1;
+	if (call_role != 0 && (th->call_props.fail_nth > 0 || th->call_props.rerun > 0))
+		fail("process role is combined with fault injection or rerun");
 	if (th->call_props.fail_nth > 0) {
 		if (th->call_props.rerun > 0)
 			fail("both fault injection and rerun are enabled for the same call");
@@ -1276,9 +1338,42 @@ void execute_call(thread_t* th)
 	// For pseudo-syscalls and user-space functions NONFAILING can abort before assigning to th->res.
 	// Arrange for res = -1 and errno = EFAULT result for such case.
 	th->res = -1;
+	// Note: the counter is global, so a report in a concurrently running call would be
+	// attributed to this call as well. To avoid that we flag the call only if no other call
+	// was running or started while it was running (reports in other procs are still attributed).
+	uint32 started = __atomic_add_fetch(&calls_started, 1, __ATOMIC_RELAXED);
+	bool alone = __atomic_add_fetch(&calls_in_flight, 1, __ATOMIC_RELAXED) == 1;
+	// Reading the counters costs a syscall (or more), so do it only if the feedback is enabled.
+	uint64 warn_count = flag_collect_warnings ? kernel_warn_count() : 0;
+	uint64 race_count = flag_collect_races ? kcsan_race_count() : 0;
 	errno = EFAULT;
-	NONFAILING(th->res = execute_syscall(call, th->args));
+	// CLOCK_REALTIME jumps are VM-wide and would affect calls of other procs,
+	// so they are done only if the host allowed them (it does so only with a single proc).
+	int time_jump_kind = th->call_props.time_jump;
+	if (time_jump_kind != 4 && !flag_realtime_jumps)
+		time_jump_kind = 0;
+	time_jump_ctx time_jump;
+	if (call_role != 0) {
+		// Note: coverage is not collected from the child process.
+		role_ctx role;
+		if (role_fork(&role, call_role) == 0) {
+			intptr_t res = -1;
+			time_jump_enter(&time_jump, time_jump_kind);
+			NONFAILING(res = execute_call_syscall(th, call));
+			time_jump_leave(&time_jump);
+			role_exit(&role, res, errno);
+		}
+		th->res = role_wait(&role);
+	} else {
+		time_jump_enter(&time_jump, time_jump_kind);
+		NONFAILING(th->res = execute_call_syscall(th, call));
+		time_jump_leave(&time_jump);
+	}
 	th->reserrno = errno;
+	alone = alone && __atomic_load_n(&calls_started, __ATOMIC_RELAXED) == started;
+	__atomic_sub_fetch(&calls_in_flight, 1, __ATOMIC_RELAXED);
+	th->kernel_warned = flag_collect_warnings && alone && kernel_warn_count() != warn_count;
+	th->race_candidate = flag_collect_races && kcsan_race_count() != race_count;
 	// Our pseudo-syscalls may misbehave.
 	if ((th->res == -1 && th->reserrno == 0) || call->attrs.ignore_return)
 		th->reserrno = EINVAL;
@@ -1298,7 +1393,7 @@ void execute_call(thread_t* th)
 	// If required, run the syscall some more times.
 	// But let's still return res, errno and coverage from the first execution.
 	for (int i = 0; i < th->call_props.rerun; i++)
-		NONFAILING(execute_syscall(call, th->args));
+		NONFAILING(execute_call_syscall(th, call));
 
 	debug("#%d [%llums] <- %s=0x%llx",
 	      th->id, current_time_ms() - start_time_ms, call->name, (uint64)th->res);
@@ -1308,8 +1403,22 @@ void execute_call(thread_t* th)
 		debug(" cover=%u", th->cov.size);
 	if (th->call_props.fail_nth > 0)
 		debug(" fault=%d", th->fault_injected);
+	if (th->kernel_warned)
+		debug(" warned");
+	if (th->race_candidate)
+		debug(" race");
 	if (th->call_props.rerun > 0)
 		debug(" rerun=%d", th->call_props.rerun);
+	if (th->call_props.role != 0)
+		debug(" role=%d", th->call_props.role);
+	if (th->call_props.time_jump != 0)
+		debug(" time_jump=%d", th->call_props.time_jump);
+	if (th->call_props.compat)
+		debug(" compat");
+	if (th->call_props.uring)
+		debug(" uring");
+	if (th->call_props.suspend != 0)
+		debug(" suspend=%d", th->call_props.suspend);
 	debug("\n");
 }
 
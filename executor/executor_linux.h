@@ -47,12 +47,16 @@ static inline __u64 kcov_remote_handle(__u64 subsys, __u64 inst)
 
 static bool detect_kernel_bitness();
 static bool detect_gvisor();
+static void kernel_warn_init();
+static void kcsan_race_init();
 
 static void os_init(int argc, char** argv, char* data, size_t data_size)
 {
 	prctl(PR_SET_PDEATHSIG, SIGKILL, 0, 0, 0);
 	is_kernel_64_bit = detect_kernel_bitness();
 	is_gvisor = detect_gvisor();
+	kernel_warn_init();
+	kcsan_race_init();
 	// Surround the main data mapping with PROT_NONE pages to make virtual address layout more consistent
 	// across different configurations (static/non-static build) and C repros.
 	// One observed case before: executor had a mapping above the data mapping (output region),
@@ -69,6 +73,69 @@ static void os_init(int argc, char** argv, char* data, size_t data_size)
 		failmsg("mmap of right data PROT_NONE page failed", "want %p, got %p", data + data_size, got);
 }
 
+// The kernel increments warn_count on every WARNING and on KASAN/KFENCE/UBSAN reports
+// (see check_panic_on_warn), so comparing it before and after a call allows to attribute
+// bugs to calls if the kernel does not panic on them (e.g. panic_on_warn=0).
+// The file is present since Linux 6.2.
+const int kKernelWarnFd = kExtraCoverFd - 1;
+static bool have_kernel_warn_count;
+
+static void kernel_warn_init()
+{
+	int fd = open("/sys/kernel/warn_count", O_RDONLY);
+	if (fd == -1)
+		return;
+	if (dup2(fd, kKernelWarnFd) < 0)
+		failmsg("failed to dup warn_count fd", "from=%d, to=%d", fd, kKernelWarnFd);
+	close(fd);
+	have_kernel_warn_count = true;
+}
+
+static uint64 kernel_warn_count()
+{
+	if (!have_kernel_warn_count)
+		return 0;
+	char buf[32];
+	ssize_t n = pread(kKernelWarnFd, buf, sizeof(buf) - 1, 0);
+	if (n <= 0)
+		return 0;
+	buf[n] = 0;
+	return strtoull(buf, NULL, 10);
+}
+
+// KCSAN counts all detected data races (including the ones that are not reported
+// due to rate limiting or filtering) in /sys/kernel/debug/kcsan, comparing the counter
+// before and after a call allows to mark calls that produced data race candidates.
+// Like warn_count, the counter is global.
+const int kKcsanFd = kKernelWarnFd - 1;
+static bool have_kcsan_race_count;
+
+static void kcsan_race_init()
+{
+	int fd = open("/sys/kernel/debug/kcsan", O_RDONLY);
+	if (fd == -1)
+		return;
+	if (dup2(fd, kKcsanFd) < 0)
+		failmsg("failed to dup kcsan fd", "from=%d, to=%d", fd, kKcsanFd);
+	close(fd);
+	have_kcsan_race_count = true;
+}
+
+static uint64 kcsan_race_count()
+{
+	if (!have_kcsan_race_count)
+		return 0;
+	char buf[1024];
+	ssize_t n = pread(kKcsanFd, buf, sizeof(buf) - 1, 0);
+	if (n <= 0)
+		return 0;
+	buf[n] = 0;
+	const char* races = strstr(buf, "data_races: ");
+	if (!races)
+		return 0;
+	return strtoull(races + strlen("data_races: "), NULL, 10);
+}
+
 static intptr_t execute_syscall(const call_t* c, intptr_t a[kMaxArgs])
 {
 	if (c->call)
@@ -277,6 +344,33 @@ static void setup_devlink_pci()
 		fail("PCI device 0000:00:10.0 is not available");
 }
 
+static void setup_io_uring()
+{
+	// Calls with the uring property are submitted to an io_uring instance created by the executor,
+	// check that io_uring is not compiled out or disabled with the io_uring_disabled sysctl.
+	struct io_uring_params params = {};
+	int fd = syscall(__NR_io_uring_setup, 1, &params);
+	if (fd == -1)
+		fail("io_uring_setup failed");
+	close(fd);
+}
+
+static void setup_suspend()
+{
+	// Suspend/resume cycles (see suspend_resume) need suspend-to-idle support
+	// and an RTC wake alarm to wake up the machine.
+	char buf[128] = {};
+	int fd = open("/sys/power/state", O_RDONLY);
+	if (fd == -1)
+		fail("failed to open /sys/power/state");
+	ssize_t n = read(fd, buf, sizeof(buf) - 1);
+	close(fd);
+	if (n <= 0 || !strstr(buf, "freeze"))
+		failmsg("suspend-to-idle is not supported", "state=%s", buf);
+	if (access("/sys/class/rtc/rtc0/wakealarm", W_OK))
+		fail("RTC wake alarm is not available");
+}
+
 static void setup_delay_kcov()
 {
 	is_kernel_64_bit = detect_kernel_bitness();
@@ -291,6 +385,18 @@ static void setup_delay_kcov()
 	const_cast<volatile char*>(cov.data)[0] = 1;
 }
 
+static void setup_compat_syscalls()
+{
+	// Calls with the compat property are executed via int $0x80,
+	// which needs CONFIG_IA32_EMULATION (and ia32_emulation=1 on newer kernels).
+#if GOARCH_amd64
+	if (!compat_syscall_available())
+		fail("IA32 emulation is not available");
+#else
+	fail("compat syscalls are supported only on amd64");
+#endif
+}
+
 #define SYZ_HAVE_FEATURES 1
 static feature_t features[] = {
     {rpc::Feature::DelayKcovMmap, setup_delay_kcov},
@@ -301,6 +407,11 @@ static feature_t features[] = {
     {rpc::Feature::LRWPANEmulation, setup_802154},
     {rpc::Feature::BinFmtMisc, setup_binfmt_misc},
     {rpc::Feature::Swap, setup_swap},
+    {rpc::Feature::Hugepages, setup_hugepages},
+    {rpc::Feature::Pmem, setup_pmem},
+    {rpc::Feature::IOUring, setup_io_uring},
+    {rpc::Feature::Suspend, setup_suspend},
+    {rpc::Feature::CompatSyscalls, setup_compat_syscalls},
     {rpc::Feature::NicVF, setup_nicvf},
     {rpc::Feature::DevlinkPCI, setup_devlink_pci},
 };
@@ -31,6 +31,8 @@
 #define ADDR_VAR_VMEXIT_CODE 0x9000
 #define ADDR_VAR_USER_CODE 0x9100
 #define ADDR_VAR_USER_CODE2 0x9120
+#define ADDR_VAR_VMCB 0xb000
+#define ADDR_VAR_VM_HSAVE 0xc000
 
 #define SEL_LDT (1 << 3)
 #define SEL_CS16 (2 << 3)
@@ -72,6 +74,36 @@
 #define MSR_IA32_STAR 0xC0000081
 #define MSR_IA32_LSTAR 0xC0000082
 #define MSR_IA32_VMX_PROCBASED_CTLS2 0x48B
+#define MSR_IA32_EFER 0xC0000080
+#define MSR_VM_HSAVE_PA 0xC0010117
+
+// Offsets of VMCB fields (AMD APM vol 2, appendix B).
+#define VMCB_INTERCEPT_MISC1 0x0c
+#define VMCB_INTERCEPT_MISC2 0x10
+#define VMCB_GUEST_ASID 0x58
+#define VMCB_EXIT_CODE 0x70
+#define VMCB_EXIT_INFO1 0x78
+#define VMCB_EXIT_INFO2 0x80
+#define VMCB_SAVE_ES 0x400
+#define VMCB_SAVE_CS 0x410
+#define VMCB_SAVE_SS 0x420
+#define VMCB_SAVE_DS 0x430
+#define VMCB_SAVE_FS 0x440
+#define VMCB_SAVE_GS 0x450
+#define VMCB_SAVE_GDTR 0x460
+#define VMCB_SAVE_LDTR 0x470
+#define VMCB_SAVE_IDTR 0x480
+#define VMCB_SAVE_TR 0x490
+#define VMCB_SAVE_EFER 0x4d0
+#define VMCB_SAVE_CR4 0x548
+#define VMCB_SAVE_CR3 0x550
+#define VMCB_SAVE_CR0 0x558
+#define VMCB_SAVE_DR7 0x560
+#define VMCB_SAVE_DR6 0x568
+#define VMCB_SAVE_RFLAGS 0x570
+#define VMCB_SAVE_RIP 0x578
+#define VMCB_SAVE_RSP 0x5d8
+#define VMCB_SAVE_G_PAT 0x668
 
 #define NEXT_INSN $0xbadc0de
 #define PREFIX_SIZE 0xba1d
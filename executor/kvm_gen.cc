@@ -27,6 +27,7 @@ int main()
 	PRINT(kvm_asm32_paged_vm86);
 	PRINT(kvm_asm64_enable_long);
 	PRINT(kvm_asm64_init_vm);
+	PRINT(kvm_asm64_init_svm);
 	PRINT(kvm_asm64_vm_exit);
 	PRINT(kvm_asm64_cpl3);
 #elif GOARCH_ppc64le
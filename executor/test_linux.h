@@ -13,11 +13,12 @@ static int cpu_feature_enabled(uint32_t function, uint32_t eax_bits, uint32_t eb
 #define FEATURE_INTEL 0x00000001
 #define FEATURE_INTEL_ECX_VMX (1 << 5)
 
-#define FEATURE_AMD 0x800000001
+#define FEATURE_AMD 0x80000001
 #define FEATURE_AMD_ECX_SVM (1 << 2)
 #endif
 
-static int test_one(int text_type, const char* text, int text_size, int flags, unsigned reason, bool check_rax)
+// If expect_rcx is not -1, test_one also checks the value of rcx after the run.
+static int test_one(int text_type, const char* text, int text_size, int flags, unsigned reason, bool check_rax, long long expect_rcx = -1)
 {
 	printf("=== testing text %d, text size 0x%x, flags 0x%x\n", text_type, text_size, flags);
 	int kvmfd = open("/dev/kvm", O_RDWR);
@@ -95,6 +96,11 @@ static int test_one(int text_type, const char* text, int text_size, int flags, u
 		dump_cpu_state(cpufd, (char*)vm_mem);
 		return 1;
 	}
+	if (expect_rcx != -1 && regs.rcx != (unsigned long long)expect_rcx) {
+		printf("wrong result: rcx=0x%llx, expect 0x%llx\n", (long long)regs.rcx, expect_rcx);
+		dump_cpu_state(cpufd, (char*)vm_mem);
+		return 1;
+	}
 #elif GOARCH_ppc64le
 	if (check_rax && regs.gpr[3] != 0xbadc0de) {
 		printf("wrong result: gps[3]=0x%llx\n", (long long)regs.gpr[3]);
@@ -165,6 +171,15 @@ static int test_kvm()
 			return res;
 	}
 
+	if (cpu_feature_enabled(FEATURE_AMD, 0, 0, FEATURE_AMD_ECX_SVM, 0) == 1) {
+		// The nested guest runs the text, its HLT is intercepted and the host code
+		// exits with the #VMEXIT code in rcx (rax holds EXITINFO1, so don't check it).
+		const long long svm_exit_hlt = 0x78;
+		const char text64_svm[] = "\xb8\xde\xc0\xad\x0b\xf4";
+		if ((res = test_one(64, text64_svm, sizeof(text64_svm) - 1, KVM_SETUP_SVM, KVM_EXIT_HLT, false, svm_exit_hlt)))
+			return res;
+	}
+
 	// Note: SMM does not work on 3.13 kernels.
 	if (ver >= 404) {
 		const char text8_smm[] = "\x66\xb8\xde\xc0\xad\x0b";
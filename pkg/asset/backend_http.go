@@ -0,0 +1,48 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Helpers shared by the HTTP-based (S3, WebDAV) backends.
+
+// bufferedUpload returns a writer that saves the data to a temporary file
+// and passes it to send on Close. HTTP object stores generally want
+// the content length to be known in advance, while compressors produce a stream.
+func bufferedUpload(send func(body *os.File, size int64) error) (io.WriteCloser, error) {
+	tmp, err := os.CreateTemp("", "syz-asset-*")
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedWriteCloser{
+		writer: tmp,
+		closeCallback: func() error {
+			defer os.Remove(tmp.Name())
+			file, err := os.Open(tmp.Name())
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			stat, err := file.Stat()
+			if err != nil {
+				return err
+			}
+			return send(file, stat.Size())
+		},
+	}, nil
+}
+
+// httpStatusError converts a non-2xx response into an error.
+func httpStatusError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	return fmt.Errorf("%v %v: %v: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status, body)
+}
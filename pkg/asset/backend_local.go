@@ -0,0 +1,121 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// localStorageBackend stores assets in a local directory.
+// The directory is supposed to be served by some web server (or be shared via NFS, etc),
+// downloadBase is the URL prefix that corresponds to the directory.
+type localStorageBackend struct {
+	dir          string
+	downloadBase string
+}
+
+func makeLocalStorageBackend(dir, downloadBase string) (*localStorageBackend, error) {
+	if !filepath.IsAbs(dir) {
+		return nil, fmt.Errorf("local storage path must be absolute: %v", dir)
+	}
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	if downloadBase == "" {
+		downloadBase = "file://" + dir
+	}
+	return &localStorageBackend{
+		dir:          dir,
+		downloadBase: strings.TrimSuffix(downloadBase, "/"),
+	}, nil
+}
+
+func (be *localStorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	file := filepath.Join(be.dir, filepath.FromSlash(req.savePath))
+	if osutil.IsExist(file) {
+		return nil, &FileExistsError{req.savePath}
+	}
+	if err := osutil.MkdirAll(filepath.Dir(file)); err != nil {
+		return nil, err
+	}
+	// Write to a temp file first, so that partially uploaded files are never visible.
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	return &uploadResponse{
+		path: req.savePath,
+		writer: &wrappedWriteCloser{
+			writer: tmp,
+			closeCallback: func() error {
+				// CreateTemp uses 0600, but assets are served to others.
+				if err := os.Chmod(tmp.Name(), 0644); err != nil {
+					os.Remove(tmp.Name())
+					return err
+				}
+				if err := os.Rename(tmp.Name(), file); err != nil {
+					os.Remove(tmp.Name())
+					return err
+				}
+				return nil
+			},
+		},
+	}, nil
+}
+
+func (be *localStorageBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return be.downloadBase + "/" + path, nil
+}
+
+func (be *localStorageBackend) getPath(url string) (string, error) {
+	if !strings.HasPrefix(url, be.downloadBase+"/") {
+		return "", ErrUnknownBucket
+	}
+	return strings.TrimPrefix(url, be.downloadBase+"/"), nil
+}
+
+func (be *localStorageBackend) list() ([]storedObject, error) {
+	ret := []storedObject{}
+	err := filepath.WalkDir(be.dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(be.dir, file)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, storedObject{
+			path:      filepath.ToSlash(rel),
+			createdAt: info.ModTime(),
+		})
+		return nil
+	})
+	return ret, err
+}
+
+func (be *localStorageBackend) remove(path string) error {
+	file := filepath.Join(be.dir, filepath.FromSlash(path))
+	err := os.Remove(file)
+	if os.IsNotExist(err) {
+		return ErrAssetDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
+	// Remove the containing folder if it became empty.
+	if dir := filepath.Dir(file); dir != be.dir {
+		os.Remove(dir)
+	}
+	return nil
+}
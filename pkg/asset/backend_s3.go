@@ -0,0 +1,178 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/syzkaller/pkg/debugtracer"
+)
+
+// s3StorageBackend stores assets in an S3-compatible object storage (AWS, MinIO, Ceph, etc).
+// Credentials are taken from the standard AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY/AWS_SESSION_TOKEN
+// environment variables. Path-style addressing is used since it's supported by all implementations.
+type s3StorageBackend struct {
+	endpoint     string
+	bucket       string
+	publicAccess bool
+	client       *s3.Client
+	tracer       debugtracer.DebugTracer
+}
+
+const (
+	defaultS3Endpoint = "https://s3.amazonaws.com"
+	defaultS3Region   = "us-east-1"
+)
+
+func makeS3StorageBackend(bucket string, cfg *Config, tracer debugtracer.DebugTracer) (*s3StorageBackend, error) {
+	endpoint := strings.TrimSuffix(cfg.S3Endpoint, "/")
+	if endpoint == "" {
+		endpoint = defaultS3Endpoint
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	bucket = strings.Trim(bucket, "/")
+	if bucket == "" || strings.Contains(bucket, "/") {
+		return nil, fmt.Errorf("bad S3 bucket name %q", bucket)
+	}
+	creds := aws.Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Source:          "EnvironmentVariables",
+	}
+	if !creds.HasKeys() {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for S3 storage")
+	}
+	client := s3.New(s3.Options{
+		BaseEndpoint: aws.String(endpoint),
+		Region:       region,
+		UsePathStyle: true,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return creds, nil
+		}),
+	})
+	tracer.Log("created s3 backend for bucket '%s' at %s", bucket, endpoint)
+	return &s3StorageBackend{
+		endpoint:     endpoint,
+		bucket:       bucket,
+		publicAccess: cfg.PublicAccess,
+		client:       client,
+		tracer:       tracer,
+	}, nil
+}
+
+func (be *s3StorageBackend) objectURL(path string) string {
+	return be.endpoint + "/" + be.bucket + "/" + (&url.URL{Path: path}).EscapedPath()
+}
+
+func (be *s3StorageBackend) exists(ctx context.Context, path string) (bool, error) {
+	_, err := be.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(be.bucket),
+		Key:    aws.String(path),
+	})
+	if err == nil {
+		return true, nil
+	}
+	var notFound *types.NotFound
+	var apiErr smithy.APIError
+	if errors.As(err, &notFound) || errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
+		return false, nil
+	}
+	return false, err
+}
+
+func (be *s3StorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	ctx := context.Background()
+	// Best-effort check only, same as for GCS.
+	exists, err := be.exists(ctx, req.savePath)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, &FileExistsError{req.savePath}
+	}
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(be.bucket),
+		Key:         aws.String(req.savePath),
+		ContentType: aws.String(req.contentType),
+	}
+	if req.contentEncoding != "" {
+		input.ContentEncoding = aws.String(req.contentEncoding)
+	}
+	if be.publicAccess {
+		input.ACL = types.ObjectCannedACLPublicRead
+	}
+	w, err := bufferedUpload(func(body *os.File, size int64) error {
+		input.Body = body
+		input.ContentLength = aws.Int64(size)
+		_, err := be.client.PutObject(ctx, input)
+		be.tracer.Log("s3 upload of %s: %v", req.savePath, err)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &uploadResponse{writer: w, path: req.savePath}, nil
+}
+
+func (be *s3StorageBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return be.objectURL(path), nil
+}
+
+func (be *s3StorageBackend) getPath(downloadURL string) (string, error) {
+	prefix := be.endpoint + "/" + be.bucket + "/"
+	if !strings.HasPrefix(downloadURL, prefix) {
+		return "", ErrUnknownBucket
+	}
+	return url.PathUnescape(strings.TrimPrefix(downloadURL, prefix))
+}
+
+func (be *s3StorageBackend) list() ([]storedObject, error) {
+	ret := []storedObject{}
+	paginator := s3.NewListObjectsV2Paginator(be.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(be.bucket),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			ret = append(ret, storedObject{
+				path:      aws.ToString(obj.Key),
+				createdAt: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return ret, nil
+}
+
+func (be *s3StorageBackend) remove(path string) error {
+	ctx := context.Background()
+	// S3 DELETE succeeds for non-existing objects, so check existence first.
+	exists, err := be.exists(ctx, path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAssetDoesNotExist
+	}
+	_, err = be.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(be.bucket),
+		Key:    aws.String(path),
+	})
+	return err
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBackend checks the generic StorageBackend contract.
+func testBackend(t *testing.T, be StorageBackend) {
+	storage := &Storage{
+		cfg:     &Config{},
+		backend: be,
+		tracer:  &debugtracer.TestTracer{T: t},
+	}
+	content := []byte("reproducer")
+	extra := &ExtraUploadArg{UniqueTag: "0123456789abcdef"}
+	asset, err := storage.UploadCrashAsset(bytes.NewReader(content), "repro.syz", dashapi.ReproSyz, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storage.UploadCrashAsset(bytes.NewReader(content), "repro.syz", dashapi.ReproSyz, extra)
+	var existsErr *FileExistsError
+	assert.True(t, errors.As(err, &existsErr), "got %v", err)
+	extra.SkipIfExists = true
+	again, err := storage.UploadCrashAsset(bytes.NewReader(content), "repro.syz", dashapi.ReproSyz, extra)
+	assert.NoError(t, err)
+	assert.Equal(t, asset.DownloadURL, again.DownloadURL)
+
+	path, err := be.getPath(asset.DownloadURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789ab/repro.syz", path)
+	_, err = be.getPath("http://unrelated.com/file")
+	assert.Equal(t, ErrUnknownBucket, err)
+
+	list, err := be.list()
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, path, list[0].path)
+		assert.WithinDuration(t, time.Now(), list[0].createdAt, time.Hour)
+	}
+	assert.NoError(t, be.remove(path))
+	assert.Equal(t, ErrAssetDoesNotExist, be.remove(path))
+	list, err = be.list()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestLocalBackend(t *testing.T) {
+	dir := t.TempDir()
+	be, err := makeLocalStorageBackend(dir, "https://example.com/assets/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBackend(t, be)
+
+	resp, err := be.upload(&uploadRequest{savePath: "dir/file"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, resp.writer.Close())
+	info, err := os.Stat(filepath.Join(dir, "dir", "file"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestS3Backend(t *testing.T) {
+	server := newFakeObjectServer(t)
+	defer server.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	be, err := makeS3StorageBackend("bucket", &Config{S3Endpoint: server.URL}, &debugtracer.TestTracer{T: t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.checkRequest = func(r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"), auth)
+		assert.Contains(t, auth, "/us-east-1/s3/aws4_request, SignedHeaders=")
+		assert.True(t, r.URL.Path == "/bucket" || strings.HasPrefix(r.URL.Path, "/bucket/"), r.URL.Path)
+	}
+	testBackend(t, be)
+}
+
+func TestWebdavBackend(t *testing.T) {
+	server := newFakeObjectServer(t)
+	defer server.Close()
+	be, err := makeWebdavStorageBackend(strings.Replace(server.URL, "http://", "http://user:pass@", 1)+"/dav",
+		&debugtracer.TestTracer{T: t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.checkRequest = func(r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "pass", pass)
+	}
+	testBackend(t, be)
+}
+
+// fakeObjectServer is a minimal in-memory implementation of the S3 and WebDAV protocols.
+type fakeObjectServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	objects      map[string][]byte
+	collections  map[string]bool
+	checkRequest func(r *http.Request)
+}
+
+func newFakeObjectServer(t *testing.T) *fakeObjectServer {
+	srv := &fakeObjectServer{
+		objects:     make(map[string][]byte),
+		collections: make(map[string]bool),
+	}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serve))
+	return srv
+}
+
+func (srv *fakeObjectServer) serve(w http.ResponseWriter, r *http.Request) {
+	if srv.checkRequest != nil {
+		srv.checkRequest(r)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	path := r.URL.Path
+	switch r.Method {
+	case http.MethodHead:
+		if _, ok := srv.objects[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(data)) != r.ContentLength {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		srv.objects[path] = data
+	case http.MethodDelete:
+		if _, ok := srv.objects[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(srv.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	case "MKCOL":
+		if srv.collections[path] {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		srv.collections[path] = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		// S3 ListObjectsV2.
+		prefix := strings.TrimSuffix(path, "/") + "/"
+		fmt.Fprintf(w, "<ListBucketResult>")
+		for _, key := range srv.keys(prefix) {
+			fmt.Fprintf(w, "<Contents><Key>%v</Key><LastModified>%v</LastModified></Contents>",
+				strings.TrimPrefix(key, prefix), time.Now().UTC().Format(time.RFC3339))
+		}
+		fmt.Fprintf(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case "PROPFIND":
+		if r.Header.Get("Depth") != "1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprintf(w, `<?xml version="1.0"?><D:multistatus xmlns:D="DAV:">`)
+		fmt.Fprintf(w, `<D:response><D:href>%v</D:href><D:propstat><D:prop>`+
+			`<D:resourcetype><D:collection/></D:resourcetype></D:prop></D:propstat></D:response>`, path)
+		for col := range srv.collections {
+			if strings.HasPrefix(col, path) && col != path && !strings.Contains(col[len(path):len(col)-1], "/") {
+				fmt.Fprintf(w, `<D:response><D:href>%v</D:href><D:propstat><D:prop>`+
+					`<D:resourcetype><D:collection/></D:resourcetype></D:prop></D:propstat></D:response>`, col)
+			}
+		}
+		for _, key := range srv.keys(path) {
+			if strings.Contains(key[len(path):], "/") {
+				continue
+			}
+			fmt.Fprintf(w, `<D:response><D:href>%v</D:href><D:propstat><D:prop>`+
+				`<D:getlastmodified>%v</D:getlastmodified><D:resourcetype/></D:prop></D:propstat></D:response>`,
+				key, time.Now().UTC().Format(http.TimeFormat))
+		}
+		fmt.Fprintf(w, `</D:multistatus>`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (srv *fakeObjectServer) keys(prefix string) []string {
+	var keys []string
+	for key := range srv.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+)
+
+// webdavStorageBackend stores assets on a WebDAV server (Apache mod_dav, nginx, Nextcloud, etc).
+// The files are downloaded via the same URL over plain HTTP(S).
+type webdavStorageBackend struct {
+	base     *url.URL // without credentials
+	user     *url.Userinfo
+	client   *http.Client
+	tracer   debugtracer.DebugTracer
+	basePath string // base.Path with the trailing slash
+}
+
+// makeWebdavStorageBackend accepts a URL of the form http(s)://[user:password@]host/path.
+func makeWebdavStorageBackend(rawURL string, tracer debugtracer.DebugTracer) (*webdavStorageBackend, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WebDAV URL: %w", err)
+	}
+	be := &webdavStorageBackend{
+		base:   base,
+		user:   base.User,
+		client: http.DefaultClient,
+		tracer: tracer,
+	}
+	base.User = nil
+	base.Path = strings.TrimSuffix(base.Path, "/") + "/"
+	be.basePath = base.Path
+	tracer.Log("created webdav backend for %s", base)
+	return be, nil
+}
+
+func (be *webdavStorageBackend) fileURL(file string) string {
+	u := *be.base
+	u.Path = be.basePath + file
+	return u.String()
+}
+
+func (be *webdavStorageBackend) do(method, file string, header http.Header,
+	body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequest(method, be.fileURL(file), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for key, vals := range header {
+		req.Header[key] = vals
+	}
+	if be.user != nil {
+		password, _ := be.user.Password()
+		req.SetBasicAuth(be.user.Username(), password)
+	}
+	return be.client.Do(req)
+}
+
+func (be *webdavStorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	resp, err := be.do(http.MethodHead, req.savePath, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil, &FileExistsError{req.savePath}
+	}
+	// WebDAV requires parent collections to exist. 405 means that the collection already exists.
+	if dir := path.Dir(req.savePath); dir != "." {
+		resp, err := be.do("MKCOL", dir+"/", nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			if err := httpStatusError(resp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	header := http.Header{}
+	header.Set("Content-Type", req.contentType)
+	if req.contentEncoding != "" {
+		header.Set("Content-Encoding", req.contentEncoding)
+	}
+	w, err := bufferedUpload(func(body *os.File, size int64) error {
+		resp, err := be.do(http.MethodPut, req.savePath, header, body, size)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		be.tracer.Log("webdav upload of %s: %s", req.savePath, resp.Status)
+		return httpStatusError(resp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &uploadResponse{writer: w, path: req.savePath}, nil
+}
+
+func (be *webdavStorageBackend) downloadURL(file string, publicURL bool) (string, error) {
+	return be.fileURL(file), nil
+}
+
+func (be *webdavStorageBackend) getPath(downloadURL string) (string, error) {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the URL: %w", err)
+	}
+	if u.Host != be.base.Host || !strings.HasPrefix(u.Path, be.basePath) {
+		return "", ErrUnknownBucket
+	}
+	return strings.TrimPrefix(u.Path, be.basePath), nil
+}
+
+type webdavMultistatus struct {
+	Responses []struct {
+		Href         string    `xml:"href"`
+		LastModified string    `xml:"propstat>prop>getlastmodified"`
+		Collection   *struct{} `xml:"propstat>prop>resourcetype>collection"`
+	} `xml:"response"`
+}
+
+const webdavPropfind = `<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><getlastmodified/><resourcetype/></prop></propfind>`
+
+func (be *webdavStorageBackend) list() ([]storedObject, error) {
+	ret := []storedObject{}
+	// Depth: infinity is frequently disabled on servers, so walk the tree manually.
+	dirs := []string{""}
+	for len(dirs) != 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+		header := http.Header{}
+		header.Set("Depth", "1")
+		header.Set("Content-Type", "application/xml")
+		resp, err := be.do("PROPFIND", dir, header, strings.NewReader(webdavPropfind), int64(len(webdavPropfind)))
+		if err != nil {
+			return nil, err
+		}
+		var res webdavMultistatus
+		err = httpStatusError(resp)
+		if err == nil {
+			err = xml.NewDecoder(resp.Body).Decode(&res)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range res.Responses {
+			href, err := url.Parse(item.Href)
+			if err != nil {
+				return nil, fmt.Errorf("bad href %q: %w", item.Href, err)
+			}
+			file := strings.TrimPrefix(href.Path, be.basePath)
+			if !strings.HasPrefix(href.Path, be.basePath) ||
+				strings.TrimSuffix(file, "/") == strings.TrimSuffix(dir, "/") {
+				// The collection itself.
+				continue
+			}
+			if item.Collection != nil {
+				dirs = append(dirs, strings.TrimSuffix(file, "/")+"/")
+				continue
+			}
+			createdAt, err := http.ParseTime(item.LastModified)
+			if err != nil {
+				return nil, fmt.Errorf("bad modification time for %v: %w", file, err)
+			}
+			ret = append(ret, storedObject{
+				path:      file,
+				createdAt: createdAt,
+			})
+		}
+	}
+	return ret, nil
+}
+
+func (be *webdavStorageBackend) remove(file string) error {
+	resp, err := be.do(http.MethodDelete, file, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrAssetDoesNotExist
+	}
+	return httpStatusError(resp)
+}
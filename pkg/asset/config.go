@@ -16,9 +16,22 @@ type Config struct {
 	Debug bool `json:"debug"`
 	// Where to upload artifacts.
 	// If "gs://bucket/" is specified, assets will be stored in the corresponding GCS bucket.
+	// If "s3://bucket/" is specified, assets will be stored in an S3-compatible bucket
+	// (see S3Endpoint and S3Region; credentials are taken from AWS_* environment variables).
+	// If "file:///absolute/path/" is specified, assets will be stored in the local directory
+	// (see DownloadURL).
+	// If "webdav://[user:password@]host/path/" or "webdavs://..." is specified, assets will be
+	// uploaded to the WebDAV server over HTTP or HTTPS respectively.
 	// If "dummy://" is specified, assets will not be actually stored anywhere. May be helpful
 	// for debugging.
 	UploadTo string `json:"upload_to"`
+	// S3 endpoint URL, "https://s3.amazonaws.com" by default.
+	S3Endpoint string `json:"s3_endpoint"`
+	// S3 region, "us-east-1" by default.
+	S3Region string `json:"s3_region"`
+	// URL prefix under which a local storage directory is served (e.g. "https://example.com/assets").
+	// If not set, file:// URLs are used.
+	DownloadURL string `json:"download_url"`
 	// Perform asset deprecation from this instance. If several syz-ci's share a common stoage,
 	// it make sense to enable derprecation only on one of them.
 	DoDeprecation bool `json:"do_deprecation"`
@@ -59,7 +72,7 @@ func (c *Config) Validate() error {
 	if c.UploadTo == "" && len(c.Assets) != 0 {
 		return fmt.Errorf("assets are specified, but upload_to is empty")
 	}
-	allowedFormats := []string{"gs://", "dummy://"}
+	allowedFormats := []string{"gs://", "s3://", "file://", "webdav://", "webdavs://", "dummy://"}
 	if c.UploadTo != "" {
 		any := false
 		for _, prefix := range allowedFormats {
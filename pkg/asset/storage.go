@@ -33,10 +33,9 @@ type Dashboard interface {
 	NeededAssetsList() (*dashapi.NeededAssetsResp, error)
 }
 
+// StorageFromConfig creates a new asset storage.
+// The dashboard may be nil, then build asset reporting and asset deprecation are not available.
 func StorageFromConfig(cfg *Config, dash Dashboard) (*Storage, error) {
-	if dash == nil {
-		return nil, fmt.Errorf("dashboard api instance is necessary")
-	}
 	tracer := debugtracer.DebugTracer(&debugtracer.NullTracer{})
 	if cfg.Debug {
 		tracer = &debugtracer.GenericTracer{
@@ -44,17 +43,9 @@ func StorageFromConfig(cfg *Config, dash Dashboard) (*Storage, error) {
 			TraceWriter: os.Stdout,
 		}
 	}
-	var backend StorageBackend
-	if strings.HasPrefix(cfg.UploadTo, "gs://") {
-		var err error
-		backend, err = makeCloudStorageBackend(strings.TrimPrefix(cfg.UploadTo, "gs://"), tracer)
-		if err != nil {
-			return nil, fmt.Errorf("the call to MakeCloudStorageBackend failed: %w", err)
-		}
-	} else if strings.HasPrefix(cfg.UploadTo, "dummy://") {
-		backend = makeDummyStorageBackend()
-	} else {
-		return nil, fmt.Errorf("unknown UploadTo during StorageFromConfig(): %#v", cfg.UploadTo)
+	backend, err := makeStorageBackend(cfg, tracer)
+	if err != nil {
+		return nil, err
 	}
 	return &Storage{
 		cfg:     cfg,
@@ -64,6 +55,29 @@ func StorageFromConfig(cfg *Config, dash Dashboard) (*Storage, error) {
 	}, nil
 }
 
+func makeStorageBackend(cfg *Config, tracer debugtracer.DebugTracer) (StorageBackend, error) {
+	scheme, rest, _ := strings.Cut(cfg.UploadTo, "://")
+	switch scheme {
+	case "gs":
+		backend, err := makeCloudStorageBackend(rest, tracer)
+		if err != nil {
+			return nil, fmt.Errorf("the call to MakeCloudStorageBackend failed: %w", err)
+		}
+		return backend, nil
+	case "s3":
+		return makeS3StorageBackend(rest, cfg, tracer)
+	case "file":
+		return makeLocalStorageBackend(rest, cfg.DownloadURL)
+	case "webdav":
+		return makeWebdavStorageBackend("http://"+rest, tracer)
+	case "webdavs":
+		return makeWebdavStorageBackend("https://"+rest, tracer)
+	case "dummy":
+		return makeDummyStorageBackend(), nil
+	}
+	return nil, fmt.Errorf("unknown UploadTo during StorageFromConfig(): %#v", cfg.UploadTo)
+}
+
 func (storage *Storage) AssetTypeEnabled(assetType dashapi.AssetType) bool {
 	return storage.cfg.IsEnabled(assetType)
 }
@@ -181,6 +195,9 @@ func (storage *Storage) UploadBuildAsset(reader io.Reader, fileName string, asse
 	}, nil
 }
 func (storage *Storage) ReportBuildAssets(build *dashapi.Build, assets ...dashapi.NewAsset) error {
+	if storage.dash == nil {
+		return errNoDashboard
+	}
 	// If the server denies the reques, we'll delete the orphaned file during deprecated files
 	// deletion later.
 	return storage.dash.AddBuildAssets(&dashapi.AddBuildAssetsReq{
@@ -201,6 +218,8 @@ func (storage *Storage) UploadCrashAsset(reader io.Reader, fileName string, asse
 	}, nil
 }
 
+var errNoDashboard = errors.New("the operation requires a dashboard")
+
 var ErrAssetDoesNotExist = errors.New("the asset did not exist")
 
 type FileExistsError struct {
@@ -219,6 +238,9 @@ const deletionEmbargo = time.Hour * 24 * 7
 // Best way: convert download URLs to paths.
 // We don't want to risk killing all assets after a slight domain change.
 func (storage *Storage) DeprecateAssets() error {
+	if storage.dash == nil {
+		return errNoDashboard
+	}
 	resp, err := storage.dash.NeededAssetsList()
 	if err != nil {
 		return fmt.Errorf("failed to query needed assets: %w", err)
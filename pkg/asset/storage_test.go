@@ -256,6 +256,26 @@ func TestUploadHtmlAsset(t *testing.T) {
 	}
 }
 
+func TestUploadReproNoDashboard(t *testing.T) {
+	storage, be := makeStorage(t, nil)
+	reproContent := []byte("r0 = open(&(0x7f0000000000)='./file0\\x00', 0x0, 0x0)\n")
+	var file *uploadedFile
+	be.objectUpload = collectBytes(&file)
+	asset, err := storage.UploadCrashAsset(bytes.NewReader(reproContent), "repro.syz", dashapi.ReproSyz, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(asset.DownloadURL, ".syz") {
+		t.Fatalf("%#v was expected to have .syz extension", asset.DownloadURL)
+	}
+	if err := validateGzip(file, reproContent); err != nil {
+		t.Fatalf("repro.syz validation failed: %s", err)
+	}
+	if err := storage.DeprecateAssets(); err == nil {
+		t.Fatalf("asset deprecation is expected to fail without a dashboard")
+	}
+}
+
 func TestRecentAssetDeletionProtection(t *testing.T) {
 	dashMock := newDashMock()
 	storage, be := makeStorage(t, dashMock)
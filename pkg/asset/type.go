@@ -57,6 +57,23 @@ var assetTypes = map[dashapi.AssetType]*TypeDescription{
 		// the omnipresent gzip compression.
 		customCompressor: gzipCompressor,
 	},
+	// The dashboard stores reproducers itself, these are only uploaded by standalone syz-manager instances.
+	dashapi.ReproSyz: {
+		GetTitle:          constTitle("syz reproducer"),
+		ReportingPrio:     6,
+		ContentType:       "text/plain",
+		ContentEncoding:   "gzip",
+		customCompressor:  gzipCompressor,
+		preserveExtension: true,
+	},
+	dashapi.ReproC: {
+		GetTitle:          constTitle("C reproducer"),
+		ReportingPrio:     7,
+		ContentType:       "text/plain",
+		ContentEncoding:   "gzip",
+		customCompressor:  gzipCompressor,
+		preserveExtension: true,
+	},
 }
 
 type QueryTypeTitle func(*targets.Target) string
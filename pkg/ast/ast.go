@@ -83,6 +83,17 @@ func (n *Incdir) Info() (Pos, string, string) {
 	return n.Pos, tok2str[tokInclude], ""
 }
 
+// IncludeConsts refers to a file with externally generated const values
+// (see compiler.ConstFile.AddExternal), the path is relative to the description file.
+type IncludeConsts struct {
+	Pos  Pos
+	File *String
+}
+
+func (n *IncludeConsts) Info() (Pos, string, string) {
+	return n.Pos, tok2str[tokIncludeConsts], ""
+}
+
 type Define struct {
 	Pos   Pos
 	Name  *Ident
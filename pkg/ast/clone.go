@@ -45,6 +45,13 @@ func (n *Incdir) Clone() Node {
 	}
 }
 
+func (n *IncludeConsts) Clone() Node {
+	return &IncludeConsts{
+		Pos:  n.Pos,
+		File: n.File.Clone().(*String),
+	}
+}
+
 func (n *Define) Clone() Node {
 	return &Define{
 		Pos:   n.Pos,
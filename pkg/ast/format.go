@@ -90,6 +90,10 @@ func (n *Incdir) serialize(w io.Writer) {
 	fmt.Fprintf(w, "incdir <%v>\n", n.Dir.Value)
 }
 
+func (n *IncludeConsts) serialize(w io.Writer) {
+	fmt.Fprintf(w, "include_consts <%v>\n", n.File.Value)
+}
+
 func (n *Define) serialize(w io.Writer) {
 	fmt.Fprintf(w, "define %v\t%v\n", n.Name.Name, fmtInt(n.Value))
 }
@@ -124,6 +124,8 @@ func (p *parser) parseTop() Node {
 		return p.parseInclude()
 	case tokIncdir:
 		return p.parseIncdir()
+	case tokIncludeConsts:
+		return p.parseIncludeConsts()
 	case tokResource:
 		return p.parseResource()
 	case tokIdent:
@@ -236,6 +238,15 @@ func (p *parser) parseIncdir() *Incdir {
 	}
 }
 
+func (p *parser) parseIncludeConsts() *IncludeConsts {
+	pos0 := p.pos
+	p.consume(tokIncludeConsts)
+	return &IncludeConsts{
+		Pos:  pos0,
+		File: p.parseString(),
+	}
+}
+
 func (p *parser) parseResource() *Resource {
 	pos0 := p.pos
 	p.consume(tokResource)
@@ -19,6 +19,7 @@ const (
 	tokIdent
 	tokInclude
 	tokIncdir
+	tokIncludeConsts
 	tokDefine
 	tokResource
 	tokString
@@ -58,21 +59,22 @@ var punctuation = [256]token{
 }
 
 var tok2str = [...]string{
-	tokIllegal:   "ILLEGAL",
-	tokComment:   "comment",
-	tokIdent:     "identifier",
-	tokInclude:   "include",
-	tokIncdir:    "incdir",
-	tokDefine:    "define",
-	tokResource:  "resource",
-	tokString:    "string",
-	tokStringHex: "hex string",
-	tokCExpr:     "CEXPR",
-	tokInt:       "int",
-	tokNewLine:   "NEWLINE",
-	tokEOF:       "EOF",
-	tokCmpEq:     "==",
-	tokCmpNeq:    "!=",
+	tokIllegal:       "ILLEGAL",
+	tokComment:       "comment",
+	tokIdent:         "identifier",
+	tokInclude:       "include",
+	tokIncdir:        "incdir",
+	tokIncludeConsts: "include_consts",
+	tokDefine:        "define",
+	tokResource:      "resource",
+	tokString:        "string",
+	tokStringHex:     "hex string",
+	tokCExpr:         "CEXPR",
+	tokInt:           "int",
+	tokNewLine:       "NEWLINE",
+	tokEOF:           "EOF",
+	tokCmpEq:         "==",
+	tokCmpNeq:        "!=",
 }
 
 func init() {
@@ -85,10 +87,11 @@ func init() {
 }
 
 var keywords = map[string]token{
-	"include":  tokInclude,
-	"incdir":   tokIncdir,
-	"define":   tokDefine,
-	"resource": tokResource,
+	"include":        tokInclude,
+	"incdir":         tokIncdir,
+	"include_consts": tokIncludeConsts,
+	"define":         tokDefine,
+	"resource":       tokResource,
 }
 
 func (tok token) String() string {
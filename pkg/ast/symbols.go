@@ -0,0 +1,70 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package ast
+
+// Symbol is an occurrence of a name of a top-level entity (syscall, resource, struct/union,
+// flags, type or define) or of a const in the description.
+type Symbol struct {
+	Name string
+	Pos  Pos
+	// Set for the occurrence that declares the entity, unset for references to it.
+	Def bool
+}
+
+// Symbols returns all declarations and references of top-level entities and consts
+// in the order they appear in the description. References are not resolved,
+// e.g. they include builtin types, consts defined in const files and template arguments.
+func (desc *Description) Symbols() []Symbol {
+	var syms []Symbol
+	for _, node := range desc.Nodes {
+		var name *Ident
+		switch n := node.(type) {
+		case *Resource:
+			name = n.Name
+		case *Call:
+			name = n.Name
+		case *Struct:
+			name = n.Name
+		case *IntFlags:
+			name = n.Name
+		case *StrFlags:
+			name = n.Name
+		case *TypeDef:
+			name = n.Name
+		case *Define:
+			name = n.Name
+		}
+		if name != nil {
+			syms = append(syms, Symbol{Name: name.Name, Pos: name.Pos, Def: true})
+		}
+		node.walk(Recursive(func(n Node) bool {
+			switch n := n.(type) {
+			case *Type:
+				// Parts after colon (ranges, bitfields) are not walked.
+				for _, t := range append([]*Type{n}, n.Colon...) {
+					if t.Ident != "" {
+						syms = append(syms, Symbol{Name: t.Ident, Pos: t.Pos})
+					}
+				}
+			case *Int:
+				if n.Ident != "" {
+					syms = append(syms, Symbol{Name: n.Ident, Pos: n.Pos})
+				}
+			}
+			return true
+		}))
+	}
+	return syms
+}
+
+// SymbolAt returns the symbol that covers the given position (only File, Line and Col are used).
+func SymbolAt(syms []Symbol, pos Pos) (Symbol, bool) {
+	for _, sym := range syms {
+		if sym.Pos.File == pos.File && sym.Pos.Line == pos.Line &&
+			pos.Col >= sym.Pos.Col && pos.Col < sym.Pos.Col+len(sym.Name) {
+			return sym, true
+		}
+	}
+	return Symbol{}, false
+}
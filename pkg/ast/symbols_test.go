@@ -0,0 +1,58 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbols(t *testing.T) {
+	const data = `resource fd_foo[fd]
+foo(fd fd_foo, arg ptr[in, foo_struct], flags flags[foo_flags])
+foo_struct {
+	f0	int32[FOO_MIN:FOO_MAX]
+}
+foo_flags = FOO_A, FOO_B
+define FOO_MAX	10
+`
+	desc := Parse([]byte(data), "foo.txt", func(pos Pos, msg string) {
+		t.Fatalf("%v: %v", pos, msg)
+	})
+	var got []string
+	syms := desc.Symbols()
+	for _, sym := range syms {
+		kind := "ref"
+		if sym.Def {
+			kind = "def"
+		}
+		got = append(got, kind+" "+sym.Name+" "+sym.Pos.String())
+	}
+	assert.Equal(t, []string{
+		"def fd_foo foo.txt:1:10",
+		"ref fd foo.txt:1:17",
+		"def foo foo.txt:2:1",
+		"ref fd_foo foo.txt:2:8",
+		"ref ptr foo.txt:2:20",
+		"ref in foo.txt:2:24",
+		"ref foo_struct foo.txt:2:28",
+		"ref flags foo.txt:2:47",
+		"ref foo_flags foo.txt:2:53",
+		"def foo_struct foo.txt:3:1",
+		"ref int32 foo.txt:4:5",
+		"ref FOO_MIN foo.txt:4:11",
+		"ref FOO_MAX foo.txt:4:19",
+		"def foo_flags foo.txt:6:1",
+		"ref FOO_A foo.txt:6:13",
+		"ref FOO_B foo.txt:6:20",
+		"def FOO_MAX foo.txt:7:8",
+	}, got)
+
+	sym, ok := SymbolAt(syms, Pos{File: "foo.txt", Line: 2, Col: 35})
+	assert.True(t, ok)
+	assert.Equal(t, "foo_struct", sym.Name)
+	_, ok = SymbolAt(syms, Pos{File: "foo.txt", Line: 2, Col: 5})
+	assert.False(t, ok)
+}
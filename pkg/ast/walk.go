@@ -48,6 +48,10 @@ func (n *Incdir) walk(cb func(Node)) {
 	cb(n.Dir)
 }
 
+func (n *IncludeConsts) walk(cb func(Node)) {
+	cb(n.File)
+}
+
 func (n *Define) walk(cb func(Node)) {
 	cb(n.Name)
 	cb(n.Value)
@@ -4,11 +4,13 @@
 package bisect
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"os"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/syzkaller/pkg/build"
@@ -41,6 +43,10 @@ type Config struct {
 	// Kernel.Commit is not reachable from Kernel.Branch.
 	// In this case, bisection starts from their merge base.
 	CrossTree bool
+	// SpeculativePools are additional managers (each with its own KernelSrc checkout of
+	// the kernel repo and its own VMs) that are used to test the commits bisection may need
+	// next in parallel with the current one. If empty, commits are tested one by one.
+	SpeculativePools []*mgrconfig.Config
 }
 
 type KernelConfig struct {
@@ -100,10 +106,46 @@ type env struct {
 	flaky bool
 	// A cache of already performed revision tests.
 	results map[string]*testResult
+	// The pool that builds and tests the current HEAD of repo.
+	main *testPool
+	// Speculative pools that don't run any tests now.
+	idlePools []*testPool
+	// Speculative tests by commit hash (both running and finished).
+	specTests map[string]*specTest
+	specUsed  int
+	// Cancelled once bisection is finished, so that speculative tests don't start new steps.
+	specCtx    context.Context
+	specCancel context.CancelFunc
+	// Protects buildTime, testTime and the trace that are updated by speculative tests.
+	mu sync.Mutex
+}
+
+// testPool is a kernel checkout with VMs that can build and test kernels independently of other pools.
+type testPool struct {
+	mgr      *mgrconfig.Config
+	repo     vcs.Repo
+	bisecter vcs.Bisecter
+	inst     instance.Env
+}
+
+// specTest is a speculative test of a commit that bisection may need next.
+type specTest struct {
+	pool *testPool // nil once the pool is returned to idlePools
+	done chan struct{}
+	run  *testRun
 }
 
 const MaxNumTests = 20 // number of tests we do per commit
 
+const (
+	// A "good" verdict with a lower confidence is re-tested up to maxGoodRetests times.
+	retestConfidence = 0.9
+	maxGoodRetests   = 2
+	// If bisection points to a merge commit and the accumulated confidence is lower,
+	// the merge commit parents are re-tested before blaming the merge commit.
+	mergeConfidence = 0.95
+)
+
 // Result describes bisection result:
 // 1. if bisection is conclusive, the single cause/fix commit in Commits
 //   - for cause bisection report is the crash on the cause commit
@@ -130,6 +172,7 @@ type Result struct {
 	Config     []byte
 	NoopChange bool
 	IsRelease  bool
+	// The estimated probability that the result is correct.
 	Confidence float64
 }
 
@@ -159,10 +202,43 @@ func Run(cfg *Config) (*Result, error) {
 	if _, err = repo.CheckoutBranch(cfg.Kernel.Repo, cfg.Kernel.Branch); err != nil {
 		return nil, &InfraError{Title: fmt.Sprintf("%v", err)}
 	}
-	return runImpl(cfg, repo, inst)
+	var pools []*testPool
+	for _, mgr := range cfg.SpeculativePools {
+		pool, err := newTestPool(cfg, mgr)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, pool)
+	}
+	return runImpl(cfg, repo, inst, pools)
+}
+
+func newTestPool(cfg *Config, mgr *mgrconfig.Config) (*testPool, error) {
+	mgr.Cover = false
+	repo, err := vcs.NewRepo(mgr.TargetOS, mgr.Type, mgr.KernelSrc)
+	if err != nil {
+		return nil, err
+	}
+	bisecter, ok := repo.(vcs.Bisecter)
+	if !ok {
+		return nil, fmt.Errorf("bisection is not implemented for %v", mgr.TargetOS)
+	}
+	inst, err := instance.NewEnv(mgr, cfg.BuildSemaphore, cfg.TestSemaphore)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = repo.CheckoutBranch(cfg.Kernel.Repo, cfg.Kernel.Branch); err != nil {
+		return nil, &InfraError{Title: fmt.Sprintf("%v", err)}
+	}
+	return &testPool{
+		mgr:      mgr,
+		repo:     repo,
+		bisecter: bisecter,
+		inst:     inst,
+	}, nil
 }
 
-func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
+func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env, pools []*testPool) (*Result, error) {
 	bisecter, ok := repo.(vcs.Bisecter)
 	if !ok {
 		return nil, fmt.Errorf("bisection is not implemented for %v", cfg.Manager.TargetOS)
@@ -179,7 +255,17 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 		inst:       inst,
 		startTime:  time.Now(),
 		confidence: 1.0,
-	}
+		main: &testPool{
+			mgr:      cfg.Manager,
+			repo:     repo,
+			bisecter: bisecter,
+			inst:     inst,
+		},
+		idlePools: append([]*testPool{}, pools...),
+		specTests: make(map[string]*specTest),
+	}
+	env.specCtx, env.specCancel = context.WithCancel(context.Background())
+	defer env.specCancel()
 	head, err := repo.HeadCommit()
 	if err != nil {
 		return nil, err
@@ -198,11 +284,15 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 	}
 	start := time.Now()
 	res, err := env.bisect()
+	env.waitSpeculative()
 	if env.flaky {
 		env.log("reproducer is flaky (%.2f repro chance estimate)", env.reproChance)
 	}
 	env.log("revisions tested: %v, total time: %v (build: %v, test: %v)",
 		env.numTests, time.Since(start), env.buildTime, env.testTime)
+	if len(env.specTests) != 0 {
+		env.log("speculatively tested revisions: %v, used: %v", len(env.specTests), env.specUsed)
+	}
 	if err != nil {
 		env.log("error: %v", err)
 		return nil, err
@@ -256,6 +346,11 @@ func (env *env) bisect() (*Result, error) {
 	if _, err := env.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
 		return nil, err
 	}
+	for _, pool := range env.idlePools {
+		if _, err := pool.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
+			return nil, err
+		}
+	}
 
 	cfg.Kernel.Commit, err = env.identifyRewrittenCommit()
 	if err != nil {
@@ -319,6 +414,13 @@ func (env *env) bisect() (*Result, error) {
 		if testRes == nil {
 			return nil, fmt.Errorf("no result for culprit commit")
 		}
+		if len(com.Parents) > 1 && res.Confidence < mergeConfidence {
+			verified, err := env.verifyMerge(com)
+			if err != nil {
+				return nil, err
+			}
+			res.Confidence *= verified
+		}
 		res.Report = testRes.rep
 		isRelease, err := env.bisecter.IsRelease(com.Hash)
 		if err != nil {
@@ -426,6 +528,45 @@ func (env *env) minimizeConfig() (*testResult, error) {
 	return testResults[hash.Hash(minConfig)], nil
 }
 
+// verifyMerge re-tests the revisions that bisection considered not to contain the bug
+// (the parents for cause bisection and the merge itself for fix bisection) and returns
+// the confidence of the verification, which is applied on top of the bisection confidence.
+// Bisection to a merge commit with a low confidence is often caused by a false negative
+// result on one of the merged branches. If the bug is reproducible on one of the revisions,
+// the result is inconclusive and the returned confidence is 0.
+func (env *env) verifyMerge(com *vcs.Commit) (float64, error) {
+	env.log("bisected to merge commit %v with a low confidence, verifying", com.Hash)
+	hashes := com.Parents
+	if env.cfg.Fix {
+		hashes = []string{com.Hash}
+	}
+	confidence := 1.0
+	for _, hash := range hashes {
+		if _, err := env.repo.SwitchCommit(hash); err != nil {
+			return 0, err
+		}
+		testRes, err := env.test()
+		if err != nil {
+			return 0, err
+		}
+		if testRes, err = env.retestGood(testRes); err != nil {
+			return 0, err
+		}
+		switch testRes.verdict {
+		case vcs.BisectBad:
+			env.log("the bug is reproducible on %v, the result is inconclusive", hash)
+			return 0, nil
+		case vcs.BisectGood:
+			confidence *= testRes.confidence
+		default:
+			env.log("unable to verify %v, keeping the bisection confidence", hash)
+			return 1, nil
+		}
+	}
+	env.log("merge commit verification confidence: %.2f", confidence)
+	return confidence, nil
+}
+
 func (env *env) detectNoopChange(com *vcs.Commit) (bool, error) {
 	testRes := env.results[com.Hash]
 	if testRes.kernelSign == "" || len(com.Parents) != 1 {
@@ -444,7 +585,7 @@ func (env *env) detectNoopChange(com *vcs.Commit) (bool, error) {
 		if _, err := env.repo.SwitchCommit(parent); err != nil {
 			return false, err
 		}
-		_, kernelSign, err := env.build()
+		_, kernelSign, err := env.build(env.main)
 		if err != nil {
 			return false, err
 		}
@@ -592,17 +733,19 @@ type testResult struct {
 	kernelSign string
 	// The ratio of bad/(good+bad) results.
 	badRatio float64
+	// The number of runs that did not crash the kernel.
+	good int
 	// An estimate how much we can trust the result.
 	confidence float64
 }
 
-func (env *env) build() (*vcs.Commit, string, error) {
-	current, err := env.repo.HeadCommit()
+func (env *env) build(p *testPool) (*vcs.Commit, string, error) {
+	current, err := p.repo.HeadCommit()
 	if err != nil {
 		return nil, "", err
 	}
 
-	bisectEnv, err := env.bisecter.EnvForCommit(
+	bisectEnv, err := p.bisecter.EnvForCommit(
 		env.cfg.DefaultCompiler, env.cfg.CompilerType,
 		env.cfg.BinDir, current.Hash, env.kernelConfig,
 		env.cfg.Kernel.Backports,
@@ -612,12 +755,12 @@ func (env *env) build() (*vcs.Commit, string, error) {
 	}
 	env.log("testing commit %v %v", current.Hash, env.cfg.CompilerType)
 	buildStart := time.Now()
-	mgr := env.cfg.Manager
+	mgr := p.mgr
 	if err := build.Clean(mgr.TargetOS, mgr.TargetVMArch, mgr.Type, mgr.KernelSrc); err != nil {
 		return current, "", fmt.Errorf("kernel clean failed: %w", err)
 	}
 	kern := &env.cfg.Kernel
-	_, imageDetails, err := env.inst.BuildKernel(&instance.BuildKernelConfig{
+	_, imageDetails, err := p.inst.BuildKernel(&instance.BuildKernelConfig{
 		CompilerBin:  bisectEnv.Compiler,
 		LinkerBin:    env.cfg.Linker,
 		CcacheBin:    env.cfg.Ccache,
@@ -632,7 +775,9 @@ func (env *env) build() (*vcs.Commit, string, error) {
 	if imageDetails.Signature != "" {
 		env.log("kernel signature: %v", imageDetails.Signature)
 	}
+	env.mu.Lock()
 	env.buildTime += time.Since(buildStart)
+	env.mu.Unlock()
 	return current, imageDetails.Signature, err
 }
 
@@ -640,22 +785,74 @@ func (env *env) build() (*vcs.Commit, string, error) {
 // Hence recoverable errors must be handled and the callers must treat testResult with care.
 // e.g. testResult.verdict will be vcs.BisectSkip for a broken build, but err will be nil.
 func (env *env) test() (*testResult, error) {
-	cfg := env.cfg
-	if cfg.Timeout != 0 && time.Since(env.startTime) > cfg.Timeout {
-		return nil, fmt.Errorf("bisection is taking too long (>%v), aborting", cfg.Timeout)
+	if err := env.checkTimeout(); err != nil {
+		return nil, err
+	}
+	return env.testResult(env.runTest(context.Background(), env.main, env.numVMs()))
+}
+
+func (env *env) checkTimeout() error {
+	if env.cfg.Timeout != 0 && time.Since(env.startTime) > env.cfg.Timeout {
+		return fmt.Errorf("bisection is taking too long (>%v), aborting", env.cfg.Timeout)
 	}
-	current, kernelSign, err := env.build()
+	return nil
+}
+
+func (env *env) numVMs() int {
+	numTests := MaxNumTests / 2
+	if env.flaky || env.numTests == 0 {
+		// Use twice as many instances if the bug is flaky and during initial testing
+		// (as we don't know yet if it's flaky or not).
+		numTests *= 2
+	}
+	return numTests
+}
+
+// testRun is the raw outcome of building and testing a revision.
+type testRun struct {
+	com        *vcs.Commit
+	kernelSign string
+	buildErr   error
+	results    []instance.EnvTestResult
+	testErr    error
+}
+
+// runTest builds and tests the current HEAD of the pool.
+// It does not update the bisection state, so it can run concurrently with other tests.
+// If ctx is cancelled during the build, the kernel is not tested.
+func (env *env) runTest(ctx context.Context, p *testPool, numVMs int) *testRun {
+	run := new(testRun)
+	run.com, run.kernelSign, run.buildErr = env.build(p)
+	if run.buildErr != nil {
+		return run
+	}
+	if err := ctx.Err(); err != nil {
+		run.testErr = err
+		return run
+	}
+	testStart := time.Now()
+	cfg := env.cfg
+	run.results, run.testErr = p.inst.Test(numVMs, cfg.Repro.Syz, cfg.Repro.Opts, cfg.Repro.C)
+	env.mu.Lock()
+	env.testTime += time.Since(testStart)
+	env.mu.Unlock()
+	return run
+}
+
+// testResult interprets the results of runTest and updates the bisection state accordingly.
+func (env *env) testResult(run *testRun) (*testResult, error) {
+	current := run.com
 	res := &testResult{
 		verdict:    vcs.BisectSkip,
 		com:        current,
-		kernelSign: kernelSign,
+		kernelSign: run.kernelSign,
 		confidence: 1.0,
 	}
 	if current == nil {
 		// This is not recoverable, as the caller must know which commit to skip.
-		return res, fmt.Errorf("couldn't get repo HEAD: %w", err)
+		return res, fmt.Errorf("couldn't get repo HEAD: %w", run.buildErr)
 	}
-	if err != nil {
+	if err := run.buildErr; err != nil {
 		errInfo := fmt.Sprintf("failed building %v: ", current.Hash)
 		var verr *osutil.VerboseError
 		var kerr *build.KernelError
@@ -674,19 +871,9 @@ func (env *env) test() (*testResult, error) {
 		res.rep = &report.Report{Title: errInfo}
 		return res, nil
 	}
-
-	numTests := MaxNumTests / 2
-	if env.flaky || env.numTests == 0 {
-		// Use twice as many instances if the bug is flaky and during initial testing
-		// (as we don't know yet if it's flaky or not).
-		numTests *= 2
-	}
 	env.numTests++
 
-	testStart := time.Now()
-
-	results, err := env.inst.Test(numTests, cfg.Repro.Syz, cfg.Repro.Opts, cfg.Repro.C)
-	env.testTime += time.Since(testStart)
+	results, err := run.results, run.testErr
 	if err != nil {
 		problem := fmt.Sprintf("repro testing failure: %v", err)
 		env.log(problem)
@@ -700,9 +887,10 @@ func (env *env) test() (*testResult, error) {
 	if bad+good > 0 {
 		res.badRatio = float64(bad) / float64(bad+good)
 	}
+	res.good = good
 	if res.verdict == vcs.BisectGood {
 		// The result could be a false negative.
-		res.confidence = 1.0 - math.Pow(1.0-env.reproChance, float64(good))
+		res.confidence = env.goodConfidence(good)
 		env.log("false negative chance: %.3f", 1.0-res.confidence)
 	}
 	if res.verdict == vcs.BisectSkip {
@@ -722,6 +910,7 @@ func (env *env) test() (*testResult, error) {
 
 // testPredicate() is meant to be invoked by bisecter.Bisect().
 func (env *env) testPredicate() (vcs.BisectResult, error) {
+	env.speculate()
 	var testRes1 *testResult
 	if env.cfg.Fix {
 		// There's a chance we might test a revision that does not yet contain the bug.
@@ -742,7 +931,11 @@ func (env *env) testPredicate() (vcs.BisectResult, error) {
 	}
 	if testRes1 == nil {
 		var err error
-		testRes1, err = env.test()
+		testRes1, err = env.testHead()
+		if err != nil {
+			return 0, err
+		}
+		testRes1, err = env.retestGood(testRes1)
 		if err != nil {
 			return 0, err
 		}
@@ -760,6 +953,124 @@ func (env *env) testPredicate() (vcs.BisectResult, error) {
 	return testRes1.verdict, nil
 }
 
+// goodConfidence returns the probability that good runs that did not crash the kernel
+// are not a false negative given the current reproducibility estimate.
+func (env *env) goodConfidence(good int) float64 {
+	return 1.0 - math.Pow(1.0-env.reproChance, float64(good))
+}
+
+// retestGood re-tests the current HEAD while a "good" verdict is not trustworthy enough.
+// For flaky reproducers a single round of runs has a high chance of a false negative,
+// and one wrong "good" verdict makes the whole bisection point to a wrong commit.
+func (env *env) retestGood(res *testResult) (*testResult, error) {
+	for i := 0; i < maxGoodRetests && res.verdict == vcs.BisectGood &&
+		res.confidence < retestConfidence; i++ {
+		env.log("re-testing %v to reduce the false negative chance", res.com.Hash)
+		res1, err := env.test()
+		if err != nil {
+			return nil, err
+		}
+		switch res1.verdict {
+		case vcs.BisectBad:
+			return res1, nil
+		case vcs.BisectGood:
+			res1.good += res.good
+			res1.confidence = env.goodConfidence(res1.good)
+			env.log("accumulated false negative chance: %.3f", 1.0-res1.confidence)
+			res = res1
+		}
+	}
+	return res, nil
+}
+
+// speculate starts testing the commits that bisection may test next (for both possible
+// verdicts for the current HEAD) on the idle speculative pools.
+func (env *env) speculate() {
+	for _, st := range env.specTests {
+		if st.pool == nil {
+			continue
+		}
+		select {
+		case <-st.done:
+			env.idlePools = append(env.idlePools, st.pool)
+			st.pool = nil
+		default:
+		}
+	}
+	predictor, ok := env.bisecter.(vcs.BisectPredictor)
+	if !ok || len(env.idlePools) == 0 {
+		return
+	}
+	commits, err := predictor.NextBisectCommits()
+	if err != nil {
+		env.log("failed to predict next bisection commits: %v", err)
+		return
+	}
+	numVMs := env.numVMs()
+	for _, commit := range commits {
+		commit := commit
+		if len(env.idlePools) == 0 {
+			break
+		}
+		if env.results[commit] != nil || env.specTests[commit] != nil {
+			continue
+		}
+		pool := env.idlePools[len(env.idlePools)-1]
+		env.idlePools = env.idlePools[:len(env.idlePools)-1]
+		st := &specTest{
+			pool: pool,
+			done: make(chan struct{}),
+		}
+		env.specTests[commit] = st
+		env.log("speculatively testing %v on %v", commit, pool.mgr.Name)
+		go func() {
+			defer close(st.done)
+			if err := env.specCtx.Err(); err != nil {
+				st.run = &testRun{buildErr: err}
+				return
+			}
+			if _, err := pool.repo.SwitchCommit(commit); err != nil {
+				env.log("failed to switch %v to %v: %v", pool.mgr.Name, commit, err)
+				st.run = &testRun{buildErr: err}
+				return
+			}
+			st.run = env.runTest(env.specCtx, pool, numVMs)
+		}()
+	}
+}
+
+// testHead tests the current HEAD, or uses the result of its speculative test if there is one.
+func (env *env) testHead() (*testResult, error) {
+	if err := env.checkTimeout(); err != nil {
+		return nil, err
+	}
+	head, err := env.repo.HeadCommit()
+	if err != nil {
+		return nil, err
+	}
+	st := env.specTests[head.Hash]
+	if st == nil {
+		return env.test()
+	}
+	<-st.done
+	if st.run.com == nil || st.run.com.Hash != head.Hash {
+		env.log("speculative test of %v failed, testing it again", head.Hash)
+		return env.test()
+	}
+	env.log("using speculative test results for %v", head.Hash)
+	env.specUsed++
+	return env.testResult(st.run)
+}
+
+// waitSpeculative cancels speculative tests and waits for them to finish their current step
+// (a build or a test can't be interrupted, but the rest of the test is skipped).
+func (env *env) waitSpeculative() {
+	env.specCancel()
+	for _, st := range env.specTests {
+		<-st.done
+	}
+}
+
 // If there's a merge from a branch that was based on a much older code revision,
 // it's likely that the bug was not yet present at all.
 var errUnknownBugPresence = errors.New("unable to determine whether there was a bug")
@@ -1042,6 +1353,8 @@ func (env *env) log(msg string, args ...interface{}) {
 	if false {
 		_ = fmt.Sprintf(msg, args...) // enable printf checker
 	}
+	env.mu.Lock()
+	defer env.mu.Unlock()
 	env.cfg.Trace.Log(msg, args...)
 }
 
@@ -6,6 +6,7 @@ package bisect
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -29,6 +30,8 @@ type testEnv struct {
 	// Kernel config used in "build"
 	config string
 	test   BisectionTest
+	// Number of Test calls.
+	numTests int
 }
 
 func (env *testEnv) BuildSyzkaller(repo, commit string) (string, error) {
@@ -51,6 +54,7 @@ func (env *testEnv) BuildKernel(buildCfg *instance.BuildKernelConfig) (string, b
 }
 
 func (env *testEnv) Test(numVMs int, reproSyz, reproOpts, reproC []byte) ([]instance.EnvTestResult, error) {
+	env.numTests++
 	commit := env.headCommit()
 	if commit >= env.test.brokenStart && commit <= env.test.brokenEnd ||
 		env.config == "baseline-skip" {
@@ -247,16 +251,58 @@ func testBisection(t *testing.T, baseDir string, test BisectionTest) {
 		}
 	}
 
-	res, err := runImpl(cfg, r, inst)
+	var pools []*testPool
+	for i := 0; i < test.speculativePools; i++ {
+		pools = append(pools, createTestPool(t, baseDir, test))
+	}
+	res, err := runImpl(cfg, r, inst, pools)
 	checkBisectionError(test, res, err)
+	if len(pools) != 0 && err == nil && len(res.Commits) == 1 {
+		speculative := 0
+		for _, pool := range pools {
+			speculative += pool.inst.(*testEnv).numTests
+		}
+		if speculative == 0 {
+			t.Fatalf("no speculative tests were run")
+		}
+	}
 	if !test.crossTree && !test.noFakeHashTest {
 		// Should be mitigated via GetCommitByTitle during bisection.
 		cfg.Kernel.Commit = fmt.Sprintf("fake-hash-for-%v-%v", cfg.Kernel.Commit, cfg.Kernel.CommitTitle)
-		res, err = runImpl(cfg, r, inst)
+		res, err = runImpl(cfg, r, inst, pools)
 		checkBisectionError(test, res, err)
 	}
 }
 
+func createTestPool(t *testing.T, baseDir string, test BisectionTest) *testPool {
+	dir := t.TempDir()
+	r, err := vcs.NewRepo(targets.TestOS, targets.TestArch64, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CheckoutBranch(baseDir, "master"); err != nil {
+		t.Fatal(err)
+	}
+	return &testPool{
+		mgr: &mgrconfig.Config{
+			Name: filepath.Base(dir),
+			Derived: mgrconfig.Derived{
+				TargetOS:     targets.TestOS,
+				TargetVMArch: targets.TestArch64,
+			},
+			Type:      "qemu",
+			KernelSrc: dir,
+		},
+		repo:     r,
+		bisecter: r.(vcs.Bisecter),
+		inst: &testEnv{
+			t:    t,
+			r:    r,
+			test: test,
+		},
+	}
+}
+
 func checkBisectionResult(t *testing.T, test BisectionTest, res *Result) {
 	if len(res.Commits) != test.commitLen {
 		t.Fatalf("expected %d commits got %d commits", test.commitLen, len(res.Commits))
@@ -328,6 +374,8 @@ type BisectionTest struct {
 	resultingConfig string
 	crossTree       bool
 	noFakeHashTest  bool
+	// Number of speculative pools used to test next bisection commits in parallel.
+	speculativePools int
 
 	extraTest func(t *testing.T, res *Result)
 }
@@ -352,10 +400,27 @@ var bisectionTests = []BisectionTest{
 		flaky:       true,
 		introduced:  "605",
 		extraTest: func(t *testing.T, res *Result) {
-			// False negative probability of each run is ~35%.
-			// We get three "good" results, so our accumulated confidence is ~27%.
-			assert.Less(t, res.Confidence, 0.3)
-			assert.Greater(t, res.Confidence, 0.2)
+			// False negative probability of each run is ~35%, so every "good" result
+			// is re-tested twice and its false negative probability drops to ~5%.
+			// We get three "good" results, so our accumulated confidence is ~85%.
+			assert.Less(t, res.Confidence, 0.9)
+			assert.Greater(t, res.Confidence, 0.8)
+		},
+	},
+	{
+		name:        "cause-finds-merge-flaky",
+		startCommit: 905,
+		commitLen:   1,
+		expectRep:   true,
+		flaky:       true,
+		introduced:  "804",
+		extraTest: func(t *testing.T, res *Result) {
+			assert.Equal(t, "804", res.Commits[0].Title)
+			assert.Len(t, res.Commits[0].Parents, 2)
+			// Both merge parents are re-verified as good, the verification confidence
+			// is applied on top of the bisection confidence.
+			assert.Less(t, res.Confidence, mergeConfidence)
+			assert.Greater(t, res.Confidence, 0.5)
 		},
 	},
 	// Test bisection returns correct cause with different baseline/config combinations.
@@ -726,6 +791,31 @@ func TestBisectionResults(t *testing.T) {
 	})
 }
 
+func TestBisectionSpeculative(t *testing.T) {
+	t.Parallel()
+	// Speculative testing must not change results of any of the bisections.
+	repoCache := make(chan string, len(bisectionTests))
+	t.Run("group", func(tt *testing.T) {
+		for _, test := range bisectionTests {
+			test := test
+			test.speculativePools = 2
+			tt.Run(test.name, func(t *testing.T) {
+				t.Parallel()
+				repoDir := ""
+				select {
+				case repoDir = <-repoCache:
+				default:
+					repoDir = createTestRepo(tt)
+				}
+				defer func() {
+					repoCache <- repoDir
+				}()
+				testBisection(t, repoDir, test)
+			})
+		}
+	})
+}
+
 func checkTest(t *testing.T, test BisectionTest) {
 	if test.expectErr &&
 		(test.commitLen != 0 ||
@@ -38,6 +38,17 @@ type Params struct {
 	Config       []byte
 	Tracer       debugtracer.DebugTracer
 	Build        json.RawMessage
+	// Out-of-tree kernel modules (currently supported only for linux).
+	ExternalModules []ExternalModule
+}
+
+// ExternalModule is an out-of-tree kernel module (e.g. a vendor driver) that is built
+// against the kernel and installed into the image.
+type ExternalModule struct {
+	// Directory with the module sources (with a Kbuild file or a kbuild Makefile).
+	Dir string `json:"dir"`
+	// Load the module on boot.
+	Load bool `json:"load"`
 }
 
 // Information that is returned from the Image function.
@@ -50,6 +61,8 @@ type ImageDetails struct {
 // Kernel is taken from KernelDir, userspace system is taken from UserspaceDir.
 // If CmdlineFile is not empty, contents of the file are appended to the kernel command line.
 // If SysctlFile is not empty, contents of the file are appended to the image /etc/sysctl.conf.
+// ExternalModules are built against the kernel and installed into the image /lib/modules,
+// modules that need to be loaded on boot are listed in /etc/modules-load.d/syzkaller.conf.
 // Output is stored in OutputDir and includes (everything except for image is optional):
 //   - image: the image
 //   - key: ssh key for the image
@@ -76,6 +89,10 @@ func Image(params Params) (details ImageDetails, err error) {
 	if err != nil {
 		return
 	}
+	if len(params.ExternalModules) != 0 && (params.TargetOS != targets.Linux || params.VMType == targets.GVisor) {
+		err = fmt.Errorf("external kernel modules are not supported for %v/%v", params.TargetOS, params.VMType)
+		return
+	}
 	if err = osutil.MkdirAll(filepath.Join(params.OutputDir, "obj")); err != nil {
 		return
 	}
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/google/syzkaller/pkg/debugtracer"
@@ -38,6 +39,14 @@ func (linux linux) build(params Params) (ImageDetails, error) {
 		return details, err
 	}
 
+	modulesDir, err := linux.buildExternalModules(params)
+	if err != nil {
+		return details, err
+	}
+	if modulesDir != "" {
+		defer os.RemoveAll(modulesDir)
+	}
+
 	kernelPath := filepath.Join(params.KernelDir, filepath.FromSlash(LinuxKernelImage(params.TargetArch)))
 
 	// Copy the kernel image to let it be uploaded to the asset storage. If the asset storage is not enabled,
@@ -49,17 +58,21 @@ func (linux linux) build(params Params) (ImageDetails, error) {
 	if fileInfo, err := os.Stat(params.UserspaceDir); err == nil && fileInfo.IsDir() {
 		// The old way of assembling the image from userspace dir.
 		// It should be removed once all syzbot instances are switched.
-		if err := linux.createImage(params, kernelPath); err != nil {
+		if err := linux.createImage(params, kernelPath, modulesDir); err != nil {
 			return details, err
 		}
 	} else if params.VMType == "qemu" {
 		// If UserspaceDir is a file (image) and we use qemu, we just copy image to the output dir assuming
 		// that qemu will use injected kernel boot. In this mode we also assume password/key-less ssh.
 		// The kernel image was already uploaded above.
-		if err := osutil.CopyFile(params.UserspaceDir, filepath.Join(params.OutputDir, "image")); err != nil {
+		if modulesDir != "" {
+			if err := embedLinuxModules(params, modulesDir); err != nil {
+				return details, err
+			}
+		} else if err := osutil.CopyFile(params.UserspaceDir, filepath.Join(params.OutputDir, "image")); err != nil {
 			return details, err
 		}
-	} else if err := embedLinuxKernel(params, kernelPath); err != nil {
+	} else if err := embedLinuxKernel(params, kernelPath, modulesDir); err != nil {
 		return details, err
 	}
 	vmlinux := filepath.Join(params.OutputDir, "obj", "vmlinux")
@@ -112,7 +125,71 @@ func (linux linux) buildKernel(params Params) error {
 	return nil
 }
 
-func (linux) createImage(params Params, kernelPath string) error {
+// buildExternalModules builds the out-of-tree modules against the kernel and installs them
+// into a staging directory that mirrors the image root (lib/modules/ and etc/modules-load.d/).
+// Returns an empty dir if there are no external modules.
+func (linux linux) buildExternalModules(params Params) (string, error) {
+	if len(params.ExternalModules) == 0 {
+		return "", nil
+	}
+	stagingDir, err := os.MkdirTemp("", "syz-modules")
+	if err != nil {
+		return "", err
+	}
+	if err := osutil.SandboxChown(stagingDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return "", err
+	}
+	var load []string
+	for _, mod := range params.ExternalModules {
+		dir := osutil.Abs(mod.Dir)
+		// The kernel is built without in-tree modules, so Module.symvers is missing
+		// and modpost can't check symbols of the module.
+		if err := runMake(params, "M="+dir, "KBUILD_MODPOST_WARN=1", "modules"); err != nil {
+			os.RemoveAll(stagingDir)
+			return "", fmt.Errorf("failed to build module %v: %w", mod.Dir, err)
+		}
+		if err := runMake(params, "M="+dir, "INSTALL_MOD_PATH="+stagingDir, "modules_install"); err != nil {
+			os.RemoveAll(stagingDir)
+			return "", fmt.Errorf("failed to install module %v: %w", mod.Dir, err)
+		}
+		if mod.Load {
+			order, err := os.ReadFile(filepath.Join(dir, "modules.order"))
+			if err != nil {
+				os.RemoveAll(stagingDir)
+				return "", fmt.Errorf("failed to read modules.order of %v: %w", mod.Dir, err)
+			}
+			load = append(load, parseModulesOrder(order)...)
+		}
+	}
+	if len(load) != 0 {
+		conf := filepath.Join(stagingDir, "etc", "modules-load.d", "syzkaller.conf")
+		if err := osutil.MkdirAll(filepath.Dir(conf)); err != nil {
+			os.RemoveAll(stagingDir)
+			return "", err
+		}
+		if err := osutil.WriteFile(conf, []byte(strings.Join(load, "\n")+"\n")); err != nil {
+			os.RemoveAll(stagingDir)
+			return "", err
+		}
+	}
+	return stagingDir, nil
+}
+
+// parseModulesOrder returns names of the modules listed in a kbuild modules.order file.
+func parseModulesOrder(data []byte) []string {
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(path.Base(line), ".ko"))
+	}
+	return names
+}
+
+func (linux) createImage(params Params, kernelPath, modulesDir string) error {
 	tempDir, err := os.MkdirTemp("", "syz-build")
 	if err != nil {
 		return err
@@ -129,6 +206,7 @@ func (linux) createImage(params Params, kernelPath string) error {
 		"SYZ_VM_TYPE="+params.VMType,
 		"SYZ_CMDLINE_FILE="+osutil.Abs(params.CmdlineFile),
 		"SYZ_SYSCTL_FILE="+osutil.Abs(params.SysctlFile),
+		"SYZ_MODULES_DIR="+modulesDir,
 	)
 	if _, err = osutil.Run(time.Hour, cmd); err != nil {
 		return fmt.Errorf("image build failed: %w", err)
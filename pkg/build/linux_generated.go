@@ -74,6 +74,10 @@ sudo mount $PARTDEV disk.mnt
 CLEANUP="sudo umount disk.mnt; $CLEANUP"
 sudo cp -a $1/. disk.mnt/.
 sudo cp $2 disk.mnt/vmlinuz
+SYZ_MODULES_DIR="${SYZ_MODULES_DIR:-}"
+if [ "$SYZ_MODULES_DIR" != "" ]; then
+	sudo cp -a $SYZ_MODULES_DIR/. disk.mnt/.
+fi
 sudo sed -i "/^root/ { s/:x:/::/ }" disk.mnt/etc/passwd
 echo "T0:23:respawn:/sbin/getty -L ttyS0 115200 vt100" | sudo tee -a disk.mnt/etc/inittab
 echo -en "auto lo\niface lo inet loopback\nauto eth0\niface eth0 inet dhcp\n" | sudo tee disk.mnt/etc/network/interfaces
@@ -21,15 +21,29 @@ import (
 // - ssh works without password (we don't copy the key)
 // - cmdline file is not supported (should be moved to kernel config)
 // - the kernel is stored in the image in /vmlinuz file.
-func embedLinuxKernel(params Params, kernelPath string) error {
+// If modulesDir is not empty, its contents are copied into the image root.
+func embedLinuxKernel(params Params, kernelPath, modulesDir string) error {
 	return embedFiles(params, func(mountDir string) error {
 		if err := copyKernel(mountDir, kernelPath); err != nil {
 			return err
 		}
+		if modulesDir != "" {
+			return osutil.CopyDirRecursively(modulesDir, mountDir)
+		}
 		return nil
 	})
 }
 
+// embedLinuxModules copies external kernel modules into an existing disk image
+// (the kernel itself is booted with qemu injected kernel boot).
+func embedLinuxModules(params Params, modulesDir string) error {
+	// The injected kernel boot gets the command line from the VM config.
+	params.CmdlineFile = ""
+	return embedFiles(params, func(mountDir string) error {
+		return osutil.CopyDirRecursively(modulesDir, mountDir)
+	})
+}
+
 // embedFiles mounts the disk image specified by params.UserspaceDir and then calls the given
 // callback function which should copy files into the image as needed.
 func embedFiles(params Params, callback func(mountDir string) error) error {
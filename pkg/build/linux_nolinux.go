@@ -9,7 +9,11 @@ import (
 	"errors"
 )
 
-func embedLinuxKernel(params Params, kernelPath string) error {
+func embedLinuxKernel(params Params, kernelPath, modulesDir string) error {
+	return errors.New("building linux image is only supported on linux")
+}
+
+func embedLinuxModules(params Params, modulesDir string) error {
 	return errors.New("building linux image is only supported on linux")
 }
 
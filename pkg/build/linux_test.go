@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"text/template"
@@ -41,6 +42,14 @@ func TestQueryLinuxCompiler(t *testing.T) {
 	}
 }
 
+func TestParseModulesOrder(t *testing.T) {
+	got := parseModulesOrder([]byte("foo.ko\ndrivers/bar/bar_core.ko\n\n  baz.ko  \n"))
+	want := []string{"foo", "bar_core", "baz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %q, expected: %q", got, want)
+	}
+}
+
 func enumerateFlags(t *testing.T, flags, allFlags []string) {
 	if len(allFlags) != 0 {
 		enumerateFlags(t, flags, allFlags[1:])
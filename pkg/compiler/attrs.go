@@ -18,6 +18,10 @@ const (
 	// This will facilitate const expressions in e.g. size[] or align[].
 	intAttr
 	exprAttr
+	// The argument is a name of int flags.
+	flagsRefAttr
+	// The arguments are names of syscalls.
+	callRefAttr
 )
 
 type attrDesc struct {
@@ -40,11 +44,18 @@ var (
 	attrInOut      = &attrDesc{Name: "inout"}
 	attrOutOverlay = &attrDesc{Name: "out_overlay"}
 	attrIf         = &attrDesc{Name: "if", Type: exprAttr}
+	attrPtrSize    = &attrDesc{Name: "ptrsize", Type: intAttr}
+	// Compile-time only call attribute, see checkIoctlOverlaps.
+	attrPolymorphic = &attrDesc{Name: "polymorphic"}
+	// Call attribute that lists expected errnos, stored in prog.Syscall.Errnos.
+	attrErrnos = &attrDesc{Name: "errnos", Type: flagsRefAttr}
+	// Call attribute that lists calls that must precede the call, stored in prog.Syscall.Requires.
+	attrRequires = &attrDesc{Name: "requires", Type: callRefAttr}
 
 	structAttrs      = makeAttrs(attrPacked, attrSize, attrAlign)
 	unionAttrs       = makeAttrs(attrVarlen, attrSize)
-	structFieldAttrs = makeAttrs(attrIn, attrOut, attrInOut, attrOutOverlay, attrIf)
-	unionFieldAttrs  = makeAttrs(attrIn, attrIf) // attrIn is safe.
+	structFieldAttrs = makeAttrs(attrIn, attrOut, attrInOut, attrOutOverlay, attrIf, attrPtrSize)
+	unionFieldAttrs  = makeAttrs(attrIn, attrIf, attrPtrSize) // attrIn is safe.
 	callAttrs        = make(map[string]*attrDesc)
 )
 
@@ -85,6 +96,9 @@ func initCallAttrs() {
 		}
 		callAttrs[prog.CppName(desc.Name)] = desc
 	}
+	callAttrs[attrPolymorphic.Name] = attrPolymorphic
+	callAttrs[attrErrnos.Name] = attrErrnos
+	callAttrs[attrRequires.Name] = attrRequires
 }
 
 func structOrUnionAttrs(n *ast.Struct) map[string]*attrDesc {
@@ -8,6 +8,7 @@ package compiler
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -31,8 +32,10 @@ func (comp *compiler) check(consts map[string]uint64) {
 	comp.checkAttributeValues()
 	comp.checkUnused()
 	comp.checkRecursion()
+	comp.checkRequiredCalls()
 	comp.checkFieldPaths()
 	comp.checkConstructors()
+	comp.checkChildProcessCalls()
 	comp.checkVarlens()
 	comp.checkDupConsts()
 	comp.checkConstsFlags(consts)
@@ -54,6 +57,7 @@ func (comp *compiler) checkDirectives() {
 	includes := make(map[string]bool)
 	incdirs := make(map[string]bool)
 	defines := make(map[string]bool)
+	includeConsts := make(map[string]bool)
 	for _, decl := range comp.desc.Nodes {
 		switch n := decl.(type) {
 		case *ast.Include:
@@ -70,6 +74,16 @@ func (comp *compiler) checkDirectives() {
 				comp.error(n.Pos, "duplicate incdir %q", name)
 			}
 			incdirs[path] = true
+		case *ast.IncludeConsts:
+			name := n.File.Value
+			if ext := filepath.Ext(name); ext != ".json" && ext != ".yaml" && ext != ".yml" {
+				comp.error(n.Pos, "include_consts file %q must be .json, .yaml or .yml", name)
+			}
+			path := n.Pos.File + "/" + name
+			if includeConsts[path] {
+				comp.error(n.Pos, "duplicate include_consts %q", name)
+			}
+			includeConsts[path] = true
 		case *ast.Define:
 			name := n.Name.Name
 			path := n.Pos.File + "/" + name
@@ -82,7 +96,6 @@ func (comp *compiler) checkDirectives() {
 }
 
 func (comp *compiler) checkNames() {
-	calls := make(map[string]*ast.Call)
 	for _, decl := range comp.desc.Nodes {
 		switch n := decl.(type) {
 		case *ast.Resource, *ast.Struct, *ast.TypeDef:
@@ -148,11 +161,11 @@ func (comp *compiler) checkNames() {
 			comp.strFlags[name] = n
 		case *ast.Call:
 			name := n.Name.Name
-			if prev := calls[name]; prev != nil {
+			if prev := comp.calls[name]; prev != nil {
 				comp.error(n.Pos, "syscall %v redeclared, previously declared at %v",
 					name, prev.Pos)
 			}
-			calls[name] = n
+			comp.calls[name] = n
 		}
 	}
 }
@@ -592,7 +605,7 @@ func (comp *compiler) validateFieldPathRec(t0, t *ast.Type, targets []*ast.Type,
 					// syscall arguments. Warn only once.
 					if !warned[parents[len(parents)-1].name] {
 						warned[parents[len(parents)-1].name] = true
-						comp.warning(target.Pos, "len target %v refer to an array with"+
+						comp.warning(target.Pos, WarnSuspiciousLen, "len target %v refer to an array with"+
 							" variable-size elements (do you mean bytesize?)",
 							target.Ident)
 					}
@@ -734,6 +747,11 @@ func (comp *compiler) collectUsed(all bool) (structs, flags, strflags map[string
 			if n.Ret != nil {
 				comp.collectUsedType(structs, flags, strflags, n.Ret, true)
 			}
+			for _, attr := range n.Attrs {
+				if callAttrs[attr.Ident] == attrErrnos && len(attr.Args) == 1 {
+					flags[attr.Args[0].Ident] = true
+				}
+			}
 		}
 	}
 	return
@@ -898,6 +916,72 @@ func (comp *compiler) checkTypeCtors(t *ast.Type, dir prog.Dir, isArg, canCreate
 	}
 }
 
+// checkChildProcessCalls checks that calls with proc_roles attribute don't create resources
+// in output arguments: memory written in the child process is not visible to the test process,
+// so such resources would never be usable. Calls with time_jumps attribute are executed
+// in a child process only with some of the time jumps, resources created by them
+// are replaced with the default value then.
+func (comp *compiler) checkChildProcessCalls() {
+	for _, decl := range comp.desc.Nodes {
+		n, ok := decl.(*ast.Call)
+		if !ok {
+			continue
+		}
+		for _, attr := range n.Attrs {
+			if attr.Ident != "proc_roles" {
+				continue
+			}
+			checked := make(map[structDir]bool)
+			for _, arg := range n.Args {
+				if res := comp.findOutResource(arg.Type, prog.DirIn, true, checked); res != "" {
+					comp.error(attr.Pos, "syscall %v with %v attribute can't create resource %v"+
+						" (it may be created in a child process)", n.Name.Name, attr.Ident, res)
+					break
+				}
+			}
+		}
+	}
+}
+
+func (comp *compiler) findOutResource(t *ast.Type, dir prog.Dir, isArg bool, checked map[structDir]bool) string {
+	desc, args, _ := comp.getArgsBase(t, isArg)
+	if desc == typeResource {
+		if dir != prog.DirIn {
+			return t.Ident
+		}
+		return ""
+	}
+	if desc == typeStruct {
+		s := comp.structs[t.Ident]
+		key := structDir{s.Name.Name, dir}
+		if checked[key] {
+			return ""
+		}
+		checked[key] = true
+		for _, fld := range s.Fields {
+			fldDir, fldHasDir := comp.genFieldDir(comp.parseIntAttrs(structFieldAttrs, fld, fld.Attrs))
+			if !fldHasDir {
+				fldDir = dir
+			}
+			if res := comp.findOutResource(fld.Type, fldDir, false, checked); res != "" {
+				return res
+			}
+		}
+		return ""
+	}
+	if desc == typePtr {
+		dir = genDir(t.Args[0])
+	}
+	for i, arg := range args {
+		if desc.Args[i].Type == typeArgType {
+			if res := comp.findOutResource(arg, dir, desc.Args[i].IsArg, checked); res != "" {
+				return res
+			}
+		}
+	}
+	return ""
+}
+
 func (comp *compiler) checkRecursion() {
 	checked := make(map[string]bool)
 	for _, decl := range comp.desc.Nodes {
@@ -928,6 +1012,50 @@ func (comp *compiler) checkResourceRecursion(n *ast.Resource) {
 	}
 }
 
+// checkRequiredCalls checks that calls don't (transitively) require themselves,
+// otherwise generation would need to insert an infinite chain of calls.
+func (comp *compiler) checkRequiredCalls() {
+	checked := make(map[string]bool)
+	for _, decl := range comp.desc.Nodes {
+		if n, ok := decl.(*ast.Call); ok {
+			comp.checkRequiredCallsRec(checked, n, nil)
+		}
+	}
+}
+
+func (comp *compiler) checkRequiredCallsRec(checked map[string]bool, n *ast.Call, path []string) bool {
+	name := n.Name.Name
+	if checked[name] {
+		return true
+	}
+	if arrayContains(path, name) {
+		comp.error(n.Pos, "recursive call requirement %v->%v", strings.Join(path, "->"), name)
+		return false
+	}
+	path = append(path, name)
+	for _, req := range callRequires(n) {
+		if next := comp.calls[req]; next != nil && !comp.checkRequiredCallsRec(checked, next, path) {
+			return false
+		}
+	}
+	checked[name] = true
+	return true
+}
+
+// callRequires returns names of calls listed in the requires attribute of the call.
+func callRequires(n *ast.Call) []string {
+	var names []string
+	for _, attr := range n.Attrs {
+		if callAttrs[attr.Ident] != attrRequires {
+			continue
+		}
+		for _, arg := range attr.Args {
+			names = append(names, arg.Ident)
+		}
+	}
+	return names
+}
+
 type pathElem struct {
 	Pos    ast.Pos
 	Struct string
@@ -1001,6 +1129,12 @@ func (comp *compiler) checkCall(n *ast.Call) {
 		comp.checkType(checkCtx{}, n.Ret, checkIsArg|checkIsRet)
 	}
 	comp.parseAttrs(callAttrs, n, n.Attrs)
+	for _, attr := range n.Attrs {
+		if (attr.Ident == "proc_roles" || attr.Ident == "time_jumps") && n.Ret != nil {
+			comp.error(attr.Pos, "syscall %v with %v attribute can't return a resource"+
+				" (it may be created in a child process)", n.Name.Name, attr.Ident)
+		}
+	}
 }
 
 type checkFlags int
@@ -1512,3 +1646,70 @@ func (comp *compiler) checkDupConstsCall(n *ast.Call, dups map[string]map[string
 	comp.error(n.Pos, "call %v: duplicate const %v, previously used in call %v at %v",
 		n.Name.Name, constArgID, dup.name, dup.pos)
 }
+
+// checkIoctlOverlaps detects ioctl descriptions that use the same fd resource and the same command value,
+// but different command names and argument types (e.g. a command is described twice under different names,
+// or two commands collide on some arch). Such descriptions skew generation towards the command
+// and make the fuzzer use wrong argument types for it. Variants of the same command name are
+// considered intentional. Descriptions that intentionally reuse a command value need to be marked
+// with the polymorphic attribute.
+// The check needs original command names, so it runs before consts are patched.
+func (comp *compiler) checkIoctlOverlaps(consts map[string]uint64) {
+	type ioctlDesc struct {
+		call *ast.Call
+		cmd  string
+		args string
+	}
+	ioctls := make(map[string][]ioctlDesc)
+	for _, decl := range comp.desc.Nodes {
+		n, ok := decl.(*ast.Call)
+		if !ok || n.CallName != "ioctl" || len(n.Args) < 2 {
+			continue
+		}
+		fd, cmd := n.Args[0].Type, n.Args[1].Type
+		if comp.getTypeDesc(fd) != typeResource || comp.getTypeDesc(cmd) != typeConst {
+			continue
+		}
+		cmdName := cmd.Args[0].Ident
+		val, ok := consts[cmdName]
+		if cmdName == "" || !ok {
+			// Literal commands are not interesting, missing consts make the call unsupported.
+			continue
+		}
+		var args []string
+		for _, arg := range n.Args[2:] {
+			args = append(args, ast.SerializeNode(arg.Type))
+		}
+		desc := ioctlDesc{n, cmdName, strings.Join(args, ", ")}
+		key := fmt.Sprintf("%v-0x%x", fd.Ident, val)
+		var conflict *ioctlDesc
+		for i, prev := range ioctls[key] {
+			if prev.cmd == desc.cmd || comp.isPolymorphic(prev.call) || comp.isPolymorphic(desc.call) {
+				continue
+			}
+			if prev.args == desc.args {
+				// Matches one of the variants of the other command.
+				conflict = nil
+				break
+			}
+			if conflict == nil {
+				conflict = &ioctls[key][i]
+			}
+		}
+		if conflict != nil {
+			comp.error(n.Pos, "%v overlaps with %v at %v: %v and %v have the same value 0x%x"+
+				" on fd %v, but arguments are different (mark the calls as polymorphic if this is intended)",
+				n.Name.Name, conflict.call.Name.Name, conflict.call.Pos, desc.cmd, conflict.cmd, val, fd.Ident)
+		}
+		ioctls[key] = append(ioctls[key], desc)
+	}
+}
+
+func (comp *compiler) isPolymorphic(n *ast.Call) bool {
+	for _, attr := range n.Attrs {
+		if attr.Ident == attrPolymorphic.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -39,6 +39,8 @@ type Prog struct {
 	Types     []prog.Type
 	// Set of unsupported syscalls/flags.
 	Unsupported map[string]bool
+	// Non-fatal problems found in the descriptions.
+	Warnings []Warning
 	// Returned if consts was nil.
 	fileConsts map[string]*ConstInfo
 }
@@ -59,6 +61,7 @@ func createCompiler(desc *ast.Description, target *targets.Target, eh ast.ErrorH
 		structs:        make(map[string]*ast.Struct),
 		intFlags:       make(map[string]*ast.IntFlags),
 		strFlags:       make(map[string]*ast.StrFlags),
+		calls:          make(map[string]*ast.Call),
 		used:           make(map[string]bool),
 		usedTypedefs:   make(map[string]bool),
 		brokenTypedefs: make(map[string]bool),
@@ -93,7 +96,9 @@ func Compile(desc *ast.Description, consts map[string]uint64, target *targets.Ta
 	if comp.target.SyscallNumbers {
 		comp.assignSyscallNumbers(consts)
 	}
+	comp.checkIoctlOverlaps(consts)
 	comp.patchConsts(consts)
+	comp.patchRequiredCalls()
 	comp.check(consts)
 	if comp.errors != 0 {
 		return nil
@@ -106,12 +111,13 @@ func Compile(desc *ast.Description, consts map[string]uint64, target *targets.Ta
 		Syscalls:    syscalls,
 		Types:       types,
 		Unsupported: comp.unsupported,
+		Warnings:    comp.warnings,
 	}
 	if comp.errors != 0 {
 		return nil
 	}
 	for _, w := range comp.warnings {
-		eh(w.pos, w.msg)
+		eh(w.Pos, w.Msg)
 	}
 	return prg
 }
@@ -121,7 +127,7 @@ type compiler struct {
 	target   *targets.Target
 	eh       ast.ErrorHandler
 	errors   int
-	warnings []warn
+	warnings []Warning
 	ptrSize  uint64
 
 	unsupported    map[string]bool
@@ -130,6 +136,7 @@ type compiler struct {
 	structs        map[string]*ast.Struct
 	intFlags       map[string]*ast.IntFlags
 	strFlags       map[string]*ast.StrFlags
+	calls          map[string]*ast.Call
 	used           map[string]bool // contains used structs/resources
 	usedTypedefs   map[string]bool
 	brokenTypedefs map[string]bool
@@ -140,18 +147,31 @@ type compiler struct {
 	fileMeta      map[string]Meta
 }
 
-type warn struct {
-	pos ast.Pos
-	msg string
+// Warning is a non-fatal problem in descriptions.
+type Warning struct {
+	Pos  ast.Pos
+	Kind WarningKind
+	Msg  string
 }
 
+type WarningKind string
+
+const (
+	// A syscall/resource/struct is disabled on the arch because a const it uses is not defined.
+	WarnMissingConst WarningKind = "missing-const"
+	// A len refers to an array with variable-size elements.
+	WarnSuspiciousLen WarningKind = "suspicious-len"
+	// A const in .const files is not referenced by any description.
+	WarnUnusedConst WarningKind = "unused-const"
+)
+
 func (comp *compiler) error(pos ast.Pos, msg string, args ...interface{}) {
 	comp.errors++
 	comp.eh(pos, fmt.Sprintf(msg, args...))
 }
 
-func (comp *compiler) warning(pos ast.Pos, msg string, args ...interface{}) {
-	comp.warnings = append(comp.warnings, warn{pos, fmt.Sprintf(msg, args...)})
+func (comp *compiler) warning(pos ast.Pos, kind WarningKind, msg string, args ...interface{}) {
+	comp.warnings = append(comp.warnings, Warning{pos, kind, fmt.Sprintf(msg, args...)})
 }
 
 func (comp *compiler) filterArch() {
@@ -172,6 +192,55 @@ func (comp *compiler) filterArch() {
 		}
 		return false
 	})
+	comp.filterPtrSizeFields()
+}
+
+// filterPtrSizeFields removes struct/union fields that have ptrsize attribute
+// that does not match pointer size of the target. This allows to describe
+// both native and compat layouts of a struct in a single description.
+func (comp *compiler) filterPtrSizeFields() {
+	filter := func(n *ast.Struct) {
+		if n == nil {
+			return
+		}
+		var fields []*ast.Field
+		for _, f := range n.Fields {
+			if comp.fieldMatchesPtrSize(f) {
+				fields = append(fields, f)
+			}
+		}
+		n.Fields = fields
+	}
+	for _, decl := range comp.desc.Nodes {
+		switch n := decl.(type) {
+		case *ast.Struct:
+			filter(n)
+		case *ast.TypeDef:
+			filter(n.Struct)
+		}
+	}
+}
+
+func (comp *compiler) fieldMatchesPtrSize(f *ast.Field) bool {
+	for _, attr := range f.Attrs {
+		if attr.Ident != attrPtrSize.Name {
+			continue
+		}
+		if len(attr.Args) != 1 {
+			// Malformed attributes are diagnosed during type checking.
+			return true
+		}
+		if _, _, ok := checkTypeKind(attr.Args[0], kindInt); !ok {
+			return true
+		}
+		sz := attr.Args[0].Value
+		if sz != 4 && sz != 8 {
+			comp.error(attr.Pos, "bad %v attribute value %v, expect 4 or 8", attr.Ident, sz)
+			return true
+		}
+		return sz == comp.ptrSize
+	}
+	return true
 }
 
 func (comp *compiler) structIsVarlen(name string) bool {
@@ -247,6 +316,12 @@ func (comp *compiler) parseAttrs(descs map[string]*attrDesc, parent ast.Node, at
 			resInt[desc] = comp.parseAttrIntArg(attr)
 		case exprAttr:
 			resExpr[desc] = comp.parseAttrExprArg(attr)
+		case flagsRefAttr:
+			resInt[desc] = 1
+			comp.checkAttrFlagsArg(attr)
+		case callRefAttr:
+			resInt[desc] = 1
+			comp.checkAttrCallArgs(attr, parentName)
 		default:
 			comp.error(attr.Pos, "attribute %v has unknown type", attr.Ident)
 			return nil, nil
@@ -268,6 +343,56 @@ func (comp *compiler) parseAttrExprArg(attr *ast.Type) prog.Expression {
 	return comp.genExpression(arg)
 }
 
+func (comp *compiler) checkAttrFlagsArg(attr *ast.Type) {
+	if len(attr.Args) != 1 {
+		comp.error(attr.Pos, "%v attribute is expected to have 1 argument", attr.Ident)
+		return
+	}
+	arg := attr.Args[0]
+	if unexpected, _, ok := checkTypeKind(arg, kindIdent); !ok {
+		comp.error(arg.Pos, "unexpected %v, expect flags name", unexpected)
+		return
+	}
+	if len(arg.Colon) != 0 || len(arg.Args) != 0 {
+		comp.error(arg.Pos, "%v attribute has colon or args", attr.Ident)
+		return
+	}
+	if comp.intFlags[arg.Ident] == nil {
+		comp.error(arg.Pos, "unknown flags %v in %v attribute", arg.Ident, attr.Ident)
+	}
+}
+
+func (comp *compiler) checkAttrCallArgs(attr *ast.Type, parentName string) {
+	if len(attr.Args) == 0 {
+		comp.error(attr.Pos, "%v attribute is expected to have arguments", attr.Ident)
+		return
+	}
+	for _, arg := range attr.Args {
+		if unexpected, _, ok := checkTypeKind(arg, kindIdent); !ok {
+			comp.error(arg.Pos, "unexpected %v, expect call name", unexpected)
+			continue
+		}
+		if len(arg.Colon) != 0 || len(arg.Args) != 0 {
+			comp.error(arg.Pos, "%v attribute has colon or args", attr.Ident)
+			continue
+		}
+		if arg.Ident == parentName {
+			comp.error(arg.Pos, "call %v requires itself", parentName)
+			continue
+		}
+		call := comp.calls[arg.Ident]
+		if call == nil {
+			comp.error(arg.Pos, "unknown call %v in %v attribute", arg.Ident, attr.Ident)
+			continue
+		}
+		for _, callAttr := range call.Attrs {
+			if callAttr.Ident == "disabled" || callAttr.Ident == "no_generate" {
+				comp.error(arg.Pos, "call %v requires %v call %v", parentName, callAttr.Ident, arg.Ident)
+			}
+		}
+	}
+}
+
 func (comp *compiler) parseAttrIntArg(attr *ast.Type) uint64 {
 	if len(attr.Args) != 1 {
 		comp.error(attr.Pos, "%v attribute is expected to have 1 argument", attr.Ident)
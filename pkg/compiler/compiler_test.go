@@ -90,12 +90,13 @@ func TestData(t *testing.T) {
 				}
 				cf := NewConstFile()
 				if err := cf.AddArch(arch, map[string]uint64{
-					"SYS_foo": 1,
-					"C0":      0,
-					"C1":      1,
-					"C2":      2,
-					"U8_MAX":  0xff,
-					"U16_MAX": 0xffff,
+					"SYS_foo":  1,
+					"C0":       0,
+					"C1":       1,
+					"C2":       2,
+					"C1_ALIAS": 1,
+					"U8_MAX":   0xff,
+					"U16_MAX":  0xffff,
 				}, nil); err != nil {
 					t.Fatal(err)
 				}
@@ -220,6 +221,87 @@ s2 {
 	}
 }
 
+func TestPtrSizeFields(t *testing.T) {
+	t.Parallel()
+	const input = `
+foo(a ptr[in, s0])
+s0 {
+	f0	int32
+	f1	int64	(ptrsize[8])
+	f1	int32	(ptrsize[4])
+	f2	int16	(ptrsize[8])
+}
+`
+	for _, arch := range []string{targets.TestArch32Shmem, targets.TestArch64} {
+		target := targets.List[targets.TestOS][arch]
+		eh := func(pos ast.Pos, msg string) {
+			t.Errorf("%v: %v", pos, msg)
+		}
+		desc := ast.Parse([]byte(input), "input", eh)
+		if desc == nil {
+			t.Fatal("failed to parse")
+		}
+		p := Compile(desc, map[string]uint64{"SYS_foo": 1}, target, eh)
+		if p == nil {
+			t.Fatal("failed to compile")
+		}
+		var s0 *prog.StructType
+		for _, typ := range p.Types {
+			if typ.Name() == "s0" {
+				s0 = typ.(*prog.StructType)
+			}
+		}
+		if s0 == nil {
+			t.Fatalf("%v: can't find s0", arch)
+		}
+		var fields []string
+		for _, f := range s0.Fields {
+			fields = append(fields, f.Name)
+		}
+		want, wantSize := []string{"f0", "f1"}, uint64(8)
+		if target.PtrSize == 8 {
+			want, wantSize = []string{"f0", "", "f1", "f2", ""}, 24
+		}
+		if !reflect.DeepEqual(fields, want) || s0.Size() != wantSize {
+			t.Errorf("%v: got fields %v (size %v), want %v (size %v)",
+				arch, fields, s0.Size(), want, wantSize)
+		}
+	}
+}
+
+func TestErrnos(t *testing.T) {
+	t.Parallel()
+	const input = `
+foo(a int32)
+foo$errnos(a int32) (errnos[foo_errnos])
+foo_errnos = 22, 2, 1
+`
+	target := targets.List[targets.TestOS][targets.TestArch64]
+	eh := func(pos ast.Pos, msg string) {
+		t.Errorf("%v: %v", pos, msg)
+	}
+	desc := ast.Parse([]byte(input), "input", eh)
+	if desc == nil {
+		t.Fatal("failed to parse")
+	}
+	p := Compile(desc, map[string]uint64{"SYS_foo": 1}, target, eh)
+	if p == nil {
+		t.Fatal("failed to compile")
+	}
+	errnos := make(map[string][]uint64)
+	for _, call := range p.Syscalls {
+		if call.Errnos != nil {
+			errnos[call.Name] = call.Errnos
+		}
+	}
+	want := map[string][]uint64{
+		"foo$errnos": {1, 2, 22},
+	}
+	if !reflect.DeepEqual(errnos, want) {
+		t.Errorf("got errnos %v, want %v", errnos, want)
+	}
+}
+
 func TestCollectUnusedError(t *testing.T) {
 	t.Parallel()
 	const input = `
@@ -385,3 +467,32 @@ recursive {
 		}
 	}
 }
+
+func TestWarningKinds(t *testing.T) {
+	t.Parallel()
+	const input = `
+foo$len(a len[b], b ptr[in, array[string]])
+bar(a const[NO_SUCH_CONST])
+`
+	desc := ast.Parse([]byte(input), "input", nil)
+	if desc == nil {
+		t.Fatal("failed to parse")
+	}
+	eh := func(pos ast.Pos, msg string) {}
+	consts := map[string]uint64{"SYS_foo": 1, "SYS_bar": 2}
+	p := Compile(desc, consts, targets.List[targets.TestOS][targets.TestArch64], eh)
+	if p == nil {
+		t.Fatal("failed to compile")
+	}
+	kinds := make(map[WarningKind]int)
+	for _, w := range p.Warnings {
+		if w.Pos.File != "input" {
+			t.Errorf("bad warning position: %v", w.Pos)
+		}
+		kinds[w.Kind]++
+	}
+	want := map[WarningKind]int{WarnSuspiciousLen: 1, WarnMissingConst: 1}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("got warnings %+v, want kinds %v", p.Warnings, want)
+	}
+}
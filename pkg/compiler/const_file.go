@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/google/syzkaller/pkg/ast"
+	"gopkg.in/yaml.v3"
 )
 
 // ConstFile serializes/deserializes .const files.
@@ -82,6 +83,16 @@ func (cf *ConstFile) Arch(arch string) map[string]uint64 {
 	return m
 }
 
+// Names returns sorted names of all consts in the file.
+func (cf *ConstFile) Names() []string {
+	var names []string
+	for name := range cf.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (cf *ConstFile) ExistsAny(constName string) bool {
 	return len(cf.m[constName].vals) > 0
 }
@@ -234,6 +245,82 @@ func (cf *ConstFile) deserializeFile(data []byte, file, arch string, eh ast.Erro
 	return true
 }
 
+// AddExternal adds consts from the files referenced by include_consts directives in desc.
+// Such files are generated by external tools (e.g. vendor SDKs or declextract) and allow to use
+// consts that syz-extract can't extract from kernel headers. Paths are relative to the description file.
+// JSON and YAML files are supported, they contain a map from const names to either a value
+// for all of the given arches, or a map from arch names to values, e.g.:
+//
+//	{"FOO_CMD": 1, "FOO_FLAG": "0x10", "FOO_IOCTL": {"amd64": 3221771017, "386": 3221508873}}
+func (cf *ConstFile) AddExternal(desc *ast.Description, arches []string, eh ast.ErrorHandler) bool {
+	if eh == nil {
+		eh = ast.LoggingHandler
+	}
+	ok := true
+	for _, node := range desc.Nodes {
+		n, isInclude := node.(*ast.IncludeConsts)
+		if !isInclude {
+			continue
+		}
+		file := filepath.Join(filepath.Dir(n.Pos.File), n.File.Value)
+		if err := cf.addExternalFile(file, arches); err != nil {
+			eh(n.Pos, fmt.Sprintf("failed to load consts from %v: %v", n.File.Value, err))
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (cf *ConstFile) addExternalFile(file string, arches []string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	// JSON is a subset of YAML, so both are parsed as YAML.
+	var consts map[string]interface{}
+	if err := yaml.Unmarshal(data, &consts); err != nil {
+		return err
+	}
+	for name, v := range consts {
+		vals := make(map[string]interface{})
+		switch perArch := v.(type) {
+		case map[string]interface{}:
+			vals = perArch
+		case map[interface{}]interface{}:
+			// YAML keys like 386 are parsed as ints.
+			for arch, v := range perArch {
+				vals[fmt.Sprint(arch)] = v
+			}
+		default:
+			for _, arch := range arches {
+				vals[arch] = v
+			}
+		}
+		for arch, v := range vals {
+			val, err := externalConstValue(v)
+			if err != nil {
+				return fmt.Errorf("const %v: %w", name, err)
+			}
+			if err := cf.addConst(arch, name, val, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func externalConstValue(v interface{}) (uint64, error) {
+	switch val := v.(type) {
+	case int:
+		return uint64(val), nil
+	case uint64:
+		return val, nil
+	case string:
+		return strconv.ParseUint(val, 0, 64)
+	}
+	return 0, fmt.Errorf("bad value %v", v)
+}
+
 type errft func(msg string, args ...interface{}) bool
 
 func (cf *ConstFile) parseConst(arches []string, name, line string, errf errft) bool {
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/syzkaller/pkg/ast"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -119,3 +120,33 @@ CONST5_SOME_UNDEFINED2 = 100, arch3:???
 		}
 	}
 }
+
+func TestConstFileExternal(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"consts.json": `{"FOO": 1, "BAR": "0x10", "BAZ": {"arch1": 2, "arch2": 3}}`,
+		"consts.yaml": "QUX: 0x20\nQUUX:\n  arch1: 4\n",
+		"bad.json":    `{"FOO": 2}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	desc := ast.Parse([]byte("include_consts <consts.json>\ninclude_consts <consts.yaml>\n"),
+		filepath.Join(dir, "test.txt"), nil)
+	cf := NewConstFile()
+	assert.NoError(t, cf.AddArch("arch1", map[string]uint64{"FOO": 1}, nil))
+	assert.True(t, cf.AddExternal(desc, []string{"arch1", "arch2"}, nil))
+	assert.Equal(t, map[string]uint64{"FOO": 1, "BAR": 16, "BAZ": 2, "QUX": 32, "QUUX": 4}, cf.Arch("arch1"))
+	assert.Equal(t, map[string]uint64{"FOO": 1, "BAR": 16, "BAZ": 3, "QUX": 32}, cf.Arch("arch2"))
+
+	// The value conflicts with the value from the .const file.
+	desc = ast.Parse([]byte("include_consts <bad.json>\n"), filepath.Join(dir, "test.txt"), nil)
+	var errors []string
+	assert.False(t, cf.AddExternal(desc, []string{"arch1"}, func(pos ast.Pos, msg string) {
+		errors = append(errors, msg)
+	}))
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "different values")
+}
@@ -229,7 +229,7 @@ func (comp *compiler) assignSyscallNumbers(consts map[string]uint64) {
 		name := "syscall " + c.CallName
 		if !comp.unsupported[name] {
 			comp.unsupported[name] = true
-			comp.warning(c.Pos, "unsupported syscall: %v due to missing const %v",
+			comp.warning(c.Pos, WarnMissingConst, "unsupported syscall: %v due to missing const %v",
 				c.CallName, str)
 		}
 	}
@@ -306,7 +306,7 @@ func (comp *compiler) patchConsts(consts0 map[string]uint64) {
 			pos, typ, name := decl.Info()
 			if id := typ + " " + name; !comp.unsupported[id] {
 				comp.unsupported[id] = true
-				comp.warning(pos, "unsupported %v: %v due to missing const %v",
+				comp.warning(pos, WarnMissingConst, "unsupported %v: %v due to missing const %v",
 					typ, name, missing)
 			}
 			if c, ok := decl.(*ast.Call); ok {
@@ -316,6 +316,32 @@ func (comp *compiler) patchConsts(consts0 map[string]uint64) {
 	}
 }
 
+// patchRequiredCalls discards syscalls that require unsupported syscalls.
+func (comp *compiler) patchRequiredCalls() {
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range comp.desc.Nodes {
+			c, ok := decl.(*ast.Call)
+			if !ok || c.NR == ^uint64(0) {
+				continue
+			}
+			for _, req := range callRequires(c) {
+				if reqCall := comp.calls[req]; reqCall == nil || reqCall.NR != ^uint64(0) {
+					continue
+				}
+				c.NR = ^uint64(0) // mark as unused to not generate it
+				changed = true
+				if id := "syscall " + c.Name.Name; !comp.unsupported[id] {
+					comp.unsupported[id] = true
+					comp.warning(c.Pos, WarnMissingConst, "unsupported syscall: %v due to unsupported required call %v",
+						c.Name.Name, req)
+				}
+				break
+			}
+		}
+	}
+}
+
 func (comp *compiler) patchIntConst(n *ast.Int, consts map[string]uint64, missing *string) bool {
 	return comp.patchConst(&n.Value, &n.Ident, consts, missing, false)
 }
@@ -123,9 +123,17 @@ func (comp *compiler) genSyscall(n *ast.Call, argSizes []uint64) *prog.Syscall {
 		ret = comp.genType(n.Ret, comp.ptrSize)
 	}
 	var attrs prog.SyscallAttrs
+	var errnos []uint64
 	descAttrs := comp.parseIntAttrs(callAttrs, n, n.Attrs)
 	for desc, val := range descAttrs {
+		if desc == attrErrnos {
+			errnos = comp.genErrnos(n)
+			continue
+		}
 		fld := reflect.ValueOf(&attrs).Elem().FieldByName(desc.Name)
+		if !fld.IsValid() {
+			continue // compile-time only attribute
+		}
 		switch desc.Type {
 		case intAttr:
 			fld.SetUint(val)
@@ -144,7 +152,21 @@ func (comp *compiler) genSyscall(n *ast.Call, argSizes []uint64) *prog.Syscall {
 		Args:        fields,
 		Ret:         ret,
 		Attrs:       attrs,
+		Errnos:      errnos,
+		Requires:    callRequires(n),
+	}
+}
+
+func (comp *compiler) genErrnos(n *ast.Call) []uint64 {
+	for _, attr := range n.Attrs {
+		if callAttrs[attr.Ident] != attrErrnos {
+			continue
+		}
+		errnos := genIntArray(comp.intFlags[attr.Args[0].Ident].Values)
+		sort.Slice(errnos, func(i, j int) bool { return errnos[i] < errnos[j] })
+		return errnos
 	}
+	return nil
 }
 
 type typeProxy struct {
@@ -0,0 +1,104 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package compiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// SyscallNumberProvider parses a syscall table file and returns syscall numbers (syscall name -> number).
+type SyscallNumberProvider func(target *targets.Target, data []byte) (map[string]uint64, error)
+
+// SyscallNumberProviders maps extensions of syscall table files (see targets.Target.SyscallTable)
+// to their parsers. Syscall numbers of a target can be provided by such table checked into sys/OS/
+// instead of being extracted from kernel headers, which is impractical for some OSes/arches.
+var SyscallNumberProviders = map[string]SyscallNumberProvider{
+	".tbl":  parseSyscallTbl,
+	".json": parseSyscallJSON,
+}
+
+// AddSyscallTable adds syscall number consts from the target syscall table (if any) located in dir.
+func (cf *ConstFile) AddSyscallTable(target *targets.Target, dir string) error {
+	if target.SyscallTable == "" {
+		return nil
+	}
+	provider := SyscallNumberProviders[filepath.Ext(target.SyscallTable)]
+	if provider == nil {
+		return fmt.Errorf("%v/%v: unsupported syscall table format %v",
+			target.OS, target.Arch, target.SyscallTable)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, target.SyscallTable))
+	if err != nil {
+		return err
+	}
+	numbers, err := provider(target, data)
+	if err != nil {
+		return fmt.Errorf("%v: %w", target.SyscallTable, err)
+	}
+	cf.arches[target.Arch] = true
+	for name, nr := range numbers {
+		if err := cf.addConst(target.Arch, target.SyscallPrefix+name, nr, true); err != nil {
+			return fmt.Errorf("%v: %w", target.SyscallTable, err)
+		}
+	}
+	return nil
+}
+
+// parseSyscallTbl parses tables in the format of Linux arch/*/entry/syscalls/syscall*.tbl files:
+// "<number> <abi> <name> [<entry point> ...]" lines, comments start with '#'.
+// Only entries with the "common" ABI, ABI equal to the target arch, or "32"/"64" ABI
+// matching the target pointer size are used.
+func parseSyscallTbl(target *targets.Target, data []byte) (map[string]uint64, error) {
+	abis := map[string]bool{
+		"common":                       true,
+		target.Arch:                    true,
+		fmt.Sprint(target.PtrSize * 8): true,
+	}
+	numbers := make(map[string]uint64)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if comment := strings.IndexByte(text, '#'); comment != -1 {
+			text = text[:comment]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %v: expect at least 3 fields", line)
+		}
+		nr, err := strconv.ParseUint(fields[0], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: bad syscall number %q", line, fields[0])
+		}
+		if !abis[fields[1]] {
+			continue
+		}
+		if nr0, ok := numbers[fields[2]]; ok && nr0 != nr {
+			return nil, fmt.Errorf("line %v: syscall %v has different numbers: %v vs %v",
+				line, fields[2], nr, nr0)
+		}
+		numbers[fields[2]] = nr
+	}
+	return numbers, s.Err()
+}
+
+// parseSyscallJSON parses tables in the {"syscall name": number, ...} format.
+func parseSyscallJSON(target *targets.Target, data []byte) (map[string]uint64, error) {
+	var numbers map[string]uint64
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return nil, err
+	}
+	return numbers, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package compiler
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyscallTable(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "syscall_64.tbl"), []byte(`
+# 64-bit system call numbers and entry vectors
+0	common	read		sys_read
+1	common	write	sys_write
+2	64	open		sys_open  # comment
+3	x32	close		compat_sys_close
+4	amd64	stat		sys_stat
+`)))
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "syscalls.json"),
+		[]byte(`{"read": 63, "write": 64}`)))
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "bad.tbl"), []byte("0 common\n")))
+
+	target := *targets.Get(targets.Linux, targets.AMD64)
+	target.SyscallTable = "syscall_64.tbl"
+	cf := NewConstFile()
+	assert.NoError(t, cf.AddSyscallTable(&target, dir))
+	assert.Equal(t, map[string]uint64{
+		"__NR_read":  0,
+		"__NR_write": 1,
+		"__NR_open":  2,
+		"__NR_stat":  4,
+	}, cf.Arch(targets.AMD64))
+
+	target.SyscallTable = "syscalls.json"
+	cf = NewConstFile()
+	assert.NoError(t, cf.AddSyscallTable(&target, dir))
+	assert.Equal(t, map[string]uint64{
+		"__NR_read":  63,
+		"__NR_write": 64,
+	}, cf.Arch(targets.AMD64))
+	// Numbers must not contradict the existing consts.
+	target.SyscallTable = "syscall_64.tbl"
+	assert.Error(t, cf.AddSyscallTable(&target, dir))
+
+	target.SyscallTable = "bad.tbl"
+	assert.Error(t, NewConstFile().AddSyscallTable(&target, dir))
+	target.SyscallTable = "syscalls.txt"
+	assert.Error(t, NewConstFile().AddSyscallTable(&target, dir))
+	target.SyscallTable = ""
+	assert.NoError(t, NewConstFile().AddSyscallTable(&target, dir))
+}
@@ -642,7 +642,7 @@ var typeText = &typeDesc{
 
 var typeArgTextType = &typeArg{
 	Kind:  kindIdent,
-	Names: []string{"target", "x86_real", "x86_16", "x86_32", "x86_64", "arm64", "ppc64"},
+	Names: []string{"target", "x86_real", "x86_16", "x86_32", "x86_64", "arm64", "ppc64", "riscv64"},
 }
 
 func genTextType(t *ast.Type) prog.TextKind {
@@ -661,6 +661,8 @@ func genTextType(t *ast.Type) prog.TextKind {
 		return prog.TextArm64
 	case "ppc64":
 		return prog.TextPpc64
+	case "riscv64":
+		return prog.TextRiscv64
 	default:
 		panic(fmt.Sprintf("unknown text type %q", t.Ident))
 	}
@@ -25,12 +25,15 @@ func LoadFile(filename string, cfg interface{}) error {
 }
 
 func LoadData(data []byte, cfg interface{}) error {
-	// Remove comment lines starting with #.
-	data = regexp.MustCompile(`(^|\n)\s*#[^\n]*`).ReplaceAll(data, nil)
+	// Remove comment lines starting with #, but keep the line breaks so that
+	// error positions point to the original lines.
+	data = regexp.MustCompile(`(^|\n)\s*#[^\n]*`).ReplaceAllFunc(data, func(comment []byte) []byte {
+		return bytes.Repeat([]byte{'\n'}, bytes.Count(comment, []byte{'\n'}))
+	})
 	dec := json.NewDecoder(bytes.NewReader(data))
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(cfg); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
+		return fmt.Errorf("failed to parse config file: %w", explainError(data, cfg, err))
 	}
 	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Name   string   `json:"name"`
+	Procs  int      `json:"procs"`
+	Cover  bool     `json:"cover"`
+	Calls  []string `json:"enable_syscalls,omitempty"`
+	Nested struct {
+		Count int `json:"count"`
+	} `json:"nested"`
+	Internal int `json:"-"`
+}
+
+func TestLoadDataErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{
+			data: `{"name": "foo", "procs": 2}`,
+		},
+		{
+			data: `{
+	# comment
+	"name": "foo",
+	"procss": 2
+}`,
+			err: `failed to parse config file: line 4: unknown param "procss" (did you mean "procs"?)`,
+		},
+		{
+			data: `{
+	"nested": {"cuont": 1}
+}`,
+			err: `failed to parse config file: line 2: unknown param "cuont" (did you mean "count"?)`,
+		},
+		{
+			data: `{"nmae": "foo"}`,
+			err:  `failed to parse config file: line 1: unknown param "nmae" (did you mean "name"?)`,
+		},
+		{
+			data: `{"completely_unrelated": 1}`,
+			err:  `failed to parse config file: line 1: unknown param "completely_unrelated"`,
+		},
+		{
+			data: `{"Internal": 1}`,
+			err:  `failed to parse config file: line 1: unknown param "Internal"`,
+		},
+		{
+			data: `{
+	"name": "foo",
+	"procs": "8"
+}`,
+			err: `failed to parse config file: line 3: param procs must be an integer, not string`,
+		},
+		{
+			data: `{
+	"cover": true,
+	"enable_syscalls": "open"
+}`,
+			err: `failed to parse config file: line 3: param enable_syscalls must be a list, not string`,
+		},
+		{
+			data: `{
+	"name": "foo"
+	"procs": 1
+}`,
+			err: `failed to parse config file: line 3: invalid character '"' after object key:value pair`,
+		},
+	}
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			cfg := new(testConfig)
+			err := config.LoadData([]byte(test.data), cfg)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// explainError augments json decoding errors with the position in the config
+// and suggestions for misspelled params.
+func explainError(data []byte, cfg interface{}, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%v: %w", position(data, syntaxErr.Offset), err)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%v: param %v must be %v, not %v",
+			position(data, typeErr.Offset), typeErr.Field, typeDesc(typeErr.Type), typeErr.Value)
+	}
+	match := regexp.MustCompile(`^json: unknown field "(.*)"$`).FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	name := match[1]
+	msg := fmt.Sprintf("unknown param %q", name)
+	if loc := regexp.MustCompile(`"` + regexp.QuoteMeta(name) + `"\s*:`).FindIndex(data); loc != nil {
+		msg = fmt.Sprintf("%v: %v", position(data, int64(loc[0])), msg)
+	}
+	if suggestion := closestName(name, fieldNames(reflect.TypeOf(cfg))); suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return errors.New(msg)
+}
+
+func position(data []byte, offset int64) string {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return fmt.Sprintf("line %v", bytes.Count(data[:offset], []byte{'\n'})+1)
+}
+
+func typeDesc(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return typ.String()
+}
+
+// fieldNames returns json names of all fields of the (possibly nested) struct type.
+func fieldNames(typ reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	var rec func(typ reflect.Type)
+	rec = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || visited[typ] {
+			return
+		}
+		visited[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				if field.Anonymous {
+					rec(field.Type)
+					continue
+				}
+				name = field.Name
+			}
+			names[name] = true
+			rec(field.Type)
+		}
+	}
+	rec(typ)
+	return names
+}
+
+// closestName returns the name most similar to the misspelled one, or "" if there are no similar names.
+func closestName(name string, names map[string]bool) string {
+	best, bestDist := "", max(len(name)/3, 2)+1
+	for candidate := range names {
+		dist := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if dist < bestDist || dist == bestDist && best != "" && candidate < best {
+			best, bestDist = candidate, dist
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -23,6 +23,7 @@ type Corpus struct {
 	signal  signal.Signal // total signal of all items
 	cover   cover.Cover   // total coverage of all items
 	updates chan<- NewItemEvent
+	energy  *energySchedule // nil unless EnableEnergySchedule was called
 	*ProgramsList
 	StatProgs  *stats.Val
 	StatSignal *stats.Val
@@ -53,6 +54,7 @@ func NewMonitoredCorpus(ctx context.Context, updates chan<- NewItemEvent) *Corpu
 // sysalls. In that case, there will be several ItemUpdate entities.
 type ItemUpdate struct {
 	Call     int
+	Cover    []uint64 // coverage the update added to the item
 	RawCover []uint64
 }
 
@@ -74,6 +76,18 @@ func (item Item) StringCall() string {
 	return item.Prog.CallName(item.Call)
 }
 
+// CallCover returns coverage the call (-1 means extra coverage) contributed to the item.
+// Note: it does not include coverage of updates beyond the max number of updates stored per item.
+func (item Item) CallCover(call int) []uint64 {
+	var res cover.Cover
+	for _, update := range item.Updates {
+		if update.Call == call {
+			res.Merge(update.Cover)
+		}
+	}
+	return res.Serialize()
+}
+
 type NewInput struct {
 	Prog     *prog.Prog
 	Call     int
@@ -98,6 +112,7 @@ func (corpus *Corpus) Save(inp NewInput) {
 
 	update := ItemUpdate{
 		Call:     inp.Call,
+		Cover:    inp.Cover,
 		RawCover: inp.RawCover,
 	}
 	exists := false
@@ -107,7 +122,8 @@ func (corpus *Corpus) Save(inp NewInput) {
 		newSignal.Merge(inp.Signal)
 		var newCover cover.Cover
 		newCover.Merge(old.Cover)
-		newCover.Merge(inp.Cover)
+		// Only the diff is stored in the update, the rest is already referenced by other updates.
+		update.Cover = newCover.MergeDiff(append([]uint64{}, inp.Cover...))
 		newItem := &Item{
 			Sig:      sig,
 			Prog:     old.Prog,
@@ -123,6 +139,9 @@ func (corpus *Corpus) Save(inp NewInput) {
 			newItem.Updates = append(newItem.Updates, update)
 		}
 		corpus.progs[sig] = newItem
+		if corpus.energy != nil {
+			corpus.energy.noteSignal(old.Prog)
+		}
 	} else {
 		corpus.progs[sig] = &Item{
 			Sig:      sig,
@@ -135,9 +154,13 @@ func (corpus *Corpus) Save(inp NewInput) {
 			Updates:  []ItemUpdate{update},
 		}
 		corpus.saveProgram(inp.Prog, inp.Signal)
+		if corpus.energy != nil {
+			corpus.energy.noteSignal(inp.Prog)
+		}
 	}
 	corpus.signal.Merge(inp.Signal)
-	newCover := corpus.cover.MergeDiff(inp.Cover)
+	// MergeDiff overwrites its argument, but inp.Cover is referenced by the item.
+	newCover := corpus.cover.MergeDiff(append([]uint64{}, inp.Cover...))
 	if corpus.updates != nil {
 		select {
 		case <-corpus.ctx.Done():
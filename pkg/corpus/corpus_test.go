@@ -62,6 +62,7 @@ func TestCorpusCoverage(t *testing.T) {
 	rs := rand.NewSource(0)
 
 	inp := generateInput(target, rs, 5, 5)
+	inp.Call = 0
 	inp.Cover = []uint64{10, 11}
 	go corpus.Save(inp)
 	event := <-ch
@@ -75,6 +76,16 @@ func TestCorpusCoverage(t *testing.T) {
 
 	// Check the total corpus size.
 	assert.Equal(t, corpus.StatCover.Val(), 3)
+
+	// Coverage contributed by each call is also tracked.
+	inp.Call = 0
+	inp.Cover = []uint64{13}
+	go corpus.Save(inp)
+	<-ch
+	item := corpus.Item(event.Sig)
+	assert.ElementsMatch(t, []uint64{10, 11, 13}, item.CallCover(0))
+	assert.ElementsMatch(t, []uint64{12}, item.CallCover(1))
+	assert.Empty(t, item.CallCover(2))
 }
 
 func TestCorpusSaveConcurrency(t *testing.T) {
@@ -0,0 +1,228 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/prog"
+)
+
+// EnergyInput contains the per-program properties the energy score is computed from.
+type EnergyInput struct {
+	// Signal size of the program.
+	Signal int
+	// Sum of 1/N over the program signal, where N is the number of corpus programs with the same signal.
+	// Programs that are the only source of some signal have high rareness.
+	Rareness float64
+	// Time since the program last contributed new signal to the corpus.
+	Age time.Duration
+	// Number of times the program was chosen for mutation.
+	Chosen int
+	// Number of VM crashes caused by mutants of the program.
+	Crashes int
+}
+
+// EnergyFunc returns the (non-negative) energy of a corpus program.
+type EnergyFunc func(in *EnergyInput) float64
+
+// DefaultEnergy prefers programs with rare signal, programs that recently gave new signal,
+// and programs whose mutants crash the kernel, and penalizes programs that were mutated a lot already.
+func DefaultEnergy(in *EnergyInput) float64 {
+	energy := 1 + 10*in.Rareness + math.Log1p(float64(in.Signal))
+	// Halve the energy for every hour without new signal, but don't go below 1/8.
+	energy *= math.Max(math.Pow(0.5, in.Age.Hours()), 1.0/8)
+	energy *= 1 + float64(in.Crashes)
+	return energy / (1 + math.Log1p(float64(in.Chosen)/100))
+}
+
+type EnergyConfig struct {
+	// Energy function, DefaultEnergy if nil.
+	Func EnergyFunc
+	// Temperature controls exploration vs exploitation: programs are sampled proportionally
+	// to energy^(1/Temperature). High temperatures flatten the distribution towards uniform selection,
+	// low temperatures concentrate mutations on the highest-energy programs. 1 if not set.
+	Temperature float64
+	// Energies are recomputed in the background after that many choices (1000 if not set).
+	RecomputePeriod int
+}
+
+// EnableEnergySchedule switches ChooseProgram from signal-proportional selection
+// to sampling proportionally to the program energy.
+// Must be called before the corpus is used.
+func (corpus *Corpus) EnableEnergySchedule(cfg EnergyConfig) {
+	if cfg.Func == nil {
+		cfg.Func = DefaultEnergy
+	}
+	if cfg.Temperature <= 0 {
+		cfg.Temperature = 1
+	}
+	if cfg.RecomputePeriod <= 0 {
+		cfg.RecomputePeriod = 1000
+	}
+	corpus.energy = &energySchedule{
+		cfg:   cfg,
+		stats: make(map[*prog.Prog]*energyStats),
+		now:   time.Now,
+		kick:  make(chan struct{}, 1),
+	}
+	go corpus.energy.loop(corpus)
+}
+
+// ChooseProgram returns a corpus program for mutation.
+func (corpus *Corpus) ChooseProgram(r *rand.Rand) *prog.Prog {
+	if corpus.energy == nil {
+		return corpus.ProgramsList.ChooseProgram(r)
+	}
+	return corpus.energy.choose(corpus, r)
+}
+
+// NoteCrash notes that a mutant of the corpus program p crashed the VM.
+func (corpus *Corpus) NoteCrash(p *prog.Prog) {
+	if corpus.energy != nil {
+		corpus.energy.noteCrash(p)
+	}
+}
+
+type energySchedule struct {
+	cfg   EnergyConfig
+	now   func() time.Time
+	mu    sync.Mutex
+	kick  chan struct{}
+	stats map[*prog.Prog]*energyStats
+	// Cached sampling state.
+	progs   []*prog.Prog
+	acc     []float64
+	sum     float64
+	choices int
+	dirty   bool
+}
+
+type energyStats struct {
+	lastSignal time.Time
+	chosen     int
+	crashes    int
+}
+
+func (es *energySchedule) getStats(p *prog.Prog) *energyStats {
+	st := es.stats[p]
+	if st == nil {
+		st = &energyStats{lastSignal: es.now()}
+		es.stats[p] = st
+	}
+	return st
+}
+
+// noteSignal is called when the program adds new signal to the corpus.
+func (es *energySchedule) noteSignal(p *prog.Prog) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.getStats(p).lastSignal = es.now()
+	es.dirty = true
+}
+
+func (es *energySchedule) noteCrash(p *prog.Prog) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if st := es.stats[p]; st != nil {
+		st.crashes++
+	}
+}
+
+// loop recomputes the energies in the background, so that ChooseProgram does not scan
+// the whole corpus signal on the mutation hot path.
+func (es *energySchedule) loop(corpus *Corpus) {
+	for {
+		select {
+		case <-corpus.ctx.Done():
+			return
+		case <-es.kick:
+			es.recompute(corpus.Items())
+		}
+	}
+}
+
+func (es *energySchedule) choose(corpus *Corpus, r *rand.Rand) *prog.Prog {
+	es.mu.Lock()
+	// Until there is anything to choose from, recompute synchronously (the corpus is tiny then).
+	initial := es.dirty && len(es.progs) == 0
+	// New programs become eligible sooner than the normal recompute period.
+	if !initial && (es.choices >= es.cfg.RecomputePeriod || es.dirty && es.choices >= 100) {
+		select {
+		case es.kick <- struct{}{}:
+		default:
+		}
+	}
+	es.mu.Unlock()
+	if initial {
+		// Note: corpus.mu must not be taken while holding es.mu, since Save does it the other way around.
+		es.recompute(corpus.Items())
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.choices++
+	if len(es.progs) == 0 {
+		return nil
+	}
+	idx := len(es.progs) - 1
+	if es.sum > 0 {
+		idx = min(sort.SearchFloat64s(es.acc, r.Float64()*es.sum), idx)
+	} else {
+		idx = r.Intn(len(es.progs))
+	}
+	p := es.progs[idx]
+	es.getStats(p).chosen++
+	return p
+}
+
+func (es *energySchedule) recompute(items []*Item) {
+	counts := make(map[uint64]int)
+	for _, item := range items {
+		for _, elem := range item.Signal.ToRaw() {
+			counts[elem]++
+		}
+	}
+	// Sort for deterministic selection given the same random source.
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Sig < items[j].Sig
+	})
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	now := es.now()
+	stats := make(map[*prog.Prog]*energyStats, len(items))
+	es.progs = es.progs[:0]
+	es.acc = es.acc[:0]
+	es.sum = 0
+	for _, item := range items {
+		st := es.getStats(item.Prog)
+		stats[item.Prog] = st
+		in := &EnergyInput{
+			Signal:  len(item.Signal),
+			Age:     now.Sub(st.lastSignal),
+			Chosen:  st.chosen,
+			Crashes: st.crashes,
+		}
+		for _, elem := range item.Signal.ToRaw() {
+			in.Rareness += 1 / float64(counts[elem])
+		}
+		energy := es.cfg.Func(in)
+		if es.cfg.Temperature != 1 {
+			energy = math.Pow(energy, 1/es.cfg.Temperature)
+		}
+		if energy <= 0 || math.IsNaN(energy) || math.IsInf(energy, 0) {
+			energy = 0
+		}
+		es.sum += energy
+		es.progs = append(es.progs, item.Prog)
+		es.acc = append(es.acc, es.sum)
+	}
+	// Drop stats of programs removed by corpus minimization.
+	es.stats = stats
+	es.choices = 0
+	es.dirty = false
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnergySchedule(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	rs := rand.NewSource(0)
+	r := rand.New(rs)
+	corpus := NewCorpus(context.Background())
+	var inputs []*EnergyInput
+	corpus.EnableEnergySchedule(EnergyConfig{
+		Func: func(in *EnergyInput) float64 {
+			inputs = append(inputs, in)
+			return in.Rareness * float64(1+in.Crashes)
+		},
+		// Energies are recomputed explicitly below.
+		RecomputePeriod: 1 << 30,
+	})
+	assert.Nil(t, corpus.ChooseProgram(r))
+
+	// The first program has only shared signal (rareness 1.5), the second one has one unique element (2.5).
+	inp1 := generateInput(target, rs, 5, 0)
+	inp1.Signal = signal.FromRaw([]uint64{1, 2, 3}, 0)
+	inp2 := generateInput(target, rs, 5, 0)
+	inp2.Signal = signal.FromRaw([]uint64{1, 2, 3, 4}, 0)
+	corpus.Save(inp1)
+	corpus.Save(inp2)
+
+	const iters = 10000
+	choose := func() map[*prog.Prog]int {
+		counts := make(map[*prog.Prog]int)
+		for i := 0; i < iters; i++ {
+			counts[corpus.ChooseProgram(r)]++
+		}
+		return counts
+	}
+	counts := choose()
+	assert.InDelta(t, iters*1.5/4, counts[inp1.Prog], iters*0.03)
+	assert.InDelta(t, iters*2.5/4, counts[inp2.Prog], iters*0.03)
+
+	// Crashes of mutants increase the energy.
+	inputs = nil
+	corpus.NoteCrash(inp1.Prog)
+	corpus.NoteCrash(inp1.Prog)
+	corpus.energy.recompute(corpus.Items())
+	counts = choose()
+	assert.InDelta(t, iters*4.5/7, counts[inp1.Prog], iters*0.03)
+	for _, in := range inputs[len(inputs)-2:] {
+		if in.Signal == 3 {
+			assert.Equal(t, 2, in.Crashes)
+		} else {
+			assert.Equal(t, 0, in.Crashes)
+		}
+		assert.Greater(t, in.Chosen, iters/4)
+	}
+}
+
+func TestEnergyTemperature(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	rs := rand.NewSource(0)
+	r := rand.New(rs)
+	corpus := NewCorpus(context.Background())
+	corpus.EnableEnergySchedule(EnergyConfig{
+		Func: func(in *EnergyInput) float64 {
+			return float64(in.Signal)
+		},
+		Temperature:     1000,
+		RecomputePeriod: 1,
+	})
+	inp1 := generateInput(target, rs, 5, 1)
+	inp2 := generateInput(target, rs, 5, 1000)
+	corpus.Save(inp1)
+	corpus.Save(inp2)
+	counts := make(map[*prog.Prog]int)
+	for i := 0; i < 1000; i++ {
+		counts[corpus.ChooseProgram(r)]++
+	}
+	// High temperature makes the selection almost uniform.
+	assert.InDelta(t, 500, counts[inp1.Prog], 60)
+}
+
+func TestDefaultEnergy(t *testing.T) {
+	base := &EnergyInput{Signal: 10, Rareness: 1}
+	energy := DefaultEnergy(base)
+	assert.Greater(t, energy, 0.0)
+	assert.Greater(t, DefaultEnergy(&EnergyInput{Signal: 10, Rareness: 5}), energy)
+	assert.Greater(t, DefaultEnergy(&EnergyInput{Signal: 10, Rareness: 1, Crashes: 1}), energy)
+	assert.Less(t, DefaultEnergy(&EnergyInput{Signal: 10, Rareness: 1, Age: 3 * time.Hour}), energy)
+	assert.Less(t, DefaultEnergy(&EnergyInput{Signal: 10, Rareness: 1, Chosen: 1000}), energy)
+	// Old programs still keep some energy.
+	assert.Greater(t, DefaultEnergy(&EnergyInput{Signal: 10, Rareness: 1, Age: 1000 * time.Hour}), energy/10)
+}
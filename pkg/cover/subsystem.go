@@ -0,0 +1,158 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/subsystem"
+	_ "github.com/google/syzkaller/pkg/subsystem/lists"
+)
+
+// SubsystemCover is coverage of a kernel subsystem. Subsystems are matched to source files
+// with the same path rules that are used to assign subsystems to bugs (see pkg/subsystem).
+// A file may belong to several subsystems (e.g. to a subsystem and its parent).
+type SubsystemCover struct {
+	Name         string
+	CoveredPCs   int
+	TotalPCs     int
+	CoveredFuncs int
+	TotalFuncs   int
+	Files        int
+	Percent      float64
+}
+
+// DoSubsystemRanking shows subsystems ranked by the percent of covered PCs.
+func (rg *ReportGenerator) DoSubsystemRanking(w io.Writer, params CoverHandlerParams) error {
+	ranking, err := rg.subsystemRanking(params)
+	if err != nil {
+		return err
+	}
+	return subsystemRankingTemplate.Execute(w, ranking)
+}
+
+// DoSubsystemRankingJSON is the same as DoSubsystemRanking, but outputs a JSON array.
+func (rg *ReportGenerator) DoSubsystemRankingJSON(w io.Writer, params CoverHandlerParams) error {
+	ranking, err := rg.subsystemRanking(params)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(ranking)
+}
+
+func (rg *ReportGenerator) subsystemRanking(params CoverHandlerParams) ([]*SubsystemCover, error) {
+	list := subsystem.GetList(rg.target.OS)
+	if list == nil {
+		return nil, fmt.Errorf("no subsystem list for %v", rg.target.OS)
+	}
+	progs := fixUpPCs(rg.target.Arch, params.Progs, params.CoverFilter)
+	data, err := rg.convertToStats(progs)
+	if err != nil {
+		return nil, err
+	}
+	return groupCoverBySubsystem(data, list), nil
+}
+
+// groupCoverBySubsystem aggregates per-file stats into per-subsystem ones.
+// Subsystems without any PCs (not compiled into the kernel) are omitted.
+func groupCoverBySubsystem(datas []fileStats, list []*subsystem.Subsystem) []*SubsystemCover {
+	matcher := subsystem.MakePathMatcher(list)
+	subsystems := make(map[*subsystem.Subsystem]*SubsystemCover)
+	for _, data := range datas {
+		for _, s := range matcher.Match(data.Name) {
+			sc := subsystems[s]
+			if sc == nil {
+				sc = &SubsystemCover{Name: s.Name}
+				subsystems[s] = sc
+			}
+			sc.CoveredPCs += data.CoveredPCs
+			sc.TotalPCs += data.TotalPCs
+			sc.CoveredFuncs += data.CoveredFunctions
+			sc.TotalFuncs += data.TotalFunctions
+			sc.Files++
+		}
+	}
+	var ret []*SubsystemCover
+	for _, sc := range subsystems {
+		if sc.TotalPCs == 0 {
+			continue
+		}
+		sc.Percent = 100.0 * float64(sc.CoveredPCs) / float64(sc.TotalPCs)
+		ret = append(ret, sc)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if a.Percent != b.Percent {
+			return a.Percent > b.Percent
+		}
+		if a.TotalPCs != b.TotalPCs {
+			return a.TotalPCs > b.TotalPCs
+		}
+		return a.Name < b.Name
+	})
+	return ret
+}
+
+var subsystemRankingTemplate = template.Must(template.New("subsystemRanking").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`
+<!DOCTYPE html>
+<html>
+	<head>
+		<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+		<style>
+			body {
+				background: white;
+			}
+			th, td {
+				text-align: left;
+				border: 1px solid black;
+			}
+			th {
+				background: gray;
+			}
+			tr:nth-child(2n+1) {
+				background: #CCC
+			}
+			table {
+				border-collapse: collapse;
+				border: 1px solid black;
+				margin-bottom: 20px;
+			}
+		</style>
+	</head>
+	<body>
+		<table>
+			<thead>
+				<tr>
+					<th>#</th>
+					<th>Subsystem</th>
+					<th>Covered / Total PCs</th>
+					<th>%</th>
+					<th>Covered / Total Functions</th>
+					<th>Files</th>
+				</tr>
+			</thead>
+			<tbody>
+				{{range $i, $s := .}}
+				<tr>
+					<td>{{inc $i}}</td>
+					<td>{{$s.Name}}</td>
+					<td>{{$s.CoveredPCs}} / {{$s.TotalPCs}}</td>
+					<td>{{printf "%.2f" $s.Percent}}%</td>
+					<td>{{$s.CoveredFuncs}} / {{$s.TotalFuncs}}</td>
+					<td>{{$s.Files}}</td>
+				</tr>
+				{{end}}
+			</tbody>
+		</table>
+	</body>
+</html>
+`))
@@ -0,0 +1,54 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/subsystem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupCoverBySubsystem(t *testing.T) {
+	fs := &subsystem.Subsystem{
+		Name:      "fs",
+		PathRules: []subsystem.PathRule{{IncludeRegexp: "^fs/", ExcludeRegexp: "^fs/ext4/"}},
+	}
+	ext4 := &subsystem.Subsystem{
+		Name:      "ext4",
+		PathRules: []subsystem.PathRule{{IncludeRegexp: "^fs/ext4/"}},
+		Parents:   []*subsystem.Subsystem{fs},
+	}
+	mm := &subsystem.Subsystem{
+		Name:      "mm",
+		PathRules: []subsystem.PathRule{{IncludeRegexp: "^mm/|^include/linux/mm\\.h$"}},
+	}
+	net := &subsystem.Subsystem{
+		Name:      "net",
+		PathRules: []subsystem.PathRule{{IncludeRegexp: "^net/"}},
+	}
+	datas := []fileStats{
+		{Name: "fs/open.c", CoveredPCs: 10, TotalPCs: 100, CoveredFunctions: 1, TotalFunctions: 10},
+		{Name: "fs/ext4/inode.c", CoveredPCs: 50, TotalPCs: 100, CoveredFunctions: 3, TotalFunctions: 5},
+		{Name: "mm/slub.c", CoveredPCs: 25, TotalPCs: 50, CoveredFunctions: 2, TotalFunctions: 4},
+		{Name: "include/linux/mm.h", CoveredPCs: 25, TotalPCs: 50},
+		{Name: "kernel/fork.c", CoveredPCs: 1, TotalPCs: 1},
+	}
+	ranking := groupCoverBySubsystem(datas, []*subsystem.Subsystem{fs, ext4, mm, net})
+	// fs/ext4 is excluded from fs, net has no PCs and is omitted.
+	assert.Equal(t, []*SubsystemCover{
+		{Name: "ext4", CoveredPCs: 50, TotalPCs: 100, CoveredFuncs: 3, TotalFuncs: 5, Files: 1, Percent: 50},
+		{Name: "mm", CoveredPCs: 50, TotalPCs: 100, CoveredFuncs: 2, TotalFuncs: 4, Files: 2, Percent: 50},
+		{Name: "fs", CoveredPCs: 10, TotalPCs: 100, CoveredFuncs: 1, TotalFuncs: 10, Files: 1, Percent: 10},
+	}, ranking)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, subsystemRankingTemplate.Execute(buf, ranking))
+	assert.Contains(t, buf.String(), "<td>3</td>\n\t\t\t\t\t<td>fs</td>")
+}
+
+func TestSubsystemListRegistered(t *testing.T) {
+	assert.NotEmpty(t, subsystem.GetList("linux"))
+}
@@ -11,12 +11,22 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
 )
 
+// Limits for compiler invocations, so that a runaway compiler does not take down the host.
+var (
+	buildTimeout = 10 * time.Minute
+	buildLimits  = osutil.Limits{
+		Memory:  8 << 30,
+		CPUTime: 10 * time.Minute,
+	}
+)
+
 // Build builds a C program from source src and returns name of the resulting binary.
 func Build(target *prog.Target, src []byte) (string, error) {
 	return build(target, src, "", "")
@@ -79,7 +89,7 @@ func build(target *prog.Target, src []byte, dir, file string, cflags ...string)
 	if file == "" {
 		cmd.Stdin = bytes.NewReader(src)
 	}
-	out, err := cmd.CombinedOutput()
+	out, err := osutil.RunLimited(buildTimeout, buildLimits, cmd)
 	if err != nil {
 		os.Remove(bin)
 		if file != "" {
@@ -105,6 +105,11 @@ func commonDefines(p *prog.Prog, opts Options) map[string]bool {
 		"SYZ_SANDBOX_ANDROID":           opts.Sandbox == sandboxAndroid,
 		"SYZ_THREADED":                  opts.Threaded,
 		"SYZ_ASYNC":                     features.Async,
+		"SYZ_PROC_ROLES":                features.ProcRoles,
+		"SYZ_TIME_JUMPS":                features.TimeJumps,
+		"SYZ_COMPAT_SYSCALLS":           features.CompatSyscalls,
+		"SYZ_URING_SYSCALLS":            features.UringSyscalls,
+		"SYZ_SUSPEND":                   features.Suspend,
 		"SYZ_REPEAT":                    opts.Repeat,
 		"SYZ_REPEAT_TIMES":              opts.RepeatTimes > 1,
 		"SYZ_MULTI_PROC":                opts.Procs > 1,
@@ -128,6 +133,8 @@ func commonDefines(p *prog.Prog, opts Options) map[string]bool {
 		"SYZ_802154":                    opts.IEEE802154,
 		"SYZ_SYSCTL":                    opts.Sysctl,
 		"SYZ_SWAP":                      opts.Swap,
+		"SYZ_HUGEPAGES":                 opts.Hugepages,
+		"SYZ_PMEM":                      opts.Pmem,
 		"SYZ_EXECUTOR_USES_SHMEM":       sysTarget.ExecutorUsesShmem,
 		"SYZ_EXECUTOR_USES_FORK_SERVER": sysTarget.ExecutorUsesForkServer,
 	}
@@ -42,6 +42,9 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 	if err := opts.Check(p.Target.OS); err != nil {
 		return nil, fmt.Errorf("csource: invalid opts: %w", err)
 	}
+	if opts.Procs > 1 {
+		p = dropRealtimeJumps(p)
+	}
 	ctx := &context{
 		p:         p,
 		opts:      opts,
@@ -52,6 +55,23 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 	return ctx.generateSource()
 }
 
+// dropRealtimeJumps removes CLOCK_REALTIME time jumps from the program.
+// The jumps are VM-wide, so with several procs they would affect calls of other procs
+// (the executor does them only with a single proc as well).
+func dropRealtimeJumps(p *prog.Prog) *prog.Prog {
+	cloned := false
+	for i, c := range p.Calls {
+		if c.Props.TimeJump == prog.TimeJumpNone || c.Props.TimeJump == prog.TimeJumpNamespace {
+			continue
+		}
+		if !cloned {
+			p, cloned = p.Clone(), true
+		}
+		p.Calls[i].Props.TimeJump = prog.TimeJumpNone
+	}
+	return p
+}
+
 type context struct {
 	p         *prog.Prog
 	opts      Options
@@ -259,6 +279,9 @@ func (ctx *context) generateCalls(p prog.ExecProg, trace bool) ([]string, []uint
 			ctx.copyin(w, &csumSeq, copyin)
 		}
 
+		if call.Props.Suspend != prog.SuspendNone {
+			fmt.Fprintf(w, "\tsuspend_resume(%v);\n", call.Props.Suspend)
+		}
 		if call.Props.FailNth > 0 {
 			fmt.Fprintf(w, "\tinject_fault(%v);\n", call.Props.FailNth)
 		}
@@ -266,13 +289,23 @@ func (ctx *context) generateCalls(p prog.ExecProg, trace bool) ([]string, []uint
 		resCopyout := call.Index != prog.ExecNoCopyout
 		argCopyout := len(call.Copyout) != 0
 
-		ctx.emitCall(w, call, ci, resCopyout || argCopyout, trace)
-
-		if call.Props.Rerun > 0 {
-			fmt.Fprintf(w, "\tfor (int i = 0; i < %v; i++) {\n", call.Props.Rerun)
-			// Rerun invocations should not affect the result value.
-			ctx.emitCall(w, call, ci, false, false)
-			fmt.Fprintf(w, "\t}\n")
+		if role := call.Props.ProcRole(); role != prog.RoleMain {
+			ctx.emitRoleCall(w, call, ci, role, resCopyout || argCopyout, trace)
+		} else {
+			if call.Props.TimeJump != prog.TimeJumpNone {
+				fmt.Fprintf(w, "\t{\n\tstruct time_jump_ctx time_jump;\n")
+				fmt.Fprintf(w, "\ttime_jump_enter(&time_jump, %v);\n", call.Props.TimeJump)
+			}
+			ctx.emitCall(w, call, ci, resCopyout || argCopyout, trace)
+			if call.Props.Rerun > 0 {
+				fmt.Fprintf(w, "\tfor (int i = 0; i < %v; i++) {\n", call.Props.Rerun)
+				// Rerun invocations should not affect the result value.
+				ctx.emitCall(w, call, ci, false, false)
+				fmt.Fprintf(w, "\t}\n")
+			}
+			if call.Props.TimeJump != prog.TimeJumpNone {
+				fmt.Fprintf(w, "\ttime_jump_leave(&time_jump);\n\t}\n")
+			}
 		}
 		// Copyout.
 		if resCopyout || argCopyout {
@@ -288,8 +321,32 @@ func isNative(sysTarget *targets.Target, callName string) bool {
 	return sysTarget.HasCallNumber(callName) && !trampoline
 }
 
+// emitRoleCall emits a call that is executed in a child process according to its process role.
+func (ctx *context) emitRoleCall(w *bytes.Buffer, call prog.ExecCall, ci, role int, haveCopyout, trace bool) {
+	fmt.Fprintf(w, "\t{\n\tstruct role_ctx role;\n")
+	fmt.Fprintf(w, "\tif (role_fork(&role, %v) == 0) {\n", role)
+	fmt.Fprintf(w, "\tintptr_t res = -1;\n")
+	if call.Props.TimeJump != prog.TimeJumpNone {
+		fmt.Fprintf(w, "\tstruct time_jump_ctx time_jump;\n")
+		fmt.Fprintf(w, "\ttime_jump_enter(&time_jump, %v);\n", call.Props.TimeJump)
+	}
+	ctx.emitCall(w, call, ci, true, false)
+	if call.Props.TimeJump != prog.TimeJumpNone {
+		fmt.Fprintf(w, "\ttime_jump_leave(&time_jump);\n")
+	}
+	fmt.Fprintf(w, "\trole_exit(&role, res, errno);\n\t}\n\t")
+	if haveCopyout || trace {
+		fmt.Fprintf(w, "res = ")
+	}
+	fmt.Fprintf(w, "role_wait(&role);\n\t}\n")
+	if trace {
+		fmt.Fprintf(w, "\tfprintf(stderr, \"### call=%v errno=%%u\\n\", res == -1 ? errno : 0);\n", ci)
+	}
+}
+
 func (ctx *context) emitCall(w *bytes.Buffer, call prog.ExecCall, ci int, haveCopyout, trace bool) {
-	native := isNative(ctx.sysTarget, call.Meta.CallName)
+	// uring_syscall reads some arguments (e.g. splice offsets) from memory in user-space.
+	native := isNative(ctx.sysTarget, call.Meta.CallName) && !call.Props.Uring
 	fmt.Fprintf(w, "\t")
 	if !native {
 		// This mimics the same as executor does for execute_syscall,
@@ -335,7 +392,13 @@ func (ctx *context) fmtCallBody(call prog.ExecCall) string {
 	}
 	argsStrs := []string{}
 	funcName := ""
-	if native {
+	if call.Props.Compat {
+		funcName = "compat_syscall"
+		argsStrs = append(argsStrs, fmt.Sprintf("/*%v*/%v", callName, call.Meta.CompatNR))
+	} else if call.Props.Uring {
+		funcName = "uring_syscall"
+		argsStrs = append(argsStrs, ctx.sysTarget.SyscallPrefix+callName)
+	} else if native {
 		funcName = "syscall"
 		argsStrs = append(argsStrs, ctx.sysTarget.SyscallPrefix+callName)
 	} else if strings.HasPrefix(callName, "syz_") {
@@ -375,6 +438,10 @@ func (ctx *context) fmtCallBody(call prog.ExecCall) string {
 	for i := 0; i < call.Meta.MissingArgs; i++ {
 		argsStrs = append(argsStrs, "0")
 	}
+	// compat_syscall and uring_syscall take the syscall number and exactly 6 args.
+	for (call.Props.Compat || call.Props.Uring) && len(argsStrs) < 7 {
+		argsStrs = append(argsStrs, "0")
+	}
 	return fmt.Sprintf("%v(%v)", funcName, strings.Join(argsStrs, ", "))
 }
 
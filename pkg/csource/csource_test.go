@@ -154,6 +154,34 @@ func testOne(t *testing.T, p *prog.Prog, opts Options) {
 	defer os.Remove(bin)
 }
 
+func TestRealtimeJumpsMultiProc(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("r0 = csource0(0x1) (time_jump: 1)\ncsource1(r0) (time_jump: 4)\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, procs := range []int{1, 2} {
+		opts := ExecutorOpts
+		opts.Procs = procs
+		src, err := Write(p, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(src), "time_jump_enter(&time_jump, 1);"); got != (procs == 1) {
+			t.Errorf("procs=%v: realtime jump is emitted: %v", procs, got)
+		}
+		if !strings.Contains(string(src), "time_jump_enter(&time_jump, 4);") {
+			t.Errorf("procs=%v: time namespace jump is not emitted", procs)
+		}
+	}
+	if p.Calls[0].Props.TimeJump != prog.TimeJumpForward {
+		t.Errorf("the original program was changed")
+	}
+}
+
 func TestExecutorMacros(t *testing.T) {
 	// Ensure that executor does not mis-spell any of the SYZ_* macros.
 	target, _ := prog.GetTarget(targets.TestOS, targets.TestArch64)
@@ -240,6 +268,84 @@ syscall(SYS_csource7, /*flag=BIT_1*/2ul);
 syscall(SYS_csource7, /*flag=BIT_0_AND_1*/3ul);
 syscall(SYS_csource7, /*flag=*/4ul);
 syscall(SYS_csource7, /*flag=BIT_0|0x4*/5ul);
+`,
+		},
+		{
+			input: `
+r0 = csource0(0x1) (role: 1)
+csource1(r0) (role: 2)
+`,
+			output: `
+{
+struct role_ctx role;
+if (role_fork(&role, 1) == 0) {
+intptr_t res = -1;
+res = syscall(SYS_csource0, /*num=*/1);
+role_exit(&role, res, errno);
+}
+res = role_wait(&role);
+}
+if (res != -1)
+	r[0] = res;
+{
+struct role_ctx role;
+if (role_fork(&role, 2) == 0) {
+intptr_t res = -1;
+res = syscall(SYS_csource1, /*fd=*/r[0]);
+role_exit(&role, res, errno);
+}
+role_wait(&role);
+}
+`,
+		},
+		{
+			input: `
+r0 = csource0(0x1) (time_jump: 1)
+csource1(r0) (time_jump: 4)
+`,
+			output: `
+{
+struct time_jump_ctx time_jump;
+time_jump_enter(&time_jump, 1);
+res = syscall(SYS_csource0, /*num=*/1);
+time_jump_leave(&time_jump);
+}
+if (res != -1)
+	r[0] = res;
+{
+struct role_ctx role;
+if (role_fork(&role, 1) == 0) {
+intptr_t res = -1;
+struct time_jump_ctx time_jump;
+time_jump_enter(&time_jump, 4);
+res = syscall(SYS_csource1, /*fd=*/r[0]);
+time_jump_leave(&time_jump);
+role_exit(&role, res, errno);
+}
+role_wait(&role);
+}
+`,
+		},
+		{
+			input: `
+r0 = csource0(0x1) (suspend: 1)
+csource1(r0) (suspend: 2, role: 1)
+`,
+			output: `
+suspend_resume(1);
+res = syscall(SYS_csource0, /*num=*/1);
+if (res != -1)
+	r[0] = res;
+suspend_resume(2);
+{
+struct role_ctx role;
+if (role_fork(&role, 1) == 0) {
+intptr_t res = -1;
+res = syscall(SYS_csource1, /*fd=*/r[0]);
+role_exit(&role, res, errno);
+}
+role_wait(&role);
+}
 `,
 		},
 	}
@@ -266,6 +372,70 @@ syscall(SYS_csource7, /*flag=BIT_0|0x4*/5ul);
 	}
 }
 
+func TestSourceCompat(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte(`
+r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x0, 0x0) (compat)
+close(r0) (compat, rerun: 2)
+`), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &context{
+		p:         p,
+		target:    target,
+		sysTarget: targets.Get(target.OS, target.Arch),
+	}
+	calls, _, err := ctx.generateProgCalls(p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := strings.Join(calls, "")
+	assert.Contains(t, src, "res = compat_syscall(/*openat*/295, /*fd=*/0xffffff9c, /*file=*/0x20000000ul, "+
+		"/*flags=*/0ul, /*mode=*/0ul, 0, 0);")
+	assert.Contains(t, src, "compat_syscall(/*close*/6, /*fd=*/r[0], 0, 0, 0, 0, 0);")
+	if runtime.GOOS != targets.Linux || runtime.GOARCH != targets.AMD64 {
+		return
+	}
+	testOne(t, p, ExecutorOpts)
+}
+
+func TestSourceUring(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte(`
+r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x42, 0x0) (uring)
+write(r0, &(0x7f0000000040)="0102", 0x2) (uring)
+close(r0) (uring)
+`), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &context{
+		p:         p,
+		target:    target,
+		sysTarget: targets.Get(target.OS, target.Arch),
+	}
+	calls, _, err := ctx.generateProgCalls(p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := strings.Join(calls, "")
+	// uring_syscall dereferences some of the arguments, so the calls are not assumed to not crash.
+	assert.Contains(t, src, "NONFAILING(res = uring_syscall(__NR_openat, /*fd=*/0xffffff9c, /*file=*/0x20000000ul, "+
+		"/*flags=O_CREAT|O_RDWR*/0x42ul, /*mode=*/0ul, 0, 0));")
+	assert.Contains(t, src, "NONFAILING(uring_syscall(__NR_close, /*fd=*/r[0], 0, 0, 0, 0, 0));")
+	if runtime.GOOS != targets.Linux || runtime.GOARCH != targets.AMD64 {
+		return
+	}
+	testOne(t, p, ExecutorOpts)
+}
+
 func generateSandboxFunctionSignatureTestCase(t *testing.T, sandbox string, sandboxArg int, expected, message string) {
 	actual := generateSandboxFunctionSignature(sandbox, sandboxArg)
 	assert.Equal(t, actual, expected, message)
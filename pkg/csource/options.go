@@ -44,6 +44,8 @@ type Options struct {
 	IEEE802154    bool `json:"ieee802154,omitempty"`
 	Sysctl        bool `json:"sysctl,omitempty"`
 	Swap          bool `json:"swap,omitempty"`
+	Hugepages     bool `json:"hugepages,omitempty"`
+	Pmem          bool `json:"pmem,omitempty"`
 
 	UseTmpDir  bool `json:"tmpdir,omitempty"`
 	HandleSegv bool `json:"segv,omitempty"`
@@ -149,6 +151,8 @@ func (opts Options) checkLinuxOnly(OS string) error {
 		"Leak":          &opts.Leak,
 		"Sysctl":        &opts.Sysctl,
 		"Swap":          &opts.Swap,
+		"Hugepages":     &opts.Hugepages,
+		"Pmem":          &opts.Pmem,
 	} {
 		if *opt {
 			return fmt.Errorf("option %v is not supported on %v", name, OS)
@@ -182,6 +186,7 @@ func DefaultOpts(cfg *mgrconfig.Config) Options {
 		opts.IEEE802154 = true
 		opts.Sysctl = true
 		opts.Swap = true
+		opts.Pmem = true
 	}
 	if cfg.Sandbox == "" || cfg.Sandbox == "setuid" {
 		opts.NetReset = false
@@ -302,6 +307,9 @@ func defaultFeatures(value bool) Features {
 		"ieee802154":  {"setup and use mac802154_hwsim for emulation", value},
 		"sysctl":      {"setup sysctl's for fuzzing", value},
 		"swap":        {"setup and use a swap file", value},
+		// Hugepages affect mm behavior of the whole machine, so they are set up only if enabled explicitly.
+		"hugepages": {"setup hugetlb pool and transparent hugepages (only if listed in -enable)", false},
+		"pmem":      {"setup emulated pmem device with DAX (needs memmap= kernel arg)", value},
 	}
 }
 
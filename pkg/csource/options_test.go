@@ -308,6 +308,7 @@ func TestParseFeaturesFlags(t *testing.T) {
 			"ieee802154":  true,
 			"sysctl":      true,
 			"swap":        true,
+			"pmem":        true,
 		}},
 		{"none", "none", false, map[string]bool{}},
 		{"all", "none", true, map[string]bool{
@@ -325,6 +326,7 @@ func TestParseFeaturesFlags(t *testing.T) {
 			"ieee802154":  true,
 			"sysctl":      true,
 			"swap":        true,
+			"pmem":        true,
 		}},
 		{"", "none", true, map[string]bool{}},
 		{"none", "all", true, map[string]bool{}},
@@ -343,6 +345,7 @@ func TestParseFeaturesFlags(t *testing.T) {
 			"ieee802154":  true,
 			"sysctl":      true,
 			"swap":        true,
+			"pmem":        true,
 		}},
 		{"tun,net_dev", "none", true, map[string]bool{
 			"tun":     true,
@@ -361,6 +364,7 @@ func TestParseFeaturesFlags(t *testing.T) {
 			"ieee802154":  true,
 			"sysctl":      true,
 			"swap":        true,
+			"pmem":        true,
 		}},
 		{"close_fds", "none", true, map[string]bool{
 			"close_fds": true,
@@ -368,6 +372,25 @@ func TestParseFeaturesFlags(t *testing.T) {
 		{"swap", "none", true, map[string]bool{
 			"swap": true,
 		}},
+		{"hugepages", "none", true, map[string]bool{
+			"hugepages": true,
+		}},
+		{"none", "pmem", true, map[string]bool{
+			"tun":         true,
+			"net_dev":     true,
+			"net_reset":   true,
+			"cgroups":     true,
+			"binfmt_misc": true,
+			"close_fds":   true,
+			"devlink_pci": true,
+			"nic_vf":      true,
+			"usb":         true,
+			"vhci":        true,
+			"wifi":        true,
+			"ieee802154":  true,
+			"sysctl":      true,
+			"swap":        true,
+		}},
 	}
 	for i, test := range tests {
 		features, err := ParseFeaturesFlags(test.Enable, test.Disable, test.Default)
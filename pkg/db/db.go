@@ -6,6 +6,19 @@
 // It is used to store corpus in syz-manager and syz-hub.
 // The database strives to minimize number of disk accesses
 // as they can be slow in virtualized environments (GCE).
+//
+// On disk the database is an append-only log of checksummed records
+// which is periodically compacted in background by atomically replacing the file with a fresh copy.
+// If the log is corrupted (e.g. due to a crash or a full disk), Open skips damaged
+// records and salvages all records with valid checksums. A truncated last record
+// (a crash in the middle of an append) is silently dropped.
+//
+// Programs often contain the same big data arguments (file system images, firmware blobs, etc).
+// Such blobs are stored on disk only once as content-addressed records (keyed by the blob hash)
+// that are referenced by all records that contain them. The deduplication is on-disk only:
+// Record.Val is a contiguous value, so in memory every record holds its own copy of the blobs.
+// Databases with blobs have format version 4, binaries built before it fail to open them
+// with "bad db version: 4" error (use syz-db unpack/pack of the older binary to convert).
 package db
 
 import (
@@ -13,10 +26,15 @@ import (
 	"bytes"
 	"compress/flate"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -30,6 +48,19 @@ type DB struct {
 	filename    string
 	uncompacted int           // number of records in the file
 	pending     *bytes.Buffer // pending writes to the file
+	blobs       map[hash.Sig]*blob
+	refs        map[string][]hash.Sig // blobs referenced by each record
+
+	mu          sync.Mutex
+	compactDone chan struct{} // non-nil while background compaction is in progress
+	compactTail *bytes.Buffer // records appended to the file while background compaction is in progress
+	compactErr  error         // error of the last background compaction
+}
+
+type blob struct {
+	// The data aliases Val of one of the referencing records, so blobs don't consume additional memory.
+	data []byte
+	refs int
 }
 
 type Record struct {
@@ -49,8 +80,7 @@ func Open(filename string, repair bool) (*DB, error) {
 		return nil, err
 	}
 	defer f.Close()
-	var deserializeErr error
-	db.Version, db.Records, db.uncompacted, deserializeErr = deserializeDB(bufio.NewReader(f))
+	deserializeErr := db.deserialize(f)
 	// Deserialization error is considered a "soft" error if repair == true,
 	// but compact below ensures that the file is at least writable.
 	if deserializeErr != nil && !repair {
@@ -67,11 +97,32 @@ func (db *DB) Save(key string, val []byte, seq uint64) {
 	if seq == seqDeleted {
 		panic("reserved seq")
 	}
+	if strings.HasPrefix(key, blobKeyPrefix) {
+		panic("reserved key")
+	}
 	if rec, ok := db.Records[key]; ok && seq == rec.Seq && bytes.Equal(val, rec.Val) {
 		return
 	}
+	db.unref(key)
+	spans := blobSpans(val)
+	sigs := make([]hash.Sig, len(spans))
+	for i, span := range spans {
+		data := val[span[0]:span[1]]
+		sigs[i] = hash.Hash(data)
+		b := db.blobs[sigs[i]]
+		if b == nil {
+			b = &blob{data: data}
+			db.blobs[sigs[i]] = b
+			db.serialize(blobKey(sigs[i]), data, 0)
+			db.uncompacted++
+		}
+		b.refs++
+	}
+	if len(sigs) != 0 {
+		db.refs[key] = sigs
+	}
 	db.Records[key] = Record{val, seq}
-	db.serialize(key, val, seq)
+	db.serialize(key, encodeValue(val, spans, sigs), seq)
 	db.uncompacted++
 }
 
@@ -79,15 +130,55 @@ func (db *DB) Delete(key string) {
 	if _, ok := db.Records[key]; !ok {
 		return
 	}
+	db.unref(key)
 	delete(db.Records, key)
 	db.serialize(key, nil, seqDeleted)
 	db.uncompacted++
 }
 
+// unref drops references to blobs from the current value of the record.
+// Unreferenced blobs are removed from the file during compaction.
+func (db *DB) unref(key string) {
+	for _, sig := range db.refs[key] {
+		b := db.blobs[sig]
+		if b.refs--; b.refs == 0 {
+			delete(db.blobs, sig)
+		}
+	}
+	delete(db.refs, key)
+}
+
+// BlobStats returns the number of distinct blobs in the database, their total size,
+// and the total size of all their occurrences in the records.
+func (db *DB) BlobStats() (count, size, refSize int) {
+	for _, b := range db.blobs {
+		count++
+		size += len(b.data)
+		refSize += b.refs * len(b.data)
+	}
+	return
+}
+
+// Flush writes pending changes to the file. If the file contains too many stale records,
+// Flush starts background compaction. Errors of background compaction are returned
+// by the next Flush call.
 func (db *DB) Flush() error {
-	if db.uncompacted/10*9 > len(db.Records) {
-		return db.compact()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if err := db.compactErr; err != nil {
+		db.compactErr = nil
+		return err
 	}
+	if err := db.appendPending(); err != nil {
+		return err
+	}
+	if db.compactDone == nil && db.uncompacted/10*9 > len(db.Records)+len(db.blobs) {
+		db.compactBackground()
+	}
+	return nil
+}
+
+func (db *DB) appendPending() error {
 	if db.pending == nil {
 		return nil
 	}
@@ -99,6 +190,9 @@ func (db *DB) Flush() error {
 	if _, err := f.Write(db.pending.Bytes()); err != nil {
 		return err
 	}
+	if db.compactTail != nil {
+		db.compactTail.Write(db.pending.Bytes())
+	}
 	db.pending = nil
 	return nil
 }
@@ -111,27 +205,114 @@ func (db *DB) BumpVersion(version uint64) error {
 	return db.compact()
 }
 
+type snapshot struct {
+	version uint64
+	records map[string]Record
+	blobs   map[hash.Sig][]byte
+	refs    map[string][]hash.Sig
+}
+
+// compact synchronously rewrites the file with the current contents of the database.
 func (db *DB) compact() error {
-	buf := new(bytes.Buffer)
-	serializeHeader(buf, db.Version)
+	db.waitCompaction()
+	return db.writeSnapshot(db.snapshot())
+}
+
+// compactBackground starts rewriting the file in background, the caller must hold db.mu.
+// The old file stays valid and receives all appends until it's replaced.
+func (db *DB) compactBackground() {
+	snap := db.snapshot()
+	done := make(chan struct{})
+	db.compactDone = done
+	db.compactTail = new(bytes.Buffer)
+	go func() {
+		err := db.writeSnapshot(snap)
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		db.compactErr = err
+		db.compactDone = nil
+		db.compactTail = nil
+		close(done)
+	}()
+}
+
+func (db *DB) waitCompaction() {
+	db.mu.Lock()
+	done := db.compactDone
+	db.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// snapshot captures the current contents of the database for compaction.
+// Pending records are dropped since they are included in the snapshot.
+func (db *DB) snapshot() *snapshot {
+	snap := &snapshot{
+		version: db.Version,
+		records: make(map[string]Record, len(db.Records)),
+		blobs:   make(map[hash.Sig][]byte, len(db.blobs)),
+		refs:    make(map[string][]hash.Sig, len(db.refs)),
+	}
 	for key, rec := range db.Records {
-		serializeRecord(buf, key, rec.Val, rec.Seq)
+		snap.records[key] = rec
+	}
+	for sig, b := range db.blobs {
+		snap.blobs[sig] = b.data
 	}
-	f, err := os.Create(db.filename + ".tmp")
+	for key, sigs := range db.refs {
+		snap.refs[key] = sigs
+	}
+	db.uncompacted = len(db.Records) + len(db.blobs)
+	db.pending = nil
+	return snap
+}
+
+// writeSnapshot writes the snapshot into a temp file and atomically replaces the database file with it.
+// Records that were appended to the database file in the meantime are appended to the new file as well.
+func (db *DB) writeSnapshot(snap *snapshot) error {
+	buf := new(bytes.Buffer)
+	serializeHeader(buf, snap.version)
+	// Blobs must precede records that reference them.
+	for sig, data := range snap.blobs {
+		serializeRecord(buf, blobKey(sig), data, 0)
+	}
+	for key, rec := range snap.records {
+		sigs := snap.refs[key]
+		var spans [][2]int
+		if len(sigs) != 0 {
+			spans = blobSpans(rec.Val)
+		}
+		serializeRecord(buf, key, encodeValue(rec.Val, spans, sigs), rec.Seq)
+	}
+	f, err := os.CreateTemp(filepath.Dir(db.filename), filepath.Base(db.filename)+".tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(f.Name())
 	defer f.Close()
+	if err := f.Chmod(osutil.DefaultFilePerm); err != nil {
+		return err
+	}
 	if _, err := f.Write(buf.Bytes()); err != nil {
 		return err
 	}
-	f.Close()
-	if err := osutil.Rename(f.Name(), db.filename); err != nil {
+	// Flush appends to the old file under db.mu, so holding it until the rename
+	// ensures that no appended records are lost.
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.compactTail != nil {
+		if _, err := f.Write(db.compactTail.Bytes()); err != nil {
+			return err
+		}
+	}
+	// Make sure the new file reaches the disk before it replaces the old one,
+	// otherwise a crash can leave us with an empty or partially written file.
+	if err := f.Sync(); err != nil {
 		return err
 	}
-	db.uncompacted = len(db.Records)
-	db.pending = nil
-	return nil
+	f.Close()
+	return osutil.Rename(f.Name(), db.filename)
 }
 
 func (db *DB) serialize(key string, val []byte, seq uint64) {
@@ -144,10 +325,104 @@ func (db *DB) serialize(key string, val []byte, seq uint64) {
 const (
 	dbMagic    = uint32(0xbaddb)
 	recMagic   = uint32(0xfee1bad)
-	curVersion = uint32(2)
+	curVersion = uint32(4)
 	seqDeleted = ^uint64(0)
+
+	// Contents of double-quoted strings (that's how programs serialize binary data) of this size
+	// or larger are stored as separate blobs.
+	blobMinSize   = 4 << 10
+	blobKeyPrefix = "\x00blob:"
 )
 
+func blobKey(sig hash.Sig) string {
+	return blobKeyPrefix + sig.String()
+}
+
+// blobSpans returns [start, end) offsets of blobs in the record value.
+func blobSpans(val []byte) [][2]int {
+	var spans [][2]int
+	for pos := 0; pos < len(val); {
+		start := bytes.IndexByte(val[pos:], '"')
+		if start == -1 {
+			break
+		}
+		start += pos + 1
+		end := bytes.IndexByte(val[start:], '"')
+		if end == -1 {
+			break
+		}
+		end += start
+		if end-start >= blobMinSize {
+			spans = append(spans, [2]int{start, end})
+		}
+		pos = end + 1
+	}
+	return spans
+}
+
+// encodeValue replaces blobs in the record value with references.
+// The encoded value is: number of blobs, then for each blob the length of the preceding literal part,
+// the literal part and the blob hash, then the final literal part.
+func encodeValue(val []byte, spans [][2]int, sigs []hash.Sig) []byte {
+	if len(val) == 0 {
+		return nil
+	}
+	buf := make([]byte, 0, len(val)-len(spans)*(blobMinSize-len(hash.Sig{}))+binary.MaxVarintLen64)
+	buf = binary.AppendUvarint(buf, uint64(len(spans)))
+	pos := 0
+	for i, span := range spans {
+		buf = binary.AppendUvarint(buf, uint64(span[0]-pos))
+		buf = append(buf, val[pos:span[0]]...)
+		buf = append(buf, sigs[i][:]...)
+		pos = span[1]
+	}
+	return append(buf, val[pos:]...)
+}
+
+func decodeValue(enc []byte, blobs map[hash.Sig]*blob) ([]byte, []hash.Sig, error) {
+	if len(enc) == 0 {
+		return nil, nil, nil
+	}
+	count, n := binary.Uvarint(enc)
+	if n <= 0 || count > uint64(len(enc)) {
+		return nil, nil, fmt.Errorf("bad blob count")
+	}
+	enc = enc[n:]
+	// First pass validates the encoding and calculates the size of the value,
+	// so that the value is allocated and copied only once.
+	var sigs []hash.Sig
+	var literals [][]byte
+	size := 0
+	for i := uint64(0); i < count; i++ {
+		litSize, n := binary.Uvarint(enc)
+		if n <= 0 || litSize > uint64(len(enc)-n) || len(enc)-n-int(litSize) < len(hash.Sig{}) {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		enc = enc[n:]
+		literals = append(literals, enc[:litSize])
+		enc = enc[litSize:]
+		var sig hash.Sig
+		copy(sig[:], enc)
+		enc = enc[len(sig):]
+		b := blobs[sig]
+		if b == nil {
+			return nil, nil, fmt.Errorf("missing blob %v", sig.String())
+		}
+		sigs = append(sigs, sig)
+		size += int(litSize) + len(b.data)
+	}
+	val := make([]byte, 0, size+len(enc))
+	for i, sig := range sigs {
+		val = append(val, literals[i]...)
+		b := blobs[sig]
+		start := len(val)
+		val = append(val, b.data...)
+		// Make the blob alias the new value to free the memory occupied by the blob record.
+		b.data = val[start:len(val):len(val)]
+	}
+	return append(val, enc...), sigs, nil
+}
+
 func serializeHeader(w *bytes.Buffer, version uint64) {
 	binary.Write(w, binary.LittleEndian, dbMagic)
 	binary.Write(w, binary.LittleEndian, curVersion)
@@ -155,6 +430,7 @@ func serializeHeader(w *bytes.Buffer, version uint64) {
 }
 
 func serializeRecord(w *bytes.Buffer, key string, val []byte, seq uint64) {
+	start := w.Len()
 	binary.Write(w, binary.LittleEndian, recMagic)
 	binary.Write(w, binary.LittleEndian, uint32(len(key)))
 	w.WriteString(key)
@@ -163,9 +439,7 @@ func serializeRecord(w *bytes.Buffer, key string, val []byte, seq uint64) {
 		if len(val) != 0 {
 			panic("deleting record with value")
 		}
-		return
-	}
-	if len(val) == 0 {
+	} else if len(val) == 0 {
 		binary.Write(w, binary.LittleEndian, uint32(len(val)))
 	} else {
 		lenPos := len(w.Bytes())
@@ -181,95 +455,234 @@ func serializeRecord(w *bytes.Buffer, key string, val []byte, seq uint64) {
 		fw.Close()
 		binary.Write(bytes.NewBuffer(w.Bytes()[lenPos:lenPos:lenPos+8]), binary.LittleEndian, uint32(len(w.Bytes())-startPos))
 	}
+	binary.Write(w, binary.LittleEndian, crc32.ChecksumIEEE(w.Bytes()[start:]))
 }
 
-func deserializeDB(r *bufio.Reader) (version uint64, records map[string]Record, uncompacted int, err0 error) {
-	records = make(map[string]Record)
-	ver, err := deserializeHeader(r)
+func (db *DB) deserialize(f *os.File) (err0 error) {
+	db.Records = make(map[string]Record)
+	db.blobs = make(map[hash.Sig]*blob)
+	db.refs = make(map[string][]hash.Sig)
+	defer db.countRefs()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	r := &recordReader{f: f, r: bufio.NewReader(f), size: st.Size()}
+	ver, fmtVer, err := deserializeHeader(r)
 	if err != nil {
 		err0 = fmt.Errorf("failed to deserialize database header: %w", err)
 		return
 	}
-	version = ver
+	db.Version = ver
+	corrupted := 0
 	for {
-		key, val, seq, err := deserializeRecord(r)
+		pos := r.pos
+		key, val, seq, err := r.readRecord(fmtVer)
 		if err == io.EOF {
-			return
+			break
+		}
+		var sigs []hash.Sig
+		if err == nil && fmtVer >= 4 && seq != seqDeleted {
+			if sigStr, ok := strings.CutPrefix(key, blobKeyPrefix); ok {
+				var sig hash.Sig
+				if sig, err = hash.FromString(sigStr); err == nil {
+					db.blobs[sig] = &blob{data: val}
+					db.uncompacted++
+					continue
+				}
+			} else {
+				val, sigs, err = decodeValue(val, db.blobs)
+			}
 		}
 		if err != nil {
-			err0 = fmt.Errorf("failed to deserialize database record: %w", err)
-			return
+			// Old formats don't have checksums, so we can't reliably find the next valid record.
+			if fmtVer < 3 {
+				if err0 == nil {
+					err0 = fmt.Errorf("failed to deserialize database record at offset %v: %w", pos, err)
+				}
+				return
+			}
+			if seekErr := r.seek(pos + 1); seekErr != nil {
+				return seekErr
+			}
+			next := r.skipToRecord()
+			if !next && errors.Is(err, io.ErrUnexpectedEOF) {
+				// We crashed while appending the last record, it's not a corruption.
+				break
+			}
+			if err0 == nil {
+				err0 = fmt.Errorf("failed to deserialize database record at offset %v: %w", pos, err)
+			}
+			corrupted++
+			if !next {
+				break
+			}
+			continue
 		}
-		uncompacted++
+		db.uncompacted++
+		delete(db.refs, key)
 		if seq == seqDeleted {
-			delete(records, key)
+			delete(db.Records, key)
 		} else {
-			records[key] = Record{val, seq}
+			db.Records[key] = Record{val, seq}
+			if len(sigs) != 0 {
+				db.refs[key] = sigs
+			}
+		}
+	}
+	if corrupted > 1 {
+		err0 = fmt.Errorf("%w (and %v more corrupted records)", err0, corrupted-1)
+	}
+	return
+}
+
+// countRefs counts references to blobs from the final record values and drops unreferenced blobs.
+func (db *DB) countRefs() {
+	for _, sigs := range db.refs {
+		for _, sig := range sigs {
+			db.blobs[sig].refs++
+		}
+	}
+	for sig, b := range db.blobs {
+		if b.refs == 0 {
+			delete(db.blobs, sig)
+		}
+	}
+}
+
+// recordReader reads the database file without loading the whole file into memory.
+type recordReader struct {
+	f    *os.File
+	r    *bufio.Reader
+	pos  int64 // file offset of the next byte returned by r
+	size int64
+}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.pos += int64(n)
+	return n, err
+}
+
+func (r *recordReader) seek(pos int64) error {
+	if _, err := r.f.Seek(pos, io.SeekStart); err != nil {
+		return err
+	}
+	r.r.Reset(r.f)
+	r.pos = pos
+	return nil
+}
+
+// skipToRecord advances the reader to the next potential record, returns false if there are none.
+func (r *recordReader) skipToRecord() bool {
+	var magic [4]byte
+	binary.LittleEndian.PutUint32(magic[:], recMagic)
+	for {
+		buf, err := r.r.Peek(len(magic))
+		if err != nil {
+			return false
 		}
+		if bytes.Equal(buf, magic[:]) {
+			return true
+		}
+		r.r.Discard(1)
+		r.pos++
 	}
 }
 
-func deserializeHeader(r *bufio.Reader) (uint64, error) {
+func deserializeHeader(r io.Reader) (uint64, uint32, error) {
 	var magic, ver uint32
 	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
 		if err == io.EOF {
-			return 0, nil
+			return 0, curVersion, nil
 		}
-		return 0, err
+		return 0, 0, err
 	}
 	if magic != dbMagic {
-		return 0, fmt.Errorf("bad db header: 0x%x", magic)
+		return 0, 0, fmt.Errorf("bad db header: 0x%x", magic)
 	}
 	if err := binary.Read(r, binary.LittleEndian, &ver); err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	if ver == 0 || ver > curVersion {
-		return 0, fmt.Errorf("bad db version: %v", ver)
+		return 0, 0, fmt.Errorf("bad db version: %v", ver)
 	}
 	var userVer uint64
 	if ver >= 2 {
 		if err := binary.Read(r, binary.LittleEndian, &userVer); err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 	}
-	return userVer, nil
+	return userVer, ver, nil
 }
 
-func deserializeRecord(r *bufio.Reader) (key string, val []byte, seq uint64, err error) {
+// readRecord reads a single record. fmtVer is the database format version.
+// It returns io.EOF if there are no more records and io.ErrUnexpectedEOF if the last record is truncated.
+func (r *recordReader) readRecord(fmtVer uint32) (key string, val []byte, seq uint64, err error) {
+	sum := crc32.NewIEEE()
+	tr := io.TeeReader(r, sum)
 	var magic uint32
-	if err = binary.Read(r, binary.LittleEndian, &magic); err != nil {
+	if err = binary.Read(tr, binary.LittleEndian, &magic); err != nil {
 		return
 	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
 	if magic != recMagic {
 		err = fmt.Errorf("bad record header: 0x%x", magic)
 		return
 	}
 	var keyLen uint32
-	if err = binary.Read(r, binary.LittleEndian, &keyLen); err != nil {
+	if err = binary.Read(tr, binary.LittleEndian, &keyLen); err != nil {
 		return
 	}
-	keyBuf := make([]byte, keyLen)
-	if _, err = io.ReadFull(r, keyBuf); err != nil {
+	if int64(keyLen) > r.size-r.pos {
+		err = io.ErrUnexpectedEOF
 		return
 	}
-	key = string(keyBuf)
-	if err = binary.Read(r, binary.LittleEndian, &seq); err != nil {
+	keyBuf := make([]byte, keyLen)
+	if _, err = io.ReadFull(tr, keyBuf); err != nil {
 		return
 	}
-	if seq == seqDeleted {
+	if err = binary.Read(tr, binary.LittleEndian, &seq); err != nil {
 		return
 	}
-	var valLen uint32
-	if err = binary.Read(r, binary.LittleEndian, &valLen); err != nil {
-		return
+	var valData []byte
+	if seq != seqDeleted {
+		var valLen uint32
+		if err = binary.Read(tr, binary.LittleEndian, &valLen); err != nil {
+			return
+		}
+		if int64(valLen) > r.size-r.pos {
+			err = io.ErrUnexpectedEOF
+			return
+		}
+		valData = make([]byte, valLen)
+		if _, err = io.ReadFull(tr, valData); err != nil {
+			return
+		}
 	}
-	if valLen != 0 {
-		fr := flate.NewReader(&io.LimitedReader{R: r, N: int64(valLen)})
+	if fmtVer >= 3 {
+		want := sum.Sum32()
+		var got uint32
+		if err = binary.Read(r, binary.LittleEndian, &got); err != nil {
+			return
+		}
+		if got != want {
+			err = fmt.Errorf("bad record checksum: 0x%x, want 0x%x", got, want)
+			return
+		}
+	}
+	if len(valData) != 0 {
+		fr := flate.NewReader(bytes.NewReader(valData))
 		if val, err = io.ReadAll(fr); err != nil {
 			return
 		}
 		fr.Close()
 	}
+	key = string(keyBuf)
 	return
 }
 
@@ -4,6 +4,8 @@
 package db
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"os"
@@ -190,6 +192,225 @@ func TestOpenCorrupted(t *testing.T) {
 	}
 }
 
+func TestOpenCorruptedMiddle(t *testing.T) {
+	fn := tempFile(t)
+	defer os.Remove(fn)
+	db, err := Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		db.Save(fmt.Sprintf("%v", i), []byte(fmt.Sprintf("value%v", i)), uint64(i))
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("failed to read db: %v", err)
+	}
+	// Corrupt a few bytes in the middle and chop off the tail (as if we crashed while appending).
+	// All other records must be salvaged.
+	data[len(data)/2] ^= 0xff
+	data[len(data)/2+1] ^= 0xff
+	data = data[:len(data)-3]
+	if err := osutil.WriteFile(fn, data); err != nil {
+		t.Fatalf("failed to write db: %v", err)
+	}
+	if _, err := Open(fn, false); err == nil {
+		t.Fatalf("no error for corrupted db")
+	}
+	db, err = Open(fn, true)
+	if err == nil {
+		t.Fatalf("no error for corrupted db")
+	}
+	t.Logf("records %v, error: %v", len(db.Records), err)
+	if len(db.Records) < 996 || len(db.Records) > 998 {
+		t.Fatalf("wrong record count: %v", len(db.Records))
+	}
+	for key, rec := range db.Records {
+		if want := "value" + key; string(rec.Val) != want || fmt.Sprint(rec.Seq) != key {
+			t.Fatalf("corrupted record %v: %q/%v", key, rec.Val, rec.Seq)
+		}
+	}
+	// Open compacts the file, so it must be clean now.
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to reopen db: %v", err)
+	}
+	if len(db.Records) < 996 || len(db.Records) > 998 {
+		t.Fatalf("wrong record count: %v", len(db.Records))
+	}
+}
+
+func TestOpenTornTail(t *testing.T) {
+	fn := tempFile(t)
+	defer os.Remove(fn)
+	db, err := Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		db.Save(fmt.Sprint(i), []byte(fmt.Sprintf("value%v", i)), uint64(i))
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("failed to read db: %v", err)
+	}
+	// Chop off the end of the last record, as if we crashed while appending it.
+	// This is not a corruption, so the rest of the records must be loaded w/o errors.
+	if err := osutil.WriteFile(fn, data[:len(data)-5]); err != nil {
+		t.Fatalf("failed to write db: %v", err)
+	}
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db with truncated tail: %v", err)
+	}
+	if len(db.Records) != 9 {
+		t.Fatalf("wrong record count: %v", len(db.Records))
+	}
+}
+
+func TestCompactBackground(t *testing.T) {
+	fn := tempFile(t)
+	defer os.Remove(fn)
+	db, err := Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	want := make(map[string]Record)
+	compactions := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint(i % 10)
+		val := []byte(fmt.Sprintf("value%v", i))
+		db.Save(key, val, uint64(i))
+		want[key] = Record{val, uint64(i)}
+		if err := db.Flush(); err != nil {
+			t.Fatalf("failed to flush db: %v", err)
+		}
+		db.mu.Lock()
+		if db.compactDone != nil {
+			compactions++
+		}
+		db.mu.Unlock()
+	}
+	if compactions == 0 {
+		t.Fatalf("background compaction was not started")
+	}
+	db.waitCompaction()
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if !reflect.DeepEqual(db.Records, want) {
+		t.Fatalf("bad db after reopen: %v, want: %v", db.Records, want)
+	}
+}
+
+func TestOpenOldFormat(t *testing.T) {
+	fn := tempFile(t)
+	defer os.Remove(fn)
+	// Version 2 database with a single record ("key", "", 42) and a deleted record "del".
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, dbMagic)
+	binary.Write(buf, binary.LittleEndian, uint32(2))
+	binary.Write(buf, binary.LittleEndian, uint64(7))
+	for _, rec := range []struct {
+		key string
+		seq uint64
+	}{{"key", 42}, {"del", 1}, {"del", seqDeleted}} {
+		binary.Write(buf, binary.LittleEndian, recMagic)
+		binary.Write(buf, binary.LittleEndian, uint32(len(rec.key)))
+		buf.WriteString(rec.key)
+		binary.Write(buf, binary.LittleEndian, rec.seq)
+		if rec.seq != seqDeleted {
+			binary.Write(buf, binary.LittleEndian, uint32(0))
+		}
+	}
+	if err := osutil.WriteFile(fn, buf.Bytes()); err != nil {
+		t.Fatalf("failed to write db: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		// The second iteration opens the database converted to the new format.
+		db, err := Open(fn, false)
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		want := map[string]Record{"key": {Seq: 42}}
+		if db.Version != 7 || !reflect.DeepEqual(db.Records, want) {
+			t.Fatalf("bad db: version %v, records %+v", db.Version, db.Records)
+		}
+	}
+}
+
+func TestBlobs(t *testing.T) {
+	fn := tempFile(t)
+	defer os.Remove(fn)
+	db, err := Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	rnd := rand.New(rand.NewSource(0))
+	blob := make([]byte, 100<<10)
+	rnd.Read(blob)
+	blobStr := fmt.Sprintf("%x", blob)
+	want := make(map[string]Record)
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprint(i)
+		val := []byte(fmt.Sprintf("foo$%v(\"%v\", 0x%x, \"%v\")", i, blobStr, i, "small"))
+		db.Save(key, val, uint64(i))
+		want[key] = Record{val, uint64(i)}
+	}
+	if count, size, refSize := db.BlobStats(); count != 1 || size != len(blobStr) || refSize != 10*size {
+		t.Fatalf("bad blob stats: %v/%v/%v", count, size, refSize)
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	// The blob must be stored only once.
+	if st, err := os.Stat(fn); err != nil || st.Size() > int64(2*len(blob)) {
+		t.Fatalf("db file is too large: %v", st.Size())
+	}
+	for i := 0; i < 2; i++ {
+		db, err = Open(fn, false)
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		if !reflect.DeepEqual(db.Records, want) {
+			t.Fatalf("bad db after reopen")
+		}
+	}
+	for i := 0; i < 9; i++ {
+		db.Delete(fmt.Sprint(i))
+	}
+	if count, _, _ := db.BlobStats(); count != 1 {
+		t.Fatalf("blob is removed while still referenced")
+	}
+	db.Save("9", []byte("bar()"), 9)
+	if count, _, _ := db.BlobStats(); count != 0 {
+		t.Fatalf("unreferenced blob is not removed")
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if want := map[string]Record{"9": {[]byte("bar()"), 9}}; !reflect.DeepEqual(db.Records, want) {
+		t.Fatalf("bad db after reopen: %q", db.Records)
+	}
+	if st, err := os.Stat(fn); err != nil || st.Size() > 1<<10 {
+		t.Fatalf("unreferenced blob is not compacted: %v", st.Size())
+	}
+}
+
 func tempFile(t *testing.T) string {
 	fn, err := osutil.TempFile("syzkaller.test.db")
 	if err != nil {
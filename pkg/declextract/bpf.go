@@ -0,0 +1,191 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BPFInterface describes the kernel BPF interface as extracted from BTF.
+type BPFInterface struct {
+	Helpers   []BTFEnumValue
+	MapTypes  []BTFEnumValue
+	ProgTypes []BTFEnumValue
+	Kfuncs    []*Kfunc
+}
+
+// Kfunc is a kernel function callable from BPF programs.
+type Kfunc struct {
+	Name string
+	// BTF ID of the FUNC type, this is what BPF_PSEUDO_KFUNC_CALL instructions refer to.
+	// Note: the IDs are specific to the particular kernel build.
+	ID   int
+	Args []KfuncArg
+	Ret  string
+}
+
+type KfuncArg struct {
+	Name string
+	Type string
+}
+
+// ExtractBPF extracts BPF helpers, map/prog types and kfuncs from kernel BTF.
+// Kfuncs are identified by the "bpf_kfunc" decl tag (added by __bpf_kfunc annotation).
+// Per-prog-type kfunc restrictions are registered at runtime and are not present in BTF.
+func ExtractBPF(btf *BTF) (*BPFInterface, error) {
+	iface := &BPFInterface{}
+	var err error
+	if iface.Helpers, err = bpfEnum(btf, "bpf_func_id", "BPF_FUNC_", "BPF_FUNC_unspec"); err != nil {
+		return nil, err
+	}
+	if iface.MapTypes, err = bpfEnum(btf, "bpf_map_type", "BPF_MAP_TYPE_", "BPF_MAP_TYPE_UNSPEC"); err != nil {
+		return nil, err
+	}
+	if iface.ProgTypes, err = bpfEnum(btf, "bpf_prog_type", "BPF_PROG_TYPE_", "BPF_PROG_TYPE_UNSPEC"); err != nil {
+		return nil, err
+	}
+	kfuncs := make(map[int]bool)
+	for _, typ := range btf.Types {
+		if typ.Kind != BTFDeclTag || typ.Name != "bpf_kfunc" || typ.Component != -1 {
+			continue
+		}
+		if typ.Type <= 0 || typ.Type >= len(btf.Types) || btf.Types[typ.Type].Kind != BTFFunc {
+			continue
+		}
+		kfuncs[typ.Type] = true
+	}
+	for id := range kfuncs {
+		fn := btf.Types[id]
+		proto := btf.Resolve(fn.Type)
+		if proto.Kind != BTFFuncProto {
+			return nil, fmt.Errorf("kfunc %v has non-prototype type %v", fn.Name, proto.Kind)
+		}
+		kfunc := &Kfunc{
+			Name: fn.Name,
+			ID:   id,
+			Ret:  btf.syzType(proto.Type),
+		}
+		for i, param := range proto.Members {
+			name := param.Name
+			if name == "" {
+				name = fmt.Sprintf("arg%v", i)
+			}
+			kfunc.Args = append(kfunc.Args, KfuncArg{Name: name, Type: btf.syzType(param.Type)})
+		}
+		iface.Kfuncs = append(iface.Kfuncs, kfunc)
+	}
+	sort.Slice(iface.Kfuncs, func(i, j int) bool {
+		return iface.Kfuncs[i].Name < iface.Kfuncs[j].Name
+	})
+	return iface, nil
+}
+
+func bpfEnum(btf *BTF, name, prefix, skip string) ([]BTFEnumValue, error) {
+	typ := btf.FindEnum(name)
+	if typ == nil {
+		return nil, fmt.Errorf("no enum %v in BTF", name)
+	}
+	var res []BTFEnumValue
+	for _, val := range typ.Values {
+		// Skip the zero value and the __MAX_BPF_*/__BPF_FUNC_MAX_ID sentinels.
+		if val.Name == skip || !strings.HasPrefix(val.Name, prefix) {
+			continue
+		}
+		res = append(res, val)
+	}
+	return res, nil
+}
+
+// syzType returns an approximate syzlang type for the BTF type.
+func (btf *BTF) syzType(id int) string {
+	typ := btf.Resolve(id)
+	switch typ.Kind {
+	case BTFUnknown:
+		return "void"
+	case BTFInt, BTFEnum, BTFEnum64:
+		switch typ.Size {
+		case 1, 2, 4, 8:
+			return fmt.Sprintf("int%v", typ.Size*8)
+		}
+		return "intptr"
+	case BTFPtr:
+		elem := btf.Resolve(typ.Type)
+		switch elem.Kind {
+		case BTFStruct, BTFUnion, BTFFwd:
+			if elem.Name != "" {
+				return fmt.Sprintf("ptr[inout, %v]", elem.Name)
+			}
+		case BTFFuncProto:
+			return "intptr"
+		}
+		return "ptr[inout, array[int8]]"
+	case BTFStruct, BTFUnion:
+		return typ.Name
+	case BTFArray:
+		return fmt.Sprintf("array[%v, %v]", btf.syzType(typ.Type), typ.Len)
+	}
+	return "intptr"
+}
+
+// SerializeBPF generates syzlang descriptions for the extracted interface.
+// The flags can be referenced from the bpf$ descriptions; kfunc signatures are emitted as comments
+// since kfuncs are called via BPF instructions rather than syscalls.
+func SerializeBPF(iface *BPFInterface) []byte {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Code generated by syz-btfextract. DO NOT EDIT.\n\n")
+	serializeFlags(buf, "btf_bpf_helpers", iface.Helpers)
+	serializeFlags(buf, "btf_bpf_map_types", iface.MapTypes)
+	serializeFlags(buf, "btf_bpf_prog_types", iface.ProgTypes)
+	if len(iface.Kfuncs) == 0 {
+		return buf.Bytes()
+	}
+	fmt.Fprintf(buf, "\n# BTF IDs are specific to the kernel build the descriptions were extracted from.\n")
+	var ids []string
+	for _, kfunc := range iface.Kfuncs {
+		var args []string
+		for _, arg := range kfunc.Args {
+			args = append(args, arg.Name+" "+arg.Type)
+		}
+		fmt.Fprintf(buf, "# kfunc %v(%v) %v (btf id %v)\n", kfunc.Name, strings.Join(args, ", "), kfunc.Ret, kfunc.ID)
+		ids = append(ids, fmt.Sprint(kfunc.ID))
+	}
+	fmt.Fprintf(buf, "btf_bpf_kfunc_ids = %v\n", strings.Join(ids, ", "))
+	return buf.Bytes()
+}
+
+func serializeFlags(buf *bytes.Buffer, name string, vals []BTFEnumValue) {
+	if len(vals) == 0 {
+		return
+	}
+	var names []string
+	for _, val := range vals {
+		names = append(names, val.Name)
+	}
+	fmt.Fprintf(buf, "%v = %v\n", name, strings.Join(names, ", "))
+}
+
+// SerializeBPFConsts generates a .const file with values of all enum values used in the descriptions.
+func SerializeBPFConsts(iface *BPFInterface, arch string) []byte {
+	consts := make(map[string]int64)
+	for _, vals := range [][]BTFEnumValue{iface.Helpers, iface.MapTypes, iface.ProgTypes} {
+		for _, val := range vals {
+			consts[val.Name] = val.Val
+		}
+	}
+	var names []string
+	for name := range consts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Code generated by syz-btfextract. DO NOT EDIT.\n")
+	fmt.Fprintf(buf, "arches = %v\n", arch)
+	for _, name := range names {
+		fmt.Fprintf(buf, "%v = %v\n", name, consts[name])
+	}
+	return buf.Bytes()
+}
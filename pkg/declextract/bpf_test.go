@@ -0,0 +1,127 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// btfBuilder produces raw little-endian BTF blobs for tests.
+type btfBuilder struct {
+	types   []byte
+	strings []byte
+	nextID  int
+}
+
+func newBTFBuilder() *btfBuilder {
+	return &btfBuilder{strings: []byte{0}, nextID: 1}
+}
+
+func (b *btfBuilder) str(s string) uint32 {
+	if s == "" {
+		return 0
+	}
+	off := uint32(len(b.strings))
+	b.strings = append(append(b.strings, s...), 0)
+	return off
+}
+
+func (b *btfBuilder) add(name string, kind BTFKind, vlen int, sizeOrType int, extra ...uint32) int {
+	b.types = binary.LittleEndian.AppendUint32(b.types, b.str(name))
+	b.types = binary.LittleEndian.AppendUint32(b.types, uint32(kind)<<24|uint32(vlen))
+	b.types = binary.LittleEndian.AppendUint32(b.types, uint32(sizeOrType))
+	for _, v := range extra {
+		b.types = binary.LittleEndian.AppendUint32(b.types, v)
+	}
+	b.nextID++
+	return b.nextID - 1
+}
+
+func (b *btfBuilder) enum(name string, vals ...string) int {
+	var extra []uint32
+	for i, val := range vals {
+		extra = append(extra, b.str(val), uint32(i))
+	}
+	return b.add(name, BTFEnum, len(vals), 4, extra...)
+}
+
+func (b *btfBuilder) bytes() []byte {
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint16(hdr[0:], btfMagic)
+	hdr[2] = 1
+	binary.LittleEndian.PutUint32(hdr[4:], 24)
+	binary.LittleEndian.PutUint32(hdr[8:], 0)
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(len(b.types)))
+	binary.LittleEndian.PutUint32(hdr[16:], uint32(len(b.types)))
+	binary.LittleEndian.PutUint32(hdr[20:], uint32(len(b.strings)))
+	return append(append(hdr, b.types...), b.strings...)
+}
+
+func TestExtractBPF(t *testing.T) {
+	b := newBTFBuilder()
+	b.enum("bpf_func_id", "BPF_FUNC_unspec", "BPF_FUNC_map_lookup_elem", "BPF_FUNC_map_update_elem",
+		"__BPF_FUNC_MAX_ID")
+	b.enum("bpf_map_type", "BPF_MAP_TYPE_UNSPEC", "BPF_MAP_TYPE_HASH", "BPF_MAP_TYPE_ARRAY")
+	b.enum("bpf_prog_type", "BPF_PROG_TYPE_UNSPEC", "BPF_PROG_TYPE_SOCKET_FILTER", "__MAX_BPF_PROG_TYPE")
+	u32 := b.add("u32", BTFInt, 0, 4, 32)
+	u64 := b.add("u64", BTFInt, 0, 8, 64)
+	cpumask := b.add("bpf_cpumask", BTFStruct, 1, 8, b.str("cpumask"), uint32(u64), 0)
+	ptr := b.add("", BTFPtr, 0, cpumask)
+	constU32 := b.add("", BTFConst, 0, u32)
+	proto := b.add("", BTFFuncProto, 2, ptr, b.str("cpu"), uint32(constU32), b.str(""), uint32(ptr))
+	fn := b.add("bpf_cpumask_acquire", BTFFunc, 1, proto)
+	b.add("bpf_kfunc", BTFDeclTag, 0, fn, 0xffffffff)
+	// Not a kfunc (no decl tag).
+	b.add("bpf_cpumask_internal", BTFFunc, 1, proto)
+	// Unrelated tag on a parameter.
+	b.add("bpf_kfunc", BTFDeclTag, 0, fn, 0)
+
+	btf, err := ParseBTF(b.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, BTFStruct, btf.Types[cpumask].Kind)
+	assert.Equal(t, "bpf_cpumask", btf.Types[cpumask].Name)
+	assert.Equal(t, []BTFMember{{Name: "cpumask", Type: u64}}, btf.Types[cpumask].Members)
+	assert.Equal(t, u32, btf.Resolve(constU32).ID)
+
+	iface, err := ExtractBPF(btf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `# Code generated by syz-btfextract. DO NOT EDIT.
+
+btf_bpf_helpers = BPF_FUNC_map_lookup_elem, BPF_FUNC_map_update_elem
+btf_bpf_map_types = BPF_MAP_TYPE_HASH, BPF_MAP_TYPE_ARRAY
+btf_bpf_prog_types = BPF_PROG_TYPE_SOCKET_FILTER
+
+# BTF IDs are specific to the kernel build the descriptions were extracted from.
+# kfunc bpf_cpumask_acquire(cpu int32, arg1 ptr[inout, bpf_cpumask]) ptr[inout, bpf_cpumask] (btf id 10)
+btf_bpf_kfunc_ids = 10
+`, string(SerializeBPF(iface)))
+	assert.Equal(t, `# Code generated by syz-btfextract. DO NOT EDIT.
+arches = amd64
+BPF_FUNC_map_lookup_elem = 1
+BPF_FUNC_map_update_elem = 2
+BPF_MAP_TYPE_ARRAY = 2
+BPF_MAP_TYPE_HASH = 1
+BPF_PROG_TYPE_SOCKET_FILTER = 1
+`, string(SerializeBPFConsts(iface, "amd64")))
+}
+
+func TestParseBTFErrors(t *testing.T) {
+	_, err := ParseBTF([]byte("short"))
+	assert.Error(t, err)
+	data := newBTFBuilder().bytes()
+	data[0] = 0
+	_, err = ParseBTF(data)
+	assert.Error(t, err)
+	b := newBTFBuilder()
+	b.add("bad", BTFKind(31), 0, 0)
+	_, err = ParseBTF(b.bytes())
+	assert.Error(t, err)
+}
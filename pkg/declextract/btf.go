@@ -0,0 +1,328 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package declextract extracts syscall descriptions from kernel build artifacts.
+package declextract
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// BTF is a parsed BPF Type Format blob (see Documentation/bpf/btf.rst in the kernel).
+type BTF struct {
+	// Types are indexed by BTF type ID, Types[0] is void.
+	Types []*BTFType
+}
+
+type BTFKind int
+
+const (
+	BTFUnknown BTFKind = iota
+	BTFInt
+	BTFPtr
+	BTFArray
+	BTFStruct
+	BTFUnion
+	BTFEnum
+	BTFFwd
+	BTFTypedef
+	BTFVolatile
+	BTFConst
+	BTFRestrict
+	BTFFunc
+	BTFFuncProto
+	BTFVar
+	BTFDatasec
+	BTFFloat
+	BTFDeclTag
+	BTFTypeTag
+	BTFEnum64
+)
+
+type BTFType struct {
+	ID   int
+	Kind BTFKind
+	Name string
+	// Size in bytes for int/struct/union/enum.
+	Size int
+	// Number of bits and bit offset encoded in int types
+	// (the latter is used by old-style bitfields).
+	IntBits   int
+	IntOffset int
+	// KindFlag is the kind_flag bit of the type info, for struct/union it means
+	// that member offsets encode bitfield sizes in the high 8 bits.
+	KindFlag bool
+	// Referenced type for ptr/typedef/modifiers/func/var/decl tag,
+	// return type for func proto, element type for array.
+	Type int
+	// Number of elements for array.
+	Len int
+	// Struct/union members, func proto params.
+	Members []BTFMember
+	// Enum values.
+	Values []BTFEnumValue
+	// Component index for decl tags (-1 if the tag applies to the type itself).
+	Component int
+}
+
+type BTFMember struct {
+	Name string
+	Type int
+	// Bit offset for struct/union members.
+	Offset int
+}
+
+type BTFEnumValue struct {
+	Name string
+	Val  int64
+}
+
+const btfMagic = 0xeb9f
+
+// LoadBTF loads BTF either from a raw BTF file (/sys/kernel/btf/vmlinux) or from .BTF section of an ELF file.
+func LoadBTF(file string) (*BTF, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte(elf.ELFMAG)) {
+		ef, err := elf.NewFile(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		sec := ef.Section(".BTF")
+		if sec == nil {
+			return nil, fmt.Errorf("%v does not have .BTF section", file)
+		}
+		if data, err = sec.Data(); err != nil {
+			return nil, err
+		}
+	}
+	return ParseBTF(data)
+}
+
+// ParseBTF parses raw BTF data.
+func ParseBTF(data []byte) (*BTF, error) {
+	if len(data) < 24 {
+		return nil, fmt.Errorf("BTF data is too short")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint16(data) != btfMagic {
+		order = binary.BigEndian
+		if order.Uint16(data) != btfMagic {
+			return nil, fmt.Errorf("bad BTF magic 0x%x", binary.LittleEndian.Uint16(data))
+		}
+	}
+	hdrLen := order.Uint32(data[4:])
+	typeOff, typeLen := order.Uint32(data[8:]), order.Uint32(data[12:])
+	strOff, strLen := order.Uint32(data[16:]), order.Uint32(data[20:])
+	end := uint64(len(data))
+	if uint64(hdrLen)+uint64(typeOff)+uint64(typeLen) > end || uint64(hdrLen)+uint64(strOff)+uint64(strLen) > end {
+		return nil, fmt.Errorf("BTF sections are out of bounds")
+	}
+	p := &btfParser{
+		order:   order,
+		types:   data[hdrLen+typeOff : hdrLen+typeOff+typeLen],
+		strings: data[hdrLen+strOff : hdrLen+strOff+strLen],
+	}
+	btf := &BTF{Types: []*BTFType{{Kind: BTFUnknown, Name: "void"}}}
+	for len(p.types) != 0 {
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, fmt.Errorf("type %v: %w", len(btf.Types), err)
+		}
+		typ.ID = len(btf.Types)
+		btf.Types = append(btf.Types, typ)
+	}
+	return btf, nil
+}
+
+type btfParser struct {
+	order   binary.ByteOrder
+	types   []byte
+	strings []byte
+}
+
+func (p *btfParser) read(n int) ([]byte, error) {
+	if len(p.types) < n {
+		return nil, fmt.Errorf("unexpected end of type data")
+	}
+	res := p.types[:n]
+	p.types = p.types[n:]
+	return res, nil
+}
+
+func (p *btfParser) str(off uint32) (string, error) {
+	if uint64(off) >= uint64(len(p.strings)) {
+		return "", fmt.Errorf("string offset %v is out of bounds", off)
+	}
+	s := p.strings[off:]
+	if end := bytes.IndexByte(s, 0); end != -1 {
+		s = s[:end]
+	}
+	return string(s), nil
+}
+
+func (p *btfParser) parseType() (*BTFType, error) {
+	hdr, err := p.read(12)
+	if err != nil {
+		return nil, err
+	}
+	info := p.order.Uint32(hdr[4:])
+	sizeOrType := int(p.order.Uint32(hdr[8:]))
+	vlen := int(info & 0xffff)
+	typ := &BTFType{
+		Kind:      BTFKind(info >> 24 & 0x1f),
+		KindFlag:  info>>31 != 0,
+		Component: -1,
+	}
+	if typ.Name, err = p.str(p.order.Uint32(hdr)); err != nil {
+		return nil, err
+	}
+	switch typ.Kind {
+	case BTFInt:
+		typ.Size = sizeOrType
+		var enc []byte
+		if enc, err = p.read(4); err == nil {
+			val := p.order.Uint32(enc)
+			typ.IntBits = int(val & 0xff)
+			typ.IntOffset = int(val >> 16 & 0xff)
+		}
+	case BTFPtr, BTFTypedef, BTFVolatile, BTFConst, BTFRestrict, BTFFunc, BTFTypeTag:
+		typ.Type = sizeOrType
+	case BTFFwd:
+	case BTFFloat:
+		typ.Size = sizeOrType
+	case BTFArray:
+		var arr []byte
+		if arr, err = p.read(12); err == nil {
+			typ.Type = int(p.order.Uint32(arr))
+			typ.Len = int(p.order.Uint32(arr[8:]))
+		}
+	case BTFStruct, BTFUnion:
+		typ.Size = sizeOrType
+		err = p.parseMembers(typ, vlen, true)
+	case BTFFuncProto:
+		typ.Type = sizeOrType
+		err = p.parseMembers(typ, vlen, false)
+	case BTFEnum, BTFEnum64:
+		typ.Size = sizeOrType
+		err = p.parseEnum(typ, vlen, typ.KindFlag)
+	case BTFVar:
+		typ.Type = sizeOrType
+		_, err = p.read(4)
+	case BTFDatasec:
+		typ.Size = sizeOrType
+		_, err = p.read(12 * vlen)
+	case BTFDeclTag:
+		typ.Type = sizeOrType
+		var comp []byte
+		if comp, err = p.read(4); err == nil {
+			typ.Component = int(int32(p.order.Uint32(comp)))
+		}
+	default:
+		return nil, fmt.Errorf("unknown BTF kind %v", typ.Kind)
+	}
+	return typ, err
+}
+
+func (p *btfParser) parseMembers(typ *BTFType, vlen int, hasOffset bool) error {
+	size := 8
+	if hasOffset {
+		size = 12
+	}
+	for i := 0; i < vlen; i++ {
+		data, err := p.read(size)
+		if err != nil {
+			return err
+		}
+		m := BTFMember{Type: int(p.order.Uint32(data[4:]))}
+		if m.Name, err = p.str(p.order.Uint32(data)); err != nil {
+			return err
+		}
+		if hasOffset {
+			m.Offset = int(p.order.Uint32(data[8:]))
+		}
+		typ.Members = append(typ.Members, m)
+	}
+	return nil
+}
+
+func (p *btfParser) parseEnum(typ *BTFType, vlen int, signed bool) error {
+	size := 8
+	if typ.Kind == BTFEnum64 {
+		size = 12
+	}
+	for i := 0; i < vlen; i++ {
+		data, err := p.read(size)
+		if err != nil {
+			return err
+		}
+		v := BTFEnumValue{}
+		if v.Name, err = p.str(p.order.Uint32(data)); err != nil {
+			return err
+		}
+		if typ.Kind == BTFEnum64 {
+			v.Val = int64(uint64(p.order.Uint32(data[8:]))<<32 | uint64(p.order.Uint32(data[4:])))
+		} else if signed {
+			v.Val = int64(int32(p.order.Uint32(data[4:])))
+		} else {
+			v.Val = int64(p.order.Uint32(data[4:]))
+		}
+		typ.Values = append(typ.Values, v)
+	}
+	return nil
+}
+
+// Resolve skips typedefs and type modifiers.
+func (btf *BTF) Resolve(id int) *BTFType {
+	for id > 0 && id < len(btf.Types) {
+		typ := btf.Types[id]
+		switch typ.Kind {
+		case BTFTypedef, BTFVolatile, BTFConst, BTFRestrict, BTFTypeTag:
+			id = typ.Type
+		default:
+			return typ
+		}
+	}
+	return btf.Types[0]
+}
+
+// FindEnum returns the named enum type, or nil.
+func (btf *BTF) FindEnum(name string) *BTFType {
+	for _, typ := range btf.Types {
+		if (typ.Kind == BTFEnum || typ.Kind == BTFEnum64) && typ.Name == name {
+			return typ
+		}
+	}
+	return nil
+}
+
+// FindStruct returns the named struct type, or nil.
+func (btf *BTF) FindStruct(name string) *BTFType {
+	for _, typ := range btf.Types {
+		if typ.Kind == BTFStruct && typ.Name == name {
+			return typ
+		}
+	}
+	return nil
+}
+
+// Size returns size of the type in bytes, or 0 if it's unknown.
+// BTF does not record pointer size, pointers are assumed to be 64-bit.
+func (btf *BTF) Size(id int) int {
+	typ := btf.Resolve(id)
+	switch typ.Kind {
+	case BTFInt, BTFEnum, BTFEnum64, BTFStruct, BTFUnion, BTFFloat:
+		return typ.Size
+	case BTFPtr:
+		return 8
+	case BTFArray:
+		return btf.Size(typ.Type) * typ.Len
+	}
+	return 0
+}
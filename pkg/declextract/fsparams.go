@@ -0,0 +1,339 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FileSystem is a file system type registered with a fs_parameter_spec table.
+type FileSystem struct {
+	// Name is the file system type name passed to mount/fsopen, e.g. ext4.
+	Name string
+	// File is the source file that defines the file_system_type.
+	File string
+	// Spec is the name of the fs_parameter_spec table.
+	Spec   string
+	Params []*FsParam
+}
+
+// FsParam is one entry of a fs_parameter_spec table.
+type FsParam struct {
+	Name string
+	Kind FsParamKind
+	// Negatable flags (fsparam_flag_no) also accept "no" + Name.
+	Negatable bool
+	// Values are names from the constant_table for enum params.
+	Values []string
+}
+
+type FsParamKind int
+
+const (
+	FsParamFlag FsParamKind = iota
+	FsParamBool
+	FsParamU32
+	FsParamU32Oct
+	FsParamU32Hex
+	FsParamS32
+	FsParamU64
+	FsParamEnum
+	FsParamString
+	FsParamBlob
+	FsParamFd
+	FsParamUID
+	FsParamGID
+	FsParamPath
+)
+
+var (
+	fsParamSpecRe   = regexp.MustCompile(`(?s)\bstruct\s+fs_parameter_spec\s+(\w+)\s*\[\s*\w*\s*\]\s*=\s*\{(.*?)\n\s*\};`)
+	fsParamRe       = regexp.MustCompile(`\b(fsparam_\w+|__fsparam)\s*\(([^;{}]*?)\)\s*,`)
+	constTableRe    = regexp.MustCompile(`(?s)\bstruct\s+constant_table\s+(\w+)\s*\[\s*\w*\s*\]\s*=\s*\{(.*?)\n\s*\};`)
+	constTableEntRe = regexp.MustCompile(`\{\s*"([^"]+)"\s*,`)
+	fsTypeRe        = regexp.MustCompile(`(?s)\bstruct\s+file_system_type\s+(\w+)\s*=\s*\{(.*?)\};`)
+)
+
+var fsParamKinds = map[string]FsParamKind{
+	"flag":           FsParamFlag,
+	"flag_no":        FsParamFlag,
+	"bool":           FsParamBool,
+	"u32":            FsParamU32,
+	"u32oct":         FsParamU32Oct,
+	"u32hex":         FsParamU32Hex,
+	"s32":            FsParamS32,
+	"u64":            FsParamU64,
+	"enum":           FsParamEnum,
+	"string":         FsParamString,
+	"string_empty":   FsParamString,
+	"file_or_string": FsParamString,
+	"blob":           FsParamBlob,
+	"fd":             FsParamFd,
+	"uid":            FsParamUID,
+	"gid":            FsParamGID,
+	"bdev":           FsParamPath,
+	"path":           FsParamPath,
+}
+
+// ExtractFsParams finds file_system_type definitions in the kernel sources and
+// parses their fs_parameter_spec tables (the .parameters field).
+// Files maps source file names to contents. Like ExtractSockets, parsing is regexp-based:
+// tables are matched by name (in the same file first), and params declared with
+// unknown macros are skipped.
+func ExtractFsParams(files map[string][]byte) []*FileSystem {
+	type table struct {
+		file string
+		body []byte
+	}
+	specs := make(map[string][]table)
+	consts := make(map[string][]table)
+	for _, file := range sortedFiles(files) {
+		data := files[file]
+		for _, match := range fsParamSpecRe.FindAllSubmatch(data, -1) {
+			specs[string(match[1])] = append(specs[string(match[1])], table{file, match[2]})
+		}
+		for _, match := range constTableRe.FindAllSubmatch(data, -1) {
+			consts[string(match[1])] = append(consts[string(match[1])], table{file, match[2]})
+		}
+	}
+	// lookup prefers the table from the same file, since tables are usually static.
+	lookup := func(tables map[string][]table, name, file string) []byte {
+		for _, t := range tables[name] {
+			if t.file == file {
+				return t.body
+			}
+		}
+		if len(tables[name]) == 1 {
+			return tables[name][0].body
+		}
+		return nil
+	}
+	fss := make(map[string]*FileSystem)
+	for _, file := range sortedFiles(files) {
+		for _, match := range fsTypeRe.FindAllSubmatch(files[file], -1) {
+			fields := parseInitializer(match[2])
+			name := strings.Trim(fields["name"], `"`)
+			spec := strings.TrimPrefix(fields["parameters"], "&")
+			if name == "" || strings.ContainsAny(name, `" `) || spec == "" || fss[name] != nil {
+				continue
+			}
+			body := lookup(specs, spec, file)
+			if body == nil {
+				continue
+			}
+			fs := &FileSystem{
+				Name: name,
+				File: file,
+				Spec: spec,
+			}
+			for _, param := range parseFsParams(body) {
+				if param.Kind == FsParamEnum {
+					param.Values = parseConstTable(lookup(consts, param.Values[0], file))
+					if len(param.Values) == 0 {
+						param.Kind = FsParamString
+					}
+				}
+				fs.Params = append(fs.Params, param)
+			}
+			if len(fs.Params) != 0 {
+				fss[name] = fs
+			}
+		}
+	}
+	var res []*FileSystem
+	for _, fs := range fss {
+		res = append(res, fs)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// parseFsParams parses entries of a fs_parameter_spec table.
+// For enum params Values contains the name of the constant_table.
+func parseFsParams(body []byte) []*FsParam {
+	var params []*FsParam
+	for _, match := range fsParamRe.FindAllSubmatch(body, -1) {
+		macro := string(match[1])
+		var args []string
+		for _, arg := range strings.Split(string(match[2]), ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+		param := new(FsParam)
+		var kind string
+		if macro == "__fsparam" {
+			// __fsparam(fs_param_is_TYPE, "name", opt, flags, data)
+			if len(args) < 5 || !strings.HasPrefix(args[0], "fs_param_is_") {
+				continue
+			}
+			kind = strings.TrimPrefix(args[0], "fs_param_is_")
+			args = append(args[1:3], args[4])
+			param.Negatable = bytes.Contains(match[2], []byte("fs_param_neg_with_no"))
+		} else {
+			kind = strings.TrimPrefix(macro, "fsparam_")
+			param.Negatable = kind == "flag_no"
+		}
+		k, ok := fsParamKinds[kind]
+		if !ok || len(args) < 2 || !strings.HasPrefix(args[0], `"`) {
+			continue
+		}
+		param.Name = strings.Trim(args[0], `"`)
+		param.Kind = k
+		if k == FsParamEnum {
+			if len(args) < 3 {
+				continue
+			}
+			param.Values = []string{args[2]}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+func parseConstTable(body []byte) []string {
+	var values []string
+	for _, match := range constTableEntRe.FindAllSubmatch(body, -1) {
+		values = append(values, string(match[1]))
+	}
+	return values
+}
+
+var fsIdentRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func fsIdent(name string) string {
+	return fsIdentRe.ReplaceAllString(name, "_")
+}
+
+// fsParamValueType returns syzlang type for the param value (in the mount options string).
+func fsParamValueType(param *FsParam, enums string) string {
+	switch param.Kind {
+	case FsParamBool:
+		return "stringnoz[fs_auto_bool_values]"
+	case FsParamU32, FsParamS32:
+		return "fmt[dec, int32]"
+	case FsParamU64:
+		return "fmt[dec, int64]"
+	case FsParamU32Oct:
+		return "fmt[oct, int32]"
+	case FsParamU32Hex:
+		return "fmt[hex, int32]"
+	case FsParamUID:
+		return "fmt[dec, uid]"
+	case FsParamGID:
+		return "fmt[dec, gid]"
+	case FsParamFd:
+		return "fmt[dec, fd]"
+	case FsParamEnum:
+		return "stringnoz[" + enums + "]"
+	case FsParamPath:
+		return "stringnoz[filename]"
+	}
+	return "stringnoz"
+}
+
+// SerializeFsParams generates mount/fsopen/fsconfig descriptions that enumerate
+// the parameters of the extracted file systems.
+func SerializeFsParams(fss []*FileSystem) []byte {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Code generated by syz-fsparamextract. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "include <uapi/linux/mount.h>\n\n")
+	fmt.Fprintf(buf, "fs_auto_bool_values = \"0\", \"1\", \"false\", \"true\", \"no\", \"yes\", \"off\", \"on\"\n")
+	for _, fs := range fss {
+		serializeFsParams(buf, fs)
+	}
+	return buf.Bytes()
+}
+
+func serializeFsParams(buf *bytes.Buffer, fs *FileSystem) {
+	id := fsIdent(fs.Name)
+	prefix := "fs_auto_" + id
+	fmt.Fprintf(buf, "\n# %v parameters are defined in %v (%v).\n", fs.Name, fs.File, fs.Spec)
+	var flags, strs, fds []string
+	var options, enums []string
+	used := make(map[string]bool)
+	option := func(name, typ string) {
+		field := fsIdent(name)
+		for i := 1; used[field]; i++ {
+			field = fmt.Sprintf("%v_%v", fsIdent(name), i)
+		}
+		used[field] = true
+		options = append(options, fmt.Sprintf("\t%v\t%v", field, typ))
+	}
+	enumVals := make(map[string]bool)
+	for _, param := range fs.Params {
+		switch param.Kind {
+		case FsParamFlag:
+			option(param.Name, fmt.Sprintf("stringnoz[%q]", param.Name))
+			flags = append(flags, param.Name)
+			if param.Negatable {
+				option("no"+param.Name, fmt.Sprintf("stringnoz[%q]", "no"+param.Name))
+				flags = append(flags, "no"+param.Name)
+			}
+			continue
+		case FsParamFd:
+			fds = append(fds, param.Name)
+		default:
+			strs = append(strs, param.Name)
+		}
+		for _, val := range param.Values {
+			if !enumVals[val] {
+				enumVals[val] = true
+				enums = append(enums, val)
+			}
+		}
+		option(param.Name+"_eq", fmt.Sprintf("fs_opt[%q, %v]", param.Name, fsParamValueType(param, prefix+"_enums")))
+	}
+	fmt.Fprintf(buf, "resource fd_fscontext_auto_%v[fd_fscontext]\n", id)
+	fmt.Fprintf(buf, "mount$auto_%v(src ptr[in, filename], dst ptr[in, filename], type ptr[in, string[%q]], "+
+		"flags flags[mount_flags], opts ptr[in, fs_options[%v_options]])\n", id, fs.Name, prefix)
+	fmt.Fprintf(buf, "fsopen$auto_%v(type ptr[in, string[%q]], flags flags[fsopen_flags]) fd_fscontext_auto_%v\n",
+		id, fs.Name, id)
+	if len(flags) != 0 {
+		fmt.Fprintf(buf, "fsconfig$auto_%v_flag(fd fd_fscontext_auto_%v, cmd const[FSCONFIG_SET_FLAG], "+
+			"key ptr[in, string[%v_flag_params]], value const[0], aux const[0])\n", id, id, prefix)
+	}
+	if len(strs) != 0 {
+		fmt.Fprintf(buf, "fsconfig$auto_%v_string(fd fd_fscontext_auto_%v, cmd const[FSCONFIG_SET_STRING], "+
+			"key ptr[in, string[%v_string_params]], value ptr[in, %v_value], aux const[0])\n", id, id, prefix, prefix)
+	}
+	if len(fds) != 0 {
+		fmt.Fprintf(buf, "fsconfig$auto_%v_fd(fd fd_fscontext_auto_%v, cmd const[FSCONFIG_SET_FD], "+
+			"key ptr[in, string[%v_fd_params]], value const[0], aux fd)\n", id, id, prefix)
+	}
+	fmt.Fprintf(buf, "\n%v_options [\n%v\n] [varlen]\n", prefix, strings.Join(options, "\n"))
+	if len(strs) != 0 {
+		fmt.Fprintf(buf, "\n%v_value {\n\tval\t%v_values\n\tnull\tconst[0, int8]\n} [packed]\n", prefix, prefix)
+		fmt.Fprintf(buf, "\n%v_values [\n", prefix)
+		fmt.Fprintf(buf, "\tdec\tfmt[dec, int64]\n\thex\tfmt[hex, int64]\n\toct\tfmt[oct, int32]\n")
+		fmt.Fprintf(buf, "\tbool\tstringnoz[fs_auto_bool_values]\n")
+		if len(enums) != 0 {
+			fmt.Fprintf(buf, "\tenum\tstringnoz[%v_enums]\n", prefix)
+		}
+		fmt.Fprintf(buf, "\tpath\tstringnoz[filename]\n\tstr\tstringnoz\n] [varlen]\n")
+	}
+	fmt.Fprintf(buf, "\n")
+	for _, set := range []struct {
+		name string
+		vals []string
+	}{
+		{"flag_params", flags},
+		{"string_params", strs},
+		{"fd_params", fds},
+		{"enums", enums},
+	} {
+		if len(set.vals) == 0 {
+			continue
+		}
+		var quoted []string
+		for _, val := range set.vals {
+			quoted = append(quoted, fmt.Sprintf("%q", val))
+		}
+		fmt.Fprintf(buf, "%v_%v = %v\n", prefix, set.name, strings.Join(quoted, ", "))
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFsParams(t *testing.T) {
+	files := map[string][]byte{
+		"fs/foo/super.c": []byte(`
+static const struct constant_table foo_param_mode[] = {
+	{"fast",	FOO_MODE_FAST},
+	{"safe",	FOO_MODE_SAFE},
+	{}
+};
+
+static const struct fs_parameter_spec foo_fs_parameters[] = {
+	fsparam_flag	("debug",	Opt_debug),
+	fsparam_flag_no	("acl",		Opt_acl),
+	fsparam_u32	("commit",	Opt_commit),
+	fsparam_u32oct	("umask",	Opt_umask),
+	fsparam_enum	("mode",	Opt_mode, foo_param_mode),
+	fsparam_string	("journal-path",	Opt_journal_path),
+	fsparam_fd	("journal_fd",	Opt_journal_fd),
+	fsparam_uid	("uid",		Opt_uid),
+	__fsparam(fs_param_is_bool, "barrier", Opt_barrier, fs_param_neg_with_no, NULL),
+	fsparam_custom	("unknown",	Opt_unknown),
+	{}
+};
+
+static struct file_system_type foo_fs_type = {
+	.owner		= THIS_MODULE,
+	.name		= "foo",
+	.init_fs_context = foo_init_fs_context,
+	.parameters	= foo_fs_parameters,
+	.kill_sb	= kill_block_super,
+};
+
+static struct file_system_type foo2_fs_type = {
+	.name		= "foo2",
+	.parameters	= foo_fs_parameters,
+};
+`),
+		"fs/bar/inode.c": []byte(`
+static const struct fs_parameter_spec bar_param_specs[] = {
+	fsparam_flag("ro", Opt_ro),
+	{}
+};
+`),
+		"fs/bar/super.c": []byte(`
+static struct file_system_type bar_fs_type = {
+	.name = "bar",
+	.parameters = bar_param_specs,
+};
+static struct file_system_type noparams_fs_type = {
+	.name = "noparams",
+};
+`),
+	}
+	fss := ExtractFsParams(files)
+	if !assert.Len(t, fss, 3) {
+		return
+	}
+	assert.Equal(t, "bar", fss[0].Name)
+	assert.Equal(t, "fs/bar/super.c", fss[0].File)
+	assert.Equal(t, []*FsParam{{Name: "ro", Kind: FsParamFlag}}, fss[0].Params)
+	assert.Equal(t, "foo", fss[1].Name)
+	assert.Equal(t, "foo2", fss[2].Name)
+	assert.Equal(t, fss[1].Params, fss[2].Params)
+	assert.Equal(t, []*FsParam{
+		{Name: "debug", Kind: FsParamFlag},
+		{Name: "acl", Kind: FsParamFlag, Negatable: true},
+		{Name: "commit", Kind: FsParamU32},
+		{Name: "umask", Kind: FsParamU32Oct},
+		{Name: "mode", Kind: FsParamEnum, Values: []string{"fast", "safe"}},
+		{Name: "journal-path", Kind: FsParamString},
+		{Name: "journal_fd", Kind: FsParamFd},
+		{Name: "uid", Kind: FsParamUID},
+		{Name: "barrier", Kind: FsParamBool, Negatable: true},
+	}, fss[1].Params)
+
+	assert.Equal(t, `# Code generated by syz-fsparamextract. DO NOT EDIT.
+
+include <uapi/linux/mount.h>
+
+fs_auto_bool_values = "0", "1", "false", "true", "no", "yes", "off", "on"
+
+# bar parameters are defined in fs/bar/super.c (bar_param_specs).
+resource fd_fscontext_auto_bar[fd_fscontext]
+mount$auto_bar(src ptr[in, filename], dst ptr[in, filename], type ptr[in, string["bar"]], flags flags[mount_flags], opts ptr[in, fs_options[fs_auto_bar_options]])
+fsopen$auto_bar(type ptr[in, string["bar"]], flags flags[fsopen_flags]) fd_fscontext_auto_bar
+fsconfig$auto_bar_flag(fd fd_fscontext_auto_bar, cmd const[FSCONFIG_SET_FLAG], key ptr[in, string[fs_auto_bar_flag_params]], value const[0], aux const[0])
+
+fs_auto_bar_options [
+	ro	stringnoz["ro"]
+] [varlen]
+
+fs_auto_bar_flag_params = "ro"
+
+# foo parameters are defined in fs/foo/super.c (foo_fs_parameters).
+resource fd_fscontext_auto_foo[fd_fscontext]
+mount$auto_foo(src ptr[in, filename], dst ptr[in, filename], type ptr[in, string["foo"]], flags flags[mount_flags], opts ptr[in, fs_options[fs_auto_foo_options]])
+fsopen$auto_foo(type ptr[in, string["foo"]], flags flags[fsopen_flags]) fd_fscontext_auto_foo
+fsconfig$auto_foo_flag(fd fd_fscontext_auto_foo, cmd const[FSCONFIG_SET_FLAG], key ptr[in, string[fs_auto_foo_flag_params]], value const[0], aux const[0])
+fsconfig$auto_foo_string(fd fd_fscontext_auto_foo, cmd const[FSCONFIG_SET_STRING], key ptr[in, string[fs_auto_foo_string_params]], value ptr[in, fs_auto_foo_value], aux const[0])
+fsconfig$auto_foo_fd(fd fd_fscontext_auto_foo, cmd const[FSCONFIG_SET_FD], key ptr[in, string[fs_auto_foo_fd_params]], value const[0], aux fd)
+
+fs_auto_foo_options [
+	debug	stringnoz["debug"]
+	acl	stringnoz["acl"]
+	noacl	stringnoz["noacl"]
+	commit_eq	fs_opt["commit", fmt[dec, int32]]
+	umask_eq	fs_opt["umask", fmt[oct, int32]]
+	mode_eq	fs_opt["mode", stringnoz[fs_auto_foo_enums]]
+	journal_path_eq	fs_opt["journal-path", stringnoz]
+	journal_fd_eq	fs_opt["journal_fd", fmt[dec, fd]]
+	uid_eq	fs_opt["uid", fmt[dec, uid]]
+	barrier_eq	fs_opt["barrier", stringnoz[fs_auto_bool_values]]
+] [varlen]
+
+fs_auto_foo_value {
+	val	fs_auto_foo_values
+	null	const[0, int8]
+} [packed]
+
+fs_auto_foo_values [
+	dec	fmt[dec, int64]
+	hex	fmt[hex, int64]
+	oct	fmt[oct, int32]
+	bool	stringnoz[fs_auto_bool_values]
+	enum	stringnoz[fs_auto_foo_enums]
+	path	stringnoz[filename]
+	str	stringnoz
+] [varlen]
+
+fs_auto_foo_flag_params = "debug", "acl", "noacl"
+fs_auto_foo_string_params = "commit", "umask", "mode", "journal-path", "uid", "barrier"
+fs_auto_foo_fd_params = "journal_fd"
+fs_auto_foo_enums = "fast", "safe"
+`, string(SerializeFsParams(fss[:2])))
+}
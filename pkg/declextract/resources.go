@@ -0,0 +1,246 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+)
+
+// InferredResource is a resource created for an opaque handle (an fd or an index)
+// returned by some calls and passed to other calls.
+type InferredResource struct {
+	Name string
+	// Base is the type the calls used for the handle, e.g. fd, HANDLE or int32.
+	Base string
+	// Noun is the object name the calls are matched by, e.g. timerfd for timerfd_create.
+	Noun string
+	// Producers are calls that return the resource.
+	Producers []string
+	// Consumers are calls that accept the resource, in the call.arg form.
+	Consumers []string
+}
+
+// Verbs of calls that create objects, they are stripped from call names to get the object noun.
+var producerVerbs = map[string]bool{
+	"create":   true,
+	"open":     true,
+	"alloc":    true,
+	"new":      true,
+	"init":     true,
+	"register": true,
+	"add":      true,
+	"get":      true,
+}
+
+var (
+	camelWordRe = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
+	intTypeRe   = regexp.MustCompile(`^int(8|16|32|64|ptr)$`)
+)
+
+// InferResources finds calls that return a handle of one of the base resource types
+// (e.g. fd or HANDLE) or an integer (an index/id), and calls that accept the handle,
+// and wires them with a new named resource instead of the plain base type.
+// Calls are matched by the object noun from the producer name (timerfd_create, CreateEventA):
+// an argument of the same type is a consumer if its name refers to the noun (e.g. hEvent,
+// timerfd_fd, event_id), or, for base resources only, if it's the first such argument
+// of a call whose name contains the noun (e.g. timerfd_settime(ufd fd)).
+// Integer handles are wired only by argument names since plain integers are too common.
+// Resources without consumers are not created. desc is modified in place: resource
+// declarations are inserted before the first call, and the remaining integer return types
+// are dropped (syzlang calls can return only resources).
+func InferResources(desc *ast.Description, bases []string) []*InferredResource {
+	isBase := make(map[string]bool)
+	for _, base := range bases {
+		isBase[base] = true
+	}
+	declared := make(map[string]bool)
+	var calls []*ast.Call
+	for _, node := range desc.Nodes {
+		if call, ok := node.(*ast.Call); ok {
+			calls = append(calls, call)
+		} else if _, _, name := node.Info(); name != "" {
+			declared[name] = true
+		}
+	}
+	resources := make(map[string]*InferredResource)
+	var nouns []string
+	for _, call := range calls {
+		if call.Ret == nil || len(call.Ret.Args) != 0 {
+			continue
+		}
+		base := call.Ret.Ident
+		if !isBase[base] && !isIntType(base) {
+			continue
+		}
+		noun := callNoun(call.Name.Name)
+		if noun == "" {
+			continue
+		}
+		res := resources[noun]
+		if res == nil {
+			res = &InferredResource{
+				Name: resourceName(base, noun, isBase[base]),
+				Base: base,
+				Noun: noun,
+			}
+			if declared[res.Name] {
+				continue
+			}
+			resources[noun] = res
+			nouns = append(nouns, noun)
+		}
+		if res.Base == base {
+			res.Producers = append(res.Producers, call.Name.Name)
+		}
+	}
+	sort.Strings(nouns)
+	var ret []*InferredResource
+	for _, noun := range nouns {
+		res := resources[noun]
+		consumers := findConsumers(calls, res, isBase[res.Base])
+		if len(consumers) == 0 {
+			continue
+		}
+		for _, call := range calls {
+			for _, name := range res.Producers {
+				if call.Name.Name == name {
+					call.Ret.Ident = res.Name
+				}
+			}
+		}
+		for call, arg := range consumers {
+			arg.Type.Ident = res.Name
+			res.Consumers = append(res.Consumers, call.Name.Name+"."+arg.Name.Name)
+		}
+		sort.Strings(res.Consumers)
+		ret = append(ret, res)
+	}
+	for _, call := range calls {
+		if call.Ret != nil && isIntType(call.Ret.Ident) {
+			call.Ret = nil
+		}
+	}
+	insertResources(desc, ret)
+	return ret
+}
+
+func findConsumers(calls []*ast.Call, res *InferredResource, isBase bool) map[*ast.Call]*ast.Field {
+	argNames := map[string]bool{
+		res.Noun:            true,
+		"h" + res.Noun:      true,
+		res.Noun + "fd":     true,
+		res.Noun + "id":     true,
+		res.Noun + "idx":    true,
+		res.Noun + "index":  true,
+		res.Noun + "handle": true,
+	}
+	producers := make(map[string]bool)
+	for _, name := range res.Producers {
+		producers[name] = true
+	}
+	consumers := make(map[*ast.Call]*ast.Field)
+	for _, call := range calls {
+		var first *ast.Field
+		for _, arg := range call.Args {
+			typ := arg.Type
+			// Integer handles may be passed as integers of a different size.
+			if len(typ.Args) != 0 || typ.Ident != res.Base && (isBase || !isIntType(typ.Ident)) {
+				continue
+			}
+			if argNames[normalizeName(arg.Name.Name)] {
+				consumers[call] = arg
+				break
+			}
+			if first == nil {
+				first = arg
+			}
+		}
+		// Producer names contain the noun by construction.
+		if consumers[call] == nil && first != nil && isBase && !producers[call.Name.Name] &&
+			strings.Contains(normalizeName(call.Name.Name), res.Noun) {
+			consumers[call] = first
+		}
+	}
+	return consumers
+}
+
+// callNoun returns the object noun of a producer call, or "" if the name doesn't contain a producer verb.
+func callNoun(name string) string {
+	if i := strings.IndexByte(name, '$'); i != -1 {
+		name = name[:i]
+	}
+	var words []string
+	for _, part := range strings.Split(name, "_") {
+		words = append(words, camelWordRe.FindAllString(part, -1)...)
+	}
+	// Strip ANSI/wide/extended function suffixes (CreateEventA, CreateEventExW).
+	for len(words) > 1 {
+		last := words[len(words)-1]
+		if last != "A" && last != "W" && last != "Ex" && last != "ExA" && last != "ExW" {
+			break
+		}
+		words = words[:len(words)-1]
+	}
+	switch {
+	case len(words) > 1 && producerVerbs[strings.ToLower(words[0])]:
+		words = words[1:]
+	case len(words) > 1 && producerVerbs[strings.ToLower(words[len(words)-1])]:
+		words = words[:len(words)-1]
+	default:
+		return ""
+	}
+	return normalizeName(strings.Join(words, ""))
+}
+
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return -1
+	}, name)
+}
+
+func isIntType(typ string) bool {
+	return intTypeRe.MatchString(typ)
+}
+
+func resourceName(base, noun string, isBase bool) string {
+	if !isBase {
+		return "id_auto_" + noun
+	}
+	return strings.ToLower(base) + "_auto_" + noun
+}
+
+func insertResources(desc *ast.Description, resources []*InferredResource) {
+	if len(resources) == 0 {
+		return
+	}
+	var nodes []ast.Node
+	for _, res := range resources {
+		nodes = append(nodes,
+			&ast.Comment{Text: fmt.Sprintf(" %v is returned by %v.", res.Name, strings.Join(res.Producers, ", "))},
+			&ast.Resource{
+				Name: &ast.Ident{Name: res.Name},
+				Base: &ast.Type{Ident: res.Base},
+			})
+	}
+	nodes = append(nodes, &ast.NewLine{})
+	pos := len(desc.Nodes)
+	for i, node := range desc.Nodes {
+		if _, ok := node.(*ast.Call); ok {
+			pos = i
+			break
+		}
+	}
+	desc.Nodes = append(desc.Nodes[:pos], append(nodes, desc.Nodes[pos:]...)...)
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferResources(t *testing.T) {
+	desc := ast.Parse([]byte(`
+resource HANDLE[intptr]
+
+CreateEventA(lpEventAttributes ptr[inout, array[int8]], bManualReset int32, bInitialState int32, lpName ptr[inout, array[int8]]) HANDLE
+CreateEventExW(lpEventAttributes ptr[inout, array[int8]], lpName ptr[inout, array[int8]], dwFlags int32, dwDesiredAccess int32) HANDLE
+SetEvent(hEvent HANDLE)
+WaitForSingleObject(hHandle HANDLE, dwMilliseconds int32)
+timerfd_create(clockid int32, flags int32) fd
+timerfd_settime(ufd fd, flags int32, utmr ptr[in, array[int8]], otmr ptr[out, array[int8]])
+read(fd fd, buf ptr[out, array[int8]], count len[buf])
+inotify_init() fd
+slot_alloc(flags int32) int32
+slot_bind(fd fd, slot_id int64, flags int32)
+slot_release(fd fd, slot int32)
+GetTickCount() int32
+`), "test.txt", nil)
+	assert.NotNil(t, desc)
+	resources := InferResources(desc, []string{"fd", "HANDLE"})
+	assert.Equal(t, []*InferredResource{
+		{
+			Name:      "handle_auto_event",
+			Base:      "HANDLE",
+			Noun:      "event",
+			Producers: []string{"CreateEventA", "CreateEventExW"},
+			Consumers: []string{"SetEvent.hEvent"},
+		},
+		{
+			Name:      "id_auto_slot",
+			Base:      "int32",
+			Noun:      "slot",
+			Producers: []string{"slot_alloc"},
+			Consumers: []string{"slot_bind.slot_id", "slot_release.slot"},
+		},
+		{
+			Name:      "fd_auto_timerfd",
+			Base:      "fd",
+			Noun:      "timerfd",
+			Producers: []string{"timerfd_create"},
+			Consumers: []string{"timerfd_settime.ufd"},
+		},
+	}, resources)
+	assert.Equal(t, `
+resource HANDLE[intptr]
+
+# handle_auto_event is returned by CreateEventA, CreateEventExW.
+resource handle_auto_event[HANDLE]
+# id_auto_slot is returned by slot_alloc.
+resource id_auto_slot[int32]
+# fd_auto_timerfd is returned by timerfd_create.
+resource fd_auto_timerfd[fd]
+
+CreateEventA(lpEventAttributes ptr[inout, array[int8]], bManualReset int32, bInitialState int32, lpName ptr[inout, array[int8]]) handle_auto_event
+CreateEventExW(lpEventAttributes ptr[inout, array[int8]], lpName ptr[inout, array[int8]], dwFlags int32, dwDesiredAccess int32) handle_auto_event
+SetEvent(hEvent handle_auto_event)
+WaitForSingleObject(hHandle HANDLE, dwMilliseconds int32)
+timerfd_create(clockid int32, flags int32) fd_auto_timerfd
+timerfd_settime(ufd fd_auto_timerfd, flags int32, utmr ptr[in, array[int8]], otmr ptr[out, array[int8]])
+read(fd fd, buf ptr[out, array[int8]], count len[buf])
+inotify_init() fd
+slot_alloc(flags int32) id_auto_slot
+slot_bind(fd fd, slot_id id_auto_slot, flags int32)
+slot_release(fd fd, slot id_auto_slot)
+GetTickCount()
+`, string(ast.Format(desc)))
+}
+
+func TestCallNoun(t *testing.T) {
+	for name, noun := range map[string]string{
+		"CreateEventA":      "event",
+		"CreateEventExW":    "event",
+		"OpenProcess":       "process",
+		"timerfd_create":    "timerfd",
+		"openat$ptmx":       "",
+		"inotify_init1":     "",
+		"GetTickCount":      "tickcount",
+		"WaitForSingleObj":  "",
+		"create":            "",
+		"bpf_map_create":    "bpfmap",
+		"io_uring_register": "iouring",
+	} {
+		assert.Equal(t, noun, callNoun(name), name)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SocketFamily is an address family registered with sock_register.
+type SocketFamily struct {
+	// Family is the address family constant, e.g. AF_VSOCK.
+	Family string
+	// Name is the lower-case family name used in the generated names, e.g. vsock.
+	Name string
+	// File is the source file that registers the family.
+	File string
+	// Bind/Connect are set if any proto_ops of the family implement the operation.
+	Bind    bool
+	Connect bool
+	// Protocols are names of struct proto registered with proto_register in the same file.
+	Protocols []string
+	// Sockaddr is the address struct from BTF, nil if it's not found.
+	Sockaddr *BTFType
+}
+
+var (
+	sockRegisterRe  = regexp.MustCompile(`\bsock_register\(\s*&\s*(\w+)\s*\)`)
+	protoRegisterRe = regexp.MustCompile(`\bproto_register\(\s*&\s*(\w+)\s*,`)
+	protoFamilyRe   = regexp.MustCompile(`(?s)\bstruct\s+net_proto_family\s+(\w+)\s*=\s*\{(.*?)\};`)
+	protoOpsRe      = regexp.MustCompile(`(?s)\bstruct\s+proto_ops\s+(\w+)\s*=\s*\{(.*?)\};`)
+	protoRe         = regexp.MustCompile(`(?s)\bstruct\s+proto\s+(\w+)\s*=\s*\{(.*?)\};`)
+	initializerRe   = regexp.MustCompile(`\.(\w+)\s*=\s*([^,\n]+?)\s*(?:,|\n|$)`)
+)
+
+// ExtractSockets finds address families registered with sock_register in the kernel sources
+// and matches them with the proto_ops and proto_register calls for the same family.
+// Files maps source file names to contents. Sockaddr layouts are taken from btf
+// (struct sockaddr_NAME), btf may be nil.
+// Parsing is regexp-based and only understands designated initializers of static structs,
+// families registered in any other way are not detected.
+func ExtractSockets(files map[string][]byte, btf *BTF) []*SocketFamily {
+	type familyOps struct {
+		bind    bool
+		connect bool
+	}
+	ops := make(map[string]*familyOps)
+	families := make(map[string]*SocketFamily)
+	for _, file := range sortedFiles(files) {
+		data := files[file]
+		for _, match := range protoOpsRe.FindAllSubmatch(data, -1) {
+			fields := parseInitializer(match[2])
+			family := normalizeFamily(fields["family"])
+			if family == "" {
+				continue
+			}
+			if ops[family] == nil {
+				ops[family] = new(familyOps)
+			}
+			ops[family].bind = ops[family].bind || implemented(fields["bind"])
+			ops[family].connect = ops[family].connect || implemented(fields["connect"])
+		}
+		registered := make(map[string]bool)
+		for _, match := range sockRegisterRe.FindAllSubmatch(data, -1) {
+			registered[string(match[1])] = true
+		}
+		if len(registered) == 0 {
+			continue
+		}
+		protos := fileProtocols(data)
+		for _, match := range protoFamilyRe.FindAllSubmatch(data, -1) {
+			if !registered[string(match[1])] {
+				continue
+			}
+			family := normalizeFamily(parseInitializer(match[2])["family"])
+			if family == "" || families[family] != nil {
+				continue
+			}
+			families[family] = &SocketFamily{
+				Family:    family,
+				Name:      strings.ToLower(strings.TrimPrefix(family, "AF_")),
+				File:      file,
+				Protocols: protos,
+			}
+		}
+	}
+	var res []*SocketFamily
+	for _, fam := range families {
+		if op := ops[fam.Family]; op != nil {
+			fam.Bind, fam.Connect = op.bind, op.connect
+		}
+		if btf != nil {
+			fam.Sockaddr = btf.FindStruct("sockaddr_" + fam.Name)
+		}
+		res = append(res, fam)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Family < res[j].Family
+	})
+	return res
+}
+
+func sortedFiles(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fileProtocols(data []byte) []string {
+	registered := make(map[string]bool)
+	for _, match := range protoRegisterRe.FindAllSubmatch(data, -1) {
+		registered[string(match[1])] = true
+	}
+	var protos []string
+	for _, match := range protoRe.FindAllSubmatch(data, -1) {
+		if !registered[string(match[1])] {
+			continue
+		}
+		name := strings.Trim(parseInitializer(match[2])["name"], `"`)
+		if name != "" {
+			protos = append(protos, name)
+		}
+	}
+	return protos
+}
+
+func parseInitializer(body []byte) map[string]string {
+	fields := make(map[string]string)
+	for _, match := range initializerRe.FindAllSubmatch(body, -1) {
+		fields[string(match[1])] = string(match[2])
+	}
+	return fields
+}
+
+// normalizeFamily converts PF_FOO to AF_FOO (they have the same values), returns "" for anything else.
+func normalizeFamily(val string) string {
+	if strings.HasPrefix(val, "PF_") {
+		return "AF_" + strings.TrimPrefix(val, "PF_")
+	}
+	if strings.HasPrefix(val, "AF_") {
+		return val
+	}
+	return ""
+}
+
+func implemented(handler string) bool {
+	return handler != "" && !strings.HasPrefix(handler, "sock_no_")
+}
+
+// SerializeSockets generates syzlang descriptions for the extracted address families.
+func SerializeSockets(families []*SocketFamily, btf *BTF) []byte {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Code generated by syz-sockextract. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "include <linux/socket.h>\n")
+	for _, fam := range families {
+		sock := "sock_auto_" + fam.Name
+		addr := "sockaddr_generic"
+		if fam.Sockaddr != nil {
+			addr = "sockaddr_auto_" + fam.Name
+		}
+		fmt.Fprintf(buf, "\n# %v is registered in %v", fam.Family, fam.File)
+		if len(fam.Protocols) != 0 {
+			fmt.Fprintf(buf, ", protocols: %v", strings.Join(fam.Protocols, ", "))
+		}
+		fmt.Fprintf(buf, ".\n")
+		fmt.Fprintf(buf, "resource %v[sock]\n", sock)
+		fmt.Fprintf(buf, "socket$auto_%v(domain const[%v], type flags[socket_type], proto int32) %v\n",
+			fam.Name, fam.Family, sock)
+		if fam.Bind {
+			fmt.Fprintf(buf, "bind$auto_%v(fd %v, addr ptr[in, %v], addrlen len[addr])\n", fam.Name, sock, addr)
+		}
+		if fam.Connect {
+			fmt.Fprintf(buf, "connect$auto_%v(fd %v, addr ptr[in, %v], addrlen len[addr])\n", fam.Name, sock, addr)
+		}
+		if fam.Sockaddr != nil {
+			serializeSockaddr(buf, btf, addr, fam)
+		}
+	}
+	return buf.Bytes()
+}
+
+// serializeSockaddr emits the BTF struct as a packed struct with explicit padding,
+// so that the layout matches the kernel one regardless of syzlang alignment rules.
+func serializeSockaddr(buf *bytes.Buffer, btf *BTF, name string, fam *SocketFamily) {
+	fmt.Fprintf(buf, "\n%v {\n", name)
+	offset := 0
+	for i, m := range fam.Sockaddr.Members {
+		if m.Offset%8 != 0 || m.Offset/8 < offset {
+			continue // bitfields are not supported
+		}
+		if pad := m.Offset/8 - offset; pad != 0 {
+			fmt.Fprintf(buf, "\tpad%v\tarray[const[0, int8], %v]\n", i, pad)
+		}
+		field := m.Name
+		if field == "" {
+			field = fmt.Sprintf("field%v", i)
+		}
+		typ := sockaddrFieldType(btf, m.Type)
+		if i == 0 && strings.HasSuffix(m.Name, "family") {
+			typ = fmt.Sprintf("const[%v, %v]", fam.Family, typ)
+		}
+		fmt.Fprintf(buf, "\t%v\t%v\n", field, typ)
+		offset = m.Offset/8 + btf.Size(m.Type)
+	}
+	fmt.Fprintf(buf, "} [packed, size[%v]]\n", fam.Sockaddr.Size)
+}
+
+// sockaddrFieldType returns syzlang type for a sockaddr field.
+// Nested structs are emitted as opaque byte arrays since they are not described.
+func sockaddrFieldType(btf *BTF, id int) string {
+	typ := btf.Resolve(id)
+	switch typ.Kind {
+	case BTFInt, BTFEnum, BTFEnum64:
+		return btf.syzType(id)
+	case BTFArray:
+		return fmt.Sprintf("array[%v, %v]", sockaddrFieldType(btf, typ.Type), typ.Len)
+	}
+	return fmt.Sprintf("array[int8, %v]", btf.Size(id))
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractSockets(t *testing.T) {
+	files := map[string][]byte{
+		"net/foo/af_foo.c": []byte(`
+static const struct proto_ops foo_stream_ops = {
+	.family =	PF_FOO,
+	.owner =	THIS_MODULE,
+	.bind =		foo_bind,
+	.connect =	sock_no_connect,
+};
+
+static const struct proto_ops foo_dgram_ops = {
+	.family =	PF_FOO,
+	.connect =	foo_dgram_connect,
+};
+
+static struct proto foo_proto = {
+	.name		= "FOO",
+	.owner		= THIS_MODULE,
+	.obj_size	= sizeof(struct foo_sock),
+};
+
+static const struct net_proto_family foo_family_ops = {
+	.family	= PF_FOO,
+	.create	= foo_create,
+	.owner	= THIS_MODULE,
+};
+
+static int __init foo_init(void)
+{
+	int err = proto_register(&foo_proto, 1);
+	if (err)
+		return err;
+	return sock_register(&foo_family_ops);
+}
+`),
+		"net/bar/af_bar.c": []byte(`
+static const struct proto_ops bar_ops = {
+	.family = AF_BAR,
+	.bind = sock_no_bind,
+	.connect = sock_no_connect,
+};
+static const struct net_proto_family bar_family = {
+	.family = AF_BAR,
+	.create = bar_create,
+};
+static const struct net_proto_family unregistered_family = {
+	.family = AF_BAZ,
+	.create = baz_create,
+};
+module_init(...) { sock_register(&bar_family); }
+`),
+	}
+	b := newBTFBuilder()
+	u16 := b.add("u16", BTFInt, 0, 2, 16)
+	u32 := b.add("u32", BTFInt, 0, 4, 32)
+	u8 := b.add("u8", BTFInt, 0, 1, 8)
+	idx := b.add("", BTFInt, 0, 4, 32)
+	arr := b.add("", BTFArray, 0, 0, uint32(u8), uint32(idx), 3)
+	inner := b.add("foo_addr", BTFStruct, 1, 4, b.str("raw"), uint32(u32), 0)
+	b.add("sockaddr_foo", BTFStruct, 4, 16,
+		b.str("sfoo_family"), uint32(u16), 0,
+		b.str("sfoo_port"), uint32(u32), 32,
+		b.str("sfoo_addr"), uint32(inner), 64,
+		b.str("sfoo_zero"), uint32(arr), 96)
+	btf, err := ParseBTF(b.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	families := ExtractSockets(files, btf)
+	if !assert.Len(t, families, 2) {
+		return
+	}
+	assert.Equal(t, "AF_BAR", families[0].Family)
+	assert.False(t, families[0].Bind)
+	assert.False(t, families[0].Connect)
+	assert.Nil(t, families[0].Sockaddr)
+	assert.Equal(t, "AF_FOO", families[1].Family)
+	assert.Equal(t, "foo", families[1].Name)
+	assert.True(t, families[1].Bind)
+	assert.True(t, families[1].Connect)
+	assert.Equal(t, []string{"FOO"}, families[1].Protocols)
+
+	assert.Equal(t, `# Code generated by syz-sockextract. DO NOT EDIT.
+
+include <linux/socket.h>
+
+# AF_BAR is registered in net/bar/af_bar.c.
+resource sock_auto_bar[sock]
+socket$auto_bar(domain const[AF_BAR], type flags[socket_type], proto int32) sock_auto_bar
+
+# AF_FOO is registered in net/foo/af_foo.c, protocols: FOO.
+resource sock_auto_foo[sock]
+socket$auto_foo(domain const[AF_FOO], type flags[socket_type], proto int32) sock_auto_foo
+bind$auto_foo(fd sock_auto_foo, addr ptr[in, sockaddr_auto_foo], addrlen len[addr])
+connect$auto_foo(fd sock_auto_foo, addr ptr[in, sockaddr_auto_foo], addrlen len[addr])
+
+sockaddr_auto_foo {
+	sfoo_family	const[AF_FOO, int16]
+	pad1	array[const[0, int8], 2]
+	sfoo_port	int32
+	sfoo_addr	array[int8, 4]
+	sfoo_zero	array[int8, 3]
+} [packed, size[16]]
+`, string(SerializeSockets(families, btf)))
+}
@@ -0,0 +1,320 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ZirconSyscall is a Zircon system call extracted from the vDSO FIDL definitions.
+type ZirconSyscall struct {
+	// Name is the C name of the call, e.g. zx_channel_read.
+	Name string
+	// File is the FIDL file that defines the call.
+	File string
+	Args []ZirconArg
+}
+
+// ZirconArg is a syscall argument with the type in syzlang syntax.
+type ZirconArg struct {
+	Name string
+	Type string
+}
+
+var (
+	fidlCommentRe  = regexp.MustCompile(`//[^\n]*`)
+	fidlProtocolRe = regexp.MustCompile(`(?s)((?:@\w+(?:\([^)]*\))?\s*)*)(?:closed\s+|open\s+)?protocol\s+(\w+)\s*\{(.*?)\n\};`)
+	fidlMethodRe   = regexp.MustCompile(`(?s)((?:@\w+(?:\([^)]*\))?\s*)*)(?:strict\s+|flexible\s+)?(\w+)\(\s*` +
+		`(?:(?:resource\s+)?struct\s*\{(.*?)\})?\s*\)\s*` +
+		`(?:->\s*\(\s*(?:(?:resource\s+)?struct\s*\{(.*?)\})?\s*\))?\s*(?:error\s+\w+\s*)?;`)
+	fidlFieldRe = regexp.MustCompile(`(?s)^((?:@\w+(?:\([^)]*\))?\s*)*)(\w+)\s+(.+)$`)
+	fidlAttrRe  = regexp.MustCompile(`@(\w+)`)
+)
+
+// Zircon handle subtypes that have more specific resources in sys/fuchsia.
+var zirconHandles = map[string]string{
+	"BTI":       "zx_bti",
+	"CHANNEL":   "zx_chan",
+	"DEBUGLOG":  "zx_log",
+	"EVENT":     "zx_event",
+	"EVENTPAIR": "zx_eventpair",
+	"FIFO":      "zx_fifo",
+	"GUEST":     "zx_guest",
+	"INTERRUPT": "zx_interrupt",
+	"IOMMU":     "zx_iommu",
+	"JOB":       "zx_job",
+	"LOG":       "zx_log",
+	"MSI":       "zx_msi",
+	"PAGER":     "zx_pager",
+	"PMT":       "zx_pmt",
+	"PORT":      "zx_port",
+	"PROCESS":   "zx_process",
+	"PROFILE":   "zx_profile",
+	"RESOURCE":  "zx_resource",
+	"SOCKET":    "zx_socket",
+	"STREAM":    "zx_stream",
+	"THREAD":    "zx_thread",
+	"TIMER":     "zx_timer",
+	"VCPU":      "zx_vcpu",
+	"VMAR":      "zx_vmar",
+	"VMO":       "zx_vmo",
+}
+
+// Scalar FIDL types (both the current capitalized and the old lower-case zx library spelling).
+var zirconScalars = map[string]string{
+	"bool":      "int8",
+	"byte":      "int8",
+	"char":      "int8",
+	"int8":      "int8",
+	"uint8":     "int8",
+	"int16":     "int16",
+	"uint16":    "int16",
+	"int32":     "int32",
+	"uint32":    "int32",
+	"int64":     "int64",
+	"uint64":    "int64",
+	"usize":     "intptr",
+	"usize64":   "int64",
+	"uintptr":   "intptr",
+	"uintptr64": "int64",
+	"Futex":     "int32",
+	"futex":     "int32",
+	"Koid":      "int64",
+	"koid":      "int64",
+	"Off":       "int64",
+	"off":       "int64",
+	"Paddr":     "int64",
+	"paddr":     "int64",
+	"Rights":    "int32",
+	"rights":    "int32",
+	"Signals":   "int32",
+	"signals":   "int32",
+	"Status":    "int32",
+	"status":    "int32",
+	"Duration":  "int64",
+	"duration":  "int64",
+	"Ticks":     "int64",
+	"ticks":     "int64",
+	"Time":      "zx_time",
+	"time":      "zx_time",
+	"Vaddr":     "zx_vaddr",
+	"vaddr":     "zx_vaddr",
+}
+
+// ExtractZircon parses Zircon syscall definitions in the FIDL dialect used in zircon/vdso/*.fidl
+// (protocols with @transport("Syscall")). Files maps file names to contents.
+// Request struct members become arguments, response struct members become trailing out pointers,
+// vectors and strings are expanded into a pointer and a size like the C bindings do.
+// Calls marked as @internal or @testonly are skipped. Named FIDL types that are not known
+// are described as opaque integers/byte arrays.
+func ExtractZircon(files map[string][]byte) ([]*ZirconSyscall, error) {
+	var res []*ZirconSyscall
+	for _, file := range sortedFiles(files) {
+		data := fidlCommentRe.ReplaceAll(files[file], nil)
+		for _, proto := range fidlProtocolRe.FindAllSubmatch(data, -1) {
+			attrs := string(proto[1])
+			if !strings.Contains(attrs, `@transport("Syscall")`) {
+				continue
+			}
+			for _, method := range fidlMethodRe.FindAllSubmatch(proto[3], -1) {
+				if skipAttrs(fidlAttributes(method[1])) {
+					continue
+				}
+				call := &ZirconSyscall{
+					Name: "zx_" + snakeCase(string(proto[2])) + "_" + snakeCase(string(method[2])),
+					File: file,
+				}
+				args, err := zirconArgs(method[3], false)
+				if err != nil {
+					return nil, fmt.Errorf("%v: %v: %w", file, call.Name, err)
+				}
+				outs, err := zirconArgs(method[4], true)
+				if err != nil {
+					return nil, fmt.Errorf("%v: %v: %w", file, call.Name, err)
+				}
+				call.Args = linkLens(append(args, outs...))
+				res = append(res, call)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+func fidlAttributes(data []byte) map[string]bool {
+	attrs := make(map[string]bool)
+	for _, match := range fidlAttrRe.FindAllSubmatch(data, -1) {
+		attrs[string(match[1])] = true
+	}
+	return attrs
+}
+
+func skipAttrs(attrs map[string]bool) bool {
+	return attrs["internal"] || attrs["testonly"]
+}
+
+func zirconArgs(body []byte, response bool) ([]ZirconArg, error) {
+	var args []ZirconArg
+	for _, field := range bytes.Split(body, []byte(";")) {
+		field = bytes.TrimSpace(field)
+		if len(field) == 0 {
+			continue
+		}
+		match := fidlFieldRe.FindSubmatch(field)
+		if match == nil {
+			return nil, fmt.Errorf("can't parse struct member %q", field)
+		}
+		attrs := fidlAttributes(match[1])
+		name, typ := string(match[2]), strings.Join(strings.Fields(string(match[3])), " ")
+		if response && (typ == "status" || typ == "Status") {
+			// The old syntax returned the status as the first response member.
+			continue
+		}
+		out := response || attrs["out"] || attrs["inout"]
+		args = append(args, zirconArg(name, typ, out, attrs["inout"])...)
+	}
+	return args, nil
+}
+
+func zirconArg(name, typ string, out, inout bool) []ZirconArg {
+	dir := "in"
+	if inout {
+		dir = "inout"
+	} else if out {
+		dir = "out"
+	}
+	if pos := strings.LastIndexByte(typ, '>'); pos != -1 {
+		typ = typ[:pos+1] // drop constraints like vector<byte>:64
+	}
+	base, param, _ := strings.Cut(strings.TrimSuffix(typ, ">"), "<")
+	kind, _, _ := strings.Cut(base, ":")
+	switch {
+	case kind == "string":
+		return []ZirconArg{
+			{name, "ptr[in, string]"},
+			{name + "_size", "len[" + name + "]"},
+		}
+	case base == "vector" || strings.HasPrefix(base, "mutable_vector"):
+		if strings.HasPrefix(base, "mutable_vector") {
+			dir, param = "out", strings.TrimPrefix(base, "mutable_vector_")
+			param, _, _ = strings.Cut(param, "_u32")
+		}
+		return []ZirconArg{
+			{name, fmt.Sprintf("ptr[%v, array[%v]]", dir, zirconScalar(param))},
+			{"num_" + name, "len[" + name + "]"},
+		}
+	case base == "experimental_pointer" || base == "mutable_experimental_pointer":
+		if base == "mutable_experimental_pointer" {
+			dir = "out"
+		}
+		elem := "int8"
+		if isKnownZirconType(param) {
+			elem = zirconScalar(param)
+		}
+		return []ZirconArg{{name, fmt.Sprintf("ptr[%v, array[%v]]", dir, elem)}}
+	}
+	if out {
+		return []ZirconArg{{name, fmt.Sprintf("ptr[%v, %v]", dir, zirconScalar(typ))}}
+	}
+	return []ZirconArg{{name, zirconScalar(typ)}}
+}
+
+func isKnownZirconType(typ string) bool {
+	base, _, _ := strings.Cut(typ, ":")
+	return zirconScalars[base] != "" || strings.EqualFold(base, "handle")
+}
+
+func zirconScalar(typ string) string {
+	base, subtype, _ := strings.Cut(typ, ":")
+	if strings.EqualFold(base, "handle") {
+		subtype, _, _ = strings.Cut(strings.Trim(subtype, "<>"), ",")
+		if res := zirconHandles[strings.TrimSpace(subtype)]; res != "" {
+			return res
+		}
+		return "zx_handle"
+	}
+	if res := zirconScalars[base]; res != "" {
+		return res
+	}
+	// Enums, bits and aliases defined in the zx library, most of them are 32-bit.
+	return "int32"
+}
+
+// linkLens turns size arguments that follow naming conventions of the zx library
+// (num_foo, foo_size, foo_len) into len of the corresponding pointer argument.
+func linkLens(args []ZirconArg) []ZirconArg {
+	ptrs := make(map[string]bool)
+	for _, arg := range args {
+		if strings.HasPrefix(arg.Type, "ptr[") && strings.Contains(arg.Type, "array[") {
+			ptrs[arg.Name] = true
+		}
+	}
+	for i, arg := range args {
+		if strings.HasPrefix(arg.Type, "ptr[") || strings.HasPrefix(arg.Type, "len[") {
+			continue
+		}
+		for _, ptr := range []string{
+			strings.TrimPrefix(arg.Name, "num_"),
+			strings.TrimSuffix(arg.Name, "_size"),
+			strings.TrimSuffix(arg.Name, "_len"),
+		} {
+			if ptr != arg.Name && ptrs[ptr] {
+				args[i].Type = "len[" + ptr + "]"
+				break
+			}
+		}
+	}
+	return args
+}
+
+// snakeCase converts CamelCase FIDL names to snake_case C names.
+func snakeCase(name string) string {
+	var res []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i != 0 && (unicode.IsLower(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				res = append(res, '_')
+			}
+			r = unicode.ToLower(r)
+		}
+		res = append(res, r)
+	}
+	return string(res)
+}
+
+// SerializeZircon generates syzlang descriptions for the extracted Zircon syscalls.
+func SerializeZircon(calls []*ZirconSyscall) []byte {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Code generated by syz-zirconextract. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "include <zircon/syscalls.h>\n")
+	byFile := make(map[string][]*ZirconSyscall)
+	for _, call := range calls {
+		byFile[call.File] = append(byFile[call.File], call)
+	}
+	var files []string
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		fmt.Fprintf(buf, "\n# %v\n", file)
+		for _, call := range byFile[file] {
+			var args []string
+			for _, arg := range call.Args {
+				args = append(args, arg.Name+" "+arg.Type)
+			}
+			fmt.Fprintf(buf, "%v(%v)\n", call.Name, strings.Join(args, ", "))
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package declextract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractZircon(t *testing.T) {
+	files := map[string][]byte{
+		"zircon/vdso/channel.fidl": []byte(`
+library zx;
+
+@transport("Syscall")
+closed protocol Channel {
+    /// ## Summary
+    ///
+    /// Create a channel.
+    @handle_unchecked
+    strict Create(struct {
+        options uint32;
+    }) -> (resource struct {
+        out0 Handle;
+        out1 Handle;
+    }) error Status;
+
+    strict Read(resource struct {
+        handle Handle:CHANNEL;
+        options uint32;
+        @voidptr
+        bytes experimental_pointer<byte>;
+        handles experimental_pointer<Handle>;
+        num_bytes uint32;
+        num_handles uint32;
+    }) -> (struct {
+        actual_bytes uint32;
+        actual_handles uint32;
+    }) error Status;
+
+    @internal
+    strict CallNoretry(resource struct {
+        handle Handle:CHANNEL;
+    }) -> () error Status;
+};
+`),
+		"zircon/vdso/process.fidl": []byte(`
+library zx;
+
+@transport("Syscall")
+protocol process {
+    @noreturn
+    exit(struct {
+        retcode int64;
+    });
+
+    create(resource struct {
+        job handle:JOB;
+        name string:MAX_NAME_LEN;
+        options uint32;
+    }) -> (resource struct {
+        status status;
+        proc_handle handle:PROCESS;
+        vmar_handle handle:VMAR;
+    });
+
+    read_memory(resource struct {
+        handle handle:PROCESS;
+        vaddr vaddr;
+        @out
+        buffer experimental_pointer<byte>;
+        buffer_size usize;
+        @inout
+        info ProcessInfo;
+    }) -> (struct {
+        status status;
+        actual usize;
+    });
+};
+
+protocol NotSyscalls {
+    Foo(struct {
+        a uint32;
+    });
+};
+`),
+	}
+	calls, err := ExtractZircon(files)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `# Code generated by syz-zirconextract. DO NOT EDIT.
+
+include <zircon/syscalls.h>
+
+# zircon/vdso/channel.fidl
+zx_channel_create(options int32, out0 ptr[out, zx_handle], out1 ptr[out, zx_handle])
+zx_channel_read(handle zx_chan, options int32, bytes ptr[in, array[int8]], handles ptr[in, array[zx_handle]], num_bytes len[bytes], num_handles len[handles], actual_bytes ptr[out, int32], actual_handles ptr[out, int32])
+
+# zircon/vdso/process.fidl
+zx_process_create(job zx_job, name ptr[in, string], name_size len[name], options int32, proc_handle ptr[out, zx_process], vmar_handle ptr[out, zx_vmar])
+zx_process_exit(retcode int64)
+zx_process_read_memory(handle zx_process, vaddr zx_vaddr, buffer ptr[out, array[int8]], buffer_size len[buffer], info ptr[inout, int32], actual ptr[out, intptr])
+`, string(SerializeZircon(calls)))
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, out := range map[string]string{
+		"Channel":      "channel",
+		"CreateChild":  "create_child",
+		"read_memory":  "read_memory",
+		"GetInfo":      "get_info",
+		"VmoReplaceAs": "vmo_replace_as",
+		"IOMMUCreate":  "iommu_create",
+	} {
+		assert.Equal(t, out, snakeCase(in), in)
+	}
+}
@@ -4,8 +4,11 @@
 package flatrpc
 
 import (
+	"reflect"
 	"testing"
 
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/google/syzkaller/prog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,13 +18,17 @@ func TestConn(t *testing.T) {
 		Arch:        "arch",
 		GitRevision: "rev1",
 		SyzRevision: "rev2",
+
+		ProtocolVersion: ProtocolVersion,
+		Features:        SupportedFeatures,
 	}
 	connectReply := &ConnectReply{
-		LeakFrames: []string{"foo", "bar"},
-		RaceFrames: []string{"bar", "baz"},
-		Features:   FeatureCoverage | FeatureLeak,
-		Files:      []string{"file1"},
-		Globs:      []string{"glob1"},
+		LeakFrames:      []string{"foo", "bar"},
+		RaceFrames:      []string{"bar", "baz"},
+		Features:        FeatureCoverage | FeatureLeak,
+		Files:           []string{"file1"},
+		Globs:           []string{"glob1"},
+		ProtocolVersion: ProtocolVersion,
 	}
 	executorMsg := &ExecutorMessage{
 		Msg: &ExecutorMessages{
@@ -140,3 +147,73 @@ func BenchmarkConn(b *testing.B) {
 		}
 	}
 }
+
+func TestProtocolVersion(t *testing.T) {
+	assert.NoError(t, CheckProtocolVersion("fuzzer", ProtocolVersion))
+	assert.Error(t, CheckProtocolVersion("fuzzer", MinProtocolVersion-1))
+
+	// Emulate a request from a fuzzer that predates protocol versioning
+	// (the table has only the first 4 fields).
+	builder := flatbuffers.NewBuilder(0)
+	name := builder.CreateString("foo")
+	builder.StartObject(4)
+	ConnectRequestRawAddName(builder, name)
+	builder.Finish(builder.EndObject())
+	req := GetRootAsConnectRequestRaw(builder.FinishedBytes(), 0).UnPack()
+	assert.Equal(t, "foo", req.Name)
+	assert.Equal(t, int32(0), req.ProtocolVersion)
+	assert.Equal(t, Feature(0), req.Features)
+	err := CheckProtocolVersion("fuzzer", req.ProtocolVersion)
+	assert.ErrorContains(t, err, "predates protocol versioning")
+}
+
+// protocolLayouts is the list of call properties in the exec encoding and the list of exec flags
+// per protocol version. The current layout must match the ProtocolVersion entry, so a layout change
+// fails the test unless ProtocolVersion is bumped in the same change.
+var protocolLayouts = map[int]protocolLayout{
+	1: {execProps: baseExecProps, execFlags: baseExecFlags},
+	2: {execProps: append(baseExecProps, "compat"), execFlags: baseExecFlags},
+	3: {execProps: append(baseExecProps, "compat", "suspend"), execFlags: baseExecFlags},
+	4: {execProps: append(baseExecProps, "compat", "suspend", "uring"), execFlags: baseExecFlags},
+	5: {
+		execProps: append(baseExecProps, "compat", "suspend", "uring"),
+		execFlags: append(baseExecFlags, "CollectRaces"),
+	},
+	6: {
+		execProps: append(baseExecProps, "compat", "suspend", "uring"),
+		execFlags: append(baseExecFlags, "CollectRaces", "CollectWarnings"),
+	},
+	7: {
+		execProps: append(baseExecProps, "compat", "suspend", "uring"),
+		execFlags: append(baseExecFlags, "CollectRaces", "CollectWarnings", "RealtimeJumps"),
+	},
+}
+
+type protocolLayout struct {
+	execProps []string
+	execFlags []string
+}
+
+var (
+	baseExecProps = []string{"fail_nth", "async", "rerun", "role", "time_jump"}
+	baseExecFlags = []string{"CollectSignal", "CollectCover", "DedupCover", "CollectComps", "Threaded", "CoverFilter"}
+)
+
+func TestProtocolVersionLayout(t *testing.T) {
+	// Call properties are serialized positionally in execInstrSetProps and exec flags are a bitmask,
+	// so peers that disagree on the lists misparse exec programs or misinterpret requests.
+	var cur protocolLayout
+	props := &prog.CallProps{}
+	props.ForeachProp(func(_, key string, _ reflect.Value) {
+		cur.execProps = append(cur.execProps, key)
+	})
+	for flag := ExecFlag(1); flag != 0; flag <<= 1 {
+		if name, ok := EnumNamesExecFlag[flag]; ok {
+			cur.execFlags = append(cur.execFlags, name)
+		}
+	}
+	assert.Equal(t, protocolLayouts[ProtocolVersion], cur,
+		"exec layout changed: bump ProtocolVersion (and MinProtocolVersion) in the same change"+
+			" and add the new layout to protocolLayouts")
+	assert.Len(t, protocolLayouts, ProtocolVersion, "protocolLayouts must have an entry for every version")
+}
@@ -31,6 +31,11 @@ const (
 	FeatureLRWPANEmulation  Feature = 131072
 	FeatureBinFmtMisc       Feature = 262144
 	FeatureSwap             Feature = 524288
+	FeatureHugepages        Feature = 1048576
+	FeaturePmem             Feature = 2097152
+	FeatureIOUring          Feature = 4194304
+	FeatureSuspend          Feature = 8388608
+	FeatureCompatSyscalls   Feature = 16777216
 )
 
 var EnumNamesFeature = map[Feature]string{
@@ -54,6 +59,11 @@ var EnumNamesFeature = map[Feature]string{
 	FeatureLRWPANEmulation:  "LRWPANEmulation",
 	FeatureBinFmtMisc:       "BinFmtMisc",
 	FeatureSwap:             "Swap",
+	FeatureHugepages:        "Hugepages",
+	FeaturePmem:             "Pmem",
+	FeatureIOUring:          "IOUring",
+	FeatureSuspend:          "Suspend",
+	FeatureCompatSyscalls:   "CompatSyscalls",
 }
 
 var EnumValuesFeature = map[string]Feature{
@@ -77,6 +87,11 @@ var EnumValuesFeature = map[string]Feature{
 	"LRWPANEmulation":  FeatureLRWPANEmulation,
 	"BinFmtMisc":       FeatureBinFmtMisc,
 	"Swap":             FeatureSwap,
+	"Hugepages":        FeatureHugepages,
+	"Pmem":             FeaturePmem,
+	"IOUring":          FeatureIOUring,
+	"Suspend":          FeatureSuspend,
+	"CompatSyscalls":   FeatureCompatSyscalls,
 }
 
 func (v Feature) String() string {
@@ -154,21 +169,24 @@ func (rcv HostMessagesRaw) UnPack(table flatbuffers.Table) *HostMessagesRawT {
 type ExecutorMessagesRaw byte
 
 const (
-	ExecutorMessagesRawNONE       ExecutorMessagesRaw = 0
-	ExecutorMessagesRawExecResult ExecutorMessagesRaw = 1
-	ExecutorMessagesRawExecuting  ExecutorMessagesRaw = 2
+	ExecutorMessagesRawNONE          ExecutorMessagesRaw = 0
+	ExecutorMessagesRawExecResult    ExecutorMessagesRaw = 1
+	ExecutorMessagesRawExecuting     ExecutorMessagesRaw = 2
+	ExecutorMessagesRawModulesUpdate ExecutorMessagesRaw = 3
 )
 
 var EnumNamesExecutorMessagesRaw = map[ExecutorMessagesRaw]string{
-	ExecutorMessagesRawNONE:       "NONE",
-	ExecutorMessagesRawExecResult: "ExecResult",
-	ExecutorMessagesRawExecuting:  "Executing",
+	ExecutorMessagesRawNONE:          "NONE",
+	ExecutorMessagesRawExecResult:    "ExecResult",
+	ExecutorMessagesRawExecuting:     "Executing",
+	ExecutorMessagesRawModulesUpdate: "ModulesUpdate",
 }
 
 var EnumValuesExecutorMessagesRaw = map[string]ExecutorMessagesRaw{
-	"NONE":       ExecutorMessagesRawNONE,
-	"ExecResult": ExecutorMessagesRawExecResult,
-	"Executing":  ExecutorMessagesRawExecuting,
+	"NONE":          ExecutorMessagesRawNONE,
+	"ExecResult":    ExecutorMessagesRawExecResult,
+	"Executing":     ExecutorMessagesRawExecuting,
+	"ModulesUpdate": ExecutorMessagesRawModulesUpdate,
 }
 
 func (v ExecutorMessagesRaw) String() string {
@@ -192,6 +210,8 @@ func (t *ExecutorMessagesRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UO
 		return t.Value.(*ExecResultRawT).Pack(builder)
 	case ExecutorMessagesRawExecuting:
 		return t.Value.(*ExecutingMessageRawT).Pack(builder)
+	case ExecutorMessagesRawModulesUpdate:
+		return t.Value.(*ModulesUpdateRawT).Pack(builder)
 	}
 	return 0
 }
@@ -204,6 +224,9 @@ func (rcv ExecutorMessagesRaw) UnPack(table flatbuffers.Table) *ExecutorMessages
 	case ExecutorMessagesRawExecuting:
 		x := ExecutingMessageRaw{_tab: table}
 		return &ExecutorMessagesRawT{Type: ExecutorMessagesRawExecuting, Value: x.UnPack()}
+	case ExecutorMessagesRawModulesUpdate:
+		x := ModulesUpdateRaw{_tab: table}
+		return &ExecutorMessagesRawT{Type: ExecutorMessagesRawModulesUpdate, Value: x.UnPack()}
 	}
 	return nil
 }
@@ -310,30 +333,39 @@ func (v ExecEnv) String() string {
 type ExecFlag uint64
 
 const (
-	ExecFlagCollectSignal ExecFlag = 1
-	ExecFlagCollectCover  ExecFlag = 2
-	ExecFlagDedupCover    ExecFlag = 4
-	ExecFlagCollectComps  ExecFlag = 8
-	ExecFlagThreaded      ExecFlag = 16
-	ExecFlagCoverFilter   ExecFlag = 32
+	ExecFlagCollectSignal   ExecFlag = 1
+	ExecFlagCollectCover    ExecFlag = 2
+	ExecFlagDedupCover      ExecFlag = 4
+	ExecFlagCollectComps    ExecFlag = 8
+	ExecFlagThreaded        ExecFlag = 16
+	ExecFlagCoverFilter     ExecFlag = 32
+	ExecFlagCollectRaces    ExecFlag = 64
+	ExecFlagCollectWarnings ExecFlag = 128
+	ExecFlagRealtimeJumps   ExecFlag = 256
 )
 
 var EnumNamesExecFlag = map[ExecFlag]string{
-	ExecFlagCollectSignal: "CollectSignal",
-	ExecFlagCollectCover:  "CollectCover",
-	ExecFlagDedupCover:    "DedupCover",
-	ExecFlagCollectComps:  "CollectComps",
-	ExecFlagThreaded:      "Threaded",
-	ExecFlagCoverFilter:   "CoverFilter",
+	ExecFlagCollectSignal:   "CollectSignal",
+	ExecFlagCollectCover:    "CollectCover",
+	ExecFlagDedupCover:      "DedupCover",
+	ExecFlagCollectComps:    "CollectComps",
+	ExecFlagThreaded:        "Threaded",
+	ExecFlagCoverFilter:     "CoverFilter",
+	ExecFlagCollectRaces:    "CollectRaces",
+	ExecFlagCollectWarnings: "CollectWarnings",
+	ExecFlagRealtimeJumps:   "RealtimeJumps",
 }
 
 var EnumValuesExecFlag = map[string]ExecFlag{
-	"CollectSignal": ExecFlagCollectSignal,
-	"CollectCover":  ExecFlagCollectCover,
-	"DedupCover":    ExecFlagDedupCover,
-	"CollectComps":  ExecFlagCollectComps,
-	"Threaded":      ExecFlagThreaded,
-	"CoverFilter":   ExecFlagCoverFilter,
+	"CollectSignal":   ExecFlagCollectSignal,
+	"CollectCover":    ExecFlagCollectCover,
+	"DedupCover":      ExecFlagDedupCover,
+	"CollectComps":    ExecFlagCollectComps,
+	"Threaded":        ExecFlagThreaded,
+	"CoverFilter":     ExecFlagCoverFilter,
+	"CollectRaces":    ExecFlagCollectRaces,
+	"CollectWarnings": ExecFlagCollectWarnings,
+	"RealtimeJumps":   ExecFlagRealtimeJumps,
 }
 
 func (v ExecFlag) String() string {
@@ -350,6 +382,8 @@ const (
 	CallFlagFinished      CallFlag = 2
 	CallFlagBlocked       CallFlag = 4
 	CallFlagFaultInjected CallFlag = 8
+	CallFlagKernelWarning CallFlag = 16
+	CallFlagRaceCandidate CallFlag = 32
 )
 
 var EnumNamesCallFlag = map[CallFlag]string{
@@ -357,6 +391,8 @@ var EnumNamesCallFlag = map[CallFlag]string{
 	CallFlagFinished:      "Finished",
 	CallFlagBlocked:       "Blocked",
 	CallFlagFaultInjected: "FaultInjected",
+	CallFlagKernelWarning: "KernelWarning",
+	CallFlagRaceCandidate: "RaceCandidate",
 }
 
 var EnumValuesCallFlag = map[string]CallFlag{
@@ -364,6 +400,8 @@ var EnumValuesCallFlag = map[string]CallFlag{
 	"Finished":      CallFlagFinished,
 	"Blocked":       CallFlagBlocked,
 	"FaultInjected": CallFlagFaultInjected,
+	"KernelWarning": CallFlagKernelWarning,
+	"RaceCandidate": CallFlagRaceCandidate,
 }
 
 func (v CallFlag) String() string {
@@ -374,10 +412,12 @@ func (v CallFlag) String() string {
 }
 
 type ConnectRequestRawT struct {
-	Name        string `json:"name"`
-	Arch        string `json:"arch"`
-	GitRevision string `json:"git_revision"`
-	SyzRevision string `json:"syz_revision"`
+	Name            string  `json:"name"`
+	Arch            string  `json:"arch"`
+	GitRevision     string  `json:"git_revision"`
+	SyzRevision     string  `json:"syz_revision"`
+	ProtocolVersion int32   `json:"protocol_version"`
+	Features        Feature `json:"features"`
 }
 
 func (t *ConnectRequestRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -393,6 +433,8 @@ func (t *ConnectRequestRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOff
 	ConnectRequestRawAddArch(builder, archOffset)
 	ConnectRequestRawAddGitRevision(builder, gitRevisionOffset)
 	ConnectRequestRawAddSyzRevision(builder, syzRevisionOffset)
+	ConnectRequestRawAddProtocolVersion(builder, t.ProtocolVersion)
+	ConnectRequestRawAddFeatures(builder, t.Features)
 	return ConnectRequestRawEnd(builder)
 }
 
@@ -401,6 +443,8 @@ func (rcv *ConnectRequestRaw) UnPackTo(t *ConnectRequestRawT) {
 	t.Arch = string(rcv.Arch())
 	t.GitRevision = string(rcv.GitRevision())
 	t.SyzRevision = string(rcv.SyzRevision())
+	t.ProtocolVersion = rcv.ProtocolVersion()
+	t.Features = rcv.Features()
 }
 
 func (rcv *ConnectRequestRaw) UnPack() *ConnectRequestRawT {
@@ -471,8 +515,32 @@ func (rcv *ConnectRequestRaw) SyzRevision() []byte {
 	return nil
 }
 
+func (rcv *ConnectRequestRaw) ProtocolVersion() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *ConnectRequestRaw) MutateProtocolVersion(n int32) bool {
+	return rcv._tab.MutateInt32Slot(12, n)
+}
+
+func (rcv *ConnectRequestRaw) Features() Feature {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return Feature(rcv._tab.GetUint64(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *ConnectRequestRaw) MutateFeatures(n Feature) bool {
+	return rcv._tab.MutateUint64Slot(14, uint64(n))
+}
+
 func ConnectRequestRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(4)
+	builder.StartObject(6)
 }
 func ConnectRequestRawAddName(builder *flatbuffers.Builder, name flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(name), 0)
@@ -486,16 +554,23 @@ func ConnectRequestRawAddGitRevision(builder *flatbuffers.Builder, gitRevision f
 func ConnectRequestRawAddSyzRevision(builder *flatbuffers.Builder, syzRevision flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(syzRevision), 0)
 }
+func ConnectRequestRawAddProtocolVersion(builder *flatbuffers.Builder, protocolVersion int32) {
+	builder.PrependInt32Slot(4, protocolVersion, 0)
+}
+func ConnectRequestRawAddFeatures(builder *flatbuffers.Builder, features Feature) {
+	builder.PrependUint64Slot(5, uint64(features), 0)
+}
 func ConnectRequestRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
 
 type ConnectReplyRawT struct {
-	LeakFrames []string `json:"leak_frames"`
-	RaceFrames []string `json:"race_frames"`
-	Features   Feature  `json:"features"`
-	Files      []string `json:"files"`
-	Globs      []string `json:"globs"`
+	LeakFrames      []string `json:"leak_frames"`
+	RaceFrames      []string `json:"race_frames"`
+	Features        Feature  `json:"features"`
+	Files           []string `json:"files"`
+	Globs           []string `json:"globs"`
+	ProtocolVersion int32    `json:"protocol_version"`
 }
 
 func (t *ConnectReplyRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -560,6 +635,7 @@ func (t *ConnectReplyRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffse
 	ConnectReplyRawAddFeatures(builder, t.Features)
 	ConnectReplyRawAddFiles(builder, filesOffset)
 	ConnectReplyRawAddGlobs(builder, globsOffset)
+	ConnectReplyRawAddProtocolVersion(builder, t.ProtocolVersion)
 	return ConnectReplyRawEnd(builder)
 }
 
@@ -585,6 +661,7 @@ func (rcv *ConnectReplyRaw) UnPackTo(t *ConnectReplyRawT) {
 	for j := 0; j < globsLength; j++ {
 		t.Globs[j] = string(rcv.Globs(j))
 	}
+	t.ProtocolVersion = rcv.ProtocolVersion()
 }
 
 func (rcv *ConnectReplyRaw) UnPack() *ConnectReplyRawT {
@@ -703,8 +780,20 @@ func (rcv *ConnectReplyRaw) GlobsLength() int {
 	return 0
 }
 
+func (rcv *ConnectReplyRaw) ProtocolVersion() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *ConnectReplyRaw) MutateProtocolVersion(n int32) bool {
+	return rcv._tab.MutateInt32Slot(14, n)
+}
+
 func ConnectReplyRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(5)
+	builder.StartObject(6)
 }
 func ConnectReplyRawAddLeakFrames(builder *flatbuffers.Builder, leakFrames flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(leakFrames), 0)
@@ -733,6 +822,9 @@ func ConnectReplyRawAddGlobs(builder *flatbuffers.Builder, globs flatbuffers.UOf
 func ConnectReplyRawStartGlobsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(4, numElems, 4)
 }
+func ConnectReplyRawAddProtocolVersion(builder *flatbuffers.Builder, protocolVersion int32) {
+	builder.PrependInt32Slot(5, protocolVersion, 0)
+}
 func ConnectReplyRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -1623,21 +1715,25 @@ func ExecutorMessageRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 }
 
 type ExecOptsRawT struct {
-	EnvFlags   ExecEnv  `json:"env_flags"`
-	ExecFlags  ExecFlag `json:"exec_flags"`
-	SandboxArg int64    `json:"sandbox_arg"`
+	EnvFlags        ExecEnv  `json:"env_flags"`
+	ExecFlags       ExecFlag `json:"exec_flags"`
+	SandboxArg      int64    `json:"sandbox_arg"`
+	ProcMemoryLimit int64    `json:"proc_memory_limit"`
+	ProcPidsLimit   int64    `json:"proc_pids_limit"`
 }
 
 func (t *ExecOptsRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	if t == nil {
 		return 0
 	}
-	return CreateExecOptsRaw(builder, t.EnvFlags, t.ExecFlags, t.SandboxArg)
+	return CreateExecOptsRaw(builder, t.EnvFlags, t.ExecFlags, t.SandboxArg, t.ProcMemoryLimit, t.ProcPidsLimit)
 }
 func (rcv *ExecOptsRaw) UnPackTo(t *ExecOptsRawT) {
 	t.EnvFlags = rcv.EnvFlags()
 	t.ExecFlags = rcv.ExecFlags()
 	t.SandboxArg = rcv.SandboxArg()
+	t.ProcMemoryLimit = rcv.ProcMemoryLimit()
+	t.ProcPidsLimit = rcv.ProcPidsLimit()
 }
 
 func (rcv *ExecOptsRaw) UnPack() *ExecOptsRawT {
@@ -1683,8 +1779,24 @@ func (rcv *ExecOptsRaw) MutateSandboxArg(n int64) bool {
 	return rcv._tab.MutateInt64(rcv._tab.Pos+flatbuffers.UOffsetT(16), n)
 }
 
-func CreateExecOptsRaw(builder *flatbuffers.Builder, envFlags ExecEnv, execFlags ExecFlag, sandboxArg int64) flatbuffers.UOffsetT {
-	builder.Prep(8, 24)
+func (rcv *ExecOptsRaw) ProcMemoryLimit() int64 {
+	return rcv._tab.GetInt64(rcv._tab.Pos + flatbuffers.UOffsetT(24))
+}
+func (rcv *ExecOptsRaw) MutateProcMemoryLimit(n int64) bool {
+	return rcv._tab.MutateInt64(rcv._tab.Pos+flatbuffers.UOffsetT(24), n)
+}
+
+func (rcv *ExecOptsRaw) ProcPidsLimit() int64 {
+	return rcv._tab.GetInt64(rcv._tab.Pos + flatbuffers.UOffsetT(32))
+}
+func (rcv *ExecOptsRaw) MutateProcPidsLimit(n int64) bool {
+	return rcv._tab.MutateInt64(rcv._tab.Pos+flatbuffers.UOffsetT(32), n)
+}
+
+func CreateExecOptsRaw(builder *flatbuffers.Builder, envFlags ExecEnv, execFlags ExecFlag, sandboxArg int64, procMemoryLimit int64, procPidsLimit int64) flatbuffers.UOffsetT {
+	builder.Prep(8, 40)
+	builder.PrependInt64(procPidsLimit)
+	builder.PrependInt64(procMemoryLimit)
 	builder.PrependInt64(sandboxArg)
 	builder.PrependUint64(uint64(execFlags))
 	builder.PrependUint64(uint64(envFlags))
@@ -2274,6 +2386,111 @@ func ExecutingMessageRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
 
+type ModulesUpdateRawT struct {
+	Files []*FileInfoRawT `json:"files"`
+}
+
+func (t *ModulesUpdateRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	if t == nil {
+		return 0
+	}
+	filesOffset := flatbuffers.UOffsetT(0)
+	if t.Files != nil {
+		filesLength := len(t.Files)
+		filesOffsets := make([]flatbuffers.UOffsetT, filesLength)
+		for j := 0; j < filesLength; j++ {
+			filesOffsets[j] = t.Files[j].Pack(builder)
+		}
+		ModulesUpdateRawStartFilesVector(builder, filesLength)
+		for j := filesLength - 1; j >= 0; j-- {
+			builder.PrependUOffsetT(filesOffsets[j])
+		}
+		filesOffset = builder.EndVector(filesLength)
+	}
+	ModulesUpdateRawStart(builder)
+	ModulesUpdateRawAddFiles(builder, filesOffset)
+	return ModulesUpdateRawEnd(builder)
+}
+
+func (rcv *ModulesUpdateRaw) UnPackTo(t *ModulesUpdateRawT) {
+	filesLength := rcv.FilesLength()
+	t.Files = make([]*FileInfoRawT, filesLength)
+	for j := 0; j < filesLength; j++ {
+		x := FileInfoRaw{}
+		rcv.Files(&x, j)
+		t.Files[j] = x.UnPack()
+	}
+}
+
+func (rcv *ModulesUpdateRaw) UnPack() *ModulesUpdateRawT {
+	if rcv == nil {
+		return nil
+	}
+	t := &ModulesUpdateRawT{}
+	rcv.UnPackTo(t)
+	return t
+}
+
+type ModulesUpdateRaw struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsModulesUpdateRaw(buf []byte, offset flatbuffers.UOffsetT) *ModulesUpdateRaw {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &ModulesUpdateRaw{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func GetSizePrefixedRootAsModulesUpdateRaw(buf []byte, offset flatbuffers.UOffsetT) *ModulesUpdateRaw {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &ModulesUpdateRaw{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *ModulesUpdateRaw) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *ModulesUpdateRaw) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *ModulesUpdateRaw) Files(obj *FileInfoRaw, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *ModulesUpdateRaw) FilesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func ModulesUpdateRawStart(builder *flatbuffers.Builder) {
+	builder.StartObject(1)
+}
+func ModulesUpdateRawAddFiles(builder *flatbuffers.Builder, files flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(files), 0)
+}
+func ModulesUpdateRawStartFilesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func ModulesUpdateRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
 type CallInfoRawT struct {
 	Flags  CallFlag          `json:"flags"`
 	Error  int32             `json:"error"`
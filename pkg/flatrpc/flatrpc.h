@@ -69,6 +69,10 @@ struct ExecutingMessageRaw;
 struct ExecutingMessageRawBuilder;
 struct ExecutingMessageRawT;
 
+struct ModulesUpdateRaw;
+struct ModulesUpdateRawBuilder;
+struct ModulesUpdateRawT;
+
 struct CallInfoRaw;
 struct CallInfoRawBuilder;
 struct CallInfoRawT;
@@ -104,12 +108,17 @@ enum class Feature : uint64_t {
   LRWPANEmulation = 131072ULL,
   BinFmtMisc = 262144ULL,
   Swap = 524288ULL,
+  Hugepages = 1048576ULL,
+  Pmem = 2097152ULL,
+  IOUring = 4194304ULL,
+  Suspend = 8388608ULL,
+  CompatSyscalls = 16777216ULL,
   NONE = 0,
-  ANY = 1048575ULL
+  ANY = 33554431ULL
 };
 FLATBUFFERS_DEFINE_BITMASK_OPERATORS(Feature, uint64_t)
 
-inline const Feature (&EnumValuesFeature())[20] {
+inline const Feature (&EnumValuesFeature())[25] {
   static const Feature values[] = {
     Feature::Coverage,
     Feature::Comparisons,
@@ -130,7 +139,12 @@ inline const Feature (&EnumValuesFeature())[20] {
     Feature::WifiEmulation,
     Feature::LRWPANEmulation,
     Feature::BinFmtMisc,
-    Feature::Swap
+    Feature::Swap,
+    Feature::Hugepages,
+    Feature::Pmem,
+    Feature::IOUring,
+    Feature::Suspend,
+    Feature::CompatSyscalls
   };
   return values;
 }
@@ -157,6 +171,11 @@ inline const char *EnumNameFeature(Feature e) {
     case Feature::LRWPANEmulation: return "LRWPANEmulation";
     case Feature::BinFmtMisc: return "BinFmtMisc";
     case Feature::Swap: return "Swap";
+    case Feature::Hugepages: return "Hugepages";
+    case Feature::Pmem: return "Pmem";
+    case Feature::IOUring: return "IOUring";
+    case Feature::Suspend: return "Suspend";
+    case Feature::CompatSyscalls: return "CompatSyscalls";
     default: return "";
   }
 }
@@ -292,31 +311,34 @@ enum class ExecutorMessagesRaw : uint8_t {
   NONE = 0,
   ExecResult = 1,
   Executing = 2,
+  ModulesUpdate = 3,
   MIN = NONE,
-  MAX = Executing
+  MAX = ModulesUpdate
 };
 
-inline const ExecutorMessagesRaw (&EnumValuesExecutorMessagesRaw())[3] {
+inline const ExecutorMessagesRaw (&EnumValuesExecutorMessagesRaw())[4] {
   static const ExecutorMessagesRaw values[] = {
     ExecutorMessagesRaw::NONE,
     ExecutorMessagesRaw::ExecResult,
-    ExecutorMessagesRaw::Executing
+    ExecutorMessagesRaw::Executing,
+    ExecutorMessagesRaw::ModulesUpdate
   };
   return values;
 }
 
 inline const char * const *EnumNamesExecutorMessagesRaw() {
-  static const char * const names[4] = {
+  static const char * const names[5] = {
     "NONE",
     "ExecResult",
     "Executing",
+    "ModulesUpdate",
     nullptr
   };
   return names;
 }
 
 inline const char *EnumNameExecutorMessagesRaw(ExecutorMessagesRaw e) {
-  if (flatbuffers::IsOutRange(e, ExecutorMessagesRaw::NONE, ExecutorMessagesRaw::Executing)) return "";
+  if (flatbuffers::IsOutRange(e, ExecutorMessagesRaw::NONE, ExecutorMessagesRaw::ModulesUpdate)) return "";
   const size_t index = static_cast<size_t>(e);
   return EnumNamesExecutorMessagesRaw()[index];
 }
@@ -333,6 +355,10 @@ template<> struct ExecutorMessagesRawTraits<rpc::ExecutingMessageRaw> {
   static const ExecutorMessagesRaw enum_value = ExecutorMessagesRaw::Executing;
 };
 
+template<> struct ExecutorMessagesRawTraits<rpc::ModulesUpdateRaw> {
+  static const ExecutorMessagesRaw enum_value = ExecutorMessagesRaw::ModulesUpdate;
+};
+
 template<typename T> struct ExecutorMessagesRawUnionTraits {
   static const ExecutorMessagesRaw enum_value = ExecutorMessagesRaw::NONE;
 };
@@ -345,6 +371,10 @@ template<> struct ExecutorMessagesRawUnionTraits<rpc::ExecutingMessageRawT> {
   static const ExecutorMessagesRaw enum_value = ExecutorMessagesRaw::Executing;
 };
 
+template<> struct ExecutorMessagesRawUnionTraits<rpc::ModulesUpdateRawT> {
+  static const ExecutorMessagesRaw enum_value = ExecutorMessagesRaw::ModulesUpdate;
+};
+
 struct ExecutorMessagesRawUnion {
   ExecutorMessagesRaw type;
   void *value;
@@ -391,6 +421,14 @@ struct ExecutorMessagesRawUnion {
     return type == ExecutorMessagesRaw::Executing ?
       reinterpret_cast<const rpc::ExecutingMessageRawT *>(value) : nullptr;
   }
+  rpc::ModulesUpdateRawT *AsModulesUpdate() {
+    return type == ExecutorMessagesRaw::ModulesUpdate ?
+      reinterpret_cast<rpc::ModulesUpdateRawT *>(value) : nullptr;
+  }
+  const rpc::ModulesUpdateRawT *AsModulesUpdate() const {
+    return type == ExecutorMessagesRaw::ModulesUpdate ?
+      reinterpret_cast<const rpc::ModulesUpdateRawT *>(value) : nullptr;
+  }
 };
 
 bool VerifyExecutorMessagesRaw(flatbuffers::Verifier &verifier, const void *obj, ExecutorMessagesRaw type);
@@ -419,7 +457,7 @@ inline const RequestFlag (&EnumValuesRequestFlag())[5] {
 }
 
 inline const char * const *EnumNamesRequestFlag() {
-  static const char * const names[17] = {
+  static const char * const names[33] = {
     "IsBinary",
     "NewSignal",
     "",
@@ -520,19 +558,25 @@ enum class ExecFlag : uint64_t {
   CollectComps = 8ULL,
   Threaded = 16ULL,
   CoverFilter = 32ULL,
+  CollectRaces = 64ULL,
+  CollectWarnings = 128ULL,
+  RealtimeJumps = 256ULL,
   NONE = 0,
-  ANY = 63ULL
+  ANY = 511ULL
 };
 FLATBUFFERS_DEFINE_BITMASK_OPERATORS(ExecFlag, uint64_t)
 
-inline const ExecFlag (&EnumValuesExecFlag())[6] {
+inline const ExecFlag (&EnumValuesExecFlag())[9] {
   static const ExecFlag values[] = {
     ExecFlag::CollectSignal,
     ExecFlag::CollectCover,
     ExecFlag::DedupCover,
     ExecFlag::CollectComps,
     ExecFlag::Threaded,
-    ExecFlag::CoverFilter
+    ExecFlag::CoverFilter,
+    ExecFlag::CollectRaces,
+    ExecFlag::CollectWarnings,
+    ExecFlag::RealtimeJumps
   };
   return values;
 }
@@ -545,6 +589,9 @@ inline const char *EnumNameExecFlag(ExecFlag e) {
     case ExecFlag::CollectComps: return "CollectComps";
     case ExecFlag::Threaded: return "Threaded";
     case ExecFlag::CoverFilter: return "CoverFilter";
+    case ExecFlag::CollectRaces: return "CollectRaces";
+    case ExecFlag::CollectWarnings: return "CollectWarnings";
+    case ExecFlag::RealtimeJumps: return "RealtimeJumps";
     default: return "";
   }
 }
@@ -554,23 +601,27 @@ enum class CallFlag : uint8_t {
   Finished = 2,
   Blocked = 4,
   FaultInjected = 8,
+  KernelWarning = 16,
+  RaceCandidate = 32,
   NONE = 0,
-  ANY = 15
+  ANY = 63
 };
 FLATBUFFERS_DEFINE_BITMASK_OPERATORS(CallFlag, uint8_t)
 
-inline const CallFlag (&EnumValuesCallFlag())[4] {
+inline const CallFlag (&EnumValuesCallFlag())[6] {
   static const CallFlag values[] = {
     CallFlag::Executed,
     CallFlag::Finished,
     CallFlag::Blocked,
-    CallFlag::FaultInjected
+    CallFlag::FaultInjected,
+    CallFlag::KernelWarning,
+    CallFlag::RaceCandidate
   };
   return values;
 }
 
 inline const char * const *EnumNamesCallFlag() {
-  static const char * const names[9] = {
+  static const char * const names[33] = {
     "Executed",
     "Finished",
     "",
@@ -579,13 +630,37 @@ inline const char * const *EnumNamesCallFlag() {
     "",
     "",
     "FaultInjected",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "KernelWarning",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "RaceCandidate",
     nullptr
   };
   return names;
 }
 
 inline const char *EnumNameCallFlag(CallFlag e) {
-  if (flatbuffers::IsOutRange(e, CallFlag::Executed, CallFlag::FaultInjected)) return "";
+  if (flatbuffers::IsOutRange(e, CallFlag::Executed, CallFlag::RaceCandidate)) return "";
   const size_t index = static_cast<size_t>(e) - static_cast<size_t>(CallFlag::Executed);
   return EnumNamesCallFlag()[index];
 }
@@ -595,17 +670,23 @@ FLATBUFFERS_MANUALLY_ALIGNED_STRUCT(8) ExecOptsRaw FLATBUFFERS_FINAL_CLASS {
   uint64_t env_flags_;
   uint64_t exec_flags_;
   int64_t sandbox_arg_;
+  int64_t proc_memory_limit_;
+  int64_t proc_pids_limit_;
 
  public:
   ExecOptsRaw()
       : env_flags_(0),
         exec_flags_(0),
-        sandbox_arg_(0) {
+        sandbox_arg_(0),
+        proc_memory_limit_(0),
+        proc_pids_limit_(0) {
   }
-  ExecOptsRaw(rpc::ExecEnv _env_flags, rpc::ExecFlag _exec_flags, int64_t _sandbox_arg)
+  ExecOptsRaw(rpc::ExecEnv _env_flags, rpc::ExecFlag _exec_flags, int64_t _sandbox_arg, int64_t _proc_memory_limit, int64_t _proc_pids_limit)
       : env_flags_(flatbuffers::EndianScalar(static_cast<uint64_t>(_env_flags))),
         exec_flags_(flatbuffers::EndianScalar(static_cast<uint64_t>(_exec_flags))),
-        sandbox_arg_(flatbuffers::EndianScalar(_sandbox_arg)) {
+        sandbox_arg_(flatbuffers::EndianScalar(_sandbox_arg)),
+        proc_memory_limit_(flatbuffers::EndianScalar(_proc_memory_limit)),
+        proc_pids_limit_(flatbuffers::EndianScalar(_proc_pids_limit)) {
   }
   rpc::ExecEnv env_flags() const {
     return static_cast<rpc::ExecEnv>(flatbuffers::EndianScalar(env_flags_));
@@ -616,8 +697,14 @@ FLATBUFFERS_MANUALLY_ALIGNED_STRUCT(8) ExecOptsRaw FLATBUFFERS_FINAL_CLASS {
   int64_t sandbox_arg() const {
     return flatbuffers::EndianScalar(sandbox_arg_);
   }
+  int64_t proc_memory_limit() const {
+    return flatbuffers::EndianScalar(proc_memory_limit_);
+  }
+  int64_t proc_pids_limit() const {
+    return flatbuffers::EndianScalar(proc_pids_limit_);
+  }
 };
-FLATBUFFERS_STRUCT_END(ExecOptsRaw, 24);
+FLATBUFFERS_STRUCT_END(ExecOptsRaw, 40);
 
 FLATBUFFERS_MANUALLY_ALIGNED_STRUCT(8) ComparisonRaw FLATBUFFERS_FINAL_CLASS {
  private:
@@ -648,6 +735,8 @@ struct ConnectRequestRawT : public flatbuffers::NativeTable {
   std::string arch{};
   std::string git_revision{};
   std::string syz_revision{};
+  int32_t protocol_version = 0;
+  rpc::Feature features = static_cast<rpc::Feature>(0);
 };
 
 struct ConnectRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -657,7 +746,9 @@ struct ConnectRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_NAME = 4,
     VT_ARCH = 6,
     VT_GIT_REVISION = 8,
-    VT_SYZ_REVISION = 10
+    VT_SYZ_REVISION = 10,
+    VT_PROTOCOL_VERSION = 12,
+    VT_FEATURES = 14
   };
   const flatbuffers::String *name() const {
     return GetPointer<const flatbuffers::String *>(VT_NAME);
@@ -671,6 +762,12 @@ struct ConnectRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::String *syz_revision() const {
     return GetPointer<const flatbuffers::String *>(VT_SYZ_REVISION);
   }
+  int32_t protocol_version() const {
+    return GetField<int32_t>(VT_PROTOCOL_VERSION, 0);
+  }
+  rpc::Feature features() const {
+    return static_cast<rpc::Feature>(GetField<uint64_t>(VT_FEATURES, 0));
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyOffset(verifier, VT_NAME) &&
@@ -681,6 +778,8 @@ struct ConnectRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            verifier.VerifyString(git_revision()) &&
            VerifyOffset(verifier, VT_SYZ_REVISION) &&
            verifier.VerifyString(syz_revision()) &&
+           VerifyField<int32_t>(verifier, VT_PROTOCOL_VERSION, 4) &&
+           VerifyField<uint64_t>(verifier, VT_FEATURES, 8) &&
            verifier.EndTable();
   }
   ConnectRequestRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -704,6 +803,12 @@ struct ConnectRequestRawBuilder {
   void add_syz_revision(flatbuffers::Offset<flatbuffers::String> syz_revision) {
     fbb_.AddOffset(ConnectRequestRaw::VT_SYZ_REVISION, syz_revision);
   }
+  void add_protocol_version(int32_t protocol_version) {
+    fbb_.AddElement<int32_t>(ConnectRequestRaw::VT_PROTOCOL_VERSION, protocol_version, 0);
+  }
+  void add_features(rpc::Feature features) {
+    fbb_.AddElement<uint64_t>(ConnectRequestRaw::VT_FEATURES, static_cast<uint64_t>(features), 0);
+  }
   explicit ConnectRequestRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -720,8 +825,12 @@ inline flatbuffers::Offset<ConnectRequestRaw> CreateConnectRequestRaw(
     flatbuffers::Offset<flatbuffers::String> name = 0,
     flatbuffers::Offset<flatbuffers::String> arch = 0,
     flatbuffers::Offset<flatbuffers::String> git_revision = 0,
-    flatbuffers::Offset<flatbuffers::String> syz_revision = 0) {
+    flatbuffers::Offset<flatbuffers::String> syz_revision = 0,
+    int32_t protocol_version = 0,
+    rpc::Feature features = static_cast<rpc::Feature>(0)) {
   ConnectRequestRawBuilder builder_(_fbb);
+  builder_.add_features(features);
+  builder_.add_protocol_version(protocol_version);
   builder_.add_syz_revision(syz_revision);
   builder_.add_git_revision(git_revision);
   builder_.add_arch(arch);
@@ -734,7 +843,9 @@ inline flatbuffers::Offset<ConnectRequestRaw> CreateConnectRequestRawDirect(
     const char *name = nullptr,
     const char *arch = nullptr,
     const char *git_revision = nullptr,
-    const char *syz_revision = nullptr) {
+    const char *syz_revision = nullptr,
+    int32_t protocol_version = 0,
+    rpc::Feature features = static_cast<rpc::Feature>(0)) {
   auto name__ = name ? _fbb.CreateString(name) : 0;
   auto arch__ = arch ? _fbb.CreateString(arch) : 0;
   auto git_revision__ = git_revision ? _fbb.CreateString(git_revision) : 0;
@@ -744,7 +855,9 @@ inline flatbuffers::Offset<ConnectRequestRaw> CreateConnectRequestRawDirect(
       name__,
       arch__,
       git_revision__,
-      syz_revision__);
+      syz_revision__,
+      protocol_version,
+      features);
 }
 
 flatbuffers::Offset<ConnectRequestRaw> CreateConnectRequestRaw(flatbuffers::FlatBufferBuilder &_fbb, const ConnectRequestRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -756,6 +869,7 @@ struct ConnectReplyRawT : public flatbuffers::NativeTable {
   rpc::Feature features = static_cast<rpc::Feature>(0);
   std::vector<std::string> files{};
   std::vector<std::string> globs{};
+  int32_t protocol_version = 0;
 };
 
 struct ConnectReplyRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -766,7 +880,8 @@ struct ConnectReplyRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_RACE_FRAMES = 6,
     VT_FEATURES = 8,
     VT_FILES = 10,
-    VT_GLOBS = 12
+    VT_GLOBS = 12,
+    VT_PROTOCOL_VERSION = 14
   };
   const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>> *leak_frames() const {
     return GetPointer<const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>> *>(VT_LEAK_FRAMES);
@@ -783,6 +898,9 @@ struct ConnectReplyRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>> *globs() const {
     return GetPointer<const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>> *>(VT_GLOBS);
   }
+  int32_t protocol_version() const {
+    return GetField<int32_t>(VT_PROTOCOL_VERSION, 0);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyOffset(verifier, VT_LEAK_FRAMES) &&
@@ -798,6 +916,7 @@ struct ConnectReplyRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            VerifyOffset(verifier, VT_GLOBS) &&
            verifier.VerifyVector(globs()) &&
            verifier.VerifyVectorOfStrings(globs()) &&
+           VerifyField<int32_t>(verifier, VT_PROTOCOL_VERSION, 4) &&
            verifier.EndTable();
   }
   ConnectReplyRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -824,6 +943,9 @@ struct ConnectReplyRawBuilder {
   void add_globs(flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>> globs) {
     fbb_.AddOffset(ConnectReplyRaw::VT_GLOBS, globs);
   }
+  void add_protocol_version(int32_t protocol_version) {
+    fbb_.AddElement<int32_t>(ConnectReplyRaw::VT_PROTOCOL_VERSION, protocol_version, 0);
+  }
   explicit ConnectReplyRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -841,9 +963,11 @@ inline flatbuffers::Offset<ConnectReplyRaw> CreateConnectReplyRaw(
     flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>> race_frames = 0,
     rpc::Feature features = static_cast<rpc::Feature>(0),
     flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>> files = 0,
-    flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>> globs = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>> globs = 0,
+    int32_t protocol_version = 0) {
   ConnectReplyRawBuilder builder_(_fbb);
   builder_.add_features(features);
+  builder_.add_protocol_version(protocol_version);
   builder_.add_globs(globs);
   builder_.add_files(files);
   builder_.add_race_frames(race_frames);
@@ -857,7 +981,8 @@ inline flatbuffers::Offset<ConnectReplyRaw> CreateConnectReplyRawDirect(
     const std::vector<flatbuffers::Offset<flatbuffers::String>> *race_frames = nullptr,
     rpc::Feature features = static_cast<rpc::Feature>(0),
     const std::vector<flatbuffers::Offset<flatbuffers::String>> *files = nullptr,
-    const std::vector<flatbuffers::Offset<flatbuffers::String>> *globs = nullptr) {
+    const std::vector<flatbuffers::Offset<flatbuffers::String>> *globs = nullptr,
+    int32_t protocol_version = 0) {
   auto leak_frames__ = leak_frames ? _fbb.CreateVector<flatbuffers::Offset<flatbuffers::String>>(*leak_frames) : 0;
   auto race_frames__ = race_frames ? _fbb.CreateVector<flatbuffers::Offset<flatbuffers::String>>(*race_frames) : 0;
   auto files__ = files ? _fbb.CreateVector<flatbuffers::Offset<flatbuffers::String>>(*files) : 0;
@@ -868,7 +993,8 @@ inline flatbuffers::Offset<ConnectReplyRaw> CreateConnectReplyRawDirect(
       race_frames__,
       features,
       files__,
-      globs__);
+      globs__,
+      protocol_version);
 }
 
 flatbuffers::Offset<ConnectReplyRaw> CreateConnectReplyRaw(flatbuffers::FlatBufferBuilder &_fbb, const ConnectReplyRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -1430,6 +1556,9 @@ struct ExecutorMessageRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const rpc::ExecutingMessageRaw *msg_as_Executing() const {
     return msg_type() == rpc::ExecutorMessagesRaw::Executing ? static_cast<const rpc::ExecutingMessageRaw *>(msg()) : nullptr;
   }
+  const rpc::ModulesUpdateRaw *msg_as_ModulesUpdate() const {
+    return msg_type() == rpc::ExecutorMessagesRaw::ModulesUpdate ? static_cast<const rpc::ModulesUpdateRaw *>(msg()) : nullptr;
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<uint8_t>(verifier, VT_MSG_TYPE, 1) &&
@@ -1450,6 +1579,10 @@ template<> inline const rpc::ExecutingMessageRaw *ExecutorMessageRaw::msg_as<rpc
   return msg_as_Executing();
 }
 
+template<> inline const rpc::ModulesUpdateRaw *ExecutorMessageRaw::msg_as<rpc::ModulesUpdateRaw>() const {
+  return msg_as_ModulesUpdate();
+}
+
 struct ExecutorMessageRawBuilder {
   typedef ExecutorMessageRaw Table;
   flatbuffers::FlatBufferBuilder &fbb_;
@@ -1830,6 +1963,73 @@ inline flatbuffers::Offset<ExecutingMessageRaw> CreateExecutingMessageRaw(
 
 flatbuffers::Offset<ExecutingMessageRaw> CreateExecutingMessageRaw(flatbuffers::FlatBufferBuilder &_fbb, const ExecutingMessageRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
 
+struct ModulesUpdateRawT : public flatbuffers::NativeTable {
+  typedef ModulesUpdateRaw TableType;
+  std::vector<std::unique_ptr<rpc::FileInfoRawT>> files{};
+  ModulesUpdateRawT() = default;
+  ModulesUpdateRawT(const ModulesUpdateRawT &o);
+  ModulesUpdateRawT(ModulesUpdateRawT&&) FLATBUFFERS_NOEXCEPT = default;
+  ModulesUpdateRawT &operator=(ModulesUpdateRawT o) FLATBUFFERS_NOEXCEPT;
+};
+
+struct ModulesUpdateRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
+  typedef ModulesUpdateRawT NativeTableType;
+  typedef ModulesUpdateRawBuilder Builder;
+  enum FlatBuffersVTableOffset FLATBUFFERS_VTABLE_UNDERLYING_TYPE {
+    VT_FILES = 4
+  };
+  const flatbuffers::Vector<flatbuffers::Offset<rpc::FileInfoRaw>> *files() const {
+    return GetPointer<const flatbuffers::Vector<flatbuffers::Offset<rpc::FileInfoRaw>> *>(VT_FILES);
+  }
+  bool Verify(flatbuffers::Verifier &verifier) const {
+    return VerifyTableStart(verifier) &&
+           VerifyOffset(verifier, VT_FILES) &&
+           verifier.VerifyVector(files()) &&
+           verifier.VerifyVectorOfTables(files()) &&
+           verifier.EndTable();
+  }
+  ModulesUpdateRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
+  void UnPackTo(ModulesUpdateRawT *_o, const flatbuffers::resolver_function_t *_resolver = nullptr) const;
+  static flatbuffers::Offset<ModulesUpdateRaw> Pack(flatbuffers::FlatBufferBuilder &_fbb, const ModulesUpdateRawT* _o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
+};
+
+struct ModulesUpdateRawBuilder {
+  typedef ModulesUpdateRaw Table;
+  flatbuffers::FlatBufferBuilder &fbb_;
+  flatbuffers::uoffset_t start_;
+  void add_files(flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<rpc::FileInfoRaw>>> files) {
+    fbb_.AddOffset(ModulesUpdateRaw::VT_FILES, files);
+  }
+  explicit ModulesUpdateRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
+        : fbb_(_fbb) {
+    start_ = fbb_.StartTable();
+  }
+  flatbuffers::Offset<ModulesUpdateRaw> Finish() {
+    const auto end = fbb_.EndTable(start_);
+    auto o = flatbuffers::Offset<ModulesUpdateRaw>(end);
+    return o;
+  }
+};
+
+inline flatbuffers::Offset<ModulesUpdateRaw> CreateModulesUpdateRaw(
+    flatbuffers::FlatBufferBuilder &_fbb,
+    flatbuffers::Offset<flatbuffers::Vector<flatbuffers::Offset<rpc::FileInfoRaw>>> files = 0) {
+  ModulesUpdateRawBuilder builder_(_fbb);
+  builder_.add_files(files);
+  return builder_.Finish();
+}
+
+inline flatbuffers::Offset<ModulesUpdateRaw> CreateModulesUpdateRawDirect(
+    flatbuffers::FlatBufferBuilder &_fbb,
+    const std::vector<flatbuffers::Offset<rpc::FileInfoRaw>> *files = nullptr) {
+  auto files__ = files ? _fbb.CreateVector<flatbuffers::Offset<rpc::FileInfoRaw>>(*files) : 0;
+  return rpc::CreateModulesUpdateRaw(
+      _fbb,
+      files__);
+}
+
+flatbuffers::Offset<ModulesUpdateRaw> CreateModulesUpdateRaw(flatbuffers::FlatBufferBuilder &_fbb, const ModulesUpdateRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
+
 struct CallInfoRawT : public flatbuffers::NativeTable {
   typedef CallInfoRaw TableType;
   rpc::CallFlag flags = static_cast<rpc::CallFlag>(0);
@@ -2176,6 +2376,8 @@ inline void ConnectRequestRaw::UnPackTo(ConnectRequestRawT *_o, const flatbuffer
   { auto _e = arch(); if (_e) _o->arch = _e->str(); }
   { auto _e = git_revision(); if (_e) _o->git_revision = _e->str(); }
   { auto _e = syz_revision(); if (_e) _o->syz_revision = _e->str(); }
+  { auto _e = protocol_version(); _o->protocol_version = _e; }
+  { auto _e = features(); _o->features = _e; }
 }
 
 inline flatbuffers::Offset<ConnectRequestRaw> ConnectRequestRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const ConnectRequestRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -2190,12 +2392,16 @@ inline flatbuffers::Offset<ConnectRequestRaw> CreateConnectRequestRaw(flatbuffer
   auto _arch = _o->arch.empty() ? 0 : _fbb.CreateString(_o->arch);
   auto _git_revision = _o->git_revision.empty() ? 0 : _fbb.CreateString(_o->git_revision);
   auto _syz_revision = _o->syz_revision.empty() ? 0 : _fbb.CreateString(_o->syz_revision);
+  auto _protocol_version = _o->protocol_version;
+  auto _features = _o->features;
   return rpc::CreateConnectRequestRaw(
       _fbb,
       _name,
       _arch,
       _git_revision,
-      _syz_revision);
+      _syz_revision,
+      _protocol_version,
+      _features);
 }
 
 inline ConnectReplyRawT *ConnectReplyRaw::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
@@ -2212,6 +2418,7 @@ inline void ConnectReplyRaw::UnPackTo(ConnectReplyRawT *_o, const flatbuffers::r
   { auto _e = features(); _o->features = _e; }
   { auto _e = files(); if (_e) { _o->files.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->files[_i] = _e->Get(_i)->str(); } } }
   { auto _e = globs(); if (_e) { _o->globs.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->globs[_i] = _e->Get(_i)->str(); } } }
+  { auto _e = protocol_version(); _o->protocol_version = _e; }
 }
 
 inline flatbuffers::Offset<ConnectReplyRaw> ConnectReplyRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const ConnectReplyRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -2227,13 +2434,15 @@ inline flatbuffers::Offset<ConnectReplyRaw> CreateConnectReplyRaw(flatbuffers::F
   auto _features = _o->features;
   auto _files = _o->files.size() ? _fbb.CreateVectorOfStrings(_o->files) : 0;
   auto _globs = _o->globs.size() ? _fbb.CreateVectorOfStrings(_o->globs) : 0;
+  auto _protocol_version = _o->protocol_version;
   return rpc::CreateConnectReplyRaw(
       _fbb,
       _leak_frames,
       _race_frames,
       _features,
       _files,
-      _globs);
+      _globs,
+      _protocol_version);
 }
 
 inline InfoRequestRawT::InfoRequestRawT(const InfoRequestRawT &o)
@@ -2621,6 +2830,42 @@ inline flatbuffers::Offset<ExecutingMessageRaw> CreateExecutingMessageRaw(flatbu
       _wait_duration);
 }
 
+inline ModulesUpdateRawT::ModulesUpdateRawT(const ModulesUpdateRawT &o) {
+  files.reserve(o.files.size());
+  for (const auto &files_ : o.files) { files.emplace_back((files_) ? new rpc::FileInfoRawT(*files_) : nullptr); }
+}
+
+inline ModulesUpdateRawT &ModulesUpdateRawT::operator=(ModulesUpdateRawT o) FLATBUFFERS_NOEXCEPT {
+  std::swap(files, o.files);
+  return *this;
+}
+
+inline ModulesUpdateRawT *ModulesUpdateRaw::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
+  auto _o = std::unique_ptr<ModulesUpdateRawT>(new ModulesUpdateRawT());
+  UnPackTo(_o.get(), _resolver);
+  return _o.release();
+}
+
+inline void ModulesUpdateRaw::UnPackTo(ModulesUpdateRawT *_o, const flatbuffers::resolver_function_t *_resolver) const {
+  (void)_o;
+  (void)_resolver;
+  { auto _e = files(); if (_e) { _o->files.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->files[_i] = std::unique_ptr<rpc::FileInfoRawT>(_e->Get(_i)->UnPack(_resolver)); } } }
+}
+
+inline flatbuffers::Offset<ModulesUpdateRaw> ModulesUpdateRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const ModulesUpdateRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
+  return CreateModulesUpdateRaw(_fbb, _o, _rehasher);
+}
+
+inline flatbuffers::Offset<ModulesUpdateRaw> CreateModulesUpdateRaw(flatbuffers::FlatBufferBuilder &_fbb, const ModulesUpdateRawT *_o, const flatbuffers::rehasher_function_t *_rehasher) {
+  (void)_rehasher;
+  (void)_o;
+  struct _VectorArgs { flatbuffers::FlatBufferBuilder *__fbb; const ModulesUpdateRawT* __o; const flatbuffers::rehasher_function_t *__rehasher; } _va = { &_fbb, _o, _rehasher}; (void)_va;
+  auto _files = _o->files.size() ? _fbb.CreateVector<flatbuffers::Offset<rpc::FileInfoRaw>> (_o->files.size(), [](size_t i, _VectorArgs *__va) { return CreateFileInfoRaw(*__va->__fbb, __va->__o->files[i].get(), __va->__rehasher); }, &_va ) : 0;
+  return rpc::CreateModulesUpdateRaw(
+      _fbb,
+      _files);
+}
+
 inline CallInfoRawT *CallInfoRaw::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
   auto _o = std::unique_ptr<CallInfoRawT>(new CallInfoRawT());
   UnPackTo(_o.get(), _resolver);
@@ -2886,6 +3131,10 @@ inline bool VerifyExecutorMessagesRaw(flatbuffers::Verifier &verifier, const voi
       auto ptr = reinterpret_cast<const rpc::ExecutingMessageRaw *>(obj);
       return verifier.VerifyTable(ptr);
     }
+    case ExecutorMessagesRaw::ModulesUpdate: {
+      auto ptr = reinterpret_cast<const rpc::ModulesUpdateRaw *>(obj);
+      return verifier.VerifyTable(ptr);
+    }
     default: return true;
   }
 }
@@ -2913,6 +3162,10 @@ inline void *ExecutorMessagesRawUnion::UnPack(const void *obj, ExecutorMessagesR
       auto ptr = reinterpret_cast<const rpc::ExecutingMessageRaw *>(obj);
       return ptr->UnPack(resolver);
     }
+    case ExecutorMessagesRaw::ModulesUpdate: {
+      auto ptr = reinterpret_cast<const rpc::ModulesUpdateRaw *>(obj);
+      return ptr->UnPack(resolver);
+    }
     default: return nullptr;
   }
 }
@@ -2928,6 +3181,10 @@ inline flatbuffers::Offset<void> ExecutorMessagesRawUnion::Pack(flatbuffers::Fla
       auto ptr = reinterpret_cast<const rpc::ExecutingMessageRawT *>(value);
       return CreateExecutingMessageRaw(_fbb, ptr, _rehasher).Union();
     }
+    case ExecutorMessagesRaw::ModulesUpdate: {
+      auto ptr = reinterpret_cast<const rpc::ModulesUpdateRawT *>(value);
+      return CreateModulesUpdateRaw(_fbb, ptr, _rehasher).Union();
+    }
     default: return 0;
   }
 }
@@ -2942,6 +3199,10 @@ inline ExecutorMessagesRawUnion::ExecutorMessagesRawUnion(const ExecutorMessages
       value = new rpc::ExecutingMessageRawT(*reinterpret_cast<rpc::ExecutingMessageRawT *>(u.value));
       break;
     }
+    case ExecutorMessagesRaw::ModulesUpdate: {
+      value = new rpc::ModulesUpdateRawT(*reinterpret_cast<rpc::ModulesUpdateRawT *>(u.value));
+      break;
+    }
     default:
       break;
   }
@@ -2959,6 +3220,11 @@ inline void ExecutorMessagesRawUnion::Reset() {
       delete ptr;
       break;
     }
+    case ExecutorMessagesRaw::ModulesUpdate: {
+      auto ptr = reinterpret_cast<rpc::ModulesUpdateRawT *>(value);
+      delete ptr;
+      break;
+    }
     default: break;
   }
   value = nullptr;
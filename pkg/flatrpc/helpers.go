@@ -4,12 +4,59 @@
 package flatrpc
 
 import (
+	"fmt"
 	"slices"
 	"syscall"
 )
 
 const AllFeatures = ^Feature(0)
 
+// ProtocolVersion is the version of the manager/fuzzer protocol implemented by this build.
+// It must be incremented on changes that peers built from older revisions can't handle
+// (e.g. new fields that change semantics of existing messages, or changes to the exec program
+// encoding such as new call properties; new optional fields that older peers can ignore are fine).
+// The version must be bumped in the same change that changes the protocol, so that no revision
+// implements a different protocol under the same version. TestProtocolVersionLayout checks this
+// for call properties and exec flags.
+// MinProtocolVersion is the oldest peer version this build can still work with.
+// Peers that predate protocol versioning report version 0.
+//
+// Version history:
+//   - 1: initial version.
+//   - 2: compat call property.
+//   - 3: suspend call property.
+//   - 4: uring call property.
+//   - 5: race candidates are reported only with the CollectRaces exec flag.
+//   - 6: kernel warnings are reported only with the CollectWarnings exec flag.
+//   - 7: CLOCK_REALTIME time jumps are done only with the RealtimeJumps exec flag.
+const (
+	ProtocolVersion    = 7
+	MinProtocolVersion = 7
+)
+
+// SupportedFeatures is the set of features known to this build.
+var SupportedFeatures = func() Feature {
+	var features Feature
+	for feat := range EnumNamesFeature {
+		features |= feat
+	}
+	return features
+}()
+
+// CheckProtocolVersion returns an error if this build can't work with the peer
+// that implements the given protocol version.
+func CheckProtocolVersion(peer string, version int32) error {
+	if version >= MinProtocolVersion {
+		return nil
+	}
+	if version == 0 {
+		return fmt.Errorf("%v predates protocol versioning (built from an older syzkaller revision?),"+
+			" this build requires protocol version %v-%v", peer, MinProtocolVersion, ProtocolVersion)
+	}
+	return fmt.Errorf("%v implements protocol version %v, this build requires protocol version %v-%v",
+		peer, version, MinProtocolVersion, ProtocolVersion)
+}
+
 // Flatbuffers compiler adds T suffix to object API types, which are actual structs representing types.
 // This leads to non-idiomatic Go code, e.g. we would have to use []FileInfoT in Go code.
 // So we use Raw suffix for all flatbuffers tables and rename object API types here to idiomatic names.
@@ -28,6 +75,7 @@ type ExecRequest = ExecRequestRawT
 type SignalUpdate = SignalUpdateRawT
 type StartLeakChecks = StartLeakChecksRawT
 type ExecutingMessage = ExecutingMessageRawT
+type ModulesUpdate = ModulesUpdateRawT
 type CallInfo = CallInfoRawT
 type Comparison = ComparisonRawT
 type ExecOpts = ExecOptsRawT
@@ -0,0 +1,156 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/image"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+// Blob corpora allow to share progress with byte-level fuzzers (libFuzzer, AFL)
+// for calls that take a single opaque blob (filesystem images, raw packets, etc).
+// The corpus format is the one used by libFuzzer: a directory with one file per input
+// named by SHA1 of the contents. Compressed blobs are stored decompressed.
+
+// Blobs larger than this are skipped during import (same as the limit used by blob mutations).
+const maxImportBlobLen = 100 << 10
+
+// BlobArg returns the first input blob argument of the call, or nil if the call does not have one.
+func BlobArg(c *prog.Call) *prog.DataArg {
+	var res *prog.DataArg
+	prog.ForeachArg(c, func(arg prog.Arg, ctx *prog.ArgCtx) {
+		if res != nil {
+			ctx.Stop = true
+			return
+		}
+		data, ok := arg.(*prog.DataArg)
+		if !ok || data.Dir() == prog.DirOut {
+			return
+		}
+		switch data.Type().(*prog.BufferType).Kind {
+		case prog.BufferBlobRand, prog.BufferBlobRange, prog.BufferCompressed:
+			res = data
+		}
+	})
+	return res
+}
+
+// ExportBlobs writes blob arguments of all invocations of the call in progs to dir.
+// It returns the number of unique blobs written.
+func ExportBlobs(progs []*prog.Prog, call, dir string) (int, error) {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return 0, err
+	}
+	written := make(map[string]bool)
+	for _, p := range progs {
+		for _, c := range p.Calls {
+			if c.Meta.Name != call {
+				continue
+			}
+			arg := BlobArg(c)
+			if arg == nil {
+				continue
+			}
+			data := arg.Data()
+			if arg.Type().(*prog.BufferType).IsCompressed() {
+				var err error
+				if data, err = image.Decompress(data); err != nil {
+					return 0, fmt.Errorf("corrupted compressed blob in %v: %w", call, err)
+				}
+			}
+			name := hash.String(data)
+			if written[name] {
+				continue
+			}
+			written[name] = true
+			if err := osutil.WriteFile(filepath.Join(dir, name), data); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(written), nil
+}
+
+// ImportBlobs creates a program for each blob in dir that invokes the call with the blob.
+// If some of the template programs contain the call, they are used as a base for the new programs
+// (everything after the call is removed). Otherwise other call arguments
+// (and calls that create resources for the call) are generated randomly,
+// which is not possible for no_generate calls (e.g. syz_mount_image).
+func ImportBlobs(target *prog.Target, call, dir string, templates []*prog.Prog, rs rand.Source) ([]*prog.Prog, error) {
+	meta := target.SyscallMap[call]
+	if meta == nil {
+		return nil, fmt.Errorf("unknown call %v", call)
+	}
+	type template struct {
+		p   *prog.Prog
+		idx int
+	}
+	var bases []template
+	for _, p := range templates {
+		for i, c := range p.Calls {
+			if c.Meta == meta && BlobArg(c) != nil {
+				bases = append(bases, template{p, i})
+			}
+		}
+	}
+	if len(bases) == 0 && meta.Attrs.NoGenerate {
+		return nil, fmt.Errorf("call %v can't be generated and there are no template programs", call)
+	}
+	rnd := rand.New(rs)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	var progs []*prog.Prog
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var p *prog.Prog
+		if len(bases) != 0 {
+			base := bases[rnd.Intn(len(bases))]
+			p = base.p.Clone()
+			for len(p.Calls) > base.idx+1 {
+				p.RemoveCall(len(p.Calls) - 1)
+			}
+		} else {
+			p = target.GenSampleProg(meta, rnd)
+		}
+		c := p.Calls[len(p.Calls)-1]
+		arg := BlobArg(c)
+		if arg == nil {
+			return nil, fmt.Errorf("call %v does not have blob arguments", call)
+		}
+		typ := arg.Type().(*prog.BufferType)
+		switch {
+		case typ.IsCompressed():
+			data = image.Compress(data)
+		case len(data) > maxImportBlobLen:
+			continue
+		case typ.Kind == prog.BufferBlobRange && uint64(len(data)) > typ.RangeEnd:
+			data = data[:typ.RangeEnd]
+		case typ.Kind == prog.BufferBlobRange && uint64(len(data)) < typ.RangeBegin:
+			data = append(data, make([]byte, typ.RangeBegin-uint64(len(data)))...)
+		}
+		arg.SetData(data)
+		target.AssignSizesCall(c)
+		progs = append(progs, p)
+	}
+	return progs, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobsRoundTrip(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs := [][]byte{
+		[]byte("first blob"),
+		bytes.Repeat([]byte{0xab}, 1000),
+		{},
+	}
+	// serialize3 is no_generate, so it needs a template.
+	template, err := target.Deserialize([]byte("mutate0()\nserialize3(&(0x7f0000000000)=\"$eJwAAAD//wEAAP//\")\nmutate0()\n"),
+		prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range []string{"mutate4", "serialize3"} {
+		t.Run(call, func(t *testing.T) {
+			inDir, outDir := t.TempDir(), t.TempDir()
+			for _, blob := range blobs {
+				err := osutil.WriteFile(filepath.Join(inDir, hash.String(blob)), blob)
+				assert.NoError(t, err)
+			}
+			progs, err := ImportBlobs(target, call, inDir, []*prog.Prog{template}, rand.NewSource(0))
+			assert.NoError(t, err)
+			assert.Len(t, progs, len(blobs))
+			for _, p := range progs {
+				c := p.Calls[len(p.Calls)-1]
+				assert.Equal(t, call, c.Meta.Name)
+				if call == "serialize3" {
+					assert.Len(t, p.Calls, 2)
+				}
+				if call == "mutate4" {
+					assert.Equal(t, BlobArg(c).Size(), c.Args[1].(*prog.ConstArg).Val)
+				}
+				// Programs must survive serialization.
+				_, err := target.Deserialize(p.Serialize(), prog.Strict)
+				assert.NoError(t, err)
+			}
+			n, err := ExportBlobs(progs, call, outDir)
+			assert.NoError(t, err)
+			assert.Equal(t, len(blobs), n)
+			for _, blob := range blobs {
+				data, err := os.ReadFile(filepath.Join(outDir, hash.String(blob)))
+				assert.NoError(t, err)
+				assert.Equal(t, blob, data)
+			}
+		})
+	}
+}
+
+func TestExportBlobsCorrupted(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("serialize3(&(0x7f0000000000)=\"$eJwAAAD//wEAAP//\")\n"), prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arg := BlobArg(p.Calls[0])
+	arg.SetData(append(arg.Data()[:2:2], "not zlib"...))
+	_, err = ExportBlobs([]*prog.Prog{p}, "serialize3", t.TempDir())
+	assert.ErrorContains(t, err, "corrupted compressed blob")
+}
+
+func TestImportBlobsNoBlob(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "blob"), []byte("data")))
+	_, err = ImportBlobs(target, "mutate0", dir, nil, rand.NewSource(0))
+	assert.Error(t, err)
+	_, err = ImportBlobs(target, "no_such_call", dir, nil, rand.NewSource(0))
+	assert.Error(t, err)
+	_, err = ImportBlobs(target, "serialize3", dir, nil, rand.NewSource(0))
+	assert.Error(t, err)
+}
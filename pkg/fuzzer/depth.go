@@ -0,0 +1,121 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// Depth feedback is used only if no new edge signal was found for that long.
+	depthPlateau = 10 * time.Minute
+	// Max number of programs that reached new max depth that are kept for further fuzzing.
+	maxDeepProgs = 64
+)
+
+// Once edge coverage plateaus, most executions don't produce any new signal and the fuzzer
+// has no guidance at all. depthSignal provides a weaker tiebreaker signal: the number of distinct
+// PCs a call covers. A call that covers more PCs than any execution of the same syscall before
+// got deeper into the syscall handler, so the program is kept and mutated in addition
+// to the corpus programs (in the same way as programs with kernel warnings, see warning.go).
+// The edge signal can't be used for this: only new signal is returned for most executions.
+// So during the plateau generated and mutated programs are executed with raw coverage collection
+// (see execFlags), and only these executions are scored.
+type depthSignal struct {
+	lastSignal atomic.Int64 // unix time in nanoseconds
+	mu         sync.Mutex
+	best       map[*prog.Syscall]int
+	progs      progSet
+}
+
+func newDepthSignal(fuzzer *Fuzzer) *depthSignal {
+	if !fuzzer.Config.DepthSignal {
+		return nil
+	}
+	ds := &depthSignal{
+		best:  make(map[*prog.Syscall]int),
+		progs: progSet{limit: maxDeepProgs},
+	}
+	ds.lastSignal.Store(time.Now().UnixNano())
+	return ds
+}
+
+func (ds *depthSignal) plateau() bool {
+	return time.Since(time.Unix(0, ds.lastSignal.Load())) >= depthPlateau
+}
+
+// execFlags returns additional flags for generated and mutated programs.
+func (ds *depthSignal) execFlags() flatrpc.ExecFlag {
+	if ds == nil || !ds.plateau() {
+		return 0
+	}
+	return flatrpc.ExecFlagCollectCover | flatrpc.ExecFlagDedupCover
+}
+
+// noteExec accounts an execution of p and returns whether some call reached a new max depth.
+// newSignal says whether the execution produced new edge signal, cover says whether
+// the execution collected raw coverage.
+func (ds *depthSignal) noteExec(fuzzer *Fuzzer, p *prog.Prog, info *flatrpc.ProgInfo, newSignal, cover bool) bool {
+	if newSignal {
+		ds.lastSignal.Store(time.Now().UnixNano())
+		return false
+	}
+	if !cover || !ds.plateau() {
+		return false
+	}
+	deeper := false
+	ds.mu.Lock()
+	for call, ci := range info.Calls {
+		if ci == nil || call >= len(p.Calls) {
+			continue
+		}
+		meta := p.Calls[call].Meta
+		depth := len(ci.Cover)
+		best, ok := ds.best[meta]
+		if depth <= best {
+			continue
+		}
+		ds.best[meta] = depth
+		// The first execution of a syscall sets the baseline, it's not an achievement.
+		if ok {
+			deeper = true
+			fuzzer.Logf(2, "call #%v %v reached new max depth %v (was %v)",
+				call, meta.Name, depth, best)
+		}
+	}
+	ds.mu.Unlock()
+	if !deeper {
+		return false
+	}
+	ds.progs.add(p.Clone(), fuzzer.rand())
+	fuzzer.statDeepProgs.Add(1)
+	return true
+}
+
+// depthProgRequest mutates one of the programs that reached new max depth.
+func depthProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
+	p := fuzzer.depthSignal.progs.choose(rnd)
+	if p == nil {
+		return nil
+	}
+	newP := p.Clone()
+	newP.Mutate(rnd,
+		prog.RecommendedCalls,
+		fuzzer.ChoiceTable(),
+		fuzzer.Config.NoMutateCalls,
+		fuzzer.Config.Corpus.Programs(),
+	)
+	return &queue.Request{
+		Prog:     newP,
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal | fuzzer.depthSignal.execFlags()),
+		Stat:     fuzzer.statExecDepth,
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepthSignal(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus: corpus.NewCorpus(ctx),
+	}, rand.New(testutil.RandSource(t)), target)
+	assert.Nil(t, fuzzer.depthSignal)
+	fuzzer = NewFuzzer(ctx, &Config{
+		Corpus:      corpus.NewCorpus(ctx),
+		DepthSignal: true,
+	}, rand.New(testutil.RandSource(t)), target)
+	ds := fuzzer.depthSignal
+	assert.NotNil(t, ds)
+	rnd := rand.New(testutil.RandSource(t))
+	assert.Nil(t, depthProgRequest(fuzzer, rnd))
+
+	p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	exec := func(newSignal, collectCover bool, cover ...uint64) bool {
+		return ds.noteExec(fuzzer, p, &flatrpc.ProgInfo{
+			Calls: []*flatrpc.CallInfo{{Flags: flatrpc.CallFlagExecuted, Cover: cover}},
+		}, newSignal, collectCover)
+	}
+	plateau := func() {
+		ds.lastSignal.Store(time.Now().Add(-2 * depthPlateau).UnixNano())
+	}
+	// Raw coverage is not collected before the plateau.
+	assert.Zero(t, ds.execFlags())
+	assert.False(t, exec(false, true, 0x1000, 0x2000))
+	assert.False(t, exec(false, true, 0x1000, 0x2000, 0x3000))
+	assert.Equal(t, 0, fuzzer.statDeepProgs.Val())
+
+	plateau()
+	assert.Equal(t, flatrpc.ExecFlagCollectCover|flatrpc.ExecFlagDedupCover, ds.execFlags())
+	// The first execution sets the baseline.
+	assert.False(t, exec(false, true, 0x1000, 0x2000))
+	// Executions without raw coverage are not scored.
+	assert.False(t, exec(false, false, 0x1000, 0x2000, 0x3000))
+	// New edge signal ends the plateau.
+	assert.False(t, exec(true, true, 0x1000, 0x2000, 0x3000))
+	assert.Zero(t, ds.execFlags())
+	assert.False(t, exec(false, true, 0x1000, 0x2000, 0x3000, 0x4000))
+	assert.Equal(t, 0, fuzzer.statDeepProgs.Val())
+
+	plateau()
+	assert.False(t, exec(false, true, 0x1000, 0x2000))
+	assert.True(t, exec(false, true, 0x1000, 0x2000, 0x3000, 0x4000, 0x5000))
+	assert.Equal(t, 1, fuzzer.statDeepProgs.Val())
+	req := depthProgRequest(fuzzer, rnd)
+	assert.NotNil(t, req)
+	assert.Equal(t, fuzzer.statExecDepth, req.Stat)
+	assert.NotZero(t, req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectCover)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// Fail_nth values are skipped only after they hit the same site faultSiteStable times in a row
+	// (in different programs), since the same nth may hit a different allocation
+	// in a program with different arguments.
+	faultSiteStable = 3
+	// Stable fail_nth values are still re-checked with 1/faultSiteRecheck probability.
+	faultSiteRecheck = 5
+	// Number of executions without faults used to compute the baseline coverage.
+	faultSiteBaseRuns = 3
+	// Max number of faults injected into a single call.
+	faultSiteMaxNth = 100
+)
+
+// Systematic fault injection fails the nth fault-injection-capable operation (most often
+// an allocation) in a call. Iterating over all nth for all smashed programs wastes executions:
+// the same syscall tends to reach the same fault sites in the same order regardless of arguments.
+// faultSites tracks the fault sites exercised by every syscall and which site every nth hit last time,
+// and steers fail_nth toward nth that haven't hit an already known site.
+//
+// Ideally a site would be identified by the stack of the injected fault that the kernel dumps
+// with fail_dump. But the console output is not attributed to calls/procs and is not returned
+// with execution results, so a site is identified by the error handling path instead:
+// the coverage that the call produces with the fault injected but does not produce
+// in any of faultSiteBaseRuns runs without it. Different sites that share the error handling path
+// are merged, and sites that don't change coverage are not identified at all (such nth are never skipped).
+type faultSites struct {
+	mu    sync.Mutex
+	calls map[*prog.Syscall]*callFaultSites
+}
+
+type callFaultSites struct {
+	sites map[uint64]bool
+	// Site hit by the nth fault last time and the number of times in a row it was hit, indexed by nth-1.
+	nth  []uint64
+	hits []int
+}
+
+func newFaultSites(fuzzer *Fuzzer) *faultSites {
+	if !fuzzer.Config.FaultInjection || !fuzzer.Config.Coverage {
+		return nil
+	}
+	return &faultSites{
+		calls: make(map[*prog.Syscall]*callFaultSites),
+	}
+}
+
+// skip returns whether injection of the nth fault into the call can be skipped.
+func (fs *faultSites) skip(call *prog.Syscall, nth int, rnd *rand.Rand) bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	cs := fs.calls[call]
+	if cs == nil || nth > len(cs.nth) || cs.nth[nth-1] == 0 || cs.hits[nth-1] < faultSiteStable {
+		return false
+	}
+	return rnd.Intn(faultSiteRecheck) != 0
+}
+
+// note records that injection of the nth fault into the call hit the site,
+// and returns whether the site is new.
+func (fs *faultSites) note(call *prog.Syscall, nth int, site uint64) bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	cs := fs.calls[call]
+	if cs == nil {
+		cs = &callFaultSites{sites: make(map[uint64]bool)}
+		fs.calls[call] = cs
+	}
+	for len(cs.nth) < nth {
+		cs.nth = append(cs.nth, 0)
+		cs.hits = append(cs.hits, 0)
+	}
+	if cs.nth[nth-1] == site {
+		cs.hits[nth-1]++
+	} else {
+		cs.nth[nth-1] = site
+		cs.hits[nth-1] = 1
+	}
+	if cs.sites[site] {
+		return false
+	}
+	cs.sites[site] = true
+	return true
+}
+
+// faultSite returns the site identifier for the call coverage with the fault injected
+// given the coverage without it, or 0 if the site can't be identified
+// (e.g. coverage is not collected, or the fault did not change the execution path).
+func faultSite(base, cover []uint64) uint64 {
+	seen := make(map[uint64]bool, len(base))
+	for _, pc := range base {
+		seen[pc] = true
+	}
+	var diff []uint64
+	for _, pc := range cover {
+		if !seen[pc] {
+			seen[pc] = true
+			diff = append(diff, pc)
+		}
+	}
+	if len(diff) == 0 {
+		return 0
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i] < diff[j] })
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, pc := range diff {
+		binary.LittleEndian.PutUint64(buf[:], pc)
+		h.Write(buf[:])
+	}
+	return max(h.Sum64(), 1)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultSite(t *testing.T) {
+	base := []uint64{1, 2, 3, 4}
+	assert.Zero(t, faultSite(base, []uint64{1, 2, 3, 4}))
+	assert.Zero(t, faultSite(base, []uint64{1, 2}))
+	assert.Zero(t, faultSite(nil, nil))
+	site := faultSite(base, []uint64{1, 2, 10, 11})
+	assert.NotZero(t, site)
+	// The order of PCs and the common part does not matter.
+	assert.Equal(t, site, faultSite(base, []uint64{11, 10, 10, 4, 3}))
+	assert.NotEqual(t, site, faultSite(base, []uint64{1, 2, 10, 12}))
+}
+
+func TestFaultSites(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus:         corpus.NewCorpus(ctx),
+		FaultInjection: true,
+	}, rand.New(testutil.RandSource(t)), target)
+	assert.Nil(t, fuzzer.faultSites)
+	fuzzer = NewFuzzer(ctx, &Config{
+		Corpus:         corpus.NewCorpus(ctx),
+		Coverage:       true,
+		FaultInjection: true,
+	}, rand.New(testutil.RandSource(t)), target)
+	fs := fuzzer.faultSites
+	assert.NotNil(t, fs)
+
+	rnd := rand.New(testutil.RandSource(t))
+	call0, call1 := target.Syscalls[0], target.Syscalls[1]
+	skipped := func(call *prog.Syscall, nth int) int {
+		n := 0
+		for i := 0; i < 100; i++ {
+			if fs.skip(call, nth, rnd) {
+				n++
+			}
+		}
+		return n
+	}
+	assert.Zero(t, skipped(call0, 1))
+	assert.True(t, fs.note(call0, 1, 100))
+	assert.True(t, fs.note(call0, 2, 200))
+	assert.False(t, fs.note(call0, 3, 100))
+	assert.True(t, fs.note(call0, 5, 300))
+	// Nth are not skipped until they hit the same site several times in a row.
+	assert.Zero(t, skipped(call0, 1))
+	for i := 1; i < faultSiteStable; i++ {
+		for nth, site := range map[int]uint64{1: 100, 3: 100, 5: 300} {
+			assert.False(t, fs.note(call0, nth, site))
+		}
+	}
+	// Known sites are mostly skipped, but are re-checked from time to time.
+	assert.Greater(t, skipped(call0, 1), 60)
+	assert.Less(t, skipped(call0, 1), 100)
+	assert.Greater(t, skipped(call0, 3), 60)
+	assert.Greater(t, skipped(call0, 5), 60)
+	// Unknown nth and other calls are not skipped.
+	assert.Zero(t, skipped(call0, 2))
+	assert.Zero(t, skipped(call0, 4))
+	assert.Zero(t, skipped(call0, 6))
+	assert.Zero(t, skipped(call1, 1))
+	// Hitting a different site resets the streak.
+	assert.True(t, fs.note(call0, 1, 400))
+	assert.Zero(t, skipped(call0, 1))
+	// Sites are tracked per call.
+	assert.True(t, fs.note(call1, 1, 100))
+}
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/syzkaller/pkg/corpus"
@@ -32,6 +33,27 @@ type Fuzzer struct {
 	ctProgs      int
 	ctMu         sync.Mutex // TODO: use RWLock.
 	ctRegenerate chan struct{}
+	// Calls the fuzzer focuses on (see SetFocus), protected by ctMu.
+	focus    map[*prog.Syscall]bool
+	focusGen int
+	// Mutation operator weights (see SetMutationCrashes and tuneMutations)
+	// and the inputs they are calculated from, protected by ctMu.
+	mutateOpts      prog.MutateOpts
+	mutationCrashes map[prog.MutationOp]int
+	yield           mutationYield
+
+	racyProgs    progSet
+	warningProgs progSet
+	longProgs    *longProgs
+	slowProgs    *slowProgs
+	faultSites   *faultSites
+	depthSignal  *depthSignal
+	lastSuspend  atomic.Int64 // unix time in nanoseconds
+	lastWarnLog  atomic.Int64 // unix time in nanoseconds
+
+	// Serialized programs of the running triage jobs (see PendingTriage).
+	triageMu sync.Mutex
+	triaging map[*triageJob][]byte
 
 	execQueues
 }
@@ -55,10 +77,24 @@ func NewFuzzer(ctx context.Context, cfg *Config, rnd *rand.Rand,
 		// We're okay to lose some of the messages -- if we are already
 		// regenerating the table, we don't want to repeat it right away.
 		ctRegenerate: make(chan struct{}),
+		mutateOpts:   prog.DefaultMutateOpts,
+		racyProgs:    progSet{limit: maxRacyProgs},
+		warningProgs: progSet{limit: maxWarningProgs},
+		triaging:     make(map[*triageJob][]byte),
 	}
+	f.mutateOpts.UringCalls = cfg.UringCalls
+	f.mutateOpts.CompatCalls = cfg.CompatCalls
+	f.longProgs = newLongProgs(f)
+	f.slowProgs = newSlowProgs(f)
+	f.faultSites = newFaultSites(f)
+	f.depthSignal = newDepthSignal(f)
 	f.execQueues = newExecQueues(f)
 	f.updateChoiceTable(nil)
 	go f.choiceTableUpdater()
+	if cfg.MutationTuning {
+		f.createMutationWeightStats()
+		go f.mutationTuner()
+	}
 	if cfg.Debug {
 		go f.logCurrentStats()
 	}
@@ -68,7 +104,7 @@ func NewFuzzer(ctx context.Context, cfg *Config, rnd *rand.Rand,
 type execQueues struct {
 	smashQueue           *queue.PlainQueue
 	triageQueue          *queue.DynamicOrderer
-	candidateQueue       *queue.PlainQueue
+	candidateQueue       *queue.DeadlineQueue
 	triageCandidateQueue *queue.DynamicOrderer
 	source               queue.Source
 }
@@ -76,17 +112,24 @@ type execQueues struct {
 func newExecQueues(fuzzer *Fuzzer) execQueues {
 	ret := execQueues{
 		triageCandidateQueue: queue.DynamicOrder(),
-		candidateQueue:       queue.PlainWithStat(fuzzer.StatCandidates),
+		candidateQueue:       queue.Deadline(fuzzer.StatCandidates, fuzzer.statCandidatesDeferred),
 		triageQueue:          queue.DynamicOrder(),
 		smashQueue:           queue.Plain(),
 	}
+	var candidates queue.Source = ret.candidateQueue
+	if n := fuzzer.Config.CandidateInterleave; n > 0 {
+		// Let normal fuzzing proceed while a large corpus is being triaged.
+		candidates = queue.Alternate(candidates, n)
+	}
 	// Sources are listed in the order, in which they will be polled.
 	ret.source = queue.Order(
 		ret.triageCandidateQueue,
-		ret.candidateQueue,
+		candidates,
 		ret.triageQueue,
 		// Alternate smash jobs with exec/fuzz once in 3 times.
 		queue.Alternate(ret.smashQueue, 3),
+		// Candidates that missed their deadline get every 2nd of the remaining executions.
+		queue.Alternate(ret.candidateQueue.Deferred(), 2),
 		queue.Callback(fuzzer.genFuzz),
 	)
 	return ret
@@ -122,16 +165,56 @@ func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags
 	// it may result it concurrent modification of req.Prog.
 	// If we are already triaging this exact prog, this is flaky coverage.
 	if req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectSignal > 0 && res.Info != nil && !inTriage {
+		newSignal := false
 		for call, info := range res.Info.Calls {
-			fuzzer.triageProgCall(req.Prog, info, call, flags)
+			newSignal = fuzzer.triageProgCall(req.Prog, info, call, flags) || newSignal
+		}
+		newSignal = fuzzer.triageProgCall(req.Prog, res.Info.Extra, -1, flags) || newSignal
+		fuzzer.accountMutation(req.Prog, newSignal)
+		if fuzzer.depthSignal != nil {
+			fuzzer.depthSignal.noteExec(fuzzer, req.Prog, res.Info, newSignal,
+				req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectCover != 0)
+		}
+	}
+	if res.Info != nil && flags&progInRace == 0 && hasRaceCandidate(res.Info) {
+		fuzzer.statRaceCandidates.Add(1)
+		if fuzzer.Config.RaceFeedback && fuzzer.Config.Collide &&
+			fuzzer.statJobsRace.Val() < maxRaceJobs {
+			fuzzer.startJob(fuzzer.statJobsRace, &raceJob{p: req.Prog.Clone()})
 		}
-		fuzzer.triageProgCall(req.Prog, res.Info.Extra, -1, flags)
 	}
 	if res.Info != nil {
 		fuzzer.statExecTime.Add(int(res.Info.Elapsed / 1e6))
+		warned := false
+		for call, info := range res.Info.Calls {
+			if info != nil && info.Flags&flatrpc.CallFlagKernelWarning != 0 {
+				warned = true
+				fuzzer.statKernelWarnings.Add(1)
+				if fuzzer.allowWarningLog() {
+					fuzzer.Logf(1, "call #%v %v probably triggered a kernel bug report in:\n%s",
+						call, req.Prog.CallName(call), req.Prog.Serialize())
+				}
+			}
+		}
+		if warned {
+			fuzzer.AddWarningProg(req.Prog)
+		}
 	}
 }
 
+// Kernels that WARN often would flood the log with programs, so they are logged at most once per interval.
+const warningLogInterval = time.Minute
+
+// allowWarningLog returns whether a program with a CallFlagKernelWarning call should be logged.
+func (fuzzer *Fuzzer) allowWarningLog() bool {
+	now := time.Now().UnixNano()
+	last := fuzzer.lastWarnLog.Load()
+	if now-last < int64(warningLogInterval) {
+		return false
+	}
+	return fuzzer.lastWarnLog.CompareAndSwap(last, now)
+}
+
 type Config struct {
 	Debug          bool
 	Corpus         *corpus.Corpus
@@ -145,19 +228,59 @@ type Config struct {
 	NoMutateCalls  map[int]bool
 	FetchRawCover  bool
 	NewInputFilter func(call string) bool
+	// New signal is considered stable if it reproduces in DeflakeRuns
+	// out of DeflakeMaxRuns re-executions (default: 3 out of 5).
+	DeflakeRuns    int
+	DeflakeMaxRuns int
+	// Re-execute programs that produced KCSAN data race candidates to confirm
+	// the races, and fuzz the programs with confirmed races more.
+	RaceFeedback bool
+	// If non-zero, every CandidateInterleave-th request is served from the normal fuzzing
+	// queues even if there are candidates left, so that fuzzing does not wait
+	// for the triage of the whole corpus.
+	CandidateInterleave int
+	// Candidates that were not executed within CandidateDeadline after they were added
+	// are deferred behind the triage and smash jobs (0 means no deadline).
+	// They are still executed, so that the corpus is never lost.
+	CandidateDeadline time.Duration
+	// Periodically re-weight the mutation operators according to the rate of new signal
+	// found by the programs they produced (see "mutation" stats).
+	MutationTuning bool
+	// Mutate more the programs that triggered non-fatal kernel warnings
+	// (see CallFlagKernelWarning and AddWarningProg).
+	WarningFeedback bool
+	// The machine supports io_uring, so calls can be switched to io_uring submission.
+	UringCalls bool
+	// The machine supports the compat syscall entry, so programs can be switched to it.
+	CompatCalls bool
+	// Smashed programs are also executed with a suspend/resume cycle of the machine
+	// right before the smashed call (see prog.CallProps.Suspend).
+	// The cycle stalls the whole machine, so it's done at most once per suspendInterval.
+	SuspendResume bool
+	// Long-program mode: keep LongProgs long-lived states made of StateCalls
+	// and execute fuzzed programs on top of them (see longprog.go).
+	LongProgs  int
+	StateCalls map[*prog.Syscall]bool
+	// Corpus programs whose mutants consistently take longer than SlowProgBudget to execute
+	// are chosen for mutation less frequently (see slowprog.go), 0 disables the demotion.
+	SlowProgBudget time.Duration
+	// Once edge coverage plateaus, mutate more the programs whose calls cover more distinct
+	// PCs than any execution of the same syscall before (see depth.go).
+	DepthSignal bool
 }
 
-func (fuzzer *Fuzzer) triageProgCall(p *prog.Prog, info *flatrpc.CallInfo, call int, flags ProgTypes) {
+// triageProgCall starts triage of the call if it produced new signal, and returns whether it did.
+func (fuzzer *Fuzzer) triageProgCall(p *prog.Prog, info *flatrpc.CallInfo, call int, flags ProgTypes) bool {
 	if info == nil {
-		return
+		return false
 	}
 	prio := signalPrio(p, info, call)
 	newMaxSignal := fuzzer.Cover.addRawMaxSignal(info.Signal, prio)
 	if newMaxSignal.Empty() {
-		return
+		return false
 	}
 	if !fuzzer.Config.NewInputFilter(p.CallName(call)) {
-		return
+		return false
 	}
 	fuzzer.Logf(2, "found new signal in call %d in %s", call, p)
 
@@ -165,14 +288,42 @@ func (fuzzer *Fuzzer) triageProgCall(p *prog.Prog, info *flatrpc.CallInfo, call
 	if flags&progCandidate > 0 {
 		queue = fuzzer.triageCandidateQueue
 	}
-	fuzzer.startJob(fuzzer.statJobsTriage, &triageJob{
+	job := &triageJob{
 		p:         p.Clone(),
 		call:      call,
 		info:      info,
 		newSignal: newMaxSignal,
 		flags:     flags,
 		queue:     queue.Append(),
-	})
+	}
+	fuzzer.triageMu.Lock()
+	fuzzer.triaging[job] = job.p.Serialize()
+	fuzzer.triageMu.Unlock()
+	fuzzer.startJob(fuzzer.statJobsTriage, job)
+	return true
+}
+
+func (fuzzer *Fuzzer) triageDone(job *triageJob) {
+	fuzzer.triageMu.Lock()
+	delete(fuzzer.triaging, job)
+	fuzzer.triageMu.Unlock()
+}
+
+// PendingTriage returns programs that produced new signal, but are not triaged yet.
+// Their signal is already in max signal, so if the fuzzer is stopped now,
+// they need to be triaged again on restart as unminimized candidates.
+func (fuzzer *Fuzzer) PendingTriage() [][]byte {
+	fuzzer.triageMu.Lock()
+	defer fuzzer.triageMu.Unlock()
+	dedup := make(map[string]bool)
+	var res [][]byte
+	for _, data := range fuzzer.triaging {
+		if !dedup[string(data)] {
+			dedup[string(data)] = true
+			res = append(res, data)
+		}
+	}
+	return res
 }
 
 func signalPrio(p *prog.Prog, info *flatrpc.CallInfo, call int) (prio uint8) {
@@ -198,7 +349,19 @@ func (fuzzer *Fuzzer) genFuzz() *queue.Request {
 	}
 	var req *queue.Request
 	rnd := fuzzer.rand()
-	if rnd.Float64() < mutateRate {
+	if fuzzer.Config.RaceFeedback && rnd.Intn(50) == 0 {
+		req = raceProgRequest(fuzzer, rnd)
+	}
+	if req == nil && fuzzer.Config.WarningFeedback && rnd.Intn(50) == 0 {
+		req = warningProgRequest(fuzzer, rnd)
+	}
+	if req == nil && fuzzer.depthSignal != nil && rnd.Intn(50) == 0 {
+		req = depthProgRequest(fuzzer, rnd)
+	}
+	if req == nil && fuzzer.longProgs != nil && rnd.Intn(longProgRate) == 0 {
+		req = longProgRequest(fuzzer, rnd)
+	}
+	if req == nil && rnd.Float64() < mutateRate {
 		req = mutateProgRequest(fuzzer, rnd)
 	}
 	if req == nil {
@@ -240,12 +403,19 @@ type Candidate struct {
 	Prog      *prog.Prog
 	Smashed   bool
 	Minimized bool
+	// Candidates with lower Prio are executed first.
+	Prio int
 }
 
 func (fuzzer *Fuzzer) AddCandidates(candidates []Candidate) {
+	var deadline time.Time
+	if fuzzer.Config.CandidateDeadline != 0 {
+		deadline = time.Now().Add(fuzzer.Config.CandidateDeadline)
+	}
 	for _, candidate := range candidates {
 		req, flags := candidateRequest(fuzzer, candidate)
-		fuzzer.enqueue(fuzzer.candidateQueue, req, flags)
+		fuzzer.prepare(req, flags)
+		fuzzer.candidateQueue.SubmitDeadline(req, candidate.Prio, deadline)
 	}
 }
 
@@ -256,16 +426,119 @@ func (fuzzer *Fuzzer) rand() *rand.Rand {
 }
 
 func (fuzzer *Fuzzer) updateChoiceTable(programs []*prog.Prog) {
-	newCt := fuzzer.target.BuildChoiceTable(programs, fuzzer.Config.EnabledCalls)
+	fuzzer.ctMu.Lock()
+	focus, focusGen := fuzzer.focus, fuzzer.focusGen
+	fuzzer.ctMu.Unlock()
+
+	numProgs := len(programs)
+	enabled := fuzzer.Config.EnabledCalls
+	if focus != nil {
+		enabled = focus
+		var focused []*prog.Prog
+		for _, p := range programs {
+			if onlyCalls(p, focus) {
+				focused = append(focused, p)
+			}
+		}
+		programs = focused
+	}
+	newCt := fuzzer.target.BuildChoiceTable(programs, enabled)
 
 	fuzzer.ctMu.Lock()
 	defer fuzzer.ctMu.Unlock()
-	if len(programs) >= fuzzer.ctProgs {
-		fuzzer.ctProgs = len(programs)
+	if numProgs >= fuzzer.ctProgs && focusGen == fuzzer.focusGen {
+		fuzzer.ctProgs = numProgs
 		fuzzer.ct = newCt
 	}
 }
 
+// SetFocus makes the fuzzer generate only the given calls (and calls that create their resources),
+// and prefer mutation of corpus programs that contain them. Nil calls reset the focus.
+// Returns the number of calls the fuzzer focuses on (0 if the focus was reset).
+func (fuzzer *Fuzzer) SetFocus(calls map[*prog.Syscall]bool) int {
+	var focus map[*prog.Syscall]bool
+	if len(calls) != 0 {
+		enabled := fuzzer.Config.EnabledCalls
+		if enabled == nil {
+			enabled = make(map[*prog.Syscall]bool)
+			for _, c := range fuzzer.target.Syscalls {
+				enabled[c] = true
+			}
+		}
+		focus, _ = fuzzer.target.TransitivelyEnabledCalls(fuzzer.target.WithResourceCtors(calls, enabled))
+		for c := range focus {
+			if c.Attrs.NoGenerate || c.Attrs.Disabled {
+				delete(focus, c)
+			}
+		}
+		if len(focus) == 0 {
+			focus = nil
+		}
+	}
+	fuzzer.ctMu.Lock()
+	fuzzer.focus = focus
+	fuzzer.focusGen++
+	// Force regeneration of the choice table.
+	fuzzer.ctProgs = 0
+	fuzzer.ctMu.Unlock()
+	fuzzer.updateChoiceTable(fuzzer.Config.Corpus.Programs())
+	return len(focus)
+}
+
+// SetMutationCrashes adjusts the mutation operator weights according to the number
+// of crashes attributed to each mutation op (see prog.MutateOpts.WeightByCrashes).
+func (fuzzer *Fuzzer) SetMutationCrashes(crashes map[prog.MutationOp]int) {
+	fuzzer.ctMu.Lock()
+	defer fuzzer.ctMu.Unlock()
+	fuzzer.mutationCrashes = crashes
+	fuzzer.updateMutateOpts()
+}
+
+func (fuzzer *Fuzzer) mutationOpts() prog.MutateOpts {
+	fuzzer.ctMu.Lock()
+	defer fuzzer.ctMu.Unlock()
+	return fuzzer.mutateOpts
+}
+
+func (fuzzer *Fuzzer) focusCalls() map[*prog.Syscall]bool {
+	fuzzer.ctMu.Lock()
+	defer fuzzer.ctMu.Unlock()
+	return fuzzer.focus
+}
+
+// chooseProgram chooses a corpus program for mutation.
+// If the fuzzer is focused on some calls, programs that contain them are preferred.
+// Demoted slow programs are avoided.
+func (fuzzer *Fuzzer) chooseProgram(rnd *rand.Rand) *prog.Prog {
+	p := fuzzer.Config.Corpus.ChooseProgram(rnd)
+	focus := fuzzer.focusCalls()
+	for i := 0; focus != nil && p != nil && i < 10 && !anyCall(p, focus); i++ {
+		p = fuzzer.Config.Corpus.ChooseProgram(rnd)
+	}
+	for i := 0; fuzzer.slowProgs != nil && p != nil && i < 3 && fuzzer.slowProgs.skip(p, rnd); i++ {
+		p = fuzzer.Config.Corpus.ChooseProgram(rnd)
+	}
+	return p
+}
+
+func onlyCalls(p *prog.Prog, calls map[*prog.Syscall]bool) bool {
+	for _, c := range p.Calls {
+		if !calls[c.Meta] {
+			return false
+		}
+	}
+	return true
+}
+
+func anyCall(p *prog.Prog, calls map[*prog.Syscall]bool) bool {
+	for _, c := range p.Calls {
+		if calls[c.Meta] {
+			return true
+		}
+	}
+	return false
+}
+
 func (fuzzer *Fuzzer) choiceTableUpdater() {
 	for {
 		select {
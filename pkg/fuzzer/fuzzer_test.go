@@ -181,6 +181,87 @@ func TestRotate(t *testing.T) {
 	assert.Equal(t, 700, minus.Len())
 }
 
+func TestFocus(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	compare := target.SyscallMap["syz_compare"]
+	fuzzer1 := target.SyscallMap["syz_test_fuzzer1"]
+	corpusObj := corpus.NewCorpus(ctx)
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus: corpusObj,
+		EnabledCalls: map[*prog.Syscall]bool{
+			compare: true,
+			fuzzer1: true,
+		},
+	}, rand.New(testutil.RandSource(t)), target)
+	p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	corpusObj.Save(corpus.NewInput{Prog: p, Signal: signal.FromRaw([]uint64{1}, 0)})
+
+	assert.Equal(t, 1, fuzzer.SetFocus(map[*prog.Syscall]bool{fuzzer1: true}))
+	rnd := rand.New(testutil.RandSource(t))
+	for i := 0; i < 100; i++ {
+		for _, c := range target.Generate(rnd, 5, fuzzer.ChoiceTable()).Calls {
+			assert.Equal(t, fuzzer1, c.Meta)
+		}
+		// Corpus programs with unfocused calls must still be mutable.
+		p.Clone().Mutate(rnd, 10, fuzzer.ChoiceTable(), nil, nil)
+	}
+
+	assert.Equal(t, 0, fuzzer.SetFocus(nil))
+	generated := make(map[*prog.Syscall]bool)
+	for i := 0; i < 100; i++ {
+		for _, c := range target.Generate(rnd, 5, fuzzer.ChoiceTable()).Calls {
+			generated[c.Meta] = true
+		}
+	}
+	assert.True(t, generated[compare])
+}
+
+func TestCandidateInterleave(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus:              corpus.NewCorpus(ctx),
+		EnabledCalls:        map[*prog.Syscall]bool{target.SyscallMap["syz_compare"]: true},
+		CandidateInterleave: 3,
+	}, rand.New(testutil.RandSource(t)), target)
+	var candidates []Candidate
+	for i := 0; i < 4; i++ {
+		p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+		assert.NoError(t, err)
+		candidates = append(candidates, Candidate{Prog: p, Prio: 4 - i})
+	}
+	fuzzer.AddCandidates(candidates)
+	assert.Equal(t, 4, fuzzer.StatCandidates.Val())
+
+	// Every 3rd request must come from normal fuzzing.
+	var got []*prog.Prog
+	for i := 0; i < 6; i++ {
+		req := fuzzer.Next()
+		if i%3 == 2 {
+			assert.NotEqual(t, fuzzer.statExecCandidate, req.Stat)
+			continue
+		}
+		assert.Equal(t, fuzzer.statExecCandidate, req.Stat)
+		got = append(got, req.Prog)
+	}
+	for i, p := range got {
+		assert.Equal(t, candidates[len(candidates)-1-i].Prog, p)
+	}
+	assert.Equal(t, 0, fuzzer.StatCandidates.Val())
+}
+
 // Based on the example from Go documentation.
 var crc32q = crc32.MakeTable(0xD5828281)
 
@@ -335,3 +416,93 @@ func checkGoroutineLeaks() {
 		panic(err)
 	}
 }
+
+func TestMutationTuning(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus:         corpus.NewCorpus(ctx),
+		MutationTuning: true,
+	}, rand.New(testutil.RandSource(t)), target)
+
+	p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	rnd := rand.New(testutil.RandSource(t))
+	for i := 0; i < 100; i++ {
+		newP := p.Clone()
+		newP.Mutate(rnd, 10, fuzzer.ChoiceTable(), nil, nil)
+		fuzzer.accountMutation(newP, i%10 == 0)
+	}
+	execs := 0
+	for _, stat := range fuzzer.statMutationExecs {
+		if stat != nil {
+			execs += stat.Val()
+		}
+	}
+	assert.NotZero(t, execs)
+
+	// Splice programs find new signal 10 times more often than the others.
+	fuzzer.statMutationExecs[prog.MutationSplice].Add(10000)
+	fuzzer.statMutationSignal[prog.MutationSplice].Add(1000)
+	fuzzer.statMutationExecs[prog.MutationInt].Add(10000)
+	fuzzer.statMutationSignal[prog.MutationInt].Add(100)
+	fuzzer.tuneMutations()
+	opts := fuzzer.mutationOpts()
+	assert.Greater(t, opts.SpliceWeight, prog.DefaultMutateOpts.SpliceWeight)
+	assert.Less(t, opts.MutateArgWeight, prog.DefaultMutateOpts.MutateArgWeight)
+
+	// Weights follow recent yield: old statistics decay.
+	for i := 0; i < 10; i++ {
+		fuzzer.statMutationExecs[prog.MutationSplice].Add(10000)
+		fuzzer.statMutationExecs[prog.MutationInt].Add(10000)
+		fuzzer.statMutationSignal[prog.MutationInt].Add(1000)
+		fuzzer.tuneMutations()
+	}
+	opts = fuzzer.mutationOpts()
+	assert.Less(t, opts.SpliceWeight, prog.DefaultMutateOpts.SpliceWeight)
+	assert.Greater(t, opts.MutateArgWeight, prog.DefaultMutateOpts.MutateArgWeight)
+}
+
+func TestKernelWarningFlag(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logged []int
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus: corpus.NewCorpus(ctx),
+		Logf: func(level int, msg string, args ...interface{}) {
+			if strings.Contains(msg, "kernel bug report") {
+				logged = append(logged, level)
+			}
+		},
+	}, rand.New(testutil.RandSource(t)), target)
+	p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	req := &queue.Request{Prog: p}
+	warned := &queue.Result{Info: &flatrpc.ProgInfo{
+		Calls: []*flatrpc.CallInfo{
+			{Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagKernelWarning},
+			{Flags: flatrpc.CallFlagExecuted},
+		},
+	}}
+	fuzzer.processResult(req, &queue.Result{Info: &flatrpc.ProgInfo{
+		Calls: []*flatrpc.CallInfo{{Flags: flatrpc.CallFlagExecuted}},
+	}}, 0)
+	assert.Equal(t, 0, fuzzer.statKernelWarnings.Val())
+	for i := 0; i < 10; i++ {
+		fuzzer.processResult(req, warned, 0)
+	}
+	assert.Equal(t, 10, fuzzer.statKernelWarnings.Val())
+	// The program is logged at a debug level and only once per warningLogInterval.
+	assert.Equal(t, []int{1}, logged)
+	fuzzer.lastWarnLog.Store(time.Now().Add(-warningLogInterval).UnixNano())
+	fuzzer.processResult(req, warned, 0)
+	assert.Equal(t, []int{1, 1}, logged)
+}
@@ -6,6 +6,7 @@ package fuzzer
 import (
 	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/google/syzkaller/pkg/corpus"
 	"github.com/google/syzkaller/pkg/cover"
@@ -27,6 +28,7 @@ const (
 	progMinimized
 	progSmashed
 	progInTriage
+	progInRace
 )
 
 func genProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
@@ -35,28 +37,40 @@ func genProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
 		fuzzer.ChoiceTable())
 	return &queue.Request{
 		Prog:     p,
-		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal | fuzzer.depthSignal.execFlags()),
 		Stat:     fuzzer.statExecGenerate,
 	}
 }
 
 func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
-	p := fuzzer.Config.Corpus.ChooseProgram(rnd)
+	p := fuzzer.chooseProgram(rnd)
 	if p == nil {
 		return nil
 	}
 	newP := p.Clone()
-	newP.Mutate(rnd,
+	newP.MutateWithOpts(rnd,
 		prog.RecommendedCalls,
 		fuzzer.ChoiceTable(),
 		fuzzer.Config.NoMutateCalls,
 		fuzzer.Config.Corpus.Programs(),
+		fuzzer.mutationOpts(),
 	)
-	return &queue.Request{
+	req := &queue.Request{
 		Prog:     newP,
-		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal | fuzzer.depthSignal.execFlags()),
 		Stat:     fuzzer.statExecFuzz,
 	}
+	req.OnDone(func(_ *queue.Request, res *queue.Result) bool {
+		if res.Status == queue.Crashed {
+			// Crash proximity is one of the inputs for the corpus energy schedule.
+			fuzzer.Config.Corpus.NoteCrash(p)
+		}
+		if fuzzer.slowProgs != nil {
+			fuzzer.slowProgs.noteExec(fuzzer, p, res)
+		}
+		return true
+	})
+	return req
 }
 
 func candidateRequest(fuzzer *Fuzzer, input Candidate) (*queue.Request, ProgTypes) {
@@ -97,13 +111,22 @@ func (job *triageJob) execute(req *queue.Request, flags ProgTypes) *queue.Result
 func (job *triageJob) run(fuzzer *Fuzzer) {
 	fuzzer.statNewInputs.Add(1)
 	job.fuzzer = fuzzer
+	defer fuzzer.triageDone(job)
 
 	callName := fmt.Sprintf("call #%v %v", job.call, job.p.CallName(job.call))
 	fuzzer.Logf(3, "triaging input for %v (new signal=%v)", callName, job.newSignal.Len())
 
 	// Compute input coverage and non-flaky signal for minimization.
 	info, stop := job.deflake(job.execute, fuzzer.statExecTriage, fuzzer.Config.FetchRawCover)
-	if stop || info.newStableSignal.Empty() {
+	if stop {
+		return
+	}
+	if flaky := job.newSignal.Len() - info.newStableSignal.Len(); flaky > 0 {
+		fuzzer.statFlakySignal.Add(flaky)
+	}
+	if info.newStableSignal.Empty() {
+		fuzzer.statTriageFlaky.Add(1)
+		fuzzer.Logf(3, "new signal for %v is flaky", callName)
 		return
 	}
 	if job.flags&progMinimized == 0 {
@@ -116,14 +139,18 @@ func (job *triageJob) run(fuzzer *Fuzzer) {
 		return
 	}
 	fuzzer.Logf(2, "added new input for %v to the corpus: %s", callName, job.p)
+	// Programs mutated from the corpus program must be attributed only to their own mutations.
+	// Note: job.p may be still referenced by the manager for crash attribution, so it's not modified.
+	p := job.p.Clone()
+	p.ResetProvenance()
 	if job.flags&progSmashed == 0 {
 		fuzzer.startJob(fuzzer.statJobsSmash, &smashJob{
-			p:    job.p.Clone(),
+			p:    p.Clone(),
 			call: job.call,
 		})
 	}
 	input := corpus.NewInput{
-		Prog:     job.p,
+		Prog:     p,
 		Call:     job.call,
 		Signal:   info.stableSignal,
 		Cover:    info.cover.Serialize(),
@@ -146,10 +173,8 @@ func (job *triageJob) deflake(exec func(*queue.Request, ProgTypes) *queue.Result
 	// to 3 out of 5 runs.
 	// By binomial distribution, a program that reproduces 80% of time will pass deflake()
 	// with a 94% probability. If it reproduces 90% of time, it passes in 99% of cases.
-	const (
-		needRuns = 3
-		maxRuns  = 5
-	)
+	// The policy can be changed with Config.DeflakeRuns/DeflakeMaxRuns.
+	needRuns, maxRuns := job.deflakeRuns()
 	signals := make([]signal.Signal, needRuns)
 	for i := 0; i < maxRuns; i++ {
 		if job.newSignal.IntersectsWith(signals[needRuns-1]) {
@@ -192,6 +217,25 @@ func (job *triageJob) deflake(exec func(*queue.Request, ProgTypes) *queue.Result
 	return
 }
 
+const (
+	defaultDeflakeRuns    = 3
+	defaultDeflakeMaxRuns = 5
+)
+
+func (job *triageJob) deflakeRuns() (needRuns, maxRuns int) {
+	needRuns, maxRuns = defaultDeflakeRuns, defaultDeflakeMaxRuns
+	if job.fuzzer != nil {
+		cfg := job.fuzzer.Config
+		if cfg.DeflakeRuns > 0 {
+			needRuns = cfg.DeflakeRuns
+		}
+		if cfg.DeflakeMaxRuns > 0 {
+			maxRuns = cfg.DeflakeMaxRuns
+		}
+	}
+	return needRuns, max(needRuns, maxRuns)
+}
+
 func (job *triageJob) minimize(newSignal signal.Signal) (stop bool) {
 	const minimizeAttempts = 3
 	job.p, job.call = prog.Minimize(job.p, job.call, prog.MinimizeParams{},
@@ -270,10 +314,11 @@ func (job *smashJob) run(fuzzer *Fuzzer) {
 	rnd := fuzzer.rand()
 	for i := 0; i < iters; i++ {
 		p := job.p.Clone()
-		p.Mutate(rnd, prog.RecommendedCalls,
+		p.MutateWithOpts(rnd, prog.RecommendedCalls,
 			fuzzer.ChoiceTable(),
 			fuzzer.Config.NoMutateCalls,
-			fuzzer.Config.Corpus.Programs())
+			fuzzer.Config.Corpus.Programs(),
+			fuzzer.mutationOpts())
 		result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
 			Prog:     p,
 			ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
@@ -293,7 +338,10 @@ func (job *smashJob) run(fuzzer *Fuzzer) {
 		}
 	}
 	if fuzzer.Config.FaultInjection && job.call >= 0 {
-		job.faultInjection(fuzzer)
+		job.faultInjection(fuzzer, rnd)
+	}
+	if fuzzer.Config.SuspendResume && job.call >= 0 && fuzzer.allowSuspend() {
+		job.suspendResume(fuzzer, rnd)
 	}
 }
 
@@ -319,15 +367,47 @@ func randomCollide(origP *prog.Prog, rnd *rand.Rand) *prog.Prog {
 	return p
 }
 
-func (job *smashJob) faultInjection(fuzzer *Fuzzer) {
-	for nth := 1; nth <= 100; nth++ {
+func (job *smashJob) faultInjection(fuzzer *Fuzzer, rnd *rand.Rand) {
+	p := job.p.Clone()
+	// Faults can be injected only into calls executed by the test process.
+	p.Calls[job.call].Props.Role = prog.RoleMain
+	if p.Calls[job.call].Props.TimeJump == prog.TimeJumpNamespace {
+		p.Calls[job.call].Props.TimeJump = prog.TimeJumpNone
+	}
+	meta := p.Calls[job.call].Meta
+	sites := fuzzer.faultSites
+	var opts flatrpc.ExecOpts
+	var base []uint64
+	if sites != nil {
+		// Coverage of the call without faults is used to identify fault sites.
+		// Union of several runs is used, otherwise flaky coverage (e.g. from interrupts
+		// or lazy initialization) would be taken for the error handling path.
+		opts = setFlags(flatrpc.ExecFlagCollectCover)
+		for i := 0; i < faultSiteBaseRuns; i++ {
+			result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
+				Prog:     p,
+				ExecOpts: opts,
+				Stat:     fuzzer.statExecSmash,
+			})
+			if result.Stop() {
+				return
+			}
+			base = append(base, callCover(result.Info, job.call)...)
+		}
+	}
+	for nth := 1; nth <= faultSiteMaxNth; nth++ {
+		if sites != nil && sites.skip(meta, nth, rnd) {
+			fuzzer.statFaultSitesSkipped.Add(1)
+			continue
+		}
 		fuzzer.Logf(2, "injecting fault into call %v, step %v",
 			job.call, nth)
-		newProg := job.p.Clone()
+		newProg := p.Clone()
 		newProg.Calls[job.call].Props.FailNth = nth
 		result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
-			Prog: newProg,
-			Stat: fuzzer.statExecSmash,
+			Prog:     newProg,
+			ExecOpts: opts,
+			Stat:     fuzzer.statExecSmash,
 		})
 		if result.Stop() {
 			return
@@ -337,9 +417,50 @@ func (job *smashJob) faultInjection(fuzzer *Fuzzer) {
 			info.Calls[job.call].Flags&flatrpc.CallFlagFaultInjected == 0 {
 			break
 		}
+		if sites == nil {
+			continue
+		}
+		if site := faultSite(base, callCover(info, job.call)); site != 0 && sites.note(meta, nth, site) {
+			fuzzer.statFaultSites.Add(1)
+			fuzzer.Logf(2, "new fault site %x in %v, step %v", site, meta.Name, nth)
+		}
 	}
 }
 
+func callCover(info *flatrpc.ProgInfo, call int) []uint64 {
+	if info == nil || len(info.Calls) <= call || info.Calls[call] == nil {
+		return nil
+	}
+	return info.Calls[call].Cover
+}
+
+// Suspend/resume cycles stall all procs of the machine for a few seconds.
+const suspendInterval = 5 * time.Minute
+
+// allowSuspend returns whether it's time for the next suspend/resume cycle.
+func (fuzzer *Fuzzer) allowSuspend() bool {
+	now := time.Now().UnixNano()
+	last := fuzzer.lastSuspend.Load()
+	if now-last < int64(suspendInterval) {
+		return false
+	}
+	return fuzzer.lastSuspend.CompareAndSwap(last, now)
+}
+
+// suspendResume executes the program with a suspend/resume cycle right before the call.
+// Resources created by the preceding calls live through the cycle, and the concurrently executed
+// async calls race with it, which exposes PM-related races and leaks.
+func (job *smashJob) suspendResume(fuzzer *Fuzzer, rnd *rand.Rand) {
+	p := job.p.Clone()
+	p.Calls[job.call].Props.Suspend = prog.SuspendIdle + rnd.Intn(2)
+	fuzzer.Logf(2, "suspend/resume before call %v, mode %v", job.call, p.Calls[job.call].Props.Suspend)
+	fuzzer.execute(fuzzer.smashQueue, &queue.Request{
+		Prog:     p,
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+		Stat:     fuzzer.statExecSmash,
+	})
+}
+
 type hintsJob struct {
 	p    *prog.Prog
 	call int
@@ -352,19 +473,8 @@ func (job *hintsJob) run(fuzzer *Fuzzer) {
 
 	var comps prog.CompMap
 	for i := 0; i < 2; i++ {
-		result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
-			Prog:     p,
-			ExecOpts: setFlags(flatrpc.ExecFlagCollectComps),
-			Stat:     fuzzer.statExecSeed,
-		})
-		if result.Stop() || result.Info == nil {
-			return
-		}
-		got := make(prog.CompMap)
-		for _, cmp := range result.Info.Calls[job.call].Comps {
-			got.AddComp(cmp.Op1, cmp.Op2)
-		}
-		if len(got) == 0 {
+		got, ok := job.collectComps(fuzzer, p, fuzzer.statExecSeed)
+		if !ok || len(got) == 0 {
 			return
 		}
 		if i == 0 {
@@ -377,13 +487,34 @@ func (job *hintsJob) run(fuzzer *Fuzzer) {
 	// Then mutate the initial program for every match between
 	// a syscall argument and a comparison operand.
 	// Execute each of such mutants to check if it gives new coverage.
-	p.MutateWithHints(job.call, comps,
-		func(p *prog.Prog) bool {
-			result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
-				Prog:     p,
-				ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
-				Stat:     fuzzer.statExecHint,
-			})
-			return !result.Stop()
+	execHint := func(p *prog.Prog) bool {
+		result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
+			Prog:     p,
+			ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+			Stat:     fuzzer.statExecHint,
 		})
+		return !result.Stop()
+	}
+	p.MutateWithHints(job.call, comps, execHint)
+	// Then extend the matches to whole byte sequences compared by memcmp/strcmp.
+	p.MutateWithBlobHints(job.call, comps,
+		func(p *prog.Prog) (prog.CompMap, bool) {
+			return job.collectComps(fuzzer, p, fuzzer.statExecBlobHintSeed)
+		}, execHint)
+}
+
+func (job *hintsJob) collectComps(fuzzer *Fuzzer, p *prog.Prog, stat *stats.Val) (prog.CompMap, bool) {
+	result := fuzzer.execute(fuzzer.smashQueue, &queue.Request{
+		Prog:     p,
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectComps),
+		Stat:     stat,
+	})
+	if result.Stop() || result.Info == nil {
+		return nil, false
+	}
+	comps := make(prog.CompMap)
+	for _, cmp := range result.Info.Calls[job.call].Comps {
+		comps.AddComp(cmp.Op1, cmp.Op2)
+	}
+	return comps, true
 }
@@ -5,6 +5,7 @@ package fuzzer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
@@ -80,6 +81,53 @@ func TestDeflakeSuccess(t *testing.T) {
 	assert.ElementsMatch(t, []uint64{0, 2}, ret.newStableSignal.ToRaw())
 }
 
+func TestDeflakeCustomRuns(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+
+	// Require the signal to reproduce in all 4 runs.
+	testJob := &triageJob{
+		p:         prog,
+		info:      &flatrpc.CallInfo{},
+		newSignal: signal.FromRaw([]uint64{0, 1, 2}, 0),
+		fuzzer: &Fuzzer{Config: &Config{
+			DeflakeRuns:    4,
+			DeflakeMaxRuns: 4,
+		}},
+	}
+	run := 0
+	ret, stop := testJob.deflake(func(_ *queue.Request, _ ProgTypes) *queue.Result {
+		run++
+		if run == 3 {
+			return fakeResult(0, []uint64{0, 2}, []uint64{10})
+		}
+		return fakeResult(0, []uint64{0, 1, 2}, []uint64{10})
+	}, nil, false)
+	assert.False(t, stop)
+	assert.Equal(t, 4, run)
+	// 1 was flaky (missing in the third run).
+	assert.ElementsMatch(t, []uint64{0, 2}, ret.newStableSignal.ToRaw())
+
+	// If maximum runs is less than the required runs, the required number is used.
+	testJob.fuzzer.Config.DeflakeMaxRuns = 2
+	needRuns, maxRuns := testJob.deflakeRuns()
+	assert.Equal(t, 4, needRuns)
+	assert.Equal(t, 4, maxRuns)
+}
+
+func TestAllowSuspend(t *testing.T) {
+	fuzzer := &Fuzzer{}
+	assert.True(t, fuzzer.allowSuspend())
+	assert.False(t, fuzzer.allowSuspend())
+	fuzzer.lastSuspend.Store(time.Now().Add(-suspendInterval).UnixNano())
+	assert.True(t, fuzzer.allowSuspend())
+	assert.False(t, fuzzer.allowSuspend())
+}
+
 func fakeResult(errno int32, signal, cover []uint64) *queue.Result {
 	return &queue.Result{
 		Info: &flatrpc.ProgInfo{
@@ -0,0 +1,189 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// Long programs are executed instead of every longProgRate-th fuzzed program.
+	longProgRate = 10
+	// Number of calls in a freshly generated state.
+	longProgInitCalls = 8
+	// Max number of calls in a state, the rest of prog.MaxCalls is left for the fuzzed part.
+	maxLongProgStateCalls = 24
+	// The state is reset after that many executions on top of it.
+	longProgResetPeriod = 200
+	// Max number of checkpointed states that are kept for reuse after resets.
+	maxLongProgCheckpoints = 32
+	// Max number of not yet finished executions on top of a state.
+	// Requests are pinned to the VM, so they wait while the VM restarts.
+	maxLongProgInflight = 16
+	// Expected number of calls generated on top of a state.
+	longProgTailCalls = 10
+)
+
+// Some bugs are reachable only from complex kernel state (e.g. deeply nested cgroups,
+// many mounts and namespaces), which is unlikely to be built by a single short fuzzed program.
+// In the long-program mode the fuzzer keeps a number of long-lived states (one per VM):
+// programs made of the state-building calls (Config.StateCalls). Fuzzed programs are generated
+// or mutated on top of a state (so that they can use resources created by the state),
+// and are executed on the VM the state belongs to (see queue.Request.PinnedVM), so that the kernel
+// state that is not reset between programs also accumulates. The state-building calls of the fuzzed
+// part that succeeded are accumulated into the state. Since the executor resets the process state
+// between programs, the state is replayed as the prefix of every long program, so a state is also
+// a checkpoint that can be restored at any time. After longProgResetPeriod executions the state is
+// reset either to a fresh one, or to one of the checkpointed states that led to new coverage.
+type longProgs struct {
+	ct          *prog.ChoiceTable
+	mu          sync.Mutex
+	states      []*longProgState
+	checkpoints progSet
+}
+
+type longProgState struct {
+	// The state program is never modified, accumulation replaces it with a new one.
+	p        *prog.Prog
+	execs    int
+	inflight int
+}
+
+func newLongProgs(fuzzer *Fuzzer) *longProgs {
+	cfg := fuzzer.Config
+	generatable := false
+	for call := range cfg.StateCalls {
+		generatable = generatable || !call.Attrs.NoGenerate && !call.Attrs.Disabled
+	}
+	if cfg.LongProgs <= 0 || !generatable {
+		return nil
+	}
+	return &longProgs{
+		ct:          fuzzer.target.BuildChoiceTable(nil, cfg.StateCalls),
+		states:      make([]*longProgState, cfg.LongProgs),
+		checkpoints: progSet{limit: maxLongProgCheckpoints},
+	}
+}
+
+// longProgRequest executes a fuzzed program on top of the state of one of the VMs.
+func longProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
+	lp := fuzzer.longProgs
+	lp.mu.Lock()
+	vm := rnd.Intn(len(lp.states))
+	state := lp.states[vm]
+	if state != nil && state.inflight >= maxLongProgInflight {
+		lp.mu.Unlock()
+		return nil
+	}
+	if state == nil || state.execs >= longProgResetPeriod {
+		state = &longProgState{p: lp.resetState(fuzzer, rnd)}
+		lp.states[vm] = state
+		fuzzer.statLongProgResets.Add(1)
+	}
+	state.execs++
+	state.inflight++
+	base := state.p
+	lp.mu.Unlock()
+
+	req := &queue.Request{
+		Prog:     longProg(fuzzer, rnd, base),
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+		Stat:     fuzzer.statExecLongProg,
+		PinnedVM: vm + 1,
+	}
+	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
+		lp.mu.Lock()
+		state.inflight--
+		lp.mu.Unlock()
+		lp.update(fuzzer, state, base, req.Prog, res)
+		return true
+	})
+	return req
+}
+
+// resetState returns either a checkpointed or a freshly generated state.
+func (lp *longProgs) resetState(fuzzer *Fuzzer, rnd *rand.Rand) *prog.Prog {
+	if rnd.Intn(2) == 0 {
+		if p := lp.checkpoints.choose(rnd); p != nil {
+			return p
+		}
+	}
+	return fuzzer.target.Generate(rnd, longProgInitCalls, lp.ct)
+}
+
+// longProg returns a long program: the state followed by a fuzzed part, which is either
+// a mutated corpus program, or freshly generated calls. The state is never mutated,
+// but the fuzzed part is generated/mutated with the state as a prefix, so it can use the state resources.
+func longProg(fuzzer *Fuzzer, rnd *rand.Rand, base *prog.Prog) *prog.Prog {
+	p := base.Clone()
+	corpus := fuzzer.Config.Corpus.Programs()
+	opts := fuzzer.mutationOpts()
+	if len(corpus) != 0 && rnd.Intn(2) == 0 {
+		p.Calls = append(p.Calls, corpus[rnd.Intn(len(corpus))].Clone().Calls...)
+		// Calls are removed from the end, so calls required by the remaining calls are preserved.
+		for i := len(p.Calls) - 1; i >= prog.MaxCalls; i-- {
+			p.RemoveCall(i)
+		}
+	} else {
+		// Insert-only mutation of the state is generation on top of it.
+		// Removal is needed to not get stuck once the program has prog.MaxCalls calls.
+		opts = prog.MutateOpts{
+			ExpectedIterations: longProgTailCalls,
+			InsertWeight:       10,
+			RemoveCallWeight:   1,
+			UringCalls:         opts.UringCalls,
+			CompatCalls:        opts.CompatCalls,
+		}
+	}
+	opts.Prefix = len(base.Calls)
+	p.MutateWithOpts(rnd, prog.MaxCalls, fuzzer.ChoiceTable(), fuzzer.Config.NoMutateCalls, corpus, opts)
+	return p
+}
+
+// update checkpoints the state if the fuzzed part found new signal on top of it,
+// and accumulates the succeeded state-building calls of the fuzzed part into the state.
+func (lp *longProgs) update(fuzzer *Fuzzer, state *longProgState, base, p *prog.Prog, res *queue.Result) {
+	if res.Info == nil {
+		return
+	}
+	productive := false
+	keep := make(map[int]bool)
+	for i := len(base.Calls); i < len(p.Calls) && i < len(res.Info.Calls); i++ {
+		info := res.Info.Calls[i]
+		if info == nil {
+			continue
+		}
+		if len(info.Signal) != 0 {
+			productive = true
+		}
+		if info.Flags&flatrpc.CallFlagFinished != 0 && info.Error == 0 &&
+			fuzzer.Config.StateCalls[p.Calls[i].Meta] {
+			keep[i] = true
+		}
+	}
+	if productive && len(base.Calls) != 0 {
+		lp.checkpoints.add(base, fuzzer.rand())
+	}
+	if len(keep) == 0 || len(base.Calls)+len(keep) > maxLongProgStateCalls {
+		return
+	}
+	newState := p.Clone()
+	for i := len(newState.Calls) - 1; i >= len(base.Calls); i-- {
+		if !keep[i] {
+			newState.RemoveCall(i)
+		}
+	}
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	// The state may have been already extended by a concurrent execution.
+	if state.p == base {
+		state.p = newState
+		fuzzer.statLongProgCalls.Add(len(keep))
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongProgs(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stateCalls := map[*prog.Syscall]bool{
+		target.SyscallMap["test$res0"]: true,
+		target.SyscallMap["test$res1"]: true,
+	}
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus: corpus.NewCorpus(ctx),
+	}, rand.New(testutil.RandSource(t)), target)
+	assert.Nil(t, fuzzer.longProgs)
+	fuzzer = NewFuzzer(ctx, &Config{
+		Corpus:     corpus.NewCorpus(ctx),
+		LongProgs:  1,
+		StateCalls: stateCalls,
+	}, rand.New(testutil.RandSource(t)), target)
+	lp := fuzzer.longProgs
+	assert.NotNil(t, lp)
+
+	rnd := rand.New(testutil.RandSource(t))
+	req := longProgRequest(fuzzer, rnd)
+	assert.Equal(t, fuzzer.statExecLongProg, req.Stat)
+	assert.Equal(t, 1, req.PinnedVM)
+	assert.LessOrEqual(t, len(req.Prog.Calls), prog.MaxCalls)
+	state := lp.states[0]
+	base := state.p
+	assert.Equal(t, 1, state.execs)
+	assert.Equal(t, 1, state.inflight)
+	assert.Equal(t, 1, fuzzer.statLongProgResets.Val())
+	assert.Greater(t, len(req.Prog.Calls), len(base.Calls))
+	// The state is a prefix of the program.
+	prefix := req.Prog.Clone()
+	for i := len(prefix.Calls) - 1; i >= len(base.Calls); i-- {
+		prefix.RemoveCall(i)
+	}
+	assert.Equal(t, string(base.Serialize()), string(prefix.Serialize()))
+	for _, call := range base.Calls {
+		assert.True(t, stateCalls[call.Meta])
+	}
+	req.Done(&queue.Result{})
+	assert.Equal(t, 0, state.inflight)
+
+	// The number of executions on top of a state is bounded.
+	for i := 0; i < maxLongProgInflight; i++ {
+		assert.NotNil(t, longProgRequest(fuzzer, rnd))
+	}
+	assert.Nil(t, longProgRequest(fuzzer, rnd))
+	state.inflight = 0
+	state.execs = 1
+
+	// The fuzzed part found new signal and created more state.
+	tail, err := target.Deserialize([]byte("r0 = test$res0()\ntest$res1(r0)\n"+anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	p := base.Clone()
+	p.Calls = append(p.Calls, tail.Calls...)
+	info := &flatrpc.ProgInfo{}
+	for range p.Calls {
+		info.Calls = append(info.Calls, &flatrpc.CallInfo{
+			Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished,
+		})
+	}
+	info.Calls[len(p.Calls)-1].Signal = []uint64{1}
+	lp.update(fuzzer, state, base, p, &queue.Result{Info: info})
+	assert.Equal(t, base, lp.checkpoints.choose(rnd))
+	assert.Len(t, state.p.Calls, len(base.Calls)+2)
+	assert.Equal(t, 2, fuzzer.statLongProgCalls.Val())
+
+	// Results of executions on top of an outdated state are not accumulated.
+	lp.update(fuzzer, state, base, p, &queue.Result{Info: info})
+	assert.Len(t, state.p.Calls, len(base.Calls)+2)
+
+	// The state is reset after longProgResetPeriod executions.
+	state.execs = longProgResetPeriod
+	longProgRequest(fuzzer, rnd)
+	assert.NotEqual(t, state, lp.states[0])
+	assert.Equal(t, 2, fuzzer.statLongProgResets.Val())
+}
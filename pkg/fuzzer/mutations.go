@@ -0,0 +1,114 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"time"
+
+	"github.com/google/syzkaller/pkg/stats"
+	"github.com/google/syzkaller/prog"
+)
+
+// Mutation operator weights are re-tuned every tuneMutationsPeriod.
+// Statistics of the previous periods are halved on every tuning,
+// so that the weights follow the changing yield of the operators.
+const tuneMutationsPeriod = 5 * time.Minute
+
+// mutationYield holds decayed per mutation op numbers of executions and executions with new signal.
+type mutationYield struct {
+	prevExecs  [prog.MutationCount]int
+	prevSignal [prog.MutationCount]int
+	execs      map[prog.MutationOp]int
+	signal     map[prog.MutationOp]int
+}
+
+func newMutationStats() (execs, signal [prog.MutationCount]*stats.Val) {
+	for op := prog.MutationOp(0); op < prog.MutationCount; op++ {
+		if op == prog.MutationNone {
+			continue
+		}
+		execs[op] = stats.Create("mutation "+op.String()+" execs",
+			"Executions of programs with arguments produced by the mutation op",
+			stats.Rate{}, stats.Graph("mutation execs"))
+		signal[op] = stats.Create("mutation "+op.String()+" signal",
+			"Executions of programs with arguments produced by the mutation op that found new signal",
+			stats.Graph("mutation signal"))
+	}
+	return
+}
+
+func (fuzzer *Fuzzer) createMutationWeightStats() {
+	for name, weight := range map[string]func(opts prog.MutateOpts) int{
+		"squash":     func(opts prog.MutateOpts) int { return opts.SquashWeight },
+		"splice":     func(opts prog.MutateOpts) int { return opts.SpliceWeight },
+		"insert":     func(opts prog.MutateOpts) int { return opts.InsertWeight },
+		"mutate arg": func(opts prog.MutateOpts) int { return opts.MutateArgWeight },
+	} {
+		weight := weight
+		stats.Create("mutation weight "+name, "Current weight of the mutation operator",
+			stats.Graph("mutation weights"), func() int {
+				return weight(fuzzer.mutationOpts())
+			})
+	}
+}
+
+// accountMutation attributes an execution of the program (and whether it found new signal)
+// to the mutation ops that produced its arguments.
+func (fuzzer *Fuzzer) accountMutation(p *prog.Prog, newSignal bool) {
+	for op := range p.MutationOps() {
+		if fuzzer.statMutationExecs[op] == nil {
+			continue
+		}
+		fuzzer.statMutationExecs[op].Add(1)
+		if newSignal {
+			fuzzer.statMutationSignal[op].Add(1)
+		}
+	}
+}
+
+func (fuzzer *Fuzzer) mutationTuner() {
+	for {
+		select {
+		case <-time.After(tuneMutationsPeriod):
+		case <-fuzzer.ctx.Done():
+			return
+		}
+		fuzzer.tuneMutations()
+	}
+}
+
+// tuneMutations re-weights mutation operators according to their recent yield of new signal
+// (see prog.MutateOpts.WeightByYield).
+func (fuzzer *Fuzzer) tuneMutations() {
+	fuzzer.ctMu.Lock()
+	defer fuzzer.ctMu.Unlock()
+	y := &fuzzer.yield
+	execs := make(map[prog.MutationOp]int)
+	signal := make(map[prog.MutationOp]int)
+	for op := prog.MutationOp(0); op < prog.MutationCount; op++ {
+		if fuzzer.statMutationExecs[op] == nil {
+			continue
+		}
+		curExecs, curSignal := fuzzer.statMutationExecs[op].Val(), fuzzer.statMutationSignal[op].Val()
+		execs[op] = y.execs[op]/2 + curExecs - y.prevExecs[op]
+		signal[op] = y.signal[op]/2 + curSignal - y.prevSignal[op]
+		y.prevExecs[op], y.prevSignal[op] = curExecs, curSignal
+	}
+	y.execs, y.signal = execs, signal
+	fuzzer.updateMutateOpts()
+	opts := fuzzer.mutateOpts
+	fuzzer.Logf(1, "mutation weights: squash %v, splice %v, insert %v, mutate arg %v",
+		opts.SquashWeight, opts.SpliceWeight, opts.InsertWeight, opts.MutateArgWeight)
+}
+
+// updateMutateOpts recalculates the mutation operator weights, ctMu must be held.
+func (fuzzer *Fuzzer) updateMutateOpts() {
+	opts := prog.DefaultMutateOpts.WeightByCrashes(fuzzer.mutationCrashes)
+	if fuzzer.Config.MutationTuning {
+		opts = opts.WeightByYield(fuzzer.yield.execs, fuzzer.yield.signal)
+	}
+	opts.UringCalls = fuzzer.Config.UringCalls
+	opts.CompatCalls = fuzzer.Config.CompatCalls
+	fuzzer.mutateOpts = opts
+}
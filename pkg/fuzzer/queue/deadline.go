@@ -0,0 +1,144 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/stats"
+)
+
+// DeadlineQueue is a thread-safe priority queue, in which every Request may have a deadline.
+// Requests with lower priority values are returned first, requests with equal priorities
+// are returned in the submission order.
+// Requests that were not taken from the queue before their deadline are not dropped,
+// they are deferred: Next() does not return them anymore, instead they are returned
+// (in the submission order) by the separate Deferred() source, which may be polled
+// with a lower priority than the rest of the work.
+type DeadlineQueue struct {
+	stat     *stats.Val
+	expired  *stats.Val
+	now      func() time.Time
+	mu       sync.Mutex
+	items    deadlineHeap
+	deferred []*Request
+	seq      uint64
+}
+
+// Deadline creates a DeadlineQueue. The stat tracks the number of requests in the queue
+// (including the deferred ones), the expired stat counts the deferred requests. Both may be nil.
+func Deadline(stat, expired *stats.Val) *DeadlineQueue {
+	return &DeadlineQueue{
+		stat:    stat,
+		expired: expired,
+		now:     time.Now,
+	}
+}
+
+func (dq *DeadlineQueue) Len() int {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	return len(dq.items) + len(dq.deferred)
+}
+
+// Submit adds the request with the highest priority (0) and without a deadline.
+func (dq *DeadlineQueue) Submit(req *Request) {
+	dq.SubmitDeadline(req, 0, time.Time{})
+}
+
+// SubmitDeadline adds the request with the given priority.
+// If deadline is not zero, the request is deferred if it's not taken from the queue by then.
+func (dq *DeadlineQueue) SubmitDeadline(req *Request, prio int, deadline time.Time) {
+	if dq.stat != nil {
+		dq.stat.Add(1)
+	}
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	dq.seq++
+	heap.Push(&dq.items, &deadlineItem{
+		req:      req,
+		prio:     prio,
+		seq:      dq.seq,
+		deadline: deadline,
+	})
+}
+
+func (dq *DeadlineQueue) Next() *Request {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	now := dq.now()
+	for len(dq.items) != 0 {
+		item := heap.Pop(&dq.items).(*deadlineItem)
+		if !item.deadline.IsZero() && now.After(item.deadline) {
+			if dq.expired != nil {
+				dq.expired.Add(1)
+			}
+			dq.deferred = append(dq.deferred, item.req)
+			continue
+		}
+		dq.taken()
+		return item.req
+	}
+	return nil
+}
+
+// Deferred returns the source of the requests that missed their deadline.
+func (dq *DeadlineQueue) Deferred() Source {
+	return Callback(dq.nextDeferred)
+}
+
+func (dq *DeadlineQueue) nextDeferred() *Request {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+	if len(dq.deferred) == 0 {
+		return nil
+	}
+	req := dq.deferred[0]
+	dq.deferred[0] = nil
+	dq.deferred = dq.deferred[1:]
+	dq.taken()
+	return req
+}
+
+func (dq *DeadlineQueue) taken() {
+	if dq.stat != nil {
+		dq.stat.Add(-1)
+	}
+}
+
+type deadlineItem struct {
+	req      *Request
+	prio     int
+	seq      uint64
+	deadline time.Time
+}
+
+type deadlineHeap []*deadlineItem
+
+func (h deadlineHeap) Len() int { return len(h) }
+
+func (h deadlineHeap) Less(i, j int) bool {
+	if h[i].prio != h[j].prio {
+		return h[i].prio < h[j].prio
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h deadlineHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *deadlineHeap) Push(x any) {
+	*h = append(*h, x.(*deadlineItem))
+}
+
+func (h *deadlineHeap) Pop() any {
+	n := len(*h)
+	item := (*h)[n-1]
+	(*h)[n-1] = nil
+	*h = (*h)[:n-1]
+	return item
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadlineQueueOrder(t *testing.T) {
+	dq := Deadline(nil, nil)
+	req1, req2, req3, req4 := &Request{}, &Request{}, &Request{}, &Request{}
+	dq.SubmitDeadline(req1, 2, time.Time{})
+	dq.SubmitDeadline(req2, 1, time.Time{})
+	dq.SubmitDeadline(req3, 2, time.Time{})
+	dq.Submit(req4)
+	assert.Equal(t, 4, dq.Len())
+	assert.Equal(t, req4, dq.Next())
+	assert.Equal(t, req2, dq.Next())
+	assert.Equal(t, req1, dq.Next())
+	assert.Equal(t, req3, dq.Next())
+	assert.Nil(t, dq.Next())
+}
+
+func TestDeadlineQueueExpire(t *testing.T) {
+	stat := stats.Create("deadline queue", "desc")
+	expired := stats.Create("deadline queue expired", "desc")
+	dq := Deadline(stat, expired)
+	deferred := dq.Deferred()
+	now := time.Now()
+	dq.now = func() time.Time { return now }
+
+	req1, req2, req3 := &Request{}, &Request{}, &Request{}
+	dq.SubmitDeadline(req1, 0, now.Add(time.Minute))
+	dq.SubmitDeadline(req2, 1, now.Add(time.Hour))
+	dq.SubmitDeadline(req3, 2, time.Time{})
+	assert.Equal(t, 3, stat.Val())
+	assert.Nil(t, deferred.Next())
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, req2, dq.Next())
+	// The expired request is not dropped, it's still accounted in the queue.
+	assert.Equal(t, 2, stat.Val())
+	assert.Equal(t, 1, expired.Val())
+	assert.Equal(t, 2, dq.Len())
+
+	now = now.Add(24 * time.Hour)
+	assert.Equal(t, req3, dq.Next())
+	assert.Nil(t, dq.Next())
+	assert.Equal(t, 1, stat.Val())
+	assert.Equal(t, req1, deferred.Next())
+	assert.Nil(t, deferred.Next())
+	assert.Equal(t, 0, stat.Val())
+	assert.Equal(t, 1, expired.Val())
+}
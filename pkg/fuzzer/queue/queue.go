@@ -43,6 +43,10 @@ type Request struct {
 	// Important requests will be retried even from crashed VMs.
 	Important bool
 
+	// If set, the request is executed only on the VM with index PinnedVM-1
+	// (e.g. because it builds on the kernel state of that VM).
+	PinnedVM int
+
 	// The callback will be called on request completion in the LIFO order.
 	// If it returns false, all further processing will be stopped.
 	// It allows wrappers to intercept Done() requests.
@@ -0,0 +1,128 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// Number of re-executions of a program that produced a data race candidate.
+	raceJobIters = 10
+	// The race is considered confirmed if it's detected at least in this many re-executions.
+	raceConfirmRuns = 2
+	// Max number of concurrently running race jobs.
+	maxRaceJobs = 8
+	// Max number of confirmed racy programs that are kept for further fuzzing.
+	maxRacyProgs = 64
+)
+
+// raceJob re-executes a program that produced a KCSAN data race candidate
+// with different async/rerun assignments and paired with other racy programs
+// to confirm the race. Programs with confirmed races are fuzzed more.
+type raceJob struct {
+	p *prog.Prog
+}
+
+func (job *raceJob) run(fuzzer *Fuzzer) {
+	fuzzer.Logf(2, "confirming data race in %s", job.p)
+	rnd := fuzzer.rand()
+	confirmed := 0
+	for i := 0; i < raceJobIters && confirmed < raceConfirmRuns; i++ {
+		p := raceCollide(fuzzer, job.p, rnd)
+		result := fuzzer.executeWithFlags(fuzzer.smashQueue, &queue.Request{
+			Prog: p,
+			Stat: fuzzer.statExecRace,
+		}, progInRace)
+		if result.Stop() {
+			return
+		}
+		if hasRaceCandidate(result.Info) {
+			confirmed++
+		}
+	}
+	if confirmed < raceConfirmRuns {
+		return
+	}
+	fuzzer.Logf(2, "confirmed data race in %s", job.p)
+	fuzzer.racyProgs.add(job.p, fuzzer.rand())
+	fuzzer.statRacyProgs.Add(1)
+}
+
+// raceCollide either collides the program with one of the known racy programs,
+// or with itself.
+func raceCollide(fuzzer *Fuzzer, p *prog.Prog, rnd *rand.Rand) *prog.Prog {
+	if rnd.Intn(3) == 0 {
+		if other := fuzzer.racyProgs.choose(rnd); other != nil {
+			paired, err := prog.PairCollide(p, other, rnd)
+			if err == nil {
+				return paired
+			}
+		}
+	}
+	return randomCollide(p, rnd)
+}
+
+// raceProgRequest mutates one of the programs with confirmed races.
+func raceProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
+	p := fuzzer.racyProgs.choose(rnd)
+	if p == nil {
+		return nil
+	}
+	newP := p.Clone()
+	newP.Mutate(rnd,
+		prog.RecommendedCalls,
+		fuzzer.ChoiceTable(),
+		fuzzer.Config.NoMutateCalls,
+		fuzzer.Config.Corpus.Programs(),
+	)
+	return &queue.Request{
+		Prog:     raceCollide(fuzzer, newP, rnd),
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+		Stat:     fuzzer.statExecRace,
+	}
+}
+
+func hasRaceCandidate(info *flatrpc.ProgInfo) bool {
+	if info == nil {
+		return false
+	}
+	for _, call := range info.Calls {
+		if call != nil && call.Flags&flatrpc.CallFlagRaceCandidate != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// progSet keeps up to limit programs, new programs replace random old ones.
+type progSet struct {
+	mu    sync.Mutex
+	limit int
+	progs []*prog.Prog
+}
+
+func (rp *progSet) add(p *prog.Prog, rnd *rand.Rand) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if len(rp.progs) < rp.limit {
+		rp.progs = append(rp.progs, p)
+		return
+	}
+	rp.progs[rnd.Intn(len(rp.progs))] = p
+}
+
+func (rp *progSet) choose(rnd *rand.Rand) *prog.Prog {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if len(rp.progs) == 0 {
+		return nil
+	}
+	return rp.progs[rnd.Intn(len(rp.progs))]
+}
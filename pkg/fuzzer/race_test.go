@@ -0,0 +1,44 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasRaceCandidate(t *testing.T) {
+	assert.False(t, hasRaceCandidate(nil))
+	assert.False(t, hasRaceCandidate(&flatrpc.ProgInfo{
+		Calls: []*flatrpc.CallInfo{nil, {Flags: flatrpc.CallFlagExecuted}},
+	}))
+	assert.True(t, hasRaceCandidate(&flatrpc.ProgInfo{
+		Calls: []*flatrpc.CallInfo{
+			{Flags: flatrpc.CallFlagExecuted},
+			{Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagRaceCandidate},
+		},
+	}))
+}
+
+func TestRacyProgs(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	rnd := rand.New(rand.NewSource(0))
+	rp := progSet{limit: maxRacyProgs}
+	assert.Nil(t, rp.choose(rnd))
+	for i := 0; i < 2*maxRacyProgs; i++ {
+		rp.add(p, rnd)
+	}
+	assert.Len(t, rp.progs, maxRacyProgs)
+	assert.Equal(t, p, rp.choose(rnd))
+}
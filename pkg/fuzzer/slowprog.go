@@ -0,0 +1,119 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// A corpus program is demoted only after that many executions of its mutants.
+	slowProgMinExecs = 10
+	// Counters of a program are halved after that many executions, so that the decision
+	// follows recent executions (e.g. after the kernel code was fixed).
+	slowProgWindow = 50
+	// A program is demoted if that fraction of its mutants exceed the time budget.
+	slowProgRatio = 0.8
+	// Demoted programs are chosen for mutation that many times less frequently.
+	slowProgPenalty = 10
+	// Stats of programs that were removed from the corpus are dropped once per that many executions.
+	slowProgCleanupPeriod = 10000
+)
+
+// Some corpus programs take a lot of time to execute (long sleeps, heavy io_uring loops, etc),
+// and so do most of their mutants. If such programs are mutated as often as others,
+// they consume a disproportional fraction of the executor time.
+// slowProgs tracks execution time of mutants of every corpus program and demotes programs
+// whose mutants consistently exceed Config.SlowProgBudget: they are chosen for mutation
+// slowProgPenalty times less frequently.
+type slowProgs struct {
+	budget time.Duration
+	mu     sync.Mutex
+	progs  map[*prog.Prog]*slowProgStats
+	execs  int
+}
+
+type slowProgStats struct {
+	execs   int
+	slow    int
+	demoted bool
+}
+
+func newSlowProgs(fuzzer *Fuzzer) *slowProgs {
+	if fuzzer.Config.SlowProgBudget <= 0 {
+		return nil
+	}
+	return &slowProgs{
+		budget: fuzzer.Config.SlowProgBudget,
+		progs:  make(map[*prog.Prog]*slowProgStats),
+	}
+}
+
+// noteExec accounts execution of a mutant of the corpus program p.
+func (sp *slowProgs) noteExec(fuzzer *Fuzzer, p *prog.Prog, res *queue.Result) {
+	if res.Info == nil {
+		return
+	}
+	slow := time.Duration(res.Info.Elapsed) > sp.budget
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	st := sp.progs[p]
+	if st == nil {
+		st = new(slowProgStats)
+		sp.progs[p] = st
+	}
+	st.execs++
+	if slow {
+		st.slow++
+	}
+	if st.execs >= slowProgWindow {
+		st.execs /= 2
+		st.slow /= 2
+	}
+	demoted := st.execs >= slowProgMinExecs && float64(st.slow) >= slowProgRatio*float64(st.execs)
+	if demoted != st.demoted {
+		st.demoted = demoted
+		if demoted {
+			fuzzer.statSlowProgs.Add(1)
+			fuzzer.Logf(1, "demoted slow program (%v of %v mutants exceeded %v):\n%s",
+				st.slow, st.execs, sp.budget, p.Serialize())
+		} else {
+			fuzzer.statSlowProgs.Add(-1)
+		}
+	}
+	if sp.execs++; sp.execs%slowProgCleanupPeriod == 0 {
+		sp.cleanup(fuzzer, fuzzer.Config.Corpus.Programs())
+	}
+}
+
+// cleanup drops stats of programs that were removed from the corpus by minimization.
+func (sp *slowProgs) cleanup(fuzzer *Fuzzer, corpus []*prog.Prog) {
+	alive := make(map[*prog.Prog]bool, len(corpus))
+	for _, p := range corpus {
+		alive[p] = true
+	}
+	for p, st := range sp.progs {
+		if alive[p] {
+			continue
+		}
+		if st.demoted {
+			fuzzer.statSlowProgs.Add(-1)
+		}
+		delete(sp.progs, p)
+	}
+}
+
+// skip returns whether the chosen corpus program should be replaced with another one.
+func (sp *slowProgs) skip(p *prog.Prog, rnd *rand.Rand) bool {
+	sp.mu.Lock()
+	st := sp.progs[p]
+	demoted := st != nil && st.demoted
+	sp.mu.Unlock()
+	return demoted && rnd.Intn(slowProgPenalty) != 0
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowProgs(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus: corpus.NewCorpus(ctx),
+	}, rand.New(testutil.RandSource(t)), target)
+	assert.Nil(t, fuzzer.slowProgs)
+	fuzzer = NewFuzzer(ctx, &Config{
+		Corpus:         corpus.NewCorpus(ctx),
+		SlowProgBudget: time.Second,
+	}, rand.New(testutil.RandSource(t)), target)
+	sp := fuzzer.slowProgs
+	assert.NotNil(t, sp)
+
+	rnd := rand.New(testutil.RandSource(t))
+	p := target.Generate(rnd, 5, fuzzer.ChoiceTable())
+	exec := func(elapsed time.Duration, n int) {
+		for i := 0; i < n; i++ {
+			sp.noteExec(fuzzer, p, &queue.Result{Info: &flatrpc.ProgInfo{Elapsed: uint64(elapsed)}})
+		}
+	}
+	// Single slow mutants are fine.
+	exec(time.Millisecond, 5)
+	exec(2*time.Second, 5)
+	assert.False(t, sp.skip(p, rnd))
+	assert.Equal(t, 0, fuzzer.statSlowProgs.Val())
+
+	// Consistently slow mutants demote the program.
+	exec(2*time.Second, 30)
+	assert.Equal(t, 1, fuzzer.statSlowProgs.Val())
+	skipped := 0
+	for i := 0; i < 100; i++ {
+		if sp.skip(p, rnd) {
+			skipped++
+		}
+	}
+	assert.Greater(t, skipped, 70)
+
+	// The program is promoted back once its mutants become fast.
+	exec(time.Millisecond, 30)
+	assert.Equal(t, 0, fuzzer.statSlowProgs.Val())
+	assert.False(t, sp.skip(p, rnd))
+
+	// Stats of programs that are not in the corpus are dropped.
+	exec(2*time.Second, 50)
+	assert.Equal(t, 1, fuzzer.statSlowProgs.Val())
+	sp.cleanup(fuzzer, nil)
+	assert.Empty(t, sp.progs)
+	assert.Equal(t, 0, fuzzer.statSlowProgs.Val())
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/prog"
+)
+
+// Soak executes a pinned set of programs over and over again (no generation, no mutation)
+// and compares their coverage and call success rates against a baseline.
+// This allows to use an existing corpus as a behavioral regression test for kernel updates.
+type Soak struct {
+	cfg   *SoakConfig
+	progs []soakProg
+
+	mu      sync.Mutex
+	pass    int
+	pos     int
+	pending int
+	current SoakBaseline
+	total   signal.Signal
+	failed  int
+}
+
+type SoakConfig struct {
+	// Programs that are executed on each pass.
+	Progs    []*prog.Prog
+	ExecOpts flatrpc.ExecOpts
+	// Results of a previous run to compare against.
+	// If nil, the results of the first pass become the baseline.
+	Baseline *SoakBaseline
+	// Programs that lose more than this fraction of their baseline signal are reported as deviations.
+	MaxSignalLoss float64
+	// PassDone is called after each complete pass over all programs.
+	PassDone func(*SoakPass)
+}
+
+// SoakBaseline is the serializable result of one soak pass.
+type SoakBaseline struct {
+	// Total number of unique signal elements over all programs.
+	Signal int
+	// Per-program stats keyed by program hash.
+	Progs map[string]SoakProgStats
+}
+
+type SoakProgStats struct {
+	Signal     int // number of unique signal elements
+	Successful int // number of calls that finished with 0 errno
+}
+
+type SoakDeviation struct {
+	Prog     string
+	Baseline SoakProgStats
+	Current  SoakProgStats
+}
+
+type SoakPass struct {
+	Number int
+	Result *SoakBaseline
+	// Number of programs that could not be executed (e.g. the VM crashed).
+	Failed int
+	// Programs whose coverage or success rate dropped compared to the baseline.
+	Deviations []SoakDeviation
+	// Relative loss of total signal compared to the baseline (negative if signal grew).
+	SignalLoss float64
+}
+
+type soakProg struct {
+	p   *prog.Prog
+	sig string
+}
+
+func NewSoak(cfg *SoakConfig) *Soak {
+	soak := &Soak{cfg: cfg}
+	for _, p := range cfg.Progs {
+		soak.progs = append(soak.progs, soakProg{p, hash.String(p.Serialize())})
+	}
+	soak.reset()
+	return soak
+}
+
+func (soak *Soak) Next() *queue.Request {
+	soak.mu.Lock()
+	defer soak.mu.Unlock()
+	if soak.pos == len(soak.progs) {
+		// Wait for the rest of the pass to finish.
+		return nil
+	}
+	item := soak.progs[soak.pos]
+	soak.pos++
+	soak.pending++
+	req := &queue.Request{
+		Prog:            item.p.Clone(),
+		ExecOpts:        soak.cfg.ExecOpts.MergeFlags(setFlags(flatrpc.ExecFlagCollectSignal)),
+		ReturnAllSignal: true,
+	}
+	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
+		soak.done(item, res)
+		return true
+	})
+	return req
+}
+
+func (soak *Soak) done(item soakProg, res *queue.Result) {
+	soak.mu.Lock()
+	if res.Status != queue.Success || res.Info == nil {
+		soak.failed++
+	} else {
+		var stats SoakProgStats
+		var sig signal.Signal
+		infos := append([]*flatrpc.CallInfo{res.Info.Extra}, res.Info.Calls...)
+		for _, info := range infos {
+			if info == nil {
+				continue
+			}
+			sig.Merge(signal.FromRaw(info.Signal, 0))
+			if info.Flags&flatrpc.CallFlagFinished != 0 && info.Error == 0 {
+				stats.Successful++
+			}
+		}
+		stats.Signal = sig.Len()
+		soak.total.Merge(sig)
+		soak.current.Progs[item.sig] = stats
+	}
+	soak.pending--
+	if soak.pos != len(soak.progs) || soak.pending != 0 {
+		soak.mu.Unlock()
+		return
+	}
+	pass := soak.finishPass()
+	soak.mu.Unlock()
+	if soak.cfg.PassDone != nil {
+		soak.cfg.PassDone(pass)
+	}
+}
+
+func (soak *Soak) finishPass() *SoakPass {
+	soak.pass++
+	result := soak.current
+	result.Signal = soak.total.Len()
+	pass := &SoakPass{
+		Number: soak.pass,
+		Result: &result,
+		Failed: soak.failed,
+	}
+	if base := soak.cfg.Baseline; base == nil {
+		soak.cfg.Baseline = &result
+	} else {
+		pass.Deviations, pass.SignalLoss = compareSoak(base, &result, soak.cfg.MaxSignalLoss)
+	}
+	soak.reset()
+	return pass
+}
+
+func (soak *Soak) reset() {
+	soak.pos = 0
+	soak.failed = 0
+	soak.total = nil
+	soak.current = SoakBaseline{Progs: make(map[string]SoakProgStats)}
+}
+
+func compareSoak(base, cur *SoakBaseline, maxLoss float64) ([]SoakDeviation, float64) {
+	var deviations []SoakDeviation
+	for sig, now := range cur.Progs {
+		was, ok := base.Progs[sig]
+		if !ok {
+			continue
+		}
+		if now.Successful < was.Successful || float64(now.Signal) < float64(was.Signal)*(1-maxLoss) {
+			deviations = append(deviations, SoakDeviation{
+				Prog:     sig,
+				Baseline: was,
+				Current:  now,
+			})
+		}
+	}
+	sort.Slice(deviations, func(i, j int) bool {
+		return deviations[i].Prog < deviations[j].Prog
+	})
+	loss := 0.0
+	if base.Signal != 0 {
+		loss = float64(base.Signal-cur.Signal) / float64(base.Signal)
+	}
+	return deviations, loss
+}
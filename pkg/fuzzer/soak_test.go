@@ -0,0 +1,98 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoak(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var progs []*prog.Prog
+	for _, text := range []string{"mutate0()\n", "mutate0()\nmutate0()\n"} {
+		p, err := target.Deserialize([]byte(text), prog.Strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		progs = append(progs, p)
+	}
+	var passes []*SoakPass
+	soak := NewSoak(&SoakConfig{
+		Progs:         progs,
+		MaxSignalLoss: 0.5,
+		PassDone: func(pass *SoakPass) {
+			passes = append(passes, pass)
+		},
+	})
+	call := func(errno int32, sig ...uint64) *flatrpc.CallInfo {
+		return &flatrpc.CallInfo{
+			Flags:  flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished,
+			Error:  errno,
+			Signal: sig,
+		}
+	}
+	runPass := func(results ...*queue.Result) {
+		var reqs []*queue.Request
+		for range progs {
+			req := soak.Next()
+			assert.NotNil(t, req)
+			assert.True(t, req.ReturnAllSignal)
+			assert.NotZero(t, req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectSignal)
+			reqs = append(reqs, req)
+		}
+		// The next pass must not start until all results are in.
+		assert.Nil(t, soak.Next())
+		for i, req := range reqs {
+			req.Done(results[i])
+		}
+	}
+
+	runPass(
+		&queue.Result{Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{call(0, 1, 2, 3)}}},
+		&queue.Result{Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{call(0, 3, 4), call(1, 5)}}},
+	)
+	assert.Len(t, passes, 1)
+	assert.Equal(t, 5, passes[0].Result.Signal)
+	assert.Empty(t, passes[0].Deviations)
+
+	// Same behavior, except one program lost most of its coverage and the other one crashed the VM.
+	runPass(
+		&queue.Result{Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{call(0, 1)}}},
+		&queue.Result{Status: queue.Crashed},
+	)
+	assert.Len(t, passes, 2)
+	pass := passes[1]
+	assert.Equal(t, 2, pass.Number)
+	assert.Equal(t, 1, pass.Failed)
+	assert.Equal(t, 1, pass.Result.Signal)
+	assert.InDelta(t, 0.8, pass.SignalLoss, 1e-9)
+	assert.Equal(t, []SoakDeviation{{
+		Prog:     hash.String(progs[0].Serialize()),
+		Baseline: SoakProgStats{Signal: 3, Successful: 1},
+		Current:  SoakProgStats{Signal: 1, Successful: 1},
+	}}, pass.Deviations)
+
+	// A call that used to succeed now fails.
+	runPass(
+		&queue.Result{Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{call(0, 1, 2, 3)}}},
+		&queue.Result{Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{call(22, 3, 4), call(1, 5)}}},
+	)
+	assert.Len(t, passes, 3)
+	assert.Equal(t, []SoakDeviation{{
+		Prog:     hash.String(progs[1].Serialize()),
+		Baseline: SoakProgStats{Signal: 3, Successful: 1},
+		Current:  SoakProgStats{Signal: 3, Successful: 0},
+	}}, passes[2].Deviations)
+	assert.Zero(t, passes[2].SignalLoss)
+}
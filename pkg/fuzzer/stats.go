@@ -3,37 +3,66 @@
 
 package fuzzer
 
-import "github.com/google/syzkaller/pkg/stats"
+import (
+	"github.com/google/syzkaller/pkg/stats"
+	"github.com/google/syzkaller/prog"
+)
 
 type Stats struct {
-	StatCandidates    *stats.Val
-	statNewInputs     *stats.Val
-	statJobs          *stats.Val
-	statJobsTriage    *stats.Val
-	statJobsSmash     *stats.Val
-	statJobsHints     *stats.Val
-	statExecTime      *stats.Val
-	statExecGenerate  *stats.Val
-	statExecFuzz      *stats.Val
-	statExecCandidate *stats.Val
-	statExecTriage    *stats.Val
-	statExecMinimize  *stats.Val
-	statExecSmash     *stats.Val
-	statExecHint      *stats.Val
-	statExecSeed      *stats.Val
-	statExecCollide   *stats.Val
+	StatCandidates         *stats.Val
+	statCandidatesDeferred *stats.Val
+	statNewInputs          *stats.Val
+	statJobs               *stats.Val
+	statJobsTriage         *stats.Val
+	statJobsSmash          *stats.Val
+	statJobsHints          *stats.Val
+	statJobsRace           *stats.Val
+	statExecTime           *stats.Val
+	statExecGenerate       *stats.Val
+	statExecFuzz           *stats.Val
+	statExecCandidate      *stats.Val
+	statExecTriage         *stats.Val
+	statExecMinimize       *stats.Val
+	statExecSmash          *stats.Val
+	statExecHint           *stats.Val
+	statExecSeed           *stats.Val
+	statExecBlobHintSeed   *stats.Val
+	statExecCollide        *stats.Val
+	statExecRace           *stats.Val
+	statKernelWarnings     *stats.Val
+	statFlakySignal        *stats.Val
+	statTriageFlaky        *stats.Val
+	statRaceCandidates     *stats.Val
+	statRacyProgs          *stats.Val
+	statExecWarning        *stats.Val
+	statWarningProgs       *stats.Val
+	statExecLongProg       *stats.Val
+	statLongProgResets     *stats.Val
+	statLongProgCalls      *stats.Val
+	statSlowProgs          *stats.Val
+	statFaultSites         *stats.Val
+	statFaultSitesSkipped  *stats.Val
+	statExecDepth          *stats.Val
+	statDeepProgs          *stats.Val
+	// Per mutation op executions and executions that found new signal (see accountMutation).
+	statMutationExecs  [prog.MutationCount]*stats.Val
+	statMutationSignal [prog.MutationCount]*stats.Val
 }
 
 func newStats() Stats {
-	return Stats{
+	s := Stats{
 		StatCandidates: stats.Create("candidates", "Number of candidate programs in triage queue",
 			stats.Console, stats.Graph("corpus")),
+		statCandidatesDeferred: stats.Create("deferred candidates",
+			"Candidate programs deferred because they were not triaged before their deadline",
+			stats.Graph("corpus")),
 		statNewInputs: stats.Create("new inputs", "Potential untriaged corpus candidates",
 			stats.Graph("corpus")),
 		statJobs:       stats.Create("fuzzer jobs", "Total running fuzzer jobs", stats.NoGraph),
 		statJobsTriage: stats.Create("triage jobs", "Running triage jobs", stats.StackedGraph("jobs")),
 		statJobsSmash:  stats.Create("smash jobs", "Running smash jobs", stats.StackedGraph("jobs")),
 		statJobsHints:  stats.Create("hints jobs", "Running hints jobs", stats.StackedGraph("jobs")),
+		statJobsRace:   stats.Create("race jobs", "Running data race confirmation jobs", stats.StackedGraph("jobs")),
 		statExecTime:   stats.Create("prog exec time", "Test program execution time (ms)", stats.Distribution{}),
 		statExecGenerate: stats.Create("exec gen", "Executions of generated programs", stats.Rate{},
 			stats.StackedGraph("exec")),
@@ -51,7 +80,45 @@ func newStats() Stats {
 			stats.Rate{}, stats.StackedGraph("exec")),
 		statExecSeed: stats.Create("exec seeds", "Executions of programs for hints extraction",
 			stats.Rate{}, stats.StackedGraph("exec")),
+		statExecBlobHintSeed: stats.Create("exec blob seeds",
+			"Executions of programs for extension of hints to memcmp/strcmp byte sequences",
+			stats.Rate{}, stats.StackedGraph("exec")),
 		statExecCollide: stats.Create("exec collide", "Executions of programs in collide mode",
 			stats.Rate{}, stats.StackedGraph("exec")),
+		statExecRace: stats.Create("exec race", "Executions of programs with data race candidates",
+			stats.Rate{}, stats.StackedGraph("exec")),
+		statKernelWarnings: stats.Create("kernel warnings", "Calls that triggered non-fatal kernel bug reports",
+			stats.Graph("kernel warnings")),
+		statFlakySignal: stats.Create("flaky signal", "New signal that did not reproduce during triage",
+			stats.Graph("flaky")),
+		statTriageFlaky: stats.Create("flaky inputs", "Triaged inputs discarded because all new signal was flaky",
+			stats.Graph("flaky")),
+		statRaceCandidates: stats.Create("race candidates", "Executions that produced KCSAN data race candidates",
+			stats.Graph("races")),
+		statRacyProgs: stats.Create("racy programs", "Programs with confirmed KCSAN data races",
+			stats.Graph("races")),
+		statExecWarning: stats.Create("exec warning", "Executions of mutated programs that triggered kernel warnings",
+			stats.Rate{}, stats.StackedGraph("exec")),
+		statWarningProgs: stats.Create("warning programs", "Programs that triggered non-fatal kernel warnings",
+			stats.Graph("kernel warnings")),
+		statExecLongProg: stats.Create("exec long prog", "Executions of fuzzed programs on top of long-lived states",
+			stats.Rate{}, stats.StackedGraph("exec")),
+		statLongProgResets: stats.Create("long prog resets", "Resets of long-lived states in the long-program mode",
+			stats.Graph("long progs")),
+		statLongProgCalls: stats.Create("long prog calls", "State-building calls accumulated into long-lived states",
+			stats.Graph("long progs")),
+		statSlowProgs: stats.Create("slow programs",
+			"Corpus programs demoted from mutation because their mutants exceed the execution time budget"),
+		statFaultSites: stats.Create("fault sites", "Unique fault injection sites exercised by syscalls",
+			stats.Graph("fault injection")),
+		statFaultSitesSkipped: stats.Create("fault sites skipped",
+			"Fault injections skipped because they hit already exercised fault sites before",
+			stats.Rate{}, stats.Graph("fault injection")),
+		statExecDepth: stats.Create("exec depth", "Executions of mutated programs that reached new max call depth",
+			stats.Rate{}, stats.StackedGraph("exec")),
+		statDeepProgs: stats.Create("deep programs",
+			"Programs whose calls reached new max depth after edge coverage plateaued"),
 	}
+	s.statMutationExecs, s.statMutationSignal = newMutationStats()
+	return s
 }
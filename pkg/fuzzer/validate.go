@@ -0,0 +1,162 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+// Validation executes calls that declare expected errnos (the errnos call attribute)
+// and detects calls that consistently fail with other errnos.
+// This usually means that the description is broken (e.g. EFAULT due to a wrong struct layout).
+type Validation struct {
+	cfg   *ValidationConfig
+	ct    *prog.ChoiceTable
+	calls []*prog.Syscall
+
+	mu      sync.Mutex
+	rnd     *rand.Rand
+	issued  int
+	pending int
+	failed  int
+	stats   map[*prog.Syscall]*ValidationCallStats
+}
+
+type ValidationConfig struct {
+	Target       *prog.Target
+	EnabledCalls map[*prog.Syscall]bool
+	ExecOpts     flatrpc.ExecOpts
+	// Number of programs executed for each call.
+	Runs int
+	// Calls that fail with unexpected errnos in at least this fraction of runs are reported as broken.
+	MinUnexpected float64
+	// Done is called once all programs are executed.
+	Done func(*ValidationReport)
+}
+
+type ValidationCallStats struct {
+	// Number of runs where the call has finished.
+	Finished   int
+	Successful int
+	Expected   int
+	// Unexpected errno -> number of runs.
+	Unexpected map[int32]int
+}
+
+type ValidationReport struct {
+	Calls map[string]*ValidationCallStats
+	// Calls that consistently fail with unexpected errnos.
+	Broken []string
+	// Number of programs that could not be executed (e.g. the VM crashed).
+	Failed int
+}
+
+func NewValidation(cfg *ValidationConfig, rnd *rand.Rand) *Validation {
+	v := &Validation{
+		cfg:   cfg,
+		ct:    cfg.Target.BuildChoiceTable(nil, cfg.EnabledCalls),
+		rnd:   rnd,
+		stats: make(map[*prog.Syscall]*ValidationCallStats),
+	}
+	for call := range cfg.EnabledCalls {
+		if call.Errnos == nil || call.Attrs.NoGenerate {
+			continue
+		}
+		v.calls = append(v.calls, call)
+		v.stats[call] = &ValidationCallStats{Unexpected: make(map[int32]int)}
+	}
+	sort.Slice(v.calls, func(i, j int) bool {
+		return v.calls[i].Name < v.calls[j].Name
+	})
+	return v
+}
+
+// Calls returns the number of calls that are validated.
+func (v *Validation) Calls() int {
+	return len(v.calls)
+}
+
+func (v *Validation) Next() *queue.Request {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.issued == len(v.calls)*v.cfg.Runs {
+		return nil
+	}
+	call := v.calls[v.issued%len(v.calls)]
+	v.issued++
+	v.pending++
+	req := &queue.Request{
+		Prog:     v.cfg.Target.GenCallProg(call, v.rnd, v.ct),
+		ExecOpts: v.cfg.ExecOpts,
+	}
+	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
+		v.done(call, res)
+		return true
+	})
+	return req
+}
+
+func (v *Validation) done(call *prog.Syscall, res *queue.Result) {
+	v.mu.Lock()
+	if res.Status != queue.Success || res.Info == nil || len(res.Info.Calls) == 0 {
+		v.failed++
+	} else {
+		// The validated call is always the last one, the rest create resources for it.
+		info := res.Info.Calls[len(res.Info.Calls)-1]
+		stats := v.stats[call]
+		if info.Flags&flatrpc.CallFlagFinished != 0 {
+			stats.Finished++
+			switch {
+			case info.Error == 0:
+				stats.Successful++
+			case expectedErrno(call, info.Error):
+				stats.Expected++
+			default:
+				stats.Unexpected[info.Error]++
+			}
+		}
+	}
+	v.pending--
+	if v.issued != len(v.calls)*v.cfg.Runs || v.pending != 0 {
+		v.mu.Unlock()
+		return
+	}
+	report := v.report()
+	v.mu.Unlock()
+	if v.cfg.Done != nil {
+		v.cfg.Done(report)
+	}
+}
+
+func (v *Validation) report() *ValidationReport {
+	report := &ValidationReport{
+		Calls:  make(map[string]*ValidationCallStats),
+		Failed: v.failed,
+	}
+	for _, call := range v.calls {
+		stats := v.stats[call]
+		report.Calls[call.Name] = stats
+		unexpected := 0
+		for _, n := range stats.Unexpected {
+			unexpected += n
+		}
+		if stats.Finished != 0 && float64(unexpected) >= float64(stats.Finished)*v.cfg.MinUnexpected {
+			report.Broken = append(report.Broken, call.Name)
+		}
+	}
+	return report
+}
+
+func expectedErrno(call *prog.Syscall, errno int32) bool {
+	idx := sort.Search(len(call.Errnos), func(i int) bool {
+		return call.Errnos[i] >= uint64(errno)
+	})
+	return idx < len(call.Errnos) && call.Errnos[idx] == uint64(errno)
+}
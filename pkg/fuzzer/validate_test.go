@@ -0,0 +1,72 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := target.SyscallMap["test$errnos"]
+	assert.Equal(t, []uint64{2, 22}, call.Errnos)
+	enabled := map[*prog.Syscall]bool{
+		call:                         true,
+		target.SyscallMap["mutate0"]: true,
+	}
+	var report *ValidationReport
+	v := NewValidation(&ValidationConfig{
+		Target:        target,
+		EnabledCalls:  enabled,
+		Runs:          10,
+		MinUnexpected: 0.8,
+		Done: func(r *ValidationReport) {
+			report = r
+		},
+	}, rand.New(rand.NewSource(0)))
+	assert.Equal(t, 1, v.Calls())
+	var reqs []*queue.Request
+	for req := v.Next(); req != nil; req = v.Next() {
+		assert.Equal(t, "test$errnos", req.Prog.Calls[len(req.Prog.Calls)-1].Meta.Name)
+		for _, c := range req.Prog.Calls {
+			assert.True(t, enabled[c.Meta], "disabled call %v", c.Meta.Name)
+		}
+		reqs = append(reqs, req)
+	}
+	assert.Len(t, reqs, 10)
+	result := func(errno int32) *queue.Result {
+		return &queue.Result{Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{{
+			Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished,
+			Error: errno,
+		}}}}
+	}
+	// 1 crashed run, 1 expected errno, 8 EFAULTs.
+	reqs[0].Done(&queue.Result{Status: queue.Crashed})
+	reqs[1].Done(result(22))
+	for _, req := range reqs[2:9] {
+		req.Done(result(14))
+	}
+	assert.Nil(t, report)
+	reqs[9].Done(result(14))
+	if !assert.NotNil(t, report) {
+		return
+	}
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, []string{"test$errnos"}, report.Broken)
+	assert.Equal(t, &ValidationCallStats{
+		Finished:   9,
+		Expected:   1,
+		Unexpected: map[int32]int{14: 8},
+	}, report.Calls["test$errnos"])
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+// Max number of programs with kernel warnings that are kept for further fuzzing.
+const maxWarningProgs = 64
+
+// Non-fatal kernel warnings (e.g. WARNING with panic_on_warn=0, or console messages
+// that are not considered crashes) don't produce coverage signal, but they show that the program
+// reaches suspicious kernel paths. Such programs are a weak positive signal:
+// they are not added to the corpus, but are mutated in addition to the corpus programs.
+
+// AddWarningProg notes a program that triggered a non-fatal kernel warning.
+// The fuzzer notes programs with calls flagged by the executor itself, the manager notes programs
+// that were executing when the kernel printed a console message matching the warning patterns.
+func (fuzzer *Fuzzer) AddWarningProg(p *prog.Prog) {
+	if !fuzzer.Config.WarningFeedback {
+		return
+	}
+	fuzzer.Logf(2, "kernel warning in %s", p)
+	fuzzer.warningProgs.add(p.Clone(), fuzzer.rand())
+	fuzzer.statWarningProgs.Add(1)
+}
+
+// warningProgRequest mutates one of the programs that triggered kernel warnings.
+func warningProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
+	p := fuzzer.warningProgs.choose(rnd)
+	if p == nil {
+		return nil
+	}
+	newP := p.Clone()
+	newP.Mutate(rnd,
+		prog.RecommendedCalls,
+		fuzzer.ChoiceTable(),
+		fuzzer.Config.NoMutateCalls,
+		fuzzer.Config.Corpus.Programs(),
+	)
+	return &queue.Request{
+		Prog:     newP,
+		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
+		Stat:     fuzzer.statExecWarning,
+	}
+}
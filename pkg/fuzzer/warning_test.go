@@ -0,0 +1,52 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarningProgs(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus:          corpus.NewCorpus(ctx),
+		WarningFeedback: true,
+	}, rand.New(testutil.RandSource(t)), target)
+	rnd := rand.New(testutil.RandSource(t))
+	assert.Nil(t, warningProgRequest(fuzzer, rnd))
+
+	p, err := target.Deserialize([]byte(anyTestProg), prog.NonStrict)
+	assert.NoError(t, err)
+	req := &queue.Request{Prog: p}
+	fuzzer.processResult(req, &queue.Result{Info: &flatrpc.ProgInfo{
+		Calls: []*flatrpc.CallInfo{{Flags: flatrpc.CallFlagExecuted}},
+	}}, 0)
+	assert.Nil(t, warningProgRequest(fuzzer, rnd))
+	fuzzer.processResult(req, &queue.Result{Info: &flatrpc.ProgInfo{
+		Calls: []*flatrpc.CallInfo{{Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagKernelWarning}},
+	}}, 0)
+	assert.Equal(t, 1, fuzzer.statWarningProgs.Val())
+	req = warningProgRequest(fuzzer, rnd)
+	assert.NotNil(t, req)
+	assert.Equal(t, fuzzer.statExecWarning, req.Stat)
+
+	fuzzer.Config.WarningFeedback = false
+	fuzzer.AddWarningProg(p)
+	assert.Equal(t, 1, fuzzer.statWarningProgs.Val())
+}
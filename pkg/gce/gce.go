@@ -47,7 +47,9 @@ type Context struct {
 }
 
 type CreateArgs struct {
-	Preemptible   bool
+	Preemptible bool
+	// Spot VMs are the newer version of preemptible VMs without the 24h runtime limit.
+	Spot          bool
 	DisplayDevice bool
 }
 
@@ -117,8 +119,7 @@ func NewContext(customZoneID string) (*Context, error) {
 	return ctx, nil
 }
 
-func (ctx *Context) CreateInstance(name, machineType, image, sshkey string,
-	preemptible, displayDevice bool) (string, error) {
+func (ctx *Context) CreateInstance(name, machineType, image, sshkey string, args CreateArgs) (string, error) {
 	prefix := "https://www.googleapis.com/compute/v1/projects/" + ctx.ProjectID
 	sshkeyAttr := "syzkaller:" + sshkey
 	oneAttr := "1"
@@ -158,13 +159,18 @@ func (ctx *Context) CreateInstance(name, machineType, image, sshkey string,
 		},
 		Scheduling: &compute.Scheduling{
 			AutomaticRestart:  &falseAttr,
-			Preemptible:       preemptible,
+			Preemptible:       args.Preemptible || args.Spot,
 			OnHostMaintenance: "TERMINATE",
 		},
 		DisplayDevice: &compute.DisplayDevice{
-			EnableDisplay: displayDevice,
+			EnableDisplay: args.DisplayDevice,
 		},
 	}
+	if args.Spot {
+		instance.Scheduling.ProvisioningModel = "SPOT"
+		// Preempted instances are recreated from scratch anyway.
+		instance.Scheduling.InstanceTerminationAction = "DELETE"
+	}
 retry:
 	if !instance.Scheduling.Preemptible && strings.HasPrefix(machineType, "e2-") {
 		// Otherwise we get "Error 400: Efficient instances do not support
@@ -183,6 +189,8 @@ retry:
 		var resourcePoolExhaustedError resourcePoolExhaustedError
 		if errors.As(err, &resourcePoolExhaustedError) && instance.Scheduling.Preemptible {
 			instance.Scheduling.Preemptible = false
+			instance.Scheduling.ProvisioningModel = ""
+			instance.Scheduling.InstanceTerminationAction = ""
 			goto retry
 		}
 		return "", err
@@ -345,6 +353,28 @@ func (ctx *Context) waitForCompletion(typ, desc, opName string, ignoreNotFound b
 	}
 }
 
+// PreemptionNotice returns a channel that is closed when the current instance
+// (the one the program runs on) gets a preemption notice. GCE gives preemptible and spot
+// instances 30 seconds between the notice and the shutdown.
+func (ctx *Context) PreemptionNotice() <-chan struct{} {
+	preempted := make(chan struct{})
+	go func() {
+		for {
+			// The request hangs until the value changes.
+			val, err := ctx.getMeta("instance/preempted?wait_for_change=true")
+			if err != nil {
+				time.Sleep(10 * time.Second)
+				continue
+			}
+			if strings.TrimSpace(val) == "TRUE" {
+				close(preempted)
+				return
+			}
+		}
+	}()
+	return preempted
+}
+
 func (ctx *Context) getMeta(path string) (string, error) {
 	req, err := http.NewRequest("GET", "http://metadata.google.internal/computeMetadata/v1/"+path, nil)
 	if err != nil {
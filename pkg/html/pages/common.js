@@ -105,7 +105,70 @@ function addInputGroup(node) {
 	return false
 }
 
+// User preferences are saved in the browser local storage, so they are shared by all pages
+// of the same origin and persist across reloads.
+const prefsKey = "syz-prefs"
+
+function loadPrefs() {
+	try {
+		return JSON.parse(localStorage.getItem(prefsKey)) || {}
+	} catch (e) {
+		return {}
+	}
+}
+
+function savePref(name, value) {
+	const prefs = loadPrefs()
+	prefs[name] = value
+	try {
+		localStorage.setItem(prefsKey, JSON.stringify(prefs))
+	} catch (e) {
+		// Local storage may be disabled, the preference then applies only to the current page.
+	}
+}
+
+// The theme is "light", "dark" or "auto" (follow the system preference).
+const themes = ["auto", "dark", "light"]
+const themeIcons = {"auto": "◐", "dark": "☾", "light": "☀"}
+
+function currentTheme() {
+	const theme = loadPrefs().theme
+	return themes.includes(theme) ? theme : "auto"
+}
+
+function applyTheme(theme) {
+	let dark = theme == "dark"
+	if (theme == "auto" && window.matchMedia)
+		dark = window.matchMedia("(prefers-color-scheme: dark)").matches
+	document.documentElement.dataset.theme = dark ? "dark" : "light"
+	const toggle = document.getElementById("theme_toggle")
+	if (toggle) {
+		toggle.textContent = themeIcons[theme]
+		toggle.title = "theme: " + theme + " (click to change)"
+	}
+}
+
+function toggleTheme() {
+	const theme = themes[(themes.indexOf(currentTheme()) + 1) % themes.length]
+	savePref("theme", theme)
+	applyTheme(theme)
+}
+
+// The script is included in the page head, apply the theme before the body is rendered to avoid flashing.
+applyTheme(currentTheme())
+if (window.matchMedia) {
+	window.matchMedia("(prefers-color-scheme: dark)").addEventListener("change", function() {
+		applyTheme(currentTheme())
+	})
+}
+
 document.addEventListener("DOMContentLoaded", function() {
+	const toggle = document.createElement("button")
+	toggle.id = "theme_toggle"
+	toggle.addEventListener("click", toggleTheme)
+	document.body.appendChild(toggle)
+	applyTheme(currentTheme())
+
 	document.addEventListener('click', function(event) {
 		const collapsible = event.target.closest('.collapsible')
 		if (!collapsible) {
@@ -24,7 +24,8 @@ func CreateFromFS(fs fs.FS, patterns ...string) *template.Template {
 }
 
 func getHeadTemplate() string {
-	const headTempl = `<style type="text/css" media="screen">%v</style><script>%v</script>`
+	const headTempl = `<meta name="viewport" content="width=device-width, initial-scale=1">` +
+		`<style type="text/css" media="screen">%v</style><script>%v</script>`
 	return fmt.Sprintf(headTempl, style, js)
 }
 
@@ -41,14 +41,14 @@ func Register(insns []*Insn) {
 	if len(insns) == 0 {
 		panic("no instructions")
 	}
+	templates = append(append([]*Insn{}, insns...), sveInsns...)
 	insnset := &InsnSet{
-		Insns: append(insns, pseudo...),
+		Insns: append(append([]*Insn{}, templates...), pseudo...),
 	}
 	for _, insn := range insnset.Insns {
 		insnset.modeInsns.Add(insn)
 	}
 	iset.Arches[iset.ArchArm64] = insnset
-	templates = insns
 }
 
 func (insnset *InsnSet) GetInsns(mode iset.Mode, typ iset.Type) []iset.Insn {
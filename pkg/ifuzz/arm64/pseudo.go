@@ -22,16 +22,104 @@ var pseudo = []*Insn{
 			return gen.text
 		},
 	},
+	{
+		Name:   "PSEUDO_SYSREG",
+		Pseudo: true,
+		Priv:   true,
+		Generator: func(cfg *iset.Config, r *rand.Rand) []byte {
+			gen := makeGen(cfg, r)
+			gen.sysreg()
+			return gen.text
+		},
+	},
+	{
+		Name:   "PSEUDO_SME_MODE",
+		Pseudo: true,
+		Generator: func(cfg *iset.Config, r *rand.Rand) []byte {
+			gen := makeGen(cfg, r)
+			gen.smeMode()
+			return gen.text
+		},
+	},
+	{
+		Name:   "PSEUDO_EXCEPTION",
+		Pseudo: true,
+		Priv:   true,
+		Generator: func(cfg *iset.Config, r *rand.Rand) []byte {
+			gen := makeGen(cfg, r)
+			gen.exception()
+			return gen.text
+		},
+	},
+}
+
+// System registers that are trapped, emulated or context-switched by KVM.
+var sysregs = []uint32{
+	sysreg(3, 0, 0, 0, 0),   // MIDR_EL1
+	sysreg(3, 0, 0, 0, 5),   // MPIDR_EL1
+	sysreg(3, 0, 0, 4, 0),   // ID_AA64PFR0_EL1
+	sysreg(3, 0, 0, 4, 4),   // ID_AA64ZFR0_EL1
+	sysreg(3, 0, 0, 5, 0),   // ID_AA64DFR0_EL1
+	sysreg(3, 0, 0, 6, 0),   // ID_AA64ISAR0_EL1
+	sysreg(3, 0, 0, 7, 0),   // ID_AA64MMFR0_EL1
+	sysreg(3, 0, 1, 0, 0),   // SCTLR_EL1
+	sysreg(3, 0, 1, 0, 2),   // CPACR_EL1
+	sysreg(3, 0, 1, 2, 0),   // ZCR_EL1
+	sysreg(3, 0, 1, 2, 6),   // SMCR_EL1
+	sysreg(3, 0, 2, 0, 0),   // TTBR0_EL1
+	sysreg(3, 0, 2, 0, 1),   // TTBR1_EL1
+	sysreg(3, 0, 2, 0, 2),   // TCR_EL1
+	sysreg(3, 0, 4, 0, 0),   // SPSR_EL1
+	sysreg(3, 0, 4, 0, 1),   // ELR_EL1
+	sysreg(3, 0, 4, 6, 0),   // ICC_PMR_EL1
+	sysreg(3, 0, 5, 2, 0),   // ESR_EL1
+	sysreg(3, 0, 6, 0, 0),   // FAR_EL1
+	sysreg(3, 0, 7, 4, 0),   // PAR_EL1
+	sysreg(3, 0, 10, 2, 0),  // MAIR_EL1
+	sysreg(3, 0, 12, 0, 0),  // VBAR_EL1
+	sysreg(3, 0, 12, 11, 5), // ICC_SGI1R_EL1
+	sysreg(3, 0, 12, 12, 0), // ICC_IAR1_EL1
+	sysreg(3, 0, 12, 12, 1), // ICC_EOIR1_EL1
+	sysreg(3, 0, 12, 12, 5), // ICC_SRE_EL1
+	sysreg(3, 0, 12, 12, 7), // ICC_IGRPEN1_EL1
+	sysreg(3, 0, 13, 0, 1),  // CONTEXTIDR_EL1
+	sysreg(3, 3, 4, 2, 2),   // SVCR
+	sysreg(3, 3, 9, 12, 0),  // PMCR_EL0
+	sysreg(3, 3, 9, 12, 1),  // PMCNTENSET_EL0
+	sysreg(3, 3, 9, 12, 5),  // PMSELR_EL0
+	sysreg(3, 3, 9, 13, 0),  // PMCCNTR_EL0
+	sysreg(3, 3, 9, 14, 0),  // PMUSERENR_EL0
+	sysreg(3, 3, 13, 0, 5),  // TPIDR2_EL0
+	sysreg(3, 3, 14, 0, 1),  // CNTPCT_EL0
+	sysreg(3, 3, 14, 0, 2),  // CNTVCT_EL0
+	sysreg(3, 3, 14, 2, 1),  // CNTP_CTL_EL0
+	sysreg(3, 3, 14, 2, 2),  // CNTP_CVAL_EL0
+	sysreg(3, 3, 14, 3, 1),  // CNTV_CTL_EL0
+	sysreg(3, 3, 14, 3, 2),  // CNTV_CVAL_EL0
+	sysreg(2, 0, 0, 0, 4),   // DBGBVR0_EL1
+	sysreg(2, 0, 0, 0, 5),   // DBGBCR0_EL1
+	sysreg(2, 0, 0, 0, 6),   // DBGWVR0_EL1
+	sysreg(2, 0, 0, 0, 7),   // DBGWCR0_EL1
+	sysreg(2, 0, 0, 2, 2),   // MDSCR_EL1
+	sysreg(2, 0, 1, 0, 4),   // OSLAR_EL1
+	sysreg(2, 0, 1, 1, 4),   // OSLSR_EL1
+}
+
+// sysreg returns the system register encoding used in bits 20:5 of MRS/MSR.
+func sysreg(op0, op1, crn, crm, op2 uint32) uint32 {
+	return op0<<14 | op1<<11 | crn<<7 | crm<<3 | op2
 }
 
 type generator struct {
 	r    *rand.Rand
+	cfg  *iset.Config
 	text []byte
 }
 
 func makeGen(cfg *iset.Config, r *rand.Rand) *generator {
 	return &generator{
-		r: r,
+		r:   r,
+		cfg: cfg,
 	}
 }
 
@@ -46,6 +134,31 @@ func (gen *generator) smcccHvc() {
 	gen.byte(0x02, 0x00, 0x00, 0xd4)
 }
 
+func (gen *generator) sysreg() {
+	reg := sysregs[gen.r.Intn(len(sysregs))]
+	rt := uint32(gen.r.Intn(16))
+	if gen.r.Intn(2) == 0 {
+		// Encoding `mrs rt, reg`.
+		gen.imm32(0xd5200000 | reg<<5 | rt)
+		return
+	}
+	gen.movRegImm32(rt, uint32(iset.GenerateInt(gen.cfg, gen.r, 4)))
+	// Encoding `msr reg, rt`.
+	gen.imm32(0xd5000000 | reg<<5 | rt)
+}
+
+func (gen *generator) smeMode() {
+	// Encodings of `smstart/smstop {sm|za}`, i.e. `msr svcr{sm,za,smza}, #imm`.
+	crm := []uint32{0x2, 0x4, 0x6}[gen.r.Intn(3)] | uint32(gen.r.Intn(2))
+	gen.imm32(0xd503407f | crm<<8)
+}
+
+func (gen *generator) exception() {
+	// Encodings of svc, hvc, smc, brk and hlt with a random imm16.
+	opcode := []uint32{0xd4000001, 0xd4000002, 0xd4000003, 0xd4200000, 0xd4400000}[gen.r.Intn(5)]
+	gen.imm32(opcode | uint32(gen.r.Intn(0x10000))<<5)
+}
+
 func (gen *generator) movRegImm32(reg, imm uint32) {
 	gen.movRegImm16(reg, imm)
 	// Encoding `movk reg, imm16, LSL #16`.
@@ -0,0 +1,31 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package arm64
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSysregEncoding(t *testing.T) {
+	// Expected values are produced by the GNU assembler.
+	assert.Equal(t, uint32(0xd5380000), 0xd5200000|sysreg(3, 0, 0, 0, 0)<<5|0)  // mrs x0, midr_el1
+	assert.Equal(t, uint32(0xd5181000), 0xd5000000|sysreg(3, 0, 1, 0, 0)<<5|0)  // msr sctlr_el1, x0
+	assert.Equal(t, uint32(0xd53be041), 0xd5200000|sysreg(3, 3, 14, 0, 2)<<5|1) // mrs x1, cntvct_el0
+	assert.Equal(t, uint32(0xd5100242), 0xd5000000|sysreg(2, 0, 0, 2, 2)<<5|2)  // msr mdscr_el1, x2
+}
+
+func TestSVEInsns(t *testing.T) {
+	for _, insn := range sveInsns {
+		assert.Equal(t, insn.Opcode, insn.AsUInt32, insn.Name)
+		assert.Equal(t, insn.Opcode, insn.Opcode&insn.OpcodeMask, insn.Name)
+		mask := insn.OpcodeMask
+		for _, field := range insn.Fields {
+			bits := uint32((1<<field.Length)-1) << (field.Start - field.Length + 1)
+			assert.Zero(t, mask&bits, "%v: field %v overlaps with the opcode", insn.Name, field.Name)
+			mask |= bits
+		}
+	}
+}
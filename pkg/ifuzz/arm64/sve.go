@@ -0,0 +1,74 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// SVE and SME instructions are not present in the generated instruction descriptions
+// (gen/json/arm64.json covers only the base A64 instruction set).
+// KVM traps and context-switches the corresponding register state lazily,
+// so even a small subset is enough to exercise that code.
+
+package arm64
+
+var sveInsns = []*Insn{
+	// add <Zd>.<T>, <Zn>.<T>, <Zm>.<T>
+	{Name: "ADD (vectors, unpredicated)", OpcodeMask: 0xff20fc00, Opcode: 0x04200000, Fields: []InsnField{
+		{"size", 23, 2},
+		{"Zm", 20, 5},
+		{"Zn", 9, 5},
+		{"Zd", 4, 5},
+	}, AsUInt32: 0x04200000},
+	// sub <Zd>.<T>, <Zn>.<T>, <Zm>.<T>
+	{Name: "SUB (vectors, unpredicated)", OpcodeMask: 0xff20fc00, Opcode: 0x04200400, Fields: []InsnField{
+		{"size", 23, 2},
+		{"Zm", 20, 5},
+		{"Zn", 9, 5},
+		{"Zd", 4, 5},
+	}, AsUInt32: 0x04200400},
+	// fadd <Zd>.<T>, <Zn>.<T>, <Zm>.<T>
+	{Name: "FADD (vectors, unpredicated)", OpcodeMask: 0xff20fc00, Opcode: 0x65000000, Fields: []InsnField{
+		{"size", 23, 2},
+		{"Zm", 20, 5},
+		{"Zn", 9, 5},
+		{"Zd", 4, 5},
+	}, AsUInt32: 0x65000000},
+	// ptrue <Pd>.<T>{, <pattern>}
+	{Name: "PTRUE", OpcodeMask: 0xff3ffc10, Opcode: 0x2518e000, Fields: []InsnField{
+		{"size", 23, 2},
+		{"pattern", 9, 5},
+		{"Pd", 3, 4},
+	}, AsUInt32: 0x2518e000},
+	// whilelo <Pd>.<T>, <Xn>, <Xm>
+	{Name: "WHILELO", OpcodeMask: 0xff20fc10, Opcode: 0x25201c10, Fields: []InsnField{
+		{"size", 23, 2},
+		{"Rm", 20, 5},
+		{"Rn", 9, 5},
+		{"Pd", 3, 4},
+	}, AsUInt32: 0x25201c10},
+	// ld1d {<Zt>.D}, <Pg>/Z, [<Xn|SP>, <Xm>, LSL #3]
+	{Name: "LD1D (scalar plus scalar)", OpcodeMask: 0xffe0e000, Opcode: 0xa5e04000, Fields: []InsnField{
+		{"Rm", 20, 5},
+		{"Pg", 12, 3},
+		{"Rn", 9, 5},
+		{"Zt", 4, 5},
+	}, AsUInt32: 0xa5e04000},
+	// st1d {<Zt>.D}, <Pg>, [<Xn|SP>, <Xm>, LSL #3]
+	{Name: "ST1D (scalar plus scalar)", OpcodeMask: 0xffe0e000, Opcode: 0xe5e04000, Fields: []InsnField{
+		{"Rm", 20, 5},
+		{"Pg", 12, 3},
+		{"Rn", 9, 5},
+		{"Zt", 4, 5},
+	}, AsUInt32: 0xe5e04000},
+	// rdvl <Xd>, #<imm>
+	{Name: "RDVL", OpcodeMask: 0xfffff800, Opcode: 0x04bf5000, Fields: []InsnField{
+		{"imm6", 10, 6},
+		{"Rd", 4, 5},
+	}, AsUInt32: 0x04bf5000},
+	// rdsvl <Xd>, #<imm>
+	{Name: "RDSVL", OpcodeMask: 0xfffff800, Opcode: 0x04bf5800, Fields: []InsnField{
+		{"imm6", 10, 6},
+		{"Rd", 4, 5},
+	}, AsUInt32: 0x04bf5800},
+	// zero {<mask>}
+	{Name: "ZERO", OpcodeMask: 0xffffff00, Opcode: 0xc0080000, Fields: []InsnField{
+		{"imm8", 7, 8},
+	}, AsUInt32: 0xc0080000},
+}
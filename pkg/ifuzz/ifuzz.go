@@ -9,6 +9,7 @@ import (
 	_ "github.com/google/syzkaller/pkg/ifuzz/arm64/generated" // pull in generated instruction descriptions
 	"github.com/google/syzkaller/pkg/ifuzz/iset"
 	_ "github.com/google/syzkaller/pkg/ifuzz/powerpc/generated" // pull in generated instruction descriptions
+	_ "github.com/google/syzkaller/pkg/ifuzz/riscv64"           // pull in instruction descriptions
 	_ "github.com/google/syzkaller/pkg/ifuzz/x86/generated"     // pull in generated instruction descriptions
 )
 
@@ -22,6 +23,7 @@ const (
 	ArchX86     = iset.ArchX86
 	ArchPowerPC = iset.ArchPowerPC
 	ArchArm64   = iset.ArchArm64
+	ArchRiscv64 = iset.ArchRiscv64
 	ModeLong64  = iset.ModeLong64
 	ModeProt32  = iset.ModeProt32
 	ModeProt16  = iset.ModeProt16
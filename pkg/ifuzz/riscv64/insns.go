@@ -0,0 +1,213 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package riscv64
+
+// Major opcodes (bits 0-6) of 32-bit instructions.
+const (
+	opLoad    = 0x03
+	opMiscMem = 0x0f
+	opOpImm   = 0x13
+	opAuipc   = 0x17
+	opOpImm32 = 0x1b
+	opStore   = 0x23
+	opAmo     = 0x2f
+	opOp      = 0x33
+	opLui     = 0x37
+	opOp32    = 0x3b
+	opBranch  = 0x63
+	opJalr    = 0x67
+	opJal     = 0x6f
+	opSystem  = 0x73
+)
+
+// Instructions of RV64IMA, Zicsr, Zifencei, privileged architecture and the hypervisor extension.
+var insns = []*Insn{
+	utype("LUI", opLui),
+	utype("AUIPC", opAuipc),
+	utype("JAL", opJal),
+	itype("JALR", 0, opJalr),
+
+	itype("BEQ", 0, opBranch),
+	itype("BNE", 1, opBranch),
+	itype("BLT", 4, opBranch),
+	itype("BGE", 5, opBranch),
+	itype("BLTU", 6, opBranch),
+	itype("BGEU", 7, opBranch),
+
+	itype("LB", 0, opLoad),
+	itype("LH", 1, opLoad),
+	itype("LW", 2, opLoad),
+	itype("LD", 3, opLoad),
+	itype("LBU", 4, opLoad),
+	itype("LHU", 5, opLoad),
+	itype("LWU", 6, opLoad),
+	itype("SB", 0, opStore),
+	itype("SH", 1, opStore),
+	itype("SW", 2, opStore),
+	itype("SD", 3, opStore),
+
+	itype("ADDI", 0, opOpImm),
+	itype("SLTI", 2, opOpImm),
+	itype("SLTIU", 3, opOpImm),
+	itype("XORI", 4, opOpImm),
+	itype("ORI", 6, opOpImm),
+	itype("ANDI", 7, opOpImm),
+	shift("SLLI", 0x00, 1),
+	shift("SRLI", 0x00, 5),
+	shift("SRAI", 0x10, 5),
+	rtype("ADD", 0x00, 0, opOp),
+	rtype("SUB", 0x20, 0, opOp),
+	rtype("SLL", 0x00, 1, opOp),
+	rtype("SLT", 0x00, 2, opOp),
+	rtype("SLTU", 0x00, 3, opOp),
+	rtype("XOR", 0x00, 4, opOp),
+	rtype("SRL", 0x00, 5, opOp),
+	rtype("SRA", 0x20, 5, opOp),
+	rtype("OR", 0x00, 6, opOp),
+	rtype("AND", 0x00, 7, opOp),
+
+	itype("ADDIW", 0, opOpImm32),
+	rtype("SLLIW", 0x00, 1, opOpImm32),
+	rtype("SRLIW", 0x00, 5, opOpImm32),
+	rtype("SRAIW", 0x20, 5, opOpImm32),
+	rtype("ADDW", 0x00, 0, opOp32),
+	rtype("SUBW", 0x20, 0, opOp32),
+	rtype("SLLW", 0x00, 1, opOp32),
+	rtype("SRLW", 0x00, 5, opOp32),
+	rtype("SRAW", 0x20, 5, opOp32),
+
+	itype("FENCE", 0, opMiscMem),
+	itype("FENCE.I", 1, opMiscMem),
+	exact("ECALL", 0x00000073),
+	exact("EBREAK", 0x00100073),
+
+	// M extension.
+	rtype("MUL", 0x01, 0, opOp),
+	rtype("MULH", 0x01, 1, opOp),
+	rtype("MULHSU", 0x01, 2, opOp),
+	rtype("MULHU", 0x01, 3, opOp),
+	rtype("DIV", 0x01, 4, opOp),
+	rtype("DIVU", 0x01, 5, opOp),
+	rtype("REM", 0x01, 6, opOp),
+	rtype("REMU", 0x01, 7, opOp),
+	rtype("MULW", 0x01, 0, opOp32),
+	rtype("DIVW", 0x01, 4, opOp32),
+	rtype("DIVUW", 0x01, 5, opOp32),
+	rtype("REMW", 0x01, 6, opOp32),
+	rtype("REMUW", 0x01, 7, opOp32),
+
+	// A extension.
+	lr("LR.W", 2),
+	lr("LR.D", 3),
+	amo("SC.W", 0x03, 2),
+	amo("SC.D", 0x03, 3),
+	amo("AMOSWAP.W", 0x01, 2),
+	amo("AMOSWAP.D", 0x01, 3),
+	amo("AMOADD.W", 0x00, 2),
+	amo("AMOADD.D", 0x00, 3),
+	amo("AMOXOR.W", 0x04, 2),
+	amo("AMOXOR.D", 0x04, 3),
+	amo("AMOAND.W", 0x0c, 2),
+	amo("AMOAND.D", 0x0c, 3),
+	amo("AMOOR.W", 0x08, 2),
+	amo("AMOOR.D", 0x08, 3),
+	amo("AMOMIN.W", 0x10, 2),
+	amo("AMOMIN.D", 0x10, 3),
+	amo("AMOMAX.W", 0x14, 2),
+	amo("AMOMAX.D", 0x14, 3),
+	amo("AMOMINU.W", 0x18, 2),
+	amo("AMOMINU.D", 0x18, 3),
+	amo("AMOMAXU.W", 0x1c, 2),
+	amo("AMOMAXU.D", 0x1c, 3),
+
+	// Zicsr extension (accessibility depends on the CSR number, see also PSEUDO_CSR).
+	itype("CSRRW", 1, opSystem),
+	itype("CSRRS", 2, opSystem),
+	itype("CSRRC", 3, opSystem),
+	itype("CSRRWI", 5, opSystem),
+	itype("CSRRSI", 6, opSystem),
+	itype("CSRRCI", 7, opSystem),
+
+	// Privileged instructions.
+	priv(exact("SRET", 0x10200073)),
+	priv(exact("MRET", 0x30200073)),
+	priv(exact("WFI", 0x10500073)),
+	priv(fence("SFENCE.VMA", 0x09)),
+	priv(fence("SINVAL.VMA", 0x0b)),
+	priv(exact("SFENCE.W.INVAL", 0x18000073)),
+	priv(exact("SFENCE.INVAL.IR", 0x18100073)),
+
+	// Hypervisor extension.
+	priv(fence("HFENCE.VVMA", 0x11)),
+	priv(fence("HFENCE.GVMA", 0x31)),
+	priv(fence("HINVAL.VVMA", 0x13)),
+	priv(fence("HINVAL.GVMA", 0x33)),
+	priv(hload("HLV.B", 0x30, 0)),
+	priv(hload("HLV.BU", 0x30, 1)),
+	priv(hload("HLV.H", 0x32, 0)),
+	priv(hload("HLV.HU", 0x32, 1)),
+	priv(hload("HLVX.HU", 0x32, 3)),
+	priv(hload("HLV.W", 0x34, 0)),
+	priv(hload("HLV.WU", 0x34, 1)),
+	priv(hload("HLVX.WU", 0x34, 3)),
+	priv(hload("HLV.D", 0x36, 0)),
+	priv(hstore("HSV.B", 0x31)),
+	priv(hstore("HSV.H", 0x33)),
+	priv(hstore("HSV.W", 0x35)),
+	priv(hstore("HSV.D", 0x37)),
+}
+
+// utype: only the major opcode is fixed (U and J formats).
+func utype(name string, opcode uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0x7f, Opcode: opcode}
+}
+
+// itype: funct3 and the major opcode are fixed (I, S and B formats).
+func itype(name string, funct3, opcode uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0x707f, Opcode: funct3<<12 | opcode}
+}
+
+// rtype: funct7, funct3 and the major opcode are fixed (R format).
+func rtype(name string, funct7, funct3, opcode uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xfe00707f, Opcode: funct7<<25 | funct3<<12 | opcode}
+}
+
+// shift: RV64 immediate shifts with 6-bit shift amount.
+func shift(name string, funct6, funct3 uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xfc00707f, Opcode: funct6<<26 | funct3<<12 | opOpImm}
+}
+
+// amo: atomic memory operations, aq/rl bits are operands.
+func amo(name string, funct5, funct3 uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xf800707f, Opcode: funct5<<27 | funct3<<12 | opAmo}
+}
+
+// lr: load-reserved, rs2 must be 0.
+func lr(name string, funct3 uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xf9f0707f, Opcode: 0x02<<27 | funct3<<12 | opAmo}
+}
+
+// fence: SYSTEM instructions with rs1/rs2 operands and rd=0.
+func fence(name string, funct7 uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xfe007fff, Opcode: funct7<<25 | opSystem}
+}
+
+// hload: hypervisor virtual-machine loads, rs2 selects the variant.
+func hload(name string, funct7, rs2 uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xfff0707f, Opcode: funct7<<25 | rs2<<20 | 4<<12 | opSystem}
+}
+
+// hstore: hypervisor virtual-machine stores, rd must be 0.
+func hstore(name string, funct7 uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xfe007fff, Opcode: funct7<<25 | 4<<12 | opSystem}
+}
+
+func exact(name string, val uint32) *Insn {
+	return &Insn{Name: name, OpcodeMask: 0xffffffff, Opcode: val}
+}
+
+func priv(insn *Insn) *Insn {
+	insn.Priv = true
+	return insn
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Pseudo instructions for riscv64 architecture.
+
+package riscv64
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/google/syzkaller/pkg/ifuzz/iset"
+)
+
+var pseudo = []*Insn{
+	{
+		Name:   "PSEUDO_SBI_CALL",
+		Pseudo: true,
+		Priv:   true,
+		Generator: func(cfg *iset.Config, r *rand.Rand) []byte {
+			gen := makeGen(cfg, r)
+			gen.sbiCall()
+			return gen.text
+		},
+	},
+	{
+		Name:   "PSEUDO_CSR",
+		Pseudo: true,
+		Priv:   true,
+		Generator: func(cfg *iset.Config, r *rand.Rand) []byte {
+			gen := makeGen(cfg, r)
+			gen.csr()
+			return gen.text
+		},
+	},
+}
+
+const (
+	regA0 = 10
+	regA6 = 16
+	regA7 = 17
+)
+
+// SBI extension IDs (a7), legacy extensions are 0-8.
+var sbiExtensions = []uint32{
+	0x10,       // Base
+	0x54494D45, // Timer
+	0x735049,   // IPI
+	0x52464E43, // RFENCE
+	0x48534D,   // Hart state management
+	0x53525354, // System reset
+	0x504D55,   // Performance monitoring
+	0x4442434E, // Debug console
+	0x535441,   // Steal-time accounting
+	0x4E41434C, // Nested acceleration
+}
+
+// CSRs that are interesting for supervisor and hypervisor emulation.
+var csrs = []uint32{
+	0x100, 0x104, 0x105, 0x106, 0x10a, 0x140, 0x141, 0x142, 0x143, 0x144, 0x14d, 0x180, // supervisor
+	0x600, 0x602, 0x603, 0x604, 0x606, 0x607, 0x60a, 0x643, 0x644, 0x645, 0x64a, 0x680, // hypervisor
+	0x200, 0x204, 0x205, 0x240, 0x241, 0x242, 0x243, 0x244, 0x24d, 0x280, // virtual supervisor
+	0xc00, 0xc01, 0xc02, 0x001, 0x002, 0x003, 0x015, // unprivileged
+}
+
+type generator struct {
+	r    *rand.Rand
+	cfg  *iset.Config
+	text []byte
+}
+
+func makeGen(cfg *iset.Config, r *rand.Rand) *generator {
+	return &generator{
+		r:   r,
+		cfg: cfg,
+	}
+}
+
+func (gen *generator) sbiCall() {
+	var ext uint32
+	if gen.r.Intn(10) == 0 {
+		ext = uint32(gen.r.Intn(9))
+	} else {
+		ext = sbiExtensions[gen.r.Intn(len(sbiExtensions))]
+	}
+	gen.li(regA7, ext)
+	gen.li(regA6, uint32(gen.r.Intn(16)))
+	for reg := uint32(regA0); reg < regA0+3; reg++ {
+		gen.li(reg, uint32(iset.GenerateInt(gen.cfg, gen.r, 4)))
+	}
+	gen.insn(0x00000073) // ecall
+}
+
+func (gen *generator) csr() {
+	csr := csrs[gen.r.Intn(len(csrs))]
+	reg := uint32(5 + gen.r.Intn(3)) // t0-t2
+	gen.li(reg, uint32(iset.GenerateInt(gen.cfg, gen.r, 4)))
+	funct3 := uint32(1 + gen.r.Intn(3)) // csrrw, csrrs, csrrc
+	gen.insn(csr<<20 | reg<<15 | funct3<<12 | reg<<7 | opSystem)
+}
+
+// li loads a 32-bit immediate into the register with lui+addiw.
+func (gen *generator) li(reg, imm uint32) {
+	lo := uint32(int32(imm<<20) >> 20)
+	hi := (imm - lo) >> 12
+	gen.insn(hi<<12 | reg<<7 | opLui)
+	gen.insn((lo&0xfff)<<20 | reg<<15 | reg<<7 | opOpImm32)
+}
+
+func (gen *generator) insn(val uint32) {
+	gen.text = binary.LittleEndian.AppendUint32(gen.text, val)
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package riscv64 allows to generate and mutate riscv64 machine code.
+package riscv64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/syzkaller/pkg/ifuzz/iset"
+)
+
+type Insn struct {
+	Name       string
+	OpcodeMask uint32
+	Opcode     uint32
+	Pseudo     bool
+	Priv       bool
+	Generator  func(cfg *iset.Config, r *rand.Rand) []byte // for pseudo instructions
+}
+
+type InsnSet struct {
+	modeInsns iset.ModeInsns
+	Insns     []*Insn
+}
+
+func init() {
+	insnset := &InsnSet{
+		Insns: append(append([]*Insn{}, insns...), pseudo...),
+	}
+	for _, insn := range insnset.Insns {
+		insnset.modeInsns.Add(insn)
+	}
+	iset.Arches[iset.ArchRiscv64] = insnset
+}
+
+func (insnset *InsnSet) GetInsns(mode iset.Mode, typ iset.Type) []iset.Insn {
+	return insnset.modeInsns[mode][typ]
+}
+
+func (insn *Insn) Info() (string, iset.Mode, bool, bool) {
+	return insn.Name, 1 << iset.ModeLong64, insn.Pseudo, insn.Priv
+}
+
+// Encode returns the instruction with random operands (all bits not covered by OpcodeMask are operands).
+func (insn *Insn) Encode(cfg *iset.Config, r *rand.Rand) []byte {
+	if insn.Pseudo {
+		return insn.Generator(cfg, r)
+	}
+	ret := make([]byte, 4)
+	binary.LittleEndian.PutUint32(ret, insn.Opcode|r.Uint32()&^insn.OpcodeMask)
+	return ret
+}
+
+// Decode returns length of the first instruction in text.
+// Compressed (RVC) instructions are 2 bytes and are not checked against the instruction tables.
+func (insnset *InsnSet) Decode(mode iset.Mode, text []byte) (int, error) {
+	if len(text) < 2 {
+		return 0, fmt.Errorf("must be at least 2 bytes")
+	}
+	if text[0]&0x3 != 0x3 {
+		if text[0] == 0 && text[1] == 0 {
+			return 0, fmt.Errorf("illegal instruction")
+		}
+		return 2, nil
+	}
+	if len(text) < 4 {
+		return 0, fmt.Errorf("must be at least 4 bytes")
+	}
+	val := binary.LittleEndian.Uint32(text)
+	if ParseInsn(val) == nil {
+		return 0, fmt.Errorf("failed to decode %08x", val)
+	}
+	return 4, nil
+}
+
+func (insnset *InsnSet) DecodeExt(mode iset.Mode, text []byte) (int, error) {
+	return 0, fmt.Errorf("no external decoder")
+}
+
+// ParseInsn returns the description of the 32-bit instruction, or nil if it's unknown.
+func ParseInsn(val uint32) *Insn {
+	for _, insn := range insns {
+		if val&insn.OpcodeMask == insn.Opcode {
+			return insn
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package riscv64
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/ifuzz/iset"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInsn(t *testing.T) {
+	tests := map[uint32]string{
+		0x00150513: "ADDI",        // addi a0, a0, 1
+		0x40b50533: "SUB",         // sub a0, a0, a1
+		0x02b50533: "MUL",         // mul a0, a0, a1
+		0x03f51513: "SLLI",        // slli a0, a0, 63
+		0x00000073: "ECALL",       // ecall
+		0x10200073: "SRET",        // sret
+		0x62000073: "HFENCE.GVMA", // hfence.gvma zero, zero
+		0x6c05c573: "HLV.D",       // hlv.d a0, (a1)
+		0x6eb54073: "HSV.D",       // hsv.d a1, (a0)
+		0x100292f3: "CSRRW",       // csrrw t0, sstatus, t0
+		0x1005a52f: "LR.W",        // lr.w a0, (a1)
+		0x0cb5352f: "AMOSWAP.D",   // amoswap.d.aqrl a0, a1, (a0)
+	}
+	for val, name := range tests {
+		insn := ParseInsn(val)
+		if assert.NotNil(t, insn, "%08x", val) {
+			assert.Equal(t, name, insn.Name, "%08x", val)
+		}
+	}
+	assert.Nil(t, ParseInsn(0xffffffff))
+
+	insnset := iset.Arches[iset.ArchRiscv64]
+	for _, test := range []struct {
+		text string
+		size int
+	}{
+		{"13055500", 4},
+		{"0505", 2}, // c.addi a0, 1
+		{"0000", 0},
+		{"1305", 0},
+		{"ffffffff", 0},
+	} {
+		text, err := hex.DecodeString(test.text)
+		assert.NoError(t, err)
+		size, err := insnset.Decode(iset.ModeLong64, text)
+		assert.Equal(t, test.size, size, test.text)
+		assert.Equal(t, test.size == 0, err != nil, test.text)
+	}
+}
+
+func TestLoadImmediate(t *testing.T) {
+	r := rand.New(testutil.RandSource(t))
+	for i := 0; i < 1000; i++ {
+		imm := r.Uint32()
+		if i < 4 {
+			imm = []uint32{0, 0x7ff, 0x800, 0xffffffff}[i]
+		}
+		gen := makeGen(&iset.Config{}, r)
+		gen.li(regA0, imm)
+		assert.Len(t, gen.text, 8)
+		lui := binary.LittleEndian.Uint32(gen.text)
+		addiw := binary.LittleEndian.Uint32(gen.text[4:])
+		assert.Equal(t, "LUI", ParseInsn(lui).Name)
+		assert.Equal(t, "ADDIW", ParseInsn(addiw).Name)
+		// Emulate lui+addiw (the result is sign-extended to 64 bits, compare the lower half).
+		val := lui&0xfffff000 + uint32(int32(addiw)>>20)
+		assert.Equal(t, imm, val, "imm=%x", imm)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package image
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// Btrfs superblock copies and tree blocks (nodes and leaves) are checksummed.
+// We support crc32c and sha256 checksums (xxhash and blake2 images are mutated as is).
+// Tree blocks are located by scanning the image for blocks with the file system fsid in the header,
+// which is simpler than following the chunk tree and works for corrupted images as well.
+var btrfs = &FileSystem{
+	Name:   "btrfs",
+	Detect: btrfsDetect,
+	Fields: btrfsFields,
+	Fixup:  btrfsFixup,
+}
+
+const (
+	btrfsSuperSize      = 4096
+	btrfsMagicOffset    = 0x40
+	btrfsCsumSize       = 0x20
+	btrfsHeaderSize     = 101
+	btrfsItemSize       = 25
+	btrfsKeyPtrSize     = 33
+	btrfsCsumCRC32C     = 0
+	btrfsCsumSHA256     = 2
+	btrfsMetadataUUID   = 1 << 10
+	btrfsMaxTreeBlocks  = 1024
+	btrfsMaxItemsPerBlk = 64
+)
+
+var (
+	btrfsMagic         = []byte("_BHRfS_M")
+	btrfsSuperOffsets  = []int{64 << 10, 64 << 20} // the third copy at 256GB is beyond any image
+	btrfsCastagnoliTab = crc32.MakeTable(crc32.Castagnoli)
+)
+
+func btrfsDetect(data []byte) bool {
+	return btrfsSuper(data, btrfsSuperOffsets[0]) != nil
+}
+
+func btrfsSuper(data []byte, offset int) []byte {
+	if offset+btrfsSuperSize > len(data) {
+		return nil
+	}
+	sb := data[offset : offset+btrfsSuperSize]
+	if !bytes.Equal(sb[btrfsMagicOffset:btrfsMagicOffset+len(btrfsMagic)], btrfsMagic) {
+		return nil
+	}
+	return sb
+}
+
+// btrfsTreeBlocks returns offsets of tree blocks in the image.
+func btrfsTreeBlocks(data, sb []byte) []int {
+	fsid := sb[0x20:0x30]
+	if binary.LittleEndian.Uint64(sb[0xbc:])&btrfsMetadataUUID != 0 {
+		fsid = sb[0x23b:0x24b]
+	}
+	sectorSize := int(binary.LittleEndian.Uint32(sb[0x90:]))
+	nodeSize := int(binary.LittleEndian.Uint32(sb[0x94:]))
+	if sectorSize < 4096 || sectorSize > 64<<10 || nodeSize < sectorSize || nodeSize > 64<<10 {
+		return nil
+	}
+	var blocks []int
+	for off := 0; off+nodeSize <= len(data) && len(blocks) < btrfsMaxTreeBlocks; off += sectorSize {
+		if btrfsSuper(data, off) != nil {
+			continue
+		}
+		if bytes.Equal(data[off+0x20:off+0x30], fsid) {
+			blocks = append(blocks, off)
+			off += nodeSize - sectorSize
+		}
+	}
+	return blocks
+}
+
+func btrfsFields(data []byte) []Field {
+	fl := &fieldList{data: data}
+	base := btrfsSuperOffsets[0]
+	sb := btrfsSuper(data, base)
+	if sb == nil {
+		return nil
+	}
+	fl.add("generation", base, 0x48, 8)
+	fl.add("root", base, 0x50, 8)
+	fl.add("chunk_root", base, 0x58, 8)
+	fl.add("log_root", base, 0x60, 8)
+	fl.add("total_bytes", base, 0x70, 8)
+	fl.add("bytes_used", base, 0x78, 8)
+	fl.add("root_dir_objectid", base, 0x80, 8)
+	fl.add("num_devices", base, 0x88, 8)
+	fl.add("sectorsize", base, 0x90, 4)
+	fl.add("nodesize", base, 0x94, 4)
+	fl.add("stripesize", base, 0x9c, 4)
+	fl.add("sys_chunk_array_size", base, 0xa0, 4)
+	fl.add("chunk_root_generation", base, 0xa4, 8)
+	fl.add("compat_flags", base, 0xac, 8)
+	fl.add("compat_ro_flags", base, 0xb4, 8)
+	fl.add("incompat_flags", base, 0xbc, 8)
+	fl.add("root_level", base, 0xc6, 1)
+	fl.add("chunk_root_level", base, 0xc7, 1)
+	fl.add("log_root_level", base, 0xc8, 1)
+	fl.add("dev_item.total_bytes", base, 0xc9+0x8, 8)
+	fl.add("dev_item.bytes_used", base, 0xc9+0x10, 8)
+	fl.add("cache_generation", base, 0x22b, 8)
+	fl.add("uuid_tree_generation", base, 0x233, 8)
+	nodeSize := int(binary.LittleEndian.Uint32(sb[0x94:]))
+	for _, block := range btrfsTreeBlocks(data, sb) {
+		fl.add("header.bytenr", block, 0x30, 8)
+		fl.add("header.flags", block, 0x38, 8)
+		fl.add("header.generation", block, 0x50, 8)
+		fl.add("header.owner", block, 0x58, 8)
+		fl.add("header.nritems", block, 0x60, 4)
+		fl.add("header.level", block, 0x64, 1)
+		nritems := int(binary.LittleEndian.Uint32(data[block+0x60:]))
+		level := data[block+0x64]
+		itemSize := btrfsItemSize
+		if level != 0 {
+			itemSize = btrfsKeyPtrSize
+		}
+		nritems = min(nritems, btrfsMaxItemsPerBlk, (nodeSize-btrfsHeaderSize)/itemSize)
+		for i := 0; i < nritems; i++ {
+			item := block + btrfsHeaderSize + i*itemSize
+			fl.add("key.objectid", item, 0, 8)
+			fl.add("key.type", item, 8, 1)
+			fl.add("key.offset", item, 9, 8)
+			if level == 0 {
+				fl.add("item.offset", item, 17, 4)
+				fl.add("item.size", item, 21, 4)
+			} else {
+				fl.add("ptr.blockptr", item, 17, 8)
+				fl.add("ptr.generation", item, 25, 8)
+			}
+		}
+	}
+	return fl.fields
+}
+
+func btrfsFixup(data []byte) {
+	var sbs [][]byte
+	for _, off := range btrfsSuperOffsets {
+		if sb := btrfsSuper(data, off); sb != nil {
+			sbs = append(sbs, sb)
+		}
+	}
+	if len(sbs) == 0 {
+		return
+	}
+	csumType := binary.LittleEndian.Uint16(sbs[0][0xc4:])
+	if csumType != btrfsCsumCRC32C && csumType != btrfsCsumSHA256 {
+		return
+	}
+	nodeSize := int(binary.LittleEndian.Uint32(sbs[0][0x94:]))
+	for _, block := range btrfsTreeBlocks(data, sbs[0]) {
+		btrfsChecksum(data[block:block+nodeSize], csumType)
+	}
+	for _, sb := range sbs {
+		btrfsChecksum(sb, csumType)
+	}
+}
+
+// btrfsChecksum stores checksum of the block (except for the checksum itself) in the beginning.
+func btrfsChecksum(block []byte, csumType uint16) {
+	csum := block[:btrfsCsumSize]
+	for i := range csum {
+		csum[i] = 0
+	}
+	switch csumType {
+	case btrfsCsumCRC32C:
+		binary.LittleEndian.PutUint32(csum, crc32.Checksum(block[btrfsCsumSize:], btrfsCastagnoliTab))
+	case btrfsCsumSHA256:
+		sum := sha256.Sum256(block[btrfsCsumSize:])
+		copy(csum, sum[:])
+	}
+}
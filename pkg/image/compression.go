@@ -35,6 +35,15 @@ func MustDecompress(compressed []byte) (data []byte, dtor func()) {
 	return mustDecompress(compressed)
 }
 
+// Decompress is like MustDecompress, but returns an error for corrupted data instead of panicking.
+func Decompress(compressed []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := decompressWriter(buf, compressed); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func DecompressCheck(compressed []byte) error {
 	return decompressWriter(io.Discard, compressed)
 }
@@ -0,0 +1,283 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+)
+
+// ExFAT has the main and the backup boot regions, each is protected with a checksum sector.
+// Directory entry sets and the up-case table are checksummed as well.
+// We fix up the entry sets and the up-case table only in the first cluster of the root directory
+// (that's where the allocation bitmap and the up-case table entries live).
+var exfat = &FileSystem{
+	Name:     "exfat",
+	Detect:   exfatDetect,
+	Fields:   exfatFields,
+	Fixup:    exfatFixup,
+	Generate: exfatGenerate,
+}
+
+const (
+	exfatBootSectors    = 12
+	exfatEntrySize      = 32
+	exfatEntryBitmap    = 0x81
+	exfatEntryUpcase    = 0x82
+	exfatEntryLabel     = 0x83
+	exfatEntryFile      = 0x85
+	exfatEntryStream    = 0xc0
+	exfatFirstCluster   = 2
+	exfatMaxRootEntries = 256
+)
+
+var exfatName = []byte("EXFAT   ")
+
+func exfatDetect(data []byte) bool {
+	return len(data) >= 512 && bytes.Equal(data[3:11], exfatName)
+}
+
+// exfatGeometry returns sector size, cluster size and offset of the first cluster of the root directory.
+func exfatGeometry(data []byte) (sectorSize, clusterSize, root int, ok bool) {
+	sectorShift, clusterShift := data[108], data[109]
+	if sectorShift < 9 || sectorShift > 12 || clusterShift > 25-sectorShift {
+		return
+	}
+	sectorSize = 1 << sectorShift
+	clusterSize = sectorSize << clusterShift
+	heap := int(binary.LittleEndian.Uint32(data[88:]))
+	rootCluster := int(binary.LittleEndian.Uint32(data[96:]))
+	root = heap*sectorSize + (rootCluster-exfatFirstCluster)*clusterSize
+	if heap <= 0 || rootCluster < exfatFirstCluster || root < 0 || root+clusterSize > len(data) {
+		return sectorSize, clusterSize, -1, true
+	}
+	return sectorSize, clusterSize, root, true
+}
+
+// exfatRootEntries returns offsets of the directory entries in the first cluster of the root directory.
+func exfatRootEntries(data []byte) []int {
+	_, clusterSize, root, ok := exfatGeometry(data)
+	if !ok || root < 0 {
+		return nil
+	}
+	var entries []int
+	for off := root; off < root+clusterSize && len(entries) < exfatMaxRootEntries; off += exfatEntrySize {
+		if data[off] == 0 {
+			break
+		}
+		entries = append(entries, off)
+	}
+	return entries
+}
+
+func exfatFields(data []byte) []Field {
+	fl := &fieldList{data: data}
+	fl.add("VolumeLength", 0, 72, 8)
+	fl.add("FatOffset", 0, 80, 4)
+	fl.add("FatLength", 0, 84, 4)
+	fl.add("ClusterHeapOffset", 0, 88, 4)
+	fl.add("ClusterCount", 0, 92, 4)
+	fl.add("FirstClusterOfRootDirectory", 0, 96, 4)
+	fl.add("FileSystemRevision", 0, 104, 2)
+	fl.add("VolumeFlags", 0, 106, 2)
+	fl.add("BytesPerSectorShift", 0, 108, 1)
+	fl.add("SectorsPerClusterShift", 0, 109, 1)
+	fl.add("NumberOfFats", 0, 110, 1)
+	fl.add("PercentInUse", 0, 112, 1)
+	for _, entry := range exfatRootEntries(data) {
+		fl.add("EntryType", entry, 0, 1)
+		switch data[entry] {
+		case exfatEntryBitmap, exfatEntryUpcase:
+			fl.add("FirstCluster", entry, 20, 4)
+			fl.add("DataLength", entry, 24, 8)
+		case exfatEntryLabel:
+			fl.add("CharacterCount", entry, 1, 1)
+		case exfatEntryFile:
+			fl.add("SecondaryCount", entry, 1, 1)
+			fl.add("FileAttributes", entry, 4, 2)
+		case exfatEntryStream:
+			fl.add("GeneralSecondaryFlags", entry, 1, 1)
+			fl.add("NameLength", entry, 3, 1)
+			fl.add("ValidDataLength", entry, 8, 8)
+			fl.add("FirstCluster", entry, 20, 4)
+			fl.add("DataLength", entry, 24, 8)
+		}
+	}
+	return fl.fields
+}
+
+func exfatFixup(data []byte) {
+	sectorSize, clusterSize, _, ok := exfatGeometry(data)
+	if !ok {
+		return
+	}
+	entries := exfatRootEntries(data)
+	for i, entry := range entries {
+		switch data[entry] {
+		case exfatEntryFile:
+			count := int(data[entry+1])
+			if i+count < len(entries) && entries[i+count] == entry+count*exfatEntrySize {
+				set := data[entry : entry+(count+1)*exfatEntrySize]
+				binary.LittleEndian.PutUint16(set[2:], exfatEntrySetChecksum(set))
+			}
+		case exfatEntryUpcase:
+			table := exfatClusterData(data, entry, sectorSize, clusterSize)
+			if table != nil {
+				binary.LittleEndian.PutUint32(data[entry+4:], exfatTableChecksum(table))
+			}
+		}
+	}
+	exfatBootChecksum(data, sectorSize, 0)
+	if backup := exfatBootSectors * sectorSize; backup < len(data) && exfatDetect(data[backup:]) {
+		exfatBootChecksum(data, sectorSize, exfatBootSectors)
+	}
+}
+
+// exfatClusterData returns contents of the file referenced by the bitmap/up-case entry
+// if the file fits into a single cluster.
+func exfatClusterData(data []byte, entry, sectorSize, clusterSize int) []byte {
+	heap := int(binary.LittleEndian.Uint32(data[88:]))
+	cluster := int(binary.LittleEndian.Uint32(data[entry+20:]))
+	size := binary.LittleEndian.Uint64(data[entry+24:])
+	off := heap*sectorSize + (cluster-exfatFirstCluster)*clusterSize
+	if cluster < exfatFirstCluster || size == 0 || size > uint64(clusterSize) ||
+		off < 0 || off+int(size) > len(data) {
+		return nil
+	}
+	return data[off : off+int(size)]
+}
+
+// exfatBootChecksum fills the checksum sector of the boot region starting at the given sector.
+func exfatBootChecksum(data []byte, sectorSize, start int) {
+	region := start * sectorSize
+	if region+exfatBootSectors*sectorSize > len(data) {
+		return
+	}
+	var sum uint32
+	for i, v := range data[region : region+(exfatBootSectors-1)*sectorSize] {
+		if i == 106 || i == 107 || i == 112 {
+			// VolumeFlags and PercentInUse are excluded.
+			continue
+		}
+		sum = (sum<<31 | sum>>1) + uint32(v)
+	}
+	checksum := data[region+(exfatBootSectors-1)*sectorSize : region+exfatBootSectors*sectorSize]
+	for i := 0; i < sectorSize; i += 4 {
+		binary.LittleEndian.PutUint32(checksum[i:], sum)
+	}
+}
+
+func exfatEntrySetChecksum(set []byte) uint16 {
+	var sum uint16
+	for i, v := range set {
+		if i == 2 || i == 3 {
+			// The checksum itself.
+			continue
+		}
+		sum = (sum<<15 | sum>>1) + uint16(v)
+	}
+	return sum
+}
+
+func exfatTableChecksum(table []byte) uint32 {
+	var sum uint32
+	for _, v := range table {
+		sum = (sum<<31 | sum>>1) + uint32(v)
+	}
+	return sum
+}
+
+// exfatGenerate creates an image with an empty root directory with a volume label.
+// The allocation bitmap, the up-case table and the root directory occupy one cluster each.
+func exfatGenerate(r *rand.Rand) []byte {
+	const (
+		sectorShift  = 9
+		sectorSize   = 1 << sectorShift
+		fatOffset    = 2 * exfatBootSectors
+		bitmapClu    = exfatFirstCluster
+		upcaseClu    = exfatFirstCluster + 1
+		rootClu      = exfatFirstCluster + 2
+		usedClusters = 3
+	)
+	clusterShift := r.Intn(4)
+	clusterSize := sectorSize << clusterShift
+	clusterCount := 64 + r.Intn(449)
+	numFats := 1 + r.Intn(2)
+	fatLength := ((clusterCount+exfatFirstCluster)*4 + sectorSize - 1) / sectorSize
+	heap := fatOffset + numFats*fatLength
+	volumeLength := heap + clusterCount<<clusterShift
+	data := make([]byte, volumeLength*sectorSize)
+
+	boot := data[:sectorSize]
+	copy(boot, []byte{0xeb, 0x76, 0x90})
+	copy(boot[3:], exfatName)
+	binary.LittleEndian.PutUint64(boot[72:], uint64(volumeLength))
+	binary.LittleEndian.PutUint32(boot[80:], fatOffset)
+	binary.LittleEndian.PutUint32(boot[84:], uint32(fatLength))
+	binary.LittleEndian.PutUint32(boot[88:], uint32(heap))
+	binary.LittleEndian.PutUint32(boot[92:], uint32(clusterCount))
+	binary.LittleEndian.PutUint32(boot[96:], rootClu)
+	binary.LittleEndian.PutUint32(boot[100:], r.Uint32())
+	binary.LittleEndian.PutUint16(boot[104:], 0x100)
+	boot[108] = sectorShift
+	boot[109] = byte(clusterShift)
+	boot[110] = byte(numFats)
+	boot[111] = 0x80
+	boot[112] = byte(usedClusters * 100 / clusterCount)
+	binary.LittleEndian.PutUint16(boot[510:], 0xaa55)
+	for sector := 1; sector <= 8; sector++ {
+		// Extended boot sector signatures.
+		binary.LittleEndian.PutUint32(data[(sector+1)*sectorSize-4:], 0xaa550000)
+	}
+
+	for fat := 0; fat < numFats; fat++ {
+		entries := data[(fatOffset+fat*fatLength)*sectorSize:]
+		binary.LittleEndian.PutUint32(entries[0:], 0xfffffff8)
+		binary.LittleEndian.PutUint32(entries[4:], 0xffffffff)
+		for clu := bitmapClu; clu <= rootClu; clu++ {
+			binary.LittleEndian.PutUint32(entries[clu*4:], 0xffffffff)
+		}
+	}
+
+	cluster := func(clu int) []byte {
+		off := heap*sectorSize + (clu-exfatFirstCluster)*clusterSize
+		return data[off : off+clusterSize]
+	}
+	bitmapSize := (clusterCount + 7) / 8
+	cluster(bitmapClu)[0] = 1<<usedClusters - 1
+	// Compressed up-case table: identity mapping except for a-z.
+	var upcase []byte
+	for _, v := range []uint16{0xffff, 'a'} {
+		upcase = binary.LittleEndian.AppendUint16(upcase, v)
+	}
+	for c := 'A'; c <= 'Z'; c++ {
+		upcase = binary.LittleEndian.AppendUint16(upcase, uint16(c))
+	}
+	for _, v := range []uint16{0xffff, 0x10000 - 'z' - 1} {
+		upcase = binary.LittleEndian.AppendUint16(upcase, v)
+	}
+	copy(cluster(upcaseClu), upcase)
+
+	root := cluster(rootClu)
+	label := "syzkaller"[:1+r.Intn(9)]
+	root[0] = exfatEntryLabel
+	root[1] = byte(len(label))
+	for i, c := range label {
+		binary.LittleEndian.PutUint16(root[2+i*2:], uint16(c))
+	}
+	entry := root[exfatEntrySize:]
+	entry[0] = exfatEntryBitmap
+	binary.LittleEndian.PutUint32(entry[20:], bitmapClu)
+	binary.LittleEndian.PutUint64(entry[24:], uint64(bitmapSize))
+	entry = root[2*exfatEntrySize:]
+	entry[0] = exfatEntryUpcase
+	binary.LittleEndian.PutUint32(entry[4:], exfatTableChecksum(upcase))
+	binary.LittleEndian.PutUint32(entry[20:], upcaseClu)
+	binary.LittleEndian.PutUint64(entry[24:], uint64(len(upcase)))
+
+	exfatBootChecksum(data, sectorSize, 0)
+	copy(data[exfatBootSectors*sectorSize:], data[:exfatBootSectors*sectorSize])
+	return data
+}
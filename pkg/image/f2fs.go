@@ -0,0 +1,166 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package image
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// F2FS has 2 superblock copies (checksummed only with the sb_checksum feature)
+// and 2 checkpoint packs, each starts and ends with a checksummed checkpoint block.
+// The checksums are crc32 seeded with the superblock magic.
+var f2fs = &FileSystem{
+	Name:   "f2fs",
+	Detect: f2fsDetect,
+	Fields: f2fsFields,
+	Fixup:  f2fsFixup,
+}
+
+const (
+	f2fsMagic          = 0xf2f52010
+	f2fsSuperOffset    = 1024
+	f2fsSuperCRCOffset = 3068
+	f2fsFeatureSBCRC   = 0x800
+	f2fsCPCRCOffset    = 164
+	f2fsMinCPCRCOffset = 192
+)
+
+func f2fsDetect(data []byte) bool {
+	return f2fsSuper(data, 0) != nil
+}
+
+// f2fsSuper returns the superblock copy in the given block.
+func f2fsSuper(data []byte, block int) []byte {
+	offset := block*4096 + f2fsSuperOffset
+	if offset+f2fsSuperCRCOffset+4 > len(data) {
+		return nil
+	}
+	sb := data[offset : offset+f2fsSuperCRCOffset+4]
+	if binary.LittleEndian.Uint32(sb) != f2fsMagic {
+		return nil
+	}
+	return sb
+}
+
+// f2fsCheckpoints returns offsets of the checkpoint blocks and the block size.
+func f2fsCheckpoints(data, sb []byte) ([]int, int) {
+	logBlockSize := binary.LittleEndian.Uint32(sb[16:])
+	logBlocksPerSeg := binary.LittleEndian.Uint32(sb[20:])
+	if logBlockSize != 12 || logBlocksPerSeg > 12 {
+		return nil, 0
+	}
+	blockSize := 1 << logBlockSize
+	cpAddr := int(binary.LittleEndian.Uint32(sb[76:]))
+	var blocks []int
+	for pack := 0; pack < 2; pack++ {
+		start := (cpAddr + pack<<logBlocksPerSeg) * blockSize
+		if start < 0 || start+blockSize > len(data) {
+			break
+		}
+		blocks = append(blocks, start)
+		total := int(binary.LittleEndian.Uint32(data[start+136:]))
+		end := start + (total-1)*blockSize
+		if total > 1 && total <= 1<<logBlocksPerSeg && end+blockSize <= len(data) {
+			blocks = append(blocks, end)
+		}
+	}
+	return blocks, blockSize
+}
+
+func f2fsFields(data []byte) []Field {
+	fl := &fieldList{data: data}
+	sb := f2fsSuper(data, 0)
+	if sb == nil {
+		return nil
+	}
+	base := f2fsSuperOffset
+	fl.add("major_ver", base, 4, 2)
+	fl.add("minor_ver", base, 6, 2)
+	fl.add("log_sectorsize", base, 8, 4)
+	fl.add("log_sectors_per_block", base, 12, 4)
+	fl.add("log_blocksize", base, 16, 4)
+	fl.add("log_blocks_per_seg", base, 20, 4)
+	fl.add("segs_per_sec", base, 24, 4)
+	fl.add("secs_per_zone", base, 28, 4)
+	fl.add("block_count", base, 36, 8)
+	fl.add("section_count", base, 44, 4)
+	fl.add("segment_count", base, 48, 4)
+	fl.add("segment_count_ckpt", base, 52, 4)
+	fl.add("segment_count_sit", base, 56, 4)
+	fl.add("segment_count_nat", base, 60, 4)
+	fl.add("segment_count_ssa", base, 64, 4)
+	fl.add("segment_count_main", base, 68, 4)
+	fl.add("segment0_blkaddr", base, 72, 4)
+	fl.add("cp_blkaddr", base, 76, 4)
+	fl.add("sit_blkaddr", base, 80, 4)
+	fl.add("nat_blkaddr", base, 84, 4)
+	fl.add("ssa_blkaddr", base, 88, 4)
+	fl.add("main_blkaddr", base, 92, 4)
+	fl.add("root_ino", base, 96, 4)
+	fl.add("node_ino", base, 100, 4)
+	fl.add("meta_ino", base, 104, 4)
+	fl.add("extension_count", base, 1148, 4)
+	fl.add("cp_payload", base, 1664, 4)
+	fl.add("feature", base, 2180, 4)
+	fl.add("hot_ext_count", base, 2757, 1)
+	fl.add("s_encoding", base, 2758, 2)
+	fl.add("s_encoding_flags", base, 2760, 2)
+	cps, _ := f2fsCheckpoints(data, sb)
+	for _, cp := range cps {
+		fl.add("checkpoint_ver", cp, 0, 8)
+		fl.add("user_block_count", cp, 8, 8)
+		fl.add("valid_block_count", cp, 16, 8)
+		fl.add("rsvd_segment_count", cp, 24, 4)
+		fl.add("overprov_segment_count", cp, 28, 4)
+		fl.add("free_segment_count", cp, 32, 4)
+		for i := 0; i < 3; i++ {
+			fl.add("cur_node_segno", cp, 36+i*4, 4)
+			fl.add("cur_node_blkoff", cp, 68+i*2, 2)
+			fl.add("cur_data_segno", cp, 84+i*4, 4)
+			fl.add("cur_data_blkoff", cp, 116+i*2, 2)
+		}
+		fl.add("ckpt_flags", cp, 132, 4)
+		fl.add("cp_pack_total_block_count", cp, 136, 4)
+		fl.add("cp_pack_start_sum", cp, 140, 4)
+		fl.add("valid_node_count", cp, 144, 4)
+		fl.add("valid_inode_count", cp, 148, 4)
+		fl.add("next_free_nid", cp, 152, 4)
+		fl.add("sit_ver_bitmap_bytesize", cp, 156, 4)
+		fl.add("nat_ver_bitmap_bytesize", cp, 160, 4)
+	}
+	return fl.fields
+}
+
+func f2fsFixup(data []byte) {
+	sb := f2fsSuper(data, 0)
+	if sb == nil {
+		return
+	}
+	cps, blockSize := f2fsCheckpoints(data, sb)
+	for _, cp := range cps {
+		block := data[cp : cp+blockSize]
+		crcOffset := int(binary.LittleEndian.Uint32(block[f2fsCPCRCOffset:]))
+		if crcOffset < f2fsMinCPCRCOffset || crcOffset > blockSize-4 {
+			continue
+		}
+		crc := f2fsCRC(f2fsMagic, block[:crcOffset])
+		if crcOffset < blockSize-4 {
+			crc = f2fsCRC(crc, block[crcOffset+4:])
+		}
+		binary.LittleEndian.PutUint32(block[crcOffset:], crc)
+	}
+	for _, sb := range [][]byte{sb, f2fsSuper(data, 1)} {
+		if sb == nil || binary.LittleEndian.Uint32(sb[2180:])&f2fsFeatureSBCRC == 0 ||
+			binary.LittleEndian.Uint32(sb[32:]) != f2fsSuperCRCOffset {
+			continue
+		}
+		binary.LittleEndian.PutUint32(sb[f2fsSuperCRCOffset:], f2fsCRC(f2fsMagic, sb[:f2fsSuperCRCOffset]))
+	}
+}
+
+// f2fsCRC is the kernel crc32_le without the pre and post inversion.
+func f2fsCRC(crc uint32, data []byte) uint32 {
+	return ^crc32.Update(^crc, crc32.IEEETable, data)
+}
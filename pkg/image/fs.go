@@ -0,0 +1,62 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package image
+
+import (
+	"math/rand"
+)
+
+// FileSystem describes on-disk format of a file system image.
+// It allows to mutate images in a structure-aware way: mutations can target metadata fields
+// (instead of random bytes of an opaque blob), and checksums are recalculated after mutation
+// so that the kernel does not reject the mutated image right away on a checksum mismatch.
+type FileSystem struct {
+	Name string
+	// Detect returns whether data contains an image of this file system.
+	Detect func(data []byte) bool
+	// Fields returns metadata fields of the image that are interesting to mutate.
+	Fields func(data []byte) []Field
+	// Fixup recalculates checksums of the metadata in the (mutated) image in place.
+	// Checksums of structures that are too corrupted to be located are left intact.
+	Fixup func(data []byte)
+	// Generate creates a new structurally valid image with random parameters (optional).
+	Generate func(r *rand.Rand) []byte
+}
+
+// Field is a little-endian integer field in an image.
+type Field struct {
+	Name   string
+	Offset int
+	Size   int // 1, 2, 4 or 8
+}
+
+var FileSystems = []*FileSystem{
+	btrfs,
+	f2fs,
+	exfat,
+}
+
+// DetectFileSystem returns the file system of the image, or nil if it's not known.
+func DetectFileSystem(data []byte) *FileSystem {
+	for _, fs := range FileSystems {
+		if fs.Detect(data) {
+			return fs
+		}
+	}
+	return nil
+}
+
+// fieldList simplifies construction of lists of fields of on-disk structures.
+type fieldList struct {
+	data   []byte
+	fields []Field
+}
+
+// add adds the field at base+offset, if it fits into the image.
+func (fl *fieldList) add(name string, base, offset, size int) {
+	if base < 0 || base+offset+size > len(fl.data) {
+		return
+	}
+	fl.fields = append(fl.fields, Field{Name: name, Offset: base + offset, Size: size})
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package image_test
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/google/syzkaller/pkg/image"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/stretchr/testify/assert"
+)
+
+// forEachSeed calls fn for images from up to max seeds of the file system (all seeds if max is 0).
+// Images are big, so they are decompressed one at a time.
+func forEachSeed(t *testing.T, fs string, max int, fn func(file string, image []byte)) {
+	files, err := filepath.Glob(filepath.FromSlash("../../sys/linux/test/syz_mount_image_" + fs + "_*"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no %v seeds: %v", fs, err)
+	}
+	if testing.Short() && (max == 0 || max > 4) {
+		max = 4
+	}
+	if max != 0 && len(files) > max {
+		// Seeds are generated with different mkfs flags, so take a uniform sample.
+		var sample []string
+		for i := 0; i < max; i++ {
+			sample = append(sample, files[i*len(files)/max])
+		}
+		files = sample
+	}
+	target, err := prog.GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := target.Deserialize(data, prog.Strict)
+		if err != nil {
+			t.Fatalf("failed to deserialize %v: %v", file, err)
+		}
+		compressed := p.Calls[0].Args[6].(*prog.PointerArg).Res.(*prog.DataArg).Data()
+		image, dtor := MustDecompress(compressed)
+		fn(filepath.Base(file), append([]byte{}, image...))
+		dtor()
+	}
+}
+
+func TestFileSystemSeeds(t *testing.T) {
+	// Seeds are produced by mkfs, so they must have correct checksums already.
+	for _, fs := range FileSystems {
+		fs := fs
+		t.Run(fs.Name, func(t *testing.T) {
+			forEachSeed(t, fs.Name, 0, func(file string, image []byte) {
+				assert.Equal(t, fs, DetectFileSystem(image), file)
+				assert.NotEmpty(t, fs.Fields(image), file)
+				fixed := append([]byte{}, image...)
+				fs.Fixup(fixed)
+				assert.True(t, bytes.Equal(image, fixed), "%v: fixup changed the image", file)
+			})
+		})
+	}
+}
+
+func TestFileSystemMutate(t *testing.T) {
+	r := rand.New(testutil.RandSource(t))
+	for _, fs := range FileSystems {
+		fs := fs
+		t.Run(fs.Name, func(t *testing.T) {
+			var images [][]byte
+			forEachSeed(t, fs.Name, 2, func(file string, image []byte) {
+				images = append(images, image)
+			})
+			if fs.Generate != nil {
+				images = append(images, fs.Generate(r))
+			}
+			for iter := 0; iter < testutil.IterCount()/10; iter++ {
+				image := append([]byte{}, images[r.Intn(len(images))]...)
+				fields := fs.Fields(image)
+				for i := 0; i < 1+r.Intn(10); i++ {
+					// Mutate both fields and random bytes to make sure we don't panic on broken images.
+					if f := fields[r.Intn(len(fields))]; r.Intn(2) == 0 {
+						for j := 0; j < f.Size; j++ {
+							image[f.Offset+j] = byte(r.Intn(256))
+						}
+					} else {
+						image[r.Intn(len(image))] = byte(r.Intn(256))
+					}
+				}
+				fs.Fields(image)
+				fs.Fixup(image)
+				// Fixup is idempotent.
+				fixed := append([]byte{}, image...)
+				fs.Fixup(fixed)
+				assert.True(t, bytes.Equal(image, fixed))
+			}
+		})
+	}
+}
+
+func TestExfatGenerate(t *testing.T) {
+	r := rand.New(testutil.RandSource(t))
+	fs := DetectFileSystem(FileSystems[len(FileSystems)-1].Generate(r))
+	if !assert.NotNil(t, fs) || !assert.Equal(t, "exfat", fs.Name) {
+		return
+	}
+	for i := 0; i < testutil.IterCount(); i++ {
+		image := fs.Generate(r)
+		assert.Equal(t, fs, DetectFileSystem(image))
+		fixed := append([]byte{}, image...)
+		fs.Fixup(fixed)
+		assert.True(t, bytes.Equal(image, fixed), "generated image has bad checksums")
+		names := make(map[string]bool)
+		for _, f := range fs.Fields(image) {
+			names[f.Name] = true
+		}
+		// The bitmap and up-case table entries in the root directory.
+		assert.True(t, names["FirstCluster"])
+		assert.True(t, names["CharacterCount"])
+	}
+}
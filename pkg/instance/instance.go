@@ -42,13 +42,14 @@ type env struct {
 }
 
 type BuildKernelConfig struct {
-	CompilerBin  string
-	LinkerBin    string
-	CcacheBin    string
-	UserspaceDir string
-	CmdlineFile  string
-	SysctlFile   string
-	KernelConfig []byte
+	CompilerBin     string
+	LinkerBin       string
+	CcacheBin       string
+	UserspaceDir    string
+	CmdlineFile     string
+	SysctlFile      string
+	ExternalModules []build.ExternalModule
+	KernelConfig    []byte
 }
 
 func NewEnv(cfg *mgrconfig.Config, buildSem, testSem *Semaphore) (Env, error) {
@@ -141,18 +142,19 @@ func (env *env) BuildKernel(buildCfg *BuildKernelConfig) (
 	}
 	imageDir := filepath.Join(env.cfg.Workdir, "image")
 	params := build.Params{
-		TargetOS:     env.cfg.TargetOS,
-		TargetArch:   env.cfg.TargetVMArch,
-		VMType:       env.cfg.Type,
-		KernelDir:    env.cfg.KernelSrc,
-		OutputDir:    imageDir,
-		Compiler:     buildCfg.CompilerBin,
-		Linker:       buildCfg.LinkerBin,
-		Ccache:       buildCfg.CcacheBin,
-		UserspaceDir: buildCfg.UserspaceDir,
-		CmdlineFile:  buildCfg.CmdlineFile,
-		SysctlFile:   buildCfg.SysctlFile,
-		Config:       buildCfg.KernelConfig,
+		TargetOS:        env.cfg.TargetOS,
+		TargetArch:      env.cfg.TargetVMArch,
+		VMType:          env.cfg.Type,
+		KernelDir:       env.cfg.KernelSrc,
+		OutputDir:       imageDir,
+		Compiler:        buildCfg.CompilerBin,
+		Linker:          buildCfg.LinkerBin,
+		Ccache:          buildCfg.CcacheBin,
+		UserspaceDir:    buildCfg.UserspaceDir,
+		CmdlineFile:     buildCfg.CmdlineFile,
+		SysctlFile:      buildCfg.SysctlFile,
+		ExternalModules: buildCfg.ExternalModules,
+		Config:          buildCfg.KernelConfig,
 	}
 	details, err := build.Image(params)
 	if err != nil {
@@ -442,6 +444,16 @@ type OptionalFuzzerArgs struct {
 	Slowdown   int
 	SandboxArg int64
 	PprofPort  int
+	// External network target, see mgrconfig.ExternalNet.
+	ExternalNet      string
+	ExternalNetRate  int
+	ExternalNetProbe int
+	// Hardware watchdog device and its timeout, see mgrconfig.Experimental.Watchdog.
+	Watchdog        string
+	WatchdogTimeout int
+	// Net namespace capture interface and comma-separated procs, see mgrconfig.NetCapture.
+	NetCapture      string
+	NetCaptureProcs string
 }
 
 type FuzzerCmdArgs struct {
@@ -478,6 +490,25 @@ func FuzzerCmd(args *FuzzerCmdArgs) string {
 			{Name: "sandbox_arg", Value: fmt.Sprint(args.Optional.SandboxArg)},
 			{Name: "pprof_port", Value: fmt.Sprint(args.Optional.PprofPort)},
 		}
+		if args.Optional.ExternalNet != "" {
+			flags = append(flags,
+				tool.Flag{Name: "external_net", Value: args.Optional.ExternalNet},
+				tool.Flag{Name: "external_net_rate", Value: fmt.Sprint(args.Optional.ExternalNetRate)},
+				tool.Flag{Name: "external_net_probe", Value: fmt.Sprint(args.Optional.ExternalNetProbe)},
+			)
+		}
+		if args.Optional.Watchdog != "" {
+			flags = append(flags,
+				tool.Flag{Name: "watchdog", Value: args.Optional.Watchdog},
+				tool.Flag{Name: "watchdog_timeout", Value: fmt.Sprint(args.Optional.WatchdogTimeout)},
+			)
+		}
+		if args.Optional.NetCapture != "" {
+			flags = append(flags,
+				tool.Flag{Name: "net_capture", Value: args.Optional.NetCapture},
+				tool.Flag{Name: "net_capture_procs", Value: args.Optional.NetCaptureProcs},
+			)
+		}
 		optionalArg = " " + tool.OptionalFlags(flags)
 	}
 	return fmt.Sprintf("%v -executor=%v -name=%v -arch=%v%v -manager=%v -sandbox=%v"+
@@ -0,0 +1,337 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package instance
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm"
+)
+
+// DescTestConfig describes a "test my descriptions" run: programs are generated only
+// for the selected syscalls (and calls that create resources they need),
+// executed in a VM and per-call results are collected.
+type DescTestConfig struct {
+	// Syscall names or patterns in the enable_syscalls format.
+	Calls []string
+	// Syscall description files (.txt), all calls declared in these files are tested.
+	DescFiles []string
+	// Number of programs to generate.
+	Programs int
+	// Number of calls in each program.
+	ProgLen int
+	Seed    int64
+}
+
+// DescCallResult holds execution results of a single syscall across all programs.
+type DescCallResult struct {
+	Name      string
+	Executed  int
+	Succeeded int
+	// Maximum number of coverage PCs collected for a single execution.
+	MaxCover int
+	// Total signal across all executions (not deduplicated).
+	Signal int
+	Errnos map[int]int
+}
+
+func (res *DescCallResult) SuccessRate() float64 {
+	if res.Executed == 0 {
+		return 0
+	}
+	return float64(res.Succeeded) / float64(res.Executed)
+}
+
+type DescTestResult struct {
+	// Results for the tested calls, sorted by name.
+	Calls  []*DescCallResult
+	Report *report.Report
+	Output []byte
+}
+
+// DescTestCalls returns the set of calls that need to be tested
+// and the set of calls that can be used in the test programs.
+func DescTestCalls(target *prog.Target, cfg *DescTestConfig) (tested, enabled map[*prog.Syscall]bool, err error) {
+	names := append([]string{}, cfg.Calls...)
+	for _, file := range cfg.DescFiles {
+		fileCalls, err := descFileCalls(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, fileCalls...)
+	}
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("no syscalls to test")
+	}
+	ids, err := mgrconfig.ParseEnabledSyscalls(target, names, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	tested = make(map[*prog.Syscall]bool)
+	enabled = make(map[*prog.Syscall]bool)
+	var queue []*prog.Syscall
+	for _, id := range ids {
+		call := target.Syscalls[id]
+		tested[call] = true
+		enabled[call] = true
+		queue = append(queue, call)
+	}
+	// Add calls that create resources required by the tested calls.
+	for len(queue) != 0 {
+		call := queue[0]
+		queue = queue[1:]
+		prog.ForeachCallType(call, func(typ prog.Type, ctx *prog.TypeCtx) {
+			res, ok := typ.(*prog.ResourceType)
+			if !ok || ctx.Dir == prog.DirOut {
+				return
+			}
+			for _, ctor := range res.Desc.Ctors {
+				if ctor.Precise && !ctor.Call.Attrs.Disabled && !enabled[ctor.Call] {
+					enabled[ctor.Call] = true
+					queue = append(queue, ctor.Call)
+				}
+			}
+		})
+	}
+	enabled, disabled := target.TransitivelyEnabledCalls(enabled)
+	for call, reason := range disabled {
+		if tested[call] {
+			return nil, nil, fmt.Errorf("%v can't be tested: %v", call.Name, reason)
+		}
+	}
+	return tested, enabled, nil
+}
+
+func descFileCalls(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	errors := new(bytes.Buffer)
+	desc := ast.Parse(data, filepath.Base(file), func(pos ast.Pos, msg string) {
+		fmt.Fprintf(errors, "%v: %v\n", pos, msg)
+	})
+	if desc == nil {
+		return nil, fmt.Errorf("failed to parse %v:\n%s", file, errors.Bytes())
+	}
+	var calls []string
+	for _, node := range desc.Nodes {
+		if call, ok := node.(*ast.Call); ok {
+			calls = append(calls, call.Name.Name)
+		}
+	}
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("%v does not declare any syscalls", file)
+	}
+	return calls, nil
+}
+
+// GenerateDescPrograms generates programs that contain at least one of the tested calls each.
+func GenerateDescPrograms(target *prog.Target, cfg *DescTestConfig,
+	tested, enabled map[*prog.Syscall]bool) []*prog.Prog {
+	rs := rand.NewSource(cfg.Seed)
+	ct := target.BuildChoiceTable(nil, enabled)
+	progLen := cfg.ProgLen
+	if progLen <= 0 {
+		progLen = prog.RecommendedCalls
+	}
+	var progs []*prog.Prog
+	for len(progs) < cfg.Programs {
+		var p *prog.Prog
+		// The choice table can select only resource-creating calls for a program,
+		// retry a few times in such case.
+		for try := 0; try < 10; try++ {
+			p = target.Generate(rs, progLen, ct)
+			if descProgHasCall(p, tested) {
+				break
+			}
+		}
+		progs = append(progs, p)
+	}
+	return progs
+}
+
+func descProgHasCall(p *prog.Prog, calls map[*prog.Syscall]bool) bool {
+	for _, c := range p.Calls {
+		if calls[c.Meta] {
+			return true
+		}
+	}
+	return false
+}
+
+// RunDescTest executes the programs one-by-one in the VM and collects per-call results.
+func (inst *ExecProgInstance) RunDescTest(progs []*prog.Prog, tested map[*prog.Syscall]bool,
+	duration time.Duration) (*DescTestResult, error) {
+	buf := new(bytes.Buffer)
+	for i, p := range progs {
+		fmt.Fprintf(buf, "executing program %v:\n%s\n", i, p.Serialize())
+	}
+	progFile, err := osutil.WriteTempFile(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(progFile)
+	vmProgFile, err := inst.VMInstance.Copy(progFile)
+	if err != nil {
+		return nil, &TestError{Title: fmt.Sprintf("failed to copy prog to VM: %v", err)}
+	}
+	target := inst.mgrCfg.SysTarget
+	command := descTestCmd(inst.execprogBin, inst.executorBin, target, inst.mgrCfg.Sandbox,
+		inst.mgrCfg.SandboxArg, inst.mgrCfg.Cover, inst.mgrCfg.Timeouts.Slowdown, vmProgFile)
+	res, err := inst.runCommand(command, duration, SyzExitConditions)
+	if err != nil {
+		return nil, err
+	}
+	return &DescTestResult{
+		Calls:  ParseDescTestOutput(progs, tested, res.Output),
+		Report: res.Report,
+		Output: res.Output,
+	}, nil
+}
+
+func descTestCmd(execprog, executor string, target *targets.Target, sandbox string, sandboxArg int64,
+	cover bool, slowdown int, progFile string) string {
+	osArg := ""
+	if target.HostFuzzer {
+		osArg = " -os=" + target.OS
+	}
+	coverArg := 0
+	if cover {
+		coverArg = 1
+	}
+	// Programs are executed in a single proc and the results are printed in the execution order,
+	// this allows to match them back to the programs.
+	optionalArg := tool.OptionalFlags([]tool.Flag{
+		{Name: "slowdown", Value: fmt.Sprint(slowdown)},
+		{Name: "sandboxArg", Value: fmt.Sprint(sandboxArg)},
+	})
+	return fmt.Sprintf("%v -executor=%v -arch=%v%v -sandbox=%v -procs=1 -repeat=1 -threaded=true"+
+		" -cover=%v -output=true -vv=1 %v %v",
+		execprog, executor, target.Arch, osArg, sandbox, coverArg, optionalArg, progFile)
+}
+
+var (
+	descTestProgRe = regexp.MustCompile(`executing program [0-9]+:`)
+	descTestCallRe = regexp.MustCompile(`CALL ([0-9]+): signal ([0-9]+), coverage ([0-9]+) errno ([0-9]+)`)
+)
+
+// ParseDescTestOutput parses syz-execprog output produced by RunDescTest.
+func ParseDescTestOutput(progs []*prog.Prog, tested map[*prog.Syscall]bool, output []byte) []*DescCallResult {
+	results := make(map[string]*DescCallResult)
+	for call := range tested {
+		results[call.Name] = &DescCallResult{
+			Name:   call.Name,
+			Errnos: make(map[int]int),
+		}
+	}
+	progIdx := -1
+	for s := bufio.NewScanner(bytes.NewReader(output)); s.Scan(); {
+		line := s.Text()
+		if descTestProgRe.MatchString(line) {
+			progIdx++
+			continue
+		}
+		match := descTestCallRe.FindStringSubmatch(line)
+		if match == nil || progIdx < 0 || progIdx >= len(progs) {
+			continue
+		}
+		callIdx, _ := strconv.Atoi(match[1])
+		p := progs[progIdx]
+		if callIdx >= len(p.Calls) {
+			continue
+		}
+		res := results[p.Calls[callIdx].Meta.Name]
+		if res == nil {
+			continue
+		}
+		signal, _ := strconv.Atoi(match[2])
+		cover, _ := strconv.Atoi(match[3])
+		errno, _ := strconv.Atoi(match[4])
+		res.Executed++
+		res.Signal += signal
+		res.MaxCover = max(res.MaxCover, cover)
+		if errno == 0 {
+			res.Succeeded++
+		} else {
+			res.Errnos[errno]++
+		}
+	}
+	var ret []*DescCallResult
+	for _, res := range results {
+		ret = append(ret, res)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// Format returns a human-readable table with the results.
+func (res *DescTestResult) Format() []byte {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "call\texecuted\tsuccess\tmax cover\tsignal\terrnos\n")
+	for _, call := range res.Calls {
+		var errnos []string
+		for errno, count := range call.Errnos {
+			errnos = append(errnos, fmt.Sprintf("%v:%v", errno, count))
+		}
+		sort.Strings(errnos)
+		fmt.Fprintf(w, "%v\t%v\t%.0f%%\t%v\t%v\t%v\n", call.Name, call.Executed,
+			call.SuccessRate()*100, call.MaxCover, call.Signal, strings.Join(errnos, " "))
+	}
+	w.Flush()
+	if res.Report != nil {
+		fmt.Fprintf(buf, "\nkernel crashed: %v\n", res.Report.Title)
+	}
+	return buf.Bytes()
+}
+
+// TestDescriptions is the "test my descriptions" entry point: it selects the calls,
+// generates programs for them, boots a VM and executes the programs there.
+func TestDescriptions(cfg *mgrconfig.Config, descCfg *DescTestConfig, debug bool,
+	opt *OptionalConfig) (*DescTestResult, error) {
+	tested, enabled, err := DescTestCalls(cfg.Target, descCfg)
+	if err != nil {
+		return nil, err
+	}
+	progs := GenerateDescPrograms(cfg.Target, descCfg, tested, enabled)
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	vmPool, err := vm.Create(cfg, debug)
+	if err != nil {
+		return nil, err
+	}
+	defer vmPool.Close()
+	inst, err := CreateExecProgInstance(vmPool, 0, cfg, reporter, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer inst.Close()
+	// Give each program a generous time budget, generated programs may block.
+	duration := time.Duration(len(progs)+1) * cfg.Timeouts.Program * 5
+	duration = max(duration, cfg.Timeouts.NoOutput)
+	return inst.RunDescTest(progs, tested, duration)
+}
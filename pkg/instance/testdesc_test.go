@@ -0,0 +1,90 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package instance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescTestCalls(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	descFile := filepath.Join(t.TempDir(), "desc.txt")
+	if err := os.WriteFile(descFile, []byte("test$res1(a0 syz_res)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &DescTestConfig{
+		DescFiles: []string{descFile},
+		Programs:  10,
+		Seed:      1,
+	}
+	tested, enabled, err := DescTestCalls(target, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res1 := target.SyscallMap["test$res1"]
+	assert.Equal(t, map[*prog.Syscall]bool{res1: true}, tested)
+	assert.True(t, enabled[res1])
+	assert.True(t, enabled[target.SyscallMap["test$res0"]], "resource ctor is not enabled")
+	progs := GenerateDescPrograms(target, cfg, tested, enabled)
+	assert.Len(t, progs, cfg.Programs)
+	for _, p := range progs {
+		for _, c := range p.Calls {
+			assert.True(t, enabled[c.Meta], "generated disabled call %v", c.Meta.Name)
+		}
+	}
+
+	_, _, err = DescTestCalls(target, &DescTestConfig{})
+	assert.Error(t, err)
+	_, _, err = DescTestCalls(target, &DescTestConfig{Calls: []string{"no_such_call"}})
+	assert.Error(t, err)
+}
+
+func TestParseDescTestOutput(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var progs []*prog.Prog
+	for _, text := range []string{
+		"r0 = test$res0()\ntest$res1(r0)\n",
+		"test$res1(0xffff)\n",
+	} {
+		p, err := target.Deserialize([]byte(text), prog.Strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		progs = append(progs, p)
+	}
+	res1 := target.SyscallMap["test$res1"]
+	tested := map[*prog.Syscall]bool{res1: true}
+	output := fmt.Sprintf(`2026/01/01 00:00:00 parsed 2 programs
+2026/01/01 00:00:00 executing program 0:
+%v
+2026/01/01 00:00:00 CALL 0: signal 10, coverage 20 errno 0
+2026/01/01 00:00:00 CALL 1: signal 5, coverage 30 errno 0
+2026/01/01 00:00:00 executing program 0:
+%v
+2026/01/01 00:00:00 CALL 0: signal 1, coverage 2 errno 9 blocked
+`, progs[0].Serialize(), progs[1].Serialize())
+	results := ParseDescTestOutput(progs, tested, []byte(output))
+	assert.Equal(t, []*DescCallResult{{
+		Name:      "test$res1",
+		Executed:  2,
+		Succeeded: 1,
+		MaxCover:  30,
+		Signal:    6,
+		Errnos:    map[int]int{9: 1},
+	}}, results)
+	assert.Equal(t, 0.5, results[0].SuccessRate())
+}
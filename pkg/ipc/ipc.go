@@ -4,6 +4,7 @@
 package ipc
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -143,6 +144,8 @@ var FlatRPCFeaturesToCSource = map[flatrpc.Feature]string{
 	flatrpc.FeatureBinFmtMisc:      "binfmt_misc",
 	flatrpc.FeatureLRWPANEmulation: "ieee802154",
 	flatrpc.FeatureSwap:            "swap",
+	flatrpc.FeatureHugepages:       "hugepages",
+	flatrpc.FeaturePmem:            "pmem",
 }
 
 func MakeEnv(config *Config, pid int) (*Env, error) {
@@ -306,7 +309,7 @@ func (env *Env) ForceRestart() {
 // RestartIfNeeded brings up an executor process if it was stopped.
 func (env *Env) RestartIfNeeded(opts *flatrpc.ExecOpts) error {
 	if env.cmd != nil {
-		if env.cmd.flags == opts.EnvFlags && env.cmd.sandboxArg == opts.SandboxArg {
+		if env.cmd.sameEnv(opts) {
 			return nil
 		}
 		env.ForceRestart()
@@ -322,6 +325,10 @@ func (env *Env) RestartIfNeeded(opts *flatrpc.ExecOpts) error {
 	return err
 }
 
+// ErrExecutorCrashed is returned when the executor process itself was killed by a crash signal
+// (e.g. SIGSEGV in the fork server), which denotes a bug in the executor rather than in the kernel.
+var ErrExecutorCrashed = errors.New("executor crashed")
+
 var (
 	rateLimiterOnce sync.Once
 	rateLimiter     <-chan time.Time
@@ -482,19 +489,21 @@ func readUint64Array(outp *[]byte, size uint32) ([]uint64, bool) {
 }
 
 type command struct {
-	pid        int
-	config     *Config
-	flags      flatrpc.ExecEnv
-	sandboxArg int64
-	timeout    time.Duration
-	cmd        *exec.Cmd
-	dir        string
-	readDone   chan []byte
-	exited     chan error
-	inrp       *os.File
-	outwp      *os.File
-	outmem     []byte
-	freshness  uint64
+	pid         int
+	config      *Config
+	flags       flatrpc.ExecEnv
+	sandboxArg  int64
+	memoryLimit int64
+	pidsLimit   int64
+	timeout     time.Duration
+	cmd         *exec.Cmd
+	dir         string
+	readDone    chan []byte
+	exited      chan error
+	inrp        *os.File
+	outwp       *os.File
+	outmem      []byte
+	freshness   uint64
 }
 
 const (
@@ -503,10 +512,12 @@ const (
 )
 
 type handshakeReq struct {
-	magic      uint64
-	flags      uint64 // env flags
-	pid        uint64
-	sandboxArg uint64
+	magic       uint64
+	flags       uint64 // env flags
+	pid         uint64
+	sandboxArg  uint64
+	memoryLimit uint64 // per-proc memory limit in MB, 0 means default
+	pidsLimit   uint64 // per-proc pids limit, 0 means default
 }
 
 type handshakeReply struct {
@@ -562,13 +573,15 @@ func (env *Env) makeCommand(opts *flatrpc.ExecOpts, tmpDir string) (*command, er
 	}
 
 	c := &command{
-		pid:        env.pid,
-		config:     env.config,
-		flags:      opts.EnvFlags,
-		sandboxArg: opts.SandboxArg,
-		timeout:    timeout,
-		dir:        dir,
-		outmem:     env.out,
+		pid:         env.pid,
+		config:      env.config,
+		flags:       opts.EnvFlags,
+		sandboxArg:  opts.SandboxArg,
+		memoryLimit: opts.ProcMemoryLimit,
+		pidsLimit:   opts.ProcPidsLimit,
+		timeout:     timeout,
+		dir:         dir,
+		outmem:      env.out,
 	}
 	defer func() {
 		if c != nil {
@@ -687,10 +700,12 @@ func (c *command) close() {
 // handshake sends handshakeReq and waits for handshakeReply.
 func (c *command) handshake() error {
 	req := &handshakeReq{
-		magic:      inMagic,
-		flags:      uint64(c.flags),
-		pid:        uint64(c.pid),
-		sandboxArg: uint64(c.sandboxArg),
+		magic:       inMagic,
+		flags:       uint64(c.flags),
+		pid:         uint64(c.pid),
+		sandboxArg:  uint64(c.sandboxArg),
+		memoryLimit: uint64(c.memoryLimit),
+		pidsLimit:   uint64(c.pidsLimit),
 	}
 	reqData := (*[unsafe.Sizeof(*req)]byte)(unsafe.Pointer(req))[:]
 	if _, err := c.outwp.Write(reqData); err != nil {
@@ -737,8 +752,14 @@ func (c *command) wait() error {
 	return <-c.exited
 }
 
+// sameEnv returns true if the command can execute programs with opts without restart.
+func (c *command) sameEnv(opts *flatrpc.ExecOpts) bool {
+	return c.flags == opts.EnvFlags && c.sandboxArg == opts.SandboxArg &&
+		c.memoryLimit == opts.ProcMemoryLimit && c.pidsLimit == opts.ProcPidsLimit
+}
+
 func (c *command) exec(opts *flatrpc.ExecOpts, progData []byte) (output []byte, hanged bool, err0 error) {
-	if c.flags != opts.EnvFlags || c.sandboxArg != opts.SandboxArg {
+	if !c.sameEnv(opts) {
 		panic("wrong command")
 	}
 	req := &executeReq{
@@ -827,6 +848,10 @@ func (c *command) exec(opts *flatrpc.ExecOpts, progData []byte) (output []byte,
 		hanged = true
 		return
 	}
+	if exitStatus == -1 && c.cmd.ProcessState != nil && osutil.ProcessCrashed(c.cmd.ProcessState) {
+		err0 = fmt.Errorf("%w: %v\n%s", ErrExecutorCrashed, c.cmd.ProcessState, output)
+		return
+	}
 	if exitStatus == -1 {
 		if c.cmd.ProcessState == nil {
 			exitStatus = statusFail
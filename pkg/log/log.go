@@ -6,13 +6,18 @@
 //   - global verbosity setting that can be used by multiple packages
 //   - ability to disable all output
 //   - ability to cache recent output in memory
+//   - module-tagged loggers with per-module verbosity levels
+//   - JSON output for log aggregation systems
 package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	golog "log"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,6 +26,8 @@ import (
 
 var (
 	flagV          = flag.Int("vv", 0, "verbosity")
+	flagVModule    = flag.String("vmodule", "", "per-module verbosity, e.g. vm=-1,repro=2")
+	flagJSON       = flag.Bool("log_json", false, "print log messages as JSON objects")
 	mu             sync.Mutex
 	cacheMem       int
 	cacheMaxMem    int
@@ -29,6 +36,10 @@ var (
 	cachingEnabled atomic.Bool
 	instanceName   string
 	prependTime    = true // for testing
+	// Per-module verbosity levels set with SetModuleLevels, the -vmodule flag is parsed lazily.
+	moduleLevels   atomic.Pointer[map[string]int]
+	jsonEnabled    atomic.Bool
+	parseFlagsOnce sync.Once
 )
 
 // EnableLogCaching enables in memory caching of log output.
@@ -69,18 +80,136 @@ func SetName(name string) {
 	instanceName = name
 }
 
+// Modules lists names of all module loggers (see Module), verbosity levels can be set only for them.
+var Modules = []string{"manager", "repro", "rpcserver", "vm"}
+
+// SetModuleLevels overrides the global verbosity for the given modules (see Module).
+// Messages of a module are printed if their level is not higher than the module level,
+// so a negative level silences all non-error messages of the module.
+func SetModuleLevels(levels map[string]int) {
+	mu.Lock()
+	defer mu.Unlock()
+	merged := make(map[string]int)
+	if old := moduleLevels.Load(); old != nil {
+		for module, level := range *old {
+			merged[module] = level
+		}
+	}
+	for module, level := range levels {
+		merged[module] = level
+	}
+	moduleLevels.Store(&merged)
+}
+
+// ParseModuleLevels parses module verbosity levels in the -vmodule flag format: "vm=-1,repro=2".
+func ParseModuleLevels(spec string) (map[string]int, error) {
+	levels := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		module, level, ok := strings.Cut(entry, "=")
+		if !ok || module == "" {
+			return nil, fmt.Errorf("bad module verbosity %q, want module=level", entry)
+		}
+		v, err := strconv.Atoi(level)
+		if err != nil {
+			return nil, fmt.Errorf("bad module verbosity %q: %w", entry, err)
+		}
+		levels[module] = v
+	}
+	if err := CheckModuleLevels(levels); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// CheckModuleLevels returns an error if levels refer to unknown modules.
+func CheckModuleLevels(levels map[string]int) error {
+	for module := range levels {
+		if !slices.Contains(Modules, module) {
+			return fmt.Errorf("unknown log module %q, known modules: %v", module, strings.Join(Modules, ", "))
+		}
+	}
+	return nil
+}
+
+// EnableJSON switches the output to JSON objects (one per line) with time, severity,
+// module, instance name, verbosity level and message fields.
+func EnableJSON() {
+	jsonEnabled.Store(true)
+}
+
+// parseFlags applies the -vmodule/-log_json flags, it's called lazily since
+// the flags are parsed after the package initialization.
+func parseFlags() {
+	if !flag.Parsed() {
+		return
+	}
+	parseFlagsOnce.Do(func() {
+		if *flagJSON {
+			jsonEnabled.Store(true)
+		}
+		levels, err := ParseModuleLevels(*flagVModule)
+		if err != nil {
+			Fatalf("%v", err)
+		}
+		SetModuleLevels(levels)
+	})
+}
+
 // V reports whether verbosity at the call site is at least the requested level.
 // See https://pkg.go.dev/github.com/golang/glog#V for details.
 func V(level int) bool {
 	return level <= *flagV
 }
 
+func moduleV(module string, level int) bool {
+	if module != "" {
+		if levels := moduleLevels.Load(); levels != nil {
+			if v, ok := (*levels)[module]; ok {
+				return level <= v
+			}
+		}
+	}
+	return V(level)
+}
+
 func Logf(v int, msg string, args ...interface{}) {
-	writeMessage(v, "", msg, args...)
+	writeMessage(v, "", "", msg, args...)
 }
 
 func Errorf(msg string, args ...interface{}) {
-	writeMessage(0, "ERROR", msg, args...)
+	writeMessage(0, "", "ERROR", msg, args...)
+}
+
+// Logger is a module-tagged logger, its messages are filtered with the module verbosity
+// level (if set) instead of the global one.
+type Logger struct {
+	module string
+}
+
+// Module returns a logger for the module (e.g. "vm" or "repro"), the name must be listed in Modules.
+func Module(name string) *Logger {
+	if !slices.Contains(Modules, name) {
+		panic(fmt.Sprintf("unknown log module %q", name))
+	}
+	return &Logger{module: name}
+}
+
+// V reports whether verbosity of the module is at least the requested level.
+func (l *Logger) V(level int) bool {
+	parseFlags()
+	return moduleV(l.module, level)
+}
+
+func (l *Logger) Logf(v int, msg string, args ...interface{}) {
+	writeMessage(v, l.module, "", msg, args...)
+}
+
+// Errorf messages are never silenced by module levels.
+func (l *Logger) Errorf(msg string, args ...interface{}) {
+	writeMessage(0, l.module, "ERROR", msg, args...)
 }
 
 func Fatal(err error) {
@@ -88,7 +217,7 @@ func Fatal(err error) {
 }
 
 func Fatalf(msg string, args ...interface{}) {
-	golog.Fatalf(message("FATAL", msg, args...))
+	golog.Fatalf(message("FATAL", "", msg, args...))
 }
 
 // SyzFatalf-reported errors are parsed by syzkaller as if they were kernel bugs.
@@ -100,7 +229,7 @@ func SyzFatal(err error) {
 	SyzFatalf("%v", err)
 }
 
-func message(severity, msg string, args ...interface{}) string {
+func message(severity, module, msg string, args ...interface{}) string {
 	var sb strings.Builder
 	if severity != "" {
 		fmt.Fprintf(&sb, "[%s] ", severity)
@@ -108,17 +237,53 @@ func message(severity, msg string, args ...interface{}) string {
 	if instanceName != "" {
 		fmt.Fprintf(&sb, "%s: ", instanceName)
 	}
+	if module != "" {
+		fmt.Fprintf(&sb, "%s: ", module)
+	}
 	fmt.Fprintf(&sb, msg, args...)
 	return sb.String()
 }
 
-func writeMessage(v int, severity, msg string, args ...interface{}) {
+type jsonMessage struct {
+	Time     time.Time `json:"time"`
+	Severity string    `json:"severity"`
+	Module   string    `json:"module,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Level    int       `json:"level"`
+	Message  string    `json:"msg"`
+}
+
+func writeJSON(v int, severity, module, msg string, args ...interface{}) {
+	if severity == "" {
+		severity = "INFO"
+	}
+	data, err := json.Marshal(jsonMessage{
+		Time:     time.Now(),
+		Severity: severity,
+		Module:   module,
+		Name:     instanceName,
+		Level:    v,
+		Message:  fmt.Sprintf(msg, args...),
+	})
+	if err != nil {
+		panic(err)
+	}
+	golog.Writer().Write(append(data, '\n'))
+}
+
+func writeMessage(v int, module, severity, msg string, args ...interface{}) {
+	parseFlags()
+	show := moduleV(module, v) || severity != ""
 	cache := v <= 1 && cachingEnabled.Load()
-	if !V(v) && !cache {
+	if !show && !cache {
 		return
 	}
-	text := message(severity, msg, args...)
-	if V(v) {
+	if show && jsonEnabled.Load() {
+		writeJSON(v, severity, module, msg, args...)
+		show = false
+	}
+	text := message(severity, module, msg, args...)
+	if show {
 		golog.Print(text)
 	}
 	if !cache {
@@ -4,7 +4,13 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
+	golog "log"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -45,3 +51,51 @@ func (nf noFormat) String() string {
 	nf.T.Fatalf("must not be formatted")
 	return ""
 }
+
+func TestModuleLevels(t *testing.T) {
+	levels, err := ParseModuleLevels("vm=-1, repro=2,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"vm": -1, "repro": 2}, levels)
+	_, err = ParseModuleLevels("vm")
+	assert.Error(t, err)
+	_, err = ParseModuleLevels("vm=x")
+	assert.Error(t, err)
+	_, err = ParseModuleLevels("vm=1,nosuchmodule=1")
+	assert.Error(t, err)
+	assert.Panics(t, func() { Module("nosuchmodule") })
+
+	SetModuleLevels(levels)
+	vm, repro, other := Module("vm"), Module("repro"), Module("manager")
+	assert.False(t, vm.V(0))
+	assert.True(t, repro.V(2))
+	assert.False(t, repro.V(3))
+	assert.True(t, other.V(0))
+	assert.False(t, other.V(1))
+}
+
+func TestJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	out := golog.Writer()
+	golog.SetOutput(buf)
+	defer golog.SetOutput(out)
+	jsonEnabled.Store(true)
+	defer jsonEnabled.Store(false)
+	SetModuleLevels(map[string]int{"vm": -1})
+
+	Module("vm").Logf(0, "silenced")
+	Module("vm").Errorf("failed %v", 1)
+	Module("rpcserver").Logf(0, "message %q", "x")
+	dec := json.NewDecoder(buf)
+	var msgs []jsonMessage
+	for dec.More() {
+		var msg jsonMessage
+		assert.NoError(t, dec.Decode(&msg))
+		assert.False(t, msg.Time.IsZero())
+		msg.Time = time.Time{}
+		msgs = append(msgs, msg)
+	}
+	assert.Equal(t, []jsonMessage{
+		{Severity: "ERROR", Module: "vm", Message: "failed 1"},
+		{Severity: "INFO", Module: "rpcserver", Message: `message "x"`},
+	}, msgs)
+}
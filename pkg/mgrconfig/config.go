@@ -133,6 +133,15 @@ type Config struct {
 	// on this value.
 	SandboxArg int64 `json:"sandbox_arg"`
 
+	// Limits for each executor proc enforced with cgroups (only Linux, requires cgroups feature).
+	// The memory limit uses cgroup v2 memory.max, or v1 memory.limit_in_bytes if the memory
+	// controller can't be bound to cgroup v2.
+	// A runaway test program is OOM-killed within its own cgroup instead of taking down
+	// the whole executor. Memory limit is in MB (default: 300), pids limit is the max number
+	// of processes and threads per proc (default: 32).
+	ProcMemoryLimit int64 `json:"proc_memory_limit"`
+	ProcPidsLimit   int64 `json:"proc_pids_limit"`
+
 	// Use KCOV coverage (default: true).
 	Cover bool `json:"cover"`
 	// Use coverage filter. Supported types of filter:
@@ -166,8 +175,11 @@ type Config struct {
 
 	// List of syscalls to test (optional). For example:
 	//	"enable_syscalls": [ "mmap", "openat$ashmem", "ioctl$ASHMEM*" ]
+	// Entries of the form "subsystem:name" refer to all syscalls of a kernel subsystem
+	// (e.g. "subsystem:netfilter", see pkg/mgrconfig/subsystems.go for the list).
 	EnabledSyscalls []string `json:"enable_syscalls,omitempty"`
 	// List of system calls that should be treated as disabled (optional).
+	// Supports the same "subsystem:name" entries.
 	DisabledSyscalls []string `json:"disable_syscalls,omitempty"`
 	// List of syscalls that should not be mutated by the fuzzer (optional).
 	NoMutateSyscalls []string `json:"no_mutate_syscalls,omitempty"`
@@ -182,6 +194,9 @@ type Config struct {
 	// If this list is not empty and none of the regexps match a bug, it's suppressed.
 	// Regexps are matched against bug title, guilty file and maintainer emails.
 	Interests []string `json:"interests,omitempty"`
+	// The kernel is booted with no_hash_pointers, so pointers printed with %p in crash reports
+	// are real addresses rather than hashes and can be extracted (e.g. KFENCE access addresses).
+	NoHashPointers bool `json:"no_hash_pointers"`
 
 	// Path to the strace binary compiled for the target architecture.
 	// If set, for each reproducer syzkaller will run it once more under strace and save
@@ -202,8 +217,42 @@ type Config struct {
 	//    "public_access": true
 	// }
 	// More details can be found in pkg/asset/config.go.
+	// Without a dashboard, found reproducers are uploaded together with the kernel and disk images
+	// and the download links are saved to workdir/crashes/*/repro.assets.
 	AssetStorage *asset.Config `json:"asset_storage"`
 
+	// External network target for syz_emit_external$* calls (optional).
+	// If set, the fuzzer sends generated UDP/ICMP/SCTP packets to a real device under test
+	// reachable from the VMs, rather than only to the local tun device. For example:
+	//	"external_net": {
+	//		"addr": "192.168.1.10",
+	//		"rate": 50,
+	//		"probe_port": 22
+	//	}
+	// Requires "sandbox": "none". The calls are disabled if the target is not configured.
+	ExternalNet *ExternalNet `json:"external_net,omitempty"`
+
+	// Bridge net namespaces of test processes to an interface of the target (optional, linux only),
+	// so that network-stack crashes can be correlated with a packet capture taken outside of the VM.
+	// The interface is supposed to be connected to the host (e.g. a second qemu NIC attached
+	// to a host tap device where tcpdump runs). For example:
+	//	"net_capture": {
+	//		"iface": "eth1",
+	//		"procs": [0, 1]
+	//	}
+	// The interface and syz_capture<proc> veth devices are added to the syz_capture bridge
+	// in the init net namespace, the other end of the veth is a port of bridge0 in the test namespace.
+	// Requires the net_dev feature.
+	NetCapture *NetCapture `json:"net_capture,omitempty"`
+
+	// Per-module log verbosity that overrides the global one (-vv flag), e.g. {"vm": -1, "repro": 1}.
+	// Known modules are "manager", "repro", "rpcserver" and "vm".
+	// Negative levels silence all but error messages of the module (e.g. noisy VM messages).
+	LogLevels map[string]int `json:"log_levels,omitempty"`
+	// Print log messages as JSON objects (one per line), so that they can be shipped
+	// to log aggregation systems.
+	LogJSON bool `json:"log_json"`
+
 	// Experimental options.
 	Experimental Experimental
 
@@ -217,6 +266,193 @@ type Experimental struct {
 	// Don't let the VM state accumulate too much by restarting
 	// syz-executor before most prog executions.
 	ResetAccState bool `json:"reset_acc_state"`
+
+	// Choose corpus programs for mutation according to an energy score
+	// (rareness of their signal, recency of new signal, crashes of their mutants)
+	// instead of proportionally to their signal size.
+	EnergySchedule bool `json:"energy_schedule"`
+	// Temperature of the energy schedule: values above 1 favor exploration (more uniform selection),
+	// values below 1 favor exploitation of the highest-energy programs.
+	EnergyTemperature float64 `json:"energy_temperature"`
+
+	// Increase weights of the mutation operators that produced programs executing at the time
+	// of crashes (see "crash mutation" stats).
+	CrashGuidedMutations bool `json:"crash_guided_mutations"`
+	// Periodically re-weight the mutation operators according to the rate of new signal found
+	// by the programs they produced (see "mutation" stats and the "mutation weights" graph).
+	MutationTuning bool `json:"mutation_tuning"`
+	// Mutate more the programs that triggered non-fatal kernel warnings: programs with calls
+	// that incremented the kernel warn_count (e.g. WARNING with panic_on_warn=0), and programs
+	// that were executing when the kernel printed a console line matching one of warning_patterns
+	// regexps (e.g. error messages that are not detected as crashes).
+	// warn_count is read before and after every call only if this is enabled.
+	WarningFeedback bool     `json:"warning_feedback"`
+	WarningPatterns []string `json:"warning_patterns,omitempty"`
+
+	// New coverage signal is added to the corpus only if it reproduces in deflake_runs
+	// out of deflake_max_runs re-executions of the program (default: 3 out of 5).
+	// Kernels with many nondeterministic paths may benefit from a stricter policy.
+	DeflakeRuns    int `json:"deflake_runs"`
+	DeflakeMaxRuns int `json:"deflake_max_runs"`
+
+	// Start in the hunt mode for the crash with the given title: generate only syscalls seen
+	// in the crash logs, prefer corpus programs with these syscalls and dedicate all VMs but one
+	// to reproduction of the crash. The hunt mode can also be entered/left via the /hunt page.
+	HuntTitle string `json:"hunt_title"`
+
+	// Triage of the corpus and seed programs on start. Programs are triaged in the order of priority:
+	// corpus programs go before programs from the seeds dir, shorter programs go before longer ones.
+	// Every corpus_triage_interleave-th execution is given to normal fuzzing while there are
+	// untriaged programs (0 means that fuzzing starts only after the whole corpus is triaged).
+	CorpusTriageInterleave int `json:"corpus_triage_interleave"`
+	// Programs that were not triaged within corpus_triage_deadline minutes after the start
+	// are deferred: they are triaged interleaved with normal fuzzing after the triage
+	// and smash jobs (0 means no deadline).
+	CorpusTriageDeadline int `json:"corpus_triage_deadline"`
+
+	// Corpus programs whose mutants consistently take longer than slow_prog_budget milliseconds
+	// to execute (long sleeps, heavy io_uring loops, etc) are mutated less frequently,
+	// so that they don't dominate the executor time (0 means no budget).
+	SlowProgBudget int `json:"slow_prog_budget"`
+
+	// Once edge coverage plateaus, use the number of distinct PCs covered by a call
+	// as a tiebreaker signal: programs whose calls reach deeper into syscall handlers than
+	// any execution of the same syscall before are mutated more. During the plateau
+	// raw coverage is collected for generated and mutated programs, which costs some exec speed.
+	DepthSignal bool `json:"depth_signal"`
+
+	// Set up a hugetlb pool and enable transparent hugepages in the VM (see "hugepages" feature).
+	// The pool takes memory from the fuzzer and changes mm behavior for the whole VM,
+	// so it's opt-in.
+	Hugepages bool `json:"hugepages"`
+
+	// If a crash with the same title happens more than crash_rate_limit times per hour,
+	// further occurrences are treated as suppressed (not saved, reported or reproduced)
+	// until the rate drops, so that a noisy known bug doesn't starve the rest of the run
+	// (0 means no limit). Rate limiting can be turned off for individual crashes in the web UI.
+	CrashRateLimit int `json:"crash_rate_limit"`
+
+	// Attach up to guilty_commits most recent commits touching the guilty file of a crash
+	// (found with git log in kernel_src, which must be a git checkout) to the crash.
+	// They are saved into the crash dir (as "commitsN") and give a head start before bisection.
+	GuiltyCommits int `json:"guilty_commits"`
+
+	// Downstream managers (e.g. per-branch or per-device managers) whose stats, crashes and corpus
+	// are aggregated on the /fleet page of this manager. They are polled over their HTTP API,
+	// so nothing needs to be configured on the downstream managers.
+	Fleet []FleetManager `json:"fleet,omitempty"`
+
+	// Collect kernel crash dumps (vmcore) for crashes, see KdumpConfig.
+	// The VM type must support it, e.g. qemu with "kdump": true in the VM config.
+	Kdump *KdumpConfig `json:"kdump,omitempty"`
+
+	// Hardware watchdog device on the target (e.g. "/dev/watchdog") for bare-metal targets
+	// without external power control. The fuzzer keeps the watchdog alive while it makes progress,
+	// so a wedged machine is reset by the watchdog. The watchdog timeout must be larger than the time
+	// the manager needs to restart the fuzzer on the machine. Resets by the watchdog are reported
+	// by the fuzzer after the reboot and are counted in the "watchdog resets" stat, not as crashes.
+	// The watchdog is disarmed when the manager stops the fuzzer.
+	Watchdog string `json:"watchdog,omitempty"`
+	// Timeout of the watchdog in seconds (if 0, the default timeout of the device is used).
+	WatchdogTimeout int `json:"watchdog_timeout,omitempty"`
+
+	// Execute smashed programs also with a suspend/resume cycle of the target right before
+	// the smashed call (see the suspend call property in docs/program_syntax.md).
+	// The kernel must support suspend and wakeup by the RTC alarm (/sys/class/rtc/rtc0/wakealarm),
+	// e.g. qemu VMs support suspend-to-idle. Each cycle stalls the whole VM for a few seconds,
+	// so cycles are done at most once in 5 minutes, and only if the machine check
+	// finds support for suspend-to-idle and the RTC alarm ("Suspend" feature).
+	SuspendResume bool `json:"suspend_resume"`
+
+	// Invoke enabled syscalls (with default arguments, in the configured sandbox) during
+	// the machine check and disable syscalls that fail with ENOSYS, or with EPERM both in the sandbox
+	// and as root. Only syscalls from a fixed list of calls that are commonly blocked and are free
+	// of side effects with default arguments are probed (e.g. bpf, perf_event_open, io_uring_setup). It detects syscalls blocked by seccomp filters or kernel lockdown on targets
+	// where the kernel config and /proc are not enough (e.g. locked down production devices).
+	// The disabled syscalls are reported in the machine check log.
+	ProbeSyscalls bool `json:"probe_syscalls"`
+
+	// The manager runs on a preemptible/spot GCE instance. On a preemption notice the manager
+	// checkpoints max signal, inputs pending triage and repros not yet sent to syz-hub to workdir,
+	// flushes the corpus and dashboard stats, and restores the state on the next start, so the work
+	// is not lost when the instance is restarted. The manager does not restart the instance itself,
+	// this needs to be done externally (e.g. by a managed instance group). The fuzzing VMs
+	// can use spot instances with the "spot" gce VM config option, preempted VMs are recreated.
+	PreemptibleHost bool `json:"preemptible_host"`
+
+	// Long-program mode for stateful subsystems: the fuzzer keeps long-lived programs made of
+	// these state-building calls (patterns as in enable_syscalls, e.g. "mkdirat$cgroup*", "mount",
+	// "unshare") and executes fuzzed programs on top of the accumulated state (nested cgroups,
+	// mounts, namespaces), periodically resetting it. Each VM has own state, and programs built
+	// on top of it are executed only on that VM. It targets bugs that are reachable only from
+	// complex state. States that led to new coverage are checkpointed and reused after resets.
+	LongProgCalls []string `json:"long_prog_calls,omitempty"`
+
+	// Scheduling of work across VMs of different speed, see VMScheduling.
+	VMScheduling *VMScheduling `json:"vm_scheduling,omitempty"`
+}
+
+// VMScheduling describes pools that mix fast VMs (e.g. local qemu) with slow ones
+// (e.g. remote boards of an isolated pool). By default all VMs are used uniformly.
+// With the scheduling, crash reproduction and triage of new inputs are done preferentially
+// on the fast VMs, while the slow VMs are mostly left for long-running fuzzing.
+type VMScheduling struct {
+	// Indexes of the slow VMs in the pool (e.g. indexes of the boards in the isolated targets list).
+	SlowVMs []int `json:"slow_vms"`
+	// Where crashes are reproduced:
+	//	"fast": only on the fast VMs;
+	//	"prefer_fast": on the fast VMs first, the slow VMs are used if there are not enough fast ones (default);
+	//	"any": on any VMs.
+	Repro string `json:"repro,omitempty"`
+	// Where new inputs are triaged (and other important requests are executed):
+	//	"fast": on the fast VMs while any of them are fuzzing (default);
+	//	"any": on any VMs.
+	Triage string `json:"triage,omitempty"`
+}
+
+const (
+	VMSchedFast       = "fast"
+	VMSchedPreferFast = "prefer_fast"
+	VMSchedAny        = "any"
+)
+
+// KdumpConfig controls processing of kernel crash dumps.
+// Only the first dump for each crash title is kept in the crash dir (as "vmcore"),
+// later crashes with the same title are not dumped to save time and disk space.
+type KdumpConfig struct {
+	// If non-zero, the dump is filtered on the host with makedumpfile using this dump level
+	// (e.g. 31 excludes zero, cache, user and free pages), which makes it much smaller.
+	DumpLevel int `json:"dump_level,omitempty"`
+	// Scripts for the crash utility (https://github.com/crash-utility/crash) that are run
+	// against kernel_obj/vmlinux and the dump. Output of each script is saved
+	// into the crash dir as "crash-<script name>".
+	CrashScripts []string `json:"crash_scripts,omitempty"`
+}
+
+// FleetManager describes a downstream manager of the fleet.
+type FleetManager struct {
+	// Name shown in the UI (defaults to the name reported by the manager).
+	Name string `json:"name,omitempty"`
+	// HTTP address of the manager (its http config param), e.g. "10.0.0.2:56741"
+	// or "https://syz-manager.example.com".
+	Addr string `json:"addr"`
+}
+
+type ExternalNet struct {
+	// IPv4 address of the device under test.
+	Addr string `json:"addr"`
+	// Max number of packets per second sent from a single VM (default: 100).
+	Rate int `json:"rate,omitempty"`
+	// TCP port on the device that is periodically probed to detect that the device
+	// has crashed or hung (optional). Unresponsive device is reported as a crash.
+	ProbePort int `json:"probe_port,omitempty"`
+}
+
+type NetCapture struct {
+	// Capture interface in the init net namespace of the target.
+	Iface string `json:"iface"`
+	// Test processes (0..procs-1) whose net namespaces are bridged (all if empty).
+	Procs []int `json:"procs,omitempty"`
 }
 
 type Subsystem struct {
@@ -5,6 +5,7 @@ package mgrconfig
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/prog"
 	_ "github.com/google/syzkaller/sys" // most mgrconfig users want targets too
@@ -35,6 +37,7 @@ type Derived struct {
 
 	Syscalls      []int
 	NoMutateCalls map[int]bool // Set of IDs of syscalls which should not be mutated.
+	StateCalls    map[int]bool // Set of IDs of state-building syscalls (see Experimental.LongProgCalls).
 	Timeouts      targets.Timeouts
 
 	// Special debugging/development mode specified by VM type "none".
@@ -176,9 +179,60 @@ func Complete(cfg *Config) error {
 	if cfg.FuzzingVMs < 0 {
 		return fmt.Errorf("fuzzing_vms cannot be less than 0")
 	}
+	if err := log.CheckModuleLevels(cfg.LogLevels); err != nil {
+		return fmt.Errorf("bad config param log_levels: %w", err)
+	}
+
+	if err := cfg.completeExternalNet(); err != nil {
+		return err
+	}
+	if err := cfg.completeNetCapture(); err != nil {
+		return err
+	}
+	if err := cfg.completeKdump(); err != nil {
+		return err
+	}
+	if err := cfg.completeVMScheduling(); err != nil {
+		return err
+	}
+	if exp := cfg.Experimental; exp.DeflakeRuns < 0 || exp.DeflakeMaxRuns < 0 ||
+		exp.DeflakeMaxRuns != 0 && exp.DeflakeRuns > exp.DeflakeMaxRuns {
+		return fmt.Errorf("bad config param experimental.deflake_runs/deflake_max_runs: %v/%v",
+			exp.DeflakeRuns, exp.DeflakeMaxRuns)
+	}
+	if exp := cfg.Experimental; exp.CorpusTriageInterleave < 0 || exp.CorpusTriageInterleave == 1 {
+		return fmt.Errorf("bad config param experimental.corpus_triage_interleave: %v, want 0 or >= 2",
+			exp.CorpusTriageInterleave)
+	}
+	if cfg.Experimental.CorpusTriageDeadline < 0 {
+		return fmt.Errorf("bad config param experimental.corpus_triage_deadline: %v",
+			cfg.Experimental.CorpusTriageDeadline)
+	}
+	if cfg.Experimental.SlowProgBudget < 0 {
+		return fmt.Errorf("bad config param experimental.slow_prog_budget: %v",
+			cfg.Experimental.SlowProgBudget)
+	}
+	if cfg.Experimental.CrashRateLimit < 0 {
+		return fmt.Errorf("bad config param experimental.crash_rate_limit: %v",
+			cfg.Experimental.CrashRateLimit)
+	}
+	fleetAddrs := make(map[string]bool)
+	for _, mgr := range cfg.Experimental.Fleet {
+		if mgr.Addr == "" || fleetAddrs[mgr.Addr] {
+			return fmt.Errorf("bad config param experimental.fleet: empty or duplicate addr %q", mgr.Addr)
+		}
+		fleetAddrs[mgr.Addr] = true
+	}
+	if err := cfg.checkDependentParams(); err != nil {
+		return err
+	}
 
+	disabled := cfg.DisabledSyscalls
+	if cfg.ExternalNet == nil && hasSyscall(cfg.Target, externalNetSyscalls) {
+		disabled = append(append([]string{}, disabled...), externalNetSyscalls)
+	}
 	var err error
-	cfg.Syscalls, err = ParseEnabledSyscalls(cfg.Target, cfg.EnabledSyscalls, cfg.DisabledSyscalls)
+	cfg.Syscalls, err = ParseEnabledSyscalls(cfg.Target, cfg.EnabledSyscalls, disabled)
 	if err != nil {
 		return err
 	}
@@ -186,10 +240,11 @@ func Complete(cfg *Config) error {
 	if err != nil {
 		return err
 	}
+	cfg.StateCalls, err = parseStateSyscalls(cfg.Target, cfg.Experimental.LongProgCalls)
+	if err != nil {
+		return err
+	}
 	if !cfg.AssetStorage.IsEmpty() {
-		if cfg.DashboardClient == "" {
-			return fmt.Errorf("asset storage also requires dashboard client")
-		}
 		err = cfg.AssetStorage.Validate()
 		if err != nil {
 			return err
@@ -200,6 +255,150 @@ func Complete(cfg *Config) error {
 	return nil
 }
 
+const externalNetSyscalls = "syz_emit_external*"
+
+func (cfg *Config) completeExternalNet() error {
+	ext := cfg.ExternalNet
+	if ext == nil {
+		return nil
+	}
+	if ip := net.ParseIP(ext.Addr); ip == nil || ip.To4() == nil {
+		return fmt.Errorf("bad config param external_net.addr: %q is not an IPv4 address", ext.Addr)
+	}
+	if ext.Rate == 0 {
+		ext.Rate = 100
+	}
+	if ext.Rate < 0 {
+		return fmt.Errorf("bad config param external_net.rate: %v", ext.Rate)
+	}
+	if ext.ProbePort < 0 || ext.ProbePort > 65535 {
+		return fmt.Errorf("bad config param external_net.probe_port: %v", ext.ProbePort)
+	}
+	if cfg.Sandbox != "none" {
+		return fmt.Errorf("external_net requires sandbox none")
+	}
+	return nil
+}
+
+func (cfg *Config) completeNetCapture() error {
+	capture := cfg.NetCapture
+	if capture == nil {
+		return nil
+	}
+	if cfg.TargetOS != targets.Linux {
+		return fmt.Errorf("net_capture is supported only on linux")
+	}
+	// IFNAMSIZ includes the terminating 0.
+	if capture.Iface == "" || len(capture.Iface) >= 16 || strings.ContainsAny(capture.Iface, "/ ") {
+		return fmt.Errorf("bad config param net_capture.iface: %q", capture.Iface)
+	}
+	for _, proc := range capture.Procs {
+		if proc < 0 || proc >= cfg.Procs {
+			return fmt.Errorf("bad config param net_capture.procs: proc %v is out of range"+
+				" (total %v procs)", proc, cfg.Procs)
+		}
+	}
+	return nil
+}
+
+func (cfg *Config) completeVMScheduling() error {
+	sched := cfg.Experimental.VMScheduling
+	if sched == nil {
+		return nil
+	}
+	if sched.Repro == "" {
+		sched.Repro = VMSchedPreferFast
+	}
+	if sched.Triage == "" {
+		sched.Triage = VMSchedFast
+	}
+	if sched.Repro != VMSchedFast && sched.Repro != VMSchedPreferFast && sched.Repro != VMSchedAny {
+		return fmt.Errorf("bad config param experimental.vm_scheduling.repro: %q, want fast/prefer_fast/any",
+			sched.Repro)
+	}
+	if sched.Triage != VMSchedFast && sched.Triage != VMSchedAny {
+		return fmt.Errorf("bad config param experimental.vm_scheduling.triage: %q, want fast/any", sched.Triage)
+	}
+	seen := make(map[int]bool)
+	for _, idx := range sched.SlowVMs {
+		if idx < 0 || seen[idx] {
+			return fmt.Errorf("bad config param experimental.vm_scheduling.slow_vms: bad or duplicate index %v", idx)
+		}
+		seen[idx] = true
+	}
+	return nil
+}
+
+func (cfg *Config) completeKdump() error {
+	kdump := cfg.Experimental.Kdump
+	if kdump == nil {
+		return nil
+	}
+	if cfg.TargetOS != targets.Linux {
+		return fmt.Errorf("experimental.kdump is supported only on linux")
+	}
+	if kdump.DumpLevel < 0 || kdump.DumpLevel > 31 {
+		return fmt.Errorf("bad config param experimental.kdump.dump_level: %v, want [0-31]", kdump.DumpLevel)
+	}
+	if len(kdump.CrashScripts) != 0 && cfg.KernelObj == "" {
+		return fmt.Errorf("experimental.kdump.crash_scripts require kernel_obj")
+	}
+	for i, script := range kdump.CrashScripts {
+		kdump.CrashScripts[i] = osutil.Abs(script)
+		if !osutil.IsExist(kdump.CrashScripts[i]) {
+			return fmt.Errorf("crash script %q does not exist", script)
+		}
+	}
+	return nil
+}
+
+// checkDependentParams checks params that make sense only in combination with other params.
+func (cfg *Config) checkDependentParams() error {
+	exp := cfg.Experimental
+	if exp.HuntTitle != "" && !cfg.Reproduce {
+		return fmt.Errorf("config param experimental.hunt_title requires reproduce")
+	}
+	if exp.EnergyTemperature != 0 && !exp.EnergySchedule {
+		return fmt.Errorf("config param experimental.energy_temperature requires experimental.energy_schedule")
+	}
+	if len(exp.WarningPatterns) != 0 && !exp.WarningFeedback {
+		return fmt.Errorf("config param experimental.warning_patterns requires experimental.warning_feedback")
+	}
+	for _, pattern := range exp.WarningPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("bad config param experimental.warning_patterns: %w", err)
+		}
+	}
+	if exp.Watchdog != "" && !filepath.IsAbs(exp.Watchdog) {
+		return fmt.Errorf("bad config param experimental.watchdog: %q is not an absolute path", exp.Watchdog)
+	}
+	if exp.WatchdogTimeout < 0 || exp.WatchdogTimeout != 0 && exp.Watchdog == "" {
+		return fmt.Errorf("bad config param experimental.watchdog_timeout: %v", exp.WatchdogTimeout)
+	}
+	if exp.EnergyTemperature < 0 {
+		return fmt.Errorf("bad config param experimental.energy_temperature: %v", exp.EnergyTemperature)
+	}
+	if cfg.ProcMemoryLimit < 0 {
+		return fmt.Errorf("bad config param proc_memory_limit: %v", cfg.ProcMemoryLimit)
+	}
+	if cfg.ProcPidsLimit < 0 {
+		return fmt.Errorf("bad config param proc_pids_limit: %v", cfg.ProcPidsLimit)
+	}
+	if (cfg.ProcMemoryLimit != 0 || cfg.ProcPidsLimit != 0) && cfg.TargetOS != targets.Linux {
+		return fmt.Errorf("config params proc_memory_limit/proc_pids_limit are supported only on linux")
+	}
+	return nil
+}
+
+func hasSyscall(target *prog.Target, pattern string) bool {
+	for _, call := range target.Syscalls {
+		if MatchSyscall(call.Name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 func (cfg *Config) initTimeouts() {
 	slowdown := 1
 	switch {
@@ -309,16 +508,16 @@ func ParseEnabledSyscalls(target *prog.Target, enabled, disabled []string) ([]in
 	syscalls := make(map[int]bool)
 	if len(enabled) != 0 {
 		for _, c := range enabled {
-			n := 0
-			for _, call := range target.Syscalls {
-				if MatchSyscall(call.Name, c) {
-					syscalls[call.ID] = true
-					n++
-				}
+			ids, err := matchSyscalls(target, c)
+			if err != nil {
+				return nil, err
 			}
-			if n == 0 {
+			if len(ids) == 0 {
 				return nil, fmt.Errorf("unknown enabled syscall: %v", c)
 			}
+			for _, id := range ids {
+				syscalls[id] = true
+			}
 		}
 	} else {
 		for _, call := range target.Syscalls {
@@ -331,16 +530,16 @@ func ParseEnabledSyscalls(target *prog.Target, enabled, disabled []string) ([]in
 		}
 	}
 	for _, c := range disabled {
-		n := 0
-		for _, call := range target.Syscalls {
-			if MatchSyscall(call.Name, c) {
-				delete(syscalls, call.ID)
-				n++
-			}
+		ids, err := matchSyscalls(target, c)
+		if err != nil {
+			return nil, err
 		}
-		if n == 0 {
+		if len(ids) == 0 {
 			return nil, fmt.Errorf("unknown disabled syscall: %v", c)
 		}
+		for _, id := range ids {
+			delete(syscalls, id)
+		}
 	}
 	if len(syscalls) == 0 {
 		return nil, fmt.Errorf("all syscalls are disabled by disable_syscalls in config")
@@ -371,6 +570,25 @@ func ParseNoMutateSyscalls(target *prog.Target, syscalls []string) (map[int]bool
 	return result, nil
 }
 
+func parseStateSyscalls(target *prog.Target, syscalls []string) (map[int]bool, error) {
+	result := make(map[int]bool)
+	for _, c := range syscalls {
+		ids, err := matchSyscalls(target, c)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("unknown long_prog_calls syscall: %v", c)
+		}
+		for _, id := range ids {
+			if !target.Syscalls[id].Attrs.Disabled {
+				result[id] = true
+			}
+		}
+	}
+	return result, nil
+}
+
 func MatchSyscall(name, pattern string) bool {
 	if pattern == name || strings.HasPrefix(name, pattern+"$") {
 		return true
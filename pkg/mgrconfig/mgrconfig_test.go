@@ -4,7 +4,9 @@
 package mgrconfig_test
 
 import (
+	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/syzkaller/pkg/config"
@@ -66,3 +68,141 @@ func TestMatchSyscall(t *testing.T) {
 		}
 	}
 }
+
+func TestExternalNet(t *testing.T) {
+	tests := []struct {
+		extra string
+		err   string
+		rate  int
+	}{
+		{extra: ``},
+		{extra: `"external_net": {"addr": "10.0.0.1"}`, rate: 100},
+		{extra: `"external_net": {"addr": "10.0.0.1", "rate": 10, "probe_port": 22}`, rate: 10},
+		{extra: `"external_net": {"addr": "::1"}`, err: "is not an IPv4 address"},
+		{extra: `"external_net": {"addr": "10.0.0.1", "probe_port": 100000}`, err: "probe_port"},
+		{extra: `"external_net": {"addr": "10.0.0.1"}, "sandbox": "setuid"`, err: "requires sandbox none"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			data := `{
+				"target": "linux/amd64",
+				"http": "localhost:0",
+				"workdir": "/syzkaller/workdir",
+				"syzkaller": "./testdata/syzkaller",
+				"type": "qemu",
+				"vm": {}`
+			if test.extra != "" {
+				data += ",\n" + test.extra
+			}
+			data += "}"
+			cfg, err := LoadData([]byte(data))
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			enabled := false
+			for _, id := range cfg.Syscalls {
+				if MatchSyscall(cfg.Target.Syscalls[id].Name, "syz_emit_external") {
+					enabled = true
+				}
+			}
+			if enabled != (cfg.ExternalNet != nil) {
+				t.Fatalf("syz_emit_external enabled=%v with external_net=%+v", enabled, cfg.ExternalNet)
+			}
+			if cfg.ExternalNet != nil && cfg.ExternalNet.Rate != test.rate {
+				t.Fatalf("rate=%v, want %v", cfg.ExternalNet.Rate, test.rate)
+			}
+		})
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	for _, test := range []struct {
+		levels string
+		err    string
+	}{
+		{levels: `{"vm": -1, "repro": 1}`},
+		{levels: `{"vm": -1, "fuzzer": 1}`, err: `unknown log module "fuzzer"`},
+	} {
+		data := `{
+			"target": "linux/amd64",
+			"http": "localhost:0",
+			"workdir": "/syzkaller/workdir",
+			"syzkaller": "./testdata/syzkaller",
+			"type": "qemu",
+			"vm": {},
+			"log_levels": ` + test.levels + `}`
+		_, err := LoadData([]byte(data))
+		if test.err == "" && err != nil || test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("log_levels %v: expected error %q, got %v", test.levels, test.err, err)
+		}
+	}
+}
+
+func TestDependentParams(t *testing.T) {
+	tests := []struct {
+		extra string
+		err   string
+	}{
+		{extra: `"experimental": {"energy_schedule": true, "energy_temperature": 2}`},
+		{extra: `"experimental": {"energy_temperature": 2}`, err: "requires experimental.energy_schedule"},
+		{extra: `"experimental": {"energy_schedule": true, "energy_temperature": -1}`, err: "energy_temperature: -1"},
+		{extra: `"experimental": {"hunt_title": "foo"}`},
+		{extra: `"experimental": {"warning_feedback": true, "warning_patterns": ["error -\\d+"]}`},
+		{extra: `"experimental": {"warning_patterns": ["foo"]}`, err: "requires experimental.warning_feedback"},
+		{extra: `"experimental": {"warning_feedback": true, "warning_patterns": ["("]}`, err: "warning_patterns"},
+		{extra: `"experimental": {"hunt_title": "foo"}, "reproduce": false`, err: "hunt_title requires reproduce"},
+		{extra: `"experimental": {"kdump": {"dump_level": 31}}`},
+		{extra: `"experimental": {"kdump": {"dump_level": 32}}`, err: "kdump.dump_level: 32"},
+		{extra: `"experimental": {"kdump": {"crash_scripts": ["foo"]}}`, err: "crash_scripts require kernel_obj"},
+		{extra: `"experimental": {"watchdog": "/dev/watchdog"}`},
+		{extra: `"experimental": {"watchdog": "watchdog"}`, err: "experimental.watchdog"},
+		{extra: `"experimental": {"watchdog": "/dev/watchdog", "watchdog_timeout": 120}`},
+		{extra: `"experimental": {"watchdog": "/dev/watchdog", "watchdog_timeout": -1}`, err: "watchdog_timeout"},
+		{extra: `"experimental": {"watchdog_timeout": 120}`, err: "watchdog_timeout"},
+		{extra: `"experimental": {"vm_scheduling": {"slow_vms": [2, 3], "repro": "fast"}}`},
+		{extra: `"experimental": {"vm_scheduling": {"slow_vms": [2, 2]}}`, err: "duplicate index 2"},
+		{extra: `"experimental": {"vm_scheduling": {"repro": "slow"}}`, err: "vm_scheduling.repro"},
+		{extra: `"experimental": {"vm_scheduling": {"triage": "prefer_fast"}}`, err: "vm_scheduling.triage"},
+		{extra: `"experimental": {"corpus_triage_interleave": 4, "corpus_triage_deadline": 60}`},
+		{extra: `"experimental": {"corpus_triage_interleave": 1}`, err: "corpus_triage_interleave: 1"},
+		{extra: `"experimental": {"corpus_triage_deadline": -1}`, err: "corpus_triage_deadline: -1"},
+		{extra: `"proc_memory_limit": 512, "proc_pids_limit": 64`},
+		{extra: `"proc_memory_limit": -1`, err: "bad config param proc_memory_limit: -1"},
+		{extra: `"procs": 4, "net_capture": {"iface": "eth1", "procs": [0, 3]}`},
+		{extra: `"procs": 4, "net_capture": {"iface": "eth1", "procs": [4]}`, err: "proc 4 is out of range"},
+		{extra: `"net_capture": {"iface": ""}`, err: "net_capture.iface"},
+		{extra: `"experimental": {"long_prog_calls": ["mkdirat$cgroup*", "mount"]}`},
+		{extra: `"experimental": {"long_prog_calls": ["foobar"]}`, err: "unknown long_prog_calls syscall: foobar"},
+		{extra: `"procs": "8"`, err: "line 8: param procs must be an integer, not string"},
+		{extra: `"experimental": {"energy_shedule": true}`,
+			err: `line 8: unknown param "energy_shedule" (did you mean "energy_schedule"?)`},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			data := `{
+				"target": "linux/amd64",
+				"http": "localhost:0",
+				"workdir": "/syzkaller/workdir",
+				"syzkaller": "./testdata/syzkaller",
+				"type": "qemu",
+				"vm": {},
+				` + test.extra + `}`
+			_, err := LoadData([]byte(data))
+			if test.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package mgrconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// SubsystemPrefix marks enable_syscalls/disable_syscalls entries that refer to all syscalls
+// that exercise a kernel subsystem, e.g. "subsystem:netfilter".
+const SubsystemPrefix = "subsystem:"
+
+// subsystemSyscalls maps kernel subsystem names (as in pkg/subsystem/lists) to patterns
+// of the syscall interfaces of the subsystem. The patterns are matched as enable_syscalls entries,
+// patterns that don't match any syscall of the target are ignored.
+var subsystemSyscalls = map[string]map[string][]string{
+	targets.Linux: {
+		"bluetooth": {"socket$bt_*", "syz_init_net_socket$bt_*", "ioctl$sock_bt*", "ioctl$HCI*",
+			"syz_emit_vhci"},
+		"bpf":      {"bpf", "mount$bpf"},
+		"btrfs":    {"syz_mount_image$btrfs", "ioctl$BTRFS*"},
+		"can":      {"socket$can*"},
+		"cgroups":  {"mkdirat$cgroup*", "openat$cgroup*", "write$cgroup*", "mount$cgroup*"},
+		"dri":      {"syz_open_dev$dri*", "openat$drirender*", "ioctl$DRM*"},
+		"ext4":     {"syz_mount_image$ext4", "ioctl$EXT4*"},
+		"f2fs":     {"syz_mount_image$f2fs", "ioctl$F2FS*"},
+		"fuse":     {"openat$fuse", "mount$fuse*", "write$FUSE*", "syz_fuse_handle_req"},
+		"input":    {"syz_open_dev$evdev", "ioctl$EVIOC*", "openat$uinput", "ioctl$UI_*"},
+		"io-uring": {"io_uring*", "syz_io_uring*"},
+		"keyrings": {"add_key", "request_key", "keyctl$*"},
+		"kvm":      {"openat$kvm", "ioctl$KVM*", "syz_kvm*"},
+		"lvs":      {"syz_genetlink_get_family_id$ipvs", "sendmsg$IPVS*", "setsockopt$IP_VS*"},
+		"media":    {"syz_open_dev$video*", "ioctl$VIDIOC*", "ioctl$MEDIA*"},
+		"mptcp": {"socket$inet_mptcp", "socket$inet6_mptcp", "syz_genetlink_get_family_id$mptcp",
+			"sendmsg$MPTCP*"},
+		"nbd": {"ioctl$NBD*", "syz_genetlink_get_family_id$nbd", "sendmsg$NBD*"},
+		"netfilter": {"socket$nl_netfilter", "sendmsg$nl_netfilter", "sendmsg$NFT*", "sendmsg$IPSET*",
+			"sendmsg$NFNL*", "sendmsg$NFQNL*", "sendmsg$NFULNL*", "setsockopt$IPT*", "setsockopt$IP6T*",
+			"setsockopt$EBT*", "setsockopt$ARPT*"},
+		"ntfs3":   {"syz_mount_image$ntfs3"},
+		"perf":    {"perf_event_open*"},
+		"ppp":     {"openat$ppp", "ioctl$PPP*"},
+		"rds":     {"socket$rds", "sendmsg$rds"},
+		"scsi":    {"syz_open_dev$sg", "ioctl$SG*", "ioctl$SCSI*"},
+		"sctp":    {"socket$inet_sctp", "socket$inet6_sctp", "setsockopt$inet_sctp*"},
+		"selinux": {"openat$selinux*", "write$selinux*"},
+		"sound":   {"syz_open_dev$snd*", "openat$sndseq", "ioctl$SNDRV*"},
+		"tipc":    {"socket$tipc", "syz_genetlink_get_family_id$tipc*", "sendmsg$TIPC*"},
+		"tls":     {"setsockopt$inet_tcp_TLS*", "setsockopt$inet6_tcp_TLS*"},
+		"usb": {"syz_usb*", "syz_open_dev$usbfs", "syz_open_dev$usbmon", "ioctl$USBDEVFS*",
+			"ioctl$MON*"},
+		"wireguard": {"syz_genetlink_get_family_id$wireguard", "sendmsg$WG*"},
+		"wireless":  {"syz_80211*", "syz_genetlink_get_family_id$nl80211", "sendmsg$NL80211*"},
+		"wpan":      {"syz_init_net_socket$802154*", "sendmsg$NL802154*", "sendmsg$IEEE802154*"},
+		"xfs":       {"syz_mount_image$xfs"},
+	},
+}
+
+// matchSyscalls returns IDs of syscalls that match an enable_syscalls/disable_syscalls entry.
+func matchSyscalls(target *prog.Target, entry string) ([]int, error) {
+	patterns := []string{entry}
+	if strings.HasPrefix(entry, SubsystemPrefix) {
+		name := strings.TrimPrefix(entry, SubsystemPrefix)
+		patterns = subsystemSyscalls[target.OS][name]
+		if patterns == nil {
+			return nil, fmt.Errorf("unknown subsystem %q, known subsystems: %v",
+				name, strings.Join(Subsystems(target.OS), ", "))
+		}
+	}
+	var ids []int
+	for _, call := range target.Syscalls {
+		for _, pattern := range patterns {
+			if MatchSyscall(call.Name, pattern) {
+				ids = append(ids, call.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// Subsystems returns names of the subsystems that can be used in "subsystem:" entries for the OS.
+func Subsystems(os string) []string {
+	var names []string
+	for name := range subsystemSyscalls[os] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package mgrconfig
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubsystemSyscalls(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Catch patterns that went stale after descriptions were renamed.
+	for name, patterns := range subsystemSyscalls[targets.Linux] {
+		for _, pattern := range patterns {
+			ids, err := matchSyscalls(target, pattern)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, ids, "subsystem %v pattern %v does not match any syscalls", name, pattern)
+		}
+	}
+
+	all, err := ParseEnabledSyscalls(target, nil, nil)
+	assert.NoError(t, err)
+	netfilter, err := ParseEnabledSyscalls(target, []string{"subsystem:netfilter"}, nil)
+	assert.NoError(t, err)
+	assert.Greater(t, len(netfilter), 10)
+	enabled := make(map[string]bool)
+	for _, id := range netfilter {
+		enabled[target.Syscalls[id].Name] = true
+	}
+	assert.True(t, enabled["setsockopt$IPT_SO_SET_REPLACE"])
+	assert.False(t, enabled["mmap"])
+
+	rest, err := ParseEnabledSyscalls(target, nil, []string{"subsystem:netfilter"})
+	assert.NoError(t, err)
+	assert.Equal(t, len(all)-len(netfilter), len(rest))
+
+	_, err = ParseEnabledSyscalls(target, []string{"subsystem:foobar"}, nil)
+	assert.ErrorContains(t, err, `unknown subsystem "foobar"`)
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package osutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+type limiter struct {
+	limits *Limits
+	cgroup string
+	fd     int
+}
+
+var cgroupSeq atomic.Uint64
+
+func setupLimits(cmd *exec.Cmd, limits *Limits) *limiter {
+	lim := &limiter{limits: limits, fd: -1}
+	if limits.Memory == 0 && limits.Processes == 0 {
+		return lim
+	}
+	parent := ownCgroup()
+	if parent == "" {
+		return lim
+	}
+	dir := filepath.Join(parent, fmt.Sprintf("syz-cmd-%v-%v", os.Getpid(), cgroupSeq.Add(1)))
+	if err := os.Mkdir(dir, DefaultDirPerm); err != nil {
+		return lim
+	}
+	lim.cgroup = dir
+	if limits.Memory != 0 {
+		if err := WriteFile(filepath.Join(dir, "memory.max"), []byte(fmt.Sprint(limits.Memory))); err != nil {
+			lim.close()
+			return lim
+		}
+		// Don't let the process to escape the limit by swapping.
+		WriteFile(filepath.Join(dir, "memory.swap.max"), []byte("0"))
+	}
+	if limits.Processes != 0 {
+		WriteFile(filepath.Join(dir, "pids.max"), []byte(fmt.Sprint(limits.Processes)))
+	}
+	fd, err := syscall.Open(dir, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		lim.close()
+		return lim
+	}
+	lim.fd = fd
+	// The child is placed into the cgroup atomically during clone,
+	// so there is no window when it runs without the limits.
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = fd
+	return lim
+}
+
+// ownCgroup returns the cgroup v2 directory of the current process,
+// if it allows to create child cgroups with the memory and pids controllers.
+func ownCgroup() string {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	for s := bufio.NewScanner(bytes.NewReader(data)); s.Scan(); {
+		path, ok := strings.CutPrefix(s.Text(), "0::")
+		if !ok {
+			continue
+		}
+		dir := filepath.Join(cgroupRoot, path)
+		controllers, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+		if err != nil {
+			return ""
+		}
+		fields := strings.Fields(string(controllers))
+		if !slices.Contains(fields, "memory") || !slices.Contains(fields, "pids") {
+			return ""
+		}
+		return dir
+	}
+	return ""
+}
+
+func (lim *limiter) started(pid int) {
+	// Go does not support setting rlimits for the child before exec,
+	// so we set them right after start. The child may run briefly without the limits,
+	// but this is enough to catch runaway processes.
+	if lim.limits.CPUTime != 0 {
+		secs := uint64((lim.limits.CPUTime + time.Second - 1) / time.Second)
+		// The process gets SIGXCPU at the soft limit and SIGKILL at the hard limit.
+		unix.Prlimit(pid, unix.RLIMIT_CPU, &unix.Rlimit{Cur: secs, Max: secs + 1}, nil)
+	}
+	if lim.limits.Memory != 0 && lim.cgroup == "" {
+		unix.Prlimit(pid, unix.RLIMIT_AS, &unix.Rlimit{Cur: lim.limits.Memory, Max: lim.limits.Memory}, nil)
+	}
+}
+
+func (lim *limiter) kill() {
+	if lim.cgroup == "" {
+		return
+	}
+	// cgroup.kill is supported since Linux 5.14, on older kernels we rely on killing the process group.
+	WriteFile(filepath.Join(lim.cgroup, "cgroup.kill"), []byte("1"))
+}
+
+func (lim *limiter) oomKilled() bool {
+	if lim.cgroup == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(lim.cgroup, "memory.events"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "oom_kill" {
+			return fields[1] != "0"
+		}
+	}
+	return false
+}
+
+func (lim *limiter) close() {
+	if lim.fd != -1 {
+		syscall.Close(lim.fd)
+		lim.fd = -1
+	}
+	if lim.cgroup == "" {
+		return
+	}
+	lim.kill()
+	// Killed processes may take some time to exit, and the cgroup can't be removed until then.
+	for i := 0; i < 100; i++ {
+		if err := syscall.Rmdir(lim.cgroup); err == nil || err == syscall.ENOENT {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	lim.cgroup = ""
+}
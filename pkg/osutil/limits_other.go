@@ -0,0 +1,21 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+//go:build !linux
+
+package osutil
+
+import (
+	"os/exec"
+)
+
+type limiter struct{}
+
+func setupLimits(cmd *exec.Cmd, limits *Limits) *limiter {
+	return &limiter{}
+}
+
+func (lim *limiter) started(pid int) {}
+func (lim *limiter) kill()           {}
+func (lim *limiter) oomKilled() bool { return false }
+func (lim *limiter) close()          {}
@@ -36,6 +36,31 @@ func RunCmd(timeout time.Duration, dir, bin string, args ...string) ([]byte, err
 // Run runs cmd with the specified timeout.
 // Returns combined output. If the command fails, err includes output.
 func Run(timeout time.Duration, cmd *exec.Cmd) ([]byte, error) {
+	return run(timeout, cmd, nil)
+}
+
+// Limits restrict resources available to a subprocess and all of its descendants.
+// Zero values mean no limit.
+type Limits struct {
+	// Max memory in bytes. If cgroups v2 are available, this limits memory usage of the whole
+	// process tree; otherwise it limits address space size of the process (RLIMIT_AS).
+	Memory uint64
+	// Max CPU time of the process (RLIMIT_CPU).
+	CPUTime time.Duration
+	// Max number of processes/threads in the process tree (requires cgroups v2).
+	Processes int
+}
+
+// RunLimited is similar to Run, but additionally applies the limits to the command.
+// If cgroups v2 are available, the command runs in a separate cgroup and all processes
+// in the cgroup are killed when the command exits or times out.
+// The limits are applied on a best-effort basis: only Linux is supported,
+// and limits that can't be applied are silently ignored.
+func RunLimited(timeout time.Duration, limits Limits, cmd *exec.Cmd) ([]byte, error) {
+	return run(timeout, cmd, &limits)
+}
+
+func run(timeout time.Duration, cmd *exec.Cmd, limits *Limits) ([]byte, error) {
 	output := new(bytes.Buffer)
 	if cmd.Stdout == nil {
 		cmd.Stdout = output
@@ -44,9 +69,17 @@ func Run(timeout time.Duration, cmd *exec.Cmd) ([]byte, error) {
 		cmd.Stderr = output
 	}
 	setPdeathsig(cmd, true)
+	var lim *limiter
+	if limits != nil {
+		lim = setupLimits(cmd, limits)
+		defer lim.close()
+	}
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start %v %+v: %w", cmd.Path, cmd.Args, err)
 	}
+	if lim != nil {
+		lim.started(cmd.Process.Pid)
+	}
 	done := make(chan bool)
 	timedout := make(chan bool, 1)
 	timer := time.NewTimer(timeout)
@@ -55,6 +88,9 @@ func Run(timeout time.Duration, cmd *exec.Cmd) ([]byte, error) {
 		case <-timer.C:
 			timedout <- true
 			killPgroup(cmd)
+			if lim != nil {
+				lim.kill()
+			}
 			cmd.Process.Kill()
 		case <-done:
 			timedout <- false
@@ -63,10 +99,16 @@ func Run(timeout time.Duration, cmd *exec.Cmd) ([]byte, error) {
 	}()
 	err := cmd.Wait()
 	close(done)
+	if lim != nil {
+		// Kill any descendants that escaped the process group.
+		lim.kill()
+	}
 	if err != nil {
 		text := fmt.Sprintf("failed to run %q: %v", cmd.Args, err)
 		if <-timedout {
 			text = fmt.Sprintf("timedout after %v %q", timeout, cmd.Args)
+		} else if lim != nil && lim.oomKilled() {
+			text = fmt.Sprintf("exceeded memory limit of %v bytes %q", limits.Memory, cmd.Args)
 		}
 		exitCode := 0
 		var exitErr *exec.ExitError
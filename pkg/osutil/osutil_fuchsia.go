@@ -35,6 +35,10 @@ func ProcessExitStatus(ps *os.ProcessState) int {
 	return 0
 }
 
+func ProcessCrashed(ps *os.ProcessState) bool {
+	return false
+}
+
 func prolongPipe(r, w *os.File) {
 }
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -129,3 +130,21 @@ func TestMonotonicNano(t *testing.T) {
 		t.Fatalf("diff %v", diff)
 	}
 }
+
+func TestRunLimited(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("limits are supported only on linux")
+	}
+	start := time.Now()
+	_, err := RunLimited(time.Minute, Limits{CPUTime: time.Second}, Command("sh", "-c", "while :; do :; done"))
+	if err == nil {
+		t.Fatalf("runaway command succeeded")
+	}
+	if strings.Contains(err.Error(), "timedout") || time.Since(start) > 30*time.Second {
+		t.Fatalf("runaway command was not killed by the CPU limit: %v", err)
+	}
+	out, err := RunLimited(time.Minute, Limits{CPUTime: time.Minute, Memory: 1 << 30}, Command("echo", "ok"))
+	if err != nil || strings.TrimSpace(string(out)) != "ok" {
+		t.Fatalf("limited command failed: %q %v", out, err)
+	}
+}
@@ -100,6 +100,20 @@ func ProcessExitStatus(ps *os.ProcessState) int {
 	return ps.Sys().(syscall.WaitStatus).ExitStatus()
 }
 
+// ProcessCrashed returns true if the process was killed by a signal that
+// denotes a bug in the process itself (as opposed to e.g. SIGKILL).
+func ProcessCrashed(ps *os.ProcessState) bool {
+	ws := ps.Sys().(syscall.WaitStatus)
+	if !ws.Signaled() {
+		return false
+	}
+	switch ws.Signal() {
+	case syscall.SIGSEGV, syscall.SIGBUS, syscall.SIGILL, syscall.SIGFPE, syscall.SIGABRT, syscall.SIGTRAP:
+		return true
+	}
+	return false
+}
+
 // CreateMemMappedFile creates a temp file with the requested size and maps it into memory.
 func CreateMemMappedFile(size int) (f *os.File, mem []byte, err error) {
 	f, err = CreateSharedMemFile(size)
@@ -36,6 +36,10 @@ func ProcessExitStatus(ps *os.ProcessState) int {
 	return ps.Sys().(syscall.WaitStatus).ExitStatus()
 }
 
+func ProcessCrashed(ps *os.ProcessState) bool {
+	return false
+}
+
 func Sandbox(cmd *exec.Cmd, user, net bool) error {
 	return nil
 }
@@ -0,0 +1,143 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConsoleClock maps host time to the kernel time of printk timestamps ("[  123.456789]")
+// of console output lines. It's fed with the console output as it's received from the machine,
+// and estimates the offset between the two clocks as the min difference between the receive time
+// and the timestamp of a line (console output can be delayed, but can't come from the future).
+type ConsoleClock struct {
+	mu      sync.Mutex
+	offset  time.Duration
+	valid   bool
+	midLine bool
+}
+
+// Note accounts console output that was received at host time now.
+func (clock *ConsoleClock) Note(out []byte, now time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	for len(out) != 0 {
+		line := out
+		end := bytes.IndexByte(out, '\n')
+		if end != -1 {
+			line, out = out[:end], out[end+1:]
+		} else {
+			out = nil
+		}
+		// Timestamps of lines that started in previous chunks were received earlier.
+		if ts, ok := parseTimestamp(line); ok && !clock.midLine {
+			if offset := now - ts; !clock.valid || offset < clock.offset {
+				clock.offset = offset
+				clock.valid = true
+			}
+		}
+		clock.midLine = end == -1
+	}
+}
+
+// KernelTime returns the kernel time that corresponds to the host time.
+func (clock *ConsoleClock) KernelTime(host time.Duration) (time.Duration, bool) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return host - clock.offset, clock.valid
+}
+
+// ExecutedProg describes a program that was executing on the machine.
+type ExecutedProg struct {
+	// Name identifies the program in the annotation.
+	// It should not contain "executing program", so that prog.ParseLog ignores the annotations.
+	Name string
+	// Host time (in the time base of the ConsoleClock) when the program started executing.
+	Start time.Duration
+}
+
+var timestampRe = regexp.MustCompile(`^\[ *([0-9]+)\.([0-9]{6})\]`)
+
+func parseTimestamp(line []byte) (time.Duration, bool) {
+	match := timestampRe.FindSubmatch(line)
+	if match == nil {
+		return 0, false
+	}
+	sec, err1 := strconv.ParseUint(string(match[1]), 10, 64)
+	usec, err2 := strconv.ParseUint(string(match[2]), 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	return time.Duration(sec)*time.Second + time.Duration(usec)*time.Microsecond, true
+}
+
+// AnnotateOutput inserts a line marking the start of each of the programs into rep.Output
+// right before the first console line that was printed after the program started executing,
+// so that kernel messages can be attributed to the programs that were running at that time.
+// Programs that started before the first timestamped line or after the start of the report
+// are not annotated.
+// Positions in rep are adjusted accordingly. Returns the number of inserted lines.
+func AnnotateOutput(rep *Report, clock *ConsoleClock, progs []ExecutedProg) int {
+	type annotation struct {
+		ts   time.Duration
+		name string
+	}
+	var annotations []annotation
+	for _, p := range progs {
+		if ts, ok := clock.KernelTime(p.Start); ok {
+			annotations = append(annotations, annotation{ts, p.Name})
+		}
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].ts < annotations[j].ts
+	})
+	positions := []*int{&rep.StartPos, &rep.EndPos, &rep.SkipPos}
+	orig := make([]int, len(positions))
+	for i, pos := range positions {
+		orig[i] = *pos
+	}
+	out := new(bytes.Buffer)
+	first, inserted := true, 0
+	for pos := 0; pos < len(rep.Output); {
+		end := bytes.IndexByte(rep.Output[pos:], '\n')
+		if end == -1 {
+			end = len(rep.Output)
+		} else {
+			end += pos + 1
+		}
+		line := rep.Output[pos:end]
+		if ts, ok := parseTimestamp(line); ok {
+			for ; len(annotations) != 0 && annotations[0].ts <= ts; annotations = annotations[1:] {
+				if first {
+					// The program started before the output that we have.
+					continue
+				}
+				a := annotations[0]
+				n, _ := fmt.Fprintf(out, "[%5d.%06d] syzkaller: started %v\n",
+					a.ts/time.Second, a.ts%time.Second/time.Microsecond, a.name)
+				for i, p := range positions {
+					if orig[i] >= pos {
+						*p += n
+					}
+				}
+				inserted++
+			}
+			first = false
+		}
+		if orig[1] > orig[0] && pos >= orig[0] {
+			// Don't break the report itself, programs started after it are irrelevant anyway.
+			annotations = nil
+		}
+		out.Write(line)
+		pos = end
+	}
+	rep.Output = out.Bytes()
+	return inserted
+}
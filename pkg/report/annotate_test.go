@@ -0,0 +1,49 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotateOutput(t *testing.T) {
+	clock := new(ConsoleClock)
+	_, ok := clock.KernelTime(time.Second)
+	assert.False(t, ok)
+	clock.Note([]byte("[   10.000000] a\n[   10.500000] b"), 20*time.Second)
+	// The first line of the chunk continues the previous line, so its timestamp is ignored.
+	clock.Note([]byte("[    0.000000] tail\n[   11.000000] c\n"), 25*time.Second)
+	ts, ok := clock.KernelTime(20 * time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 10500*time.Millisecond, ts)
+
+	const before = "[   10.000000] a\n[   10.500000] b tail\n[   11.000000] c\n"
+	const crash = "[   12.000000] BUG: crash\n[   12.100000] trace\n"
+	rep := &Report{
+		Output:   []byte(before + crash),
+		StartPos: len(before),
+		EndPos:   len(before + crash),
+		SkipPos:  len(before) + 10,
+	}
+	progs := []ExecutedProg{
+		{Name: "prog2", Start: 21400 * time.Millisecond},
+		{Name: "prog0", Start: 19 * time.Second},
+		{Name: "prog1", Start: 20200 * time.Millisecond},
+		{Name: "prog3", Start: 21550 * time.Millisecond},
+	}
+	assert.Equal(t, 2, AnnotateOutput(rep, clock, progs))
+	assert.Equal(t, "[   10.000000] a\n"+
+		"[   10.500000] b tail\n"+
+		"[   10.700000] syzkaller: started prog1\n"+
+		"[   11.000000] c\n"+
+		"[   11.900000] syzkaller: started prog2\n"+
+		crash, string(rep.Output))
+	assert.True(t, strings.HasPrefix(string(rep.Output[rep.StartPos:]), "[   12.000000] BUG: crash"))
+	assert.Equal(t, len(rep.Output), rep.EndPos)
+	assert.Equal(t, rep.StartPos+10, rep.SkipPos)
+}
@@ -18,6 +18,9 @@ const (
 	LockdepBug       = Type("LOCKDEP")
 	AtomicSleep      = Type("ATOMIC_SLEEP")
 	KMSAN            = Type("KMSAN")
+	MTE              = Type("MTE") // arm64 Memory Tagging Extension tag check fault
+	PAC              = Type("PAC") // arm64 Pointer Authentication failure
+	PM               = Type("PM")  // power management (suspend/resume) failure
 	SyzFailure       = Type("SYZ_FAILURE")
 )
 
@@ -27,3 +30,34 @@ func (t Type) String() string {
 	}
 	return string(t)
 }
+
+// Severity is a rough estimation of the security impact of a crash.
+// Severities are ordered, so they can be compared and sorted.
+type Severity int
+
+const (
+	UnknownSeverity  Severity = iota
+	LowSeverity               // denial of service at most (WARNINGs, hangs, leaks, NULL derefs)
+	MediumSeverity            // potential info leaks (out-of-bounds/use-after-free reads, wild accesses)
+	HighSeverity              // memory corruptions (out-of-bounds/use-after-free writes, double frees)
+	CriticalSeverity          // memory corruptions with likely controlled offset or size
+)
+
+var severityNames = []string{"unknown", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return severityNames[UnknownSeverity]
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity is the reverse of Severity.String.
+func ParseSeverity(name string) (Severity, bool) {
+	for i, s := range severityNames {
+		if s == name {
+			return Severity(i), true
+		}
+	}
+	return UnknownSeverity, false
+}
@@ -26,6 +26,8 @@ const (
 
 const objdumpCallTimeout = 10 * time.Second
 
+var objdumpLimits = osutil.Limits{Memory: 1 << 30, CPUTime: objdumpCallTimeout}
+
 type DecompiledOpcode struct {
 	Offset          int
 	IsBad           bool
@@ -65,7 +67,8 @@ func objdumpExecutor(rawOpcodes []byte, args []string, target *targets.Target) (
 		return nil, fmt.Errorf("failed to write to temp file: %w", err)
 	}
 
-	return osutil.RunCmd(objdumpCallTimeout, "", target.Objdump, append(args, fileName)...)
+	cmd := osutil.Command(target.Objdump, append(args, fileName)...)
+	return osutil.RunLimited(objdumpCallTimeout, objdumpLimits, cmd)
 }
 
 // nolint: lll
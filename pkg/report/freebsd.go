@@ -32,7 +32,42 @@ func (ctx *freebsd) Symbolize(rep *Report) error {
 	return nil
 }
 
-var freebsdStackParams = &stackParams{}
+var freebsdStackParams = &stackParams{
+	stackStartRes: []*regexp.Regexp{
+		regexp.MustCompile(`stack backtrace:`),
+		regexp.MustCompile(`lock order .* (?:established|attempted) at:`),
+	},
+	frameRes: []*regexp.Regexp{
+		// db_trace_self_wrapper() at db_trace_self_wrapper+0x47/frame 0xfffffe001fa45770
+		compile(`^([a-zA-Z0-9_]+)\(\) at [a-zA-Z0-9_]+\+0x`),
+		// #0 0xffffffff80bc6b62 at witness_checkorder+0xbe2
+		compile(`^#[0-9]+ {{ADDR}} at {{FUNC}}`),
+	},
+	skipPatterns: []string{
+		"^db_trace",
+		"^kdb_",
+		"^vpanic$",
+		"^panic$",
+		"^kassert_panic$",
+		"^witness_",
+		// Lock primitives, WITNESS reports the function that takes the lock.
+		"^_*mtx_",
+		"^_*sx_",
+		"^_*rw_",
+		"^_*rm_",
+		"^_*lockmgr",
+		"^_vn_lock$",
+		"^VOP_LOCK",
+		"^vop_stdlock$",
+	},
+}
+
+var freebsdAssertStack = &stackFmt{
+	parts: []*regexp.Regexp{
+		compile("KDB: stack backtrace:"),
+		parseStackTrace,
+	},
+}
 
 // nolint: goconst
 var freebsdOopses = append([]*oops{
@@ -103,9 +138,49 @@ var freebsdOopses = append([]*oops{
 					"([a-zA-Z0-9_]+)\\(\\) at [a-zA-Z0-9_+/ ]+\\+0x.*\\n"),
 				fmt: "%[1]v in %[2]v",
 			},
+			{
+				// MPASS() and KASSERT() without a custom message print the failed expression.
+				title:      compile("panic: Assertion (.+) failed at .*\\n(?:.*\\n)*?KDB: stack backtrace:\\n"),
+				fmt:        "panic: Assertion %[1]v failed in %[2]v",
+				stack:      freebsdAssertStack,
+				reportType: crash.Bug,
+			},
+			{
+				// Other KASSERT() failures print the custom message, but still go through kassert_panic.
+				title: compile("(panic: .*)\\n(?:.*\\n)*?KDB: stack backtrace:\\n" +
+					"(?:.*\\n)*?kassert_panic\\(\\) at "),
+				fmt:        "%[1]v in %[2]v",
+				stack:      freebsdAssertStack,
+				reportType: crash.Bug,
+			},
 		},
 		[]*regexp.Regexp{},
 		crash.UnknownType,
 	},
+	{
+		[]byte("lock order reversal:"),
+		[]oopsFormat{
+			{
+				// WITNESS prints the held lock first and the acquired lock second,
+				// lock names are followed by the lock type names in parentheses:
+				//  1st 0xfffff80004a3a9a8 so_rcv (so_rcv, sx) @ /sys/kern/uipc_socket.c:2057
+				// Types are used since names of some locks are different for every instance.
+				title: compile("lock order reversal:\\n" +
+					" *1st {{ADDR}} .*?\\(([^,)]+)(?:, [a-z]+)?\\) @ .*\\n" +
+					" *2nd {{ADDR}} .*?\\(([^,)]+)(?:, [a-z]+)?\\) @ .*\\n"),
+				fmt: "lock order reversal: %[1]v -> %[2]v in %[3]v",
+				stack: &stackFmt{
+					// Older versions print only the current stack, newer ones may print the stack
+					// where the reverse order was established first.
+					parts: []*regexp.Regexp{
+						compile("^(?:stack backtrace:|lock order .* attempted at:)"),
+						parseStackTrace,
+					},
+				},
+			},
+		},
+		[]*regexp.Regexp{},
+		crash.LockdepBug,
+	},
 	&groupGoRuntimeErrors,
 }, commonOopses...)
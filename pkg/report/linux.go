@@ -171,13 +171,19 @@ func (ctx *linux) Parse(output []byte) *Report {
 		rep.AltTitles = altTitles
 		rep.Corrupted = corrupted != ""
 		rep.CorruptedReason = corrupted
+		setReportType(rep, oops, format)
+		if info := decodeArm64Fault(rep.Type, report); info != "" {
+			prefix = append(prefix, []byte(info))
+		}
+		if info := pmDeviceInfo(rep.Type, report); info != "" {
+			prefix = append(prefix, []byte(info))
+		}
 		for _, line := range prefix {
 			rep.Report = append(rep.Report, line...)
 			rep.Report = append(rep.Report, '\n')
 		}
 		rep.reportPrefixLen = len(rep.Report)
 		rep.Report = append(rep.Report, report...)
-		setReportType(rep, oops, format)
 		if !rep.Corrupted {
 			rep.Corrupted, rep.CorruptedReason = ctx.isCorrupted(title, report, format)
 		}
@@ -204,6 +210,67 @@ func (ctx *linux) Parse(output []byte) *Report {
 	}
 }
 
+var (
+	mteFaultAddrRe = regexp.MustCompile(`Unable to handle kernel paging request at virtual address ([0-9a-f]{16})`)
+	mteFaultWnRRe  = regexp.MustCompile(`WnR = ([01])`)
+	fpacESRRe      = regexp.MustCompile(`Internal error: Oops - FPAC: ([0-9a-f]+)`)
+)
+
+// decodeArm64Fault returns a human-readable description of MTE/PAC faults,
+// since the raw kernel output only contains the faulting address and ESR value.
+func decodeArm64Fault(typ crash.Type, report []byte) string {
+	switch typ {
+	case crash.MTE:
+		match := mteFaultAddrRe.FindSubmatch(report)
+		if match == nil {
+			return ""
+		}
+		addr, err := strconv.ParseUint(string(match[1]), 16, 64)
+		if err != nil {
+			return ""
+		}
+		access := "access"
+		if match := mteFaultWnRRe.FindSubmatch(report); match != nil {
+			access = map[string]string{"0": "read", "1": "write"}[string(match[1])]
+		}
+		return fmt.Sprintf("MTE tag check fault: %v of address 0x%016x with pointer tag 0x%x",
+			access, addr, addr>>56&0xf)
+	case crash.PAC:
+		match := fpacESRRe.FindSubmatch(report)
+		if match == nil {
+			return ""
+		}
+		esr, err := strconv.ParseUint(string(match[1]), 16, 64)
+		if err != nil {
+			return ""
+		}
+		// ISS[1] selects instruction/data key, ISS[0] selects A/B key.
+		key := [2]string{"I", "D"}[esr>>1&1] + [2]string{"A", "B"}[esr&1]
+		return fmt.Sprintf("Pointer authentication failure: ESR 0x%x, key %v", esr, key)
+	}
+	return ""
+}
+
+var (
+	pmDriverDeviceRe = regexp.MustCompile(`([^\s\]]+) ([^\s\]]+): (?:PM: dpm_run_callback|\*\*\*\* DPM device timeout)`)
+	pmDeviceRe       = regexp.MustCompile(`PM: Device ([^\s\]]+) failed to`)
+)
+
+// pmDeviceInfo returns the device involved in a power management failure.
+// Titles contain only the driver name, since device names depend on the machine configuration.
+func pmDeviceInfo(typ crash.Type, report []byte) string {
+	if typ != crash.PM {
+		return ""
+	}
+	if match := pmDriverDeviceRe.FindSubmatch(report); match != nil {
+		return fmt.Sprintf("PM failure: device %s, driver %s", match[2], match[1])
+	}
+	if match := pmDeviceRe.FindSubmatch(report); match != nil {
+		return fmt.Sprintf("PM failure: device %s", match[1])
+	}
+	return ""
+}
+
 func (ctx *linux) findFirstOops(output []byte) (oops *oops, startPos int, context string) {
 	for pos, next := 0, 0; pos < len(output); pos = next + 1 {
 		next = bytes.IndexByte(output[pos:], '\n')
@@ -2025,6 +2092,22 @@ var linuxOopses = append([]*oops{
 	{
 		[]byte("Unable to handle kernel"),
 		[]oopsFormat{
+			{
+				// arm64 synchronous MTE tag check fault (without KASAN, which reports these itself).
+				title: compile("Unable to handle kernel paging request"),
+				report: compile("Unable to handle kernel paging request at virtual address [0-9a-f]+\\n" +
+					"(?:.*\\n){0,10}?.*FSC = 0x11: synchronous tag check fault"),
+				fmt: "BUG: MTE tag check fault in %[1]v",
+				alt: []string{"bad-access in %[1]v"},
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						linuxRipFrame,
+						linuxCallTrace,
+						parseStackTrace,
+					},
+				},
+				reportType: crash.MTE,
+			},
 			{
 				title: compile("Unable to handle kernel (paging request|NULL pointer dereference|access to user memory)"),
 				fmt:   "BUG: unable to handle kernel %[1]v in %[2]v",
@@ -2298,6 +2381,66 @@ var linuxOopses = append([]*oops{
 	{
 		[]byte("UBSAN:"),
 		[]oopsFormat{
+			// Array bounds and integer overflow reports are titled with the type of the expression,
+			// otherwise unrelated bugs in the same function get the same title.
+			{
+				title: compile("UBSAN: array-index-out-of-bounds in .*\\n" +
+					".*index -?[0-9]+ is out of range for type '(.*)'"),
+				fmt: "UBSAN: array-index-out-of-bounds in %[2]v for type '%[1]v'",
+				alt: []string{
+					"UBSAN: array-index-out-of-bounds in %[2]v",
+					"bad-access in %[2]v",
+				},
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						linuxCallTrace,
+						parseStackTrace,
+					},
+					skip: []string{"ubsan"},
+				},
+			},
+			{
+				title: compile("UBSAN: Undefined behaviour in .*\\n" +
+					".*index -?[0-9]+ is out of range for type '(.*)'"),
+				fmt: "UBSAN: array-index-out-of-bounds in %[2]v for type '%[1]v'",
+				alt: []string{
+					"UBSAN: undefined-behaviour in %[2]v",
+					"bad-access in %[2]v",
+				},
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						linuxCallTrace,
+						parseStackTrace,
+					},
+					skip: []string{"ubsan"},
+				},
+			},
+			{
+				title: compile("UBSAN: (signed|unsigned)-integer-overflow in .*\\n" +
+					".*[0-9]+ ([-+*]) -?[0-9]+ cannot be represented in type '(.*)'"),
+				fmt: "UBSAN: %[1]v-integer-overflow in %[4]v for %[2]v on type '%[3]v'",
+				alt: []string{"UBSAN: %[1]v-integer-overflow in %[4]v"},
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						linuxCallTrace,
+						parseStackTrace,
+					},
+					skip: []string{"ubsan", "handle_overflow"},
+				},
+			},
+			{
+				title: compile("UBSAN: Undefined behaviour in .*\\n.*(signed|unsigned) integer overflow:\\n" +
+					".*[0-9]+ ([-+*]) -?[0-9]+ cannot be represented in type '(.*)'"),
+				fmt: "UBSAN: %[1]v-integer-overflow in %[4]v for %[2]v on type '%[3]v'",
+				alt: []string{"UBSAN: undefined-behaviour in %[4]v"},
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						linuxCallTrace,
+						parseStackTrace,
+					},
+					skip: []string{"ubsan", "handle_overflow"},
+				},
+			},
 			{
 				title:  compile("UBSAN:"),
 				report: compile("UBSAN: Undefined behaviour in"),
@@ -2397,6 +2540,19 @@ var linuxOopses = append([]*oops{
 	{
 		[]byte("Internal error:"),
 		[]oopsFormat{
+			{
+				// arm64 with FEAT_FPAC traps on pointer authentication failures.
+				title: compile("Internal error: Oops - FPAC"),
+				fmt:   "BUG: pointer authentication failure in %[1]v",
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						linuxRipFrame,
+						linuxCallTrace,
+						parseStackTrace,
+					},
+				},
+				reportType: crash.PAC,
+			},
 			{
 				title: compile("Internal error:"),
 				fmt:   "Internal error in %[1]v",
@@ -2479,5 +2635,50 @@ var linuxOopses = append([]*oops{
 		[]*regexp.Regexp{},
 		crash.UnknownType,
 	},
+	{
+		[]byte("PM: dpm_run_callback"),
+		[]oopsFormat{
+			{
+				// Newer kernels prefix the message with the driver and device names.
+				title:        compile("([^\\s\\]]+) [^\\s\\]]+: PM: dpm_run_callback\\(\\): ([a-zA-Z0-9_]+)"),
+				fmt:          "PM: %[2]v failed in %[1]v",
+				noStackTrace: true,
+			},
+			{
+				title:        compile("PM: dpm_run_callback\\(\\): ([a-zA-Z0-9_]+)"),
+				fmt:          "PM: %[1]v failed",
+				noStackTrace: true,
+			},
+		},
+		[]*regexp.Regexp{},
+		crash.PM,
+	},
+	{
+		// Device suspend/resume callback hang detected by CONFIG_DPM_WATCHDOG.
+		[]byte("DPM device timeout"),
+		[]oopsFormat{
+			{
+				title:        compile("([^\\s\\]]+) [^\\s\\]]+: \\*\\*\\*\\* DPM device timeout"),
+				fmt:          "PM: dpm_watchdog timeout in %[1]v",
+				noStackTrace: true,
+			},
+		},
+		[]*regexp.Regexp{},
+		crash.PM,
+	},
+	{
+		// Printed when some tasks can't be frozen before suspend, e.g.
+		// "Freezing user space processes failed after 20.006 seconds (1 tasks refusing to freeze, wq_busy=0)".
+		[]byte("tasks refusing to freeze"),
+		[]oopsFormat{
+			{
+				title:        compile("Freezing (?:of tasks|user space processes|remaining freezable tasks) failed"),
+				fmt:          "PM: freezing of tasks failed",
+				noStackTrace: true,
+			},
+		},
+		[]*regexp.Regexp{},
+		crash.PM,
+	},
 	&groupGoRuntimeErrors,
 }, commonOopses...)
@@ -444,3 +444,69 @@ func testDisassembly(t *testing.T, reporter *Reporter, linuxReporter *linux, tes
 		t.Fatalf("expected:\n%s\ngot:\n%s", output, result)
 	}
 }
+
+func TestArm64FaultInfo(t *testing.T) {
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.ARM64,
+		},
+	}
+	reporter, err := NewReporter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"714": "MTE tag check fault: read of address 0xf3ff0000c8e47a40 with pointer tag 0x3\n",
+		"715": "Pointer authentication failure: ESR 0x72000000, key IA\n",
+	}
+	for file, info := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", "linux", "report", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Skip the test header.
+		data = data[bytes.Index(data, []byte("\n\n"))+2:]
+		rep := reporter.Parse(data)
+		if rep == nil {
+			t.Fatalf("%v: no report", file)
+		}
+		if !bytes.HasPrefix(rep.Report, []byte(info)) {
+			t.Errorf("%v: want report prefix %q, got:\n%s", file, info, rep.Report)
+		}
+	}
+}
+
+func TestPMDeviceInfo(t *testing.T) {
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.AMD64,
+		},
+	}
+	reporter, err := NewReporter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"718": "PM failure: device 0000:02:00.0, driver r8169\n",
+		"719": "PM failure: device 1-1\n",
+		"720": "PM failure: device 0000:00:1f.3, driver snd_hda_intel\n",
+	}
+	for file, info := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", "linux", "report", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Skip the test header.
+		data = data[bytes.Index(data, []byte("\n\n"))+2:]
+		rep := reporter.Parse(data)
+		if rep == nil {
+			t.Fatalf("%v: no report", file)
+		}
+		// The info follows the console context lines preceding the report.
+		if !bytes.Contains(rep.Report, []byte(info)) {
+			t.Errorf("%v: want %q in the report, got:\n%s", file, info, rep.Report)
+		}
+	}
+}
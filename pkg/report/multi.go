@@ -0,0 +1,71 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+// A single execution may produce several distinct oopses: e.g. a WARNING followed by
+// a KASAN report caused by the same bug, or a consequence of the first bug in a different subsystem.
+// ParseFrom returns only the first one, which is not necessarily the root cause.
+// ParseMulti extracts all of them and returns the one chosen by SelectPrimary,
+// so that the crash title, bucketing and reproduction are based on the real bug,
+// the rest of the reports are kept in Report.Secondary.
+
+// Max number of secondary reports extracted from one output.
+const maxSecondaryReports = 8
+
+// ParseMulti is like ParseFrom, but also extracts not corrupted reports that follow the first one
+// (with titles distinct from all previous ones). The primary report (see SelectPrimary) is returned,
+// all other reports are stored in its Secondary field in the order of appearance.
+func (reporter *Reporter) ParseMulti(output []byte, minReportPos int) *Report {
+	rep := reporter.ParseFrom(output, minReportPos)
+	if rep == nil {
+		return nil
+	}
+	titles := map[string]bool{rep.Title: true}
+	for pos := rep.SkipPos; pos < len(output) && len(rep.Secondary) < maxSecondaryReports; {
+		next := reporter.ParseFrom(output, pos)
+		if next == nil {
+			break
+		}
+		pos = next.SkipPos
+		// Corrupted secondary reports are mostly consequences of the previous ones
+		// (e.g. "Kernel panic - not syncing: panic_on_warn set" line) and don't carry useful info.
+		if titles[next.Title] || next.Corrupted {
+			continue
+		}
+		titles[next.Title] = true
+		next.Index = len(rep.Secondary) + 1
+		rep.Secondary = append(rep.Secondary, next)
+	}
+	primary := SelectPrimary(rep)
+	if primary == rep {
+		return rep
+	}
+	for _, next := range rep.Secondary {
+		if next != primary {
+			primary.Secondary = append(primary.Secondary, next)
+		}
+	}
+	primary.Secondary = append([]*Report{rep}, primary.Secondary...)
+	rep.Secondary = nil
+	return primary
+}
+
+// SelectPrimary returns the report that most likely describes the real bug among rep and
+// its secondary reports: the most severe one, not corrupted ones are preferred,
+// and the earliest one is preferred among equal ones.
+func SelectPrimary(rep *Report) *Report {
+	best := rep
+	for _, next := range rep.Secondary {
+		if best.Corrupted != next.Corrupted {
+			if best.Corrupted {
+				best = next
+			}
+			continue
+		}
+		if next.Severity > best.Severity {
+			best = next
+		}
+	}
+	return best
+}
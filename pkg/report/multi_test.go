@@ -0,0 +1,70 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMulti(t *testing.T) {
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.AMD64,
+			SysTarget:  targets.Get(targets.Linux, targets.AMD64),
+		},
+	}
+	reporter, err := NewReporter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readLog := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("testdata", "linux", "report", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Strip the test header.
+		_, data, _ = bytes.Cut(data, []byte("\n\n"))
+		return data
+	}
+	warning := readLog("640")
+	kasan := readLog("417")
+	var output []byte
+	output = append(output, "some output\n"...)
+	output = append(output, warning...)
+	output = append(output, kasan...)
+	// Duplicates and corrupted reports are not included.
+	output = append(output, warning...)
+	output = append(output, "[  473.000000] Kernel panic - not syncing: Fatal exception\n"...)
+
+	rep := reporter.ParseMulti(output, 0)
+	if !assert.NotNil(t, rep) {
+		return
+	}
+	// KASAN report is more severe, so it becomes the primary one.
+	assert.Equal(t, "KASAN: use-after-free Read in usbvision_release", rep.Title)
+	assert.Equal(t, 1, rep.Index)
+	if !assert.Len(t, rep.Secondary, 1) {
+		return
+	}
+	secondary := rep.Secondary[0]
+	assert.Equal(t, "WARNING in packet_release", secondary.Title)
+	assert.Equal(t, 0, secondary.Index)
+	assert.Empty(t, secondary.Secondary)
+	assert.Less(t, secondary.StartPos, rep.StartPos)
+	assert.Equal(t, rep, SelectPrimary(rep))
+
+	// A single report.
+	rep = reporter.ParseMulti(warning, 0)
+	assert.Empty(t, rep.Secondary)
+	assert.Equal(t, rep, SelectPrimary(rep))
+	assert.Nil(t, reporter.ParseMulti([]byte("no crashes here\n"), 0))
+}
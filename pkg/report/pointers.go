@@ -0,0 +1,56 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// Kernel pointers printed with %p are hashed unless the kernel is booted with no_hash_pointers.
+// On 64-bit kernels a hashed pointer looks like 00000000xxxxxxxx, a real one looks like
+// ffff8880xxxxxxxx, and pointers printed before the hashing key is ready look like (____ptrval____).
+// Titles are normalized to look the same for all of these (see dynamicTitleReplacement),
+// and the address of the bad memory access is extracted into Report.AccessAddr only if it's real.
+
+var accessAddrRes = []struct {
+	re *regexp.Regexp
+	// The address is printed with %p, so it's real only with no_hash_pointers.
+	hashed bool
+}{
+	{regexp.MustCompile(`(?:Read|Write) of size [0-9]+ at addr ([0-9a-f]+)`), false},
+	{regexp.MustCompile(`BUG: unable to handle page fault for address: ([0-9a-f]+)`), false},
+	{regexp.MustCompile(`BUG: unable to handle kernel (?:NULL pointer dereference|paging request) at ([0-9a-f]+)`),
+		false},
+	{regexp.MustCompile(`Unable to handle kernel (?:NULL pointer dereference|paging request) at virtual address ` +
+		`([0-9a-f]+)`), false},
+	{regexp.MustCompile(`general protection fault, probably for non-canonical address 0x([0-9a-f]+)`), false},
+	{regexp.MustCompile(`(?:Out-of-bounds|Use-after-free|Invalid) (?:read|write) at 0x([0-9a-f]+)`), true},
+}
+
+// extractAccessAddr returns the address of the bad memory access mentioned in the report, or 0.
+func extractAccessAddr(report []byte, noHashPointers bool) uint64 {
+	for _, addr := range accessAddrRes {
+		if addr.hashed && !noHashPointers {
+			continue
+		}
+		match := addr.re.FindSubmatch(report)
+		if match == nil {
+			continue
+		}
+		val, err := strconv.ParseUint(string(match[1]), 16, 64)
+		if err != nil || addr.hashed && isHashedPointer(match[1]) {
+			// The kernel wasn't booted with no_hash_pointers after all.
+			continue
+		}
+		return val
+	}
+	return 0
+}
+
+// isHashedPointer returns whether a 64-bit pointer printed with %p looks hashed
+// (the hash is 32-bit, so the upper half is zero).
+func isHashedPointer(ptr []byte) bool {
+	return len(ptr) == 16 && string(ptr[:8]) == "00000000"
+}
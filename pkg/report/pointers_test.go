@@ -0,0 +1,70 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointerTitles(t *testing.T) {
+	titles := []string{
+		"WARNING: refcount bug in foo at ffff888012345678",
+		"WARNING: refcount bug in foo at 00000000deadbeef",
+		"WARNING: refcount bug in foo at (____ptrval____)",
+		"WARNING: refcount bug in foo at 0x(ptrval)",
+	}
+	for _, title := range titles {
+		assert.Equal(t, "WARNING: refcount bug in foo at ADDR",
+			sanitizeTitle(replaceTable(dynamicTitleReplacement, title)), title)
+	}
+}
+
+func TestExtractAccessAddr(t *testing.T) {
+	tests := []struct {
+		report         string
+		noHashPointers bool
+		addr           uint64
+	}{
+		{
+			report: `BUG: KASAN: slab-use-after-free in foo+0x10/0x20
+Read of size 8 at addr ffff888012345678 by task syz-executor/1234`,
+			addr: 0xffff888012345678,
+		},
+		{
+			report: `BUG: unable to handle page fault for address: ffffc90000123000`,
+			addr:   0xffffc90000123000,
+		},
+		{
+			report: `general protection fault, probably for non-canonical address 0xdffffc0000000001: 0000 [#1]`,
+			addr:   0xdffffc0000000001,
+		},
+		{
+			// KFENCE prints the address with %p, it's hashed by default.
+			report: `BUG: KFENCE: use-after-free read in foo+0x10/0x20
+
+Use-after-free read at 0x00000000b5e3d2a1 (in kfence-#42):`,
+		},
+		{
+			report: `BUG: KFENCE: use-after-free read in foo+0x10/0x20
+
+Use-after-free read at 0x00000000b5e3d2a1 (in kfence-#42):`,
+			noHashPointers: true,
+		},
+		{
+			report: `BUG: KFENCE: use-after-free read in foo+0x10/0x20
+
+Use-after-free read at 0xffff88807d8f2000 (in kfence-#42):`,
+			noHashPointers: true,
+			addr:           0xffff88807d8f2000,
+		},
+		{
+			report: `WARNING: CPU: 0 PID: 1 at foo+0x10/0x20`,
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.addr, extractAccessAddr([]byte(test.report), test.noHashPointers), "test #%v", i)
+	}
+}
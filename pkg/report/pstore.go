@@ -0,0 +1,83 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PstoreRecord is a single file from /sys/fs/pstore (e.g. dmesg-ramoops-0 or dmesg-efi-165123456701001).
+type PstoreRecord struct {
+	Name string
+	Data []byte
+}
+
+// dmesg records start with a header like "Panic#1 Part1" or "Oops#2 Part3".
+var pstoreDmesgHeader = regexp.MustCompile(`^([A-Za-z]+)#([0-9]+) Part([0-9]+)\n`)
+
+// ExtractPstore reconstructs kernel log of the previous boot from pstore records.
+// Console records are used as is, dmesg records are split by the kernel into several parts
+// (Part1 being the most recent one) that are glued back in the chronological order.
+// Compressed records that the kernel failed to decompress are ignored.
+func ExtractPstore(records []PstoreRecord) []byte {
+	type dmesgPart struct {
+		event int
+		part  int
+		data  []byte
+	}
+	var console [][]byte
+	var parts []dmesgPart
+	for _, rec := range records {
+		switch {
+		case strings.HasSuffix(rec.Name, ".enc.z"):
+		case strings.HasPrefix(rec.Name, "console-"):
+			console = append(console, rec.Data)
+		case strings.HasPrefix(rec.Name, "dmesg-"):
+			match := pstoreDmesgHeader.FindSubmatch(rec.Data)
+			if match == nil {
+				parts = append(parts, dmesgPart{data: rec.Data})
+				continue
+			}
+			event, _ := strconv.Atoi(string(match[2]))
+			part, _ := strconv.Atoi(string(match[3]))
+			parts = append(parts, dmesgPart{event, part, rec.Data[len(match[0]):]})
+		}
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		if parts[i].event != parts[j].event {
+			return parts[i].event < parts[j].event
+		}
+		return parts[i].part > parts[j].part
+	})
+	buf := new(bytes.Buffer)
+	for _, data := range console {
+		buf.Write(data)
+		if len(data) != 0 && data[len(data)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	for _, part := range parts {
+		buf.Write(part.data)
+	}
+	return buf.Bytes()
+}
+
+// ParseRecovered is used for reports that don't contain an actual kernel oops
+// (e.g. "lost connection to test machine"). If the output recovered after the machine reboot
+// (e.g. with ExtractPstore) contains an oops, it returns a new report for that oops.
+// Recovered output must be appended to the original output at recoveredPos.
+// Otherwise, it returns the original report.
+func (reporter *Reporter) ParseRecovered(rep *Report, recoveredPos int) *Report {
+	if len(rep.Report) != 0 || recoveredPos >= len(rep.Output) {
+		return rep
+	}
+	if recovered := reporter.ParseFrom(rep.Output, recoveredPos); recovered != nil {
+		return recovered
+	}
+	return rep
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPstore(t *testing.T) {
+	records := []PstoreRecord{
+		{Name: "dmesg-ramoops-1", Data: []byte("Panic#2 Part1\nkernel BUG at mm/foo.c:1!\nend of event 2\n")},
+		{Name: "dmesg-ramoops-0", Data: []byte("Oops#1 Part1\nthe latest line of event 1\n")},
+		{Name: "dmesg-efi-165000000002001", Data: []byte("Oops#1 Part2\nthe first line of event 1\n")},
+		{Name: "dmesg-efi-165000000003001.enc.z", Data: []byte("\x78\x9c garbage")},
+		{Name: "console-ramoops-0", Data: []byte("console line 1\nconsole line 2")},
+		{Name: "pmsg-ramoops-0", Data: []byte("user space message\n")},
+	}
+	assert.Equal(t, "console line 1\nconsole line 2\n"+
+		"the first line of event 1\n"+
+		"the latest line of event 1\n"+
+		"kernel BUG at mm/foo.c:1!\nend of event 2\n",
+		string(ExtractPstore(records)))
+	assert.Empty(t, ExtractPstore(nil))
+}
+
+func TestParseRecovered(t *testing.T) {
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.AMD64,
+			SysTarget:  targets.Get(targets.Linux, targets.AMD64),
+		},
+	}
+	reporter, err := NewReporter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	console := []byte("[   10.000000] some console output\n")
+	pstore := []byte("[   11.000000] BUG: unable to handle page fault for address: ffff888000000000\n")
+
+	lost := &Report{
+		Title:  "lost connection to test machine",
+		Output: append(append([]byte{}, console...), pstore...),
+	}
+	rep := reporter.ParseRecovered(lost, len(console))
+	assert.Equal(t, "BUG: unable to handle kernel paging request in corrupted", rep.Title)
+	assert.GreaterOrEqual(t, rep.StartPos, len(console))
+
+	// No oops in the recovered output.
+	lost.Output = append(append([]byte{}, console...), "nothing interesting\n"...)
+	assert.Equal(t, lost, reporter.ParseRecovered(lost, len(console)))
+
+	// The report already has an oops.
+	real := reporter.Parse(lost.Output[:len(console)])
+	assert.Nil(t, real)
+	crashed := &Report{Title: "WARNING in foo", Report: []byte("WARNING in foo"), Output: lost.Output}
+	assert.Equal(t, crashed, reporter.ParseRecovered(crashed, len(console)))
+}
@@ -34,6 +34,11 @@ type Reporter struct {
 	impl         reporterImpl
 	suppressions []*regexp.Regexp
 	interests    []*regexp.Regexp
+	// Kernel source repo used to find recent commits touching the guilty file (optional).
+	history        vcs.FileHistory
+	historyCommits int
+	// The kernel is booted with no_hash_pointers, so pointers printed with %p are real.
+	noHashPointers bool
 }
 
 type Report struct {
@@ -44,6 +49,10 @@ type Report struct {
 	AltTitles []string
 	// Bug type (e.g. hang, memory leak, etc).
 	Type crash.Type
+	// Estimated security impact of the bug (see EstimateSeverity).
+	Severity crash.Severity
+	// The address of the bad memory access (if the report contains it and it's not hashed).
+	AccessAddr uint64
 	// The indicative function name.
 	Frame string
 	// Report contains whole oops text.
@@ -55,6 +64,12 @@ type Report struct {
 	EndPos   int
 	// SkipPos is position in output where parsing for the next report should start.
 	SkipPos int
+	// Secondary contains other distinct reports found in the same output
+	// in the order of appearance (filled in by ParseMulti).
+	Secondary []*Report
+	// Index is the position of the report in the output among the reports extracted by ParseMulti
+	// (0 for the first one, 1 for the second one, etc).
+	Index int
 	// Suppressed indicates whether the report should not be reported to user.
 	Suppressed bool
 	// Corrupted indicates whether the report is truncated of corrupted in some other way.
@@ -65,6 +80,9 @@ type Report struct {
 	Recipients vcs.Recipients
 	// GuiltyFile is the source file that we think is to blame for the crash  (filled in by Symbolize).
 	GuiltyFile string
+	// GuiltyCommits are the most recent commits that touched GuiltyFile
+	// (filled in by Symbolize if Experimental.GuiltyCommits is set in the manager config).
+	GuiltyCommits []*vcs.Commit
 	// Arbitrary information about the test VM, may be attached to the report by users of the package.
 	MachineInfo []byte
 	// reportPrefixLen is length of additional prefix lines that we added before actual crash report.
@@ -125,10 +143,23 @@ func NewReporter(cfg *mgrconfig.Config) (*Reporter, error) {
 		return nil, err
 	}
 	reporter := &Reporter{
-		typ:          typ,
-		impl:         rep,
-		suppressions: supps,
-		interests:    interests,
+		typ:            typ,
+		impl:           rep,
+		suppressions:   supps,
+		interests:      interests,
+		noHashPointers: cfg.NoHashPointers,
+	}
+	if n := cfg.Experimental.GuiltyCommits; n > 0 && cfg.KernelSrc != "" {
+		repo, err := vcs.NewRepo(cfg.TargetOS, cfg.Type, cfg.KernelSrc, vcs.OptPrecious, vcs.OptDontSandbox)
+		if err != nil {
+			return nil, err
+		}
+		history, ok := repo.(vcs.FileHistory)
+		if !ok {
+			return nil, fmt.Errorf("guilty_commits: %v repos don't support file history", cfg.TargetOS)
+		}
+		reporter.history = history
+		reporter.historyCommits = n
 	}
 	return reporter, nil
 }
@@ -195,6 +226,8 @@ func (reporter *Reporter) ParseFrom(output []byte, minReportPos int) *Report {
 	if match := reportFrameRe.FindStringSubmatch(rep.Title); match != nil {
 		rep.Frame = match[1]
 	}
+	rep.Severity = EstimateSeverity(rep.Title, rep.Type, rep.Report)
+	rep.AccessAddr = extractAccessAddr(rep.Report, reporter.noHashPointers)
 	rep.SkipPos = len(output)
 	if pos := bytes.IndexByte(rep.Output[rep.StartPos:], '\n'); pos != -1 {
 		rep.SkipPos = rep.StartPos + pos
@@ -222,6 +255,19 @@ func (reporter *Reporter) Symbolize(rep *Report) error {
 	if !reporter.isInteresting(rep) {
 		rep.Suppressed = true
 	}
+	if reporter.history != nil && rep.GuiltyFile != "" && !rep.Suppressed {
+		commits, err := reporter.history.RecentFileCommits(rep.GuiltyFile, reporter.historyCommits)
+		if err != nil {
+			return fmt.Errorf("failed to query commits touching %v: %w", rep.GuiltyFile, err)
+		}
+		rep.GuiltyCommits = commits
+	}
+	for _, secondary := range rep.Secondary {
+		secondary.symbolized = true
+		if err := reporter.impl.Symbolize(secondary); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -323,6 +369,12 @@ var dynamicTitleReplacement = []replacement{
 		regexp.MustCompile(`syzkaller[0-9]+((/|:)[0-9]+)?`),
 		"syzkaller",
 	},
+	{
+		// Pointers printed with %p before the hashing key is ready
+		// (real and hashed pointers are replaced below).
+		regexp.MustCompile(`(?:0x)?\(_*ptrval_*\)`),
+		"ADDR",
+	},
 	{
 		// Replace that everything looks like an address with "ADDR",
 		// addresses in descriptions can't be good regardless of the oops regexps.
@@ -20,6 +20,7 @@ import (
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/pkg/vcs"
 	"github.com/google/syzkaller/sys/targets"
 	"github.com/stretchr/testify/assert"
 )
@@ -298,6 +299,36 @@ func testGuiltyFile(t *testing.T, reporter *Reporter, fn string) {
 	}
 }
 
+type testFileHistory struct {
+	files []string
+}
+
+func (h *testFileHistory) RecentFileCommits(file string, n int) ([]*vcs.Commit, error) {
+	h.files = append(h.files, file)
+	var commits []*vcs.Commit
+	for i := 0; i < n; i++ {
+		commits = append(commits, &vcs.Commit{Title: fmt.Sprintf("%v change %v", file, i)})
+	}
+	return commits, nil
+}
+
+func TestGuiltyCommits(t *testing.T) {
+	reporter, _ := prepareLinuxReporter(t, targets.AMD64)
+	history := new(testFileHistory)
+	reporter.history = history
+	reporter.historyCommits = 2
+	_, report := parseGuiltyTest(t, filepath.Join("testdata", targets.Linux, "guilty", "0"))
+	rep := reporter.Parse(report)
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"net/ipv6/ip6_output.c"}, history.files)
+	assert.Equal(t, []*vcs.Commit{
+		{Title: "net/ipv6/ip6_output.c change 0"},
+		{Title: "net/ipv6/ip6_output.c change 1"},
+	}, rep.GuiltyCommits)
+}
+
 func TestRawGuiltyFile(t *testing.T) {
 	forEachFile(t, "guilty_raw", testRawGuiltyFile)
 }
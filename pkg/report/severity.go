@@ -0,0 +1,110 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/report/crash"
+)
+
+// Memory corruptions that write more than that many bytes at once (memcpy-like),
+// or this far from the object bounds likely have attacker-controlled size or offset.
+const (
+	controlledWriteSize   = 8
+	controlledWriteOffset = 64
+)
+
+var (
+	memAccessTitleRe = regexp.MustCompile(`^(?:KASAN|KFENCE): ([a-z-]+)(?: (Read|Write))?`)
+	accessSizeRe     = regexp.MustCompile(`(Read|Write) of size (\d+) at addr`)
+	kfenceAccessRe   = regexp.MustCompile(`(?:Out-of-bounds|Use-after-free) (read|write) at`)
+	accessOffsetRe   = regexp.MustCompile(`The buggy address is located (\d+) bytes to the (?:right|left) of`)
+)
+
+// Crashes that are memory corruptions regardless of the access details.
+var corruptionTitles = []string{
+	"KASAN: double-free",
+	"KASAN: invalid-free",
+	"KFENCE: memory corruption",
+	"KFENCE: invalid free",
+	"BUG: corrupted list",
+	"kernel panic: corrupted stack end",
+	"kernel panic: stack is corrupted",
+	"WARNING: refcount bug",
+}
+
+// Crashes that are (most likely) NULL pointer dereferences.
+var nullDerefTitles = []string{
+	"BUG: unable to handle kernel NULL pointer dereference",
+	"KASAN: null-ptr-deref",
+}
+
+// EstimateSeverity classifies a crash by its likely security impact based on the title
+// and the report type, and for memory safety bugs on the access details in the report.
+// The heuristics are tuned for Linux titles and KASAN/KFENCE reports.
+func EstimateSeverity(title string, typ crash.Type, report []byte) crash.Severity {
+	for _, prefix := range corruptionTitles {
+		if strings.HasPrefix(title, prefix) {
+			return crash.HighSeverity
+		}
+	}
+	for _, prefix := range nullDerefTitles {
+		if strings.HasPrefix(title, prefix) {
+			return crash.LowSeverity
+		}
+	}
+	if match := memAccessTitleRe.FindStringSubmatch(title); match != nil {
+		return memAccessSeverity(match[1], match[2], report)
+	}
+	switch {
+	case strings.HasPrefix(title, "general protection fault"):
+		// With KASAN GPFs on NULL derefs are accompanied by a note on the memory range.
+		if strings.Contains(string(report), "KASAN: null-ptr-deref") {
+			return crash.LowSeverity
+		}
+		return crash.MediumSeverity
+	case strings.HasPrefix(title, "BUG: unable to handle kernel paging request"),
+		strings.HasPrefix(title, "BUG: bad usercopy"),
+		strings.HasPrefix(title, "KMSAN: kernel-infoleak"),
+		strings.HasPrefix(title, "UBSAN: array-index-out-of-bounds"):
+		return crash.MediumSeverity
+	}
+	switch typ {
+	case crash.UnknownType, crash.SyzFailure:
+		return crash.UnknownSeverity
+	case crash.KASAN, crash.MTE, crash.PAC:
+		return crash.MediumSeverity
+	}
+	return crash.LowSeverity
+}
+
+func memAccessSeverity(kind, access string, report []byte) crash.Severity {
+	size := 0
+	if match := accessSizeRe.FindSubmatch(report); match != nil {
+		// Old KASAN titles don't contain the access type.
+		if access == "" {
+			access = string(match[1])
+		}
+		size, _ = strconv.Atoi(string(match[2]))
+	}
+	if match := kfenceAccessRe.FindSubmatch(report); match != nil && access == "" &&
+		string(match[1]) == "write" {
+		access = "Write"
+	}
+	if access != "Write" {
+		return crash.MediumSeverity
+	}
+	if kind == "wild-memory-access" || kind == "user-memory-access" || size > controlledWriteSize {
+		return crash.CriticalSeverity
+	}
+	if match := accessOffsetRe.FindSubmatch(report); match != nil {
+		if offset, err := strconv.Atoi(string(match[1])); err == nil && offset >= controlledWriteOffset {
+			return crash.CriticalSeverity
+		}
+	}
+	return crash.HighSeverity
+}
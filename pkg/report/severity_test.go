@@ -0,0 +1,114 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSeverity(t *testing.T) {
+	tests := []struct {
+		title    string
+		typ      crash.Type
+		report   string
+		severity crash.Severity
+	}{
+		{
+			title:    "WARNING in foo",
+			typ:      crash.Warning,
+			severity: crash.LowSeverity,
+		},
+		{
+			title:    "INFO: task hung in foo",
+			typ:      crash.Hang,
+			severity: crash.LowSeverity,
+		},
+		{
+			title:    "BUG: unable to handle kernel NULL pointer dereference in foo",
+			severity: crash.LowSeverity,
+		},
+		{
+			title:    "general protection fault in foo",
+			report:   "KASAN: null-ptr-deref in range [0x0000000000000010-0x0000000000000017]",
+			severity: crash.LowSeverity,
+		},
+		{
+			title:    "general protection fault in foo",
+			report:   "KASAN: maybe wild-memory-access in range [0xdead000000000108-0xdead00000000010f]",
+			severity: crash.MediumSeverity,
+		},
+		{
+			title:    "KASAN: use-after-free Read in foo",
+			typ:      crash.KASAN,
+			report:   "Read of size 8 at addr ffff888012345678 by task syz-executor/1",
+			severity: crash.MediumSeverity,
+		},
+		{
+			title: "KASAN: slab-out-of-bounds Write in foo",
+			typ:   crash.KASAN,
+			report: `Write of size 4 at addr ffff888012345678 by task syz-executor/1
+The buggy address is located 0 bytes to the right of
+ 64-byte region [ffff888012345600, ffff888012345640)`,
+			severity: crash.HighSeverity,
+		},
+		{
+			title: "KASAN: slab-out-of-bounds Write in foo",
+			typ:   crash.KASAN,
+			report: `Write of size 4 at addr ffff888012345678 by task syz-executor/1
+The buggy address is located 256 bytes to the right of
+ 64-byte region [ffff888012345600, ffff888012345640)`,
+			severity: crash.CriticalSeverity,
+		},
+		{
+			title:    "KASAN: use-after-free Write in foo",
+			typ:      crash.KASAN,
+			report:   "[  771.751900][T15335] Write of size 128 at addr ffffc90002f7f910 by task kworker/0:1/15335",
+			severity: crash.CriticalSeverity,
+		},
+		{
+			// Old KASAN titles don't contain the access type.
+			title:    "KASAN: slab-out-of-bounds in foo at addr ADDR",
+			typ:      crash.KASAN,
+			report:   "Write of size 1 at addr ffff88006b2ca2a0 by task syz-executor/1",
+			severity: crash.HighSeverity,
+		},
+		{
+			title:    "KFENCE: out-of-bounds in foo",
+			report:   "BUG: KFENCE: out-of-bounds write in foo\n\nOut-of-bounds write at 0xffff8c3f2e291fff (1B left of kfence-#72):",
+			severity: crash.HighSeverity,
+		},
+		{
+			title:    "KASAN: double-free in foo",
+			typ:      crash.KASAN,
+			severity: crash.HighSeverity,
+		},
+		{
+			title:    "KMSAN: kernel-infoleak in foo",
+			typ:      crash.KMSAN,
+			severity: crash.MediumSeverity,
+		},
+		{
+			title:    "SYZFAIL: failed to recv rpc",
+			typ:      crash.SyzFailure,
+			severity: crash.UnknownSeverity,
+		},
+	}
+	for _, test := range tests {
+		got := EstimateSeverity(test.title, test.typ, []byte(test.report))
+		assert.Equal(t, test.severity, got, "title: %v", test.title)
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	for s := crash.UnknownSeverity; s <= crash.CriticalSeverity; s++ {
+		parsed, ok := crash.ParseSeverity(s.String())
+		assert.True(t, ok)
+		assert.Equal(t, s, parsed)
+	}
+	_, ok := crash.ParseSeverity("catastrophic")
+	assert.False(t, ok)
+}
@@ -32,6 +32,43 @@ type Result struct {
 	// Information about the final (non-symbolized) crash that we reproduced.
 	// Can be different from what we started reproducing.
 	Report *report.Report
+	// How reliably the final reproducer triggers the crash.
+	Reliability Reliability
+}
+
+// After a reproducer is found, it's rerun ReliabilityRuns times to estimate its hit rate.
+const ReliabilityRuns = 5
+
+// Reproducers that crash in less than MinReliability of reruns are considered flaky.
+const MinReliability = 0.6
+
+// Reliability is the number of reruns of a reproducer and how many of them crashed.
+type Reliability struct {
+	Runs    int
+	Crashes int
+}
+
+// Score returns the hit rate of the reproducer (1 if it was not measured).
+func (rel Reliability) Score() float64 {
+	if rel.Runs == 0 {
+		return 1
+	}
+	return float64(rel.Crashes) / float64(rel.Runs)
+}
+
+func (rel Reliability) Flaky() bool {
+	return rel.Score() < MinReliability
+}
+
+func (rel Reliability) String() string {
+	if rel.Runs == 0 {
+		return "not measured"
+	}
+	flaky := ""
+	if rel.Flaky() {
+		flaky = ", flaky"
+	}
+	return fmt.Sprintf("%v/%v%v", rel.Crashes, rel.Runs, flaky)
 }
 
 type Stats struct {
@@ -41,6 +78,8 @@ type Stats struct {
 	SimplifyProgTime time.Duration
 	ExtractCTime     time.Duration
 	SimplifyCTime    time.Duration
+	MinimizeCTime    time.Duration
+	ReliabilityTime  time.Duration
 }
 
 type reproInstance struct {
@@ -75,6 +114,9 @@ type execInterface interface {
 
 var ErrNoPrograms = errors.New("crash log does not contain any programs")
 
+// reproLog is the logger of reproduction, its verbosity can be set separately (e.g. with -vmodule=repro=2).
+var reproLog = log.Module("repro")
+
 func Run(crashLog []byte, cfg *mgrconfig.Config, features flatrpc.Feature, reporter *report.Reporter,
 	vmPool *vm.Pool, vmIndexes []int) (*Result, *Stats, error) {
 	ctx, err := prepareCtx(crashLog, cfg, features, reporter, len(vmIndexes))
@@ -160,6 +202,7 @@ func (ctx *context) run() (*Result, *Stats, error) {
 	if res != nil {
 		ctx.reproLogf(3, "repro crashed as (corrupted=%v):\n%s",
 			ctx.report.Corrupted, ctx.report.Report)
+		res.Reliability = ctx.measureReliability(res)
 		// Try to rerun the repro if the report is corrupted.
 		for attempts := 0; ctx.report.Corrupted && attempts < 3; attempts++ {
 			ctx.reproLogf(3, "report is corrupted, running repro again")
@@ -179,6 +222,40 @@ func (ctx *context) run() (*Result, *Stats, error) {
 	return res, ctx.stats, nil
 }
 
+// measureReliability reruns the final reproducer to estimate how reliably it triggers the crash.
+// VM errors are not counted as runs.
+func (ctx *context) measureReliability(res *Result) Reliability {
+	defer func(start time.Time) {
+		ctx.stats.ReliabilityTime = time.Since(start)
+	}(time.Now())
+	// Reruns overwrite ctx.report, but we want to keep a non-corrupted report if we have one.
+	report := ctx.report
+	var rel Reliability
+	for i := 0; i < ReliabilityRuns; i++ {
+		var crashed bool
+		var err error
+		if res.CRepro {
+			crashed, err = ctx.testCProg(res.Prog, res.Duration, res.Opts)
+		} else {
+			crashed, err = ctx.testProg(res.Prog, res.Duration, res.Opts)
+		}
+		if err != nil {
+			ctx.reproLogf(2, "reliability run failed: %v", err)
+			continue
+		}
+		rel.Runs++
+		if crashed {
+			rel.Crashes++
+			if report.Corrupted && !ctx.report.Corrupted {
+				report = ctx.report
+			}
+		}
+	}
+	ctx.report = report
+	ctx.reproLogf(2, "reproducer reliability: %v", rel)
+	return rel
+}
+
 func createStartOptions(cfg *mgrconfig.Config, features flatrpc.Feature,
 	crashType crash.Type) csource.Options {
 	opts := csource.DefaultOpts(cfg)
@@ -212,6 +289,11 @@ func createStartOptions(cfg *mgrconfig.Config, features flatrpc.Feature,
 	if features&flatrpc.FeatureSwap == 0 {
 		opts.Swap = false
 	}
+	// Hugepages are not in the default options, they are enabled only if the manager config opted in.
+	opts.Hugepages = features&flatrpc.FeatureHugepages != 0
+	if features&flatrpc.FeaturePmem == 0 {
+		opts.Pmem = false
+	}
 	return opts
 }
 
@@ -261,6 +343,10 @@ func (ctx *context) repro() (*Result, error) {
 		if err != nil {
 			return nil, err
 		}
+		res, err = ctx.minimizeC(res)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return res, nil
@@ -522,7 +608,7 @@ func (ctx *context) simplifyC(res *Result) (*Result, error) {
 	ctx.reproLogf(2, "simplifying C reproducer")
 	start := time.Now()
 	defer func() {
-		ctx.stats.SimplifyCTime = time.Since(start)
+		ctx.stats.SimplifyCTime += time.Since(start)
 	}()
 
 	for _, simplify := range cSimplifies {
@@ -542,6 +628,34 @@ func (ctx *context) simplifyC(res *Result) (*Result, error) {
 	return res, nil
 }
 
+// Remove calls that are not needed for the C reproducer.
+// The program was minimized with syz-executor, but the C reproducer may not need some of the calls
+// (e.g. the ones that only help to trigger the bug in the fuzzing setup). Once calls are removed,
+// setup features (tun, devlink, cgroups, etc) and the loop may become unnecessary, so options
+// are simplified once again.
+func (ctx *context) minimizeC(res *Result) (*Result, error) {
+	ctx.reproLogf(2, "minimizing C reproducer")
+	start := time.Now()
+	removed := false
+	for i := len(res.Prog.Calls) - 1; i >= 0 && len(res.Prog.Calls) > 1; i-- {
+		p := res.Prog.Clone()
+		p.RemoveCall(i)
+		crashed, err := ctx.testCProg(p, res.Duration, res.Opts)
+		if err != nil {
+			return nil, err
+		}
+		if crashed {
+			res.Prog = p
+			removed = true
+		}
+	}
+	ctx.stats.MinimizeCTime = time.Since(start)
+	if !removed {
+		return res, nil
+	}
+	return ctx.simplifyC(res)
+}
+
 func checkOpts(opts *csource.Options, timeouts targets.Timeouts, timeout time.Duration) bool {
 	if !opts.Repeat && timeout >= time.Minute {
 		// If we have a non-repeating C reproducer with timeout > vm.NoOutputTimeout and it hangs
@@ -669,7 +783,7 @@ func (ctx *context) reproLogf(level int, format string, args ...interface{}) {
 		ctx.logf(format, args...)
 	}
 	prefix := fmt.Sprintf("reproducing crash '%v': ", ctx.crashTitle)
-	log.Logf(level, prefix+format, args...)
+	reproLog.Logf(level, prefix+format, args...)
 	ctx.stats.Log = append(ctx.stats.Log, []byte(fmt.Sprintf(format, args...)+"\n")...)
 }
 
@@ -788,6 +902,8 @@ var cSimplifies = append(progSimplifies, []Simplify{
 		opts.VhciInjection = false
 		opts.Wifi = false
 		opts.Swap = false
+		opts.Hugepages = false
+		opts.Pmem = false
 		return true
 	},
 	func(opts *csource.Options) bool {
@@ -904,4 +1020,18 @@ var cSimplifies = append(progSimplifies, []Simplify{
 		opts.Swap = false
 		return true
 	},
+	func(opts *csource.Options) bool {
+		if !opts.Hugepages {
+			return false
+		}
+		opts.Hugepages = false
+		return true
+	},
+	func(opts *csource.Options) bool {
+		if !opts.Pmem {
+			return false
+		}
+		opts.Pmem = false
+		return true
+	},
 }...)
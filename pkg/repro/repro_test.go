@@ -4,6 +4,7 @@
 package repro
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -128,12 +129,17 @@ type testExecInterface struct {
 	t *testing.T
 	// For now only do the simplest imitation.
 	run func([]byte) (*instance.RunResult, error)
+	// If set, used to run C programs instead of run.
+	runC func([]byte, csource.Options) (*instance.RunResult, error)
 }
 
 func (tei *testExecInterface) Close() {}
 
 func (tei *testExecInterface) RunCProg(p *prog.Prog, duration time.Duration,
 	opts csource.Options) (*instance.RunResult, error) {
+	if tei.runC != nil {
+		return tei.runC(p.Serialize(), opts)
+	}
 	return tei.RunSyzProg(p.Serialize(), duration, opts, instance.SyzExitConditions)
 }
 
@@ -213,6 +219,76 @@ alarm(0xa)
 `, string(result.Prog.Serialize())); diff != "" {
 		t.Fatal(diff)
 	}
+	if want := (Reliability{Runs: ReliabilityRuns, Crashes: ReliabilityRuns}); result.Reliability != want {
+		t.Fatalf("reliability %+v, want %+v", result.Reliability, want)
+	}
+}
+
+func TestMinimizeC(t *testing.T) {
+	ctx := prepareTestCtx(t, testReproLog)
+	go generateTestInstances(ctx, 3, &testExecInterface{
+		t: t,
+		// The C reproducer needs only alarm(0xa), but only if cgroups are set up.
+		runC: func(log []byte, opts csource.Options) (*instance.RunResult, error) {
+			if !opts.Cgroups || !bytes.Contains(log, []byte("alarm(0xa)")) {
+				return &instance.RunResult{}, nil
+			}
+			return &instance.RunResult{Report: &report.Report{Title: "some crash"}}, nil
+		},
+	})
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("getpid()\npause()\nalarm(0xa)\n"), prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &Result{
+		Prog:     p,
+		Duration: 10 * time.Second,
+		Opts: csource.Options{
+			Repeat:       true,
+			Procs:        1,
+			Sandbox:      "none",
+			NetInjection: true,
+			Cgroups:      true,
+			UseTmpDir:    true,
+		},
+		CRepro: true,
+	}
+	res, err = ctx.minimizeC(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("alarm(0xa)\n", string(res.Prog.Serialize())); diff != "" {
+		t.Fatal(diff)
+	}
+	if !res.Opts.Cgroups || res.Opts.NetInjection {
+		t.Fatalf("bad simplified options: %+v", res.Opts)
+	}
+}
+
+func TestReliability(t *testing.T) {
+	tests := []struct {
+		rel   Reliability
+		flaky bool
+		str   string
+	}{
+		{Reliability{}, false, "not measured"},
+		{Reliability{Runs: 5, Crashes: 5}, false, "5/5"},
+		{Reliability{Runs: 5, Crashes: 3}, false, "3/5"},
+		{Reliability{Runs: 5, Crashes: 2}, true, "2/5, flaky"},
+		{Reliability{Runs: 4, Crashes: 0}, true, "0/4, flaky"},
+	}
+	for _, test := range tests {
+		if flaky := test.rel.Flaky(); flaky != test.flaky {
+			t.Errorf("%+v: flaky=%v, want %v", test.rel, flaky, test.flaky)
+		}
+		if str := test.rel.String(); str != test.str {
+			t.Errorf("%+v: %q, want %q", test.rel, str, test.str)
+		}
+	}
 }
 
 // There happen to be transient errors like ssh/scp connection failures.
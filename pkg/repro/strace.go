@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"github.com/google/syzkaller/pkg/instance"
-	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/vm"
@@ -40,10 +39,10 @@ func RunStrace(result *Result, cfg *mgrconfig.Config, reporter *report.Reporter,
 
 	var runRes *instance.RunResult
 	if result.CRepro {
-		log.Logf(1, "running C repro under strace")
+		reproLog.Logf(1, "running C repro under strace")
 		runRes, err = inst.RunCProg(result.Prog, result.Duration, result.Opts)
 	} else {
-		log.Logf(1, "running syz repro under strace")
+		reproLog.Logf(1, "running syz repro under strace")
 		runRes, err = inst.RunSyzProg(result.Prog.Serialize(), result.Duration,
 			result.Opts, instance.SyzExitConditions)
 	}
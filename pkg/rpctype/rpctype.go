@@ -14,6 +14,9 @@ type HubConnectArgs struct {
 	Manager string
 	// See pkg/mgrconfig.Config.HubDomain.
 	Domain string
+	// Version of the fuzzed kernel in the "major.minor" form (empty if unknown).
+	// Used to avoid sending programs that were found only on newer kernels.
+	KernelVersion string
 	// Manager has started with an empty corpus and requests whole hub corpus.
 	Fresh bool
 	// Set of system call names supported by this manager.
@@ -316,6 +316,7 @@ func parseProg(target *prog.Target, dir, filename string) (*prog.Prog, map[strin
 		"ENOMEM":     12,
 		"EACCES":     13,
 		"EFAULT":     14,
+		"EBUSY":      16,
 		"EXDEV":      18,
 		"EINVAL":     22,
 		"ENOTTY":     25,
@@ -461,6 +462,8 @@ func (ctx *Context) createCTest(p *prog.Prog, sandbox string, threaded bool, tim
 		Cgroups:     p.Target.OS == targets.Linux && sandbox != "",
 		Trace:       true,
 		Swap:        ctx.Features&flatrpc.FeatureSwap != 0,
+		Hugepages:   ctx.Features&flatrpc.FeatureHugepages != 0,
+		Pmem:        ctx.Features&flatrpc.FeaturePmem != 0,
 	}
 	if sandbox != "" {
 		if ctx.Features&flatrpc.FeatureNetInjection != 0 {
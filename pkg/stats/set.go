@@ -37,6 +37,8 @@ type UI struct {
 	Level Level
 	Value string
 	V     int
+	// V is the mean value of a distribution (rather than a counter).
+	Mean bool
 }
 
 func Create(name, desc string, opts ...any) *Val {
@@ -55,6 +57,10 @@ func Import(named map[string]uint64) {
 	global.Import(named)
 }
 
+func History() []HistoryLine {
+	return global.History()
+}
+
 var global = newSet(256, true)
 
 type set struct {
@@ -123,6 +129,7 @@ func (s *set) Collect(level Level) []UI {
 			Level: v.level,
 			Value: v.fmt(val, period),
 			V:     val,
+			Mean:  v.hist,
 		})
 	}
 	sort.Slice(res, func(i, j int) bool {
@@ -146,6 +153,43 @@ func (s *set) Import(named map[string]uint64) {
 	}
 }
 
+// HistoryLine is the raw history of a single graphed metric.
+type HistoryLine struct {
+	Graph  string        `json:"graph"`
+	Name   string        `json:"name"`
+	Rate   bool          `json:"rate,omitempty"`
+	Period time.Duration `json:"period"` // time between points
+	Points []float64     `json:"points"`
+}
+
+// History returns history of all graphed metrics (distribution metrics are not included).
+func (s *set) History() []HistoryLine {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []HistoryLine
+	for title, graph := range s.graphs {
+		for name, ln := range graph.lines {
+			if ln.data == nil {
+				continue
+			}
+			res = append(res, HistoryLine{
+				Graph:  title,
+				Name:   name,
+				Rate:   ln.rate,
+				Period: time.Duration(s.historyScale) * tickPeriod,
+				Points: append([]float64{}, ln.data[:s.historyPos]...),
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Graph != res[j].Graph {
+			return res[i].Graph < res[j].Graph
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
 // Additional options for Val metrics.
 
 // Level controls if the metric should be printed to console in periodic heartbeat logs,
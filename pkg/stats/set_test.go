@@ -65,11 +65,11 @@ func TestSet(t *testing.T) {
 
 	ui := set.Collect(All)
 	a.Equal(len(ui), 5)
-	a.Equal(ui[0], UI{"v2", "desc2", "", Console, "v2 100 1s", 100})
-	a.Equal(ui[1], UI{"v1", "desc1", "", Simple, "11", 11})
-	a.Equal(ui[2], UI{"v0", "desc0", "", All, "2", 2})
-	a.Equal(ui[3], UI{"v3", "desc3", "/v3", All, "24", 24})
-	a.Equal(ui[4], UI{"v4", "desc4", "", All, "20 (20/sec)", 20})
+	a.Equal(ui[0], UI{"v2", "desc2", "", Console, "v2 100 1s", 100, false})
+	a.Equal(ui[1], UI{"v1", "desc1", "", Simple, "11", 11, false})
+	a.Equal(ui[2], UI{"v0", "desc0", "", All, "2", 2, false})
+	a.Equal(ui[3], UI{"v3", "desc3", "/v3", All, "24", 24, true})
+	a.Equal(ui[4], UI{"v4", "desc4", "", All, "20 (20/sec)", 20, false})
 
 	ui1 := set.Collect(Simple)
 	a.Equal(len(ui1), 2)
@@ -152,6 +152,25 @@ func TestSetHistoryRate(t *testing.T) {
 	a.Equal(step(4), []float64{2.75, 2.25, 2.5})
 }
 
+func TestSetHistoryExport(t *testing.T) {
+	a := assert.New(t)
+	set := newSet(4, false)
+	v0 := set.Create("v0", "desc0", Rate{}, Graph("g"))
+	v1 := set.Create("v1", "desc1", Graph("g"))
+	set.Create("v2", "desc2", NoGraph).Add(1)
+	set.Create("v3", "desc3", Distribution{}).Add(1)
+	a.Empty(set.History())
+	v0.Add(2)
+	v1.Add(3)
+	set.tick()
+	v0.Add(1)
+	set.tick()
+	a.Equal([]HistoryLine{
+		{Graph: "g", Name: "v0", Rate: true, Period: time.Second, Points: []float64{2, 1}},
+		{Graph: "g", Name: "v1", Period: time.Second, Points: []float64{3, 3}},
+	}, set.History())
+}
+
 func TestSetHistoryDistribution(t *testing.T) {
 	a := assert.New(t)
 	set := newSet(4, false)
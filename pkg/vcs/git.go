@@ -447,6 +447,27 @@ func (git *git) fetchCommits(since, base, user, domain string, greps []string, f
 	return commits, s.Err()
 }
 
+func (git *git) RecentFileCommits(file string, n int) ([]*Commit, error) {
+	const commitSeparator = "---===syzkaller-commit-separator===---"
+	output, err := git.git("log", "--no-merges", "-n", fmt.Sprint(n),
+		"--format=%H%n%s%n%ae%n%an%n%ad%n%P%n%cd%n%b%n"+commitSeparator, "HEAD", "--", file)
+	if err != nil {
+		return nil, err
+	}
+	var commits []*Commit
+	for _, out := range bytes.Split(output, []byte(commitSeparator+"\n")) {
+		if len(out) == 0 {
+			continue
+		}
+		com, err := gitParseCommit(out, nil, nil, git.ignoreCC)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, com)
+	}
+	return commits, nil
+}
+
 func (git *git) git(args ...string) ([]byte, error) {
 	cmd := osutil.Command("git", args...)
 	cmd.Dir = git.dir
@@ -527,6 +548,76 @@ func (git *git) Bisect(bad, good string, dt debugtracer.DebugTracer, pred func()
 	}
 }
 
+func (git *git) NextBisectCommits() ([]string, error) {
+	head, err := git.HeadCommit()
+	if err != nil {
+		return nil, err
+	}
+	output, err := git.git("rev-parse", "refs/bisect/bad")
+	if err != nil {
+		return nil, err
+	}
+	bad := strings.TrimSpace(string(output))
+	// Note: rev-list --bisect implicitly adds refs/bisect/bad and refs/bisect/good-* to the range,
+	// so it can't be used for bad HEAD. --bisect-all does not use the refs, so the good and skipped
+	// commits are passed explicitly. --bisect-all sorts commits with equal distance by hash,
+	// while git bisect takes the last one in the rev-list order, so ties are resolved here.
+	ifGood, err := git.git("rev-list", "--bisect", bad, "--not", head.Hash)
+	if err != nil {
+		return nil, err
+	}
+	output, err = git.git("for-each-ref", "--format=%(refname) %(objectname)", "refs/bisect/")
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"rev-list", "--bisect-all", head.Hash, "--not"}
+	skipped := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		ref, hash, _ := strings.Cut(line, " ")
+		if strings.HasPrefix(ref, "refs/bisect/good-") {
+			args = append(args, hash)
+		} else if strings.HasPrefix(ref, "refs/bisect/skip-") {
+			skipped[hash] = true
+		}
+	}
+	output, err = git.git(args...)
+	if err != nil {
+		return nil, err
+	}
+	best, bestDist := make(map[string]bool), -1
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, rest, _ := strings.Cut(line, " ")
+		dist := 0
+		if _, err := fmt.Sscanf(rest, "(dist=%d)", &dist); err != nil || hash == "" || skipped[hash] {
+			continue
+		}
+		if dist > bestDist {
+			best, bestDist = make(map[string]bool), dist
+		}
+		if dist == bestDist {
+			best[hash] = true
+		}
+	}
+	args[1] = "--topo-order"
+	output, err = git.git(args...)
+	if err != nil {
+		return nil, err
+	}
+	var ifBad string
+	for _, hash := range strings.Fields(string(output)) {
+		if best[hash] {
+			ifBad = hash
+		}
+	}
+	var ret []string
+	for _, next := range []string{ifBad, strings.TrimSpace(string(ifGood))} {
+		if next != "" && next != head.Hash && next != bad {
+			ret = append(ret, next)
+		}
+	}
+	return ret, nil
+}
+
 var gitFullHashRe = regexp.MustCompile("[a-f0-9]{40}")
 
 func (git *git) bisectInconclusive(output []byte) ([]*Commit, error) {
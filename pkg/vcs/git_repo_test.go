@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -165,6 +166,30 @@ func TestMetadata(t *testing.T) {
 	}
 }
 
+func TestRecentFileCommits(t *testing.T) {
+	t.Parallel()
+	repo := MakeTestRepo(t, t.TempDir())
+	repo.CommitFileChange("master", "0")
+	repo.CommitFileChange("master", "1")
+	repo.CommitChange("unrelated")
+	repo.CommitFileChange("master", "2")
+	commits, err := repo.repo.RecentFileCommits("file", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Commit{repo.Commits["master"]["2"], repo.Commits["master"]["1"]}
+	if diff := cmp.Diff(want, commits); diff != "" {
+		t.Fatal(diff)
+	}
+	commits, err = repo.repo.RecentFileCommits("nonexistent", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 0 {
+		t.Fatalf("got commits for a nonexistent file: %+v", commits)
+	}
+}
+
 func checkCommit(t *testing.T, idx int, test testCommit, com *Commit, checkTags bool) {
 	if !checkTags {
 		return
@@ -401,3 +426,59 @@ func TestBisect(t *testing.T) {
 		}
 	}
 }
+
+func TestNextBisectCommits(t *testing.T) {
+	t.Parallel()
+	repoDir := t.TempDir()
+	repo := MakeTestRepo(t, repoDir)
+	var commits []string
+	for i := 0; i < 20; i++ {
+		repo.CommitChange(fmt.Sprintf("commit %v", i))
+		com, err := repo.repo.HeadCommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, com.Hash)
+	}
+	for _, firstBad := range []int{1, 7, 12, 19} {
+		var predicted []string
+		steps := 0
+		pred := func() (BisectResult, error) {
+			current, err := repo.repo.HeadCommit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if steps != 0 {
+				assert.Contains(t, predicted, current.Hash)
+			}
+			steps++
+			bad, err := repo.repo.git("rev-parse", "refs/bisect/bad")
+			if err != nil {
+				t.Fatal(err)
+			}
+			predicted, err = repo.repo.NextBisectCommits()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The bisection refs must not be touched.
+			badAfter, err := repo.repo.git("rev-parse", "refs/bisect/bad")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, bad, badAfter)
+			for i, hash := range commits {
+				if hash == current.Hash && i >= firstBad {
+					return BisectBad, nil
+				}
+			}
+			return BisectGood, nil
+		}
+		result, err := repo.repo.Bisect(commits[19], commits[0], &debugtracer.TestTracer{T: t}, pred)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, result, 1)
+		assert.Equal(t, commits[firstBad], result[0].Hash)
+		assert.Greater(t, steps, 1)
+	}
+}
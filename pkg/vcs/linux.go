@@ -29,6 +29,7 @@ type linux struct {
 
 var (
 	_ Bisecter        = new(linux)
+	_ BisectPredictor = new(linux)
 	_ ConfigMinimizer = new(linux)
 )
 
@@ -95,6 +95,22 @@ type Bisecter interface {
 		kernelConfig []byte, backports []BackportCommit) (*BisectEnv, error)
 }
 
+// BisectPredictor may be optionally implemented by Bisecter.
+type BisectPredictor interface {
+	// NextBisectCommits returns the commits that an ongoing Bisect would test next
+	// if the current commit turns out to be bad or good (the current commit is not included).
+	// Can only be called from the Bisect predicate. Skipped commits are not taken into account,
+	// so the prediction is not guaranteed to be right.
+	NextBisectCommits() ([]string, error)
+}
+
+// FileHistory may be optionally implemented by Repo.
+type FileHistory interface {
+	// RecentFileCommits returns up to n most recent non-merge commits reachable from HEAD
+	// that touched the file (path relative to the repo root), newest first.
+	RecentFileCommits(file string, n int) ([]*Commit, error)
+}
+
 type ConfigMinimizer interface {
 	Minimize(target *targets.Target, original, baseline []byte, types []crash.Type,
 		dt debugtracer.DebugTracer, pred func(test []byte) (BisectResult, error)) ([]byte, error)
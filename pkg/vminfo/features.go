@@ -160,6 +160,11 @@ func (ctx *checkContext) featureToFlags(feat flatrpc.Feature) (flatrpc.ExecEnv,
 	case flatrpc.FeatureLRWPANEmulation:
 	case flatrpc.FeatureBinFmtMisc:
 	case flatrpc.FeatureSwap:
+	case flatrpc.FeatureHugepages:
+	case flatrpc.FeaturePmem:
+	case flatrpc.FeatureIOUring:
+	case flatrpc.FeatureSuspend:
+	case flatrpc.FeatureCompatSyscalls:
 	default:
 		panic(fmt.Sprintf("unknown feature %v", flatrpc.EnumNamesFeature[feat]))
 	}
@@ -202,3 +207,41 @@ func (ctx *checkContext) featureSucceeded(feat flatrpc.Feature, testProg *prog.P
 	}
 	return ""
 }
+
+// featureCalls returns whether the syscall uses the machine setup done by the feature.
+// Setup of such features is not free (e.g. the hugetlb pool takes memory from the fuzzer),
+// so they are disabled if none of the corresponding syscalls are enabled.
+var featureCalls = map[flatrpc.Feature]func(call *prog.Syscall) bool{
+	flatrpc.FeatureHugepages: func(call *prog.Syscall) bool {
+		switch call.CallName {
+		case "mmap", "mremap", "madvise", "process_madvise", "memfd_create", "shmget", "mount":
+			return true
+		}
+		return false
+	},
+	flatrpc.FeaturePmem: func(call *prog.Syscall) bool {
+		return strings.HasPrefix(call.Name, "openat$pmem")
+	},
+}
+
+// DisableUnused disables features that are useless with the set of enabled syscalls.
+func (features Features) DisableUnused(calls map[*prog.Syscall]bool) {
+	for feat, used := range featureCalls {
+		info, ok := features[feat]
+		if !ok || !info.Enabled {
+			continue
+		}
+		enabled := false
+		for call := range calls {
+			if used(call) {
+				enabled = true
+				break
+			}
+		}
+		if !enabled {
+			info.Enabled = false
+			info.Reason = "disabled: no enabled syscalls use it"
+			features[feat] = info
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vminfo
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisableUnused(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	makeFeatures := func() Features {
+		return Features{
+			flatrpc.FeatureHugepages: {Enabled: true, NeedSetup: true, Reason: "enabled"},
+			flatrpc.FeaturePmem:      {Enabled: true, NeedSetup: true, Reason: "enabled"},
+			flatrpc.FeatureSwap:      {Enabled: true, NeedSetup: true, Reason: "enabled"},
+		}
+	}
+	calls := func(names ...string) map[*prog.Syscall]bool {
+		res := make(map[*prog.Syscall]bool)
+		for _, name := range names {
+			res[target.SyscallMap[name]] = true
+		}
+		return res
+	}
+
+	features := makeFeatures()
+	features.DisableUnused(calls("mmap", "openat$pmem0"))
+	assert.Equal(t, makeFeatures(), features)
+
+	features = makeFeatures()
+	features.DisableUnused(calls("read", "openat$pmem0"))
+	assert.Equal(t, flatrpc.FeaturePmem|flatrpc.FeatureSwap, features.Enabled())
+	assert.NotEqual(t, "enabled", features[flatrpc.FeatureHugepages].Reason)
+
+	features = makeFeatures()
+	features.DisableUnused(calls("memfd_create"))
+	assert.Equal(t, flatrpc.FeatureHugepages|flatrpc.FeatureSwap, features.Enabled())
+}
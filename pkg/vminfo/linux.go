@@ -33,6 +33,8 @@ func (linux) checkFiles() []string {
 		"/proc/filesystems",
 		"/sys/kernel/security/lsm",
 		"/dev/raw-gadget",
+		"/proc/mounts",
+		"/sys/class/rtc/rtc0/since_epoch",
 	}
 }
 
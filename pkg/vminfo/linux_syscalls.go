@@ -42,7 +42,7 @@ func (linux) syscallCheck(ctx *checkContext, call *prog.Syscall) string {
 }
 
 func linuxSupportedLSM(ctx *checkContext, call *prog.Syscall) string {
-	for _, lsm := range []string{"selinux", "apparmor", "smack"} {
+	for _, lsm := range []string{"selinux", "apparmor", "smack", "landlock"} {
 		if !strings.Contains(strings.ToLower(call.Name), lsm) {
 			continue
 		}
@@ -82,6 +82,7 @@ var linuxSyscallChecks = map[string]func(*checkContext, *prog.Syscall) string{
 	"syz_kvm_setup_cpu":           linuxSyzKvmSetupCPUSupported,
 	"syz_emit_vhci":               linuxVhciInjectionSupported,
 	"syz_init_net_socket":         linuxSyzInitNetSocketSupported,
+	"syz_emit_external":           linuxSyzEmitExternalSupported,
 	"syz_genetlink_get_family_id": linuxSyzGenetlinkGetFamilyIDSupported,
 	"syz_mount_image":             linuxSyzMountImageSupported,
 	"syz_read_part_table":         linuxSyzReadPartTableSupported,
@@ -99,6 +100,46 @@ var linuxSyscallChecks = map[string]func(*checkContext, *prog.Syscall) string{
 	"syz_pkey_set":                linuxPkeysSupported,
 	"syz_socket_connect_nvme_tcp": linuxSyzSocketConnectNvmeTCPSupported,
 	"syz_pidfd_open":              alwaysSupported,
+	"syz_xen_hypercall":           linuxXenHypercallSupported,
+	"syz_landlock_path":           linuxSyzLandlockPathSupported,
+	"syz_wireguard_pair":          linuxSyzWireguardPairSupported,
+	"syz_binder_peer":             linuxSyzBinderPeerSupported,
+	"syz_tee_open_session":        linuxSyzTeeOpenSessionSupported,
+}
+
+func (linux) probeSyscalls() map[string]bool {
+	return linuxProbeSyscalls
+}
+
+// linuxProbeSyscalls are syscalls that are commonly blocked by seccomp filters, lockdown or sysctls,
+// and fail argument validation (or do nothing) with all-zero arguments. Other syscalls are not probed:
+// even with zero arguments some of them have side effects, e.g. kexec_load unloads the crash kernel,
+// seccomp enables the strict mode, ptrace makes the process traced, and reboot/swapoff/acct
+// take effect with some arguments and must not be executed by the machine check at all.
+var linuxProbeSyscalls = map[string]bool{
+	"add_key":                 true,
+	"bpf":                     true,
+	"clock_adjtime":           true,
+	"delete_module":           true,
+	"fanotify_init":           true,
+	"init_module":             true,
+	"io_uring_setup":          true,
+	"kcmp":                    true,
+	"keyctl":                  true,
+	"landlock_create_ruleset": true,
+	"mount":                   true,
+	"name_to_handle_at":       true,
+	"open_by_handle_at":       true,
+	"perf_event_open":         true,
+	"pidfd_getfd":             true,
+	"process_vm_readv":        true,
+	"quotactl":                true,
+	"request_key":             true,
+	"setns":                   true,
+	"syslog":                  true,
+	"umount2":                 true,
+	"unshare":                 true,
+	"userfaultfd":             true,
 }
 
 func linuxSyzOpenDevSupported(ctx *checkContext, call *prog.Syscall) string {
@@ -163,10 +204,21 @@ func linuxSyzOpenProcfsSupported(ctx *checkContext, call *prog.Syscall) string {
 	return ctx.canOpen("/proc/cmdline")
 }
 
+func linuxSyzLandlockPathSupported(ctx *checkContext, call *prog.Syscall) string {
+	return ctx.supportedSyscalls([]string{"landlock_create_ruleset"})
+}
+
 func linuxCheckUSBEmulation(ctx *checkContext, call *prog.Syscall) string {
 	return ctx.rootCanOpen("/dev/raw-gadget")
 }
 
+func linuxXenHypercallSupported(ctx *checkContext, call *prog.Syscall) string {
+	if reason := ctx.rootCanOpen("/dev/xen/privcmd"); reason != "" {
+		return reason
+	}
+	return ctx.rootCanOpen("/dev/xen/hypercall")
+}
+
 func linuxSyzKvmSetupCPUSupported(ctx *checkContext, call *prog.Syscall) string {
 	switch call.Name {
 	case "syz_kvm_setup_cpu$x86":
@@ -282,6 +334,30 @@ func linuxSyzInitNetSocketSupported(ctx *checkContext, call *prog.Syscall) strin
 	return linuxSupportedSocket(ctx, call)
 }
 
+func linuxSyzEmitExternalSupported(ctx *checkContext, call *prog.Syscall) string {
+	// The target address is passed via env, so we can't check it here.
+	// The calls are disabled by the manager if external_net is not configured.
+	return ctx.onlySandboxNone()
+}
+
+func linuxSyzWireguardPairSupported(ctx *checkContext, call *prog.Syscall) string {
+	if reason := ctx.onlySandboxNone(); reason != "" {
+		return reason
+	}
+	// The call creates wireguard devices, so the wireguard generic netlink family must be present.
+	return ctx.callSucceeds(`syz_genetlink_get_family_id$wireguard(&AUTO='wireguard\x00', 0xffffffffffffffff)`)
+}
+
+func linuxSyzBinderPeerSupported(ctx *checkContext, call *prog.Syscall) string {
+	// The peer needs a binder device with a free context manager slot,
+	// binderfs mounted by the executor provides a private one.
+	return ctx.callSucceeds(`syz_binder_peer(&AUTO='./binderfs/binder0\x00')`)
+}
+
+func linuxSyzTeeOpenSessionSupported(ctx *checkContext, call *prog.Syscall) string {
+	return ctx.canOpen("/dev/tee0")
+}
+
 func linuxBtfVmlinuxSupported(ctx *checkContext, call *prog.Syscall) string {
 	if reason := ctx.onlySandboxNone(); reason != "" {
 		return reason
@@ -317,23 +393,31 @@ func linuxRequireKernel(ctx *checkContext, major, minor int) string {
 var kernelVersionRe = regexp.MustCompile(` ([0-9]+)\.([0-9]+)\.`)
 
 func matchKernelVersion(ver string, x, y int) (bool, bool) {
+	major, minor, ok := parseKernelVersion(ver)
+	if !ok {
+		return false, true
+	}
+	return major*1000+minor >= x*1000+y, false
+}
+
+func parseKernelVersion(ver string) (int, int, bool) {
 	match := kernelVersionRe.FindStringSubmatch(ver)
 	if match == nil {
-		return false, true
+		return 0, 0, false
 	}
 	major, err := strconv.Atoi(match[1])
 	if err != nil {
-		return false, true
+		return 0, 0, false
 	}
 	if major <= 0 || major > 999 {
-		return false, true
+		return 0, 0, false
 	}
 	minor, err := strconv.Atoi(match[2])
 	if err != nil {
-		return false, true
+		return 0, 0, false
 	}
 	if minor <= 0 || minor > 999 {
-		return false, true
+		return 0, 0, false
 	}
-	return major*1000+minor >= x*1000+y, false
+	return major, minor, true
 }
@@ -10,10 +10,15 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
 	"github.com/google/syzkaller/sys/targets"
 	"github.com/stretchr/testify/assert"
 )
@@ -87,6 +92,44 @@ func TestLinuxSyscalls(t *testing.T) {
 			t.Errorf("feature %v is not enabled: %v", flatrpc.EnumNamesFeature[feat], info.Reason)
 		}
 	}
+	if ver := KernelVersion(files); ver != "6.8" {
+		t.Errorf("bad kernel version %q, want 6.8", ver)
+	}
+}
+
+func TestLinuxModules(t *testing.T) {
+	cfg := testConfig(t, targets.Linux, targets.AMD64)
+	checker := New(cfg)
+	files := []*flatrpc.FileInfo{
+		{
+			Name:   "/proc/modules",
+			Exists: true,
+			Data: []byte("nf_tables 352256 0 - Live 0xffffffffa0200000\n" +
+				"late_mod 16384 0 - Live 0xffffffffa0300000\n" +
+				"no_text 8192 0 - Live 0xffffffffa0400000\n"),
+		},
+		{
+			Name:   "/sys/module/nf_tables/sections/.text",
+			Exists: true,
+			Data:   []byte("0xffffffffa0200000\n"),
+		},
+		{
+			Name:   "/sys/module/late_mod/sections/.text",
+			Exists: true,
+			Data:   []byte("0xffffffffa0300000\n"),
+		},
+	}
+	modules, err := checker.Modules(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []cover.KernelModule{
+		{Name: "nf_tables", Addr: 0xffffffffa0200000, Size: 352256},
+		{Name: "late_mod", Addr: 0xffffffffa0300000, Size: 16384},
+	}
+	if diff := cmp.Diff(want, modules); diff != "" {
+		t.Fatal(diff)
+	}
 }
 
 func TestReadKVMInfo(t *testing.T) {
@@ -428,3 +471,92 @@ D                   : d
 `,
 	},
 }
+
+func TestLinuxSyscallsProbe(t *testing.T) {
+	cfg := testConfig(t, targets.Linux, targets.AMD64)
+	cfg.Experimental.ProbeSyscalls = true
+	cfg.Sandbox = "setuid"
+	checker := New(cfg)
+	// These calls are blocked e.g. by seccomp or lockdown.
+	blocked := map[string]bool{
+		"bpf":         true,
+		"init_module": true,
+	}
+	// These calls fail with EPERM only due to missing privileges in the sandbox.
+	privileged := map[string]bool{
+		"mount":  true,
+		"setns":  true,
+		"syslog": true,
+	}
+	// These calls have side effects, so they must not be probed (in particular re-executed as root)
+	// even if they fail with EPERM in the sandbox.
+	dangerous := map[string]bool{
+		"kexec_load": true,
+		"reboot":     true,
+		"swapoff":    true,
+		"seccomp":    true,
+		"ptrace":     true,
+	}
+	var mu sync.Mutex
+	probes := make(map[string]int)
+	executedAsRoot := make(map[string]bool)
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+			}
+			req := checker.Next()
+			if req == nil {
+				continue
+			}
+			root := req.ExecOpts.EnvFlags&flatrpc.ExecEnvSandboxSetuid == 0
+			info := &flatrpc.ProgInfo{}
+			for _, call := range req.Prog.Calls {
+				name := call.Meta.CallName
+				mu.Lock()
+				if root {
+					executedAsRoot[name] = true
+				}
+				if len(req.Prog.Calls) == 1 && !root && (blocked[name] || privileged[name]) {
+					probes[name]++
+				}
+				mu.Unlock()
+				res := &flatrpc.CallInfo{}
+				if blocked[name] || (privileged[name] || dangerous[name]) && !root {
+					res.Error = int32(syscall.EPERM)
+				}
+				info.Calls = append(info.Calls, res)
+			}
+			req.Done(&queue.Result{
+				Status: queue.Success,
+				Info:   info,
+			})
+		}
+	}()
+	enabled, disabled, _, err := checker.Run(nil, allFeatures())
+	close(stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range cfg.Syscalls {
+		call := cfg.Target.Syscalls[id]
+		if blocked[call.CallName] {
+			assert.False(t, enabled[call], call.Name)
+			assert.Contains(t, disabled[call], "is blocked: operation not permitted", call.Name)
+		} else {
+			assert.NotContains(t, disabled[call], "is blocked", call.Name)
+		}
+	}
+	// Variants of the same syscall share the probe.
+	assert.NotEmpty(t, probes)
+	for name, n := range probes {
+		assert.Equal(t, 1, n, name)
+		assert.True(t, linuxProbeSyscalls[name], name)
+	}
+	for name := range dangerous {
+		assert.False(t, executedAsRoot[name], name)
+	}
+}
@@ -26,6 +26,10 @@ func (netbsd) machineInfos() []machineInfoFunc {
 	return nil
 }
 
+func (netbsd) probeSyscalls() map[string]bool {
+	return nil
+}
+
 func (netbsd) syscallCheck(ctx *checkContext, call *prog.Syscall) string {
 	switch call.CallName {
 	case "openat":
@@ -26,6 +26,10 @@ func (openbsd) machineInfos() []machineInfoFunc {
 	return nil
 }
 
+func (openbsd) probeSyscalls() map[string]bool {
+	return nil
+}
+
 func (openbsd) syscallCheck(ctx *checkContext, call *prog.Syscall) string {
 	switch call.CallName {
 	case "openat":
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/google/syzkaller/pkg/flatrpc"
@@ -41,6 +42,14 @@ type checkContext struct {
 	syscalls        chan syscallResult
 	pendingSyscalls int
 	features        chan featureResult
+	// Results of probeSyscall per CallName, all variants of a syscall share the probe.
+	probesMu sync.Mutex
+	probes   map[string]*syscallProbe
+}
+
+type syscallProbe struct {
+	once   sync.Once
+	reason string
 }
 
 type syscallResult struct {
@@ -63,6 +72,7 @@ func newCheckContext(ctx context.Context, cfg *mgrconfig.Config, impl checker,
 		executor: executor,
 		syscalls: make(chan syscallResult),
 		features: make(chan featureResult, 100),
+		probes:   make(map[string]*syscallProbe),
 	}
 }
 
@@ -80,10 +90,13 @@ func (ctx *checkContext) start(fileInfos []*flatrpc.FileInfo) {
 			// the checking function and are assumed to be unconditionally supported.
 			syscallCheck = alwaysSupported
 		}
+		probe := ctx.cfg.Experimental.ProbeSyscalls && ctx.cfg.SysTarget.HasCallNumber(call.CallName) &&
+			ctx.impl.probeSyscalls()[call.CallName]
 		// HostFuzzer targets can't run Go binaries on the targets,
 		// so we actually run on the host on another OS. The same for targets.TestOS OS.
 		if ctx.cfg.SysTarget.HostFuzzer || ctx.target.OS == targets.TestOS {
 			syscallCheck = alwaysSupported
+			probe = false
 		}
 		go func() {
 			var reason string
@@ -95,6 +108,9 @@ func (ctx *checkContext) start(fileInfos []*flatrpc.FileInfo) {
 			if reason == "" {
 				reason = syscallCheck(ctx, call)
 			}
+			if reason == "" && probe {
+				reason = ctx.probeSyscall(call)
+			}
 			ctx.syscalls <- syscallResult{call, reason}
 		}()
 	}
@@ -168,6 +184,51 @@ func (ctx *checkContext) supportedSyscalls(names []string) string {
 	return ""
 }
 
+// probeSyscall invokes the syscall with default (benign) arguments in the configured sandbox.
+// Seccomp filters and kernel lockdown fail calls with ENOSYS/EPERM regardless of the arguments,
+// while normal calls fail for such arguments with other errors (e.g. EINVAL or EFAULT).
+// EPERM is also returned for calls that need privileges the sandbox does not have, so such calls
+// are re-probed as root and are considered blocked only if they fail with EPERM as root as well.
+// All variants of a syscall are blocked in the same way, so the probe is done once per CallName.
+// Only syscalls that are known to be free of side effects with such arguments are probed
+// (see checker.probeSyscalls), the rest are never executed by the probe.
+func (ctx *checkContext) probeSyscall(call *prog.Syscall) string {
+	ctx.probesMu.Lock()
+	probe := ctx.probes[call.CallName]
+	if probe == nil {
+		probe = new(syscallProbe)
+		ctx.probes[call.CallName] = probe
+	}
+	ctx.probesMu.Unlock()
+	probe.once.Do(func() {
+		probe.reason = ctx.probeSyscallImpl(call.CallName)
+	})
+	return probe.reason
+}
+
+func (ctx *checkContext) probeSyscallImpl(callName string) string {
+	// Use the plain syscall (or the first variant).
+	name := callName
+	if ctx.target.SyscallMap[name] == nil {
+		for _, call := range ctx.target.Syscalls {
+			if call.CallName == callName {
+				name = call.Name
+				break
+			}
+		}
+	}
+	probe := []string{name + "()"}
+	errno := syscall.Errno(ctx.execRaw(probe, prog.NonStrictUnsafe, false).Calls[0].Error)
+	if errno == syscall.EPERM && ctx.sandbox != 0 {
+		errno = syscall.Errno(ctx.execRaw(probe, prog.NonStrictUnsafe, true).Calls[0].Error)
+	}
+	switch errno {
+	case syscall.ENOSYS, syscall.EPERM:
+		return fmt.Sprintf("syscall %v is blocked: %v", callName, errno)
+	}
+	return ""
+}
+
 func supportedOpenat(ctx *checkContext, call *prog.Syscall) string {
 	fname, ok := extractStringConst(call.Args[1].Type)
 	if !ok || fname[0] != '/' {
@@ -82,6 +82,12 @@ func (checker *Checker) MachineInfo(fileInfos []*flatrpc.FileInfo) ([]cover.Kern
 	return modules, info.Bytes(), nil
 }
 
+// Modules parses the list of kernel modules from a fresh snapshot of RequiredFiles.
+// It's used to pick up modules that were loaded after the machine check.
+func (checker *Checker) Modules(fileInfos []*flatrpc.FileInfo) ([]cover.KernelModule, error) {
+	return checker.parseModules(createVirtualFilesystem(fileInfos))
+}
+
 func (checker *Checker) CheckFiles() []string {
 	return checker.checkFiles()
 }
@@ -109,10 +115,26 @@ type checker interface {
 	parseModules(files filesystem) ([]cover.KernelModule, error)
 	machineInfos() []machineInfoFunc
 	syscallCheck(*checkContext, *prog.Syscall) string
+	// probeSyscalls returns names of syscalls that are safe to probe (see probeSyscall).
+	probeSyscalls() map[string]bool
 }
 
 type filesystem map[string]*flatrpc.FileInfo
 
+// KernelVersion returns "major.minor" version of the kernel running in the VM,
+// or an empty string if it can't be determined from the fetched files.
+func KernelVersion(fileInfos []*flatrpc.FileInfo) string {
+	data, err := createVirtualFilesystem(fileInfos).ReadFile("/proc/version")
+	if err != nil {
+		return ""
+	}
+	major, minor, ok := parseKernelVersion(string(data))
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%v.%v", major, minor)
+}
+
 func createVirtualFilesystem(fileInfos []*flatrpc.FileInfo) filesystem {
 	files := make(filesystem)
 	for _, file := range fileInfos {
@@ -174,6 +196,10 @@ func (stub) machineInfos() []machineInfoFunc {
 	return nil
 }
 
+func (stub) probeSyscalls() map[string]bool {
+	return nil
+}
+
 func (stub) syscallCheck(*checkContext, *prog.Syscall) string {
 	return ""
 }
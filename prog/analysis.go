@@ -23,6 +23,7 @@ type state struct {
 	files     map[string]bool
 	resources map[string][]*ResultArg
 	strings   map[string]bool
+	calls     map[*Syscall]bool
 	ma        *memAlloc
 	va        *vmaAlloc
 }
@@ -48,6 +49,7 @@ func newState(target *Target, ct *ChoiceTable, corpus []*Prog) *state {
 		files:     make(map[string]bool),
 		resources: make(map[string][]*ResultArg),
 		strings:   make(map[string]bool),
+		calls:     make(map[*Syscall]bool),
 		ma:        newMemAlloc(target.NumPages * target.PageSize),
 		va:        newVmaAlloc(target.NumPages),
 	}
@@ -59,6 +61,9 @@ func (s *state) analyze(c *Call) {
 }
 
 func (s *state) analyzeImpl(c *Call, resources bool) {
+	if resources {
+		s.calls[c.Meta] = true
+	}
 	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
 		switch a := arg.(type) {
 		case *PointerArg:
@@ -209,6 +214,11 @@ type RequiredFeatures struct {
 	Csums          bool
 	FaultInjection bool
 	Async          bool
+	ProcRoles      bool
+	TimeJumps      bool
+	CompatSyscalls bool
+	Suspend        bool
+	UringSyscalls  bool
 }
 
 func (p *Prog) RequiredFeatures() RequiredFeatures {
@@ -230,6 +240,21 @@ func (p *Prog) RequiredFeatures() RequiredFeatures {
 		if c.Props.Async {
 			features.Async = true
 		}
+		if c.Props.ProcRole() != RoleMain {
+			features.ProcRoles = true
+		}
+		if c.Props.TimeJump != TimeJumpNone {
+			features.TimeJumps = true
+		}
+		if c.Props.Compat {
+			features.CompatSyscalls = true
+		}
+		if c.Props.Suspend != SuspendNone {
+			features.Suspend = true
+		}
+		if c.Props.Uring {
+			features.UringSyscalls = true
+		}
 	}
 	return features
 }
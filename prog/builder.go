@@ -0,0 +1,274 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"fmt"
+)
+
+// BuilderArg describes a value of a syscall argument for Builder.AddCall.
+// It's converted to Arg according to the type of the argument it's passed for,
+// so the same value can be used e.g. for int32 and flags arguments.
+// A nil BuilderArg is the same as Default().
+type BuilderArg interface {
+	build(pg *Builder, t Type, dir Dir) (Arg, error)
+}
+
+// AddCall appends a call of the syscall with the given name to the program.
+// Args are matched with the syscall arguments in order, missing trailing args get default values.
+// Len, csum and conditional fields are computed automatically.
+// The returned call can be passed to Res to use resources it creates in subsequent calls.
+func (pg *Builder) AddCall(name string, args ...BuilderArg) (*Call, error) {
+	meta := pg.target.SyscallMap[name]
+	if meta == nil {
+		return nil, fmt.Errorf("unknown syscall %v", name)
+	}
+	if len(args) > len(meta.Args) {
+		return nil, fmt.Errorf("%v: too many args: want at most %v, got %v", name, len(meta.Args), len(args))
+	}
+	callArgs := make([]Arg, len(meta.Args))
+	for i, field := range meta.Args {
+		var val BuilderArg
+		if i < len(args) {
+			val = args[i]
+		}
+		arg, err := buildArg(pg, val, field.Type, field.Dir(DirIn))
+		if err != nil {
+			return nil, fmt.Errorf("%v: arg %v: %w", name, field.Name, err)
+		}
+		callArgs[i] = arg
+	}
+	c := MakeCall(meta, callArgs)
+	c.setDefaultConditions(pg.target, true)
+	if err := pg.Append(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate checks the program constructed so far.
+func (pg *Builder) Validate() error {
+	return pg.p.validate()
+}
+
+func buildArg(pg *Builder, val BuilderArg, t Type, dir Dir) (Arg, error) {
+	if val == nil {
+		val = Default()
+	}
+	return val.build(pg, t, dir)
+}
+
+type builderFunc func(pg *Builder, t Type, dir Dir) (Arg, error)
+
+func (f builderFunc) build(pg *Builder, t Type, dir Dir) (Arg, error) {
+	return f(pg, t, dir)
+}
+
+// Default is the default value for the argument type (pointers point to default values).
+func Default() BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		if typ, ok := t.(*PtrType); ok && !typ.Optional() {
+			return Ptr(Default()).build(pg, t, dir)
+		}
+		return t.DefaultArg(dir), nil
+	})
+}
+
+// Int is an integer value for int, flags, const, proc and resource (special value) arguments.
+func Int(v uint64) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		switch t.(type) {
+		case *IntType, *FlagsType, *ConstType, *LenType, *ProcType, *CsumType:
+			return MakeConstArg(t, dir, v), nil
+		case *ResourceType:
+			return MakeResultArg(t, dir, nil, v), nil
+		}
+		return nil, fmt.Errorf("can't use int for %v", t)
+	})
+}
+
+// Flags is a bitwise or of the named constants (e.g. Flags("O_RDWR", "O_CREAT")).
+func Flags(names ...string) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		var v uint64
+		for _, name := range names {
+			val, ok := pg.target.ConstMap[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown const %v", name)
+			}
+			v |= val
+		}
+		return Int(v).build(pg, t, dir)
+	})
+}
+
+// Str is a string value for buffer arguments, it's zero-terminated if the argument type is.
+func Str(s string) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		data := []byte(s)
+		if typ, ok := t.(*BufferType); ok && !typ.NoZ &&
+			(typ.Kind == BufferString || typ.Kind == BufferFilename) &&
+			(len(data) == 0 || data[len(data)-1] != 0) {
+			data = append(data, 0)
+		}
+		return Data(data).build(pg, t, dir)
+	})
+}
+
+// Data is a raw value for buffer arguments (for output buffers only the size matters).
+func Data(data []byte) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		typ, ok := t.(*BufferType)
+		if !ok {
+			return nil, fmt.Errorf("can't use data for %v", t)
+		}
+		if !typ.Varlen() && uint64(len(data)) != typ.Size() {
+			return nil, fmt.Errorf("wrong data size for %v: want %v, got %v", t, typ.Size(), len(data))
+		}
+		if dir == DirOut {
+			return MakeOutDataArg(t, dir, uint64(len(data))), nil
+		}
+		return MakeDataArg(t, dir, append([]byte{}, data...)), nil
+	})
+}
+
+// Ptr is a pointer to the given value, memory for the value is allocated automatically.
+func Ptr(elem BuilderArg) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		typ, ok := t.(*PtrType)
+		if !ok {
+			return nil, fmt.Errorf("can't use pointer for %v", t)
+		}
+		inner, err := buildArg(pg, elem, typ.Elem, typ.ElemDir)
+		if err != nil {
+			return nil, err
+		}
+		return MakePointerArg(t, dir, pg.Allocate(inner.Size(), typ.Elem.Alignment()), inner), nil
+	})
+}
+
+// Nil is a null value for optional pointers.
+func Nil() BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		switch t.(type) {
+		case *PtrType, *VmaType:
+			return MakeSpecialPointerArg(t, dir, 0), nil
+		}
+		return nil, fmt.Errorf("can't use nil for %v", t)
+	})
+}
+
+// Vma is a pointer to a newly allocated region of the given number of pages.
+func Vma(npages uint64) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		if _, ok := t.(*VmaType); !ok {
+			return nil, fmt.Errorf("can't use vma for %v", t)
+		}
+		return MakeVmaPointerArg(t, dir, pg.AllocateVMA(npages), npages*pg.target.PageSize), nil
+	})
+}
+
+// Struct is a value for struct arguments. Fields are matched with the struct fields in order
+// (padding is skipped), missing trailing fields get default values.
+func Struct(fields ...BuilderArg) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		typ, ok := t.(*StructType)
+		if !ok {
+			return nil, fmt.Errorf("can't use struct for %v", t)
+		}
+		var inner []Arg
+		next := 0
+		for _, field := range typ.Fields {
+			var val BuilderArg
+			if !IsPad(field.Type) {
+				if next < len(fields) {
+					val = fields[next]
+				}
+				next++
+			}
+			arg, err := buildArg(pg, val, field.Type, field.Dir(dir))
+			if err != nil {
+				return nil, fmt.Errorf("%v.%v: %w", typ.Name(), field.Name, err)
+			}
+			inner = append(inner, arg)
+		}
+		if next < len(fields) {
+			return nil, fmt.Errorf("too many fields for %v: want at most %v, got %v", t, next, len(fields))
+		}
+		return MakeGroupArg(t, dir, inner), nil
+	})
+}
+
+// Union is a value for union arguments with the given option.
+func Union(option string, val BuilderArg) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		typ, ok := t.(*UnionType)
+		if !ok {
+			return nil, fmt.Errorf("can't use union for %v", t)
+		}
+		for i, field := range typ.Fields {
+			if field.Name != option {
+				continue
+			}
+			arg, err := buildArg(pg, val, field.Type, field.Dir(dir))
+			if err != nil {
+				return nil, fmt.Errorf("%v.%v: %w", typ.Name(), field.Name, err)
+			}
+			return MakeUnionArg(t, dir, arg, i), nil
+		}
+		return nil, fmt.Errorf("union %v has no option %v", typ.Name(), option)
+	})
+}
+
+// Array is a value for array arguments.
+func Array(elems ...BuilderArg) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		typ, ok := t.(*ArrayType)
+		if !ok {
+			return nil, fmt.Errorf("can't use array for %v", t)
+		}
+		n := uint64(len(elems))
+		if typ.Kind == ArrayRangeLen && (n < typ.RangeBegin || n > typ.RangeEnd) {
+			return nil, fmt.Errorf("wrong number of elements for %v: want [%v, %v], got %v",
+				t, typ.RangeBegin, typ.RangeEnd, n)
+		}
+		var inner []Arg
+		for i, elem := range elems {
+			arg, err := buildArg(pg, elem, typ.Elem, dir)
+			if err != nil {
+				return nil, fmt.Errorf("elem %v: %w", i, err)
+			}
+			inner = append(inner, arg)
+		}
+		return MakeGroupArg(t, dir, inner), nil
+	})
+}
+
+// Res uses a resource created by the call (the return value or an output argument)
+// that is compatible with the argument. If the call creates several such resources,
+// the first one is used.
+func Res(c *Call) BuilderArg {
+	return builderFunc(func(pg *Builder, t Type, dir Dir) (Arg, error) {
+		typ, ok := t.(*ResourceType)
+		if !ok {
+			return nil, fmt.Errorf("can't use resource for %v", t)
+		}
+		if dir == DirOut {
+			return nil, fmt.Errorf("can't use resource for output argument %v", t)
+		}
+		var res *ResultArg
+		ForeachArg(c, func(arg Arg, ctx *ArgCtx) {
+			a, ok := arg.(*ResultArg)
+			if !ok || res != nil || a.Dir() == DirIn ||
+				!pg.target.isCompatibleResource(typ.Desc.Name, a.Type().(*ResourceType).Desc.Name) {
+				return
+			}
+			res = a
+		})
+		if res == nil {
+			return nil, fmt.Errorf("%v does not create resource %v", c.Meta.Name, typ.Desc.Name)
+		}
+		return MakeResultArg(t, dir, res, 0), nil
+	})
+}
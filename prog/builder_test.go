@@ -0,0 +1,79 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	target, _, _ := initTest(t)
+	pg := MakeProgGen(target)
+	mustAdd := func(name string, args ...BuilderArg) *Call {
+		c, err := pg.AddCall(name, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	open := mustAdd("openat", Flags("AT_FDCWD"), Ptr(Str("./file0")), Flags("O_RDWR", "O_CREAT"))
+	mustAdd("write", Res(open), Ptr(Str("hello")))
+	pipe := mustAdd("pipe", nil)
+	mustAdd("close", Res(pipe))
+	sock := mustAdd("socket$inet", Flags("AF_INET"), Flags("SOCK_STREAM"))
+	mustAdd("bind$inet", Res(sock), Ptr(Struct(nil, Int(1), Union("loopback", nil))))
+	mustAdd("readv", Res(open), Ptr(Array(
+		Struct(Ptr(Data(make([]byte, 4)))),
+		Struct(Nil()),
+	)))
+	assert.NoError(t, pg.Validate())
+	p, err := pg.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x42, 0x0)
+write(r0, &(0x7f0000000040)='hello', 0x5)
+pipe(&(0x7f0000000080)={<r1=>0xffffffffffffffff})
+close(r1)
+r2 = socket$inet(0x2, 0x1, 0x0)
+bind$inet(r2, &(0x7f00000000c0)={0x2, 0x1, @loopback}, 0x10)
+readv(r0, &(0x7f0000000140)=[{&(0x7f0000000100)=""/4, 0x4}, {0x0}], 0x2)
+`, string(p.Serialize()))
+}
+
+func TestBuilderErrors(t *testing.T) {
+	target, _, _ := initTest(t)
+	pg := MakeProgGen(target)
+	open, err := pg.AddCall("openat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write, err := pg.AddCall("write", Res(open))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		args []BuilderArg
+		err  string
+	}{
+		{"foo", nil, "unknown syscall foo"},
+		{"close", []BuilderArg{nil, nil}, "close: too many args: want at most 1, got 2"},
+		{"close", []BuilderArg{Str("foo")}, "close: arg fd: can't use data for fd"},
+		{"close", []BuilderArg{Flags("NO_SUCH_CONST")}, "close: arg fd: unknown const NO_SUCH_CONST"},
+		{"socket$inet", []BuilderArg{Res(open)}, "socket$inet: arg domain: can't use resource for const[2, const]"},
+		{"close", []BuilderArg{Res(write)}, "close: arg fd: write does not create resource fd"},
+		{"bind$inet", []BuilderArg{nil, Ptr(Struct(nil, nil, Union("foo", nil)))},
+			"bind$inet: arg addr: sockaddr_in.addr: union ipv4_addr has no option foo"},
+		{"bind$inet", []BuilderArg{nil, Ptr(Struct(nil, nil, nil, nil))},
+			"bind$inet: arg addr: too many fields for sockaddr_in: want at most 3, got 4"},
+	} {
+		_, err := pg.AddCall(test.name, test.args...)
+		assert.EqualError(t, err, test.err)
+	}
+	_, err = pg.Finalize()
+	assert.NoError(t, err)
+}
@@ -47,6 +47,7 @@ func cloneCall(c *Call, newargs map[*ResultArg]*ResultArg) *Call {
 		c1.Args[ai] = clone(arg, newargs)
 	}
 	c1.Props = c.Props
+	cloneProvenance(c, c1)
 	return c1
 }
 
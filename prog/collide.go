@@ -66,6 +66,9 @@ func AssignRandomRerun(prog *Prog, rand *rand.Rand) {
 		if !prog.Calls[i].Props.Async || rand.Intn(4) != 0 {
 			continue
 		}
+		if prog.Calls[i].Props.ProcRole() != RoleMain || prog.Calls[i+1].Props.ProcRole() != RoleMain {
+			continue
+		}
 		// We assign rerun to consecutive pairs of calls, where the first call is async.
 		// TODO: consider assigning rerun also to non-collided progs.
 		rerun := rerunSteps[rand.Intn(len(rerunSteps))]
@@ -137,3 +140,23 @@ func DupCallCollide(origProg *Prog, rand *rand.Rand) (*Prog, error) {
 	prog.Calls = retCalls
 	return prog, nil
 }
+
+// PairCollide concatenates two programs and marks calls of the second one async,
+// so that they are executed concurrently with the first program.
+// The programs don't share resources, the intention is to race two programs
+// that independently reached the same kernel code.
+func PairCollide(first, second *Prog, rand *rand.Rand) (*Prog, error) {
+	if len(first.Calls)+len(second.Calls) > MaxCalls {
+		return nil, fmt.Errorf("the progs are too big for the PairCollide transformation")
+	}
+	prog := first.Clone()
+	leftAsync := maxAsyncPerProg
+	for _, c := range second.Clone().Calls {
+		c.Props.Async = leftAsync > 0 && rand.Intn(4) != 0
+		if c.Props.Async {
+			leftAsync--
+		}
+		prog.Calls = append(prog.Calls, c)
+	}
+	return prog, nil
+}
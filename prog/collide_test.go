@@ -208,3 +208,41 @@ dup(r2)
 		}
 	}
 }
+
+func TestPairCollide(t *testing.T) {
+	target, rs, iters := initTest(t)
+	if iters > 100 {
+		iters = 100
+	}
+	r := rand.New(rs)
+	first, err := target.Deserialize([]byte(`r0 = openat(0xffffffffffffff9c, &AUTO='./file1\x00', 0x42, 0x1ff)
+write(r0, &AUTO="01010101", 0x4)
+`), Strict)
+	assert.NoError(t, err)
+	second, err := target.Deserialize([]byte(`r0 = openat(0xffffffffffffff9c, &AUTO='./file1\x00', 0x42, 0x1ff)
+read(r0, &AUTO=""/4, 0x4)
+close(r0)
+`), Strict)
+	assert.NoError(t, err)
+	anyAsync := false
+	for i := 0; i < iters; i++ {
+		collided, err := PairCollide(first, second, r)
+		assert.NoError(t, err)
+		assert.Len(t, collided.Calls, 5)
+		for i, c := range collided.Calls {
+			if i < len(first.Calls) {
+				assert.False(t, c.Props.Async)
+			}
+			anyAsync = anyAsync || c.Props.Async
+		}
+		assert.NoError(t, collided.validate())
+	}
+	assert.True(t, anyAsync)
+	// The originals must be left intact.
+	assert.Len(t, first.Calls, 2)
+	assert.Len(t, second.Calls, 3)
+
+	big := target.Generate(r, MaxCalls, target.DefaultChoiceTable())
+	_, err = PairCollide(big, second, r)
+	assert.Error(t, err)
+}
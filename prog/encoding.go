@@ -346,6 +346,15 @@ func (p *parser) parseProg() (*Prog, error) {
 			c.Props = p.parseCallProps()
 			p.Parse(')')
 		}
+		if c.Props.Compat && meta.CompatNR == 0 {
+			// E.g. a program from a 64-bit target deserialized for the 32-bit target.
+			p.strictFailf("compat is not supported for %v", meta.Name)
+			c.Props.Compat = false
+		}
+		if c.Props.Uring && (!meta.Uring || c.Props.Compat) {
+			p.strictFailf("uring is not supported for %v", meta.Name)
+			c.Props.Uring = false
+		}
 
 		if !p.EOF() {
 			if p.Char() != '#' {
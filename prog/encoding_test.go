@@ -471,7 +471,7 @@ func TestSerializeCallProps(t *testing.T) {
 		},
 		{
 			"serialize0(0x0) (fail_nth: 5)\n",
-			[]CallProps{{5, false, 0}},
+			[]CallProps{{FailNth: 5}},
 		},
 		{
 			"serialize0(0x0) (fail_nth)\n",
@@ -483,11 +483,57 @@ func TestSerializeCallProps(t *testing.T) {
 		},
 		{
 			"serialize0(0x0) (async)\n",
-			[]CallProps{{0, true, 0}},
+			[]CallProps{{Async: true}},
 		},
 		{
 			"serialize0(0x0) (async, rerun: 10)\n",
-			[]CallProps{{0, true, 10}},
+			[]CallProps{{Async: true, Rerun: 10}},
+		},
+		{
+			"serialize0(0x0) (role: 2)\n",
+			[]CallProps{{Role: RoleUnprivileged}},
+		},
+		{
+			"serialize0(0x0) (role: 3)\n",
+			nil,
+		},
+		{
+			"serialize0(0x0) (fail_nth: 1, role: 1)\n",
+			nil,
+		},
+		{
+			"serialize0(0x0) (time_jump: 3)\n",
+			[]CallProps{{TimeJump: TimeJumpY2038}},
+		},
+		{
+			"serialize0(0x0) (time_jump: 5)\n",
+			nil,
+		},
+		{
+			"serialize0(0x0) (role: 1, time_jump: 4)\n",
+			nil,
+		},
+		{
+			"serialize0(0x0) (fail_nth: 1, time_jump: 4)\n",
+			nil,
+		},
+		{
+			// Test calls don't have the compat syscall entry.
+			"serialize0(0x0) (compat)\n",
+			nil,
+		},
+		{
+			"serialize0(0x0) (suspend: 1)\n",
+			[]CallProps{{Suspend: SuspendIdle}},
+		},
+		{
+			"serialize0(0x0) (suspend: 3)\n",
+			nil,
+		},
+		{
+			// Test calls don't have io_uring equivalents.
+			"serialize0(0x0) (uring)\n",
+			nil,
 		},
 	}
 
@@ -133,6 +133,9 @@ type argInfo struct {
 	Addr uint64 // physical addr
 	Idx  uint64 // copyout instruction index
 	Ret  bool
+	// The resource is written in a child process (see CallProps.ProcRole),
+	// so it's never copied out and users get the default value.
+	NoCopyout bool
 }
 
 func (w *execContext) writeCallProps(props CallProps) {
@@ -239,6 +242,11 @@ func (w *execContext) writeCopyout(c *Call) {
 			if info.Ret {
 				return // Idx is already assigned above.
 			}
+			if c.Props.ProcRole() != RoleMain {
+				info.NoCopyout = true
+				w.args[arg] = info
+				return
+			}
 			info.Idx = w.copyoutSeq
 			w.copyoutSeq++
 			w.args[arg] = info
@@ -268,6 +276,10 @@ func (w *execContext) writeArg(arg Arg) {
 			if !ok {
 				panic("no copyout index")
 			}
+			if info.NoCopyout {
+				w.writeConstArg(a.Size(), a.Type().(*ResourceType).Default(), 0, 0, 0, a.Type().Format())
+				break
+			}
 			w.write(execArgResult)
 			meta := a.Size() | uint64(a.Type().Format())<<8
 			w.write(meta)
@@ -578,13 +578,22 @@ func TestSerializeForExec(t *testing.T) {
 			`test() (fail_nth: 3)
 test() (fail_nth: 4)
 test() (async, rerun: 10)
+test() (role: 1)
+test() (time_jump: 2)
+test() (suspend: 2)
 `,
 			[]any{
-				execInstrSetProps, 3, 0, 0,
+				execInstrSetProps, 3, 0, 0, 0, 0, 0, 0, 0,
 				callID("test"), ExecNoCopyout, 0,
-				execInstrSetProps, 4, 0, 0,
+				execInstrSetProps, 4, 0, 0, 0, 0, 0, 0, 0,
 				callID("test"), ExecNoCopyout, 0,
-				execInstrSetProps, 0, 1, 10,
+				execInstrSetProps, 0, 1, 10, 0, 0, 0, 0, 0,
+				callID("test"), ExecNoCopyout, 0,
+				execInstrSetProps, 0, 0, 0, 1, 0, 0, 0, 0,
+				callID("test"), ExecNoCopyout, 0,
+				execInstrSetProps, 0, 0, 0, 0, 2, 0, 0, 0,
+				callID("test"), ExecNoCopyout, 0,
+				execInstrSetProps, 0, 0, 0, 0, 0, 0, 2, 0,
 				callID("test"), ExecNoCopyout, 0,
 				execInstrEOF,
 			},
@@ -593,21 +602,49 @@ test() (async, rerun: 10)
 					{
 						Meta:  target.SyscallMap["test"],
 						Index: ExecNoCopyout,
-						Props: CallProps{3, false, 0},
+						Props: CallProps{FailNth: 3},
+					},
+					{
+						Meta:  target.SyscallMap["test"],
+						Index: ExecNoCopyout,
+						Props: CallProps{FailNth: 4},
+					},
+					{
+						Meta:  target.SyscallMap["test"],
+						Index: ExecNoCopyout,
+						Props: CallProps{Async: true, Rerun: 10},
+					},
+					{
+						Meta:  target.SyscallMap["test"],
+						Index: ExecNoCopyout,
+						Props: CallProps{Role: RoleChild},
 					},
 					{
 						Meta:  target.SyscallMap["test"],
 						Index: ExecNoCopyout,
-						Props: CallProps{4, false, 0},
+						Props: CallProps{TimeJump: TimeJumpBackward},
 					},
 					{
 						Meta:  target.SyscallMap["test"],
 						Index: ExecNoCopyout,
-						Props: CallProps{0, true, 10},
+						Props: CallProps{Suspend: SuspendMem},
 					},
 				},
 			},
 		},
+		{
+			// Resources created in a child process are never copied out.
+			`test$time_jumps_res(&(0x7f0000000010)=<r0=>0x0) (time_jump: 4)
+test$res1(r0)
+`,
+			[]any{
+				execInstrSetProps, 0, 0, 0, 0, 4, 0, 0, 0,
+				callID("test$time_jumps_res"), ExecNoCopyout, 1, execArgAddr64, 0x10,
+				callID("test$res1"), ExecNoCopyout, 1, execArgConst, 4, 0xffff,
+				execInstrEOF,
+			},
+			nil,
+		},
 		{
 			`test$res3(&(0x7f0000000010)=<r0=>0x0)
 test$res1(r0)
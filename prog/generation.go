@@ -26,9 +26,7 @@ func (target *Target) Generate(rs rand.Source, ncalls int, ct *ChoiceTable) *Pro
 	// resources and overflow ncalls. Remove some of these calls.
 	// The resources in the last call will be replaced with the default values,
 	// which is exactly what we want.
-	for len(p.Calls) > ncalls {
-		p.RemoveCall(ncalls - 1)
-	}
+	p.trimCalls(0, ncalls-1, ncalls)
 	p.sanitizeFix()
 	p.debugValidate()
 	return p
@@ -218,6 +218,156 @@ func checkCompressedArg(arg *DataArg, compMap CompMap, exec func() bool) {
 	arg.SetData(data0)
 }
 
+// MutateWithBlobHints extends hint substitutions of data arguments to whole byte sequences
+// compared by instrumented memcmp/strcmp-style loops (magic strings, netlink family names, etc).
+// Such calls are not intercepted, KCOV reports the comparisons of the loop chunk-by-chunk
+// and only up to the first mismatching chunk, so after a chunk is substituted the program
+// is re-executed with the collectComps callback and the next chunk is matched against
+// the comparisons that were not observed before. The resulting mutants are executed
+// with the exec callback. Both callbacks must return whether we should continue
+// substitution (true) or abort the process (false). The total number of callback
+// invocations per call is limited by maxBlobHintExecs.
+func (p *Prog) MutateWithBlobHints(callIndex int, comps CompMap,
+	collectComps func(p *Prog) (CompMap, bool), exec func(p *Prog) bool) {
+	p = p.Clone()
+	c := p.Calls[callIndex]
+	h := &blobHints{
+		p:            p,
+		c:            c,
+		comps:        comps,
+		collectComps: collectComps,
+		exec:         exec,
+		budget:       maxBlobHintExecs,
+	}
+	ForeachArg(c, func(arg Arg, ctx *ArgCtx) {
+		if h.stop || h.budget <= 0 {
+			ctx.Stop = true
+			return
+		}
+		if a, ok := arg.(*DataArg); ok && a.Dir() != DirOut && blobHintsType(a.Type().(*BufferType)) {
+			h.checkArg(a)
+		}
+	})
+}
+
+const (
+	// Max number of executions (both collectComps and exec) done per call.
+	maxBlobHintExecs = 64
+	// Max number of chunks substituted in a single chain.
+	maxBlobHintChunks = 32
+)
+
+// blobHintWidths are sizes of chunks memcmp/strcmp-style code compares:
+// words for memcmp on arches with efficient unaligned access and bytes for strcmp.
+var blobHintWidths = []int{8, 1}
+
+type blobHints struct {
+	p            *Prog
+	c            *Call
+	comps        CompMap
+	collectComps func(p *Prog) (CompMap, bool)
+	exec         func(p *Prog) bool
+	budget       int
+	stop         bool
+}
+
+func blobHintsType(t *BufferType) bool {
+	switch t.Kind {
+	case BufferFilename, BufferGlob, BufferCompressed:
+		return false
+	case BufferString:
+		// Enumerated strings are already complete, see generateHints.
+		return len(t.Values) == 0
+	}
+	return true
+}
+
+func (h *blobHints) checkArg(arg *DataArg) {
+	data := arg.Data()
+	original := append([]byte{}, data...)
+	size := min(len(data), maxDataLength)
+	for _, width := range blobHintWidths {
+		for i := 0; i+width <= size; i++ {
+			for _, replacer := range blobReplacers(readChunk(data, i, width), width, h.comps, nil) {
+				if h.stop || h.budget <= 0 {
+					return
+				}
+				writeChunk(data, i, width, replacer)
+				h.extend(data, i+width, width)
+				copy(data, original)
+			}
+		}
+	}
+}
+
+// extend substitutes chunks starting from offset with the values the kernel compares them with.
+func (h *blobHints) extend(data []byte, offset, width int) {
+	chunks := 1
+	for ; chunks < maxBlobHintChunks && offset+width <= len(data); offset += width {
+		if h.budget <= 1 {
+			// Leave one execution for the mutant.
+			break
+		}
+		h.budget--
+		comps, ok := h.collectComps(h.p)
+		if !ok {
+			h.stop = true
+			return
+		}
+		replacers := blobReplacers(readChunk(data, offset, width), width, comps, h.comps)
+		if len(replacers) != 1 {
+			// Either the compared sequence has ended, or we can't tell which comparison is the next chunk.
+			break
+		}
+		writeChunk(data, offset, width, replacers[0])
+		chunks++
+	}
+	// Single chunk substitutions are already done by MutateWithHints.
+	if chunks == 1 || h.p.Target.sanitize(h.c, false) != nil || h.p.checkConditions() != nil {
+		return
+	}
+	h.p.debugValidate()
+	h.budget--
+	h.stop = !h.exec(h.p)
+}
+
+// blobReplacers returns values of width bytes the chunk value is compared with in comps,
+// comparisons that are also present in seen are ignored.
+func blobReplacers(val uint64, width int, comps, seen CompMap) []uint64 {
+	mask := ^uint64(0)
+	if width < 8 {
+		mask = 1<<(uint(width)*8) - 1
+	}
+	var res []uint64
+	for newV := range comps[val] {
+		// Note: executor sign extends comparison operands.
+		if hi := newV &^ mask; hi != 0 && hi != ^mask {
+			continue
+		}
+		newV &= mask
+		if newV == val || seen[val][newV] {
+			continue
+		}
+		res = append(res, newV)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+	return res
+}
+
+func readChunk(data []byte, offset, width int) uint64 {
+	buf := make([]byte, 8)
+	copy(buf, data[offset:offset+width])
+	return binary.LittleEndian.Uint64(buf)
+}
+
+func writeChunk(data []byte, offset, width int, val uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, val)
+	copy(data[offset:offset+width], buf)
+}
+
 // Shrink and expand mutations model the cases when the syscall arguments
 // are casted to narrower (and wider) integer types.
 //
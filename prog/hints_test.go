@@ -667,6 +667,118 @@ func TestHintsData(t *testing.T) {
 	}
 }
 
+func TestHintsBlob(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	// Emulates KCOV comparisons of strcmp(data, "nl80211") and
+	// of word-wise memcmp(data, "_BHRfS_M_BHRfS_M", 16).
+	emulate := func(data []byte, expected string, width int) CompMap {
+		comps := make(CompMap)
+		for i := 0; i+width <= len(expected) && i+width <= len(data); i += width {
+			op1 := readChunk(data, i, width)
+			op2 := readChunk([]byte(expected), i, width)
+			comps.AddComp(op1, op2)
+			if op1 != op2 {
+				break
+			}
+		}
+		return comps
+	}
+	type Test struct {
+		in       string
+		expected string
+		width    int
+		out      []string
+	}
+	tests := []Test{
+		{
+			in:       "abcdefgh",
+			expected: "nl80211",
+			width:    1,
+			out:      []string{"nl80211h"},
+		},
+		{
+			in:       "0123456789abcdefXY",
+			expected: "_BHRfS_M_BHRfS_M",
+			width:    8,
+			out:      []string{"_BHRfS_M_BHRfS_MXY"},
+		},
+		{
+			// Single chunk substitutions are done by MutateWithHints.
+			in:       "xbcdefgh",
+			expected: "n",
+			width:    1,
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			p, err := target.Deserialize([]byte(fmt.Sprintf("test$hint_data(&AUTO=\"%v\")",
+				hex.EncodeToString([]byte(test.in)))), Strict)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := func(p *Prog) []byte {
+				return p.Calls[0].Args[0].(*PointerArg).Res.(*DataArg).Data()
+			}
+			comps := emulate(data(p), test.expected, test.width)
+			var got []string
+			p.MutateWithBlobHints(0, comps, func(p *Prog) (CompMap, bool) {
+				return emulate(data(p), test.expected, test.width), true
+			}, func(p *Prog) bool {
+				got = append(got, string(data(p)))
+				return true
+			})
+			assert.Equal(t, test.out, got)
+			assert.Equal(t, test.in, string(data(p)))
+		})
+	}
+}
+
+func TestHintsBlobEnumString(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte(`test$str2(&AUTO='foo\x00')`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comps := make(CompMap)
+	comps.AddComp(uint64('f'), uint64('x'))
+	p.MutateWithBlobHints(0, comps, func(p *Prog) (CompMap, bool) {
+		t.Fatalf("enumerated string is mutated")
+		return nil, false
+	}, func(p *Prog) bool {
+		t.Fatalf("enumerated string is mutated")
+		return false
+	})
+}
+
+func TestHintsBlobBudget(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	data := make([]byte, 256)
+	p, err := target.Deserialize([]byte(fmt.Sprintf("test$hint_data(&AUTO=\"%v\")",
+		hex.EncodeToString(data))), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every byte is compared with 3 different values, so the number of chains
+	// is not limited by anything but the budget.
+	comps := make(CompMap)
+	for v := uint64(1); v <= 3; v++ {
+		comps.AddComp(0, v)
+	}
+	execs := 0
+	p.MutateWithBlobHints(0, comps, func(p *Prog) (CompMap, bool) {
+		execs++
+		next := make(CompMap)
+		next.AddComp(0, uint64(execs+10))
+		return next, true
+	}, func(p *Prog) bool {
+		execs++
+		return true
+	})
+	if execs == 0 || execs > maxBlobHintExecs {
+		t.Fatalf("got %v executions, want (0, %v]", execs, maxBlobHintExecs)
+	}
+}
+
 func TestInplaceIntersect(t *testing.T) {
 	m1 := CompMap{
 		0xdead: compSet(0x1, 0x2),
@@ -85,7 +85,8 @@ func Minimize(p0 *Prog, callIndex0 int, params MinimizeParams, pred0 func(*Prog,
 func removeCalls(p0 *Prog, callIndex0 int, pred func(*Prog, int) bool) (*Prog, int) {
 	if callIndex0 >= 0 && callIndex0+2 < len(p0.Calls) {
 		// It's frequently the case that all subsequent calls were not necessary.
-		// Try to drop them all at once.
+		// Try to drop them all at once (this does not break required call sequences,
+		// since required calls always precede the calls that require them).
 		p := p0.Clone()
 		for i := len(p0.Calls) - 1; i > callIndex0; i-- {
 			p.RemoveCall(i)
@@ -95,7 +96,9 @@ func removeCalls(p0 *Prog, callIndex0 int, pred func(*Prog, int) bool) (*Prog, i
 		}
 	}
 	for i := len(p0.Calls) - 1; i >= 0; i-- {
-		if i == callIndex0 {
+		// Calls required by later calls (see Syscall.Requires) are kept,
+		// they can be removed once all the calls that require them are removed.
+		if i == callIndex0 || p0.isRequired(i) {
 			continue
 		}
 		callIndex := callIndex0
@@ -160,6 +163,51 @@ func minimizeCallProps(p0 *Prog, callIndex, callIndex0 int, pred func(*Prog, int
 		}
 	}
 
+	// Try to execute the call without changing the clocks.
+	if props.TimeJump != TimeJumpNone {
+		p := p0.Clone()
+		p.Calls[callIndex].Props.TimeJump = TimeJumpNone
+		if pred(p, callIndex0) {
+			p0 = p
+		}
+	}
+
+	// Try to execute the call without the suspend/resume cycle.
+	if props.Suspend != SuspendNone {
+		p := p0.Clone()
+		p.Calls[callIndex].Props.Suspend = SuspendNone
+		if pred(p, callIndex0) {
+			p0 = p
+		}
+	}
+
+	// Try to execute the call via the native syscall entry.
+	if props.Compat {
+		p := p0.Clone()
+		p.Calls[callIndex].Props.Compat = false
+		if pred(p, callIndex0) {
+			p0 = p
+		}
+	}
+
+	// Try to execute the call as a syscall.
+	if props.Uring {
+		p := p0.Clone()
+		p.Calls[callIndex].Props.Uring = false
+		if pred(p, callIndex0) {
+			p0 = p
+		}
+	}
+
+	// Try to execute the call in the test process.
+	if props.Role != RoleMain {
+		p := p0.Clone()
+		p.Calls[callIndex].Props.Role = RoleMain
+		if pred(p, callIndex0) {
+			p0 = p
+		}
+	}
+
 	return p0
 }
 
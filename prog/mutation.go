@@ -47,6 +47,15 @@ type MutateOpts struct {
 	InsertWeight       int
 	MutateArgWeight    int
 	RemoveCallWeight   int
+	// Occasionally switch calls to io_uring submission (see CallProps.Uring).
+	// Should be set only if the target machine supports io_uring.
+	UringCalls bool
+	// Occasionally switch programs to the compat syscall entry (see CallProps.Compat).
+	// Should be set only if the target machine supports it.
+	CompatCalls bool
+	// The first Prefix calls are not mutated (e.g. they build a state for the rest of the program),
+	// but new calls may use resources created by them.
+	Prefix int
 }
 
 func (o MutateOpts) weight() int {
@@ -98,6 +107,12 @@ func (p *Prog) MutateWithOpts(rs rand.Source, ncalls int, ct *ChoiceTable, noMut
 		}
 		ok = ctx.removeCall()
 	}
+	if opts.CompatCalls && r.oneOf(compatMutationRate) {
+		ctx.toggleCompat()
+	}
+	if opts.UringCalls && r.oneOf(uringMutationRate) {
+		ctx.toggleUring()
+	}
 	p.sanitizeFix()
 	p.debugValidate()
 	if got := len(p.Calls); got < 1 || got > ncalls {
@@ -125,10 +140,15 @@ func (ctx *mutator) splice() bool {
 	if len(ctx.corpus) == 0 || len(p.Calls) == 0 || len(p.Calls) >= ctx.ncalls {
 		return false
 	}
+	if len(p.Calls) <= ctx.opts.Prefix {
+		return false
+	}
 	p0 := ctx.corpus[r.Intn(len(ctx.corpus))]
 	p0c := p0.Clone()
-	idx := r.Intn(len(p.Calls))
+	setCallsProvenance(p0c.Calls, MutationSplice)
+	idx := ctx.opts.Prefix + r.Intn(len(p.Calls)-ctx.opts.Prefix)
 	p.Calls = append(p.Calls[:idx], append(p0c.Calls, p.Calls[idx:]...)...)
+	// Calls are removed from the end, so calls required by the remaining calls are preserved.
 	for i := len(p.Calls) - 1; i >= ctx.ncalls; i-- {
 		p.RemoveCall(i)
 	}
@@ -144,7 +164,7 @@ func (ctx *mutator) squashAny() bool {
 		return false
 	}
 	ptr := complexPtrs[r.Intn(len(complexPtrs))]
-	if ctx.noMutate[ptr.call.Meta.ID] {
+	if ctx.noMutate[ptr.call.Meta.ID] || ctx.inPrefix(ptr.call) {
 		return false
 	}
 	if !p.Target.isAnyPtr(ptr.arg.Type()) {
@@ -174,6 +194,7 @@ func (ctx *mutator) squashAny() bool {
 	base := bases[idx]
 	baseSize := base.Res.Size()
 	arg.data = mutateData(r, arg.Data(), 0, maxBlobLen)
+	ptr.call.setProvenance(arg, MutationSquash)
 	// Update base pointer if size has increased.
 	if baseSize < base.Res.Size() {
 		newArg := r.allocAddr(s, base.Type(), base.Dir(), base.Res.Size(), base.Res)
@@ -189,17 +210,16 @@ func (ctx *mutator) insertCall() bool {
 	if len(p.Calls) >= ctx.ncalls {
 		return false
 	}
-	idx := r.biasedRand(len(p.Calls)+1, 5)
+	idx := ctx.opts.Prefix + r.biasedRand(len(p.Calls)-ctx.opts.Prefix+1, 5)
 	var c *Call
 	if idx < len(p.Calls) {
 		c = p.Calls[idx]
 	}
 	s := analyze(ctx.ct, ctx.corpus, p, c)
 	calls := r.generateCall(s, p, idx)
+	setCallsProvenance(calls, MutationInsert)
 	p.insertBefore(c, calls)
-	for len(p.Calls) > ctx.ncalls {
-		p.RemoveCall(idx)
-	}
+	p.trimCalls(ctx.opts.Prefix, idx, ctx.ncalls)
 	return true
 }
 
@@ -210,10 +230,58 @@ func (ctx *mutator) removeCall() bool {
 		return false
 	}
 	idx := r.Intn(len(p.Calls))
+	if idx < ctx.opts.Prefix || p.isRequired(idx) {
+		return false
+	}
 	p.RemoveCall(idx)
 	return true
 }
 
+func (ctx *mutator) inPrefix(c *Call) bool {
+	for _, c1 := range ctx.p.Calls[:ctx.opts.Prefix] {
+		if c1 == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Programs are switched between the native and the compat syscall entry once per that many mutations.
+const compatMutationRate = 50
+
+// toggleCompat switches all calls of the program that can be executed via the compat syscall entry
+// to the compat entry, or back to the native entry if some of them already use it.
+// Calls submitted via io_uring are not switched.
+func (ctx *mutator) toggleCompat() {
+	compat := false
+	for _, c := range ctx.p.Calls[ctx.opts.Prefix:] {
+		compat = compat || c.Props.Compat
+	}
+	for _, c := range ctx.p.Calls[ctx.opts.Prefix:] {
+		if c.Meta.CompatNR != 0 && !c.Props.Uring && !ctx.noMutate[c.Meta.ID] {
+			c.Props.Compat = !compat
+		}
+	}
+}
+
+// Programs are switched between syscalls and io_uring submission once per that many mutations.
+const uringMutationRate = 50
+
+// toggleUring switches all calls of the program that have an equivalent io_uring operation
+// to io_uring submission, or back to syscalls if some of them are already submitted via io_uring.
+// Calls executed via the compat syscall entry are not switched.
+func (ctx *mutator) toggleUring() {
+	uring := false
+	for _, c := range ctx.p.Calls[ctx.opts.Prefix:] {
+		uring = uring || c.Props.Uring
+	}
+	for _, c := range ctx.p.Calls[ctx.opts.Prefix:] {
+		if c.Meta.Uring && !c.Props.Compat && !ctx.noMutate[c.Meta.ID] {
+			c.Props.Uring = !uring
+		}
+	}
+}
+
 // Mutate an argument of a random call.
 func (ctx *mutator) mutateArg() bool {
 	p, r := ctx.p, ctx.r
@@ -222,7 +290,7 @@ func (ctx *mutator) mutateArg() bool {
 	}
 
 	idx := chooseCall(p, r)
-	if idx < 0 {
+	if idx < ctx.opts.Prefix {
 		return false
 	}
 	c := p.Calls[idx]
@@ -244,14 +312,13 @@ func (ctx *mutator) mutateArg() bool {
 			ok = false
 			continue
 		}
+		op := argMutationOp(arg)
+		c.setProvenance(arg, op)
+		setCallsProvenance(calls, op)
 		moreCalls, fieldsPatched := r.patchConditionalFields(c, s)
 		calls = append(calls, moreCalls...)
 		p.insertBefore(c, calls)
-		idx += len(calls)
-		for len(p.Calls) > ctx.ncalls {
-			idx--
-			p.RemoveCall(idx)
-		}
+		idx = p.trimCallsBefore(ctx.opts.Prefix, idx+len(calls), ctx.ncalls)
 		if idx < 0 || idx >= len(p.Calls) || p.Calls[idx] != c {
 			panic(fmt.Sprintf("wrong call index: idx=%v calls=%v p.Calls=%v ncalls=%v",
 				idx, len(calls), len(p.Calls), ctx.ncalls))
@@ -438,14 +505,37 @@ func (r *randGen) mutateImage(compressed []byte) (data []byte, retry bool) {
 	if len(data) == 0 {
 		return compressed, true // Do not mutate empty data.
 	}
-	hm := MakeGenericHeatmap(data, r.Rand)
-	for i := hm.NumMutations(); i > 0; i-- {
-		index := hm.ChooseLocation()
-		width := 1 << uint(r.Intn(4))
-		if index+width > len(data) {
-			width = 1
+	fs := image.DetectFileSystem(data)
+	var fields []image.Field
+	if fs != nil {
+		if fs.Generate != nil && r.oneOf(100) {
+			// Start over from a new valid image of the same file system.
+			return image.Compress(fs.Generate(r.Rand)), false
 		}
-		storeInt(data[index:], r.randInt(uint64(width*8)), width)
+		if r.bin() {
+			fields = fs.Fields(data)
+		}
+	}
+	if len(fields) != 0 {
+		// Structure-aware mutation of the file system metadata.
+		for i := 1 + r.Intn(3); i > 0; i-- {
+			f := fields[r.Intn(len(fields))]
+			storeInt(data[f.Offset:], r.randInt(uint64(f.Size*8)), f.Size)
+		}
+	} else {
+		hm := MakeGenericHeatmap(data, r.Rand)
+		for i := hm.NumMutations(); i > 0; i-- {
+			index := hm.ChooseLocation()
+			width := 1 << uint(r.Intn(4))
+			if index+width > len(data) {
+				width = 1
+			}
+			storeInt(data[index:], r.randInt(uint64(width*8)), width)
+		}
+	}
+	// Keep checksums valid so that the kernel looks past them, but sometimes test checksum verification.
+	if fs != nil && !r.oneOf(10) {
+		fs.Fixup(data)
 	}
 	return image.Compress(data), false
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMutationFlags(t *testing.T) {
@@ -243,6 +244,34 @@ func TestMutateRandom(t *testing.T) {
 	})
 }
 
+func TestMutatePrefix(t *testing.T) {
+	target, rs, iters := initTest(t)
+	ct := target.DefaultChoiceTable()
+	var corpus []*Prog
+	for i := 0; i < 10; i++ {
+		corpus = append(corpus, target.Generate(rs, 10, ct))
+	}
+	opts := DefaultMutateOpts
+	for i := 0; i < iters; i++ {
+		base := target.Generate(rs, 5, ct)
+		data0 := base.Serialize()
+		p := base.Clone()
+		p.Calls = append(p.Calls, target.Generate(rs, 5, ct).Calls...)
+		opts.Prefix = len(base.Calls)
+		for try := 0; try < 10; try++ {
+			p.MutateWithOpts(rs, 20, ct, nil, corpus, opts)
+		}
+		prefix := p.Clone()
+		for i := len(prefix.Calls) - 1; i >= len(base.Calls); i-- {
+			prefix.RemoveCall(i)
+		}
+		if data := prefix.Serialize(); !bytes.Equal(data0, data) {
+			t.Fatalf("prefix changed after mutate\noriginal:\n%s\n\nnew:\n%s\n\nfull:\n%s",
+				data0, data, p.Serialize())
+		}
+	}
+}
+
 func TestMutateCorpus(t *testing.T) {
 	target, rs, iters := initTest(t)
 	ct := target.DefaultChoiceTable()
@@ -479,3 +508,95 @@ func BenchmarkStoreLoadInt(b *testing.B) {
 }
 
 var sink interface{}
+
+func TestMutateCompat(t *testing.T) {
+	target := initTargetTest(t, "linux", "amd64")
+	p, err := target.Deserialize([]byte(`
+r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x0, 0x0)
+syz_open_procfs(0x0, &(0x7f0000000040)='status\x00')
+close(r0) (compat)
+`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &mutator{p: p, noMutate: map[int]bool{target.SyscallMap["close"].ID: true}}
+	// The program already uses the compat entry, so it's switched back to the native one,
+	// but calls that must not be mutated keep their props.
+	ctx.toggleCompat()
+	assert.Equal(t, []bool{false, false, true}, compatProps(p))
+	ctx.noMutate = nil
+	ctx.toggleCompat()
+	assert.Equal(t, []bool{false, false, false}, compatProps(p))
+	ctx.toggleCompat()
+	// syz_open_procfs has no compat entry.
+	assert.Equal(t, []bool{true, false, true}, compatProps(p))
+	assert.NoError(t, p.validate())
+
+	p.Calls[1].Props.Compat = true
+	assert.ErrorContains(t, p.validate(), "compat is not supported")
+}
+
+func compatProps(p *Prog) []bool {
+	var ret []bool
+	for _, c := range p.Calls {
+		ret = append(ret, c.Props.Compat)
+	}
+	return ret
+}
+
+func TestMutateUring(t *testing.T) {
+	target := initTargetTest(t, "linux", "amd64")
+	p, err := target.Deserialize([]byte(`
+r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x0, 0x0)
+ioctl(r0, 0x5401, &(0x7f0000000040))
+read(r0, &(0x7f0000000080)=""/16, 0x10) (compat)
+close(r0)
+`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &mutator{p: p}
+	// ioctl has no io_uring equivalent, and compat calls are not switched.
+	ctx.toggleUring()
+	assert.Equal(t, []bool{true, false, false, true}, uringProps(p))
+	assert.NoError(t, p.validate())
+	// Calls submitted via io_uring are not switched to the compat entry.
+	ctx.toggleCompat()
+	assert.Equal(t, []bool{false, false, false, false}, compatProps(p))
+	ctx.toggleCompat()
+	assert.Equal(t, []bool{false, true, true, false}, compatProps(p))
+	assert.NoError(t, p.validate())
+	ctx.toggleUring()
+	assert.Equal(t, []bool{false, false, false, false}, uringProps(p))
+
+	p.Calls[1].Props.Uring = true
+	assert.ErrorContains(t, p.validate(), "uring is not supported")
+	p.Calls[1].Props.Uring = false
+	p.Calls[2].Props.Uring = true
+	assert.ErrorContains(t, p.validate(), "uring is not compatible with compat")
+}
+
+func TestMutateUringCompatDisabled(t *testing.T) {
+	target, rs, iters := initTest(t)
+	ct := target.DefaultChoiceTable()
+	for i := 0; i < iters; i++ {
+		p := target.Generate(rs, 10, ct)
+		p.Mutate(rs, 10, ct, nil, nil)
+		for _, c := range p.Calls {
+			if c.Props.Uring {
+				t.Fatalf("mutation enabled uring without MutateOpts.UringCalls:\n%s", p.Serialize())
+			}
+			if c.Props.Compat {
+				t.Fatalf("mutation enabled compat without MutateOpts.CompatCalls:\n%s", p.Serialize())
+			}
+		}
+	}
+}
+
+func uringProps(p *Prog) []bool {
+	var ret []bool
+	for _, c := range p.Calls {
+		ret = append(ret, c.Props.Uring)
+	}
+	return ret
+}
@@ -32,9 +32,67 @@ func (p *Prog) CallName(call int) string {
 // IMPORTANT: keep the exact values of "key" tag for existing props unchanged,
 // otherwise the backwards compatibility would be broken.
 type CallProps struct {
-	FailNth int  `key:"fail_nth"`
-	Async   bool `key:"async"`
-	Rerun   int  `key:"rerun"`
+	FailNth  int  `key:"fail_nth"`
+	Async    bool `key:"async"`
+	Rerun    int  `key:"rerun"`
+	Role     int  `key:"role"`
+	TimeJump int  `key:"time_jump"`
+	// The call is executed via the compat (32-bit) syscall entry, see Syscall.CompatNR.
+	Compat bool `key:"compat"`
+	// The machine is suspended and resumed right before the call, see Suspend* values.
+	Suspend int `key:"suspend"`
+	// The call is submitted as the equivalent io_uring operation instead of the syscall, see Syscall.Uring.
+	Uring bool `key:"uring"`
+}
+
+// Process roles (values of CallProps.Role) describe in which process the call is executed.
+const (
+	// The call is executed by the test process itself (the default).
+	RoleMain = iota
+	// The call is executed in a forked child process of the test process.
+	RoleChild
+	// The call is executed in a forked child process that dropped privileges (runs as nobody).
+	RoleUnprivileged
+	roleCount
+)
+
+// Time jumps (values of CallProps.TimeJump) change the clocks right before the call is executed,
+// they allow to reach timer, timeout and expiry logic that triggers only around time discontinuities.
+// CLOCK_REALTIME jumps are reverted right after the call. Since they are VM-wide,
+// they are done only when programs are executed in a single proc, otherwise they are ignored.
+const (
+	TimeJumpNone = iota
+	// CLOCK_REALTIME is moved an hour forward.
+	TimeJumpForward
+	// CLOCK_REALTIME is moved an hour back.
+	TimeJumpBackward
+	// CLOCK_REALTIME is set a second before the 32-bit time_t overflow (2038-01-19 03:14:07 UTC).
+	TimeJumpY2038
+	// The call is executed in a forked child process in a new time namespace
+	// with CLOCK_MONOTONIC and CLOCK_BOOTTIME moved a day forward.
+	TimeJumpNamespace
+	timeJumpCount
+)
+
+// Suspend/resume cycles (values of CallProps.Suspend) are done right before the call is executed,
+// concurrently executing calls race with the suspend and resume of devices.
+// The machine is woken up by the RTC alarm.
+const (
+	SuspendNone = iota
+	// Suspend-to-idle (s2idle, "freeze" in /sys/power/state).
+	SuspendIdle
+	// Suspend-to-RAM ("mem" in /sys/power/state, the actual mode depends on /sys/power/mem_sleep).
+	SuspendMem
+	suspendCount
+)
+
+// ProcRole returns the process role the call is actually executed in:
+// calls with TimeJumpNamespace are executed in a child process as well.
+func (props CallProps) ProcRole() int {
+	if props.Role == RoleMain && props.TimeJump == TimeJumpNamespace {
+		return RoleChild
+	}
+	return props.Role
 }
 
 type Call struct {
@@ -43,6 +101,9 @@ type Call struct {
 	Ret     *ResultArg
 	Props   CallProps
 	Comment string
+
+	// Mutation ops that produced argument values (see MutationOps).
+	provenance map[Arg]MutationOp
 }
 
 func MakeCall(meta *Syscall, args []Arg) *Call {
@@ -471,6 +532,63 @@ func (p *Prog) RemoveCall(idx int) {
 	p.Calls = p.Calls[:len(p.Calls)-1]
 }
 
+// isRequired returns whether the call idx is the only instance of a call
+// that is required by some later call (see Syscall.Requires).
+func (p *Prog) isRequired(idx int) bool {
+	meta := p.Calls[idx].Meta
+	for i := 0; i < idx; i++ {
+		if p.Calls[i].Meta == meta {
+			return false
+		}
+	}
+	for _, c := range p.Calls[idx+1:] {
+		if c.Meta == meta {
+			return false
+		}
+		for _, req := range c.Meta.requiredCalls {
+			if req == meta {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trimCalls removes calls at idx until the program has at most ncalls calls.
+// Calls required by later calls are preserved, preceding calls are removed instead.
+// Calls before lo are never removed.
+func (p *Prog) trimCalls(lo, idx, ncalls int) {
+	for len(p.Calls) > ncalls {
+		i := idx
+		for i > lo && p.isRequired(i) {
+			i--
+		}
+		if p.isRequired(i) {
+			// The last call is never required.
+			i = len(p.Calls) - 1
+		}
+		p.RemoveCall(i)
+	}
+}
+
+// trimCallsBefore removes calls before idx until the program has at most ncalls calls
+// and returns the new index of the call idx. Calls required by later calls are preserved
+// unless there is nothing else to remove before idx. Calls before lo are never removed.
+func (p *Prog) trimCallsBefore(lo, idx, ncalls int) int {
+	for len(p.Calls) > ncalls && idx > lo {
+		i := idx - 1
+		for i >= lo && p.isRequired(i) {
+			i--
+		}
+		if i < lo {
+			i = idx - 1
+		}
+		p.RemoveCall(i)
+		idx--
+	}
+	return idx
+}
+
 func (p *Prog) sanitizeFix() {
 	if err := p.sanitize(true); err != nil {
 		panic(err)
@@ -0,0 +1,230 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import "math"
+
+// MutationOp identifies the class of mutation that produced an argument value.
+type MutationOp int
+
+const (
+	MutationNone MutationOp = iota
+	MutationSquash
+	MutationSplice
+	MutationInsert
+	MutationInt
+	MutationFlags
+	MutationLen
+	MutationResource
+	MutationVma
+	MutationProc
+	MutationBuffer
+	MutationArray
+	MutationPtr
+	MutationStruct
+	MutationUnion
+	MutationConst
+	MutationCount
+)
+
+var mutationOpNames = [MutationCount]string{
+	MutationNone:     "none",
+	MutationSquash:   "squash",
+	MutationSplice:   "splice",
+	MutationInsert:   "insert",
+	MutationInt:      "int",
+	MutationFlags:    "flags",
+	MutationLen:      "len",
+	MutationResource: "resource",
+	MutationVma:      "vma",
+	MutationProc:     "proc",
+	MutationBuffer:   "buffer",
+	MutationArray:    "array",
+	MutationPtr:      "ptr",
+	MutationStruct:   "struct",
+	MutationUnion:    "union",
+	MutationConst:    "const",
+}
+
+func (op MutationOp) String() string {
+	if op < 0 || op >= MutationCount {
+		return "unknown"
+	}
+	return mutationOpNames[op]
+}
+
+// argMutationOp returns the mutation class used for the argument by mutateArg.
+func argMutationOp(arg Arg) MutationOp {
+	switch arg.Type().(type) {
+	case *IntType:
+		return MutationInt
+	case *FlagsType:
+		return MutationFlags
+	case *LenType:
+		return MutationLen
+	case *ResourceType:
+		return MutationResource
+	case *VmaType:
+		return MutationVma
+	case *ProcType:
+		return MutationProc
+	case *BufferType:
+		return MutationBuffer
+	case *ArrayType:
+		return MutationArray
+	case *PtrType:
+		return MutationPtr
+	case *StructType:
+		return MutationStruct
+	case *UnionType:
+		return MutationUnion
+	case *ConstType, *CsumType:
+		return MutationConst
+	}
+	return MutationNone
+}
+
+// setProvenance records that the argument value was produced by the mutation op.
+// The latest mutation of an argument wins.
+func (c *Call) setProvenance(arg Arg, op MutationOp) {
+	if c.provenance == nil {
+		c.provenance = make(map[Arg]MutationOp)
+	}
+	c.provenance[arg] = op
+}
+
+// setCallsProvenance marks all top-level arguments of the calls as produced by the op.
+func setCallsProvenance(calls []*Call, op MutationOp) {
+	for _, c := range calls {
+		for _, arg := range c.Args {
+			c.setProvenance(arg, op)
+		}
+	}
+}
+
+// MutationOps returns the number of arguments in the program produced by each mutation op.
+// Only arguments that are still reachable from the program are accounted.
+func (p *Prog) MutationOps() map[MutationOp]int {
+	var res map[MutationOp]int
+	for _, c := range p.Calls {
+		if len(c.provenance) == 0 {
+			continue
+		}
+		ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+			if op, ok := c.provenance[arg]; ok {
+				if res == nil {
+					res = make(map[MutationOp]int)
+				}
+				res[op]++
+			}
+		})
+	}
+	return res
+}
+
+// ResetProvenance forgets the mutation ops that produced the program arguments.
+// It's called when the program enters the corpus, so that programs mutated from it
+// are attributed only to their own mutations rather than to the whole lineage.
+func (p *Prog) ResetProvenance() {
+	for _, c := range p.Calls {
+		c.provenance = nil
+	}
+}
+
+// WeightByCrashes returns opts with the weights of the mutation operators increased
+// proportionally to the share of crashes attributed to them (up to 2x).
+// crashes is the number of crashes attributed to each mutation op (see MutationOps).
+func (o MutateOpts) WeightByCrashes(crashes map[MutationOp]int) MutateOpts {
+	var squash, splice, insert, arg, total int
+	for op, n := range crashes {
+		switch op {
+		case MutationNone:
+			continue
+		case MutationSquash:
+			squash += n
+		case MutationSplice:
+			splice += n
+		case MutationInsert:
+			insert += n
+		default:
+			arg += n
+		}
+		total += n
+	}
+	if total == 0 {
+		return o
+	}
+	scale := func(weight, n int) int {
+		return weight + weight*n/total
+	}
+	o.SquashWeight = scale(o.SquashWeight, squash)
+	o.SpliceWeight = scale(o.SpliceWeight, splice)
+	o.InsertWeight = scale(o.InsertWeight, insert)
+	o.MutateArgWeight = scale(o.MutateArgWeight, arg)
+	return o
+}
+
+// WeightByYield returns opts with the weights of the mutation operators scaled by their yield
+// relative to the average yield (from 0.5x to 2x), where yield is the share of executions of programs
+// produced by the op that were rewarded (e.g. found new signal). execs and rewards are accounted
+// per mutation op (see MutationOps). Operators with too few executions keep their weight,
+// and the lower bound makes sure that no operator is starved.
+func (o MutateOpts) WeightByYield(execs, rewards map[MutationOp]int) MutateOpts {
+	const minExecs = 1000
+	var classExecs, classRewards [4]int
+	var totalExecs, totalRewards int
+	for op := range execs {
+		class := 3
+		switch op {
+		case MutationNone:
+			continue
+		case MutationSquash:
+			class = 0
+		case MutationSplice:
+			class = 1
+		case MutationInsert:
+			class = 2
+		}
+		classExecs[class] += execs[op]
+		classRewards[class] += rewards[op]
+		totalExecs += execs[op]
+		totalRewards += rewards[op]
+	}
+	if totalRewards == 0 {
+		return o
+	}
+	avg := float64(totalRewards) / float64(totalExecs)
+	scale := func(weight, class int) int {
+		if classExecs[class] < minExecs {
+			return weight
+		}
+		factor := float64(classRewards[class]) / float64(classExecs[class]) / avg
+		factor = min(max(factor, 0.5), 2)
+		return max(int(math.Round(float64(weight)*factor)), 1)
+	}
+	o.SquashWeight = scale(o.SquashWeight, 0)
+	o.SpliceWeight = scale(o.SpliceWeight, 1)
+	o.InsertWeight = scale(o.InsertWeight, 2)
+	o.MutateArgWeight = scale(o.MutateArgWeight, 3)
+	return o
+}
+
+// cloneProvenance transfers provenance of c to its clone c1.
+func cloneProvenance(c, c1 *Call) {
+	if len(c.provenance) == 0 {
+		return
+	}
+	var args, args1 []Arg
+	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+		args = append(args, arg)
+	})
+	ForeachArg(c1, func(arg Arg, _ *ArgCtx) {
+		args1 = append(args1, arg)
+	})
+	for i, arg := range args {
+		if op, ok := c.provenance[arg]; ok {
+			c1.setProvenance(args1[i], op)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutationProvenance(t *testing.T) {
+	target, rs, iters := initTest(t)
+	ct := target.DefaultChoiceTable()
+	seen := make(map[MutationOp]bool)
+	for i := 0; i < iters; i++ {
+		p := target.Generate(rs, 10, ct)
+		assert.Empty(t, p.MutationOps())
+		corpus := []*Prog{p.Clone()}
+		for try := 0; try < 10; try++ {
+			p.Mutate(rs, 20, ct, nil, corpus)
+		}
+		ops := p.MutationOps()
+		for op, n := range ops {
+			assert.Positive(t, n)
+			assert.Less(t, op, MutationCount)
+			assert.NotEqual(t, "unknown", op.String())
+			seen[op] = true
+		}
+		assert.Equal(t, ops, p.Clone().MutationOps())
+		p.ResetProvenance()
+		assert.Empty(t, p.MutationOps())
+	}
+	for _, op := range []MutationOp{MutationSplice, MutationInsert, MutationInt, MutationFlags} {
+		assert.True(t, seen[op], "%v", op)
+	}
+}
+
+func TestWeightByCrashes(t *testing.T) {
+	opts := DefaultMutateOpts
+	assert.Equal(t, opts, opts.WeightByCrashes(nil))
+	weighted := opts.WeightByCrashes(map[MutationOp]int{
+		MutationSplice: 2,
+		MutationInt:    1,
+		MutationFlags:  1,
+	})
+	assert.Equal(t, opts.SquashWeight, weighted.SquashWeight)
+	assert.Equal(t, opts.SpliceWeight*3/2, weighted.SpliceWeight)
+	assert.Equal(t, opts.InsertWeight, weighted.InsertWeight)
+	assert.Equal(t, opts.MutateArgWeight*3/2, weighted.MutateArgWeight)
+	assert.Equal(t, opts.RemoveCallWeight, weighted.RemoveCallWeight)
+}
+
+func TestWeightByYield(t *testing.T) {
+	opts := DefaultMutateOpts
+	assert.Equal(t, opts, opts.WeightByYield(nil, nil))
+	weighted := opts.WeightByYield(map[MutationOp]int{
+		MutationSquash: 100,
+		MutationSplice: 10000,
+		MutationInsert: 10000,
+		MutationInt:    5000,
+		MutationFlags:  5000,
+	}, map[MutationOp]int{
+		MutationSplice: 1000,
+		MutationInsert: 10,
+		MutationInt:    500,
+		MutationFlags:  1500,
+	})
+	// Squash has too few executions to judge.
+	assert.Equal(t, opts.SquashWeight, weighted.SquashWeight)
+	// Average yield is 0.1.
+	assert.Equal(t, opts.SpliceWeight, weighted.SpliceWeight)
+	assert.Equal(t, opts.InsertWeight/2, weighted.InsertWeight)
+	assert.Equal(t, opts.MutateArgWeight*2, weighted.MutateArgWeight)
+	assert.Equal(t, opts.RemoveCallWeight, weighted.RemoveCallWeight)
+}
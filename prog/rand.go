@@ -517,6 +517,9 @@ func createTargetIfuzzConfig(target *Target) *ifuzz.Config {
 	case "arm64":
 		cfg.Mode = ifuzz.ModeLong64
 		cfg.Arch = ifuzz.ArchArm64
+	case "riscv64":
+		cfg.Mode = ifuzz.ModeLong64
+		cfg.Arch = ifuzz.ArchRiscv64
 	default:
 		return nil
 	}
@@ -561,6 +564,9 @@ func createIfuzzConfig(kind TextKind) *ifuzz.Config {
 	case TextArm64:
 		cfg.Mode = ifuzz.ModeLong64
 		cfg.Arch = ifuzz.ArchArm64
+	case TextRiscv64:
+		cfg.Mode = ifuzz.ModeLong64
+		cfg.Arch = ifuzz.ArchRiscv64
 	default:
 		panic(fmt.Sprintf("unknown text kind: %v", kind))
 	}
@@ -581,8 +587,9 @@ func (r *randGen) generateCall(s *state, p *Prog, insertionPoint int) []*Call {
 	if insertionPoint > 0 {
 		// Choosing the base call is based on the insertion point of the new calls sequence.
 		insertionCall := p.Calls[r.Intn(insertionPoint)].Meta
-		if !insertionCall.Attrs.NoGenerate {
-			// We must be careful not to bias towards a non-generatable call.
+		if s.ct.Generatable(insertionCall.ID) {
+			// We must be careful not to bias towards a non-generatable call
+			// (no_generate calls, or calls not enabled in the choice table).
 			biasCall = insertionCall.ID
 		}
 	}
@@ -598,13 +605,42 @@ func (r *randGen) generateParticularCall(s *state, meta *Syscall) (calls []*Call
 	if meta.Attrs.NoGenerate {
 		panic(fmt.Sprintf("generating no_generate call: %v", meta.Name))
 	}
+	calls = r.generateRequiredCalls(s, meta)
 	c := MakeCall(meta, nil)
-	c.Args, calls = r.generateArgs(s, meta.Args, DirIn)
+	args, argCalls := r.generateArgs(s, meta.Args, DirIn)
+	c.Args, calls = args, append(calls, argCalls...)
 	moreCalls, _ := r.patchConditionalFields(c, s)
 	r.target.assignSizesCall(c)
+	if meta.Attrs.ProcRoles && r.oneOf(4) {
+		c.Props.Role = RoleChild + r.Intn(roleCount-RoleChild)
+	}
+	if meta.Attrs.TimeJumps && r.oneOf(4) {
+		c.Props.TimeJump = TimeJumpForward + r.Intn(timeJumpCount-TimeJumpForward)
+		if c.Props.TimeJump == TimeJumpNamespace && c.Props.Role != RoleMain {
+			c.Props.TimeJump = TimeJumpNone
+		}
+	}
 	return append(append(calls, moreCalls...), c)
 }
 
+// generateRequiredCalls generates calls that must precede meta (see Syscall.Requires),
+// but are not present in the program yet.
+func (r *randGen) generateRequiredCalls(s *state, meta *Syscall) []*Call {
+	var calls []*Call
+	for _, req := range meta.requiredCalls {
+		if s.calls[req] || !s.ct.Generatable(req.ID) {
+			// Callers are expected to disable calls with disabled requirements
+			// (see TransitivelyEnabledCalls), but we may still get here e.g. in tests.
+			continue
+		}
+		// The calls are added to the program by the caller, but we note them right away
+		// so that they are not generated again for arguments of this call.
+		s.calls[req] = true
+		calls = append(calls, r.generateParticularCall(s, req)...)
+	}
+	return calls
+}
+
 // GenerateAllSyzProg generates a program that contains all pseudo syz_ calls for testing.
 func (target *Target) GenerateAllSyzProg(rs rand.Source) *Prog {
 	p := &Prog{
@@ -644,8 +680,14 @@ func (target *Target) PseudoSyscalls() []*Syscall {
 
 // GenSampleProg generates a single sample program for the call.
 func (target *Target) GenSampleProg(meta *Syscall, rs rand.Source) *Prog {
+	return target.GenCallProg(meta, rs, target.DefaultChoiceTable())
+}
+
+// GenCallProg generates a single program for the call, calls that create resources
+// for the call are chosen only among the calls enabled in the choice table.
+func (target *Target) GenCallProg(meta *Syscall, rs rand.Source, ct *ChoiceTable) *Prog {
 	r := newRand(target, rs)
-	s := newState(target, target.DefaultChoiceTable(), nil)
+	s := newState(target, ct, nil)
 	p := &Prog{
 		Target: target,
 	}
@@ -978,7 +1020,7 @@ func (r *randGen) resourceCentric(s *state, t *ResourceType, dir Dir) (arg Arg,
 				}
 			}
 		})
-		if !includeCall {
+		if !includeCall && !p.isRequired(idx) {
 			p.RemoveCall(idx)
 		} else {
 			for _, res := range newResources {
@@ -239,3 +239,79 @@ func TestNoGenerate(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateProcRoles(t *testing.T) {
+	target, rs, _ := initRandomTargetTest(t, "test", "64")
+	meta := target.SyscallMap["test$proc_roles"]
+	ct := target.BuildChoiceTable(nil, map[*Syscall]bool{meta: true})
+	roles := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		p := target.Generate(rs, 5, ct)
+		for _, c := range p.Calls {
+			roles[c.Props.Role]++
+		}
+		if err := p.validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for role := RoleMain; role < roleCount; role++ {
+		if roles[role] == 0 {
+			t.Errorf("role %v was never generated: %v", role, roles)
+		}
+	}
+}
+
+func TestGenerateTimeJumps(t *testing.T) {
+	target, rs, _ := initRandomTargetTest(t, "test", "64")
+	meta := target.SyscallMap["test$time_jumps"]
+	ct := target.BuildChoiceTable(nil, map[*Syscall]bool{meta: true})
+	jumps := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		p := target.Generate(rs, 5, ct)
+		for _, c := range p.Calls {
+			jumps[c.Props.TimeJump]++
+		}
+		if err := p.validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for jump := TimeJumpNone; jump < timeJumpCount; jump++ {
+		if jumps[jump] == 0 {
+			t.Errorf("time jump %v was never generated: %v", jump, jumps)
+		}
+	}
+}
+
+func TestGenerateRequiredCalls(t *testing.T) {
+	target, rs, _ := initRandomTargetTest(t, "test", "64")
+	enabled := make(map[*Syscall]bool)
+	for _, name := range []string{"test$requires_init", "test$requires_setup", "test$requires_run"} {
+		enabled[target.SyscallMap[name]] = true
+	}
+	ct := target.BuildChoiceTable(nil, enabled)
+	for i := 0; i < 100; i++ {
+		p := target.Generate(rs, 5, ct)
+		checkRequiredCalls(t, p)
+		for j := 0; j < 10; j++ {
+			p.Mutate(rs, 5, ct, nil, []*Prog{p.Clone()})
+			checkRequiredCalls(t, p)
+		}
+		p1, _ := Minimize(p, -1, MinimizeParams{}, func(p1 *Prog, _ int) bool {
+			checkRequiredCalls(t, p1)
+			return len(p1.Calls) != 0
+		})
+		checkRequiredCalls(t, p1)
+	}
+}
+
+func checkRequiredCalls(t *testing.T, p *Prog) {
+	seen := make(map[*Syscall]bool)
+	for _, c := range p.Calls {
+		for _, req := range c.Meta.requiredCalls {
+			if !seen[req] {
+				t.Fatalf("%v is not preceded by %v:\n%s", c.Meta.Name, req.Name, p.Serialize())
+			}
+		}
+		seen[c.Meta] = true
+	}
+}
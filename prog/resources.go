@@ -186,6 +186,11 @@ func (target *Target) transitivelyEnabled(enabled map[*Syscall]bool) (map[*Sysca
 					continue nextCall
 				}
 			}
+			for _, req := range c.requiredCalls {
+				if !supported[req] {
+					continue nextCall
+				}
+			}
 			supported[c] = true
 			for _, res := range c.createsResources {
 				for _, kind := range res.Kind {
@@ -225,9 +230,51 @@ func (target *Target) TransitivelyEnabledCalls(enabled map[*Syscall]bool) (map[*
 			disabled[c] = fmt.Sprintf("%v %v", res.Name, ctors[res.Name])
 			break
 		}
+		if disabled[c] != "" {
+			continue
+		}
+		for _, req := range c.requiredCalls {
+			if !supported[req] {
+				disabled[c] = fmt.Sprintf("required call %v is disabled", req.Name)
+				break
+			}
+		}
 	}
 	if len(enabled) != len(supported)+len(disabled) {
 		panic("lost syscalls")
 	}
 	return supported, disabled
 }
+
+// WithResourceCtors extends calls with calls from enabled that can (transitively) create
+// input resources of calls and with the calls they require. Only precise constructors are considered.
+// The result can be used to generate programs focused on the given calls.
+func (target *Target) WithResourceCtors(calls, enabled map[*Syscall]bool) map[*Syscall]bool {
+	ret := make(map[*Syscall]bool)
+	var queue []*Syscall
+	for c := range calls {
+		if enabled[c] {
+			ret[c] = true
+			queue = append(queue, c)
+		}
+	}
+	for len(queue) != 0 {
+		c := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, res := range c.inputResources {
+			for _, ctor := range target.calcResourceCtors(res, true) {
+				if enabled[ctor.Call] && !ret[ctor.Call] {
+					ret[ctor.Call] = true
+					queue = append(queue, ctor.Call)
+				}
+			}
+		}
+		for _, req := range c.requiredCalls {
+			if enabled[req] && !ret[req] {
+				ret[req] = true
+				queue = append(queue, req)
+			}
+		}
+	}
+	return ret
+}
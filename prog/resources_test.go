@@ -103,6 +103,24 @@ func TestTransitivelyEnabledCallsLinux(t *testing.T) {
 	}
 }
 
+func TestTransitivelyEnabledCallsRequires(t *testing.T) {
+	t.Parallel()
+	target, err := GetTarget("test", "64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup := target.SyscallMap["test$requires_setup"]
+	run := target.SyscallMap["test$requires_run"]
+	trans, disabled := target.TransitivelyEnabledCalls(map[*Syscall]bool{setup: true, run: true})
+	if len(trans) != 0 {
+		t.Fatalf("enabled calls with disabled requirements: %v", trans)
+	}
+	if disabled[setup] != "required call test$requires_init is disabled" ||
+		disabled[run] != "required call test$requires_setup is disabled" {
+		t.Fatalf("bad disabled reasons: %v", disabled)
+	}
+}
+
 func TestGetInputResources(t *testing.T) {
 	expectedRequiredResources := map[string]bool{
 		"required_res1": false,
@@ -130,6 +148,30 @@ func TestGetInputResources(t *testing.T) {
 	}
 }
 
+func TestWithResourceCtors(t *testing.T) {
+	t.Parallel()
+	target, err := GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := make(map[*Syscall]bool)
+	for _, c := range target.Syscalls {
+		enabled[c] = true
+	}
+	calls := map[*Syscall]bool{target.SyscallMap["read"]: true}
+	focus := target.WithResourceCtors(calls, enabled)
+	assert.True(t, focus[target.SyscallMap["read"]])
+	assert.True(t, focus[target.SyscallMap["openat"]])
+	assert.False(t, focus[target.SyscallMap["write"]])
+	// All input resources of the focused calls can be created.
+	supported, disabled := target.TransitivelyEnabledCalls(focus)
+	assert.Empty(t, disabled)
+	assert.Equal(t, len(focus), len(supported))
+
+	delete(enabled, target.SyscallMap["read"])
+	assert.Empty(t, target.WithResourceCtors(calls, enabled))
+}
+
 func TestClockGettime(t *testing.T) {
 	t.Parallel()
 	target, err := GetTarget("linux", "amd64")
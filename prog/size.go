@@ -180,6 +180,12 @@ func (target *Target) assignSizesCall(c *Call) {
 	target.assignSizesArray(c.Args, c.Meta.Args, nil)
 }
 
+// AssignSizesCall recalculates all size arguments of the call.
+// It needs to be called after call arguments were changed outside of the prog package.
+func (target *Target) AssignSizesCall(c *Call) {
+	target.assignSizesCall(c)
+}
+
 func (r *randGen) mutateSize(arg *ConstArg, parent []Arg, fields []Field) bool {
 	typ := arg.Type().(*LenType)
 	elemSize := typ.BitSize / 8
@@ -28,6 +28,7 @@ type Target struct {
 	Resources []*ResourceDesc
 	Consts    []ConstValue
 	Flags     []FlagDesc
+	Warnings  []DescWarning
 
 	// MakeDataMmap creates calls that mmaps target data memory range.
 	MakeDataMmap func() []*Call
@@ -169,6 +170,16 @@ func (target *Target) initTarget() {
 		c.ID = i
 		target.SyscallMap[c.Name] = c
 	}
+	for _, c := range target.Syscalls {
+		c.requiredCalls = nil
+		for _, name := range c.Requires {
+			req := target.SyscallMap[name]
+			if req == nil {
+				panic(fmt.Sprintf("call %v requires unknown call %v", c.Name, name))
+			}
+			c.requiredCalls = append(c.requiredCalls, req)
+		}
+	}
 
 	target.FlagsMap = make(map[string][]string)
 	for _, c := range target.Flags {
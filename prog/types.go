@@ -12,12 +12,20 @@ import (
 type Syscall struct {
 	ID          int
 	NR          uint64 // kernel syscall number
+	CompatNR    uint64 // syscall number of the compat (32-bit) entry, 0 if the call can't be executed via it
+	Uring       bool   // the call has an equivalent io_uring operation and can be submitted via io_uring
 	Name        string
 	CallName    string
 	MissingArgs int // number of trailing args that should be zero-filled
 	Args        []Field
 	Ret         Type
 	Attrs       SyscallAttrs
+	// Errnos the call is expected to fail with (sorted), set with the errnos call attribute.
+	// Used to detect broken descriptions, nil if not specified.
+	Errnos []uint64
+	// Calls that must precede this call in a program, set with the requires call attribute.
+	// Generation inserts them before the call if the program does not contain them yet.
+	Requires []string
 
 	// Resources that are required for this call to be generated (in/inout).
 	inputResources []*ResourceDesc
@@ -25,6 +33,8 @@ type Syscall struct {
 	createsResources []*ResourceDesc
 	// Both inputs and output resources (including no_generate).
 	usesResources []*ResourceDesc
+	// Resolved Requires.
+	requiredCalls []*Syscall
 }
 
 // SyscallAttrs represents call attributes in syzlang.
@@ -45,6 +55,8 @@ type SyscallAttrs struct {
 	NoGenerate    bool
 	NoMinimize    bool
 	RemoteCover   bool
+	ProcRoles     bool
+	TimeJumps     bool
 }
 
 // MaxArgs is maximum number of syscall arguments.
@@ -570,6 +582,7 @@ const (
 	TextX86bit64
 	TextArm64
 	TextPpc64
+	TextRiscv64
 )
 
 type BufferType struct {
@@ -771,6 +784,14 @@ type ConstValue struct {
 	Value uint64
 }
 
+// DescWarning is a non-fatal problem found in descriptions during compilation
+// (e.g. a syscall disabled on the arch due to a missing const).
+type DescWarning struct {
+	Pos  string
+	Kind string
+	Msg  string
+}
+
 type TypeCtx struct {
 	Meta     *Syscall
 	Dir      Dir
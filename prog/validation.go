@@ -73,6 +73,31 @@ func (ctx *validCtx) validateCall(c *Call) error {
 	if c.Props.Rerun > 0 && c.Props.FailNth > 0 {
 		return fmt.Errorf("rerun > 0 && fail_nth > 0")
 	}
+	if c.Props.Role < RoleMain || c.Props.Role >= roleCount {
+		return fmt.Errorf("bad role %v", c.Props.Role)
+	}
+	if c.Props.TimeJump < TimeJumpNone || c.Props.TimeJump >= timeJumpCount {
+		return fmt.Errorf("bad time_jump %v", c.Props.TimeJump)
+	}
+	if c.Props.Suspend < SuspendNone || c.Props.Suspend >= suspendCount {
+		return fmt.Errorf("bad suspend %v", c.Props.Suspend)
+	}
+	if c.Props.Compat && c.Meta.CompatNR == 0 {
+		return fmt.Errorf("compat is not supported for the call")
+	}
+	if c.Props.Uring && !c.Meta.Uring {
+		return fmt.Errorf("uring is not supported for the call")
+	}
+	if c.Props.Uring && c.Props.Compat {
+		return fmt.Errorf("uring is not compatible with compat")
+	}
+	if c.Props.TimeJump == TimeJumpNamespace && c.Props.Role != RoleMain {
+		return fmt.Errorf("time_jump %v is not compatible with role", c.Props.TimeJump)
+	}
+	// Fault injection and reruns are done in the test process and can't be combined with a child process.
+	if c.Props.ProcRole() != RoleMain && (c.Props.FailNth > 0 || c.Props.Rerun > 0) {
+		return fmt.Errorf("role %v is not compatible with fail_nth/rerun", c.Props.ProcRole())
+	}
 	if len(c.Args) != len(c.Meta.Args) {
 		return fmt.Errorf("wrong number of arguments, want %v, got %v",
 			len(c.Meta.Args), len(c.Args))
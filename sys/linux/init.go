@@ -110,6 +110,14 @@ func InitTarget(target *prog.Target) {
 		1 << 16, // gVisor's MaxFilenameLen
 	}
 
+	// On 32-bit arches 64-bit arguments (e.g. offsets) are split across registers,
+	// executor translates only 64-bit arguments into io_uring operations.
+	if target.PtrSize == 8 {
+		for _, c := range target.Syscalls {
+			c.Uring = uringCalls[c.CallName]
+		}
+	}
+
 	if target.Arch == runtime.GOARCH {
 		KCOV_INIT_TRACE = uintptr(target.GetConst("KCOV_INIT_TRACE"))
 		KCOV_ENABLE = uintptr(target.GetConst("KCOV_ENABLE"))
@@ -120,6 +128,54 @@ func InitTarget(target *prog.Target) {
 	}
 }
 
+// Syscalls that have equivalent IORING_OP_* operations (see prog.CallProps.Uring).
+// Keep in sync with uring_prep in executor/common_linux.h.
+var uringCalls = map[string]bool{
+	"read":            true,
+	"write":           true,
+	"pread64":         true,
+	"pwrite64":        true,
+	"readv":           true,
+	"writev":          true,
+	"preadv":          true,
+	"pwritev":         true,
+	"preadv2":         true,
+	"pwritev2":        true,
+	"fsync":           true,
+	"fdatasync":       true,
+	"sync_file_range": true,
+	"fallocate":       true,
+	"fadvise64":       true,
+	"ftruncate":       true,
+	"madvise":         true,
+	"openat":          true,
+	"openat2":         true,
+	"close":           true,
+	"statx":           true,
+	"renameat":        true,
+	"renameat2":       true,
+	"unlinkat":        true,
+	"mkdirat":         true,
+	"symlinkat":       true,
+	"linkat":          true,
+	"setxattr":        true,
+	"fsetxattr":       true,
+	"getxattr":        true,
+	"fgetxattr":       true,
+	"splice":          true,
+	"tee":             true,
+	"epoll_ctl":       true,
+	"socket":          true,
+	"bind":            true,
+	"listen":          true,
+	"connect":         true,
+	"accept":          true,
+	"accept4":         true,
+	"sendmsg":         true,
+	"recvmsg":         true,
+	"shutdown":        true,
+}
+
 var (
 	// This should not be here, but for now we expose this for syz-fuzzer.
 	KCOV_INIT_TRACE    uintptr
@@ -0,0 +1,104 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Cache stores extracted consts across runs, so that re-extraction after editing a few descriptions
+// only compiles the changed files (and does not build the kernel for arches where nothing changed).
+// Entries are keyed by the state of the kernel checkout (shared by all arches) and by the per-file
+// compilation inputs (arch, consts, includes, defines), so any change in these invalidates the entry.
+type Cache struct {
+	dir    string
+	kernel string
+}
+
+type cacheEntry struct {
+	Consts     map[string]uint64
+	Undeclared map[string]bool
+}
+
+// NewCache returns nil if the kernel state can't be identified (e.g. the source dir is not a git checkout).
+func NewCache(dir, sourceDir string, extra ...string) (*Cache, error) {
+	head, err := osutil.RunCmd(time.Minute, sourceDir, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("not a git checkout: %w", err)
+	}
+	// Local modifications to the kernel headers need to invalidate the cache as well.
+	diff, err := osutil.RunCmd(10*time.Minute, sourceDir, "git", "diff", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	pieces := [][]byte{head, diff}
+	for _, s := range extra {
+		pieces = append(pieces, []byte(s))
+	}
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	return &Cache{
+		dir:    dir,
+		kernel: hash.String(pieces...),
+	}, nil
+}
+
+func (c *Cache) key(arch *Arch, info *compiler.ConstInfo) string {
+	var consts []string
+	for _, cnst := range info.Consts {
+		consts = append(consts, cnst.Name)
+	}
+	sort.Strings(consts)
+	var defines []string
+	for name, val := range info.Defines {
+		defines = append(defines, name+"="+val)
+	}
+	sort.Strings(defines)
+	data, err := json.Marshal([]interface{}{
+		arch.target.OS, arch.target.Arch, arch.includeDirs,
+		consts, info.Includes, info.Incdirs, defines,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return hash.String([]byte(c.kernel), data)
+}
+
+func (c *Cache) Get(arch *Arch, info *compiler.ConstInfo) (map[string]uint64, map[string]bool, bool) {
+	if c == nil {
+		return nil, nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, c.key(arch, info)))
+	if err != nil {
+		return nil, nil, false
+	}
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, nil, false
+	}
+	return entry.Consts, entry.Undeclared, true
+}
+
+func (c *Cache) Put(arch *Arch, info *compiler.ConstInfo, consts map[string]uint64, undeclared map[string]bool) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(&cacheEntry{Consts: consts, Undeclared: undeclared})
+	if err != nil {
+		panic(err)
+	}
+	if err := osutil.WriteFile(filepath.Join(c.dir, c.key(arch, info)), data); err != nil {
+		fmt.Printf("failed to write cache: %v\n", err)
+	}
+}
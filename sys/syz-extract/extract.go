@@ -28,6 +28,8 @@ var (
 	flagBuildDir  = flag.String("builddir", "", "path to kernel build dir")
 	flagArch      = flag.String("arch", "", "comma-separated list of arches to generate (all by default)")
 	flagConfig    = flag.String("config", "", "base kernel config file instead of defconfig")
+	flagCacheDir  = flag.String("cachedir", "", "dir to cache extracted consts across runs "+
+		"(requires sourcedir to be a git checkout)")
 )
 
 type Arch struct {
@@ -38,6 +40,7 @@ type Arch struct {
 	build       bool
 	files       []*File
 	configFile  string
+	cache       *Cache
 	err         error
 	done        chan bool
 }
@@ -88,6 +91,15 @@ func main() {
 		tool.Fail(fmt.Errorf("provide path to kernel checkout via -sourcedir " +
 			"flag (or make extract SOURCEDIR)"))
 	}
+	if *flagCacheDir != "" {
+		cache, err := createCache(*flagCacheDir)
+		if err != nil {
+			fmt.Printf("not using the cache: %v\n", err)
+		}
+		for _, arch := range arches {
+			arch.cache = cache
+		}
+	}
 	if err := extractor.prepare(*flagSourceDir, *flagBuild, arches); err != nil {
 		tool.Fail(err)
 	}
@@ -101,21 +113,21 @@ func main() {
 		go worker(extractor, jobC)
 	}
 
-	failed := false
+	// Errors are printed together at the end, otherwise they are lost among the progress output
+	// of other arches that are processed concurrently.
+	var errs []string
 	constFiles := make(map[string]*compiler.ConstFile)
 	for _, arch := range arches {
 		fmt.Printf("generating %v/%v...\n", OS, arch.target.Arch)
 		<-arch.done
 		if arch.err != nil {
-			failed = true
-			fmt.Printf("%v\n", arch.err)
+			errs = append(errs, fmt.Sprintf("%v/%v: %v", OS, arch.target.Arch, arch.err))
 			continue
 		}
 		for _, f := range arch.files {
 			<-f.done
 			if f.err != nil {
-				failed = true
-				fmt.Printf("%v: %v\n", f.name, f.err)
+				errs = append(errs, fmt.Sprintf("%v/%v: %v: %v", OS, arch.target.Arch, f.name, f.err))
 				continue
 			}
 			if constFiles[f.name] == nil {
@@ -136,6 +148,13 @@ func main() {
 		}
 	}
 
+	failed := len(errs) != 0
+	if len(errs) != 0 {
+		fmt.Printf("\nextraction failed for %v arch/file combinations:\n\n", len(errs))
+		for _, err := range errs {
+			fmt.Printf("%v\n", err)
+		}
+	}
 	if !failed && *flagArch == "" {
 		failed = checkUnsupportedCalls(arches)
 	}
@@ -153,17 +172,17 @@ func worker(extractor Extractor, jobC chan interface{}) {
 	for job := range jobC {
 		switch j := job.(type) {
 		case *Arch:
-			infos, err := processArch(extractor, j)
+			pending, err := processArch(extractor, j)
 			j.err = err
 			close(j.done)
-			if j.err == nil {
-				for _, f := range j.files {
-					f.info = infos[filepath.Join("sys", j.target.OS, f.name)]
-					jobC <- f
-				}
+			for _, f := range pending {
+				jobC <- f
 			}
 		case *File:
 			j.consts, j.undeclared, j.err = processFile(extractor, j.arch, j)
+			if j.err == nil && j.info != nil {
+				j.arch.cache.Put(j.arch, j.info, j.consts, j.undeclared)
+			}
 			close(j.done)
 		}
 	}
@@ -279,7 +298,8 @@ func checkUnsupportedCalls(arches []*Arch) bool {
 	return failed
 }
 
-func processArch(extractor Extractor, arch *Arch) (map[string]*compiler.ConstInfo, error) {
+// processArch returns files that need to be extracted, files with cached consts are already done.
+func processArch(extractor Extractor, arch *Arch) ([]*File, error) {
 	errBuf := new(bytes.Buffer)
 	eh := func(pos ast.Pos, msg string) {
 		fmt.Fprintf(errBuf, "%v: %v\n", pos, msg)
@@ -292,10 +312,61 @@ func processArch(extractor Extractor, arch *Arch) (map[string]*compiler.ConstInf
 	if infos == nil {
 		return nil, fmt.Errorf("%v", errBuf.String())
 	}
+	// Consts provided by include_consts files and the syscall table don't need to be extracted.
+	external := compiler.NewConstFile()
+	if !external.AddExternal(top, []string{arch.target.Arch}, eh) {
+		return nil, fmt.Errorf("%v", errBuf.String())
+	}
+	if err := external.AddSyscallTable(arch.target, filepath.Join("sys", arch.target.OS)); err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		consts := info.Consts[:0]
+		for _, c := range info.Consts {
+			if !external.ExistsAny(c.Name) {
+				consts = append(consts, c)
+			}
+		}
+		info.Consts = consts
+	}
+	var pending []*File
+	for _, f := range arch.files {
+		f.info = infos[filepath.Join("sys", arch.target.OS, f.name)]
+		if f.info != nil {
+			if consts, undeclared, ok := arch.cache.Get(arch, f.info); ok {
+				f.consts, f.undeclared = consts, undeclared
+				close(f.done)
+				continue
+			}
+		}
+		pending = append(pending, f)
+	}
+	if len(pending) == 0 {
+		// Everything is cached, no need to configure/build the kernel for this arch.
+		return nil, nil
+	}
 	if err := extractor.prepareArch(arch); err != nil {
 		return nil, err
 	}
-	return infos, nil
+	fmt.Printf("extracting %v/%v: %v files (%v cached)\n", arch.target.OS, arch.target.Arch,
+		len(pending), len(arch.files)-len(pending))
+	return pending, nil
+}
+
+func createCache(dir string) (*Cache, error) {
+	// The kernel config affects values of some consts as well.
+	var extra []string
+	for _, config := range []string{*flagConfig, filepath.Join(*flagBuildDir, ".config")} {
+		if config == "" || config == ".config" {
+			continue
+		}
+		data, err := os.ReadFile(config)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		extra = append(extra, string(data))
+	}
+	return NewCache(dir, *flagSourceDir, extra...)
 }
 
 func processFile(extractor Extractor, arch *Arch, file *File) (map[string]uint64, map[string]bool, error) {
@@ -10,6 +10,30 @@ import (
 	"github.com/google/syzkaller/pkg/compiler"
 )
 
+// unusedConsts() returns warnings for consts that are present in .const files,
+// but are not referenced by descriptions on any arch (e.g. descriptions were changed,
+// but consts were not re-extracted).
+func unusedConsts(consts *compiler.ConstFile, constInfos []map[string]*compiler.ConstInfo) []compiler.Warning {
+	used := make(map[string]bool)
+	for _, constInfo := range constInfos {
+		for _, info := range constInfo {
+			for _, def := range info.Consts {
+				used[def.Name] = true
+			}
+		}
+	}
+	var warnings []compiler.Warning
+	for _, name := range consts.Names() {
+		if !used[name] {
+			warnings = append(warnings, compiler.Warning{
+				Kind: compiler.WarnUnusedConst,
+				Msg:  fmt.Sprintf("const %v is not used by descriptions", name),
+			})
+		}
+	}
+	return warnings
+}
+
 // constsAreAllDefined() ensures that for every const there's at least one arch that defines it.
 func constsAreAllDefined(consts *compiler.ConstFile, constInfo map[string]*compiler.ConstInfo,
 	eh ast.ErrorHandler) {
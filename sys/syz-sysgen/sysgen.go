@@ -30,6 +30,7 @@ type SyscallData struct {
 	Name     string
 	CallName string
 	NR       int32
+	CompatNR int32
 	NeedCall bool
 	Attrs    []uint64
 }
@@ -98,6 +99,7 @@ func main() {
 		sort.Strings(archs)
 
 		var jobs []*Job
+		var constInfos []map[string]*compiler.ConstInfo
 		for _, arch := range archs {
 			target := targets.List[OS][arch]
 			constInfo := compiler.ExtractConsts(descriptions, target, nil)
@@ -106,12 +108,27 @@ func main() {
 				// so let's patch it before we start goroutines.
 				compiler.FabricateSyscallConsts(target, constInfo, constFile)
 			}
+			constInfos = append(constInfos, constInfo)
 			jobs = append(jobs, &Job{
 				Target:      target,
 				Unsupported: make(map[string]bool),
 				ConstInfo:   constInfo,
 			})
 		}
+		unused := unusedConsts(constFile, constInfos)
+		// Consts from include_consts files are not checked for being unused since the files
+		// are produced by external tools and may contain more consts than descriptions need.
+		if !constFile.AddExternal(descriptions, archs, nil) {
+			os.Exit(1)
+		}
+		for _, job := range jobs {
+			if err := constFile.AddSyscallTable(job.Target, filepath.Join(*srcDir, "sys", OS)); err != nil {
+				tool.Fail(err)
+			}
+		}
+		for _, job := range jobs {
+			job.Warnings = unused
+		}
 		sort.Slice(jobs, func(i, j int) bool {
 			return jobs[i].Target.Arch < jobs[j].Target.Arch
 		})
@@ -175,6 +192,7 @@ type Job struct {
 	OK          bool
 	Errors      []string
 	Unsupported map[string]bool
+	Warnings    []compiler.Warning
 	ArchData    ArchData
 	ConstInfo   map[string]*compiler.ConstInfo
 }
@@ -204,10 +222,12 @@ func processJob(job *Job, descriptions *ast.Description, constFile *compiler.Con
 	for what := range prog.Unsupported {
 		job.Unsupported[what] = true
 	}
+	warnings := append(prog.Warnings, job.Warnings...)
+	setCompatNRs(job.Target, prog.Syscalls, prog.Types, constFile)
 
 	sysFile := filepath.Join(*outDir, "sys", job.Target.OS, "gen", job.Target.Arch+".go")
 	out := new(bytes.Buffer)
-	generate(job.Target, prog, consts, flags, out)
+	generate(job.Target, prog, consts, flags, warnings, out)
 	rev := hash.String(out.Bytes())
 	fmt.Fprintf(out, "const revision_%v = %q\n", job.Target.Arch, rev)
 	writeSource(sysFile, out.Bytes())
@@ -224,8 +244,76 @@ func processJob(job *Job, descriptions *ast.Description, constFile *compiler.Con
 	job.OK = len(job.Errors) == 0
 }
 
+// setCompatNRs sets numbers of the compat syscall entry for syscalls that have one.
+// Argument data is always laid out for the native arch, so only calls with the same
+// memory layout on the compat arch are executed via the compat entry (see compatLayout).
+func setCompatNRs(target *targets.Target, syscalls []*prog.Syscall, types []prog.Type,
+	constFile *compiler.ConstFile) {
+	if target.CompatArch == "" {
+		return
+	}
+	consts := constFile.Arch(target.CompatArch)
+	for _, c := range syscalls {
+		if !target.HasCallNumber(c.CallName) {
+			continue
+		}
+		if nr, ok := consts[target.SyscallPrefix+c.CallName]; ok && compatLayout(c, types) {
+			c.CompatNR = nr
+		}
+	}
+}
+
+// compatLayout returns whether the data pointed to by the call arguments has the same layout
+// on the 32-bit compat arch. Pointers and 8-byte integers have different sizes or alignment there,
+// so pointed-to data must not contain them. Scalar arguments in registers are just truncated.
+func compatLayout(c *prog.Syscall, types []prog.Type) bool {
+	resolve := func(typ prog.Type) prog.Type {
+		if ref, ok := typ.(prog.Ref); ok {
+			return types[ref]
+		}
+		return typ
+	}
+	seen := make(map[prog.Type]bool)
+	var inMemory func(typ prog.Type) bool
+	inMemory = func(typ prog.Type) bool {
+		typ = resolve(typ)
+		if seen[typ] {
+			return true
+		}
+		seen[typ] = true
+		switch t := typ.(type) {
+		case *prog.PtrType, *prog.VmaType:
+			return false
+		case *prog.StructType:
+			for _, f := range t.Fields {
+				if !inMemory(f.Type) {
+					return false
+				}
+			}
+		case *prog.UnionType:
+			for _, f := range t.Fields {
+				if !inMemory(f.Type) {
+					return false
+				}
+			}
+		case *prog.ArrayType:
+			return inMemory(t.Elem)
+		case *prog.BufferType:
+		default:
+			return typ.Size() <= 4
+		}
+		return true
+	}
+	for _, arg := range c.Args {
+		if ptr, ok := resolve(arg.Type).(*prog.PtrType); ok && !inMemory(ptr.Elem) {
+			return false
+		}
+	}
+	return true
+}
+
 func generate(target *targets.Target, prg *compiler.Prog, consts map[string]uint64, flags []prog.FlagDesc,
-	out io.Writer) {
+	warnings []compiler.Warning, out io.Writer) {
 	tag := fmt.Sprintf("syz_target,syz_os_%v,syz_arch_%v", target.OS, target.Arch)
 	if target.VMArch != "" {
 		tag += fmt.Sprintf(" syz_target,syz_os_%v,syz_arch_%v", target.OS, target.VMArch)
@@ -242,10 +330,10 @@ func generate(target *targets.Target, prg *compiler.Prog, consts map[string]uint
 		"OS: %q, Arch: %q, Revision: revision_%v, PtrSize: %v, PageSize: %v, "+
 		"NumPages: %v, DataOffset: %v, LittleEndian: %v, ExecutorUsesShmem: %v, "+
 		"Syscalls: syscalls_%v, Resources: resources_%v, Consts: consts_%v,"+
-		"Flags: flags_%v}, types_%v, InitTarget)\n}\n\n",
+		"Flags: flags_%v, Warnings: warnings_%v}, types_%v, InitTarget)\n}\n\n",
 		target.OS, target.Arch, target.Arch, target.PtrSize, target.PageSize,
 		target.NumPages, target.DataOffset, target.LittleEndian, target.ExecutorUsesShmem,
-		target.Arch, target.Arch, target.Arch, target.Arch, target.Arch)
+		target.Arch, target.Arch, target.Arch, target.Arch, target.Arch, target.Arch)
 
 	fmt.Fprintf(out, "var resources_%v = ", target.Arch)
 	serializer.Write(out, prg.Resources)
@@ -273,6 +361,22 @@ func generate(target *targets.Target, prg *compiler.Prog, consts map[string]uint
 	fmt.Fprintf(out, "var consts_%v = ", target.Arch)
 	serializer.Write(out, constArr)
 	fmt.Fprintf(out, "\n\n")
+
+	descWarnings := make([]prog.DescWarning, 0, len(warnings))
+	for _, w := range warnings {
+		pos := ""
+		if w.Pos.File != "" {
+			pos = w.Pos.String()
+		}
+		descWarnings = append(descWarnings, prog.DescWarning{
+			Pos:  pos,
+			Kind: string(w.Kind),
+			Msg:  w.Msg,
+		})
+	}
+	fmt.Fprintf(out, "var warnings_%v = ", target.Arch)
+	serializer.Write(out, descWarnings)
+	fmt.Fprintf(out, "\n\n")
 }
 
 func generateExecutorSyscalls(target *targets.Target, syscalls []*prog.Syscall, rev string) ArchData {
@@ -343,6 +447,7 @@ func newSyscallData(target *targets.Target, sc *prog.Syscall, attrs []uint64) Sy
 		Name:     sc.Name,
 		CallName: callName,
 		NR:       int32(sc.NR),
+		CompatNR: int32(sc.CompatNR),
 		NeedCall: (!target.HasCallNumber(sc.CallName) || patchCallName) &&
 			// These are declared in the compiler for internal purposes.
 			!strings.HasPrefix(sc.Name, "syz_builtin"),
@@ -426,7 +531,7 @@ var syscallsTempl = template.Must(template.New("").Parse(`// AUTOGENERATED FILE
 {{range $arch := $os.Archs}}
 #if GOARCH_{{$arch.GOARCH}}
 const call_t syscalls[] = {
-{{range $c := $arch.Calls}}    {"{{$c.Name}}", {{$c.NR}}{{if or $c.Attrs $c.NeedCall}}, { {{- range $attr := $c.Attrs}}{{$attr}}, {{end}}}{{end}}{{if $c.NeedCall}}, (syscall_t){{$c.CallName}}{{end}}},
+{{range $c := $arch.Calls}}    {"{{$c.Name}}", {{$c.NR}}{{if or $c.Attrs $c.NeedCall $c.CompatNR}}, { {{- range $attr := $c.Attrs}}{{$attr}}, {{end}}}{{end}}{{if $c.NeedCall}}, (syscall_t){{$c.CallName}}{{else if $c.CompatNR}}, 0{{end}}{{if $c.CompatNR}}, {{$c.CompatNR}}{{end}}},
 {{end}}};
 #endif
 {{end}}
@@ -20,6 +20,7 @@ type Target struct {
 	OS               string
 	Arch             string
 	VMArch           string // e.g. amd64 for 386, or arm64 for arm
+	CompatArch       string // arch of the compat syscall entry usable by 64-bit processes, e.g. 386 for amd64
 	PtrSize          uint64
 	PageSize         uint64
 	NumPages         uint64
@@ -42,6 +43,9 @@ type Target struct {
 	HostEndian         binary.ByteOrder
 	SyscallTrampolines map[string]string
 	Addr2Line          func() (string, error)
+	// Syscall table file in sys/OS/ that provides syscall numbers for the target
+	// instead of extraction from kernel headers, e.g. "syscall_64.tbl" (see pkg/compiler.SyscallNumberProviders).
+	SyscallTable string
 
 	init      *sync.Once
 	initOther *sync.Once
@@ -255,6 +259,7 @@ var List = map[string]map[string]*Target{
 	},
 	Linux: {
 		AMD64: {
+			CompatArch:       I386,
 			PtrSize:          8,
 			PageSize:         4 << 10,
 			LittleEndian:     true,
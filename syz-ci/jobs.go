@@ -643,13 +643,14 @@ func (jp *JobProcessor) testPatch(job *Job, mgrcfg *mgrconfig.Config) error {
 
 	log.Logf(0, "job: building kernel...")
 	kernelConfig, details, err := env.BuildKernel(&instance.BuildKernelConfig{
-		CompilerBin:  mgr.mgrcfg.Compiler,
-		LinkerBin:    mgr.mgrcfg.Linker,
-		CcacheBin:    mgr.mgrcfg.Ccache,
-		UserspaceDir: mgr.mgrcfg.Userspace,
-		CmdlineFile:  mgr.mgrcfg.KernelCmdline,
-		SysctlFile:   mgr.mgrcfg.KernelSysctl,
-		KernelConfig: req.KernelConfig,
+		CompilerBin:     mgr.mgrcfg.Compiler,
+		LinkerBin:       mgr.mgrcfg.Linker,
+		CcacheBin:       mgr.mgrcfg.Ccache,
+		UserspaceDir:    mgr.mgrcfg.Userspace,
+		CmdlineFile:     mgr.mgrcfg.KernelCmdline,
+		SysctlFile:      mgr.mgrcfg.KernelSysctl,
+		ExternalModules: mgr.mgrcfg.KernelModules,
+		KernelConfig:    req.KernelConfig,
 	})
 	resp.Build.CompilerID = details.CompilerID
 	if err != nil {
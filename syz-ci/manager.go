@@ -342,19 +342,20 @@ func (mgr *Manager) build(kernelCommit *vcs.Commit) error {
 		return fmt.Errorf("failed to create tmp dir: %w", err)
 	}
 	params := build.Params{
-		TargetOS:     mgr.managercfg.TargetOS,
-		TargetArch:   mgr.managercfg.TargetVMArch,
-		VMType:       mgr.managercfg.Type,
-		KernelDir:    mgr.kernelBuildDir,
-		OutputDir:    tmpDir,
-		Compiler:     mgr.mgrcfg.Compiler,
-		Linker:       mgr.mgrcfg.Linker,
-		Ccache:       mgr.mgrcfg.Ccache,
-		UserspaceDir: mgr.mgrcfg.Userspace,
-		CmdlineFile:  mgr.mgrcfg.KernelCmdline,
-		SysctlFile:   mgr.mgrcfg.KernelSysctl,
-		Config:       mgr.configData,
-		Build:        mgr.mgrcfg.Build,
+		TargetOS:        mgr.managercfg.TargetOS,
+		TargetArch:      mgr.managercfg.TargetVMArch,
+		VMType:          mgr.managercfg.Type,
+		KernelDir:       mgr.kernelBuildDir,
+		OutputDir:       tmpDir,
+		Compiler:        mgr.mgrcfg.Compiler,
+		Linker:          mgr.mgrcfg.Linker,
+		Ccache:          mgr.mgrcfg.Ccache,
+		UserspaceDir:    mgr.mgrcfg.Userspace,
+		CmdlineFile:     mgr.mgrcfg.KernelCmdline,
+		SysctlFile:      mgr.mgrcfg.KernelSysctl,
+		ExternalModules: mgr.mgrcfg.KernelModules,
+		Config:          mgr.configData,
+		Build:           mgr.mgrcfg.Build,
 	}
 	details, err := build.Image(params)
 	info := mgr.createBuildInfo(kernelCommit, details.CompilerID)
@@ -66,6 +66,7 @@ import (
 
 	"github.com/google/syzkaller/dashboard/dashapi"
 	"github.com/google/syzkaller/pkg/asset"
+	"github.com/google/syzkaller/pkg/build"
 	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
@@ -199,8 +200,10 @@ type ManagerConfig struct {
 	// File with kernel cmdline values (optional).
 	KernelCmdline string `json:"kernel_cmdline"`
 	// File with sysctl values (e.g. output of sysctl -a, optional).
-	KernelSysctl string      `json:"kernel_sysctl"`
-	Jobs         ManagerJobs `json:"jobs"`
+	KernelSysctl string `json:"kernel_sysctl"`
+	// Out-of-tree kernel modules that are built against the kernel and installed into the image (optional).
+	KernelModules []build.ExternalModule `json:"kernel_modules"`
+	Jobs          ManagerJobs            `json:"jobs"`
 	// Extra commits to cherry pick to older kernel revisions.
 	BisectBackports []vcs.BackportCommit `json:"bisect_backports"`
 	// Base syz-manager config for the instance.
@@ -457,6 +460,9 @@ func loadManagerConfig(cfg *Config, mgr *ManagerConfig) error {
 	mgr.KernelBaselineConfig = osutil.Abs(mgr.KernelBaselineConfig)
 	mgr.KernelCmdline = osutil.Abs(mgr.KernelCmdline)
 	mgr.KernelSysctl = osutil.Abs(mgr.KernelSysctl)
+	for i := range mgr.KernelModules {
+		mgr.KernelModules[i].Dir = osutil.Abs(mgr.KernelModules[i].Dir)
+	}
 	if mgr.KernelConfig != "" && mgr.KernelBaselineConfig == "" {
 		mgr.KernelBaselineConfig = inferBaselineConfig(mgr.KernelConfig)
 	}
@@ -0,0 +1,63 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+)
+
+const (
+	externalProbePeriod   = 10 * time.Second
+	externalProbeFailures = 6
+)
+
+// setupExternalNet passes the external network target to executor processes (see syz_emit_external)
+// and starts liveness probing of the target if probePort is set.
+func setupExternalNet(addr string, rate, procs, probePort int, scale time.Duration) {
+	// The rate is per VM, but the executor enforces it per test process.
+	procRate := max(rate/procs, 1)
+	os.Setenv("SYZKALLER_EXTERNAL_NET", addr)
+	os.Setenv("SYZKALLER_EXTERNAL_NET_RATE", fmt.Sprint(procRate))
+	log.Logf(0, "external network target %v, %v packets/sec per proc", addr, procRate)
+	if probePort != 0 {
+		go probeExternalNet(net.JoinHostPort(addr, fmt.Sprint(probePort)), scale)
+	}
+}
+
+// probeExternalNet periodically connects to the target and aborts fuzzing if it stops responding.
+// The resulting SYZFATAL is reported by the manager as a crash, since it most likely means
+// that one of the sent packets has crashed or hung the device.
+func probeExternalNet(addr string, scale time.Duration) {
+	failures := 0
+	for ; ; time.Sleep(externalProbePeriod * scale) {
+		conn, err := net.DialTimeout("tcp", addr, externalProbePeriod*scale)
+		if err == nil {
+			conn.Close()
+			failures = 0
+			continue
+		}
+		failures++
+		log.Logf(0, "external network target probe failed (%v/%v): %v",
+			failures, externalProbeFailures, err)
+		if failures == externalProbeFailures {
+			log.SyzFatalf("external network target %v is not responding: %v", addr, err)
+		}
+	}
+}
+
+// setupNetCapture passes the capture interface to executor processes, they bridge their net namespaces
+// to the interface (see initialize_net_capture in executor/common_linux.h).
+func setupNetCapture(iface, procs string) {
+	os.Setenv("SYZKALLER_NET_CAPTURE", iface)
+	os.Setenv("SYZKALLER_NET_CAPTURE_PROCS", procs)
+	if procs == "" {
+		procs = "all"
+	}
+	log.Logf(0, "bridging net namespaces of procs %v to %v", procs, iface)
+}
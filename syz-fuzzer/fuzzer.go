@@ -4,6 +4,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -41,6 +42,7 @@ type FuzzerTool struct {
 	requests  chan *flatrpc.ExecRequest
 	signalMu  sync.RWMutex
 	maxSignal signal.Signal
+	watchdog  *watchdog
 }
 
 // TODO: split into smaller methods.
@@ -55,6 +57,14 @@ func main() {
 		flagManager   = flag.String("manager", "", "manager rpc address")
 		flagProcs     = flag.Int("procs", 1, "number of parallel test processes")
 		flagPprofPort = flag.Int("pprof_port", 0, "HTTP port for the pprof endpoint (disabled if 0)")
+
+		flagExternalNet      = flag.String("external_net", "", "IPv4 address of the external network target")
+		flagExternalNetRate  = flag.Int("external_net_rate", 100, "max packets/sec sent to the external target")
+		flagExternalNetProbe = flag.Int("external_net_probe", 0, "TCP port to probe external target liveness")
+		flagWatchdog         = flag.String("watchdog", "", "hardware watchdog device to keep alive")
+		flagWatchdogTimeout  = flag.Int("watchdog_timeout", 0, "hardware watchdog timeout in seconds")
+		flagNetCapture       = flag.String("net_capture", "", "interface to bridge test net namespaces to")
+		flagNetCaptureProcs  = flag.String("net_capture_procs", "", "comma-separated procs to bridge (default: all)")
 	)
 	defer tool.Init()()
 	log.Logf(0, "fuzzer started")
@@ -82,6 +92,18 @@ func main() {
 	if *flagPprofPort != 0 {
 		setupPprofHandler(*flagPprofPort)
 	}
+	if *flagExternalNet != "" {
+		setupExternalNet(*flagExternalNet, *flagExternalNetRate, *flagProcs, *flagExternalNetProbe, timeouts.Scale)
+	}
+	if *flagNetCapture != "" {
+		setupNetCapture(*flagNetCapture, *flagNetCaptureProcs)
+	}
+
+	var wd *watchdog
+	if *flagWatchdog != "" {
+		// Give the manager a chance to detect the hang and reboot the machine first.
+		wd = setupWatchdog(*flagWatchdog, *flagWatchdogTimeout, 2*timeouts.NoOutput)
+	}
 
 	executorArch, executorSyzRevision, executorGitRevision, err := executorVersion(executor)
 	if err != nil {
@@ -100,6 +122,9 @@ func main() {
 		Arch:        executorArch,
 		GitRevision: executorGitRevision,
 		SyzRevision: executorSyzRevision,
+
+		ProtocolVersion: flatrpc.ProtocolVersion,
+		Features:        flatrpc.SupportedFeatures,
 	}
 	if err := flatrpc.Send(conn, connectReq); err != nil {
 		log.SyzFatal(err)
@@ -109,6 +134,9 @@ func main() {
 		log.SyzFatal(err)
 	}
 	connectReply := connectReplyRaw.UnPack()
+	if err := flatrpc.CheckProtocolVersion("manager", connectReply.ProtocolVersion); err != nil {
+		log.SyzFatal(err)
+	}
 
 	infoReq := &flatrpc.InfoRequest{
 		Files: host.ReadFiles(connectReply.Files),
@@ -150,6 +178,7 @@ func main() {
 		leakFrames: connectReply.LeakFrames,
 
 		requests: make(chan *flatrpc.ExecRequest, *flagProcs*4),
+		watchdog: wd,
 	}
 	fuzzerTool.filterDataRaceFrames(connectReply.RaceFrames)
 	// TODO: repair leak checking.
@@ -159,6 +188,9 @@ func main() {
 	for pid := 0; pid < *flagProcs; pid++ {
 		startProc(fuzzerTool, pid, config)
 	}
+	if target.OS == targets.Linux {
+		go fuzzerTool.watchModules(connectReply.Files, infoReq.Files)
+	}
 
 	fuzzerTool.handleConn()
 }
@@ -201,6 +233,57 @@ func (tool *FuzzerTool) filterDataRaceFrames(frames []string) {
 	log.Logf(0, "%s", output)
 }
 
+// watchModules periodically checks if new kernel modules were loaded
+// (e.g. auto-loaded as the result of executing a program) and sends a fresh snapshot
+// of the module files to the manager, so that it can symbolize coverage in these modules.
+func (tool *FuzzerTool) watchModules(files []string, initial []*flatrpc.FileInfo) {
+	var last string
+	for _, file := range initial {
+		if file.Name == "/proc/modules" {
+			last = liveModules(file.Data)
+		}
+	}
+	ticker := time.NewTicker(10 * time.Second * tool.timeouts.Scale)
+	defer ticker.Stop()
+	for range ticker.C {
+		data, err := os.ReadFile("/proc/modules")
+		if err != nil {
+			return
+		}
+		current := liveModules(data)
+		if current == last {
+			continue
+		}
+		last = current
+		msg := &flatrpc.ExecutorMessage{
+			Msg: &flatrpc.ExecutorMessages{
+				Type: flatrpc.ExecutorMessagesRawModulesUpdate,
+				Value: &flatrpc.ModulesUpdate{
+					Files: host.ReadFiles(files),
+				},
+			},
+		}
+		if err := flatrpc.Send(tool.conn, msg); err != nil {
+			log.SyzFatal(err)
+		}
+	}
+}
+
+// liveModules returns names and load addresses of the fully loaded modules in /proc/modules.
+// Reference counts change all the time, so we can't compare the file contents as is.
+func liveModules(data []byte) string {
+	var res []string
+	for _, line := range strings.Split(string(data), "\n") {
+		// Format: name size refcount deps state address [taint].
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[4] != "Live" {
+			continue
+		}
+		res = append(res, fields[0]+" "+fields[5])
+	}
+	return strings.Join(res, "\n")
+}
+
 func (tool *FuzzerTool) startExecutingCall(progID int64, pid, try int, wait time.Duration) {
 	msg := &flatrpc.ExecutorMessage{
 		Msg: &flatrpc.ExecutorMessages{
@@ -222,8 +305,13 @@ func (tool *FuzzerTool) handleConn() {
 	for {
 		raw, err := flatrpc.Recv[flatrpc.HostMessageRaw](tool.conn)
 		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// The manager stopped us, it will take care of the machine.
+				tool.watchdog.disarm()
+			}
 			log.SyzFatal(err)
 		}
+		tool.watchdog.progress()
 		switch msg := raw.UnPack().Msg.Value.(type) {
 		case *flatrpc.ExecRequest:
 			msg.ProgData = slices.Clone(msg.ProgData)
@@ -132,6 +132,10 @@ func (proc *Proc) executeProgram(req *flatrpc.ExecRequest, wait time.Duration) (
 		if err == nil || returnError {
 			return info, output, err
 		}
+		if errors.Is(err, ipc.ErrExecutorCrashed) {
+			// Retrying won't help to debug it, the manager collects the core dump (if enabled).
+			log.SyzFatal(err)
+		}
 		log.Logf(4, "fuzzer detected executor failure='%v', retrying #%d", err, try+1)
 		if try > 10 {
 			log.SyzFatalf("executor %v failed %v times: %v\n%s", proc.pid, try, err, output)
@@ -0,0 +1,132 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+)
+
+// The hardware watchdog (-watchdog flag) resets bare-metal targets that get wedged and can't be
+// rebooted by the manager. The watchdog is kept alive only while the fuzzer makes progress
+// (receives requests from the manager), so hung executors eventually lead to a reset as well.
+// The watchdog is disarmed when the manager closes the connection, otherwise (e.g. if the fuzzer
+// crashes) the manager is expected to restart the fuzzer in time.
+type watchdog struct {
+	mu     sync.Mutex
+	file   *os.File
+	period time.Duration
+	stall  time.Duration
+	// Time of the last progress in unix nanoseconds.
+	lastProgress atomic.Int64
+}
+
+const (
+	watchdogPetPeriod = 5 * time.Second
+	// WDIOF_CARDRESET bit of the watchdog bootstatus.
+	watchdogCardReset = 0x20
+	// Writing the magic character before close disarms the watchdog (if the driver supports it).
+	watchdogMagicClose = 'V'
+	watchdogResetStr   = "SYZ-FUZZER: WATCHDOG RESET"
+)
+
+func setupWatchdog(device string, timeout int, stall time.Duration) *watchdog {
+	reportWatchdogReset(device)
+	file, err := os.OpenFile(device, os.O_WRONLY, 0)
+	if err != nil {
+		log.SyzFatalf("failed to open watchdog device: %v", err)
+	}
+	wd := &watchdog{
+		file:   file,
+		period: watchdogPetPeriod,
+		stall:  stall,
+	}
+	if timeout != 0 {
+		if err := setWatchdogTimeout(file, timeout); err != nil {
+			log.SyzFatalf("failed to set watchdog timeout: %v", err)
+		}
+		wd.period = min(wd.period, time.Duration(timeout)*time.Second/2)
+	}
+	wd.progress()
+	go wd.loop()
+	log.Logf(0, "keeping watchdog %v alive (timeout %vs)", device, timeout)
+	return wd
+}
+
+func (wd *watchdog) progress() {
+	if wd != nil {
+		wd.lastProgress.Store(time.Now().UnixNano())
+	}
+}
+
+// disarm stops the watchdog, so that it does not reset the machine after the fuzzer exits.
+func (wd *watchdog) disarm() {
+	if wd == nil {
+		return
+	}
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if wd.file == nil {
+		return
+	}
+	if _, err := wd.file.Write([]byte{watchdogMagicClose}); err != nil {
+		log.Logf(0, "failed to disarm watchdog: %v", err)
+	}
+	wd.file.Close()
+	wd.file = nil
+}
+
+func (wd *watchdog) loop() {
+	stalled := false
+	for range time.NewTicker(wd.period).C {
+		if time.Since(time.Unix(0, wd.lastProgress.Load())) > wd.stall {
+			if !stalled {
+				log.Logf(0, "no progress for %v, letting the watchdog reset the machine", wd.stall)
+			}
+			stalled = true
+			continue
+		}
+		stalled = false
+		if !wd.pet() {
+			return
+		}
+	}
+}
+
+func (wd *watchdog) pet() bool {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if wd.file == nil {
+		return false
+	}
+	if _, err := wd.file.Write([]byte{0}); err != nil {
+		log.Logf(0, "failed to write to watchdog device: %v", err)
+	}
+	return true
+}
+
+// reportWatchdogReset prints the banner for the manager if the machine was reset by the watchdog.
+func reportWatchdogReset(device string) {
+	name := filepath.Base(device)
+	if name == "watchdog" {
+		name = "watchdog0"
+	}
+	data, err := os.ReadFile(filepath.Join("/sys/class/watchdog", name, "bootstatus"))
+	if err != nil {
+		return
+	}
+	status, err := strconv.ParseUint(strings.TrimSpace(string(data)), 0, 64)
+	if err != nil || status&watchdogCardReset == 0 {
+		return
+	}
+	bootID, _ := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	log.Logf(0, "%v boot_id=%v", watchdogResetStr, strings.TrimSpace(string(bootID)))
+}
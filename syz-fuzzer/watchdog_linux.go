@@ -0,0 +1,14 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func setWatchdogTimeout(file *os.File, timeout int) error {
+	return unix.IoctlSetPointerInt(int(file.Fd()), unix.WDIOC_SETTIMEOUT, timeout)
+}
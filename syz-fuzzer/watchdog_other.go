@@ -0,0 +1,15 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func setWatchdogTimeout(file *os.File, timeout int) error {
+	return fmt.Errorf("watchdog timeout is not supported on this OS")
+}
@@ -4,18 +4,24 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/syzkaller/pkg/log"
 )
 
 func (hub *Hub) initHTTP(addr string) {
 	http.HandleFunc("/", hub.httpSummary)
+	http.HandleFunc("/api/managers", hub.httpManagers)
+	http.HandleFunc("/ban", hub.httpBan)
+	http.HandleFunc("/limit", hub.httpLimit)
 
 	ln, err := net.Listen("tcp4", addr)
 	if err != nil {
@@ -40,36 +46,154 @@ func (hub *Hub) httpSummary(w http.ResponseWriter, r *http.Request) {
 		Corpus: len(hub.st.Corpus.Records),
 		Repros: len(hub.st.Repros.Records),
 	}
-	for name, mgr := range hub.st.Managers {
+	managers := hub.collectManagers()
+	for _, mgr := range managers {
 		total.Added += mgr.Added
 		total.Deleted += mgr.Deleted
+		total.Dropped += mgr.Dropped
 		total.New += mgr.New
 		total.SentRepros += mgr.SentRepros
 		total.RecvRepros += mgr.RecvRepros
-		data.Managers = append(data.Managers, UIManager{
+		total.AddedPerHour += mgr.AddedPerHour
+		total.NewPerHour += mgr.NewPerHour
+	}
+	data.Managers = append([]UIManager{total}, managers...)
+	if err := summaryTemplate.Execute(w, data); err != nil {
+		log.Logf(0, "failed to execute template: %v", err)
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// httpManagers returns per-manager stats in JSON format. The request must pass either
+// the hub admin key in the key parameter (stats of all managers are returned),
+// or the client name and key in the client/key parameters (only managers of the client are returned).
+func (hub *Hub) httpManagers(w http.ResponseWriter, r *http.Request) {
+	client, key := r.FormValue("client"), r.FormValue("key")
+	if client == "" {
+		if err := hub.checkControl(key, ""); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	} else if _, err := hub.checkManager(client, key, ""); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	hub.mu.Lock()
+	all := hub.collectManagers()
+	hub.mu.Unlock()
+	managers := []UIManager{}
+	for _, mgr := range all {
+		if strings.HasPrefix(mgr.Name, client) {
+			managers = append(managers, mgr)
+		}
+	}
+
+	data, err := json.MarshalIndent(managers, "", "\t")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode json: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// httpBan bans (or unbans with banned=0) the manager given in the manager parameter.
+func (hub *Hub) httpBan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name, banned := r.FormValue("manager"), r.FormValue("banned") != "0"
+	if err := hub.checkControl(r.FormValue("key"), name); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	hub.mu.Lock()
+	err := hub.st.SetBanned(name, banned)
+	hub.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Logf(0, "manager %v: banned=%v", name, banned)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// httpLimit sets the maximum number of programs accepted from the manager per sync.
+func (hub *Hub) httpLimit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := r.FormValue("manager")
+	if err := hub.checkControl(r.FormValue("key"), name); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	limit, err := strconv.Atoi(r.FormValue("limit"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("bad limit: %v", err), http.StatusBadRequest)
+		return
+	}
+	hub.mu.Lock()
+	err = hub.st.SetLimit(name, limit)
+	hub.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Logf(0, "manager %v: limit=%v", name, limit)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func (hub *Hub) collectManagers() []UIManager {
+	var managers []UIManager
+	now := time.Now()
+	for name, mgr := range hub.st.Managers {
+		ui := UIManager{
 			Name:       name,
 			Domain:     mgr.Domain,
+			Kernel:     mgr.KernelVersion,
+			Connected:  mgr.Connected,
+			LastSync:   mgr.LastSync,
+			Banned:     mgr.Banned,
+			Limit:      mgr.Limit,
 			Corpus:     len(mgr.Corpus.Records),
 			Added:      mgr.Added,
 			Deleted:    mgr.Deleted,
+			Dropped:    mgr.Dropped,
 			New:        mgr.New,
 			SentRepros: mgr.SentRepros,
 			RecvRepros: mgr.RecvRepros,
-		})
+		}
+		// Stats are not persisted, so the rates are computed since the last connect.
+		if !mgr.Connected.IsZero() {
+			hours := now.Sub(mgr.Connected).Hours()
+			if hours > 0 {
+				ui.AddedPerHour = float64(mgr.Added) / hours
+				ui.NewPerHour = float64(mgr.New) / hours
+			}
+		}
+		managers = append(managers, ui)
 	}
-	sort.Slice(data.Managers, func(i, j int) bool {
-		return data.Managers[i].Name < data.Managers[j].Name
+	sort.Slice(managers, func(i, j int) bool {
+		return managers[i].Name < managers[j].Name
 	})
-	data.Managers = append([]UIManager{total}, data.Managers...)
-	if err := summaryTemplate.Execute(w, data); err != nil {
-		log.Logf(0, "failed to execute template: %v", err)
-		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
-		return
+	return managers
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
 	}
+	return time.Since(t).Truncate(time.Second).String() + " ago"
 }
 
 func compileTemplate(html string) *template.Template {
-	return template.Must(template.New("").Parse(strings.Replace(html, "{{STYLE}}", htmlStyle, -1)))
+	return template.Must(template.New("").Funcs(template.FuncMap{
+		"formatTime": formatTime,
+	}).Parse(strings.Replace(html, "{{STYLE}}", htmlStyle, -1)))
 }
 
 type UISummaryData struct {
@@ -78,15 +202,23 @@ type UISummaryData struct {
 }
 
 type UIManager struct {
-	Name       string
-	Domain     string
-	Corpus     int
-	Added      int
-	Deleted    int
-	New        int
-	Repros     int
-	SentRepros int
-	RecvRepros int
+	Name         string
+	Domain       string
+	Kernel       string
+	Connected    time.Time
+	LastSync     time.Time
+	Banned       bool
+	Limit        int
+	Corpus       int
+	Added        int
+	Deleted      int
+	Dropped      int
+	New          int
+	AddedPerHour float64
+	NewPerHour   float64
+	Repros       int
+	SentRepros   int
+	RecvRepros   int
 }
 
 var summaryTemplate = compileTemplate(`
@@ -105,25 +237,64 @@ var summaryTemplate = compileTemplate(`
 	<tr>
 		<th>Name</th>
 		<th>Domain</th>
+		<th>Kernel</th>
+		<th>Connected</th>
+		<th>Last sync</th>
 		<th>Corpus</th>
 		<th>Added</th>
 		<th>Deleted</th>
+		<th>Dropped</th>
 		<th>New</th>
+		<th>Added/h</th>
+		<th>New/h</th>
 		<th>Repros</th>
 		<th>Sent</th>
 		<th>Recv</th>
+		<th>Limit</th>
+		<th>Control</th>
 	</tr>
 	{{range $m := $.Managers}}
 	<tr>
 		<td>{{$m.Name}}</td>
 		<td>{{$m.Domain}}</td>
+		<td>{{$m.Kernel}}</td>
+		<td>{{formatTime $m.Connected}}</td>
+		<td>{{formatTime $m.LastSync}}</td>
 		<td>{{$m.Corpus}}</td>
 		<td>{{$m.Added}}</td>
 		<td>{{$m.Deleted}}</td>
+		<td>{{$m.Dropped}}</td>
 		<td>{{$m.New}}</td>
+		<td>{{printf "%.1f" $m.AddedPerHour}}</td>
+		<td>{{printf "%.1f" $m.NewPerHour}}</td>
 		<td>{{$m.Repros}}</td>
 		<td>{{$m.SentRepros}}</td>
 		<td>{{$m.RecvRepros}}</td>
+		{{if eq $m.Name "total"}}
+		<td></td>
+		<td></td>
+		{{else}}
+		<td>
+			<form action="/limit" method="post">
+				<input type="hidden" name="manager" value="{{$m.Name}}">
+				<input type="number" name="limit" min="0" value="{{$m.Limit}}" style="width:6em">
+				<input type="password" name="key" placeholder="admin key" style="width:6em">
+				<input type="submit" value="set">
+			</form>
+		</td>
+		<td>
+			<form action="/ban" method="post">
+				<input type="hidden" name="manager" value="{{$m.Name}}">
+				<input type="password" name="key" placeholder="admin key" style="width:6em">
+				{{if $m.Banned}}
+				<input type="hidden" name="banned" value="0">
+				<input type="submit" value="unban">
+				{{else}}
+				<input type="submit" value="ban">
+				{{end}}
+			</form>
+		</td>
+		{{end}}
 	</tr>
 	{{end}}
 </table>
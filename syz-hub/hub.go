@@ -4,6 +4,7 @@
 package main
 
 import (
+	"crypto/subtle"
 	"flag"
 	"fmt"
 	"strings"
@@ -29,13 +30,17 @@ type Config struct {
 		Name string
 		Key  string
 	}
+	// Key that allows to ban/limit any manager via the http interface.
+	// Without it, only the key of the client that owns the manager is accepted.
+	AdminKey string `json:"admin_key"`
 }
 
 type Hub struct {
-	mu   sync.Mutex
-	st   *state.State
-	keys map[string]string
-	auth auth.Endpoint
+	mu       sync.Mutex
+	st       *state.State
+	keys     map[string]string
+	adminKey string
+	auth     auth.Endpoint
 }
 
 func main() {
@@ -51,9 +56,10 @@ func main() {
 		log.Fatalf("failed to load state: %v", err)
 	}
 	hub := &Hub{
-		st:   st,
-		keys: make(map[string]string),
-		auth: auth.MakeEndpoint(auth.GoogleTokenInfoEndpoint),
+		st:       st,
+		keys:     make(map[string]string),
+		adminKey: cfg.AdminKey,
+		auth:     auth.MakeEndpoint(auth.GoogleTokenInfoEndpoint),
 	}
 	for _, mgr := range cfg.Clients {
 		hub.keys[mgr.Name] = mgr.Key
@@ -77,9 +83,9 @@ func (hub *Hub) Connect(a *rpctype.HubConnectArgs, r *int) error {
 	hub.mu.Lock()
 	defer hub.mu.Unlock()
 
-	log.Logf(0, "connect from %v: domain=%v fresh=%v calls=%v corpus=%v",
-		name, a.Domain, a.Fresh, len(a.Calls), len(a.Corpus))
-	if err := hub.st.Connect(name, a.Domain, a.Fresh, a.Calls, a.Corpus); err != nil {
+	log.Logf(0, "connect from %v: domain=%v kernel=%v fresh=%v calls=%v corpus=%v",
+		name, a.Domain, a.KernelVersion, a.Fresh, len(a.Calls), len(a.Corpus))
+	if err := hub.st.Connect(name, a.Domain, a.KernelVersion, a.Fresh, a.Calls, a.Corpus); err != nil {
 		log.Logf(0, "connect error: %v", err)
 		return err
 	}
@@ -145,6 +151,23 @@ func (hub *Hub) verifyKey(key, expectedKey string) error {
 	return nil
 }
 
+// checkControl verifies that the key allows to change settings of managers (ban/limit).
+// Only the hub admin key is accepted: client keys are not, otherwise a banned or limited
+// client could lift the restrictions from its own managers.
+func (hub *Hub) checkControl(key, manager string) error {
+	if hub.adminKey == "" {
+		return fmt.Errorf("admin key is not configured")
+	}
+	if key == "" {
+		return fmt.Errorf("no key")
+	}
+	if subtle.ConstantTimeCompare([]byte(key), []byte(hub.adminKey)) == 1 {
+		return nil
+	}
+	log.Logf(0, "unauthorized control request for manager %v", manager)
+	return fmt.Errorf("unauthorized")
+}
+
 // Returns the verified manager identity or error.
 func (hub *Hub) checkManager(client, key, manager string) (string, error) {
 	expectedKey, ok := hub.keys[client]
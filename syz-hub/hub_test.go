@@ -4,8 +4,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+
+	"github.com/google/syzkaller/syz-hub/state"
 )
 
 func TestAuth(t *testing.T) {
@@ -101,3 +108,84 @@ func TestAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPControl(t *testing.T) {
+	st, err := state.Make(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := &Hub{
+		st:       st,
+		keys:     map[string]string{"foo": "foo-key", "bar": "bar-key"},
+		adminKey: "admin-key",
+	}
+	if err := st.Connect("foo", "", "6.8", false, []string{"open"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	post := func(handler http.HandlerFunc, params url.Values) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+	if code := post(hub.httpBan, url.Values{"manager": {"foo"}}); code != http.StatusForbidden {
+		t.Fatalf("ban without key returned %v", code)
+	}
+	if code := post(hub.httpBan, url.Values{"manager": {"foo"}, "key": {"bar-key"}}); code != http.StatusForbidden {
+		t.Fatalf("ban with key of another client returned %v", code)
+	}
+	if code := post(hub.httpLimit, url.Values{"manager": {"foo"}, "limit": {"10"}}); code != http.StatusForbidden {
+		t.Fatalf("limit without key returned %v", code)
+	}
+	// Clients can't control even their own managers, otherwise they could lift their own ban/limit.
+	if code := post(hub.httpBan, url.Values{"manager": {"foo"}, "key": {"foo-key"}}); code != http.StatusForbidden {
+		t.Fatalf("ban with key of the owning client returned %v", code)
+	}
+	if code := post(hub.httpLimit, url.Values{"manager": {"foo"}, "limit": {"10"},
+		"key": {"foo-key"}}); code != http.StatusForbidden {
+		t.Fatalf("limit with key of the owning client returned %v", code)
+	}
+	if code := post(hub.httpBan, url.Values{"manager": {"bar"}, "key": {"admin-key"}}); code != http.StatusBadRequest {
+		t.Fatalf("banning unknown manager returned %v", code)
+	}
+	if code := post(hub.httpBan, url.Values{"manager": {"foo"}, "key": {"admin-key"}}); code != http.StatusFound {
+		t.Fatalf("ban returned %v", code)
+	}
+	if code := post(hub.httpLimit, url.Values{"manager": {"foo"}, "limit": {"10"},
+		"key": {"admin-key"}}); code != http.StatusFound {
+		t.Fatalf("limit returned %v", code)
+	}
+	getManagers := func(query string) (int, []UIManager) {
+		rec := httptest.NewRecorder()
+		hub.httpManagers(rec, httptest.NewRequest(http.MethodGet, "/api/managers?"+query, nil))
+		var managers []UIManager
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &managers); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec.Code, managers
+	}
+	for _, query := range []string{"", "key=foo-key", "client=foo", "client=foo&key=bar-key", "client=baz&key=foo-key"} {
+		if code, _ := getManagers(query); code != http.StatusForbidden {
+			t.Fatalf("managers request %q returned %v", query, code)
+		}
+	}
+	for _, query := range []string{"key=admin-key", "client=foo&key=foo-key"} {
+		code, managers := getManagers(query)
+		if code != http.StatusOK || len(managers) != 1 || managers[0].Name != "foo" || !managers[0].Banned ||
+			managers[0].Limit != 10 || managers[0].Kernel != "6.8" {
+			t.Fatalf("bad managers for %q: %v %+v", query, code, managers)
+		}
+	}
+	// Clients see only own managers.
+	if code, managers := getManagers("client=bar&key=bar-key"); code != http.StatusOK || len(managers) != 0 {
+		t.Fatalf("bad managers for bar: %v %+v", code, managers)
+	}
+	rec := httptest.NewRecorder()
+	hub.httpSummary(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "unban") {
+		t.Fatalf("bad summary page: %v\n%s", rec.Code, rec.Body.String())
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/syzkaller/pkg/db"
@@ -35,15 +36,23 @@ type State struct {
 type Manager struct {
 	name          string
 	Domain        string
+	KernelVersion string
 	corpusSeq     uint64
 	reproSeq      uint64
 	corpusFile    string
 	corpusSeqFile string
 	reproSeqFile  string
 	domainFile    string
+	kernelFile    string
+	bannedFile    string
+	limitFile     string
 	ownRepros     map[string]bool
 	Connected     time.Time
+	LastSync      time.Time
+	Banned        bool
+	Limit         int
 	Added         int
+	Dropped       int
 	Deleted       int
 	New           int
 	SentRepros    int
@@ -145,6 +154,9 @@ func (st *State) createManager(name string) (*Manager, error) {
 		corpusSeqFile: filepath.Join(dir, "seq"),
 		reproSeqFile:  filepath.Join(dir, "repro.seq"),
 		domainFile:    filepath.Join(dir, "domain"),
+		kernelFile:    filepath.Join(dir, "kernel"),
+		bannedFile:    filepath.Join(dir, "banned"),
+		limitFile:     filepath.Join(dir, "limit"),
 		ownRepros:     make(map[string]bool),
 	}
 	mgr.corpusSeq = loadSeqFile(mgr.corpusSeqFile)
@@ -160,6 +172,10 @@ func (st *State) createManager(name string) (*Manager, error) {
 	}
 	domainData, _ := os.ReadFile(mgr.domainFile)
 	mgr.Domain = string(domainData)
+	kernelData, _ := os.ReadFile(mgr.kernelFile)
+	mgr.KernelVersion = string(kernelData)
+	mgr.Banned = osutil.IsExist(mgr.bannedFile)
+	mgr.Limit = int(loadSeqFile(mgr.limitFile))
 	corpus, _, err := loadDB(mgr.corpusFile, name, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open manager corpus %v: %w", mgr.corpusFile, err)
@@ -171,7 +187,7 @@ func (st *State) createManager(name string) (*Manager, error) {
 	return mgr, nil
 }
 
-func (st *State) Connect(name, domain string, fresh bool, calls []string, corpus [][]byte) error {
+func (st *State) Connect(name, domain, kernel string, fresh bool, calls []string, corpus [][]byte) error {
 	mgr := st.Managers[name]
 	if mgr == nil {
 		var err error
@@ -180,9 +196,14 @@ func (st *State) Connect(name, domain string, fresh bool, calls []string, corpus
 			return err
 		}
 	}
+	if mgr.Banned {
+		return fmt.Errorf("banned manager %v", name)
+	}
 	mgr.Connected = time.Now()
 	mgr.Domain = domain
 	writeFile(mgr.domainFile, []byte(mgr.Domain))
+	mgr.KernelVersion = kernel
+	writeFile(mgr.kernelFile, []byte(mgr.KernelVersion))
 	if fresh {
 		mgr.corpusSeq = 0
 		mgr.reproSeq = st.reproSeq
@@ -212,6 +233,14 @@ func (st *State) Sync(name string, add [][]byte, del []string) (string, []rpctyp
 	if mgr == nil || mgr.Connected.IsZero() {
 		return "", nil, 0, fmt.Errorf("unconnected manager %v", name)
 	}
+	if mgr.Banned {
+		return "", nil, 0, fmt.Errorf("banned manager %v", name)
+	}
+	mgr.LastSync = time.Now()
+	if mgr.Limit != 0 && len(add) > mgr.Limit {
+		mgr.Dropped += len(add) - mgr.Limit
+		add = add[:mgr.Limit]
+	}
 	if len(del) != 0 {
 		for _, sig := range del {
 			mgr.Corpus.Delete(sig)
@@ -229,11 +258,44 @@ func (st *State) Sync(name string, add [][]byte, del []string) (string, []rpctyp
 	return mgr.Domain, progs, more, err
 }
 
+// SetBanned bans or unbans the manager. Banned managers can't connect, sync and send repros.
+func (st *State) SetBanned(name string, banned bool) error {
+	mgr := st.Managers[name]
+	if mgr == nil {
+		return fmt.Errorf("unknown manager %v", name)
+	}
+	mgr.Banned = banned
+	if banned {
+		writeFile(mgr.bannedFile, nil)
+	} else {
+		os.Remove(mgr.bannedFile)
+	}
+	return nil
+}
+
+// SetLimit sets the maximum number of programs accepted from the manager
+// in a single sync, the rest is dropped. Zero means no limit.
+func (st *State) SetLimit(name string, limit int) error {
+	mgr := st.Managers[name]
+	if mgr == nil {
+		return fmt.Errorf("unknown manager %v", name)
+	}
+	if limit < 0 {
+		return fmt.Errorf("negative limit %v", limit)
+	}
+	mgr.Limit = limit
+	saveSeqFile(mgr.limitFile, uint64(limit))
+	return nil
+}
+
 func (st *State) AddRepro(name string, repro []byte) error {
 	mgr := st.Managers[name]
 	if mgr == nil || mgr.Connected.IsZero() {
 		return fmt.Errorf("unconnected manager %v", name)
 	}
+	if mgr.Banned {
+		return fmt.Errorf("banned manager %v", name)
+	}
 	if _, _, err := prog.CallSet(repro); err != nil {
 		log.Logf(0, "manager %v: failed to extract call set: %v, program:\n%v",
 			mgr.name, err, string(repro))
@@ -307,6 +369,7 @@ func (st *State) pendingInputs(mgr *Manager) ([]rpctype.HubInput, int, error) {
 		Seq uint64
 	}
 	var records []Record
+	sources, filterSources := st.kernelCompatibleManagers(mgr.KernelVersion)
 	for key, rec := range st.Corpus.Records {
 		if mgr.corpusSeq >= rec.Seq {
 			continue
@@ -321,6 +384,9 @@ func (st *State) pendingInputs(mgr *Manager) ([]rpctype.HubInput, int, error) {
 		if !managerSupportsAllCalls(mgr.Calls, calls) {
 			continue
 		}
+		if filterSources && !inputFromManagers(key, sources) {
+			continue
+		}
 		records = append(records, Record{key, rec.Val, rec.Seq})
 	}
 	maxSeq := st.corpusSeq
@@ -379,6 +445,53 @@ func (st *State) inputDomain(key, self string) string {
 	return domain
 }
 
+// kernelCompatibleManagers returns managers that fuzz kernels not newer than the given version
+// (or unknown versions). Inputs that come only from other managers (newer kernels) most likely use
+// interfaces that are not present in the older kernel and would only waste triage time.
+// If filter is false, all managers are compatible and inputs don't need to be filtered.
+// This is computed once per sync, since the check for each input is on the hot path.
+func (st *State) kernelCompatibleManagers(kernel string) (compatible []*Manager, filter bool) {
+	major, minor, ok := parseKernelVersion(kernel)
+	if !ok {
+		return nil, false
+	}
+	for _, mgr := range st.Managers {
+		major1, minor1, ok := parseKernelVersion(mgr.KernelVersion)
+		if !ok || major1 < major || major1 == major && minor1 <= minor {
+			compatible = append(compatible, mgr)
+		} else {
+			filter = true
+		}
+	}
+	return compatible, filter
+}
+
+func inputFromManagers(key string, managers []*Manager) bool {
+	for _, mgr := range managers {
+		if _, ok := mgr.Corpus.Records[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseKernelVersion parses "major.minor" version as sent in rpctype.HubConnectArgs.KernelVersion.
+func parseKernelVersion(ver string) (int, int, bool) {
+	majorStr, minorStr, ok := strings.Cut(ver, ".")
+	if !ok {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
 func (st *State) addInputs(mgr *Manager, inputs [][]byte) {
 	if len(inputs) == 0 {
 		return
@@ -38,7 +38,12 @@ func (ts *TestState) Reload() {
 
 func (ts *TestState) Connect(name, domain string, fresh bool, calls []string, corpus [][]byte) {
 	ts.t.Helper()
-	if err := ts.state.Connect(name, domain, fresh, calls, corpus); err != nil {
+	ts.ConnectKernel(name, domain, "", fresh, calls, corpus)
+}
+
+func (ts *TestState) ConnectKernel(name, domain, kernel string, fresh bool, calls []string, corpus [][]byte) {
+	ts.t.Helper()
+	if err := ts.state.Connect(name, domain, kernel, fresh, calls, corpus); err != nil {
 		ts.t.Fatalf("Connect failed: %v", err)
 	}
 }
@@ -186,3 +191,88 @@ func TestDomain(t *testing.T) {
 		}
 	}
 }
+
+func TestKernelVersion(t *testing.T) {
+	st := MakeTestState(t)
+
+	calls := []string{"open", "read"}
+	st.ConnectKernel("old", "", "5.10", false, calls, nil)
+	st.ConnectKernel("new", "", "6.8", false, calls, nil)
+	st.ConnectKernel("unknown", "", "", false, calls, nil)
+	st.Sync("new", [][]byte{[]byte("open(0x0)"), []byte("read(0x0)")}, nil)
+	st.Sync("old", [][]byte{[]byte("open(0x1)")}, nil)
+	{
+		// open(0x0) is present only on the newer kernel.
+		_, inputs, _ := st.Sync("unknown", [][]byte{[]byte("read(0x1)")}, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Prog: []byte("open(0x0)")},
+			{Prog: []byte("open(0x1)")},
+			{Prog: []byte("read(0x0)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+	{
+		_, inputs, _ := st.Sync("old", nil, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Prog: []byte("read(0x1)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+	st.Reload()
+	st.ConnectKernel("old2", "", "5.4", false, calls, nil)
+	{
+		// Kernel versions of other managers must survive the restart.
+		_, inputs, _ := st.Sync("old2", nil, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Prog: []byte("read(0x1)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
+func TestBanLimit(t *testing.T) {
+	st := MakeTestState(t)
+
+	calls := []string{"open"}
+	st.Connect("foo", "", false, calls, nil)
+	st.Connect("bar", "", false, calls, nil)
+	if err := st.state.SetLimit("baz", 1); err == nil {
+		t.Fatalf("SetLimit for unknown manager succeeded")
+	}
+	if err := st.state.SetLimit("foo", 1); err != nil {
+		t.Fatal(err)
+	}
+	st.Sync("foo", [][]byte{[]byte("open(0x0)"), []byte("open(0x1)")}, nil)
+	if mgr := st.state.Managers["foo"]; mgr.Dropped != 1 || len(mgr.Corpus.Records) != 1 {
+		t.Fatalf("bad limited sync: dropped=%v corpus=%v", mgr.Dropped, len(mgr.Corpus.Records))
+	}
+	if err := st.state.SetBanned("bar", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := st.state.Sync("bar", nil, nil); err == nil {
+		t.Fatalf("sync from banned manager succeeded")
+	}
+	if err := st.state.AddRepro("bar", []byte("open(0x0)")); err == nil {
+		t.Fatalf("repro from banned manager was accepted")
+	}
+	st.Reload()
+	if mgr := st.state.Managers["foo"]; mgr.Limit != 1 || mgr.Banned {
+		t.Fatalf("bad foo state after reload: limit=%v banned=%v", mgr.Limit, mgr.Banned)
+	}
+	if err := st.state.Connect("bar", "", "", false, calls, nil); err == nil {
+		t.Fatalf("connect from banned manager succeeded")
+	}
+	if err := st.state.SetBanned("bar", false); err != nil {
+		t.Fatal(err)
+	}
+	st.Connect("bar", "", false, calls, nil)
+	_, inputs, _ := st.Sync("bar", nil, nil)
+	if diff := cmp.Diff(inputs, []rpctype.HubInput{
+		{Prog: []byte("open(0x0)")},
+	}); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	crash_pkg "github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/vm"
+)
+
+// Userspace core dumps of crashed executors (see executor_core_dumps in the qemu config)
+// are first copied out of the VM into workdir/cores/instance, and then moved into the crash dir
+// next to the error log when the crash is saved.
+const (
+	coreDumpTmpDir = "cores"
+	coreDumpDir    = "core"
+)
+
+// collectCoreDump copies the executor core dump and binary out of the VM after an executor failure.
+// Returns the temporary dir with the files, or an empty string if there is no core dump.
+func (mgr *Manager) collectCoreDump(inst *vm.Instance, instanceName, executorBin string,
+	rep *report.Report) string {
+	if rep.Type != crash_pkg.SyzFailure ||
+		osutil.IsExist(filepath.Join(mgr.crashDir(rep.Title), coreDumpDir)) {
+		return ""
+	}
+	dir := filepath.Join(mgr.cfg.Workdir, coreDumpTmpDir, instanceName)
+	os.RemoveAll(dir)
+	osutil.MkdirAll(dir)
+	files, err := inst.CollectCoreDump(dir, executorBin)
+	if err != nil {
+		mgrLog.Logf(0, "%s: failed to collect executor core dump: %v", instanceName, err)
+	}
+	if err != nil || len(files) == 0 {
+		os.RemoveAll(dir)
+		return ""
+	}
+	mgrLog.Logf(0, "%s: collected executor core dump", instanceName)
+	return dir
+}
+
+// storeCoreDump moves the collected core dump into the crash dir.
+// Only the first core dump for each crash title is kept.
+func (mgr *Manager) storeCoreDump(crash *Crash) {
+	dir := mgr.crashDir(crash.Title)
+	dst := filepath.Join(dir, coreDumpDir)
+	if osutil.IsExist(dst) {
+		os.RemoveAll(crash.cores)
+		return
+	}
+	osutil.MkdirAll(dir)
+	if err := osutil.WriteFile(filepath.Join(dir, "description"), []byte(crash.Title+"\n")); err != nil {
+		mgrLog.Logf(0, "failed to write crash: %v", err)
+	}
+	// Both dirs are in the workdir, so it's a cheap rename.
+	if err := os.Rename(crash.cores, dst); err != nil {
+		mgrLog.Errorf("failed to store executor core dump: %v", err)
+		os.RemoveAll(crash.cores)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	crash_pkg "github.com/google/syzkaller/pkg/report/crash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreCoreDump(t *testing.T) {
+	workdir := t.TempDir()
+	mgr := &Manager{
+		cfg:      &mgrconfig.Config{Workdir: workdir},
+		crashdir: filepath.Join(workdir, "crashes"),
+	}
+	rep := &report.Report{Title: "SYZFATAL: executor crashed: signal: segmentation fault",
+		Type: crash_pkg.SyzFailure}
+	newCores := func(data string) string {
+		dir := filepath.Join(workdir, coreDumpTmpDir, data)
+		osutil.MkdirAll(dir)
+		assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "core.syz-executor.1"), []byte(data)))
+		assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "syz-executor"), []byte("binary")))
+		return dir
+	}
+	first := newCores("first")
+	mgr.storeCoreDump(&Crash{cores: first, Report: rep})
+	assert.False(t, osutil.IsExist(first))
+
+	// Only the first core dump is kept.
+	second := newCores("second")
+	mgr.storeCoreDump(&Crash{cores: second, Report: rep})
+	assert.False(t, osutil.IsExist(second))
+	dir := filepath.Join(mgr.crashDir(rep.Title), coreDumpDir)
+	data, err := os.ReadFile(filepath.Join(dir, "core.syz-executor.1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+	assert.True(t, osutil.IsExist(filepath.Join(dir, "syz-executor")))
+}
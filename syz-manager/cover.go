@@ -8,7 +8,6 @@ import (
 
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/cover/backend"
-	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 )
 
@@ -21,7 +20,7 @@ func getReportGenerator(cfg *mgrconfig.Config, modules []cover.KernelModule) (*c
 	cachedRepGenMu.Lock()
 	defer cachedRepGenMu.Unlock()
 	if cachedRepGen == nil {
-		log.Logf(0, "initializing coverage information...")
+		mgrLog.Logf(0, "initializing coverage information...")
 		rg, err := cover.MakeReportGenerator(cfg, cfg.KernelSubsystem, modules, cfg.RawCover)
 		if err != nil {
 			return nil, err
@@ -13,7 +13,6 @@ import (
 
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/cover/backend"
-	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 )
 
@@ -92,7 +91,7 @@ func covFilterAddFilter(pcs map[uint64]uint32, filters []string, foreach func(fu
 	})
 	for _, re := range res {
 		sort.Strings(used[re])
-		log.Logf(0, "coverage filter: %v: %v", re, used[re])
+		mgrLog.Logf(0, "coverage filter: %v: %v", re, used[re])
 	}
 	if len(res) != len(used) {
 		return fmt.Errorf("some filters don't match anything")
@@ -141,7 +140,7 @@ func createCoverageBitmap(cfg *mgrconfig.Config, pcs map[uint64]uint32) []byte {
 		return nil
 	}
 	start, size := coverageFilterRegion(pcs)
-	log.Logf(0, "coverage filter from 0x%x to 0x%x, size 0x%x, pcs %v", start, start+uint64(size), size, len(pcs))
+	mgrLog.Logf(0, "coverage filter from 0x%x to 0x%x, size 0x%x, pcs %v", start, start+uint64(size), size, len(pcs))
 	// The file starts with two uint32: covFilterStart and covFilterSize,
 	// and a bitmap with size ((covFilterSize>>4)/8+2 bytes follow them.
 	// 8-bit = 1-byte
@@ -0,0 +1,56 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+// descWarningsFile contains warnings produced by the descriptions compiler for the target
+// (calls disabled due to missing consts, suspicious lens, unused consts).
+const descWarningsFile = "description_warnings.json"
+
+type descWarningGroup struct {
+	Kind     string
+	Warnings []prog.DescWarning
+}
+
+// groupDescWarnings groups warnings by kind, groups and warnings inside of groups are sorted.
+func groupDescWarnings(warnings []prog.DescWarning) []descWarningGroup {
+	byKind := make(map[string][]prog.DescWarning)
+	for _, w := range warnings {
+		byKind[w.Kind] = append(byKind[w.Kind], w)
+	}
+	var groups []descWarningGroup
+	for kind, list := range byKind {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Pos != list[j].Pos {
+				return list[i].Pos < list[j].Pos
+			}
+			return list[i].Msg < list[j].Msg
+		})
+		groups = append(groups, descWarningGroup{kind, list})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Kind < groups[j].Kind
+	})
+	return groups
+}
+
+func writeDescWarnings(workdir string, target *prog.Target) error {
+	warnings := target.Warnings
+	if warnings == nil {
+		warnings = []prog.DescWarning{}
+	}
+	data, err := json.MarshalIndent(warnings, "", "\t")
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFile(filepath.Join(workdir, descWarningsFile), data)
+}
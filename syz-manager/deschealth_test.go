@@ -0,0 +1,35 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescWarnings(t *testing.T) {
+	warnings := []prog.DescWarning{
+		{Pos: "b.txt:2:1", Kind: "suspicious-len", Msg: "len target b"},
+		{Pos: "a.txt:9:1", Kind: "missing-const", Msg: "unsupported syscall: foo"},
+		{Pos: "a.txt:1:1", Kind: "missing-const", Msg: "unsupported syscall: bar"},
+	}
+	groups := groupDescWarnings(warnings)
+	assert.Equal(t, []descWarningGroup{
+		{"missing-const", []prog.DescWarning{warnings[2], warnings[1]}},
+		{"suspicious-len", []prog.DescWarning{warnings[0]}},
+	}, groups)
+
+	dir := t.TempDir()
+	assert.NoError(t, writeDescWarnings(dir, &prog.Target{Warnings: warnings}))
+	data, err := os.ReadFile(filepath.Join(dir, descWarningsFile))
+	assert.NoError(t, err)
+	var loaded []prog.DescWarning
+	assert.NoError(t, json.Unmarshal(data, &loaded))
+	assert.ElementsMatch(t, warnings, loaded)
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// Diagnostics of the first machine check are written to workdir/machine_check.txt.
+// The idea is to explain why the fuzzing can't start (or why it will be less efficient)
+// and what can be done about it, instead of looping on obscure VM failures.
+const diagnosticsFile = "machine_check.txt"
+
+// Maximum tolerated difference between the VM and host clocks.
+const maxClockDrift = time.Minute
+
+type diagStatus int
+
+const (
+	diagOK diagStatus = iota
+	diagWarning
+	diagFailure
+)
+
+func (status diagStatus) String() string {
+	switch status {
+	case diagOK:
+		return "OK"
+	case diagWarning:
+		return "WARNING"
+	default:
+		return "FAIL"
+	}
+}
+
+type diagItem struct {
+	name    string
+	status  diagStatus
+	details string
+	hint    string
+}
+
+type diagInput struct {
+	cfg      *mgrconfig.Config
+	files    []*flatrpc.FileInfo
+	features vminfo.Features
+	enabled  int
+	now      time.Time
+}
+
+func machineDiagnostics(in *diagInput) []diagItem {
+	items := []diagItem{
+		diagExecutor(in),
+		diagCoverage(in),
+		diagSyscalls(in),
+	}
+	if in.cfg.TargetOS == targets.Linux {
+		items = append(items, diagNetwork(in), diagDebugfs(in), diagClock(in))
+	}
+	return items
+}
+
+func diagExecutor(in *diagInput) diagItem {
+	item := diagItem{name: "executor"}
+	feat := in.features[flatrpc.FeatureSandboxSetuid]
+	if !feat.Enabled {
+		item.status = diagFailure
+		item.details = fmt.Sprintf("simple program fails with sandbox %q: %v", in.cfg.Sandbox, feat.Reason)
+		item.hint = "check the executor output above; make sure the image has a writable /tmp," +
+			" the kernel supports the requested sandbox (namespaces for sandbox=namespace," +
+			" setuid/nobody user for sandbox=setuid) or try sandbox=none"
+		return item
+	}
+	item.details = fmt.Sprintf("simple program executes with sandbox %q", in.cfg.Sandbox)
+	return item
+}
+
+func diagCoverage(in *diagInput) diagItem {
+	item := diagItem{name: "coverage"}
+	feat := in.features[flatrpc.FeatureCoverage]
+	switch {
+	case !in.cfg.Cover:
+		item.status = diagWarning
+		item.details = "coverage is disabled in the config"
+		item.hint = "fuzzing without coverage is much less efficient, consider setting \"cover\": true"
+	case !feat.Enabled:
+		item.status = diagFailure
+		item.details = fmt.Sprintf("kcov does not work: %v", feat.Reason)
+		item.hint = "build the kernel with CONFIG_KCOV=y and CONFIG_DEBUG_FS=y," +
+			" make sure debugfs is mounted, or set \"cover\": false"
+	default:
+		item.details = "kcov works"
+		if comps := in.features[flatrpc.FeatureComparisons]; !comps.Enabled {
+			item.status = diagWarning
+			item.details += fmt.Sprintf(", but comparisons are not supported: %v", comps.Reason)
+			item.hint = "build the kernel with CONFIG_KCOV_ENABLE_COMPARISONS=y to enable hints"
+		}
+	}
+	return item
+}
+
+func diagNetwork(in *diagInput) diagItem {
+	item := diagItem{name: "network"}
+	var disabled []string
+	for _, feat := range []flatrpc.Feature{flatrpc.FeatureNetInjection, flatrpc.FeatureNetDevices} {
+		if info := in.features[feat]; !info.Enabled {
+			disabled = append(disabled, fmt.Sprintf("%v: %v", flatrpc.EnumNamesFeature[feat], info.Reason))
+		}
+	}
+	if len(disabled) == 0 {
+		item.details = "packet injection and test network devices are set up"
+		return item
+	}
+	item.status = diagWarning
+	item.details = strings.Join(disabled, "; ")
+	item.hint = "enable CONFIG_TUN and the network device drivers listed in docs/linux/kernel_configs.md" +
+		" to fuzz the network stack"
+	return item
+}
+
+func diagSyscalls(in *diagInput) diagItem {
+	item := diagItem{
+		name:    "syscalls",
+		details: fmt.Sprintf("%v/%v enabled", in.enabled, len(in.cfg.Target.Syscalls)),
+	}
+	if in.enabled == 0 {
+		item.status = diagFailure
+		item.hint = "all system calls are disabled; check the enable_syscalls/disable_syscalls" +
+			" config parameters and the list of disabled syscalls above"
+	}
+	return item
+}
+
+func diagDebugfs(in *diagInput) diagItem {
+	item := diagItem{name: "debugfs"}
+	mounts := diagFile(in.files, "/proc/mounts")
+	if mounts == nil {
+		item.status = diagWarning
+		item.details = "failed to read /proc/mounts"
+		return item
+	}
+	for s := bufio.NewScanner(bytes.NewReader(mounts)); s.Scan(); {
+		fields := strings.Fields(s.Text())
+		if len(fields) >= 3 && fields[2] == "debugfs" {
+			item.details = fmt.Sprintf("mounted at %v", fields[1])
+			return item
+		}
+	}
+	item.status = diagWarning
+	item.details = "debugfs is not mounted"
+	item.hint = "kcov and a number of syscall descriptions require debugfs;" +
+		" add \"debugfs /sys/kernel/debug debugfs defaults 0 0\" to /etc/fstab in the image"
+	return item
+}
+
+func diagClock(in *diagInput) diagItem {
+	item := diagItem{name: "clock"}
+	data := diagFile(in.files, "/sys/class/rtc/rtc0/since_epoch")
+	secs, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if data == nil || err != nil {
+		item.status = diagWarning
+		item.details = "failed to read RTC time"
+		item.hint = "VM time can't be verified, crash timestamps in logs may be misleading"
+		return item
+	}
+	drift := time.Unix(secs, 0).Sub(in.now)
+	if drift < 0 {
+		drift = -drift
+	}
+	if drift > maxClockDrift {
+		item.status = diagWarning
+		item.details = fmt.Sprintf("VM clock differs from the host clock by %v", drift.Round(time.Second))
+		item.hint = "make sure the VM RTC is set from the host clock (e.g. qemu -rtc base=utc)"
+		return item
+	}
+	item.details = "VM clock is in sync with the host"
+	return item
+}
+
+func diagFile(files []*flatrpc.FileInfo, name string) []byte {
+	for _, file := range files {
+		if file.Name == name && file.Exists && file.Error == "" {
+			return file.Data
+		}
+	}
+	return nil
+}
+
+func formatDiagnostics(items []diagItem) []byte {
+	buf := new(bytes.Buffer)
+	for _, item := range items {
+		fmt.Fprintf(buf, "%-8v %-10v: %v\n", item.status, item.name, item.details)
+		if item.hint != "" && item.status != diagOK {
+			fmt.Fprintf(buf, "%-19v  hint: %v\n", "", item.hint)
+		}
+	}
+	return buf.Bytes()
+}
+
+// diagnosticsError returns an error describing all failed checks, or nil if none failed.
+func diagnosticsError(items []diagItem) error {
+	var failed []string
+	for _, item := range items {
+		if item.status == diagFailure {
+			failed = append(failed, fmt.Sprintf("%v: %v (%v)", item.name, item.details, item.hint))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("machine check failed:\n%v", strings.Join(failed, "\n"))
+}
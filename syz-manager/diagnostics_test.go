@@ -0,0 +1,129 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachineDiagnostics(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &mgrconfig.Config{
+		Sandbox: "none",
+		Cover:   true,
+	}
+	cfg.Target = target
+	cfg.TargetOS = targets.Linux
+	now := time.Unix(1700000000, 0)
+	allEnabled := func() vminfo.Features {
+		features := make(vminfo.Features)
+		for feat := range flatrpc.EnumNamesFeature {
+			features[feat] = vminfo.Feature{Enabled: true, Reason: "enabled"}
+		}
+		return features
+	}
+	files := func(mounts string, rtc int64) []*flatrpc.FileInfo {
+		return []*flatrpc.FileInfo{
+			{Name: "/proc/mounts", Exists: true, Data: []byte(mounts)},
+			{Name: "/sys/class/rtc/rtc0/since_epoch", Exists: true, Data: []byte(fmt.Sprintf("%v\n", rtc))},
+		}
+	}
+	const debugfsMounts = "proc /proc proc rw 0 0\ndebugfs /sys/kernel/debug debugfs rw 0 0\n"
+
+	type test struct {
+		name     string
+		in       *diagInput
+		statuses map[string]diagStatus
+		fail     bool
+	}
+	tests := []test{
+		{
+			name: "ok",
+			in: &diagInput{
+				cfg:      cfg,
+				files:    files(debugfsMounts, now.Unix()+10),
+				features: allEnabled(),
+				enabled:  10,
+				now:      now,
+			},
+			statuses: map[string]diagStatus{
+				"executor": diagOK,
+				"coverage": diagOK,
+				"syscalls": diagOK,
+				"network":  diagOK,
+				"debugfs":  diagOK,
+				"clock":    diagOK,
+			},
+		},
+		{
+			name: "no-kcov",
+			in: func() *diagInput {
+				features := allEnabled()
+				features[flatrpc.FeatureCoverage] = vminfo.Feature{Reason: "open(/sys/kernel/debug/kcov) failed"}
+				return &diagInput{
+					cfg:      cfg,
+					files:    files("proc /proc proc rw 0 0\n", now.Unix()-3600),
+					features: features,
+					enabled:  10,
+					now:      now,
+				}
+			}(),
+			statuses: map[string]diagStatus{
+				"executor": diagOK,
+				"coverage": diagFailure,
+				"debugfs":  diagWarning,
+				"clock":    diagWarning,
+			},
+			fail: true,
+		},
+		{
+			name: "broken-executor",
+			in: func() *diagInput {
+				features := allEnabled()
+				features[flatrpc.FeatureSandboxSetuid] = vminfo.Feature{Reason: "executor failed"}
+				features[flatrpc.FeatureNetInjection] = vminfo.Feature{Reason: "tun is not supported"}
+				return &diagInput{
+					cfg:      cfg,
+					features: features,
+					now:      now,
+				}
+			}(),
+			statuses: map[string]diagStatus{
+				"executor": diagFailure,
+				"syscalls": diagFailure,
+				"network":  diagWarning,
+				"debugfs":  diagWarning,
+				"clock":    diagWarning,
+			},
+			fail: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items := machineDiagnostics(test.in)
+			statuses := make(map[string]diagStatus)
+			for _, item := range items {
+				statuses[item.name] = item.status
+			}
+			for name, status := range test.statuses {
+				assert.Equal(t, status, statuses[name], name)
+			}
+			err := diagnosticsError(items)
+			assert.Equal(t, test.fail, err != nil, "error: %v", err)
+			t.Logf("\n%s", formatDiagnostics(items))
+		})
+	}
+}
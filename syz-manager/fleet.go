@@ -0,0 +1,427 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/html/pages"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/stats"
+	"github.com/google/syzkaller/prog"
+)
+
+// In the fleet mode the manager periodically polls the /api/summary page of the downstream managers
+// (experimental.fleet config param) and shows their aggregated stats, crashes and corpus on the /fleet page.
+// Every manager serves /api/summary, so downstream managers need no special configuration.
+
+// FleetSummary is the state of a manager served on /api/summary.
+type FleetSummary struct {
+	Name     string
+	Revision string
+	Stats    []FleetStat
+	Corpus   int
+	Crashes  []*FleetCrash
+}
+
+type FleetStat struct {
+	UIStat
+	// Raw value used to compute the fleet total.
+	Raw int
+	// Raw is a mean value, so the total is the mean rather than the sum.
+	Mean bool
+}
+
+type FleetCrash struct {
+	Title    string
+	ID       string
+	Count    int
+	LastTime time.Time
+	Active   bool
+	Triaged  string
+}
+
+const (
+	fleetPollPeriod  = time.Minute
+	fleetPollTimeout = 30 * time.Second
+)
+
+type fleetState struct {
+	mu       sync.Mutex
+	managers []*fleetManager
+}
+
+type fleetManager struct {
+	name string
+	url  string
+	// The last successfully polled summary.
+	summary  *FleetSummary
+	lastPoll time.Time
+	// Error of the last poll (if any).
+	err error
+}
+
+func newFleet(cfgs []mgrconfig.FleetManager) *fleetState {
+	fleet := new(fleetState)
+	for _, cfg := range cfgs {
+		fleet.managers = append(fleet.managers, &fleetManager{
+			name: cfg.Name,
+			url:  fleetURL(cfg.Addr),
+		})
+	}
+	return fleet
+}
+
+func fleetURL(addr string) string {
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+	return strings.TrimSuffix(addr, "/")
+}
+
+func (mgr *Manager) fleetLoop() {
+	client := &http.Client{Timeout: fleetPollTimeout}
+	for {
+		mgr.fleet.poll(client, time.Now())
+		time.Sleep(fleetPollPeriod)
+	}
+}
+
+// poll fetches summaries of all downstream managers in parallel.
+func (fleet *fleetState) poll(client *http.Client, now time.Time) {
+	var wg sync.WaitGroup
+	for _, fm := range fleet.managers {
+		fm := fm
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary, err := fetchFleetSummary(client, fm.url)
+			if err != nil {
+				mgrLog.Logf(1, "fleet: failed to poll %v: %v", fm.url, err)
+			}
+			fleet.mu.Lock()
+			defer fleet.mu.Unlock()
+			fm.err = err
+			if err == nil {
+				fm.summary = summary
+				fm.lastPoll = now
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func fetchFleetSummary(client *http.Client, addr string) (*FleetSummary, error) {
+	resp, err := client.Get(addr + "/api/summary")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with %v", resp.Status)
+	}
+	summary := new(FleetSummary)
+	if err := json.NewDecoder(resp.Body).Decode(summary); err != nil {
+		return nil, fmt.Errorf("failed to decode summary: %w", err)
+	}
+	return summary, nil
+}
+
+func (mgr *Manager) fleetSummary() (*FleetSummary, error) {
+	crashes, err := mgr.collectCrashes(mgr.cfg.Workdir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect crashes: %w", err)
+	}
+	summary := &FleetSummary{
+		Name:     mgr.cfg.Name,
+		Revision: prog.GitRevisionBase,
+	}
+	for _, stat := range stats.Collect(stats.Simple) {
+		summary.Stats = append(summary.Stats, FleetStat{
+			UIStat: UIStat{
+				Name:  stat.Name,
+				Value: stat.Value,
+				Hint:  stat.Desc,
+				Link:  stat.Link,
+			},
+			Raw:  stat.V,
+			Mean: stat.Mean,
+		})
+	}
+	if mgr.corpus != nil {
+		summary.Corpus = len(mgr.corpus.Items())
+	}
+	for _, crash := range crashes {
+		summary.Crashes = append(summary.Crashes, &FleetCrash{
+			Title:    crash.Description,
+			ID:       crash.ID,
+			Count:    crash.Count,
+			LastTime: crash.LastTime,
+			Active:   crash.Active,
+			Triaged:  crash.Triaged,
+		})
+	}
+	return summary, nil
+}
+
+func (mgr *Manager) httpAPISummary(w http.ResponseWriter, r *http.Request) {
+	summary, err := mgr.fleetSummary()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		mgrLog.Logf(0, "failed to encode summary: %v", err)
+	}
+}
+
+func (mgr *Manager) httpFleet(w http.ResponseWriter, r *http.Request) {
+	if mgr.fleet == nil {
+		http.Error(w, "fleet is not configured (see experimental.fleet config param)", http.StatusNotFound)
+		return
+	}
+	local, err := mgr.fleetSummary()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries := []*fleetManager{{name: local.Name, url: "", summary: local, lastPoll: time.Now()}}
+	mgr.fleet.mu.Lock()
+	for _, fm := range mgr.fleet.managers {
+		fmCopy := *fm
+		entries = append(entries, &fmCopy)
+	}
+	mgr.fleet.mu.Unlock()
+	executeTemplate(w, fleetTemplate, aggregateFleet(mgr.cfg.Name, entries))
+}
+
+// aggregateFleet merges summaries of the managers into the UI data.
+// Links to the managers' pages are prefixed with their URLs (empty for the local manager).
+func aggregateFleet(name string, managers []*fleetManager) *UIFleetData {
+	data := &UIFleetData{Name: name}
+	statIndex := make(map[string]int)
+	var totals []fleetTotal
+	crashIndex := make(map[string]*UIFleetCrash)
+	for i, fm := range managers {
+		row := &UIFleetManager{
+			Name:     fm.name,
+			Link:     fm.url + "/",
+			LastPoll: fm.lastPoll,
+		}
+		if fm.err != nil {
+			row.Error = fm.err.Error()
+		}
+		data.Managers = append(data.Managers, row)
+		summary := fm.summary
+		if summary == nil {
+			continue
+		}
+		if row.Name == "" {
+			row.Name = summary.Name
+		}
+		if len(summary.Revision) >= 8 {
+			row.Revision = summary.Revision[:8]
+		}
+		row.Corpus = summary.Corpus
+		row.CrashTypes = len(summary.Crashes)
+		data.Corpus += summary.Corpus
+		for _, stat := range summary.Stats {
+			idx, ok := statIndex[stat.Name]
+			if !ok {
+				idx = len(data.Stats)
+				statIndex[stat.Name] = idx
+				data.Stats = append(data.Stats, &UIFleetStat{
+					Name:   stat.Name,
+					Hint:   stat.Hint,
+					Values: make([]UIStat, len(managers)),
+				})
+				totals = append(totals, fleetTotal{})
+			}
+			totals[idx].sum += stat.Raw
+			totals[idx].count++
+			totals[idx].mean = totals[idx].mean || stat.Mean
+			if stat.Link != "" {
+				stat.Link = fm.url + stat.Link
+			}
+			data.Stats[idx].Values[i] = stat.UIStat
+		}
+		for _, crash := range summary.Crashes {
+			agg := crashIndex[crash.Title]
+			if agg == nil {
+				agg = &UIFleetCrash{Title: crash.Title}
+				crashIndex[crash.Title] = agg
+				data.Crashes = append(data.Crashes, agg)
+			}
+			agg.Count += crash.Count
+			agg.Active = agg.Active || crash.Active
+			if crash.LastTime.After(agg.LastTime) {
+				agg.LastTime = crash.LastTime
+			}
+			if crash.Triaged != "" {
+				agg.Triaged = true
+			}
+			agg.Managers = append(agg.Managers, UIFleetCrashManager{
+				Name:  row.Name,
+				Count: crash.Count,
+				Link:  fm.url + "/crash?id=" + url.QueryEscape(crash.ID),
+			})
+		}
+	}
+	for i, total := range totals {
+		data.Stats[i].Total = total.value()
+	}
+	sort.Slice(data.Crashes, func(i, j int) bool {
+		a, b := data.Crashes[i], data.Crashes[j]
+		if len(a.Managers) != len(b.Managers) {
+			return len(a.Managers) > len(b.Managers)
+		}
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	})
+	return data
+}
+
+type fleetTotal struct {
+	sum   int
+	count int
+	mean  bool
+}
+
+func (total fleetTotal) value() string {
+	if total.mean {
+		return strconv.Itoa(total.sum / total.count)
+	}
+	return strconv.Itoa(total.sum)
+}
+
+type UIFleetData struct {
+	Name     string
+	Managers []*UIFleetManager
+	Stats    []*UIFleetStat
+	Crashes  []*UIFleetCrash
+	Corpus   int
+}
+
+type UIFleetManager struct {
+	Name       string
+	Link       string
+	Revision   string
+	Corpus     int
+	CrashTypes int
+	LastPoll   time.Time
+	Error      string
+}
+
+type UIFleetStat struct {
+	Name   string
+	Hint   string
+	Total  string
+	Values []UIStat // per manager
+}
+
+type UIFleetCrash struct {
+	Title    string
+	Count    int
+	LastTime time.Time
+	Active   bool
+	Triaged  bool
+	Managers []UIFleetCrashManager
+}
+
+type UIFleetCrashManager struct {
+	Name  string
+	Count int
+	Link  string
+}
+
+var fleetTemplate = pages.Create(`
+<!doctype html>
+<html>
+<head>
+	<title>{{.Name}} syzkaller fleet</title>
+	{{HEAD}}
+</head>
+<body>
+
+<table class="list_table">
+	<caption>Managers ({{len $.Managers}}, total corpus {{$.Corpus}}):</caption>
+	<tr>
+		<th>Name</th>
+		<th>Revision</th>
+		<th>Corpus</th>
+		<th>Crash types</th>
+		<th>Last poll</th>
+		<th>Error</th>
+	</tr>
+	{{range $m := $.Managers}}
+	<tr>
+		<td><a href="{{$m.Link}}">{{$m.Name}}</a></td>
+		<td>{{$m.Revision}}</td>
+		<td><a href="{{$m.Link}}corpus">{{$m.Corpus}}</a></td>
+		<td>{{$m.CrashTypes}}</td>
+		<td class="time">{{formatTime $m.LastPoll}}</td>
+		<td>{{$m.Error}}</td>
+	</tr>
+	{{end}}
+</table>
+
+<table class="list_table">
+	<caption>Stats:</caption>
+	<tr>
+		<th></th>
+		<th>Total</th>
+		{{range $m := $.Managers}}
+		<th><a href="{{$m.Link}}">{{$m.Name}}</a></th>
+		{{end}}
+	</tr>
+	{{range $s := $.Stats}}
+	<tr>
+		<td class="stat_name" title="{{$s.Hint}}">{{$s.Name}}</td>
+		<td class="stat_value">{{$s.Total}}</td>
+		{{range $v := $s.Values}}
+		<td class="stat_value">
+			{{if $v.Link}}
+				<a href="{{$v.Link}}">{{$v.Value}}</a>
+			{{else}}
+				{{$v.Value}}
+			{{end}}
+		</td>
+		{{end}}
+	</tr>
+	{{end}}
+</table>
+
+<table class="list_table">
+	<caption>Crashes ({{len $.Crashes}}):</caption>
+	<tr>
+		<th><a onclick="return sortTable(this, 'Description', textSort)" href="#">Description</a></th>
+		<th><a onclick="return sortTable(this, 'Count', numSort)" href="#">Count</a></th>
+		<th><a onclick="return sortTable(this, 'Last Time', textSort, true)" href="#">Last Time</a></th>
+		<th>Repro</th>
+		<th>Managers</th>
+	</tr>
+	{{range $c := $.Crashes}}
+	<tr>
+		<td class="title">{{$c.Title}}</td>
+		<td class="stat {{if not $c.Active}}inactive{{end}}">{{$c.Count}}</td>
+		<td class="time {{if not $c.Active}}inactive{{end}}">{{formatTime $c.LastTime}}</td>
+		<td>{{if $c.Triaged}}yes{{end}}</td>
+		<td>
+			{{range $m := $c.Managers}}
+				<a href="{{$m.Link}}">{{$m.Name}} ({{$m.Count}})</a>
+			{{end}}
+		</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
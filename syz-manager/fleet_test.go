@@ -0,0 +1,110 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFleet(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	serve := func(summary *FleetSummary) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/summary" {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(summary)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	branch := serve(&FleetSummary{
+		Name:     "ci-branch",
+		Revision: "0123456789abcdef",
+		Stats: []FleetStat{
+			{UIStat: UIStat{Name: "corpus", Value: "10", Link: "/corpus"}, Raw: 10},
+			{UIStat: UIStat{Name: "prog exec time", Value: "4"}, Raw: 4, Mean: true},
+		},
+		Corpus: 10,
+		Crashes: []*FleetCrash{
+			{Title: "KASAN: use-after-free in foo", ID: "id1", Count: 2, LastTime: now, Triaged: "has repro"},
+			{Title: "WARNING in bar", ID: "id2", Count: 1, LastTime: now.Add(-time.Hour)},
+		},
+	})
+	device := serve(&FleetSummary{
+		Name: "ci-device",
+		Stats: []FleetStat{
+			{UIStat: UIStat{Name: "corpus", Value: "20"}, Raw: 20},
+			{UIStat: UIStat{Name: "prog exec time", Value: "8"}, Raw: 8, Mean: true},
+		},
+		Corpus: 20,
+		Crashes: []*FleetCrash{
+			{Title: "WARNING in bar", ID: "id3", Count: 3, LastTime: now, Active: true},
+		},
+	})
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+
+	fleet := newFleet([]mgrconfig.FleetManager{
+		{Addr: branch.URL},
+		{Name: "device", Addr: device.URL + "/"},
+		{Addr: broken.URL},
+	})
+	fleet.poll(http.DefaultClient, now)
+	data := aggregateFleet("central", fleet.managers)
+
+	assert.Len(t, data.Managers, 3)
+	assert.Equal(t, "ci-branch", data.Managers[0].Name)
+	assert.Equal(t, "01234567", data.Managers[0].Revision)
+	assert.Equal(t, "device", data.Managers[1].Name)
+	assert.Equal(t, device.URL+"/", data.Managers[1].Link)
+	assert.NotEmpty(t, data.Managers[2].Error)
+	assert.True(t, data.Managers[2].LastPoll.IsZero())
+	assert.Equal(t, 30, data.Corpus)
+
+	assert.Len(t, data.Stats, 2)
+	assert.Equal(t, []UIStat{
+		{Name: "corpus", Value: "10", Link: branch.URL + "/corpus"},
+		{Name: "corpus", Value: "20"},
+		{},
+	}, data.Stats[0].Values)
+	assert.Equal(t, "30", data.Stats[0].Total)
+	assert.Equal(t, "6", data.Stats[1].Total)
+
+	// The crash seen by both managers goes first.
+	assert.Equal(t, []*UIFleetCrash{
+		{
+			Title:    "WARNING in bar",
+			Count:    4,
+			LastTime: now,
+			Active:   true,
+			Managers: []UIFleetCrashManager{
+				{Name: "ci-branch", Count: 1, Link: branch.URL + "/crash?id=id2"},
+				{Name: "device", Count: 3, Link: device.URL + "/crash?id=id3"},
+			},
+		},
+		{
+			Title:    "KASAN: use-after-free in foo",
+			Count:    2,
+			LastTime: now,
+			Triaged:  true,
+			Managers: []UIFleetCrashManager{
+				{Name: "ci-branch", Count: 2, Link: branch.URL + "/crash?id=id1"},
+			},
+		},
+	}, data.Crashes)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, fleetTemplate.Execute(buf, data))
+	assert.Contains(t, buf.String(), device.URL+"/crash?id=id3")
+}
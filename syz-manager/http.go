@@ -20,10 +20,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/asset"
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/html/pages"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
+	crash_pkg "github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/stats"
 	"github.com/google/syzkaller/pkg/vcs"
 	"github.com/google/syzkaller/prog"
@@ -42,12 +45,20 @@ func (mgr *Manager) initHTTP() {
 	handle("/stats", mgr.httpStats)
 	handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}).ServeHTTP)
 	handle("/syscalls", mgr.httpSyscalls)
+	handle("/descriptions", mgr.httpDescriptions)
 	handle("/corpus", mgr.httpCorpus)
 	handle("/corpus.db", mgr.httpDownloadCorpus)
+	handle("/snapshot", mgr.httpSnapshot)
 	handle("/crash", mgr.httpCrash)
+	handle("/hunt", mgr.httpHunt)
+	handle("/ratelimit", mgr.httpRateLimit)
+	handle("/repro_queue", mgr.httpReproQueue)
+	handle("/fleet", mgr.httpFleet)
+	handle("/api/summary", mgr.httpAPISummary)
 	handle("/cover", mgr.httpCover)
 	handle("/subsystemcover", mgr.httpSubsystemCover)
 	handle("/modulecover", mgr.httpModuleCover)
+	handle("/subsystemranking", mgr.httpSubsystemRanking)
 	handle("/prio", mgr.httpPrio)
 	handle("/file", mgr.httpFile)
 	handle("/report", mgr.httpReport)
@@ -58,11 +69,12 @@ func (mgr *Manager) initHTTP() {
 	handle("/filecover", mgr.httpFileCover)
 	handle("/input", mgr.httpInput)
 	handle("/debuginput", mgr.httpDebugInput)
+	handle("/program", mgr.httpProgram)
 	handle("/modules", mgr.modulesInfo)
 	// Browsers like to request this, without special handler this goes to / handler.
 	handle("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
 
-	log.Logf(0, "serving http on http://%v", mgr.cfg.HTTP)
+	mgrLog.Logf(0, "serving http on http://%v", mgr.cfg.HTTP)
 	go func() {
 		err := http.ListenAndServe(mgr.cfg.HTTP, nil)
 		if err != nil {
@@ -77,6 +89,8 @@ func (mgr *Manager) httpSummary(w http.ResponseWriter, r *http.Request) {
 		Revision:     prog.GitRevisionBase[:8],
 		RevisionLink: vcs.LogLink(vcs.SyzkallerRepo, prog.GitRevisionBase),
 		Expert:       mgr.expertMode,
+		Hunt:         mgr.huntTitle(),
+		Fleet:        mgr.fleet != nil,
 		Log:          log.CachedLogOutput(),
 	}
 
@@ -138,10 +152,18 @@ func (mgr *Manager) httpSyscalls(w http.ResponseWriter, r *http.Request) {
 	executeTemplate(w, syscallsTemplate, data)
 }
 
+func (mgr *Manager) httpDescriptions(w http.ResponseWriter, r *http.Request) {
+	data := &UIDescriptionsData{
+		Name:   mgr.cfg.Name,
+		Groups: groupDescWarnings(mgr.target.Warnings),
+	}
+	executeTemplate(w, descriptionsTemplate, data)
+}
+
 func (mgr *Manager) httpStats(w http.ResponseWriter, r *http.Request) {
 	data, err := stats.RenderHTML()
 	if err != nil {
-		log.Logf(0, "failed to execute template: %v", err)
+		mgrLog.Logf(0, "failed to execute template: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -155,6 +177,7 @@ func (mgr *Manager) httpCrash(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to read crash info", http.StatusInternalServerError)
 		return
 	}
+	mgr.fillRateLimit(crash)
 	executeTemplate(w, crashTemplate, crash)
 }
 
@@ -183,6 +206,14 @@ func (mgr *Manager) httpCorpus(w http.ResponseWriter, r *http.Request) {
 		}
 		return a.Short < b.Short
 	})
+	if r.FormValue("json") == "1" {
+		// Used by tools/syz-corpus-analyze.
+		w.Header().Set("Content-Type", ctApplicationJSON)
+		if err := json.NewEncoder(w).Encode(data.Inputs); err != nil {
+			mgrLog.Logf(0, "failed to encode corpus: %v", err)
+		}
+		return
+	}
 	executeTemplate(w, corpusTemplate, data)
 }
 
@@ -212,6 +243,8 @@ const (
 	DoRawCover
 	DoFilterPCs
 	DoCoverJSONL
+	DoSubsystemRanking
+	DoSubsystemRankingJSON
 )
 
 func (mgr *Manager) httpCover(w http.ResponseWriter, r *http.Request) {
@@ -234,6 +267,18 @@ func (mgr *Manager) httpSubsystemCover(w http.ResponseWriter, r *http.Request) {
 	mgr.httpCoverCover(w, r, DoHTMLTable)
 }
 
+func (mgr *Manager) httpSubsystemRanking(w http.ResponseWriter, r *http.Request) {
+	if !mgr.cfg.Cover {
+		mgr.httpCoverFallback(w, r)
+		return
+	}
+	if r.FormValue("json") == "1" {
+		mgr.httpCoverCover(w, r, DoSubsystemRankingJSON)
+		return
+	}
+	mgr.httpCoverCover(w, r, DoSubsystemRanking)
+}
+
 func (mgr *Manager) httpModuleCover(w http.ResponseWriter, r *http.Request) {
 	if !mgr.cfg.Cover {
 		mgr.httpCoverFallback(w, r)
@@ -258,7 +303,8 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 		return
 	}
 
-	rg, err := getReportGenerator(mgr.cfg, mgr.serv.modules)
+	modules, _ := mgr.serv.kernelModules()
+	rg, err := getReportGenerator(mgr.cfg, modules)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
 		return
@@ -290,6 +336,17 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 				Data: string(inp.ProgData),
 				PCs:  coverToPCs(mgr.cfg, inp.Updates[updateID].RawCover),
 			})
+		} else if r.FormValue("call_id") != "" {
+			callID, err := strconv.Atoi(r.FormValue("call_id"))
+			if err != nil || callID < -1 || callID >= len(inp.Prog.Calls) {
+				http.Error(w, "bad call_id", http.StatusBadRequest)
+				return
+			}
+			progs = append(progs, cover.Prog{
+				Sig:  sig,
+				Data: string(inp.ProgData),
+				PCs:  coverToPCs(mgr.cfg, inp.CallCover(callID)),
+			})
 		} else {
 			progs = append(progs, cover.Prog{
 				Sig:  sig,
@@ -333,15 +390,17 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 		Do          handlerFuncType
 		contentType string
 	}{
-		DoHTML:          {rg.DoHTML, ""},
-		DoHTMLTable:     {rg.DoHTMLTable, ""},
-		DoModuleCover:   {rg.DoModuleCover, ""},
-		DoCSV:           {rg.DoCSV, ctTextPlain},
-		DoCSVFiles:      {rg.DoCSVFiles, ctTextPlain},
-		DoRawCoverFiles: {rg.DoRawCoverFiles, ctTextPlain},
-		DoRawCover:      {rg.DoRawCover, ctTextPlain},
-		DoFilterPCs:     {rg.DoFilterPCs, ctTextPlain},
-		DoCoverJSONL:    {rg.DoCoverJSONL, ctApplicationJSON},
+		DoHTML:                 {rg.DoHTML, ""},
+		DoHTMLTable:            {rg.DoHTMLTable, ""},
+		DoModuleCover:          {rg.DoModuleCover, ""},
+		DoCSV:                  {rg.DoCSV, ctTextPlain},
+		DoCSVFiles:             {rg.DoCSVFiles, ctTextPlain},
+		DoRawCoverFiles:        {rg.DoRawCoverFiles, ctTextPlain},
+		DoRawCover:             {rg.DoRawCover, ctTextPlain},
+		DoFilterPCs:            {rg.DoFilterPCs, ctTextPlain},
+		DoCoverJSONL:           {rg.DoCoverJSONL, ctApplicationJSON},
+		DoSubsystemRanking:     {rg.DoSubsystemRanking, ""},
+		DoSubsystemRankingJSON: {rg.DoSubsystemRankingJSON, ctApplicationJSON},
 	}
 
 	if ct := flagToFunc[funcFlag].contentType; ct != "" {
@@ -488,12 +547,13 @@ func (mgr *Manager) httpDebugInput(w http.ResponseWriter, r *http.Request) {
 }
 
 func (mgr *Manager) modulesInfo(w http.ResponseWriter, r *http.Request) {
-	if mgr.serv.canonicalModules == nil {
+	kernelModules, canonicalModules := mgr.serv.kernelModules()
+	if canonicalModules == nil {
 		fmt.Fprintf(w, "module information not retrieved yet, please retry after fuzzing starts\n")
 		return
 	}
 	// NewCanonicalizer() is initialized with serv.modules.
-	modules, err := json.MarshalIndent(mgr.serv.modules, "", "\t")
+	modules, err := json.MarshalIndent(kernelModules, "", "\t")
 	if err != nil {
 		fmt.Fprintf(w, "unable to create JSON modules info: %v", err)
 		return
@@ -527,6 +587,7 @@ func (mgr *Manager) httpReport(w http.ResponseWriter, r *http.Request) {
 	prog, _ := os.ReadFile(filepath.Join(mgr.crashdir, crashID, "repro.prog"))
 	cprog, _ := os.ReadFile(filepath.Join(mgr.crashdir, crashID, "repro.cprog"))
 	rep, _ := os.ReadFile(filepath.Join(mgr.crashdir, crashID, "repro.report"))
+	reliability, _ := os.ReadFile(filepath.Join(mgr.crashdir, crashID, "repro.reliability"))
 
 	commitDesc := ""
 	if len(tag) != 0 {
@@ -539,6 +600,9 @@ func (mgr *Manager) httpReport(w http.ResponseWriter, r *http.Request) {
 	if len(prog) == 0 && len(cprog) == 0 {
 		fmt.Fprintf(w, "The bug is not reproducible.\n")
 	} else {
+		if len(reliability) != 0 {
+			fmt.Fprintf(w, "The reproducer triggered the crash in %s reruns.\n\n", reliability)
+		}
 		fmt.Fprintf(w, "Syzkaller reproducer:\n%s\n\n", prog)
 		if len(cprog) != 0 {
 			fmt.Fprintf(w, "C reproducer:\n%s\n\n", cprog)
@@ -560,9 +624,7 @@ func (mgr *Manager) httpFilterPCs(w http.ResponseWriter, r *http.Request) {
 
 func (mgr *Manager) collectCrashes(workdir string) ([]*UICrashType, error) {
 	// Note: mu is not locked here.
-	reproReply := make(chan map[string]bool)
-	mgr.reproRequest <- reproReply
-	repros := <-reproReply
+	repros := mgr.reproQueue.running()
 
 	crashdir := filepath.Join(workdir, "crashes")
 	dirs, err := osutil.ListDir(crashdir)
@@ -573,6 +635,7 @@ func (mgr *Manager) collectCrashes(workdir string) ([]*UICrashType, error) {
 	for _, dir := range dirs {
 		crash := readCrash(workdir, dir, repros, mgr.firstConnect.Load(), false)
 		if crash != nil {
+			mgr.fillRateLimit(crash)
 			crashTypes = append(crashTypes, crash)
 		}
 	}
@@ -612,6 +675,7 @@ func readCrash(workdir, dir string, repros map[string]bool, start int64, full bo
 	reproAttempts := 0
 	hasRepro, hasCRepro := false, false
 	strace := ""
+	severity := crash_pkg.UnknownSeverity
 	reports := make(map[string]bool)
 	for _, f := range files {
 		if strings.HasPrefix(f, "log") {
@@ -623,6 +687,11 @@ func readCrash(workdir, dir string, repros map[string]bool, start int64, full bo
 			}
 		} else if strings.HasPrefix(f, "report") {
 			reports[f] = true
+		} else if strings.HasPrefix(f, "severity") {
+			data, _ := os.ReadFile(filepath.Join(crashdir, dir, f))
+			if s, ok := crash_pkg.ParseSeverity(string(data)); ok {
+				severity = max(severity, s)
+			}
 		} else if f == "repro.prog" {
 			hasRepro = true
 		} else if f == "repro.cprog" {
@@ -649,13 +718,30 @@ func readCrash(workdir, dir string, repros map[string]bool, start int64, full bo
 			if osutil.IsExist(filepath.Join(workdir, reportFile)) {
 				crash.Report = reportFile
 			}
+			commitsFile := filepath.Join("crashes", dir, "commits"+index)
+			if osutil.IsExist(filepath.Join(workdir, commitsFile)) {
+				crash.Commits = commitsFile
+			}
+			secondaryFile := filepath.Join("crashes", dir, "secondary"+index)
+			if osutil.IsExist(filepath.Join(workdir, secondaryFile)) {
+				crash.Secondary = secondaryFile
+			}
 		}
 		sort.Slice(crashes, func(i, j int) bool {
 			return crashes[i].Time.After(crashes[j].Time)
 		})
 	}
 
+	var assets []UIAsset
+	if full {
+		assets = readCrashAssets(filepath.Join(crashdir, dir, "repro.assets"))
+	}
 	triaged := reproStatus(hasRepro, hasCRepro, repros[desc], reproAttempts >= maxReproAttempts)
+	if hasRepro {
+		if reliability, err := os.ReadFile(filepath.Join(crashdir, dir, "repro.reliability")); err == nil {
+			triaged += fmt.Sprintf(" (%s)", reliability)
+		}
+	}
 	return &UICrashType{
 		Description: desc,
 		LastTime:    modTime,
@@ -663,9 +749,32 @@ func readCrash(workdir, dir string, repros map[string]bool, start int64, full bo
 		ID:          dir,
 		Count:       len(crashes),
 		Triaged:     triaged,
+		HasRepro:    hasRepro,
+		Severity:    severity,
 		Strace:      strace,
 		Crashes:     crashes,
+		Assets:      assets,
+	}
+}
+
+func readCrashAssets(file string) []UIAsset {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var assets []dashapi.NewAsset
+	if err := json.Unmarshal(data, &assets); err != nil {
+		return nil
+	}
+	var ret []UIAsset
+	for _, a := range assets {
+		title := string(a.Type)
+		if desc := asset.GetTypeDescription(a.Type); desc != nil {
+			title = desc.GetTitle(nil)
+		}
+		ret = append(ret, UIAsset{Title: title, DownloadURL: a.DownloadURL})
 	}
+	return ret
 }
 
 func reproStatus(hasRepro, hasCRepro, reproducing, nonReproducible bool) string {
@@ -686,7 +795,7 @@ func reproStatus(hasRepro, hasCRepro, reproducing, nonReproducible bool) string
 func executeTemplate(w http.ResponseWriter, templ *template.Template, data interface{}) {
 	buf := new(bytes.Buffer)
 	if err := templ.Execute(buf, data); err != nil {
-		log.Logf(0, "failed to execute template: %v", err)
+		mgrLog.Logf(0, "failed to execute template: %v", err)
 		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -705,6 +814,8 @@ type UISummaryData struct {
 	Revision     string
 	RevisionLink string
 	Expert       bool
+	Hunt         string
+	Fleet        bool
 	Stats        []UIStat
 	Crashes      []*UICrashType
 	Log          string
@@ -715,6 +826,11 @@ type UISyscallsData struct {
 	Calls []UICallType
 }
 
+type UIDescriptionsData struct {
+	Name   string
+	Groups []descWarningGroup
+}
+
 type UICrashType struct {
 	Description string
 	LastTime    time.Time
@@ -722,17 +838,31 @@ type UICrashType struct {
 	ID          string
 	Count       int
 	Triaged     string
+	HasRepro    bool
+	Severity    crash_pkg.Severity // max severity of the saved crashes
 	Strace      string
 	Crashes     []*UICrash
+	Assets      []UIAsset
+	// Rate limiting state, if experimental.crash_rate_limit is enabled.
+	RateLimiting bool
+	RateLimited  bool
+	RateLimitOff bool
+}
+
+type UIAsset struct {
+	Title       string
+	DownloadURL string
 }
 
 type UICrash struct {
-	Index  int
-	Time   time.Time
-	Active bool
-	Log    string
-	Report string
-	Tag    string
+	Index     int
+	Time      time.Time
+	Active    bool
+	Log       string
+	Report    string
+	Commits   string
+	Secondary string
+	Tag       string
 }
 
 type UIStat struct {
@@ -771,9 +901,16 @@ var summaryTemplate = pages.Create(`
 <body>
 <b>{{.Name }} syzkaller</b>
 <a href='/config'>[config]</a>
+{{if .Fleet}}<a href='/fleet'>[fleet]</a>{{end}}
 <a href='{{.RevisionLink}}'>{{.Revision}}</a>
 <a class="navigation_tab" href='expert_mode'>{{if .Expert}}disable{{else}}enable{{end}} expert mode</a>
 <br>
+{{if .Hunt}}
+<form method="post" action="/hunt">
+	<b>Hunting for:</b> <a href="/hunt">{{.Hunt}}</a>
+	<input type="submit" value="stop">
+</form>
+{{end}}
 
 <table class="list_table">
 	<caption><a href='/stats'>Stats 📈</a></caption>
@@ -796,13 +933,18 @@ var summaryTemplate = pages.Create(`
 	<tr>
 		<th><a onclick="return sortTable(this, 'Description', textSort)" href="#">Description</a></th>
 		<th><a onclick="return sortTable(this, 'Count', numSort)" href="#">Count</a></th>
+		<th><a onclick="return sortTable(this, 'Severity', numSort)" href="#">Severity</a></th>
 		<th><a onclick="return sortTable(this, 'Last Time', textSort, true)" href="#">Last Time</a></th>
 		<th><a onclick="return sortTable(this, 'Report', textSort)" href="#">Report</a></th>
 	</tr>
 	{{range $c := $.Crashes}}
 	<tr>
-		<td class="title"><a href="/crash?id={{$c.ID}}">{{$c.Description}}</a></td>
+		<td class="title">
+			<a href="/crash?id={{$c.ID}}">{{$c.Description}}</a>
+			{{if $c.RateLimited}}<span title="crashes too often and is treated as suppressed">[rate limited]</span>{{end}}
+		</td>
 		<td class="stat {{if not $c.Active}}inactive{{end}}">{{$c.Count}}</td>
+		<td sort-value="{{printf "%d" $c.Severity}}">{{if $c.Severity}}{{$c.Severity}}{{end}}</td>
 		<td class="time {{if not $c.Active}}inactive{{end}}">{{formatTime $c.LastTime}}</td>
 		<td>
 			{{if $c.Triaged}}
@@ -857,6 +999,37 @@ var syscallsTemplate = pages.Create(`
 </body></html>
 `)
 
+var descriptionsTemplate = pages.Create(`
+<!doctype html>
+<html>
+<head>
+	<title>{{.Name }} syzkaller</title>
+	{{HEAD}}
+</head>
+<body>
+
+{{if not $.Groups}}
+<b>The descriptions compiler did not produce any warnings.</b>
+{{end}}
+{{range $g := $.Groups}}
+<table class="list_table">
+	<caption>Description warnings: {{$g.Kind}} ({{len $g.Warnings}})</caption>
+	<tr>
+		<th><a onclick="return sortTable(this, 'Location', textSort)" href="#">Location</a></th>
+		<th><a onclick="return sortTable(this, 'Warning', textSort)" href="#">Warning</a></th>
+	</tr>
+	{{range $w := $g.Warnings}}
+	<tr>
+		<td>{{$w.Pos}}</td>
+		<td>{{$w.Msg}}</td>
+	</tr>
+	{{end}}
+</table>
+<br>
+{{end}}
+</body></html>
+`)
+
 var crashTemplate = pages.Create(`
 <!doctype html>
 <html>
@@ -866,16 +1039,40 @@ var crashTemplate = pages.Create(`
 </head>
 <body>
 <b>{{.Description}}</b>
+<form method="post" action="/hunt">
+	<input type="hidden" name="title" value="{{.Description}}">
+	<input type="submit" value="hunt" title="focus fuzzing on reproduction of this crash">
+</form>
+{{if .RateLimiting}}
+<form method="post" action="/ratelimit">
+	{{if .RateLimited}}<b>Rate limited:</b> the crash happens too often and is treated as suppressed.{{end}}
+	<input type="hidden" name="title" value="{{.Description}}">
+	{{if .RateLimitOff}}
+	<input type="submit" value="enable rate limiting">
+	{{else}}
+	<input type="hidden" name="disable" value="1">
+	<input type="submit" value="disable rate limiting" title="always save, report and reproduce this crash">
+	{{end}}
+</form>
+{{end}}
 
 {{if .Triaged}}
 Report: <a href="/report?id={{.ID}}">{{.Triaged}}</a>
 {{end}}
+{{if .HasRepro}}
+<br><a href="/program?crash={{.ID}}">Repro program</a>
+{{end}}
+{{range $a := .Assets}}
+<br><a href="{{$a.DownloadURL}}">{{$a.Title}}</a>
+{{end}}
 
 <table class="list_table">
 	<tr>
 		<th>#</th>
 		<th>Log</th>
 		<th>Report</th>
+		<th>Commits</th>
+		<th>Secondary</th>
 		<th>Time</th>
 		<th>Tag</th>
 	</tr>
@@ -888,6 +1085,16 @@ Report: <a href="/report?id={{.ID}}">{{.Triaged}}</a>
 				<a href="/file?name={{$c.Report}}">report</a></td>
 			{{end}}
 		</td>
+		<td>
+			{{if $c.Commits}}
+				<a href="/file?name={{$c.Commits}}">commits</a>
+			{{end}}
+		</td>
+		<td>
+			{{if $c.Secondary}}
+				<a href="/file?name={{$c.Secondary}}">secondary</a>
+			{{end}}
+		</td>
 		<td class="time {{if not $c.Active}}inactive{{end}}">{{formatTime $c.Time}}</td>
 		<td class="tag {{if not $c.Active}}inactive{{end}}" title="{{$c.Tag}}">{{formatTagHash $c.Tag}}</td>
 	</tr>
@@ -919,7 +1126,7 @@ var corpusTemplate = pages.Create(`
 		/ <a href="/debuginput?sig={{$inp.Sig}}">[raw]</a>
 	{{end}}
 		</td>
-		<td><a href="/input?sig={{$inp.Sig}}">{{$inp.Short}}</a></td>
+		<td><a href="/program?sig={{$inp.Sig}}">{{$inp.Short}}</a></td>
 	</tr>
 	{{end}}
 </table>
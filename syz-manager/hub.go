@@ -6,6 +6,7 @@ package main
 import (
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/syzkaller/pkg/auth"
@@ -43,6 +44,7 @@ func (mgr *Manager) hubSyncLoop(keyGet keyGetter) {
 		cfg:           mgr.cfg,
 		target:        mgr.target,
 		domain:        mgr.cfg.TargetOS + "/" + mgr.cfg.HubDomain,
+		kernelVersion: mgr.serv.kernelVersion,
 		enabledCalls:  mgr.targetEnabledSyscalls,
 		leak:          mgr.enabledFeatures&flatrpc.FeatureLeak != 0,
 		fresh:         mgr.fresh,
@@ -60,6 +62,7 @@ func (mgr *Manager) hubSyncLoop(keyGet keyGetter) {
 	if mgr.cfg.Reproduce && mgr.dash != nil {
 		hc.needMoreRepros = mgr.needMoreRepros
 	}
+	mgr.hubConn.Store(hc)
 	hc.loop()
 }
 
@@ -68,10 +71,12 @@ type HubConnector struct {
 	cfg            *mgrconfig.Config
 	target         *prog.Target
 	domain         string
+	kernelVersion  string
 	enabledCalls   map[*prog.Syscall]bool
 	leak           bool
 	fresh          bool
 	hubCorpus      map[hash.Sig]bool
+	newReprosMu    sync.Mutex // newRepros are also read by the preemption checkpoint
 	newRepros      [][]byte
 	hubReproQueue  chan *Crash
 	needMoreRepros chan chan bool
@@ -98,18 +103,20 @@ func (hc *HubConnector) loop() {
 	var doneOnce bool
 	for query := 0; ; time.Sleep(10 * time.Minute) {
 		corpus, repros := hc.mgr.getMinimizedCorpus()
+		hc.newReprosMu.Lock()
 		hc.newRepros = append(hc.newRepros, repros...)
+		hc.newReprosMu.Unlock()
 		if hub == nil {
 			var err error
 			if hub, err = hc.connect(corpus); err != nil {
-				log.Logf(0, "failed to connect to hub at %v: %v", hc.cfg.HubAddr, err)
+				mgrLog.Logf(0, "failed to connect to hub at %v: %v", hc.cfg.HubAddr, err)
 			} else {
-				log.Logf(0, "connected to hub at %v, corpus %v", hc.cfg.HubAddr, len(corpus))
+				mgrLog.Logf(0, "connected to hub at %v, corpus %v", hc.cfg.HubAddr, len(corpus))
 			}
 		}
 		if hub != nil {
 			if err := hc.sync(hub, corpus); err != nil {
-				log.Logf(0, "hub sync failed: %v", err)
+				mgrLog.Logf(0, "hub sync failed: %v", err)
 				hub.Close()
 				hub = nil
 			} else {
@@ -134,11 +141,12 @@ func (hc *HubConnector) connect(corpus [][]byte) (*rpctype.RPCClient, error) {
 		return nil, err
 	}
 	a := &rpctype.HubConnectArgs{
-		Client:  hc.cfg.HubClient,
-		Key:     key,
-		Manager: hc.cfg.Name,
-		Domain:  hc.domain,
-		Fresh:   hc.fresh,
+		Client:        hc.cfg.HubClient,
+		Key:           key,
+		Manager:       hc.cfg.Name,
+		Domain:        hc.domain,
+		KernelVersion: hc.kernelVersion,
+		Fresh:         hc.fresh,
 	}
 	for call := range hc.enabledCalls {
 		a.Calls = append(a.Calls, call.Name)
@@ -203,7 +211,9 @@ func (hc *HubConnector) sync(hub *rpctype.RPCClient, corpus [][]byte) error {
 		hc.needMoreRepros <- needReproReply
 		a.NeedRepros = <-needReproReply
 	}
+	hc.newReprosMu.Lock()
 	a.Repros = hc.newRepros
+	hc.newReprosMu.Unlock()
 	for {
 		r := new(rpctype.HubSyncRes)
 		if err := hub.Call("Hub.Sync", a, r); err != nil {
@@ -218,7 +228,7 @@ func (hc *HubConnector) sync(hub *rpctype.RPCClient, corpus [][]byte) error {
 		hc.statRecvProgDrop.Add(progDropped)
 		hc.statRecvRepro.Add(len(r.Repros) - reproDropped)
 		hc.statRecvReproDrop.Add(reproDropped)
-		log.Logf(0, "hub sync: send: add %v, del %v, repros %v;"+
+		mgrLog.Logf(0, "hub sync: send: add %v, del %v, repros %v;"+
 			" recv: progs %v (min %v, smash %v), repros %v; more %v",
 			len(a.Add), len(a.Del), len(a.Repros),
 			len(r.Inputs)-progDropped, minimized, smashed,
@@ -227,7 +237,9 @@ func (hc *HubConnector) sync(hub *rpctype.RPCClient, corpus [][]byte) error {
 		a.Del = nil
 		a.Repros = nil
 		a.NeedRepros = false
+		hc.newReprosMu.Lock()
 		hc.newRepros = nil
+		hc.newReprosMu.Unlock()
 		if len(r.Inputs)+r.More == 0 {
 			return nil
 		}
@@ -239,7 +251,7 @@ func (hc *HubConnector) processProgs(inputs []rpctype.HubInput) (minimized, smas
 	for _, inp := range inputs {
 		p, disabled, bad := parseProgram(hc.target, hc.enabledCalls, inp.Prog)
 		if bad != nil || disabled {
-			log.Logf(0, "rejecting program from hub (bad=%v, disabled=%v):\n%s",
+			mgrLog.Logf(0, "rejecting program from hub (bad=%v, disabled=%v):\n%s",
 				bad, disabled, inp)
 			dropped++
 			continue
@@ -292,7 +304,7 @@ func (hc *HubConnector) processRepros(repros [][]byte) int {
 	for _, repro := range repros {
 		_, disabled, bad := parseProgram(hc.target, hc.enabledCalls, repro)
 		if bad != nil || disabled {
-			log.Logf(0, "rejecting repro from hub (bad=%v, disabled=%v):\n%s",
+			mgrLog.Logf(0, "rejecting repro from hub (bad=%v, disabled=%v):\n%s",
 				bad, disabled, repro)
 			dropped++
 			continue
@@ -315,3 +327,10 @@ func (hc *HubConnector) processRepros(repros [][]byte) int {
 	}
 	return dropped
 }
+
+// unsentRepros returns repros that were not sent to the hub yet.
+func (hc *HubConnector) unsentRepros() [][]byte {
+	hc.newReprosMu.Lock()
+	defer hc.newReprosMu.Unlock()
+	return append([][]byte{}, hc.newRepros...)
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/prog"
+)
+
+// In the hunt mode the whole manager focuses on reproducing a single crash:
+// the fuzzer generates only syscalls seen in the crash logs (and calls that create their resources),
+// prefers mutation of corpus programs that contain these syscalls, the crash is reproduced
+// every time it happens (regardless of the number of previous attempts),
+// and all VMs except for one are used for its reproduction.
+// The hunt ends once the crash is reproduced.
+type huntState struct {
+	title string
+	// Syscalls seen in the crash logs.
+	calls map[*prog.Syscall]bool
+}
+
+func (mgr *Manager) huntTitle() string {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if mgr.hunt == nil {
+		return ""
+	}
+	return mgr.hunt.title
+}
+
+func (mgr *Manager) startHunt(title string) {
+	logs := readCrashLogs(mgr.crashdir, title)
+	hunt := &huntState{
+		title: title,
+		calls: make(map[*prog.Syscall]bool),
+	}
+	for _, data := range logs {
+		addLogCalls(mgr.target, hunt.calls, data)
+	}
+	mgr.mu.Lock()
+	mgr.hunt = hunt
+	mgr.mu.Unlock()
+	mgrLog.Logf(0, "hunting for '%v': %v crash logs, %v syscalls", title, len(logs), len(hunt.calls))
+	mgr.applyHunt()
+}
+
+func (mgr *Manager) stopHunt(reason string) {
+	mgr.mu.Lock()
+	hunt := mgr.hunt
+	mgr.hunt = nil
+	mgr.mu.Unlock()
+	if hunt == nil {
+		return
+	}
+	mgrLog.Logf(0, "stopped hunting for '%v': %v", hunt.title, reason)
+	mgr.applyHunt()
+}
+
+// huntNoteCrash updates the hunt state with syscalls from the new crash log.
+func (mgr *Manager) huntNoteCrash(crash *Crash) {
+	mgr.mu.Lock()
+	hunt := mgr.hunt
+	changed := false
+	if hunt != nil && hunt.title == crash.Title {
+		before := len(hunt.calls)
+		addLogCalls(mgr.target, hunt.calls, crash.Output)
+		changed = len(hunt.calls) != before
+	}
+	mgr.mu.Unlock()
+	if changed {
+		mgr.applyHunt()
+	}
+}
+
+// applyHunt focuses the fuzzer on the hunted syscalls (if any).
+func (mgr *Manager) applyHunt() {
+	fuzzerObj := mgr.fuzzer.Load()
+	if fuzzerObj == nil {
+		// Will be applied once the fuzzer is created.
+		return
+	}
+	var calls map[*prog.Syscall]bool
+	mgr.mu.Lock()
+	if mgr.hunt != nil {
+		calls = make(map[*prog.Syscall]bool)
+		for c := range mgr.hunt.calls {
+			calls[c] = true
+		}
+	}
+	mgr.mu.Unlock()
+	if calls == nil {
+		fuzzerObj.SetFocus(nil)
+		return
+	}
+	focused := fuzzerObj.SetFocus(calls)
+	if focused == 0 {
+		mgrLog.Logf(0, "hunt: no enabled syscalls from the crash logs, fuzzing all syscalls")
+		return
+	}
+	mgrLog.Logf(0, "hunt: fuzzing %v syscalls", focused)
+}
+
+func (mgr *Manager) httpHunt(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		if title := r.FormValue("title"); title != "" {
+			mgr.startHunt(title)
+		} else {
+			mgr.stopHunt("requested via http")
+		}
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if mgr.hunt == nil {
+		fmt.Fprintf(w, "not hunting\n")
+		return
+	}
+	var calls []string
+	for c := range mgr.hunt.calls {
+		calls = append(calls, c.Name)
+	}
+	sort.Strings(calls)
+	fmt.Fprintf(w, "hunting for: %v\nsyscalls: %v\n", mgr.hunt.title, strings.Join(calls, " "))
+}
+
+func readCrashLogs(crashdir, title string) [][]byte {
+	dir := filepath.Join(crashdir, hash.String([]byte(title)))
+	files, err := filepath.Glob(filepath.Join(dir, "log*"))
+	if err != nil {
+		return nil
+	}
+	var logs [][]byte
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			logs = append(logs, data)
+		}
+	}
+	return logs
+}
+
+func addLogCalls(target *prog.Target, calls map[*prog.Syscall]bool, data []byte) {
+	for _, entry := range target.ParseLog(data) {
+		for _, c := range entry.P.Calls {
+			calls[c.Meta] = true
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHuntCalls(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const title = "KASAN: use-after-free Read in foo"
+	crashdir := t.TempDir()
+	dir := filepath.Join(crashdir, hash.String([]byte(title)))
+	osutil.MkdirAll(dir)
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "log0"), []byte(`
+08:42:13 executing program 1:
+r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x0, 0x0)
+read(r0, &(0x7f0000000040)=""/10, 0xa)
+`)))
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "log1"), []byte(`
+08:43:13 executing program 0:
+close(0xffffffffffffffff)
+`)))
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "report0"), []byte(`
+mmap(&(0x7f0000000000/0x1000)=nil, 0x1000, 0x0, 0x0, 0xffffffffffffffff, 0x0)
+`)))
+
+	logs := readCrashLogs(crashdir, title)
+	assert.Len(t, logs, 2)
+	calls := make(map[*prog.Syscall]bool)
+	for _, data := range logs {
+		addLogCalls(target, calls, data)
+	}
+	assert.Equal(t, map[*prog.Syscall]bool{
+		target.SyscallMap["openat"]: true,
+		target.SyscallMap["read"]:   true,
+		target.SyscallMap["close"]:  true,
+	}, calls)
+	assert.Empty(t, readCrashLogs(crashdir, "another title"))
+}
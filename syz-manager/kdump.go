@@ -0,0 +1,135 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	crash_pkg "github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/vm"
+)
+
+// Kernel crash dumps (see mgrconfig.KdumpConfig) are first copied out of the crashed VM
+// into workdir/dumps, and then moved into the crash dir when the crash is saved.
+const (
+	crashDumpTmpDir   = "dumps"
+	crashDumpFile     = "vmcore"
+	crashScriptPrefix = "crash-"
+)
+
+// Crash types that panic the kernel (with the usual panic_on_warn=1 config) and thus
+// trigger the capture kernel. For other crashes (hangs, lost connections, leaks)
+// the capture kernel won't boot and we would only waste time waiting for it.
+var dumpedCrashTypes = map[crash_pkg.Type]bool{
+	crash_pkg.Bug:         true,
+	crash_pkg.Warning:     true,
+	crash_pkg.KASAN:       true,
+	crash_pkg.KMSAN:       true,
+	crash_pkg.UBSAN:       true,
+	crash_pkg.LockdepBug:  true,
+	crash_pkg.AtomicSleep: true,
+	crash_pkg.MTE:         true,
+	crash_pkg.PAC:         true,
+}
+
+func (mgr *Manager) needCrashDump(rep *report.Report) bool {
+	if mgr.cfg.Experimental.Kdump == nil || mgr.mode == ModeSmokeTest ||
+		rep.Corrupted || rep.Suppressed || !dumpedCrashTypes[rep.Type] {
+		return false
+	}
+	return !osutil.IsExist(filepath.Join(mgr.crashDir(rep.Title), crashDumpFile))
+}
+
+func (mgr *Manager) crashDir(title string) string {
+	return filepath.Join(mgr.crashdir, hash.String([]byte(title)))
+}
+
+// collectCrashDump copies the kernel crash dump out of the crashed VM.
+// Returns the name of the temporary dump file, or an empty string if the dump is not needed or failed.
+func (mgr *Manager) collectCrashDump(inst *vm.Instance, instanceName string, rep *report.Report) string {
+	if !mgr.needCrashDump(rep) {
+		return ""
+	}
+	dir := filepath.Join(mgr.cfg.Workdir, crashDumpTmpDir)
+	osutil.MkdirAll(dir)
+	file := filepath.Join(dir, instanceName)
+	start := time.Now()
+	if err := inst.CollectDump(file); err != nil {
+		mgrLog.Logf(0, "%s: failed to collect kernel crash dump: %v", instanceName, err)
+		return ""
+	}
+	mgrLog.Logf(0, "%s: collected kernel crash dump in %v", instanceName, time.Since(start))
+	return file
+}
+
+// storeCrashDump moves the collected dump into the crash dir and processes it in the background.
+func (mgr *Manager) storeCrashDump(crash *Crash) {
+	dir := mgr.crashDir(crash.Title)
+	dump := filepath.Join(dir, crashDumpFile)
+	if osutil.IsExist(dump) {
+		// Another VM has dumped the same crash in the meantime.
+		os.Remove(crash.dump)
+		return
+	}
+	osutil.MkdirAll(dir)
+	if err := osutil.WriteFile(filepath.Join(dir, "description"), []byte(crash.Title+"\n")); err != nil {
+		mgrLog.Logf(0, "failed to write crash: %v", err)
+	}
+	if err := osutil.Rename(crash.dump, dump); err != nil {
+		mgrLog.Errorf("failed to store kernel crash dump: %v", err)
+		os.Remove(crash.dump)
+		return
+	}
+	vmlinux := ""
+	if mgr.cfg.KernelObj != "" {
+		vmlinux = filepath.Join(mgr.cfg.KernelObj, mgr.sysTarget.KernelObject)
+	}
+	go processCrashDump(mgr.cfg.Experimental.Kdump, vmlinux, dump)
+}
+
+// processCrashDump filters the dump with makedumpfile and runs the crash utility scripts on it.
+func processCrashDump(cfg *mgrconfig.KdumpConfig, vmlinux, dump string) {
+	if cfg.DumpLevel != 0 {
+		// The dump stays in place while it's being filtered, so that the same crash is not dumped again.
+		tmp := dump + ".tmp"
+		_, err := osutil.RunCmd(time.Hour, "", "makedumpfile", "-c",
+			"-d", fmt.Sprint(cfg.DumpLevel), dump, tmp)
+		if err == nil {
+			err = osutil.Rename(tmp, dump)
+		}
+		if err != nil {
+			mgrLog.Errorf("failed to filter kernel crash dump %v: %v", dump, err)
+			os.Remove(tmp)
+		}
+	}
+	for _, script := range cfg.CrashScripts {
+		output, err := runCrashScript(script, vmlinux, dump)
+		if err != nil {
+			output = append(output, fmt.Sprintf("\nfailed to run crash script: %v\n", err)...)
+		}
+		file := filepath.Join(filepath.Dir(dump), crashScriptPrefix+filepath.Base(script))
+		if err := osutil.WriteFile(file, output); err != nil {
+			mgrLog.Errorf("failed to write crash script output: %v", err)
+		}
+	}
+}
+
+func runCrashScript(script, vmlinux, dump string) ([]byte, error) {
+	f, err := os.Open(script)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// crash executes commands from stdin and exits on EOF.
+	cmd := osutil.Command("crash", "-s", vmlinux, dump)
+	cmd.Stdin = f
+	return osutil.Run(10*time.Minute, cmd)
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	crash_pkg "github.com/google/syzkaller/pkg/report/crash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreCrashDump(t *testing.T) {
+	workdir := t.TempDir()
+	mgr := &Manager{
+		cfg: &mgrconfig.Config{
+			Workdir:      workdir,
+			Experimental: mgrconfig.Experimental{Kdump: &mgrconfig.KdumpConfig{}},
+		},
+		crashdir: filepath.Join(workdir, "crashes"),
+	}
+	rep := &report.Report{Title: "WARNING in foo", Type: crash_pkg.Warning}
+	assert.True(t, mgr.needCrashDump(rep))
+	assert.False(t, mgr.needCrashDump(&report.Report{Title: "lost connection to test machine"}))
+	assert.False(t, mgr.needCrashDump(&report.Report{Title: "WARNING in bar", Type: crash_pkg.Warning,
+		Corrupted: true}))
+
+	newDump := func(data string) string {
+		file := filepath.Join(workdir, "dump-"+data)
+		assert.NoError(t, osutil.WriteFile(file, []byte(data)))
+		return file
+	}
+	first := newDump("first")
+	mgr.storeCrashDump(&Crash{dump: first, Report: rep})
+	assert.False(t, mgr.needCrashDump(rep))
+	assert.False(t, osutil.IsExist(first))
+
+	// Only the first dump is kept.
+	second := newDump("second")
+	mgr.storeCrashDump(&Crash{dump: second, Report: rep})
+	assert.False(t, osutil.IsExist(second))
+	data, err := os.ReadFile(filepath.Join(mgr.crashDir(rep.Title), crashDumpFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+	description, err := os.ReadFile(filepath.Join(mgr.crashDir(rep.Title), "description"))
+	assert.NoError(t, err)
+	assert.Equal(t, rep.Title+"\n", string(description))
+}
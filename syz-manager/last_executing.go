@@ -5,12 +5,16 @@ package main
 
 import (
 	"sort"
+	"sync"
 	"time"
+
+	"github.com/google/syzkaller/prog"
 )
 
 // LastExecuting keeps the given number of last executed programs
 // for each proc in a VM, and allows to query this set after a crash.
 type LastExecuting struct {
+	mu        sync.Mutex
 	count     int
 	procs     []ExecRecord
 	positions []int
@@ -20,6 +24,11 @@ type ExecRecord struct {
 	Proc int
 	Prog []byte
 	Time time.Duration
+	// Start is the time when the program started executing (osutil.MonotonicNano),
+	// it's used to annotate the console output (see report.AnnotateOutput).
+	Start time.Duration
+	// The executed program, used to attribute crashes to mutation ops (see MutationOps).
+	p *prog.Prog
 }
 
 func MakeLastExecuting(procs, count int) *LastExecuting {
@@ -31,12 +40,16 @@ func MakeLastExecuting(procs, count int) *LastExecuting {
 }
 
 // Note execution of the 'prog' on 'proc' at time 'now'.
-func (last *LastExecuting) Note(proc int, prog []byte, now time.Duration) {
+func (last *LastExecuting) Note(proc int, data []byte, p *prog.Prog, now time.Duration) {
+	last.mu.Lock()
+	defer last.mu.Unlock()
 	pos := &last.positions[proc]
 	last.procs[proc*last.count+*pos] = ExecRecord{
-		Proc: proc,
-		Prog: prog,
-		Time: now,
+		Proc:  proc,
+		Prog:  data,
+		Time:  now,
+		Start: now,
+		p:     p,
 	}
 	*pos++
 	if *pos == last.count {
@@ -49,6 +62,8 @@ func (last *LastExecuting) Note(proc int, prog []byte, now time.Duration) {
 // ExecRecord.Time is the difference in start executing time between this
 // program and the program that started executing last.
 func (last *LastExecuting) Collect() []ExecRecord {
+	last.mu.Lock()
+	defer last.mu.Unlock()
 	procs := last.procs
 	last.procs = nil // The type must not be used after this.
 	sort.Slice(procs, func(i, j int) bool {
@@ -64,3 +79,43 @@ func (last *LastExecuting) Collect() []ExecRecord {
 	}
 	return procs
 }
+
+// Latest returns the program that started executing last on each proc (if it's known).
+// Unlike Collect, it can be called while programs are still executing.
+func (last *LastExecuting) Latest() []*prog.Prog {
+	last.mu.Lock()
+	defer last.mu.Unlock()
+	if last.procs == nil {
+		return nil
+	}
+	var res []*prog.Prog
+	for proc, pos := range last.positions {
+		rec := last.procs[proc*last.count+(pos+last.count-1)%last.count]
+		if rec.p != nil {
+			res = append(res, rec.p)
+		}
+	}
+	return res
+}
+
+// MutationOps returns the set of mutation ops that produced the last executed program on each proc.
+// Records must be returned by Collect. The ops are computed only here (i.e. after a crash),
+// so that the program execution path does not pay for it.
+func MutationOps(records []ExecRecord) map[prog.MutationOp]bool {
+	latest := make(map[int]ExecRecord)
+	for _, rec := range records {
+		if prev, ok := latest[rec.Proc]; !ok || rec.Time < prev.Time {
+			latest[rec.Proc] = rec
+		}
+	}
+	res := make(map[prog.MutationOp]bool)
+	for _, rec := range latest {
+		if rec.p == nil {
+			continue
+		}
+		for op := range rec.p.MutationOps() {
+			res[op] = true
+		}
+	}
+	return res
+}
@@ -6,6 +6,9 @@ package main
 import (
 	"testing"
 
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,41 +19,93 @@ func TestLastExecutingEmpty(t *testing.T) {
 
 func TestLastExecuting(t *testing.T) {
 	last := MakeLastExecuting(10, 3)
-	last.Note(0, []byte("prog1"), 1)
+	last.Note(0, []byte("prog1"), nil, 1)
 
-	last.Note(1, []byte("prog2"), 2)
-	last.Note(1, []byte("prog3"), 3)
+	last.Note(1, []byte("prog2"), nil, 2)
+	last.Note(1, []byte("prog3"), nil, 3)
 
-	last.Note(3, []byte("prog4"), 4)
-	last.Note(3, []byte("prog5"), 5)
-	last.Note(3, []byte("prog6"), 6)
+	last.Note(3, []byte("prog4"), nil, 4)
+	last.Note(3, []byte("prog5"), nil, 5)
+	last.Note(3, []byte("prog6"), nil, 6)
 
-	last.Note(7, []byte("prog7"), 7)
-	last.Note(7, []byte("prog8"), 8)
-	last.Note(7, []byte("prog9"), 9)
-	last.Note(7, []byte("prog10"), 10)
-	last.Note(7, []byte("prog11"), 11)
+	last.Note(7, []byte("prog7"), nil, 7)
+	last.Note(7, []byte("prog8"), nil, 8)
+	last.Note(7, []byte("prog9"), nil, 9)
+	last.Note(7, []byte("prog10"), nil, 10)
+	last.Note(7, []byte("prog11"), nil, 11)
 
-	last.Note(9, []byte("prog12"), 12)
+	last.Note(9, []byte("prog12"), nil, 12)
 
-	last.Note(8, []byte("prog13"), 13)
+	last.Note(8, []byte("prog13"), nil, 13)
 
 	assert.Equal(t, last.Collect(), []ExecRecord{
-		{Proc: 0, Prog: []byte("prog1"), Time: 12},
+		{Proc: 0, Prog: []byte("prog1"), Time: 12, Start: 1},
 
-		{Proc: 1, Prog: []byte("prog2"), Time: 11},
-		{Proc: 1, Prog: []byte("prog3"), Time: 10},
+		{Proc: 1, Prog: []byte("prog2"), Time: 11, Start: 2},
+		{Proc: 1, Prog: []byte("prog3"), Time: 10, Start: 3},
 
-		{Proc: 3, Prog: []byte("prog4"), Time: 9},
-		{Proc: 3, Prog: []byte("prog5"), Time: 8},
-		{Proc: 3, Prog: []byte("prog6"), Time: 7},
+		{Proc: 3, Prog: []byte("prog4"), Time: 9, Start: 4},
+		{Proc: 3, Prog: []byte("prog5"), Time: 8, Start: 5},
+		{Proc: 3, Prog: []byte("prog6"), Time: 7, Start: 6},
 
-		{Proc: 7, Prog: []byte("prog9"), Time: 4},
-		{Proc: 7, Prog: []byte("prog10"), Time: 3},
-		{Proc: 7, Prog: []byte("prog11"), Time: 2},
+		{Proc: 7, Prog: []byte("prog9"), Time: 4, Start: 9},
+		{Proc: 7, Prog: []byte("prog10"), Time: 3, Start: 10},
+		{Proc: 7, Prog: []byte("prog11"), Time: 2, Start: 11},
 
-		{Proc: 9, Prog: []byte("prog12"), Time: 1},
+		{Proc: 9, Prog: []byte("prog12"), Time: 1, Start: 12},
 
-		{Proc: 8, Prog: []byte("prog13"), Time: 0},
+		{Proc: 8, Prog: []byte("prog13"), Time: 0, Start: 13},
 	})
 }
+
+func TestLastExecutingLatest(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := testutil.RandSource(t)
+	ct := target.DefaultChoiceTable()
+	progs := make([]*prog.Prog, 4)
+	for i := range progs {
+		progs[i] = target.Generate(rs, 5, ct)
+	}
+	last := MakeLastExecuting(3, 2)
+	assert.Empty(t, last.Latest())
+	last.Note(0, []byte("prog1"), progs[0], 1)
+	last.Note(0, []byte("prog2"), progs[1], 2)
+	last.Note(0, []byte("prog3"), progs[2], 3)
+	last.Note(2, []byte("prog4"), progs[3], 4)
+	assert.Equal(t, []*prog.Prog{progs[2], progs[3]}, last.Latest())
+	last.Collect()
+	assert.Empty(t, last.Latest())
+}
+
+func TestLastExecutingMutationOps(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := testutil.RandSource(t)
+	ct := target.DefaultChoiceTable()
+	last := MakeLastExecuting(2, 2)
+	mutated := func() *prog.Prog {
+		for {
+			p := target.Generate(rs, 5, ct)
+			p.Mutate(rs, 10, ct, nil, nil)
+			// Mutation may occasionally fail to apply any operation.
+			if len(p.MutationOps()) != 0 {
+				return p
+			}
+		}
+	}
+	last.Note(0, []byte("prog1"), mutated(), 1)
+	last.Note(0, []byte("prog2"), target.Generate(rs, 5, ct), 2)
+	p := mutated()
+	last.Note(1, []byte("prog3"), p, 3)
+	want := make(map[prog.MutationOp]bool)
+	for op := range p.MutationOps() {
+		want[op] = true
+	}
+	assert.NotEmpty(t, want)
+	assert.Equal(t, want, MutationOps(last.Collect()))
+}
@@ -16,6 +16,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,9 +48,13 @@ import (
 )
 
 var (
-	flagConfig = flag.String("config", "", "configuration file")
-	flagDebug  = flag.Bool("debug", false, "dump all VM output to console")
-	flagBench  = flag.String("bench", "", "write execution statistics into this file periodically")
+	flagConfig   = flag.String("config", "", "configuration file")
+	flagDebug    = flag.Bool("debug", false, "dump all VM output to console")
+	flagBench    = flag.String("bench", "", "write execution statistics into this file periodically")
+	flagSnapshot = flag.String("snapshot", "", "resume fuzzing from the state snapshot archive"+
+		" (downloaded from /snapshot page of another manager)")
+	flagCheckConfig = flag.Bool("checkconfig", false, "validate the config (including the VM config,"+
+		" image, kernel and ssh key paths) and exit without starting VMs")
 
 	flagMode = flag.String("mode", "fuzzing", "mode of operation, one of:\n"+
 		" - fuzzing: the default continuous fuzzing mode\n"+
@@ -55,13 +62,27 @@ var (
 		"	The test consists of booting VMs and running some simple test programs\n"+
 		"	to ensure that fuzzing can proceed in general. After completing the test\n"+
 		"	the process exits and the exit status indicates success/failure.\n"+
-		"	If the kernel oopses during testing, the report is saved to workdir/report.json.\n")
+		"	If the kernel oopses during testing, the report is saved to workdir/report.json.\n"+
+		" - soak: run the existing corpus over and over again without generation/mutation\n"+
+		"	and compare coverage and call success rates with a baseline saved in workdir/soak.json\n"+
+		"	(the first pass becomes the baseline if there is none). Deviations are logged and\n"+
+		"	the results of the last pass are saved to workdir/soak-report.json.\n"+
+		"	The process exits with an error if total coverage drops significantly, any program\n"+
+		"	fails to execute, or the kernel crashes (the report is saved to workdir/report.json).\n"+
+		" - validate: execute calls that declare expected errnos (errnos attribute) and report\n"+
+		"	calls that consistently fail with unexpected errnos (likely broken descriptions).\n"+
+		"	The results are saved to workdir/validate-report.json.\n"+
+		"	The process exits with an error if any such calls are found.\n")
 )
 
+// mgrLog is the logger of the manager, its verbosity can be set separately (e.g. with -vmodule=manager=1).
+var mgrLog = log.Module("manager")
+
 type Manager struct {
 	cfg             *mgrconfig.Config
 	mode            Mode
 	vmPool          *vm.Pool
+	vmSched         *vmScheduler
 	target          *prog.Target
 	sysTarget       *targets.Target
 	reporter        *report.Reporter
@@ -73,6 +94,7 @@ type Manager struct {
 	corpusPreloaded chan bool
 	firstConnect    atomic.Int64 // unix time, or 0 if not connected
 	crashTypes      map[string]bool
+	crashLimiter    *crashRateLimiter
 	vmStop          chan bool
 	enabledFeatures flatrpc.Feature
 	checkDone       bool
@@ -81,11 +103,18 @@ type Manager struct {
 	nextInstanceID  atomic.Uint64
 
 	dash *dashapi.Dashboard
+	// Requests to upload dashboard stats right away (used on preemption).
+	dashStatsFlush chan chan struct{}
+	hubConn        atomic.Pointer[HubConnector]
 
 	mu                    sync.Mutex
 	fuzzer                atomic.Pointer[fuzzer.Fuzzer]
 	phase                 int
 	targetEnabledSyscalls map[*prog.Syscall]bool
+	// Max signal restored from a snapshot, applied after the corpus is triaged.
+	snapshotSignal []uint64
+	// Untriaged inputs saved on preemption by the previous run (see preempt.go).
+	preemptTriage [][]byte
 
 	disabledHashes   map[string]struct{}
 	seeds            [][]byte
@@ -94,16 +123,24 @@ type Manager struct {
 	memoryLeakFrames map[string]bool
 	dataRaceFrames   map[string]bool
 	saturatedCalls   map[string]bool
+	hunt             *huntState
+	reproQueue       *reproQueue
+	fleet            *fleetState
+	// Console output patterns of non-fatal kernel warnings (experimental.warning_patterns).
+	warningRes []*regexp.Regexp
+	// Boot IDs of machines that reported a reset by the hardware watchdog.
+	watchdogBoots map[string]bool
 
 	needMoreRepros     chan chan bool
 	externalReproQueue chan *Crash
-	reproRequest       chan chan map[string]bool
 
 	// For checking that files that we are using are not changing under us.
 	// Maps file name to modification time.
 	usedFiles map[string]time.Time
 
-	assetStorage *asset.Storage
+	assetStorage    *asset.Storage
+	buildAssetsOnce sync.Once
+	buildAssets     []dashapi.NewAsset
 
 	bootTime stats.AverageValue[time.Duration]
 
@@ -116,6 +153,8 @@ type Mode int
 const (
 	ModeFuzzing Mode = iota
 	ModeSmokeTest
+	ModeSoak
+	ModeValidate
 )
 
 const (
@@ -137,8 +176,13 @@ const currentDBVersion = 4
 
 type Crash struct {
 	instanceName  string
-	fromHub       bool // this crash was created based on a repro from syz-hub
-	fromDashboard bool // .. or from dashboard
+	fromHub       bool   // this crash was created based on a repro from syz-hub
+	fromDashboard bool   // .. or from dashboard
+	variant       string // VM configuration variant the crash happened on (if any)
+	// Programs executing at the time of the crash (used to attribute it to mutation ops).
+	lastExec []ExecRecord
+	dump     string // temporary file with the kernel crash dump (if collected)
+	cores    string // temporary dir with the executor core dump (if collected)
 	*report.Report
 }
 
@@ -152,13 +196,52 @@ func main() {
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
+	if *flagCheckConfig {
+		if err := checkConfig(cfg); err != nil {
+			log.Fatalf("%v", err)
+		}
+		mgrLog.Logf(0, "config is valid")
+		return
+	}
 	if cfg.DashboardAddr != "" {
 		// This lets better distinguish logs of individual syz-manager instances.
 		log.SetName(cfg.Name)
 	}
+	log.SetModuleLevels(cfg.LogLevels)
+	if cfg.LogJSON {
+		log.EnableJSON()
+	}
 	RunManager(cfg)
 }
 
+// checkConfig does the checks that are otherwise done only when the manager starts.
+// Note: VM pool creation validates the VM config, but does not boot any VMs.
+func checkConfig(cfg *mgrconfig.Config) error {
+	if _, err := report.NewReporter(cfg); err != nil {
+		return err
+	}
+	if cfg.VMLess {
+		return nil
+	}
+	pool, err := vm.Create(cfg, false)
+	if err != nil {
+		return fmt.Errorf("bad vm config: %w", err)
+	}
+	defer pool.Close()
+	if cfg.FuzzingVMs >= pool.Count() && cfg.Reproduce {
+		return fmt.Errorf("fuzzing_vms (%v) leaves no VMs for reproduction (total %v VMs), set reproduce=false",
+			cfg.FuzzingVMs, pool.Count())
+	}
+	sched, err := newVMScheduler(cfg.Experimental.VMScheduling, pool.Count())
+	if err != nil {
+		return err
+	}
+	if cfg.Reproduce && sched.reproVMs(pool.Count()) == 0 {
+		return fmt.Errorf("experimental.vm_scheduling leaves no VMs for reproduction, set reproduce=false")
+	}
+	return nil
+}
+
 func RunManager(cfg *mgrconfig.Config) {
 	var mode Mode
 	switch *flagMode {
@@ -168,22 +251,50 @@ func RunManager(cfg *mgrconfig.Config) {
 		mode = ModeSmokeTest
 		cfg.DashboardClient = ""
 		cfg.HubClient = ""
+	case "soak":
+		mode = ModeSoak
+		cfg.DashboardClient = ""
+		cfg.HubClient = ""
+	case "validate":
+		mode = ModeValidate
+		cfg.DashboardClient = ""
+		cfg.HubClient = ""
 	default:
 		flag.PrintDefaults()
 		log.Fatalf("unknown mode: %v", *flagMode)
 	}
 
 	var vmPool *vm.Pool
+	var vmSched *vmScheduler
 	if !cfg.VMLess {
 		var err error
 		vmPool, err = vm.Create(cfg, *flagDebug)
 		if err != nil {
 			log.Fatalf("%v", err)
 		}
+		vmSched, err = newVMScheduler(cfg.Experimental.VMScheduling, vmPool.Count())
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
 	}
 
 	crashdir := filepath.Join(cfg.Workdir, "crashes")
 	osutil.MkdirAll(crashdir)
+	// Remove kernel crash dumps that were collected, but not saved before the previous exit.
+	os.RemoveAll(filepath.Join(cfg.Workdir, crashDumpTmpDir))
+	os.RemoveAll(filepath.Join(cfg.Workdir, coreDumpTmpDir))
+
+	var snapshotSignal []uint64
+	preempted := new(preemptState)
+	if *flagSnapshot != "" {
+		var err error
+		snapshotSignal, err = restoreSnapshot(*flagSnapshot, cfg.Workdir, cfg.Target.OS+"/"+cfg.Target.Arch)
+		if err != nil {
+			log.Fatalf("failed to restore snapshot: %v", err)
+		}
+	} else if cfg.Experimental.PreemptibleHost {
+		snapshotSignal, preempted = restorePreemptCheckpoint(cfg.Workdir, cfg.Target.OS+"/"+cfg.Target.Arch)
+	}
 
 	reporter, err := report.NewReporter(cfg)
 	if err != nil {
@@ -195,6 +306,7 @@ func RunManager(cfg *mgrconfig.Config) {
 		cfg:                cfg,
 		mode:               mode,
 		vmPool:             vmPool,
+		vmSched:            vmSched,
 		corpus:             corpus.NewMonitoredCorpus(context.Background(), corpusUpdates),
 		corpusPreloaded:    make(chan bool),
 		target:             cfg.Target,
@@ -202,6 +314,7 @@ func RunManager(cfg *mgrconfig.Config) {
 		reporter:           reporter,
 		crashdir:           crashdir,
 		crashTypes:         make(map[string]bool),
+		crashLimiter:       newCrashRateLimiter(cfg.Experimental.CrashRateLimit),
 		disabledHashes:     make(map[string]struct{}),
 		memoryLeakFrames:   make(map[string]bool),
 		dataRaceFrames:     make(map[string]bool),
@@ -209,13 +322,36 @@ func RunManager(cfg *mgrconfig.Config) {
 		vmStop:             make(chan bool),
 		externalReproQueue: make(chan *Crash, 10),
 		needMoreRepros:     make(chan chan bool),
-		reproRequest:       make(chan chan map[string]bool),
 		usedFiles:          make(map[string]time.Time),
 		saturatedCalls:     make(map[string]bool),
+		snapshotSignal:     snapshotSignal,
+		preemptTriage:      preempted.Triage,
+		newRepros:          preempted.HubRepros,
+		dashStatsFlush:     make(chan chan struct{}),
+		reproQueue:         loadReproQueue(filepath.Join(cfg.Workdir, reproQueueDir)),
 	}
 
+	for _, pattern := range cfg.Experimental.WarningPatterns {
+		mgr.warningRes = append(mgr.warningRes, regexp.MustCompile(pattern))
+	}
+	if cfg.Experimental.EnergySchedule {
+		mgr.corpus.EnableEnergySchedule(corpus.EnergyConfig{
+			Temperature: cfg.Experimental.EnergyTemperature,
+		})
+	}
 	mgr.initStats()
+	if cfg.Experimental.HuntTitle != "" {
+		mgr.startHunt(cfg.Experimental.HuntTitle)
+	}
+	if err := writeDescWarnings(cfg.Workdir, mgr.target); err != nil {
+		mgrLog.Errorf("failed to write description warnings: %v", err)
+	}
 	go mgr.preloadCorpus()
+	if len(cfg.Experimental.Fleet) != 0 {
+		// Must be set before the HTTP server is created, /fleet handler reads it.
+		mgr.fleet = newFleet(cfg.Experimental.Fleet)
+		go mgr.fleetLoop()
+	}
 	mgr.initHTTP() // Creates HTTP server.
 	mgr.collectUsedFiles()
 	go mgr.corpusInputHandler(corpusUpdates)
@@ -234,7 +370,11 @@ func RunManager(cfg *mgrconfig.Config) {
 	}
 
 	if !cfg.AssetStorage.IsEmpty() {
-		mgr.assetStorage, err = asset.StorageFromConfig(cfg.AssetStorage, mgr.dash)
+		var dash asset.Dashboard
+		if mgr.dash != nil {
+			dash = mgr.dash
+		}
+		mgr.assetStorage, err = asset.StorageFromConfig(cfg.AssetStorage, dash)
 		if err != nil {
 			log.Fatalf("failed to init asset storage: %v", err)
 		}
@@ -248,10 +388,13 @@ func RunManager(cfg *mgrconfig.Config) {
 	if mgr.mode != ModeSmokeTest {
 		osutil.HandleInterrupts(vm.Shutdown)
 	}
+	if mgr.cfg.Experimental.PreemptibleHost {
+		go mgr.preemptionLoop()
+	}
 	if mgr.vmPool == nil {
-		log.Logf(0, "no VMs started (type=none)")
-		log.Logf(0, "you are supposed to start syz-fuzzer manually as:")
-		log.Logf(0, "syz-fuzzer -manager=manager.ip:%v [other flags as necessary]", mgr.serv.port)
+		mgrLog.Logf(0, "no VMs started (type=none)")
+		mgrLog.Logf(0, "you are supposed to start syz-fuzzer manually as:")
+		mgrLog.Logf(0, "syz-fuzzer -manager=manager.ip:%v [other flags as necessary]", mgr.serv.port)
 		<-vm.Shutdown
 		return
 	}
@@ -271,7 +414,7 @@ func (mgr *Manager) heartbeatLoop() {
 		for _, stat := range stats.Collect(stats.Console) {
 			fmt.Fprintf(buf, "%v=%v ", stat.Name, stat.Value)
 		}
-		log.Logf(0, "%s", buf.String())
+		mgrLog.Logf(0, "%s", buf.String())
 	}
 }
 
@@ -318,19 +461,22 @@ type ReproResult struct {
 // Manager needs to be refactored (#605).
 // nolint: gocyclo, gocognit, funlen
 func (mgr *Manager) vmLoop() {
-	log.Logf(0, "booting test machines...")
-	log.Logf(0, "wait for the connection from test machine...")
+	mgrLog.Logf(0, "booting test machines...")
+	mgrLog.Logf(0, "wait for the connection from test machine...")
 	instancesPerRepro := 3
 	vmCount := mgr.vmPool.Count()
-	maxReproVMs := vmCount - mgr.cfg.FuzzingVMs
+	// With experimental.vm_scheduling some VMs may not be usable for reproduction.
+	reproVMsTotal := mgr.vmSched.reproVMs(vmCount)
+	maxReproVMs := min(vmCount-mgr.cfg.FuzzingVMs, reproVMsTotal)
 	if instancesPerRepro > maxReproVMs && maxReproVMs > 0 {
 		instancesPerRepro = maxReproVMs
 	}
+	// In the hunt mode, the hunted crash may use all VMs except for one that continues fuzzing.
+	huntReproVMs := min(max(maxReproVMs, vmCount-1), reproVMsTotal)
+	reproVMs := 0
 	instances := SequentialResourcePool(vmCount, 5*time.Second)
 	runDone := make(chan *RunResult, 1)
 	pendingRepro := make(map[*Crash]bool)
-	reproducing := make(map[string]bool)
-	var reproQueue []*Crash
 	reproDone := make(chan *ReproResult, 1)
 	stopPending := false
 	shutdown := vm.Shutdown
@@ -339,53 +485,86 @@ func (mgr *Manager) vmLoop() {
 		phase := mgr.phase
 		mgr.mu.Unlock()
 
+		huntTitle := mgr.huntTitle()
 		for crash := range pendingRepro {
-			if reproducing[crash.Title] {
+			delete(pendingRepro, crash)
+			if mgr.reproQueue.contains(crash.Title) {
+				// The queued crash will be retried if the current attempt fails.
 				continue
 			}
-			delete(pendingRepro, crash)
-			if !mgr.needRepro(crash) {
+			if crash.Title != huntTitle && !mgr.needRepro(crash) {
 				continue
 			}
-			log.Logf(1, "loop: add to repro queue '%v'", crash.Title)
-			reproducing[crash.Title] = true
-			reproQueue = append(reproQueue, crash)
+			mgrLog.Logf(1, "loop: add to repro queue '%v'", crash.Title)
+			mgr.reproQueue.add(crash)
 		}
 
-		log.Logf(1, "loop: phase=%v shutdown=%v instances=%v/%v %+v repro: pending=%v reproducing=%v queued=%v",
+		mgrLog.Logf(1, "loop: phase=%v shutdown=%v instances=%v/%v %+v repro: pending=%v queued=%v",
 			phase, shutdown == nil, instances.Len(), vmCount, instances.Snapshot(),
-			len(pendingRepro), len(reproducing), len(reproQueue))
+			len(pendingRepro), mgr.reproQueue.active())
 
+		// reproVMsFor returns the number of VMs for reproduction of the crash,
+		// or 0 if it can't be started now.
+		reproVMsFor := func(crash *Crash) int {
+			if crash == nil {
+				return 0
+			}
+			if crash.Title == huntTitle {
+				return max(huntReproVMs-reproVMs, 0)
+			}
+			if reproVMs+instancesPerRepro > maxReproVMs {
+				return 0
+			}
+			return instancesPerRepro
+		}
+		// nextRepro returns the next crash to reproduce (the hunted crash goes first)
+		// and the number of VMs for its reproduction, or 0 if it can't be started now.
+		nextRepro := func() (*Crash, int) {
+			if phase < phaseTriagedHub {
+				return nil, 0
+			}
+			crash := mgr.reproQueue.next(huntTitle)
+			return crash, reproVMsFor(crash)
+		}
 		canRepro := func() bool {
-			return phase >= phaseTriagedHub && len(reproQueue) != 0 &&
-				(mgr.statNumReproducing.Val()+1)*instancesPerRepro <= maxReproVMs
+			_, vms := nextRepro()
+			return vms != 0
 		}
 
 		if shutdown != nil {
-			for canRepro() {
-				vmIndexes := instances.Take(instancesPerRepro)
+			for {
+				crash, vms := nextRepro()
+				if vms == 0 {
+					break
+				}
+				vmIndexes := instances.TakeRanked(vms, mgr.vmSched.reproRank)
 				if vmIndexes == nil {
 					break
 				}
-				last := len(reproQueue) - 1
-				crash := reproQueue[last]
-				reproQueue[last] = nil
-				reproQueue = reproQueue[:last]
+				reproVMs += len(vmIndexes)
+				mgr.reproQueue.start(crash.Title)
 				mgr.statNumReproducing.Add(1)
-				log.Logf(0, "loop: starting repro of '%v' on instances %+v", crash.Title, vmIndexes)
+				mgrLog.Logf(0, "loop: starting repro of '%v' on instances %+v", crash.Title, vmIndexes)
 				go func() {
 					reproDone <- mgr.runRepro(crash, vmIndexes, instances.Put)
 				}()
 			}
-			for !canRepro() {
-				idx := instances.TakeOne()
-				if idx == nil {
+			for {
+				rank := mgr.vmSched.fuzzRank
+				if canRepro() {
+					// Keep the free VMs for the pending reproduction,
+					// but don't leave idle the VMs that can't be used for it.
+					rank = mgr.vmSched.fuzzOnlyRank
+				}
+				idxs := instances.TakeRanked(1, rank)
+				if idxs == nil {
 					break
 				}
-				log.Logf(1, "loop: starting instance %v", *idx)
+				idx := idxs[0]
+				mgrLog.Logf(1, "loop: starting instance %v", idx)
 				go func() {
-					crash, err := mgr.runInstance(*idx)
-					runDone <- &RunResult{*idx, crash, err}
+					crash, err := mgr.runInstance(idx)
+					runDone <- &RunResult{idx, crash, err}
 				}()
 			}
 		}
@@ -400,12 +579,12 @@ func (mgr *Manager) vmLoop() {
 		case <-instances.Freed:
 			// An instance has been released.
 		case stopRequest <- true:
-			log.Logf(1, "loop: issued stop request")
+			mgrLog.Logf(1, "loop: issued stop request")
 			stopPending = true
 		case res := <-runDone:
-			log.Logf(1, "loop: instance %v finished, crash=%v", res.idx, res.crash != nil)
+			mgrLog.Logf(1, "loop: instance %v finished, crash=%v", res.idx, res.crash != nil)
 			if res.err != nil && shutdown != nil {
-				log.Logf(0, "%v", res.err)
+				mgrLog.Logf(0, "%v", res.err)
 			}
 			stopPending = false
 			instances.Put(res.idx)
@@ -414,19 +593,20 @@ func (mgr *Manager) vmLoop() {
 			if shutdown != nil && res.crash != nil {
 				needRepro := mgr.saveCrash(res.crash)
 				if needRepro {
-					log.Logf(1, "loop: add pending repro for '%v'", res.crash.Title)
+					mgrLog.Logf(1, "loop: add pending repro for '%v'", res.crash.Title)
 					pendingRepro[res.crash] = true
 				}
 			}
 		case res := <-reproDone:
 			mgr.statNumReproducing.Add(-1)
+			reproVMs -= len(res.instances)
 			crepro := false
 			title := ""
 			if res.repro != nil {
 				crepro = res.repro.CRepro
 				title = res.repro.Report.Title
 			}
-			log.Logf(0, "loop: repro on %+v finished '%v', repro=%v crepro=%v desc='%v'"+
+			mgrLog.Logf(0, "loop: repro on %+v finished '%v', repro=%v crepro=%v desc='%v'"+
 				" hub=%v from_dashboard=%v",
 				res.instances, res.report0.Title, res.repro != nil, crepro, title,
 				res.fromHub, res.fromDashboard,
@@ -434,34 +614,30 @@ func (mgr *Manager) vmLoop() {
 			if res.err != nil {
 				reportReproError(res.err)
 			}
-			delete(reproducing, res.report0.Title)
+			mgr.reproQueue.finish(res.report0.Title, res.repro != nil)
 			if res.repro == nil {
 				if res.fromHub {
-					log.Logf(1, "repro '%v' came from syz-hub, not reporting the failure",
+					mgrLog.Logf(1, "repro '%v' came from syz-hub, not reporting the failure",
 						res.report0.Title)
 				} else {
-					log.Logf(1, "report repro failure of '%v'", res.report0.Title)
+					mgrLog.Logf(1, "report repro failure of '%v'", res.report0.Title)
 					mgr.saveFailedRepro(res.report0, res.stats)
 				}
 			} else {
 				mgr.saveRepro(res)
+				if res.report0.Title == huntTitle {
+					mgr.stopHunt("reproduced")
+				}
 			}
 		case <-shutdown:
-			log.Logf(1, "loop: shutting down...")
+			mgrLog.Logf(1, "loop: shutting down...")
 			shutdown = nil
 		case crash := <-mgr.externalReproQueue:
-			log.Logf(1, "loop: got repro request")
+			mgrLog.Logf(1, "loop: got repro request")
 			pendingRepro[crash] = true
 		case reply := <-mgr.needMoreRepros:
 			reply <- phase >= phaseTriagedHub &&
-				len(reproQueue)+len(pendingRepro)+len(reproducing) == 0
-			goto wait
-		case reply := <-mgr.reproRequest:
-			repros := make(map[string]bool)
-			for title := range reproducing {
-				repros[title] = true
-			}
-			reply <- repros
+				mgr.reproQueue.active()+len(pendingRepro) == 0
 			goto wait
 		}
 	}
@@ -478,7 +654,7 @@ func reportReproError(err error) {
 	switch err {
 	case repro.ErrNoPrograms:
 		// This is not extraordinary as programs are collected via SSH.
-		log.Logf(0, "repro failed: %v", err)
+		mgrLog.Logf(0, "repro failed: %v", err)
 		return
 	case repro.ErrNoVMs:
 		// This error is to be expected if we're shutting down.
@@ -487,7 +663,7 @@ func reportReproError(err error) {
 		}
 	}
 	// Report everything else as errors.
-	log.Errorf("repro failed: %v", err)
+	mgrLog.Errorf("repro failed: %v", err)
 }
 
 func (mgr *Manager) runRepro(crash *Crash, vmIndexes []int, putInstances func(...int)) *ReproResult {
@@ -511,7 +687,7 @@ func (mgr *Manager) runRepro(crash *Crash, vmIndexes []int, putInstances func(..
 		for i := 1; i <= straceAttempts; i++ {
 			strace := repro.RunStrace(res, mgr.cfg, mgr.reporter, mgr.vmPool, vmIndexes[0])
 			sameBug := strace.IsSameBug(res)
-			log.Logf(0, "strace run attempt %d/%d for '%s': same bug %v, error %v",
+			mgrLog.Logf(0, "strace run attempt %d/%d for '%s': same bug %v, error %v",
 				i, straceAttempts, res.Report.Title, sameBug, strace.Error)
 			// We only want to save strace output if it resulted in the same bug.
 			// Otherwise, it will be hard to reproduce on syzbot and will confuse users.
@@ -578,6 +754,40 @@ func (pool *ResourcePool) Take(cnt int) []int {
 	return ret
 }
 
+// TakeRanked is like Take, but takes the resources with the lowest rank first
+// (resources of the same rank are taken in the Take order). Resources with negative rank are not taken.
+func (pool *ResourcePool) TakeRanked(cnt int, rank func(id int) int) []int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	var candidates []int
+	for i := len(pool.ids) - 1; i >= 0; i-- {
+		if rank(pool.ids[i]) >= 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) < cnt {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return rank(pool.ids[candidates[i]]) < rank(pool.ids[candidates[j]])
+	})
+	taken := make(map[int]bool)
+	for _, i := range candidates[:cnt] {
+		taken[i] = true
+	}
+	ret := []int{}
+	var rest []int
+	for i, id := range pool.ids {
+		if taken[i] {
+			ret = append(ret, id)
+		} else {
+			rest = append(rest, id)
+		}
+	}
+	pool.ids = rest
+	return ret
+}
+
 func (pool *ResourcePool) TakeOne() *int {
 	ret := pool.Take(1)
 	if ret == nil {
@@ -592,7 +802,7 @@ func (mgr *Manager) preloadCorpus() {
 		if corpusDB == nil {
 			log.Fatalf("failed to open corpus database: %v", err)
 		}
-		log.Errorf("read %v inputs from corpus and got error: %v", len(corpusDB.Records), err)
+		mgrLog.Errorf("read %v inputs from corpus and got error: %v", len(corpusDB.Records), err)
 	}
 	mgr.corpusDB = corpusDB
 
@@ -646,19 +856,34 @@ func (mgr *Manager) loadCorpus() {
 			broken++
 		}
 		if item != nil {
+			item.Prio = len(item.Prog.Calls)
 			candidates = append(candidates, *item)
 		}
 	}
 	mgr.fresh = len(mgr.corpusDB.Records) == 0
+	// Inputs that were being triaged when the previous run was preempted
+	// were never minimized/smashed, so triage them from scratch.
+	for _, data := range mgr.preemptTriage {
+		_, item := mgr.loadProg(data, false, false)
+		if item != nil {
+			item.Prio = len(item.Prog.Calls)
+			candidates = append(candidates, *item)
+		}
+	}
+	if len(mgr.preemptTriage) != 0 {
+		mgrLog.Logf(0, "%-24v: %v", "preempted triage", len(mgr.preemptTriage))
+	}
+	mgr.preemptTriage = nil
 	seeds := 0
 	for _, seed := range mgr.seeds {
 		_, item := mgr.loadProg(seed, true, false)
 		if item != nil {
+			item.Prio = seedPrio + len(item.Prog.Calls)
 			candidates = append(candidates, *item)
 			seeds++
 		}
 	}
-	log.Logf(0, "%-24v: %v (%v broken, %v seeds)", "corpus", len(candidates), broken, seeds)
+	mgrLog.Logf(0, "%-24v: %v (%v broken, %v seeds)", "corpus", len(candidates), broken, seeds)
 	mgr.seeds = nil
 
 	// We duplicate all inputs in the corpus and shuffle the second part.
@@ -666,11 +891,15 @@ func (mgr *Manager) loadCorpus() {
 	// in such case it will also lost all cached candidates. Or, the input can be somewhat flaky
 	// and doesn't give the coverage on first try. So we give each input the second chance.
 	// Shuffling should alleviate deterministically losing the same inputs on fuzzer crashing.
+	// The second copies are triaged after all the first copies.
 	candidates = append(candidates, candidates...)
 	shuffle := candidates[len(candidates)/2:]
 	rand.Shuffle(len(shuffle), func(i, j int) {
 		shuffle[i], shuffle[j] = shuffle[j], shuffle[i]
 	})
+	for i := range shuffle {
+		shuffle[i].Prio += secondChancePrio
+	}
 	if mgr.phase != phaseInit {
 		panic(fmt.Sprintf("loadCorpus: bad phase %v", mgr.phase))
 	}
@@ -678,6 +907,14 @@ func (mgr *Manager) loadCorpus() {
 	mgr.fuzzer.Load().AddCandidates(candidates)
 }
 
+// Priorities of the corpus candidates (lower values are triaged first).
+// Corpus programs are known to give coverage, so they go before the seeds;
+// within each group shorter programs go first since they are cheaper to triage.
+const (
+	seedPrio         = 1 << 16
+	secondChancePrio = 1 << 20
+)
+
 // Returns (delete item from the corpus, a fuzzer.Candidate object).
 func (mgr *Manager) loadProg(data []byte, minimized, smashed bool) (drop bool, candidate *fuzzer.Candidate) {
 	p, disabled, bad := parseProgram(mgr.target, mgr.targetEnabledSyscalls, data)
@@ -756,11 +993,13 @@ func (mgr *Manager) runInstance(index int) (*Crash, error) {
 	// Use unique instance names to prevent name collisions in case of untimely RPC messages.
 	instanceName := fmt.Sprintf("vm-%d", mgr.nextInstanceID.Add(1))
 	injectExec := make(chan bool, 10)
-	mgr.serv.createInstance(instanceName, injectExec)
+	mgr.serv.createInstance(instanceName, index, injectExec, mgr.vmSched.isSlow(index))
 
-	rep, vmInfo, err := mgr.runInstanceInner(index, instanceName, injectExec)
+	clock := new(report.ConsoleClock)
+	rep, vmInfo, dump, cores, err := mgr.runInstanceInner(index, instanceName, injectExec, clock)
 	lastExec, machineInfo := mgr.serv.shutdownInstance(instanceName, rep != nil)
 	if rep != nil {
+		annotateExecuting(rep, clock, lastExec)
 		prependExecuting(rep, lastExec)
 		if len(vmInfo) != 0 {
 			machineInfo = append(append(vmInfo, '\n'), machineInfo...)
@@ -778,13 +1017,31 @@ func (mgr *Manager) runInstance(index int) (*Crash, error) {
 	}
 	crash := &Crash{
 		instanceName: instanceName,
+		variant:      mgr.vmPool.Variant(index),
+		lastExec:     lastExec,
+		dump:         dump,
+		cores:        cores,
 		Report:       rep,
 	}
 	return crash, nil
 }
 
-func (mgr *Manager) runInstanceInner(index int, instanceName string, injectExec <-chan bool) (
-	*report.Report, []byte, error) {
+// noteWarning attributes a console line matching warning_patterns to the programs
+// that were executing on the instance when the line was printed.
+func (mgr *Manager) noteWarning(instanceName string, line []byte) {
+	fuzzer := mgr.fuzzer.Load()
+	if fuzzer == nil {
+		return
+	}
+	mgrLog.Logf(1, "%s: kernel warning: %s", instanceName, line)
+	for _, p := range mgr.serv.lastExecuting(instanceName) {
+		fuzzer.AddWarningProg(p)
+	}
+}
+
+func (mgr *Manager) runInstanceInner(index int, instanceName string, injectExec <-chan bool,
+	clock *report.ConsoleClock) (
+	*report.Report, []byte, string, string, error) {
 	start := time.Now()
 
 	inst, err := mgr.vmPool.Create(index)
@@ -803,20 +1060,20 @@ func (mgr *Manager) runInstanceInner(index int, instanceName string, injectExec
 					Output: output,
 				}
 			}
-			return rep, nil, nil
+			return rep, nil, "", "", nil
 		}
-		return nil, nil, fmt.Errorf("failed to create instance: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to create instance: %w", err)
 	}
 	defer inst.Close()
 
 	fwdAddr, err := inst.Forward(mgr.serv.port)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to setup port forwarding: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to setup port forwarding: %w", err)
 	}
 
 	fuzzerBin, err := inst.Copy(mgr.cfg.FuzzerBin)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to copy binary: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to copy binary: %w", err)
 	}
 
 	// If ExecutorBin is provided, it means that syz-executor is already in the image,
@@ -825,7 +1082,7 @@ func (mgr *Manager) runInstanceInner(index int, instanceName string, injectExec
 	if executorBin == "" {
 		executorBin, err = inst.Copy(mgr.cfg.ExecutorBin)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to copy binary: %w", err)
+			return nil, nil, "", "", fmt.Errorf("failed to copy binary: %w", err)
 		}
 	}
 
@@ -858,29 +1115,82 @@ func (mgr *Manager) runInstanceInner(index int, instanceName string, injectExec
 			PprofPort:  inst.PprofPort(),
 		},
 	}
+	if ext := mgr.cfg.ExternalNet; ext != nil {
+		args.Optional.ExternalNet = ext.Addr
+		args.Optional.ExternalNetRate = ext.Rate
+		args.Optional.ExternalNetProbe = ext.ProbePort
+	}
+	args.Optional.Watchdog = mgr.cfg.Experimental.Watchdog
+	args.Optional.WatchdogTimeout = mgr.cfg.Experimental.WatchdogTimeout
+	if capture := mgr.cfg.NetCapture; capture != nil {
+		var procs []string
+		for _, proc := range capture.Procs {
+			procs = append(procs, fmt.Sprint(proc))
+		}
+		args.Optional.NetCapture = capture.Iface
+		args.Optional.NetCaptureProcs = strings.Join(procs, ",")
+	}
 	cmd := instance.FuzzerCmd(args)
-	_, rep, err := inst.Run(mgr.cfg.Timeouts.VMRunningTime, mgr.reporter, cmd,
-		vm.ExitTimeout, vm.StopChan(mgr.vmStop), vm.InjectExecuting(injectExec),
+	// Instances that can't be used for reproduction are not stopped to free VMs for it.
+	var stop <-chan bool
+	if mgr.vmSched.reproRank(index) >= 0 {
+		stop = mgr.vmStop
+	}
+	opts := []any{
+		vm.ExitTimeout, vm.StopChan(stop), vm.InjectExecuting(injectExec), clock,
 		vm.EarlyFinishCb(func() {
 			// Depending on the crash type and kernel config, fuzzing may continue
 			// running for several seconds even after kernel has printed a crash report.
 			// This litters the log and we want to prevent it.
 			mgr.serv.stopFuzzing(instanceName)
 		}),
-	)
+	}
+	warningRes := mgr.warningRes
+	if mgr.cfg.Experimental.Watchdog != "" {
+		warningRes = append(warningRes[:len(warningRes):len(warningRes)], watchdogResetRe)
+	}
+	if len(warningRes) != 0 {
+		opts = append(opts, vm.OutputWarnings{
+			Res: warningRes,
+			Cb: func(line []byte) {
+				if match := watchdogResetRe.FindSubmatch(line); match != nil {
+					mgr.noteWatchdogReset(instanceName, string(match[1]))
+					return
+				}
+				mgr.noteWarning(instanceName, line)
+			},
+		})
+	}
+	_, rep, err := inst.Run(mgr.cfg.Timeouts.VMRunningTime, mgr.reporter, cmd, opts...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to run fuzzer: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to run fuzzer: %w", err)
 	}
 	if rep == nil {
 		// This is the only "OK" outcome.
-		log.Logf(0, "%s: running for %v, restarting", instanceName, time.Since(start))
-		return nil, nil, nil
+		mgrLog.Logf(0, "%s: running for %v, restarting", instanceName, time.Since(start))
+		return nil, nil, "", "", nil
 	}
 	vmInfo, err := inst.Info()
 	if err != nil {
 		vmInfo = []byte(fmt.Sprintf("error getting VM info: %v\n", err))
 	}
-	return rep, vmInfo, nil
+	dump := mgr.collectCrashDump(inst, instanceName, rep)
+	cores := mgr.collectCoreDump(inst, instanceName, executorBin, rep)
+	return rep, vmInfo, dump, cores, nil
+}
+
+// annotateExecuting marks the start of the last executing programs in the console output
+// according to the kernel timestamps, so that crashes can be attributed to the programs
+// that were running when the kernel started printing something suspicious.
+func annotateExecuting(rep *report.Report, clock *report.ConsoleClock, lastExec []ExecRecord) {
+	var progs []report.ExecutedProg
+	for _, exec := range lastExec {
+		progs = append(progs, report.ExecutedProg{
+			Name:  fmt.Sprintf("program %v ago on proc %v (see above)", exec.Time, exec.Proc),
+			Start: exec.Start,
+		})
+	}
+	report.AnnotateOutput(rep, clock, progs)
 }
 
 func prependExecuting(rep *report.Report, lastExec []ExecRecord) {
@@ -903,18 +1213,18 @@ func (mgr *Manager) emailCrash(crash *Crash) {
 	}
 	args := []string{"-s", "syzkaller: " + crash.Title}
 	args = append(args, mgr.cfg.EmailAddrs...)
-	log.Logf(0, "sending email to %v", mgr.cfg.EmailAddrs)
+	mgrLog.Logf(0, "sending email to %v", mgr.cfg.EmailAddrs)
 
 	cmd := exec.Command("mailx", args...)
 	cmd.Stdin = bytes.NewReader(crash.Report.Report)
 	if _, err := osutil.Run(10*time.Minute, cmd); err != nil {
-		log.Logf(0, "failed to send email: %v", err)
+		mgrLog.Logf(0, "failed to send email: %v", err)
 	}
 }
 
 func (mgr *Manager) saveCrash(crash *Crash) bool {
 	if err := mgr.reporter.Symbolize(crash.Report); err != nil {
-		log.Errorf("failed to symbolize report: %v", err)
+		mgrLog.Errorf("failed to symbolize report: %v", err)
 	}
 	if crash.Type == crash_pkg.MemoryLeak {
 		mgr.mu.Lock()
@@ -932,10 +1242,18 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	}
 	if crash.Suppressed {
 		flags += " [suppressed]"
+	} else if !crash.Corrupted && mgr.crashLimiter.noteCrash(crash.Title, time.Now()) {
+		crash.Suppressed = true
+		mgr.statRateLimited.Add(1)
+		flags += " [rate limited]"
 	}
-	log.Logf(0, "%s: crash: %v%v", crash.instanceName, crash.Title, flags)
+	if crash.variant != "" {
+		flags += fmt.Sprintf(" [variant %v]", crash.variant)
+	}
+	mgrLog.Logf(0, "%s: crash: %v%v", crash.instanceName, crash.Title, flags)
+	mgr.huntNoteCrash(crash)
 
-	if mgr.mode == ModeSmokeTest {
+	if mgr.mode == ModeSmokeTest || mgr.mode == ModeSoak {
 		data, err := json.Marshal(crash.Report)
 		if err != nil {
 			log.Fatalf("failed to serialize crash report: %v", err)
@@ -943,6 +1261,9 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 		if err := osutil.WriteFile(filepath.Join(mgr.cfg.Workdir, "report.json"), data); err != nil {
 			log.Fatal(err)
 		}
+		if mgr.mode == ModeSoak {
+			log.Fatalf("kernel crashed in soak mode, exiting")
+		}
 		log.Fatalf("kernel crashed in smoke testing mode, exiting")
 	}
 
@@ -954,6 +1275,25 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	}
 
 	mgr.statCrashes.Add(1)
+	if !crash.Suppressed {
+		for op := range MutationOps(crash.lastExec) {
+			if stat := mgr.statCrashMutations[op]; stat != nil {
+				stat.Add(1)
+			}
+		}
+		if fuzzer := mgr.fuzzer.Load(); fuzzer != nil && mgr.cfg.Experimental.CrashGuidedMutations {
+			crashes := make(map[prog.MutationOp]int)
+			for op, stat := range mgr.statCrashMutations {
+				if stat != nil {
+					crashes[prog.MutationOp(op)] = stat.Val()
+				}
+			}
+			fuzzer.SetMutationCrashes(crashes)
+		}
+	}
+	if stat := mgr.statCrashVariants[crash.variant]; stat != nil {
+		stat.Add(1)
+	}
 	mgr.mu.Lock()
 	if !mgr.crashTypes[crash.Title] {
 		mgr.crashTypes[crash.Title] = true
@@ -961,6 +1301,14 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	}
 	mgr.mu.Unlock()
 
+	if crash.dump != "" {
+		// Dumps are stored locally even if the crash is reported to the dashboard.
+		mgr.storeCrashDump(crash)
+	}
+	if crash.cores != "" {
+		mgr.storeCoreDump(crash)
+	}
+
 	if mgr.dash != nil {
 		if crash.Type == crash_pkg.MemoryLeak {
 			return true
@@ -974,12 +1322,13 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 			Recipients:  crash.Recipients.ToDash(),
 			Log:         crash.Output,
 			Report:      crash.Report.Report,
+			Severity:    crash.Severity,
 			MachineInfo: crash.MachineInfo,
 		}
 		setGuiltyFiles(dc, crash.Report)
 		resp, err := mgr.dash.ReportCrash(dc)
 		if err != nil {
-			log.Logf(0, "failed to report crash to dashboard: %v", err)
+			mgrLog.Logf(0, "failed to report crash to dashboard: %v", err)
 		} else {
 			// Don't store the crash locally, if we've successfully
 			// uploaded it to the dashboard. These will just eat disk space.
@@ -992,7 +1341,7 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	dir := filepath.Join(mgr.crashdir, id)
 	osutil.MkdirAll(dir)
 	if err := osutil.WriteFile(filepath.Join(dir, "description"), []byte(crash.Title+"\n")); err != nil {
-		log.Logf(0, "failed to write crash: %v", err)
+		mgrLog.Logf(0, "failed to write crash: %v", err)
 	}
 
 	// Save up to mgr.cfg.MaxCrashLogs reports, overwrite the oldest once we've reached that number.
@@ -1026,6 +1375,14 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	writeOrRemove("tag", []byte(mgr.cfg.Tag))
 	writeOrRemove("report", crash.Report.Report)
 	writeOrRemove("machineInfo", crash.MachineInfo)
+	writeOrRemove("variant", []byte(crash.variant))
+	writeOrRemove("commits", guiltyCommitsText(crash.Report))
+	writeOrRemove("secondary", secondaryReportsText(crash.Report))
+	var severity []byte
+	if crash.Severity != crash_pkg.UnknownSeverity {
+		severity = []byte(crash.Severity.String())
+	}
+	writeOrRemove("severity", severity)
 	return mgr.needLocalRepro(crash)
 }
 
@@ -1069,7 +1426,7 @@ func (mgr *Manager) needRepro(crash *Crash) bool {
 	}
 	needRepro, err := mgr.dash.NeedRepro(cid)
 	if err != nil {
-		log.Logf(0, "dashboard.NeedRepro failed: %v", err)
+		mgrLog.Logf(0, "dashboard.NeedRepro failed: %v", err)
 	}
 	return needRepro
 }
@@ -1086,7 +1443,7 @@ func (mgr *Manager) saveFailedRepro(rep *report.Report, stats *repro.Stats) {
 		if rep.Type == crash_pkg.MemoryLeak {
 			// Don't send failed leak repro attempts to dashboard
 			// as we did not send the crash itself.
-			log.Logf(1, "failed repro of '%v': not sending because of the memleak type", rep.Title)
+			mgrLog.Logf(1, "failed repro of '%v': not sending because of the memleak type", rep.Title)
 			return
 		}
 		cid := &dashapi.CrashID{
@@ -1098,7 +1455,7 @@ func (mgr *Manager) saveFailedRepro(rep *report.Report, stats *repro.Stats) {
 			ReproLog:     truncateReproLog(reproLog),
 		}
 		if err := mgr.dash.ReportFailedRepro(cid); err != nil {
-			log.Logf(0, "failed to report failed repro to dashboard (log size %d): %v",
+			mgrLog.Logf(0, "failed to report failed repro to dashboard (log size %d): %v",
 				len(reproLog), err)
 		} else {
 			return
@@ -1121,7 +1478,8 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 	progText := repro.Prog.Serialize()
 
 	// Append this repro to repro list to send to hub if it didn't come from hub originally.
-	if !res.fromHub {
+	// Flaky repros are not shared, other managers would most likely fail to reproduce the crash with them.
+	if !res.fromHub && !repro.Reliability.Flaky() {
 		progForHub := []byte(fmt.Sprintf("# %+v\n# %v\n# %v\n%s",
 			repro.Opts, repro.Report.Title, mgr.cfg.Tag, progText))
 		mgr.mu.Lock()
@@ -1139,7 +1497,7 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 			}
 			cprogText = cprog
 		} else {
-			log.Logf(0, "failed to write C source: %v", err)
+			mgrLog.Logf(0, "failed to write C source: %v", err)
 		}
 	}
 
@@ -1161,6 +1519,9 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 			output = res.strace.Output
 			crashFlags = dashapi.CrashUnderStrace
 		}
+		if repro.Reliability.Flaky() {
+			crashFlags |= dashapi.CrashFlakyRepro
+		}
 
 		dc := &dashapi.Crash{
 			BuildID:       mgr.cfg.Tag,
@@ -1180,7 +1541,7 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 		}
 		setGuiltyFiles(dc, report)
 		if _, err := mgr.dash.ReportCrash(dc); err != nil {
-			log.Logf(0, "failed to report repro to dashboard: %v", err)
+			mgrLog.Logf(0, "failed to report repro to dashboard: %v", err)
 		} else {
 			// Don't store the crash locally, if we've successfully
 			// uploaded it to the dashboard. These will just eat disk space.
@@ -1193,7 +1554,7 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 	osutil.MkdirAll(dir)
 
 	if err := osutil.WriteFile(filepath.Join(dir, "description"), []byte(rep.Title+"\n")); err != nil {
-		log.Logf(0, "failed to write crash: %v", err)
+		mgrLog.Logf(0, "failed to write crash: %v", err)
 	}
 	osutil.WriteFile(filepath.Join(dir, "repro.prog"), append([]byte(opts), progText...))
 	if mgr.cfg.Tag != "" {
@@ -1208,10 +1569,13 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 	if len(cprogText) > 0 {
 		osutil.WriteFile(filepath.Join(dir, "repro.cprog"), cprogText)
 	}
+	if repro.Reliability.Runs != 0 {
+		osutil.WriteFile(filepath.Join(dir, "repro.reliability"), []byte(repro.Reliability.String()))
+	}
 	repro.Prog.ForEachAsset(func(name string, typ prog.AssetType, r io.Reader) {
 		fileName := filepath.Join(dir, name+".gz")
 		if err := osutil.WriteGzipStream(fileName, r); err != nil {
-			log.Logf(0, "failed to write crash asset: type %d, write error %v", typ, err)
+			mgrLog.Logf(0, "failed to write crash asset: type %d, write error %v", typ, err)
 		}
 	})
 	if res.strace != nil {
@@ -1227,6 +1591,17 @@ func (mgr *Manager) saveRepro(res *ReproResult) {
 	if reproLog := fullReproLog(res.stats); len(reproLog) > 0 {
 		osutil.WriteFile(filepath.Join(dir, "repro.stats"), reproLog)
 	}
+	if mgr.assetStorage != nil && mgr.dash == nil {
+		// Uploads (in particular of the kernel/disk images) may take long,
+		// so do them in the background to not block the VM loop.
+		reproProg := append([]byte(opts), progText...)
+		go func() {
+			assets := append(mgr.uploadReproAssets(repro), mgr.uploadLocalReproAssets(reproProg, cprogText)...)
+			if data, err := json.MarshalIndent(assets, "", "\t"); err == nil {
+				osutil.WriteFile(filepath.Join(dir, "repro.assets"), data)
+			}
+		}()
+	}
 }
 
 func (mgr *Manager) uploadReproAssets(repro *repro.Result) []dashapi.NewAsset {
@@ -1244,7 +1619,7 @@ func (mgr *Manager) uploadReproAssets(repro *repro.Result) []dashapi.NewAsset {
 		}
 		asset, err := mgr.assetStorage.UploadCrashAsset(r, name, dashTyp, nil)
 		if err != nil {
-			log.Logf(1, "processing of the asset %v (%v) failed: %v", name, typ, err)
+			mgrLog.Logf(1, "processing of the asset %v (%v) failed: %v", name, typ, err)
 			return
 		}
 		ret = append(ret, asset)
@@ -1252,14 +1627,92 @@ func (mgr *Manager) uploadReproAssets(repro *repro.Result) []dashapi.NewAsset {
 	return ret
 }
 
+// uploadLocalReproAssets uploads the reproducers and the kernel/disk images,
+// so that a locally saved crash can be handed over to someone else.
+// With a dashboard this is not needed: the dashboard stores reproducers itself
+// and syz-ci uploads the build assets.
+func (mgr *Manager) uploadLocalReproAssets(progText, cprogText []byte) []dashapi.NewAsset {
+	var ret []dashapi.NewAsset
+	upload := func(data []byte, name string, typ dashapi.AssetType) {
+		if len(data) == 0 || !mgr.assetStorage.AssetTypeEnabled(typ) {
+			return
+		}
+		asset, err := mgr.assetStorage.UploadCrashAsset(bytes.NewReader(data), name, typ, nil)
+		if err != nil {
+			mgrLog.Logf(0, "failed to upload %v: %v", name, err)
+			return
+		}
+		ret = append(ret, asset)
+	}
+	upload(progText, "repro.syz", dashapi.ReproSyz)
+	upload(cprogText, "repro.c", dashapi.ReproC)
+	mgr.buildAssetsOnce.Do(mgr.uploadBuildAssets)
+	return append(ret, mgr.buildAssets...)
+}
+
+func (mgr *Manager) uploadBuildAssets() {
+	var kernelObject string
+	if mgr.cfg.KernelObj != "" && mgr.sysTarget.KernelObject != "" {
+		kernelObject = filepath.Join(mgr.cfg.KernelObj, mgr.sysTarget.KernelObject)
+	}
+	files := []struct {
+		typ  dashapi.AssetType
+		file string
+	}{
+		{dashapi.KernelImage, vmKernelImage(mgr.cfg.VM)},
+		{dashapi.KernelObject, kernelObject},
+		{dashapi.BootableDisk, mgr.cfg.Image},
+	}
+	for _, f := range files {
+		if f.file == "" || !mgr.assetStorage.AssetTypeEnabled(f.typ) {
+			continue
+		}
+		stat, err := os.Stat(f.file)
+		if err != nil {
+			mgrLog.Logf(0, "failed to upload %v: %v", f.file, err)
+			continue
+		}
+		file, err := os.Open(f.file)
+		if err != nil {
+			mgrLog.Logf(0, "failed to upload %v: %v", f.file, err)
+			continue
+		}
+		// The same file is not uploaded again after manager restarts.
+		extra := &asset.ExtraUploadArg{
+			UniqueTag:    hash.String([]byte(fmt.Sprintf("%v-%v-%v", f.file, stat.Size(), stat.ModTime()))),
+			SkipIfExists: true,
+		}
+		uploaded, err := mgr.assetStorage.UploadCrashAsset(file, filepath.Base(f.file), f.typ, extra)
+		file.Close()
+		if err != nil {
+			mgrLog.Logf(0, "failed to upload %v: %v", f.file, err)
+			continue
+		}
+		mgr.buildAssets = append(mgr.buildAssets, uploaded)
+	}
+}
+
+// vmKernelImage returns the kernel image specified in the VM config (if any).
+func vmKernelImage(vmCfg json.RawMessage) string {
+	var cfg struct {
+		Kernel string `json:"kernel"`
+	}
+	if err := json.Unmarshal(vmCfg, &cfg); err != nil {
+		return ""
+	}
+	return cfg.Kernel
+}
+
 func fullReproLog(stats *repro.Stats) []byte {
 	if stats == nil {
 		return nil
 	}
 	return []byte(fmt.Sprintf("Extracting prog: %v\nMinimizing prog: %v\n"+
-		"Simplifying prog options: %v\nExtracting C: %v\nSimplifying C: %v\n\n\n%s",
+		"Simplifying prog options: %v\nExtracting C: %v\nSimplifying C: %v\n"+
+		"Minimizing C: %v\nMeasuring reliability: %v\n\n\n%s",
 		stats.ExtractProgTime, stats.MinimizeProgTime,
-		stats.SimplifyProgTime, stats.ExtractCTime, stats.SimplifyCTime, stats.Log))
+		stats.SimplifyProgTime, stats.ExtractCTime, stats.SimplifyCTime,
+		stats.MinimizeCTime, stats.ReliabilityTime, stats.Log))
 }
 
 func (mgr *Manager) corpusInputHandler(updates <-chan corpus.NewItemEvent) {
@@ -1272,7 +1725,7 @@ func (mgr *Manager) corpusInputHandler(updates <-chan corpus.NewItemEvent) {
 		mgr.corpusDBMu.Lock()
 		mgr.corpusDB.Save(update.Sig, update.ProgData, 0)
 		if err := mgr.corpusDB.Flush(); err != nil {
-			log.Errorf("failed to save corpus database: %v", err)
+			mgrLog.Errorf("failed to save corpus database: %v", err)
 		}
 		mgr.corpusDBMu.Unlock()
 	}
@@ -1314,7 +1767,7 @@ func (mgr *Manager) minimizeCorpusLocked() {
 	mgr.corpus.Minimize(mgr.cfg.Cover)
 	newSize := mgr.corpus.StatProgs.Val()
 
-	log.Logf(1, "minimized corpus: %v -> %v", currSize, newSize)
+	mgrLog.Logf(1, "minimized corpus: %v -> %v", currSize, newSize)
 	mgr.lastMinCorpus = newSize
 
 	// From time to time we get corpus explosion due to different reason:
@@ -1347,7 +1800,7 @@ func (mgr *Manager) minimizeCorpusLocked() {
 			continue
 		}
 		mgr.saturatedCalls[call] = true
-		log.Logf(0, "coverage for %v has saturated, not accepting more inputs", call)
+		mgrLog.Logf(0, "coverage for %v has saturated, not accepting more inputs", call)
 	}
 
 	// Don't minimize persistent corpus until fuzzers have triaged all inputs from it.
@@ -1372,6 +1825,33 @@ func setGuiltyFiles(crash *dashapi.Crash, report *report.Report) {
 	}
 }
 
+// guiltyCommitsText lists the recent commits touching the guilty file (see Experimental.GuiltyCommits).
+func guiltyCommitsText(rep *report.Report) []byte {
+	if len(rep.GuiltyCommits) == 0 {
+		return nil
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "Recent commits touching %v:\n", rep.GuiltyFile)
+	for _, com := range rep.GuiltyCommits {
+		fmt.Fprintf(buf, "%v %v (%v, %v)\n", com.Hash, com.Title, com.Author, com.Date.Format(time.DateOnly))
+	}
+	return buf.Bytes()
+}
+
+// secondaryReportsText lists the other reports found in the crash output
+// numbered by their position in the output.
+func secondaryReportsText(rep *report.Report) []byte {
+	if len(rep.Secondary) == 0 {
+		return nil
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "The crash is report #%v in the output.\n\n", rep.Index)
+	for _, secondary := range rep.Secondary {
+		fmt.Fprintf(buf, "#%v: %v\n\n%s\n", secondary.Index, secondary.Title, secondary.Report)
+	}
+	return buf.Bytes()
+}
+
 func (mgr *Manager) collectSyscallInfo() map[string]*corpus.CallCov {
 	mgr.mu.Lock()
 	enabledSyscalls := mgr.targetEnabledSyscalls
@@ -1409,7 +1889,7 @@ func (mgr *Manager) currentBugFrames() BugFrames {
 func (mgr *Manager) machineChecked(features flatrpc.Feature, enabledSyscalls map[*prog.Syscall]bool,
 	opts flatrpc.ExecOpts) queue.Source {
 	if mgr.mode == ModeSmokeTest {
-		log.Logf(0, "smoke test succeeded, shutting down...")
+		mgrLog.Logf(0, "smoke test succeeded, shutting down...")
 		close(vm.Shutdown)
 		time.Sleep(10 * time.Second)
 		os.Exit(0)
@@ -1427,6 +1907,31 @@ func (mgr *Manager) machineChecked(features flatrpc.Feature, enabledSyscalls map
 		stats.Simple, stats.NoGraph, stats.Link("/syscalls"))
 	statSyscalls.Add(len(enabledSyscalls))
 
+	if mgr.mode == ModeSoak {
+		mgr.firstConnect.Store(time.Now().Unix())
+		return mgr.soakSource(opts)
+	}
+	if mgr.mode == ModeValidate {
+		mgr.firstConnect.Store(time.Now().Unix())
+		return mgr.validationSource(enabledSyscalls, opts)
+	}
+	if features&flatrpc.FeatureKCSAN != 0 {
+		opts.ExecFlags |= flatrpc.ExecFlagCollectRaces
+	}
+	if mgr.cfg.Experimental.WarningFeedback {
+		opts.ExecFlags |= flatrpc.ExecFlagCollectWarnings
+	}
+	stateCalls := make(map[*prog.Syscall]bool)
+	for id := range mgr.cfg.StateCalls {
+		if call := mgr.target.Syscalls[id]; enabledSyscalls[call] {
+			stateCalls[call] = true
+		}
+	}
+	// Keep one long-lived state per VM.
+	longProgs := 1
+	if mgr.vmPool != nil {
+		longProgs = mgr.vmPool.Count()
+	}
 	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
 	fuzzerObj := fuzzer.NewFuzzer(context.Background(), &fuzzer.Config{
 		Corpus:         mgr.corpus,
@@ -1438,11 +1943,27 @@ func (mgr *Manager) machineChecked(features flatrpc.Feature, enabledSyscalls map
 		EnabledCalls:   enabledSyscalls,
 		NoMutateCalls:  mgr.cfg.NoMutateCalls,
 		FetchRawCover:  mgr.cfg.RawCover,
+		DeflakeRuns:    mgr.cfg.Experimental.DeflakeRuns,
+		DeflakeMaxRuns: mgr.cfg.Experimental.DeflakeMaxRuns,
+		RaceFeedback:   features&flatrpc.FeatureKCSAN != 0,
+		MutationTuning: mgr.cfg.Experimental.MutationTuning,
+
+		WarningFeedback: mgr.cfg.Experimental.WarningFeedback,
+		SuspendResume:   features&flatrpc.FeatureSuspend != 0,
+		UringCalls:      features&flatrpc.FeatureIOUring != 0,
+		CompatCalls:     features&flatrpc.FeatureCompatSyscalls != 0,
+		LongProgs:       longProgs,
+		StateCalls:      stateCalls,
+		SlowProgBudget:  time.Duration(mgr.cfg.Experimental.SlowProgBudget) * time.Millisecond,
+		DepthSignal:     mgr.cfg.Experimental.DepthSignal,
+
+		CandidateInterleave: mgr.cfg.Experimental.CorpusTriageInterleave,
+		CandidateDeadline:   time.Duration(mgr.cfg.Experimental.CorpusTriageDeadline) * time.Minute,
 		Logf: func(level int, msg string, args ...interface{}) {
 			if level != 0 {
 				return
 			}
-			log.Logf(level, msg, args...)
+			mgrLog.Logf(level, msg, args...)
 		},
 		NewInputFilter: func(call string) bool {
 			mgr.mu.Lock()
@@ -1451,6 +1972,7 @@ func (mgr *Manager) machineChecked(features flatrpc.Feature, enabledSyscalls map
 		},
 	}, rnd, mgr.target)
 	mgr.fuzzer.Store(fuzzerObj)
+	mgr.applyHunt()
 
 	mgr.loadCorpus()
 	mgr.firstConnect.Store(time.Now().Unix())
@@ -1509,7 +2031,7 @@ func (mgr *Manager) fuzzerLoop(fuzzer *fuzzer.Fuzzer) {
 		if mgr.cfg.Cover {
 			// Distribute new max signal over all instances.
 			newSignal, dropSignal := fuzzer.Cover.GrabSignalDelta()
-			log.Logf(2, "distributing %d new signal, %d dropped signal",
+			mgrLog.Logf(2, "distributing %d new signal, %d dropped signal",
 				len(newSignal), len(dropSignal))
 			if len(newSignal)+len(dropSignal) != 0 {
 				mgr.serv.distributeSignalDelta(newSignal, dropSignal)
@@ -1520,6 +2042,11 @@ func (mgr *Manager) fuzzerLoop(fuzzer *fuzzer.Fuzzer) {
 		if fuzzer.StatCandidates.Val() == 0 {
 			mgr.mu.Lock()
 			if mgr.phase == phaseLoadedCorpus {
+				if len(mgr.snapshotSignal) != 0 {
+					// Applying it earlier would prevent triage of the corpus candidates.
+					fuzzer.Cover.AddMaxSignal(signal.FromRaw(mgr.snapshotSignal, 0))
+					mgr.snapshotSignal = nil
+				}
 				if mgr.enabledFeatures&flatrpc.FeatureLeak != 0 {
 					mgr.serv.startLeakChecking()
 				}
@@ -1544,7 +2071,7 @@ func (mgr *Manager) hubIsUnreachable() {
 	if mgr.phase == phaseTriagedCorpus {
 		dash = mgr.dash
 		mgr.phase = phaseTriagedHub
-		log.Errorf("did not manage to connect to syz-hub; moving forward")
+		mgrLog.Errorf("did not manage to connect to syz-hub; moving forward")
 	}
 	mgr.mu.Unlock()
 	if dash != nil {
@@ -1600,7 +2127,13 @@ func (mgr *Manager) dashboardReporter() {
 	triageInfoSent := false
 	var lastFuzzingTime time.Duration
 	var lastCrashes, lastSuppressedCrashes, lastExecs uint64
-	for range time.NewTicker(time.Minute).C {
+	ticker := time.NewTicker(time.Minute)
+	for {
+		var flushed chan struct{}
+		select {
+		case <-ticker.C:
+		case flushed = <-mgr.dashStatsFlush:
+		}
 		mgr.mu.Lock()
 		req := &dashapi.ManagerStatsReq{
 			Name:              mgr.cfg.Name,
@@ -1622,8 +2155,12 @@ func (mgr *Manager) dashboardReporter() {
 		}
 		mgr.mu.Unlock()
 
-		if err := mgr.dash.UploadManagerStats(req); err != nil {
-			log.Logf(0, "failed to upload dashboard stats: %v", err)
+		err := mgr.dash.UploadManagerStats(req)
+		if flushed != nil {
+			close(flushed)
+		}
+		if err != nil {
+			mgrLog.Logf(0, "failed to upload dashboard stats: %v", err)
 			continue
 		}
 		mgr.mu.Lock()
@@ -1645,7 +2182,7 @@ func (mgr *Manager) dashboardReproTasks() {
 		}
 		resp, err := mgr.dash.LogToRepro(&dashapi.LogToReproReq{BuildID: mgr.cfg.Tag})
 		if err != nil {
-			log.Logf(0, "failed to query logs to reproduce: %v", err)
+			mgrLog.Logf(0, "failed to query logs to reproduce: %v", err)
 			continue
 		}
 		if len(resp.CrashLog) > 0 {
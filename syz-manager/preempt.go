@@ -0,0 +1,151 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/gce"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// The manager may run on a preemptible/spot GCE instance (experimental.preemptible_host).
+// GCE gives the instance 30 seconds after the preemption notice, then the OS is shut down
+// and the manager gets SIGTERM and exits as usual. On the notice the manager saves state
+// that would otherwise be lost and restores it on the next start:
+//   - max signal (preempted.tar.gz);
+//   - inputs with new signal that are still being triaged, they are re-triaged as candidates
+//     (preempted.json);
+//   - repros that were not sent to syz-hub yet (preempted.json).
+//
+// The rest is already persistent: the corpus database is flushed on every new input
+// (it's flushed once more on the notice), and crashes pending reproduction, including
+// the ones being reproduced, are kept in workdir/repro_queue and are retried on restart.
+// Dashboard stats are uploaded right away, so that the last minute is not lost.
+//
+// Recreating/restarting the preempted instance is out of scope of the manager
+// (the instance is gone after the shutdown), this is left to the managed instance group
+// or whatever else started the manager. Preempted fuzzing VMs (vm/gce spot option)
+// are recreated by the VM loop as any other lost VM.
+const (
+	preemptCheckpointFile = "preempted.tar.gz"
+	preemptStateFile      = "preempted.json"
+)
+
+type preemptState struct {
+	Triage    [][]byte `json:"triage,omitempty"`
+	HubRepros [][]byte `json:"hub_repros,omitempty"`
+}
+
+func (mgr *Manager) preemptionLoop() {
+	gceCtx, err := gce.NewContext("")
+	if err != nil {
+		mgrLog.Errorf("failed to init gce for preemption notices: %v", err)
+		return
+	}
+	<-gceCtx.PreemptionNotice()
+	mgrLog.Logf(0, "the manager instance is preempted, checkpointing fuzzer state")
+	mgr.preempt()
+}
+
+func (mgr *Manager) preempt() {
+	if err := mgr.writeCheckpoint(); err != nil {
+		mgrLog.Errorf("failed to write preemption checkpoint: %v", err)
+	} else {
+		mgrLog.Logf(0, "fuzzer state is saved to %v", preemptCheckpointFile)
+	}
+	state := mgr.pendingState()
+	if err := writePreemptState(mgr.cfg.Workdir, state); err != nil {
+		mgrLog.Errorf("failed to save pending state: %v", err)
+	} else {
+		mgrLog.Logf(0, "saved %v inputs pending triage and %v repros pending hub sync to %v",
+			len(state.Triage), len(state.HubRepros), preemptStateFile)
+	}
+	mgr.corpusDBMu.Lock()
+	if err := mgr.corpusDB.Flush(); err != nil {
+		mgrLog.Errorf("failed to save corpus database: %v", err)
+	}
+	mgr.corpusDBMu.Unlock()
+	if mgr.dash != nil {
+		flushed := make(chan struct{})
+		select {
+		case mgr.dashStatsFlush <- flushed:
+			<-flushed
+		case <-time.After(10 * time.Second):
+			mgrLog.Logf(0, "failed to upload dashboard stats before preemption")
+		}
+	}
+}
+
+func (mgr *Manager) pendingState() *preemptState {
+	state := new(preemptState)
+	if fuzzerObj := mgr.fuzzer.Load(); fuzzerObj != nil {
+		state.Triage = fuzzerObj.PendingTriage()
+	}
+	mgr.mu.Lock()
+	state.HubRepros = append(state.HubRepros, mgr.newRepros...)
+	mgr.mu.Unlock()
+	if hc := mgr.hubConn.Load(); hc != nil {
+		state.HubRepros = append(state.HubRepros, hc.unsentRepros()...)
+	}
+	return state
+}
+
+func writePreemptState(workdir string, state *preemptState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(workdir, preemptStateFile)
+	if err := osutil.WriteFile(file+".tmp", data); err != nil {
+		return err
+	}
+	return osutil.Rename(file+".tmp", file)
+}
+
+func (mgr *Manager) writeCheckpoint() error {
+	file := filepath.Join(mgr.cfg.Workdir, preemptCheckpointFile)
+	f, err := os.Create(file + ".tmp")
+	if err != nil {
+		return err
+	}
+	if err := mgr.writeSnapshot(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return osutil.Rename(file+".tmp", file)
+}
+
+// restorePreemptCheckpoint returns max signal and pending state saved by the previous run
+// of the manager on preemption (if any). The checkpoint is consumed.
+func restorePreemptCheckpoint(workdir, target string) ([]uint64, *preemptState) {
+	state := new(preemptState)
+	if file := filepath.Join(workdir, preemptStateFile); osutil.IsExist(file) {
+		defer os.Remove(file)
+		if data, err := os.ReadFile(file); err != nil {
+			mgrLog.Logf(0, "failed to read preemption state: %v", err)
+		} else if err := json.Unmarshal(data, state); err != nil {
+			mgrLog.Logf(0, "failed to parse preemption state: %v", err)
+			state = new(preemptState)
+		}
+	}
+	file := filepath.Join(workdir, preemptCheckpointFile)
+	if !osutil.IsExist(file) {
+		return nil, state
+	}
+	defer os.Remove(file)
+	// The corpus database is always up-to-date, so the corpus is not restored.
+	maxSignal, err := restoreSnapshot(file, "", target)
+	if err != nil {
+		mgrLog.Logf(0, "failed to restore preemption checkpoint: %v", err)
+		return nil, state
+	}
+	return maxSignal, state
+}
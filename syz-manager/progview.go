@@ -0,0 +1,227 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/html/pages"
+	"github.com/google/syzkaller/prog"
+)
+
+// httpProgram renders a corpus input or a crash repro program with every call annotated
+// with the coverage it contributed, it helps to understand why a program is in the corpus.
+// Calls of crash programs are annotated with the corpus inputs and coverage of the same syscall.
+func (mgr *Manager) httpProgram(w http.ResponseWriter, r *http.Request) {
+	var data *UIProgram
+	if sig := r.FormValue("sig"); sig != "" {
+		mgr.mu.Lock()
+		inp := mgr.corpus.Item(sig)
+		mgr.mu.Unlock()
+		if inp == nil {
+			http.Error(w, "can't find the input", http.StatusInternalServerError)
+			return
+		}
+		data = corpusProgramView(inp)
+	} else {
+		crash := readCrash(mgr.cfg.Workdir, r.FormValue("crash"), nil, 0, false)
+		if crash == nil {
+			http.Error(w, "failed to read crash info", http.StatusInternalServerError)
+			return
+		}
+		progData, err := os.ReadFile(filepath.Join(mgr.cfg.Workdir, "crashes", crash.ID, "repro.prog"))
+		if err != nil {
+			http.Error(w, "the crash has no repro program", http.StatusInternalServerError)
+			return
+		}
+		p, err := mgr.target.Deserialize(progData, prog.NonStrict)
+		if err != nil {
+			http.Error(w, "failed to parse the repro program", http.StatusInternalServerError)
+			return
+		}
+		mgr.mu.Lock()
+		corpus := mgr.corpus
+		mgr.mu.Unlock()
+		data = crashProgramView(crash, p, corpus.CallCover())
+	}
+	data.CoverEnabled = mgr.cfg.Cover
+	data.RawCover = mgr.cfg.RawCover
+	executeTemplate(w, programTemplate, data)
+}
+
+func corpusProgramView(inp *corpus.Item) *UIProgram {
+	data := &UIProgram{
+		Title:      "corpus input " + inp.Sig,
+		Sig:        inp.Sig,
+		Cover:      len(inp.Cover),
+		ExtraCover: len(inp.CallCover(-1)),
+	}
+	maxCover := 0
+	for i, line := range programLines(inp.Prog) {
+		call := &UIProgramCall{
+			Index: i,
+			Text:  line,
+			Name:  inp.Prog.CallName(i),
+			Main:  i == inp.Call,
+			Cover: len(inp.CallCover(i)),
+		}
+		for id, update := range inp.Updates {
+			if update.Call == i {
+				call.UpdateIDs = append(call.UpdateIDs, id)
+			}
+		}
+		maxCover = max(maxCover, call.Cover)
+		data.Calls = append(data.Calls, call)
+	}
+	for _, call := range data.Calls {
+		if maxCover != 0 {
+			call.Percent = call.Cover * 100 / maxCover
+		}
+	}
+	return data
+}
+
+func crashProgramView(crash *UICrashType, p *prog.Prog, callCover map[string]*corpus.CallCov) *UIProgram {
+	data := &UIProgram{
+		Title:   crash.Description,
+		CrashID: crash.ID,
+	}
+	maxCover := 0
+	for i, line := range programLines(p) {
+		call := &UIProgramCall{
+			Index: i,
+			Text:  line,
+			Name:  p.CallName(i),
+		}
+		if cc := callCover[call.Name]; cc != nil {
+			call.Inputs = cc.Count
+			call.Cover = len(cc.Cover)
+		}
+		maxCover = max(maxCover, call.Cover)
+		data.Calls = append(data.Calls, call)
+	}
+	for _, call := range data.Calls {
+		if maxCover != 0 {
+			call.Percent = call.Cover * 100 / maxCover
+		}
+	}
+	return data
+}
+
+// programLines returns the serialized calls of the program (one line per call).
+func programLines(p *prog.Prog) []string {
+	return strings.Split(strings.TrimSuffix(string(p.Serialize()), "\n"), "\n")
+}
+
+type UIProgram struct {
+	Title        string
+	Sig          string // set for corpus inputs
+	CrashID      string // set for crash repro programs
+	Cover        int
+	ExtraCover   int
+	CoverEnabled bool
+	RawCover     bool
+	Calls        []*UIProgramCall
+}
+
+type UIProgramCall struct {
+	Index int
+	Text  string
+	Name  string
+	// The call the program was added to the corpus for.
+	Main bool
+	// For corpus inputs: PCs covered by the call, for crash programs:
+	// the number of corpus inputs for the syscall and their total coverage.
+	Cover   int
+	Inputs  int
+	Percent int // Cover relative to the call with the max coverage
+	// Corpus updates that added new signal of the call (see corpus.ItemUpdate).
+	UpdateIDs []int
+}
+
+var programTemplate = pages.Create(`
+<!doctype html>
+<html>
+<head>
+	<title>{{.Title}}</title>
+	{{HEAD}}
+	<style>
+		.cover_bar {
+			background: #9c9;
+			height: 1em;
+		}
+		.main_call {
+			font-weight: bold;
+		}
+		.no_cover {
+			color: #999;
+		}
+	</style>
+</head>
+<body>
+<b>{{.Title}}</b>
+{{if .Sig}}
+<br>Coverage: {{if .CoverEnabled}}<a href="/cover?input={{.Sig}}">{{.Cover}}</a>{{else}}{{.Cover}}{{end}}
+{{if .ExtraCover}}
+	(extra: {{if .CoverEnabled}}<a href="/cover?input={{.Sig}}&call_id=-1">{{.ExtraCover}}</a>{{else}}{{.ExtraCover}}{{end}})
+{{end}}
+<br><a href="/input?sig={{.Sig}}">Program text</a>
+{{else}}
+<br><a href="/crash?id={{.CrashID}}">Crash</a>, <a href="/file?name=crashes/{{.CrashID}}/repro.prog">program text</a>
+{{end}}
+
+<table class="list_table">
+	<tr>
+		<th>#</th>
+		<th>Call</th>
+		{{if .Sig}}
+		<th>Coverage</th>
+		<th>New signal</th>
+		{{else}}
+		<th>Corpus inputs</th>
+		<th>Corpus coverage</th>
+		{{end}}
+		<th></th>
+	</tr>
+	{{range $c := $.Calls}}
+	<tr class="{{if $c.Main}}main_call{{end}} {{if not $c.Cover}}no_cover{{end}}">
+		<td>{{$c.Index}}</td>
+		<td>{{$c.Text}}</td>
+		{{if $.Sig}}
+		<td>
+			{{if and $.CoverEnabled $c.Cover}}
+				<a href="/cover?input={{$.Sig}}&call_id={{$c.Index}}">{{$c.Cover}}</a>
+			{{else}}
+				{{$c.Cover}}
+			{{end}}
+		</td>
+		<td>
+			{{range $id := $c.UpdateIDs}}
+				{{if $.RawCover}}
+					<a href="/rawcover?input={{$.Sig}}&update_id={{$id}}">[{{$id}}]</a>
+				{{else}}
+					[{{$id}}]
+				{{end}}
+			{{end}}
+		</td>
+		{{else}}
+		<td><a href="/corpus?call={{$c.Name}}">{{$c.Inputs}}</a></td>
+		<td>
+			{{if and $.CoverEnabled $c.Cover}}
+				<a href="/cover?call={{$c.Name}}">{{$c.Cover}}</a>
+			{{else}}
+				{{$c.Cover}}
+			{{end}}
+		</td>
+		{{end}}
+		<td style="width: 10em"><div class="cover_bar" style="width: {{$c.Percent}}%"></div></td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
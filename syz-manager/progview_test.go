@@ -0,0 +1,51 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgramView(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("r0 = test$res0()\ntest$res1(r0)\ntest$res2()\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpusObj := corpus.NewCorpus(context.Background())
+	corpusObj.Save(corpus.NewInput{Prog: p, Call: 1, Cover: []uint64{1, 2, 3, 4}})
+	corpusObj.Save(corpus.NewInput{Prog: p, Call: 0, Cover: []uint64{1, 5}})
+	inp := corpusObj.Items()[0]
+
+	data := corpusProgramView(inp)
+	assert.Equal(t, 5, data.Cover)
+	assert.Len(t, data.Calls, 3)
+	// Only PC 5 is new for the second update.
+	assert.Equal(t, &UIProgramCall{Index: 0, Text: "r0 = test$res0()", Name: "test$res0",
+		Cover: 1, Percent: 25, UpdateIDs: []int{1}}, data.Calls[0])
+	assert.Equal(t, &UIProgramCall{Index: 1, Text: "test$res1(r0)", Name: "test$res1",
+		Main: true, Cover: 4, Percent: 100, UpdateIDs: []int{0}}, data.Calls[1])
+	assert.Equal(t, 0, data.Calls[2].Cover)
+
+	crash := &UICrashType{Description: "crash title", ID: "0123"}
+	data = crashProgramView(crash, p, corpusObj.CallCover())
+	assert.Equal(t, "crash title", data.Title)
+	assert.Equal(t, 1, data.Calls[1].Inputs)
+	assert.Equal(t, 5, data.Calls[1].Cover)
+	assert.Equal(t, 0, data.Calls[0].Inputs)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, programTemplate.Execute(buf, corpusProgramView(inp)))
+	assert.Contains(t, buf.String(), "test$res1(r0)")
+}
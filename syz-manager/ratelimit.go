@@ -0,0 +1,133 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const crashRatePeriod = time.Hour
+
+// crashRateLimiter temporarily treats crashes that happen more than limit times per crashRatePeriod
+// as suppressed (see experimental.crash_rate_limit). A VM still needs to be restarted after such crash,
+// but the crash is not saved, reported or reproduced, and doesn't take VMs away from fuzzing.
+type crashRateLimiter struct {
+	limit  int
+	mu     sync.Mutex
+	titles map[string]*crashRate
+}
+
+type crashRate struct {
+	times   []time.Time // crashes during the last crashRatePeriod
+	limited bool
+	// Rate limiting was turned off for the crash via the web UI.
+	disabled bool
+}
+
+func newCrashRateLimiter(limit int) *crashRateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &crashRateLimiter{
+		limit:  limit,
+		titles: make(map[string]*crashRate),
+	}
+}
+
+// noteCrash accounts a crash and returns whether it needs to be treated as suppressed.
+func (rl *crashRateLimiter) noteCrash(title string, now time.Time) bool {
+	if rl == nil {
+		return false
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rate := rl.titles[title]
+	if rate == nil {
+		rate = new(crashRate)
+		rl.titles[title] = rate
+	}
+	rate.times = append(rate.times, now)
+	for len(rate.times) != 0 && now.Sub(rate.times[0]) > crashRatePeriod {
+		rate.times = rate.times[1:]
+	}
+	limited := len(rate.times) > rl.limit
+	if limited != rate.limited {
+		rate.limited = limited
+		if limited {
+			mgrLog.Logf(0, "'%v' happened %v times during the last %v, treating it as suppressed",
+				title, len(rate.times), crashRatePeriod)
+		} else {
+			mgrLog.Logf(0, "'%v' is not rate limited anymore", title)
+		}
+	}
+	return limited && !rate.disabled
+}
+
+// limited returns whether crashes with the title are currently treated as suppressed.
+func (rl *crashRateLimiter) limited(title string) bool {
+	if rl == nil {
+		return false
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rate := rl.titles[title]
+	return rate != nil && rate.limited && !rate.disabled
+}
+
+// disabled returns whether rate limiting was turned off for the title.
+func (rl *crashRateLimiter) disabled(title string) bool {
+	if rl == nil {
+		return false
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rate := rl.titles[title]
+	return rate != nil && rate.disabled
+}
+
+func (rl *crashRateLimiter) setDisabled(title string, disabled bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rate := rl.titles[title]
+	if rate == nil {
+		rate = new(crashRate)
+		rl.titles[title] = rate
+	}
+	rate.disabled = disabled
+}
+
+func (mgr *Manager) fillRateLimit(crash *UICrashType) {
+	if mgr.crashLimiter == nil {
+		return
+	}
+	crash.RateLimiting = true
+	crash.RateLimited = mgr.crashLimiter.limited(crash.Description)
+	crash.RateLimitOff = mgr.crashLimiter.disabled(crash.Description)
+}
+
+func (mgr *Manager) httpRateLimit(w http.ResponseWriter, r *http.Request) {
+	if mgr.crashLimiter == nil {
+		http.Error(w, "crash rate limiting is not enabled", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+		return
+	}
+	title := r.FormValue("title")
+	if title == "" {
+		http.Error(w, "no title", http.StatusBadRequest)
+		return
+	}
+	disabled := r.FormValue("disable") != ""
+	mgr.crashLimiter.setDisabled(title, disabled)
+	state := "on"
+	if disabled {
+		state = "off"
+	}
+	mgrLog.Logf(0, "crash rate limiting for '%v' is turned %v via http", title, state)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrashRateLimiter(t *testing.T) {
+	assert.Nil(t, newCrashRateLimiter(0))
+	var nilLimiter *crashRateLimiter
+	assert.False(t, nilLimiter.noteCrash("foo", time.Now()))
+
+	const title = "WARNING in foo"
+	rl := newCrashRateLimiter(3)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.False(t, rl.noteCrash(title, now))
+		now = now.Add(time.Minute)
+	}
+	assert.True(t, rl.noteCrash(title, now))
+	assert.True(t, rl.limited(title))
+	assert.False(t, rl.limited("another title"))
+	assert.False(t, rl.noteCrash("another title", now))
+
+	// The crash can be excluded from rate limiting.
+	rl.setDisabled(title, true)
+	assert.False(t, rl.noteCrash(title, now))
+	assert.False(t, rl.limited(title))
+	assert.True(t, rl.disabled(title))
+	rl.setDisabled(title, false)
+	assert.True(t, rl.noteCrash(title, now))
+
+	// The limit is lifted once the rate drops.
+	now = now.Add(crashRatePeriod + time.Second)
+	assert.False(t, rl.noteCrash(title, now))
+	assert.False(t, rl.limited(title))
+}
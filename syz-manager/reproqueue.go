@@ -0,0 +1,372 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/html/pages"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	crash_pkg "github.com/google/syzkaller/pkg/report/crash"
+)
+
+// reproQueue holds crashes pending reproduction (one per title) in the order they will be reproduced.
+// The queue is persisted in workdir/repro_queue (the list of entries and the crash logs),
+// so that crashes that still need repro attempts survive manager restarts.
+// Crashes that failed to reproduce are retried with an exponential backoff
+// up to maxReproAttempts times.
+type reproQueue struct {
+	mu      sync.Mutex
+	dir     string
+	entries []*reproEntry
+	now     func() time.Time
+}
+
+type reproEntry struct {
+	Title         string
+	Type          crash_pkg.Type
+	Corrupted     bool
+	Suppressed    bool
+	FromHub       bool
+	FromDashboard bool
+	// Number of failed repro attempts and the time of the next attempt.
+	Attempts    int
+	NextAttempt time.Time
+
+	running   bool
+	cancelled bool
+	crash     *Crash
+}
+
+const reproQueueDir = "repro_queue"
+
+// reproBackoff is the delay before the next attempt after the given number of failed attempts.
+func reproBackoff(attempts int) time.Duration {
+	return time.Hour << (attempts - 1)
+}
+
+// loadReproQueue restores the queue from dir. Entries whose crash logs were lost are dropped.
+func loadReproQueue(dir string) *reproQueue {
+	rq := &reproQueue{
+		dir: dir,
+		now: time.Now,
+	}
+	data, err := os.ReadFile(rq.entriesFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			mgrLog.Logf(0, "failed to read repro queue: %v", err)
+		}
+		return rq
+	}
+	var entries []*reproEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		mgrLog.Logf(0, "failed to parse repro queue: %v", err)
+		return rq
+	}
+	for _, entry := range entries {
+		output, err := os.ReadFile(rq.logFile(entry.Title))
+		if err != nil {
+			mgrLog.Logf(0, "dropping '%v' from repro queue: %v", entry.Title, err)
+			continue
+		}
+		entry.crash = &Crash{
+			fromHub:       entry.FromHub,
+			fromDashboard: entry.FromDashboard,
+			Report: &report.Report{
+				Title:      entry.Title,
+				Type:       entry.Type,
+				Corrupted:  entry.Corrupted,
+				Suppressed: entry.Suppressed,
+				Output:     output,
+			},
+		}
+		rq.entries = append(rq.entries, entry)
+	}
+	if len(rq.entries) != 0 {
+		mgrLog.Logf(0, "restored %v crashes pending reproduction", len(rq.entries))
+	}
+	return rq
+}
+
+func (rq *reproQueue) entriesFile() string {
+	return filepath.Join(rq.dir, "queue.json")
+}
+
+func (rq *reproQueue) logFile(title string) string {
+	return filepath.Join(rq.dir, hash.String([]byte(title))+".log")
+}
+
+// save persists the queue, rq.mu must be held.
+func (rq *reproQueue) save() {
+	entries := []*reproEntry{}
+	for _, entry := range rq.entries {
+		if !entry.cancelled {
+			entries = append(entries, entry)
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		panic(err)
+	}
+	if err := osutil.WriteFile(rq.entriesFile(), data); err != nil {
+		mgrLog.Logf(0, "failed to save repro queue: %v", err)
+	}
+}
+
+func (rq *reproQueue) find(title string) (int, *reproEntry) {
+	for i, entry := range rq.entries {
+		if entry.Title == title {
+			return i, entry
+		}
+	}
+	return -1, nil
+}
+
+func (rq *reproQueue) remove(i int) {
+	os.Remove(rq.logFile(rq.entries[i].Title))
+	rq.entries = append(rq.entries[:i], rq.entries[i+1:]...)
+}
+
+// add appends the crash to the queue, unless there is already a crash with the same title.
+func (rq *reproQueue) add(crash *Crash) bool {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	if _, entry := rq.find(crash.Title); entry != nil {
+		return false
+	}
+	if err := osutil.MkdirAll(rq.dir); err != nil {
+		mgrLog.Logf(0, "failed to create repro queue dir: %v", err)
+	}
+	if err := osutil.WriteFile(rq.logFile(crash.Title), crash.Output); err != nil {
+		mgrLog.Logf(0, "failed to save crash log for repro queue: %v", err)
+	}
+	rq.entries = append(rq.entries, &reproEntry{
+		Title:         crash.Title,
+		Type:          crash.Type,
+		Corrupted:     crash.Corrupted,
+		Suppressed:    crash.Suppressed,
+		FromHub:       crash.fromHub,
+		FromDashboard: crash.fromDashboard,
+		crash:         crash,
+	})
+	rq.save()
+	return true
+}
+
+// contains returns whether the crash with the title is queued or being reproduced.
+func (rq *reproQueue) contains(title string) bool {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	_, entry := rq.find(title)
+	return entry != nil
+}
+
+// next returns the crash that should be reproduced next, or nil if there are none.
+// The hunted crash goes first regardless of the backoff.
+func (rq *reproQueue) next(huntTitle string) *Crash {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	if _, entry := rq.find(huntTitle); entry != nil && !entry.running && huntTitle != "" {
+		return entry.crash
+	}
+	now := rq.now()
+	for _, entry := range rq.entries {
+		if !entry.running && !entry.NextAttempt.After(now) {
+			return entry.crash
+		}
+	}
+	return nil
+}
+
+// start marks the crash returned by next as being reproduced.
+func (rq *reproQueue) start(title string) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	if _, entry := rq.find(title); entry != nil {
+		entry.running = true
+	}
+}
+
+// finish removes the crash from the queue if it was reproduced (or should not be retried),
+// otherwise schedules the next attempt.
+func (rq *reproQueue) finish(title string, reproduced bool) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	i, entry := rq.find(title)
+	if entry == nil {
+		return
+	}
+	entry.running = false
+	entry.Attempts++
+	if reproduced || entry.cancelled || entry.FromHub || entry.FromDashboard ||
+		entry.Attempts >= maxReproAttempts {
+		rq.remove(i)
+	} else {
+		entry.NextAttempt = rq.now().Add(reproBackoff(entry.Attempts))
+		mgrLog.Logf(0, "repro of '%v' failed (attempt %v/%v), next attempt at %v",
+			title, entry.Attempts, maxReproAttempts, entry.NextAttempt.Format(time.DateTime))
+	}
+	rq.save()
+}
+
+// cancel removes the crash from the queue (if it is being reproduced, it's removed once it finishes).
+func (rq *reproQueue) cancel(title string) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	i, entry := rq.find(title)
+	if entry == nil {
+		return
+	}
+	if entry.running {
+		entry.cancelled = true
+	} else {
+		rq.remove(i)
+	}
+	rq.save()
+}
+
+// moveToFront makes the crash the next to reproduce (ignoring the backoff).
+func (rq *reproQueue) moveToFront(title string) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	i, entry := rq.find(title)
+	if entry == nil {
+		return
+	}
+	entry.NextAttempt = time.Time{}
+	copy(rq.entries[1:i+1], rq.entries[:i])
+	rq.entries[0] = entry
+	rq.save()
+}
+
+// active returns the number of crashes that are being reproduced or are ready for reproduction.
+func (rq *reproQueue) active() int {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	now := rq.now()
+	n := 0
+	for _, entry := range rq.entries {
+		if entry.running || !entry.NextAttempt.After(now) {
+			n++
+		}
+	}
+	return n
+}
+
+// running returns titles of the crashes that are being reproduced.
+func (rq *reproQueue) running() map[string]bool {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	res := make(map[string]bool)
+	for _, entry := range rq.entries {
+		if entry.running {
+			res[entry.Title] = true
+		}
+	}
+	return res
+}
+
+type UIReproQueueEntry struct {
+	Title       string
+	Status      string
+	Attempts    int
+	NextAttempt time.Time
+}
+
+func (rq *reproQueue) list() []UIReproQueueEntry {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	now := rq.now()
+	var res []UIReproQueueEntry
+	for _, entry := range rq.entries {
+		status := "queued"
+		switch {
+		case entry.running && entry.cancelled:
+			status = "cancelled"
+		case entry.running:
+			status = "reproducing"
+		case entry.NextAttempt.After(now):
+			status = "backoff"
+		}
+		res = append(res, UIReproQueueEntry{
+			Title:       entry.Title,
+			Status:      status,
+			Attempts:    entry.Attempts,
+			NextAttempt: entry.NextAttempt,
+		})
+	}
+	return res
+}
+
+func (mgr *Manager) httpReproQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		title := r.FormValue("title")
+		switch action := r.FormValue("action"); action {
+		case "first":
+			mgr.reproQueue.moveToFront(title)
+		case "cancel":
+			mgr.reproQueue.cancel(title)
+		default:
+			http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusBadRequest)
+			return
+		}
+		http.Redirect(w, r, "/repro_queue", http.StatusFound)
+		return
+	}
+	data := &UIReproQueueData{
+		Name:    mgr.cfg.Name,
+		Entries: mgr.reproQueue.list(),
+	}
+	executeTemplate(w, reproQueueTemplate, data)
+}
+
+type UIReproQueueData struct {
+	Name    string
+	Entries []UIReproQueueEntry
+}
+
+var reproQueueTemplate = pages.Create(`
+<!doctype html>
+<html>
+<head>
+	<title>{{.Name}} syzkaller</title>
+	{{HEAD}}
+</head>
+<body>
+
+<table class="list_table">
+	<caption>Repro queue ({{len $.Entries}}):</caption>
+	<tr>
+		<th>Title</th>
+		<th>Status</th>
+		<th>Failed attempts</th>
+		<th>Next attempt</th>
+		<th></th>
+	</tr>
+	{{range $e := $.Entries}}
+	<tr>
+		<td class="title">{{$e.Title}}</td>
+		<td>{{$e.Status}}</td>
+		<td>{{$e.Attempts}}</td>
+		<td class="time">{{if eq $e.Status "backoff"}}{{formatTime $e.NextAttempt}}{{end}}</td>
+		<td>
+			<form method="post" action="/repro_queue" style="display:inline">
+				<input type="hidden" name="title" value="{{$e.Title}}">
+				<button type="submit" name="action" value="first">reproduce first</button>
+				<button type="submit" name="action" value="cancel">cancel</button>
+			</form>
+		</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
@@ -0,0 +1,74 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReproQueue(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	load := func() *reproQueue {
+		rq := loadReproQueue(dir)
+		rq.now = func() time.Time { return now }
+		return rq
+	}
+	newCrash := func(title string) *Crash {
+		return &Crash{Report: &report.Report{Title: title, Output: []byte(title + " log")}}
+	}
+	rq := load()
+	assert.Nil(t, rq.next(""))
+	assert.True(t, rq.add(newCrash("first")))
+	assert.True(t, rq.add(newCrash("second")))
+	assert.True(t, rq.add(newCrash("third")))
+	assert.False(t, rq.add(newCrash("second")))
+	assert.Equal(t, "first", rq.next("").Title)
+	// The hunted crash goes first.
+	assert.Equal(t, "third", rq.next("third").Title)
+
+	rq.start("first")
+	assert.Equal(t, map[string]bool{"first": true}, rq.running())
+	assert.Equal(t, "second", rq.next("").Title)
+	rq.finish("first", false)
+	assert.Equal(t, 2, rq.active())
+	// The failed crash is retried after the backoff.
+	assert.Equal(t, "second", rq.next("").Title)
+	rq.start("second")
+	rq.finish("second", true)
+	assert.False(t, rq.contains("second"))
+
+	rq.cancel("third")
+	assert.Nil(t, rq.next(""))
+	now = now.Add(reproBackoff(1))
+	assert.Equal(t, "first", rq.next("").Title)
+	now = now.Add(-time.Minute)
+
+	// The queue survives restarts.
+	rq = load()
+	entries := rq.list()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "first", entries[0].Title)
+	assert.Equal(t, "backoff", entries[0].Status)
+	assert.Equal(t, 1, entries[0].Attempts)
+	assert.Equal(t, []byte("first log"), rq.next("first").Output)
+
+	assert.True(t, rq.add(newCrash("fourth")))
+	assert.Equal(t, "fourth", rq.next("").Title)
+	rq.moveToFront("first")
+	assert.Equal(t, "first", rq.next("").Title)
+
+	// The crash is dropped after maxReproAttempts failed attempts.
+	for i := 1; i < maxReproAttempts; i++ {
+		rq.start("first")
+		rq.finish("first", false)
+		rq.moveToFront("first")
+	}
+	assert.False(t, rq.contains("first"))
+	assert.Len(t, load().list(), 1)
+}
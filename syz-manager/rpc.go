@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -29,6 +30,9 @@ import (
 	"github.com/google/syzkaller/prog"
 )
 
+// rpcLog is the logger of the RPC server, its verbosity can be set separately (e.g. with -vmodule=rpcserver=2).
+var rpcLog = log.Module("rpcserver")
+
 type RPCServer struct {
 	mgr     RPCManagerView
 	cfg     *mgrconfig.Config
@@ -36,22 +40,36 @@ type RPCServer struct {
 	checker *vminfo.Checker
 	port    int
 
-	infoOnce         sync.Once
-	checkDone        atomic.Bool
-	checkFailures    int
-	baseSource       *queue.DynamicSourceCtl
-	enabledFeatures  flatrpc.Feature
-	setupFeatures    flatrpc.Feature
+	infoOnce        sync.Once
+	checkDone       atomic.Bool
+	kernelVersion   string
+	checkFailures   int
+	baseSource      *queue.DynamicSourceCtl
+	enabledFeatures flatrpc.Feature
+	setupFeatures   flatrpc.Feature
+	execCoverFilter map[uint64]uint32
+	coverFilter     map[uint64]uint32
+
+	// Modules may be loaded in VMs at runtime, so both the module list
+	// and the canonicalizer grow over time.
+	modulesMu        sync.Mutex
 	modules          []cover.KernelModule
 	canonicalModules *cover.Canonicalizer
-	execCoverFilter  map[uint64]uint32
-	coverFilter      map[uint64]uint32
 
 	mu         sync.Mutex
 	runners    map[string]*Runner
 	execSource queue.Source
 	checkLeaks bool
 
+	// With experimental.vm_scheduling important requests are executed on the fast VMs,
+	// slow VMs park them in fastQueue (see nextRequest).
+	triageOnFast   bool
+	fastQueue      *queue.PlainQueue
+	numFastFuzzing atomic.Int64
+	// Requests pinned to VMs (queue.Request.PinnedVM) taken by other VMs are parked here
+	// until the target VM asks for a request. Indexed by VM index, protected by mu.
+	pinnedQueues map[int]*queue.PlainQueue
+
 	statNumFuzzing         *stats.Val
 	statExecs              *stats.Val
 	statExecRetries        *stats.Val
@@ -61,6 +79,7 @@ type RPCServer struct {
 	statNoExecRequests     *stats.Val
 	statNoExecDuration     *stats.Val
 	statCoverFiltered      *stats.Val
+	statModulesLoaded      *stats.Val
 }
 
 type Runner struct {
@@ -69,12 +88,14 @@ type Runner struct {
 	injectExec    chan<- bool
 	conn          *flatrpc.Conn
 	machineInfo   []byte
-	canonicalizer *cover.CanonicalizerInstance
+	canonicalizer atomic.Pointer[cover.CanonicalizerInstance]
 	nextRequestID int64
 	requests      map[int64]*queue.Request
 	executing     map[int64]bool
 	lastExec      *LastExecuting
 	rnd           *rand.Rand
+	slow          bool
+	index         int
 }
 
 type BugFrames struct {
@@ -118,6 +139,14 @@ func startRPCServer(mgr *Manager) (*RPCServer, error) {
 		statNoExecDuration: stats.Create("no exec duration",
 			"Total duration fuzzer was stalled with no exec requests (ns/sec)", stats.Rate{}),
 		statCoverFiltered: stats.Create("filtered coverage", "", stats.NoGraph),
+		statModulesLoaded: stats.Create("late modules",
+			"Number of kernel modules first seen after the machine check", stats.NoGraph),
+	}
+	if mgr.vmSched.triageOnFast() {
+		serv.triageOnFast = true
+		serv.fastQueue = queue.PlainWithStat(stats.Create("fast queue",
+			"Number of important requests waiting for fast VMs (see experimental.vm_scheduling)",
+			stats.NoGraph))
 	}
 	s, err := flatrpc.ListenAndServe(mgr.cfg.RPC, serv.handleConn)
 	if err != nil {
@@ -125,7 +154,7 @@ func startRPCServer(mgr *Manager) (*RPCServer, error) {
 	}
 	baseSource.Store(serv.checker)
 
-	log.Logf(0, "serving rpc on tcp://%v", s.Addr)
+	rpcLog.Logf(0, "serving rpc on tcp://%v", s.Addr)
 	serv.port = s.Addr.Port
 	return serv, nil
 }
@@ -133,13 +162,13 @@ func startRPCServer(mgr *Manager) (*RPCServer, error) {
 func (serv *RPCServer) handleConn(conn *flatrpc.Conn) {
 	name, machineInfo, canonicalizer, err := serv.handshake(conn)
 	if err != nil {
-		log.Logf(1, "%v", err)
+		rpcLog.Logf(1, "%v", err)
 		return
 	}
 
 	if serv.cfg.VMLess {
 		// There is no VM loop, so minic what it would do.
-		serv.createInstance(name, nil)
+		serv.createInstance(name, 0, nil, false)
 		defer func() {
 			serv.stopFuzzing(name)
 			serv.shutdownInstance(name, false)
@@ -150,25 +179,25 @@ func (serv *RPCServer) handleConn(conn *flatrpc.Conn) {
 	runner := serv.runners[name]
 	if runner == nil || runner.stopped {
 		serv.mu.Unlock()
-		log.Logf(2, "VM %v shut down before connect", name)
+		rpcLog.Logf(2, "VM %v shut down before connect", name)
 		return
 	}
 	runner.conn = conn
 	runner.machineInfo = machineInfo
-	runner.canonicalizer = canonicalizer
+	runner.canonicalizer.Store(canonicalizer)
 	checkLeaks := serv.checkLeaks
 	serv.mu.Unlock()
 	defer close(runner.finished)
 
 	if checkLeaks {
 		if err := runner.sendStartLeakChecks(); err != nil {
-			log.Logf(2, "%v", err)
+			rpcLog.Logf(2, "%v", err)
 			return
 		}
 	}
 
 	err = serv.connectionLoop(runner)
-	log.Logf(2, "runner %v: %v", name, err)
+	rpcLog.Logf(2, "runner %v: %v", name, err)
 }
 
 func (serv *RPCServer) handshake(conn *flatrpc.Conn) (string, []byte, *cover.CanonicalizerInstance, error) {
@@ -177,7 +206,15 @@ func (serv *RPCServer) handshake(conn *flatrpc.Conn) (string, []byte, *cover.Can
 		return "", nil, nil, err
 	}
 	connectReq := connectReqRaw.UnPack()
-	log.Logf(1, "fuzzer %v connected", connectReq.Name)
+	rpcLog.Logf(1, "fuzzer %v connected", connectReq.Name)
+	if err := flatrpc.CheckProtocolVersion("fuzzer", connectReq.ProtocolVersion); err != nil {
+		if !serv.cfg.VMLess {
+			log.Fatalf("%v: %v", connectReq.Name, err)
+		}
+		// In the VM-less mode fuzzers connect on their own, so only reject the incompatible one.
+		rpcLog.Logf(0, "rejecting %v: %v", connectReq.Name, err)
+		return "", nil, nil, err
+	}
 	if !serv.cfg.VMLess {
 		checkRevisions(connectReq, serv.cfg.Target)
 	}
@@ -196,6 +233,13 @@ func (serv *RPCServer) handshake(conn *flatrpc.Conn) (string, []byte, *cover.Can
 		connectReply.Globs = serv.target.RequiredGlobs()
 		connectReply.Features = flatrpc.AllFeatures
 	}
+	connectReply.ProtocolVersion = flatrpc.ProtocolVersion
+	// Don't ask the fuzzer to set up features it does not know about (it may be built
+	// from an older revision), instead report them as unsupported.
+	unsupported := connectReply.Features & flatrpc.SupportedFeatures &^ connectReq.Features
+	connectReply.Features &= connectReq.Features
+	configDisabled := serv.configDisabledFeatures()
+	connectReply.Features &^= configDisabled
 	if err := flatrpc.Send(conn, connectReply); err != nil {
 		return "", nil, nil, err
 	}
@@ -205,25 +249,46 @@ func (serv *RPCServer) handshake(conn *flatrpc.Conn) (string, []byte, *cover.Can
 		return "", nil, nil, err
 	}
 	infoReq := infoReqRaw.UnPack()
+	for feat := range flatrpc.EnumNamesFeature {
+		if unsupported&feat != 0 {
+			rpcLog.Logf(0, "fuzzer %v does not support feature %v", connectReq.Name, feat)
+			infoReq.Features = append(infoReq.Features, &flatrpc.FeatureInfo{
+				Id:        feat,
+				NeedSetup: true,
+				Reason:    "not supported by the fuzzer",
+			})
+		} else if configDisabled&feat != 0 {
+			infoReq.Features = append(infoReq.Features, &flatrpc.FeatureInfo{
+				Id:        feat,
+				NeedSetup: true,
+				Reason:    "not enabled in the config",
+			})
+		}
+	}
 	modules, machineInfo, err := serv.checker.MachineInfo(infoReq.Files)
 	if err != nil {
-		log.Logf(0, "parsing of machine info failed: %v", err)
+		rpcLog.Logf(0, "parsing of machine info failed: %v", err)
 		if infoReq.Error == "" {
 			infoReq.Error = err.Error()
 		}
 	}
 	if infoReq.Error != "" {
-		log.Logf(0, "machine check failed: %v", infoReq.Error)
+		rpcLog.Logf(0, "machine check failed: %v", infoReq.Error)
 		serv.checkFailures++
 		if serv.checkFailures == 10 {
-			log.Fatalf("machine check failing")
+			log.Fatalf("machine check failing on every VM, last error: %v\n"+
+				"the VM boots, but the fuzzer can't fetch the machine info;"+
+				" check that the image matches the kernel and the executor binary"+
+				" is built for the target", infoReq.Error)
 		}
 		return "", nil, nil, errors.New("machine check failed")
 	}
 
 	serv.infoOnce.Do(func() {
+		serv.modulesMu.Lock()
 		serv.modules = modules
 		serv.canonicalModules = cover.NewCanonicalizer(modules, serv.cfg.Cover)
+		serv.modulesMu.Unlock()
 		var err error
 		serv.execCoverFilter, serv.coverFilter, err = createCoverageFilter(serv.cfg, modules)
 		if err != nil {
@@ -247,7 +312,7 @@ func (serv *RPCServer) handshake(conn *flatrpc.Conn) (string, []byte, *cover.Can
 		}()
 	})
 
-	canonicalizer := serv.canonicalModules.NewInstance(modules)
+	canonicalizer := serv.addModules(modules).NewInstance(modules)
 	instCoverFilter := canonicalizer.DecanonicalizeFilter(serv.execCoverFilter)
 	infoReply := &flatrpc.InfoReply{
 		CoverFilter: createCoverageBitmap(serv.cfg, instCoverFilter),
@@ -275,9 +340,13 @@ func (serv *RPCServer) connectionLoop(runner *Runner) error {
 
 	serv.statNumFuzzing.Add(1)
 	defer serv.statNumFuzzing.Add(-1)
+	if !runner.slow {
+		serv.numFastFuzzing.Add(1)
+		defer serv.numFastFuzzing.Add(-1)
+	}
 	for {
 		for len(runner.requests)-len(runner.executing) < 2*serv.cfg.Procs {
-			req := serv.execSource.Next()
+			req := serv.nextRequest(runner)
 			if req == nil {
 				break
 			}
@@ -300,6 +369,8 @@ func (serv *RPCServer) connectionLoop(runner *Runner) error {
 			err = serv.handleExecutingMessage(runner, msg)
 		case *flatrpc.ExecResult:
 			err = serv.handleExecResult(runner, msg)
+		case *flatrpc.ModulesUpdate:
+			err = serv.handleModulesUpdate(runner, msg)
 		default:
 			panic(fmt.Sprintf("unknown message %T", msg))
 		}
@@ -340,7 +411,7 @@ func (serv *RPCServer) sendRequest(runner *Runner, req *queue.Request) error {
 	if serv.cfg.Experimental.ResetAccState || req.ExecOpts.ExecFlags&resetFlags != 0 && runner.rnd.Intn(restartIn) == 0 {
 		flags |= flatrpc.RequestFlagResetState
 	}
-	signalFilter := runner.canonicalizer.Decanonicalize(req.SignalFilter.ToRaw())
+	signalFilter := runner.canonicalizer.Load().Decanonicalize(req.SignalFilter.ToRaw())
 	msg := &flatrpc.HostMessage{
 		Msg: &flatrpc.HostMessages{
 			Type: flatrpc.HostMessagesRawExecRequest,
@@ -378,7 +449,7 @@ func (serv *RPCServer) handleExecutingMessage(runner *Runner, msg *flatrpc.Execu
 	} else {
 		serv.statExecRetries.Add(1)
 	}
-	runner.lastExec.Note(proc, req.Prog.Serialize(), osutil.MonotonicNano())
+	runner.lastExec.Note(proc, req.Prog.Serialize(), req.Prog, osutil.MonotonicNano())
 	select {
 	case runner.injectExec <- true:
 	default:
@@ -402,14 +473,15 @@ func (serv *RPCServer) handleExecResult(runner *Runner, msg *flatrpc.ExecResult)
 			// Coverage collection is disabled, but signal was requested => use a substitute signal.
 			addFallbackSignal(req.Prog, msg.Info)
 		}
+		canonicalizer := runner.canonicalizer.Load()
 		for i := 0; i < len(msg.Info.Calls); i++ {
 			call := msg.Info.Calls[i]
-			call.Cover = runner.canonicalizer.Canonicalize(call.Cover)
-			call.Signal = runner.canonicalizer.Canonicalize(call.Signal)
+			call.Cover = canonicalizer.Canonicalize(call.Cover)
+			call.Signal = canonicalizer.Canonicalize(call.Signal)
 		}
 		if msg.Info.Extra != nil {
-			msg.Info.Extra.Cover = runner.canonicalizer.Canonicalize(msg.Info.Extra.Cover)
-			msg.Info.Extra.Signal = runner.canonicalizer.Canonicalize(msg.Info.Extra.Signal)
+			msg.Info.Extra.Cover = canonicalizer.Canonicalize(msg.Info.Extra.Cover)
+			msg.Info.Extra.Signal = canonicalizer.Canonicalize(msg.Info.Extra.Signal)
 		}
 	}
 	status := queue.Success
@@ -427,12 +499,63 @@ func (serv *RPCServer) handleExecResult(runner *Runner, msg *flatrpc.ExecResult)
 	return nil
 }
 
+func (serv *RPCServer) handleModulesUpdate(runner *Runner, msg *flatrpc.ModulesUpdate) error {
+	modules, err := serv.checker.Modules(msg.Files)
+	if err != nil {
+		// The runner keeps using the old module map, coverage from the new modules will be discarded.
+		rpcLog.Logf(0, "failed to parse updated modules: %v", err)
+		return nil
+	}
+	runner.canonicalizer.Store(serv.addModules(modules).NewInstance(modules))
+	return nil
+}
+
+// addModules adds modules that are not yet known to the canonical module map
+// and returns the up-to-date canonicalizer.
+// Modules that are already known keep their canonical addresses.
+func (serv *RPCServer) addModules(modules []cover.KernelModule) *cover.Canonicalizer {
+	serv.modulesMu.Lock()
+	defer serv.modulesMu.Unlock()
+	known := make(map[string]bool)
+	for _, mod := range serv.modules {
+		known[mod.Name] = true
+	}
+	var added []cover.KernelModule
+	for _, mod := range modules {
+		if !known[mod.Name] {
+			added = append(added, mod)
+		}
+	}
+	if len(added) == 0 {
+		return serv.canonicalModules
+	}
+	for _, mod := range added {
+		rpcLog.Logf(0, "new kernel module %v at 0x%x", mod.Name, mod.Addr)
+	}
+	serv.statModulesLoaded.Add(len(added))
+	// Don't append in place, the old slice may still be used by readers.
+	serv.modules = append(slices.Clone(serv.modules), added...)
+	serv.canonicalModules = cover.NewCanonicalizer(serv.modules, serv.cfg.Cover)
+	// The cached report generator does not know about the new modules,
+	// their debug info will be loaded when the next coverage report is requested.
+	resetReportGenerator()
+	return serv.canonicalModules
+}
+
+// kernelModules returns the current module list and canonicalizer.
+func (serv *RPCServer) kernelModules() ([]cover.KernelModule, *cover.Canonicalizer) {
+	serv.modulesMu.Lock()
+	defer serv.modulesMu.Unlock()
+	return serv.modules, serv.canonicalModules
+}
+
 func checkRevisions(a *flatrpc.ConnectRequest, target *prog.Target) {
 	if target.Arch != a.Arch {
 		log.Fatalf("mismatching target/executor arches: %v vs %v", target.Arch, a.Arch)
 	}
 	if prog.GitRevision != a.GitRevision {
-		log.Fatalf("mismatching manager/fuzzer git revisions: %v vs %v",
+		// The protocol version was already checked, so the fuzzer is compatible.
+		rpcLog.Logf(0, "mismatching manager/fuzzer git revisions: %v vs %v",
 			prog.GitRevision, a.GitRevision)
 	}
 	if target.Revision != a.SyzRevision {
@@ -441,13 +564,26 @@ func checkRevisions(a *flatrpc.ConnectRequest, target *prog.Target) {
 	}
 }
 
+// configDisabledFeatures returns features that are set up only if they are explicitly enabled in the config.
+func (serv *RPCServer) configDisabledFeatures() flatrpc.Feature {
+	var features flatrpc.Feature
+	if !serv.cfg.Experimental.Hugepages {
+		features |= flatrpc.FeatureHugepages
+	}
+	if !serv.cfg.Experimental.SuspendResume {
+		features |= flatrpc.FeatureSuspend
+	}
+	return features
+}
+
 func (serv *RPCServer) runCheck(checkFilesInfo []*flatrpc.FileInfo, checkFeatureInfo []*flatrpc.FeatureInfo) error {
 	enabledCalls, disabledCalls, features, checkErr := serv.checker.Run(checkFilesInfo, checkFeatureInfo)
 	enabledCalls, transitivelyDisabled := serv.target.TransitivelyEnabledCalls(enabledCalls)
+	features.DisableUnused(enabledCalls)
 	// Note: need to print disbled syscalls before failing due to an error.
 	// This helps to debug "all system calls are disabled".
 	buf := new(bytes.Buffer)
-	if len(serv.cfg.EnabledSyscalls) != 0 || log.V(1) {
+	if len(serv.cfg.EnabledSyscalls) != 0 || serv.cfg.Experimental.ProbeSyscalls || rpcLog.V(1) {
 		if len(disabledCalls) != 0 {
 			var lines []string
 			for call, reason := range disabledCalls {
@@ -491,7 +627,23 @@ func (serv *RPCServer) runCheck(checkFilesInfo []*flatrpc.FileInfo, checkFeature
 	sort.Strings(lines)
 	buf.WriteString(strings.Join(lines, ""))
 	fmt.Fprintf(buf, "\n")
-	log.Logf(0, "machine check:\n%s", buf.Bytes())
+	rpcLog.Logf(0, "machine check:\n%s", buf.Bytes())
+	diag := machineDiagnostics(&diagInput{
+		cfg:      serv.cfg,
+		files:    checkFilesInfo,
+		features: features,
+		enabled:  len(enabledCalls),
+		now:      time.Now(),
+	})
+	report := formatDiagnostics(diag)
+	rpcLog.Logf(0, "machine diagnostics:\n%s", report)
+	reportFile := filepath.Join(serv.cfg.Workdir, diagnosticsFile)
+	if err := osutil.WriteFile(reportFile, report); err != nil {
+		rpcLog.Logf(0, "failed to write %v: %v", reportFile, err)
+	}
+	if err := diagnosticsError(diag); err != nil {
+		return fmt.Errorf("%w\nsee %v for details", err, reportFile)
+	}
 	if checkErr != nil {
 		return checkErr
 	}
@@ -500,6 +652,7 @@ func (serv *RPCServer) runCheck(checkFilesInfo []*flatrpc.FileInfo, checkFeature
 	}
 	serv.enabledFeatures = features.Enabled()
 	serv.setupFeatures = features.NeedSetup()
+	serv.kernelVersion = vminfo.KernelVersion(checkFilesInfo)
 	newSource := serv.mgr.machineChecked(serv.enabledFeatures, enabledCalls, serv.execOpts())
 	serv.baseSource.Store(newSource)
 	serv.checkDone.Store(true)
@@ -518,8 +671,79 @@ func validateRequest(req *queue.Request) error {
 	return nil
 }
 
-func (serv *RPCServer) createInstance(name string, injectExec chan<- bool) {
+const (
+	// Max number of important requests parked for the fast VMs.
+	maxFastQueue = 1000
+	// Max number of requests a runner parks (for the fast VMs, or pinned to other VMs)
+	// before it gets a request to execute.
+	maxParkedPerRequest = 10
+)
+
+// nextRequest returns the next request to execute on the runner.
+// Requests pinned to other VMs are parked in their pinned queues,
+// and runners take requests from their pinned queue first.
+func (serv *RPCServer) nextRequest(runner *Runner) *queue.Request {
+	if req := serv.pinnedQueue(runner.index).Next(); req != nil {
+		return req
+	}
+	for i := 0; i < maxParkedPerRequest; i++ {
+		req := serv.nextUnpinnedRequest(runner)
+		if req == nil || req.PinnedVM == 0 || req.PinnedVM-1 == runner.index {
+			return req
+		}
+		serv.pinnedQueue(req.PinnedVM - 1).Submit(req)
+	}
+	return nil
+}
+
+func (serv *RPCServer) pinnedQueue(index int) *queue.PlainQueue {
+	serv.mu.Lock()
+	defer serv.mu.Unlock()
+	if serv.pinnedQueues == nil {
+		serv.pinnedQueues = make(map[int]*queue.PlainQueue)
+	}
+	q := serv.pinnedQueues[index]
+	if q == nil {
+		q = queue.Plain()
+		serv.pinnedQueues[index] = q
+	}
+	return q
+}
+
+// nextUnpinnedRequest returns the next request from the exec source.
+// If important requests (triage, candidates) are executed on the fast VMs, slow runners
+// park them in fastQueue, and fast runners take requests from fastQueue first.
+// When no fast runners are fuzzing, slow runners execute the parked requests themselves.
+func (serv *RPCServer) nextUnpinnedRequest(runner *Runner) *queue.Request {
+	if !serv.triageOnFast {
+		return serv.execSource.Next()
+	}
+	if !runner.slow {
+		if req := serv.fastQueue.Next(); req != nil {
+			return req
+		}
+		return serv.execSource.Next()
+	}
+	for i := 0; i < maxParkedPerRequest; i++ {
+		noFast := serv.numFastFuzzing.Load() == 0
+		if noFast {
+			if req := serv.fastQueue.Next(); req != nil {
+				return req
+			}
+		}
+		req := serv.execSource.Next()
+		if req == nil || !req.Important || noFast || serv.fastQueue.Len() >= maxFastQueue {
+			return req
+		}
+		serv.fastQueue.Submit(req)
+	}
+	return nil
+}
+
+func (serv *RPCServer) createInstance(name string, index int, injectExec chan<- bool, slow bool) {
 	runner := &Runner{
+		slow:       slow,
+		index:      index,
 		injectExec: injectExec,
 		finished:   make(chan bool),
 		requests:   make(map[int64]*queue.Request),
@@ -568,6 +792,17 @@ func (serv *RPCServer) shutdownInstance(name string, crashed bool) ([]ExecRecord
 	return runner.lastExec.Collect(), runner.machineInfo
 }
 
+// lastExecuting returns the programs that started executing last on each proc of the instance.
+func (serv *RPCServer) lastExecuting(name string) []*prog.Prog {
+	serv.mu.Lock()
+	runner := serv.runners[name]
+	serv.mu.Unlock()
+	if runner == nil {
+		return nil
+	}
+	return runner.lastExec.Latest()
+}
+
 func (serv *RPCServer) distributeSignalDelta(plus, minus signal.Signal) {
 	plusRaw := plus.ToRaw()
 	minusRaw := minus.ToRaw()
@@ -577,12 +812,13 @@ func (serv *RPCServer) distributeSignalDelta(plus, minus signal.Signal) {
 }
 
 func (runner *Runner) sendSignalUpdate(plus, minus []uint64) error {
+	canonicalizer := runner.canonicalizer.Load()
 	msg := &flatrpc.HostMessage{
 		Msg: &flatrpc.HostMessages{
 			Type: flatrpc.HostMessagesRawSignalUpdate,
 			Value: &flatrpc.SignalUpdate{
-				NewMax:  runner.canonicalizer.Decanonicalize(plus),
-				DropMax: runner.canonicalizer.Decanonicalize(minus),
+				NewMax:  canonicalizer.Decanonicalize(plus),
+				DropMax: canonicalizer.Decanonicalize(minus),
 			},
 		},
 	}
@@ -653,10 +889,16 @@ func (serv *RPCServer) execOpts() flatrpc.ExecOpts {
 	if serv.cfg.HasCovFilter() {
 		exec |= flatrpc.ExecFlagCoverFilter
 	}
+	if serv.cfg.Procs == 1 {
+		// CLOCK_REALTIME time jumps are VM-wide and would affect calls of other procs.
+		exec |= flatrpc.ExecFlagRealtimeJumps
+	}
 	return flatrpc.ExecOpts{
-		EnvFlags:   env,
-		ExecFlags:  exec,
-		SandboxArg: serv.cfg.SandboxArg,
+		EnvFlags:        env,
+		ExecFlags:       exec,
+		SandboxArg:      serv.cfg.SandboxArg,
+		ProcMemoryLimit: serv.cfg.ProcMemoryLimit,
+		ProcPidsLimit:   serv.cfg.ProcPidsLimit,
 	}
 }
 
@@ -0,0 +1,28 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRequestPinned(t *testing.T) {
+	source := queue.Plain()
+	serv := &RPCServer{execSource: source}
+	vm0 := &Runner{index: 0}
+	vm1 := &Runner{index: 1}
+	pinned := &queue.Request{PinnedVM: 2}
+	free := &queue.Request{}
+	source.Submit(pinned)
+	source.Submit(free)
+
+	// The request pinned to vm1 is parked, vm0 gets the next one.
+	assert.Equal(t, free, serv.nextRequest(vm0))
+	assert.Nil(t, serv.nextRequest(vm0))
+	assert.Equal(t, pinned, serv.nextRequest(vm1))
+	assert.Nil(t, serv.nextRequest(vm1))
+}
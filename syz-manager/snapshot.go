@@ -0,0 +1,275 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/stats"
+	"github.com/google/syzkaller/prog"
+)
+
+// Fuzzer state snapshot is a tar.gz archive with the following files:
+//   - manifest.json: snapshotManifest
+//   - corpus.db: the corpus database
+//   - cover.txt: corpus coverage PCs, one hex value per line
+//   - maxsignal.txt: max signal observed by the fuzzer, one hex value per line
+//   - stats.json: snapshotStats
+//   - choice_table.json: snapshotChoiceTable
+//
+// Everything except for the manifest is optional. Corpus and max signal are used
+// when resuming from a snapshot, the rest is intended for offline analysis.
+const (
+	snapshotVersion      = 1
+	snapshotManifestFile = "manifest.json"
+	snapshotCorpusFile   = "corpus.db"
+	snapshotCoverFile    = "cover.txt"
+	snapshotSignalFile   = "maxsignal.txt"
+	snapshotStatsFile    = "stats.json"
+	snapshotChoiceFile   = "choice_table.json"
+)
+
+type snapshotManifest struct {
+	Version   int       `json:"version"`
+	Revision  string    `json:"revision"`
+	Name      string    `json:"name"`
+	Target    string    `json:"target"`
+	Time      time.Time `json:"time"`
+	Corpus    int       `json:"corpus"`
+	Cover     int       `json:"cover"`
+	MaxSignal int       `json:"max_signal"`
+}
+
+type snapshotStats struct {
+	Values  map[string]int      `json:"values"`
+	History []stats.HistoryLine `json:"history"`
+}
+
+// snapshotChoiceTable contains static+dynamic call priorities for the enabled syscalls.
+// Prios[i][j] is the priority of generating Calls[j] given Calls[i] is already present in the program.
+type snapshotChoiceTable struct {
+	Calls []string  `json:"calls"`
+	Prios [][]int32 `json:"prios"`
+}
+
+func (mgr *Manager) httpSnapshot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		fmt.Sprintf("%v-%v.tar.gz", mgr.cfg.Name, time.Now().Format("2006-01-02-15-04-05"))))
+	if err := mgr.writeSnapshot(w); err != nil {
+		// Headers are already sent, so the best we can do is to produce a broken archive.
+		mgrLog.Errorf("failed to write snapshot: %v", err)
+	}
+}
+
+func (mgr *Manager) writeSnapshot(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	add := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "\t")
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	items := mgr.corpus.Items()
+	var corpusCover cover.Cover
+	var progs []*prog.Prog
+	for _, item := range items {
+		corpusCover.Merge(item.Cover)
+		progs = append(progs, item.Prog)
+	}
+	var maxSignal []uint64
+	if fuzzerObj := mgr.fuzzer.Load(); fuzzerObj != nil {
+		maxSignal = fuzzerObj.Cover.CopyMaxSignal().ToRaw()
+	}
+	manifest := &snapshotManifest{
+		Version:   snapshotVersion,
+		Revision:  prog.GitRevision,
+		Name:      mgr.cfg.Name,
+		Target:    mgr.cfg.Target.OS + "/" + mgr.cfg.Target.Arch,
+		Time:      now,
+		Corpus:    len(items),
+		Cover:     len(corpusCover),
+		MaxSignal: len(maxSignal),
+	}
+	if err := addJSON(snapshotManifestFile, manifest); err != nil {
+		return err
+	}
+
+	mgr.corpusDBMu.Lock()
+	corpusDB, err := os.ReadFile(filepath.Join(mgr.cfg.Workdir, "corpus.db"))
+	mgr.corpusDBMu.Unlock()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := add(snapshotCorpusFile, corpusDB); err != nil {
+			return err
+		}
+	}
+	if err := add(snapshotCoverFile, serializePCs(corpusCover.Serialize())); err != nil {
+		return err
+	}
+	if err := add(snapshotSignalFile, serializePCs(maxSignal)); err != nil {
+		return err
+	}
+
+	st := &snapshotStats{
+		Values:  make(map[string]int),
+		History: stats.History(),
+	}
+	for _, stat := range stats.Collect(stats.All) {
+		st.Values[stat.Name] = stat.V
+	}
+	if err := addJSON(snapshotStatsFile, st); err != nil {
+		return err
+	}
+
+	mgr.mu.Lock()
+	var enabled []*prog.Syscall
+	for call := range mgr.targetEnabledSyscalls {
+		enabled = append(enabled, call)
+	}
+	mgr.mu.Unlock()
+	sort.Slice(enabled, func(i, j int) bool {
+		return enabled[i].ID < enabled[j].ID
+	})
+	if len(enabled) != 0 {
+		prios := mgr.target.CalculatePriorities(progs)
+		ct := &snapshotChoiceTable{}
+		for _, call := range enabled {
+			ct.Calls = append(ct.Calls, call.Name)
+			row := make([]int32, len(enabled))
+			for j, call1 := range enabled {
+				row[j] = prios[call.ID][call1.ID]
+			}
+			ct.Prios = append(ct.Prios, row)
+		}
+		if err := addJSON(snapshotChoiceFile, ct); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func serializePCs(pcs []uint64) []byte {
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	var buf strings.Builder
+	for _, pc := range pcs {
+		fmt.Fprintf(&buf, "0x%x\n", pc)
+	}
+	return []byte(buf.String())
+}
+
+func parsePCs(data []byte) ([]uint64, error) {
+	var pcs []uint64
+	s := bufio.NewScanner(strings.NewReader(string(data)))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		pc, err := strconv.ParseUint(line, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		pcs = append(pcs, pc)
+	}
+	return pcs, s.Err()
+}
+
+// restoreSnapshot prepares workdir for resuming from the snapshot archive:
+// the corpus database is replaced with the one from the snapshot (the old one is kept as corpus.db.old).
+// If workdir is empty, the corpus is not restored.
+// Returns max signal saved in the snapshot.
+func restoreSnapshot(file, workdir, target string) ([]uint64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		switch hdr.Name {
+		case snapshotManifestFile, snapshotCorpusFile, snapshotSignalFile:
+			if files[hdr.Name], err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("failed to read snapshot: %w", err)
+			}
+		}
+	}
+	manifest := new(snapshotManifest)
+	if data := files[snapshotManifestFile]; data == nil {
+		return nil, fmt.Errorf("snapshot does not contain %v", snapshotManifestFile)
+	} else if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("bad snapshot manifest: %w", err)
+	}
+	if manifest.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %v", manifest.Version)
+	}
+	if manifest.Target != target {
+		return nil, fmt.Errorf("snapshot is for %v, but the manager is for %v", manifest.Target, target)
+	}
+	maxSignal, err := parsePCs(files[snapshotSignalFile])
+	if err != nil {
+		return nil, fmt.Errorf("bad snapshot max signal: %w", err)
+	}
+	if data := files[snapshotCorpusFile]; data != nil && workdir != "" {
+		corpusFile := filepath.Join(workdir, "corpus.db")
+		if osutil.IsExist(corpusFile) {
+			if err := os.Rename(corpusFile, corpusFile+".old"); err != nil {
+				return nil, err
+			}
+		}
+		if err := osutil.WriteFile(corpusFile, data); err != nil {
+			return nil, err
+		}
+	}
+	mgrLog.Logf(0, "restored snapshot of %v taken at %v: %v programs, %v max signal",
+		manifest.Name, manifest.Time.Format(time.RFC3339), manifest.Corpus, manifest.MaxSignal)
+	return maxSignal, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workdir := t.TempDir()
+	cfg := &mgrconfig.Config{
+		Name:    "test",
+		Workdir: workdir,
+	}
+	cfg.Target = target
+	mgr := &Manager{
+		cfg:                   cfg,
+		target:                target,
+		corpus:                corpus.NewCorpus(context.Background()),
+		targetEnabledSyscalls: map[*prog.Syscall]bool{target.Syscalls[0]: true, target.Syscalls[1]: true},
+	}
+	p := target.Generate(rand.NewSource(0), 5, target.DefaultChoiceTable())
+	mgr.corpus.Save(corpus.NewInput{
+		Prog:   p,
+		Signal: signal.FromRaw([]uint64{1, 2}, 0),
+		Cover:  []uint64{0x10, 0x20},
+	})
+	corpusDB, err := db.Open(filepath.Join(workdir, "corpus.db"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpusDB.Save("sig", p.Serialize(), 0)
+	if err := corpusDB.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := mgr.writeSnapshot(buf); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	if err := osutil.WriteFile(archive, buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = restoreSnapshot(archive, t.TempDir(), "linux/amd64")
+	assert.ErrorContains(t, err, "snapshot is for test/64")
+
+	newWorkdir := t.TempDir()
+	assert.NoError(t, osutil.WriteFile(filepath.Join(newWorkdir, "corpus.db"), []byte("old")))
+	maxSignal, err := restoreSnapshot(archive, newWorkdir, "test/64")
+	assert.NoError(t, err)
+	assert.Empty(t, maxSignal)
+	want, err := os.ReadFile(filepath.Join(workdir, "corpus.db"))
+	assert.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(newWorkdir, "corpus.db"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	old, err := os.ReadFile(filepath.Join(newWorkdir, "corpus.db.old"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("old"), old)
+
+	// Preemption checkpoints don't touch the corpus and are consumed on restore.
+	mgr.corpusDB = corpusDB
+	mgr.newRepros = [][]byte{[]byte("repro")}
+	mgr.preempt()
+	checkpoint := filepath.Join(workdir, preemptCheckpointFile)
+	assert.True(t, osutil.IsExist(checkpoint))
+	maxSignal, state := restorePreemptCheckpoint(workdir, "test/64")
+	assert.Empty(t, maxSignal)
+	assert.Equal(t, &preemptState{HubRepros: [][]byte{[]byte("repro")}}, state)
+	assert.False(t, osutil.IsExist(checkpoint))
+	assert.False(t, osutil.IsExist(filepath.Join(workdir, preemptStateFile)))
+	got, err = os.ReadFile(filepath.Join(workdir, "corpus.db"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.False(t, osutil.IsExist(filepath.Join(workdir, "corpus.db.old")))
+}
+
+func TestSnapshotPCs(t *testing.T) {
+	pcs := []uint64{0x30, 0x10, 0xffffffff81000000}
+	data := serializePCs(pcs)
+	assert.Equal(t, "0x10\n0x30\n0xffffffff81000000\n", string(data))
+	parsed, err := parsePCs(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0x10, 0x30, 0xffffffff81000000}, parsed)
+	_, err = parsePCs([]byte("foo\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// Programs that lose more than this fraction of their coverage are reported.
+	soakProgSignalLoss = 0.2
+	// The soak run fails if total coverage drops by more than this fraction.
+	soakMaxSignalLoss = 0.05
+)
+
+// soakSource returns the source for the soak mode: the corpus is executed
+// over and over again and the results are compared with the baseline.
+func (mgr *Manager) soakSource(opts flatrpc.ExecOpts) queue.Source {
+	<-mgr.corpusPreloaded
+	var progs []*prog.Prog
+	for _, rec := range mgr.corpusDB.Records {
+		p, disabled, err := parseProgram(mgr.target, mgr.targetEnabledSyscalls, rec.Val)
+		if err != nil || disabled {
+			continue
+		}
+		progs = append(progs, p)
+	}
+	if len(progs) == 0 {
+		log.Fatalf("soak: the corpus is empty")
+	}
+	baselineFile := filepath.Join(mgr.cfg.Workdir, "soak.json")
+	var baseline *fuzzer.SoakBaseline
+	if data, err := os.ReadFile(baselineFile); err == nil {
+		baseline = new(fuzzer.SoakBaseline)
+		if err := json.Unmarshal(data, baseline); err != nil {
+			log.Fatalf("soak: failed to parse %v: %v", baselineFile, err)
+		}
+		mgrLog.Logf(0, "soak: loaded baseline with %v programs and %v signal",
+			len(baseline.Progs), baseline.Signal)
+	} else if !os.IsNotExist(err) {
+		log.Fatalf("soak: %v", err)
+	}
+	mgrLog.Logf(0, "soak: running %v programs", len(progs))
+	return fuzzer.NewSoak(&fuzzer.SoakConfig{
+		Progs:         progs,
+		ExecOpts:      opts,
+		Baseline:      baseline,
+		MaxSignalLoss: soakProgSignalLoss,
+		PassDone: func(pass *fuzzer.SoakPass) {
+			mgr.soakPassDone(pass, baseline == nil, baselineFile)
+		},
+	})
+}
+
+func (mgr *Manager) soakPassDone(pass *fuzzer.SoakPass, saveBaseline bool, baselineFile string) {
+	if pass.Number == 1 && saveBaseline {
+		if pass.Failed > 0 {
+			log.Fatalf("soak: pass 1: %v programs failed to execute, not saving the baseline", pass.Failed)
+		}
+		mgrLog.Logf(0, "soak: pass 1: saving baseline with %v signal", pass.Result.Signal)
+		writeSoakJSON(baselineFile, pass.Result)
+		return
+	}
+	mgrLog.Logf(0, "soak: pass %v: signal %v (loss %.1f%%), %v deviations, %v programs failed",
+		pass.Number, pass.Result.Signal, pass.SignalLoss*100, len(pass.Deviations), pass.Failed)
+	for _, dev := range pass.Deviations {
+		mgrLog.Logf(0, "soak: program %v: signal %v -> %v, successful calls %v -> %v", dev.Prog,
+			dev.Baseline.Signal, dev.Current.Signal, dev.Baseline.Successful, dev.Current.Successful)
+	}
+	writeSoakJSON(filepath.Join(mgr.cfg.Workdir, "soak-report.json"), pass)
+	if pass.Failed > 0 {
+		log.Fatalf("soak: %v programs failed to execute", pass.Failed)
+	}
+	if pass.SignalLoss > soakMaxSignalLoss {
+		log.Fatalf("soak: total signal dropped by %.1f%% compared to the baseline", pass.SignalLoss*100)
+	}
+}
+
+func writeSoakJSON(file string, v any) {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		log.Fatalf("failed to serialize soak results: %v", err)
+	}
+	if err := osutil.WriteFile(file, data); err != nil {
+		log.Fatal(err)
+	}
+}
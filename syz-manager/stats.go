@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/stats"
+	"github.com/google/syzkaller/prog"
 )
 
 type Stats struct {
@@ -17,14 +18,20 @@ type Stats struct {
 	statCrashes        *stats.Val
 	statCrashTypes     *stats.Val
 	statSuppressed     *stats.Val
+	statRateLimited    *stats.Val
 	statUptime         *stats.Val
 	statFuzzingTime    *stats.Val
 	statAvgBootTime    *stats.Val
+	statWatchdogResets *stats.Val
+	// Number of crashes attributed to programs produced by each mutation op.
+	statCrashMutations [prog.MutationCount]*stats.Val
+	// Number of crashes per VM configuration variant (empty if the VM type has no variants).
+	statCrashVariants map[string]*stats.Val
 }
 
 func (mgr *Manager) initStats() {
 	mgr.statNumReproducing = stats.Create("reproducing", "Number of crashes being reproduced",
-		stats.Console, stats.NoGraph)
+		stats.Console, stats.NoGraph, stats.Link("/repro_queue"))
 	mgr.statExecs = stats.Create("exec total", "Total test program executions",
 		stats.Console, stats.Rate{}, stats.Prometheus("syz_exec_total"))
 	mgr.statCrashes = stats.Create("crashes", "Total number of VM crashes",
@@ -33,6 +40,11 @@ func (mgr *Manager) initStats() {
 		stats.Simple, stats.NoGraph)
 	mgr.statSuppressed = stats.Create("suppressed", "Total number of suppressed VM crashes",
 		stats.Simple, stats.Graph("crashes"))
+	if mgr.cfg.Experimental.CrashRateLimit > 0 {
+		mgr.statRateLimited = stats.Create("rate limited",
+			"Number of crashes treated as suppressed because of experimental.crash_rate_limit",
+			stats.Simple, stats.Graph("crashes"))
+	}
 	mgr.statFuzzingTime = stats.Create("fuzzing", "Total fuzzing time in all VMs (seconds)",
 		stats.NoGraph, func(v int, period time.Duration) string { return fmt.Sprintf("%v sec", v/1e9) })
 	mgr.statUptime = stats.Create("uptime", "Total uptime (seconds)", stats.Simple, stats.NoGraph,
@@ -54,6 +66,33 @@ func (mgr *Manager) initStats() {
 			return fmt.Sprintf("%v sec", v)
 		})
 
+	if mgr.cfg.Experimental.Watchdog != "" {
+		mgr.statWatchdogResets = stats.Create("watchdog resets",
+			"Number of times machines were reset by the hardware watchdog (see experimental.watchdog)",
+			stats.Simple, stats.Graph("crashes"))
+	}
+
+	for op := prog.MutationOp(0); op < prog.MutationCount; op++ {
+		if op == prog.MutationNone {
+			continue
+		}
+		mgr.statCrashMutations[op] = stats.Create("crash mutation "+op.String(),
+			"Number of crashes where the last executing programs had arguments produced by the mutation op",
+			stats.Simple, stats.Graph("crash mutations"))
+	}
+	statDescWarnings := stats.Create("desc warnings", "Number of warnings produced by the descriptions compiler",
+		stats.Simple, stats.NoGraph, stats.Link("/descriptions"))
+	statDescWarnings.Add(len(mgr.target.Warnings))
+	mgr.statCrashVariants = make(map[string]*stats.Val)
+	for i := 0; mgr.vmPool != nil && i < mgr.vmPool.Count(); i++ {
+		variant := mgr.vmPool.Variant(i)
+		if variant == "" || mgr.statCrashVariants[variant] != nil {
+			continue
+		}
+		mgr.statCrashVariants[variant] = stats.Create("crashes "+variant,
+			"Number of crashes on VMs running the configuration variant",
+			stats.Simple, stats.Graph("crash variants"))
+	}
 	stats.Create("heap", "Process heap size (bytes)", stats.Graph("memory"),
 		func() int {
 			var ms runtime.MemStats
@@ -0,0 +1,76 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/vm"
+)
+
+const (
+	// Number of random programs executed for each validated call.
+	validationRuns = 100
+	// Calls that fail with unexpected errnos at least this often are reported.
+	validationMinUnexpected = 0.9
+)
+
+// validationSource returns the source for the validate mode: calls with the errnos attribute
+// are executed a number of times and calls that consistently fail with other errnos are reported.
+func (mgr *Manager) validationSource(enabledSyscalls map[*prog.Syscall]bool, opts flatrpc.ExecOpts) queue.Source {
+	validation := fuzzer.NewValidation(&fuzzer.ValidationConfig{
+		Target:        mgr.target,
+		EnabledCalls:  enabledSyscalls,
+		ExecOpts:      opts,
+		Runs:          validationRuns,
+		MinUnexpected: validationMinUnexpected,
+		Done:          mgr.validationDone,
+	}, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if validation.Calls() == 0 {
+		log.Fatalf("validate: none of the enabled calls declare expected errnos")
+	}
+	mgrLog.Logf(0, "validate: running %v programs for each of %v calls", validationRuns, validation.Calls())
+	return validation
+}
+
+func (mgr *Manager) validationDone(report *fuzzer.ValidationReport) {
+	reportFile := filepath.Join(mgr.cfg.Workdir, "validate-report.json")
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		log.Fatalf("failed to serialize validation results: %v", err)
+	}
+	if err := osutil.WriteFile(reportFile, data); err != nil {
+		log.Fatal(err)
+	}
+	mgrLog.Logf(0, "validate: %v calls, %v programs failed", len(report.Calls), report.Failed)
+	for _, name := range report.Broken {
+		stats := report.Calls[name]
+		var errnos []int
+		for errno := range stats.Unexpected {
+			errnos = append(errnos, int(errno))
+		}
+		sort.Ints(errnos)
+		mgrLog.Logf(0, "validate: %v: %v/%v runs failed with unexpected errnos %v",
+			name, stats.Finished-stats.Successful-stats.Expected, stats.Finished, errnos)
+	}
+	if len(report.Broken) != 0 {
+		log.Fatalf("validate: %v calls consistently fail with unexpected errnos, see %v",
+			len(report.Broken), reportFile)
+	}
+	mgrLog.Logf(0, "validate: succeeded, shutting down...")
+	close(vm.Shutdown)
+	time.Sleep(10 * time.Second)
+	os.Exit(0)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+)
+
+// vmScheduler decides what VMs are used for according to experimental.vm_scheduling.
+// VMs are split into fast and slow ones: reproduction prefers the fast VMs,
+// fuzzing prefers the slow VMs. A nil scheduler treats all VMs equally.
+type vmScheduler struct {
+	slow   map[int]bool
+	repro  string
+	triage string
+}
+
+func newVMScheduler(cfg *mgrconfig.VMScheduling, count int) (*vmScheduler, error) {
+	if cfg == nil || len(cfg.SlowVMs) == 0 {
+		return nil, nil
+	}
+	sched := &vmScheduler{
+		slow:   make(map[int]bool),
+		repro:  cfg.Repro,
+		triage: cfg.Triage,
+	}
+	for _, idx := range cfg.SlowVMs {
+		if idx >= count {
+			return nil, fmt.Errorf("experimental.vm_scheduling.slow_vms: index %v is out of range"+
+				" (total %v VMs)", idx, count)
+		}
+		sched.slow[idx] = true
+	}
+	return sched, nil
+}
+
+func (sched *vmScheduler) isSlow(idx int) bool {
+	return sched != nil && sched.slow[idx]
+}
+
+// reproRank is a ResourcePool.TakeRanked rank for reproduction VMs.
+func (sched *vmScheduler) reproRank(idx int) int {
+	if !sched.isSlow(idx) {
+		return 0
+	}
+	switch sched.repro {
+	case mgrconfig.VMSchedFast:
+		return -1
+	case mgrconfig.VMSchedPreferFast:
+		return 1
+	}
+	return 0
+}
+
+// fuzzRank is a ResourcePool.TakeRanked rank for fuzzing VMs.
+func (sched *vmScheduler) fuzzRank(idx int) int {
+	if sched == nil || sched.slow[idx] {
+		return 0
+	}
+	return 1
+}
+
+// fuzzOnlyRank is like fuzzRank, but only for the VMs that can't be used for reproduction.
+func (sched *vmScheduler) fuzzOnlyRank(idx int) int {
+	if sched.reproRank(idx) >= 0 {
+		return -1
+	}
+	return 0
+}
+
+// reproVMs returns the number of VMs that can be used for reproduction.
+func (sched *vmScheduler) reproVMs(count int) int {
+	ret := 0
+	for idx := 0; idx < count; idx++ {
+		if sched.reproRank(idx) >= 0 {
+			ret++
+		}
+	}
+	return ret
+}
+
+// triageOnFast says if important requests (triage, candidates) should be executed on the fast VMs.
+func (sched *vmScheduler) triageOnFast() bool {
+	return sched != nil && sched.triage == mgrconfig.VMSchedFast
+}
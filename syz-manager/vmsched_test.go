@@ -0,0 +1,65 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVMScheduler(t *testing.T) {
+	sched, err := newVMScheduler(&mgrconfig.VMScheduling{SlowVMs: []int{4}}, 4)
+	assert.Error(t, err)
+	assert.Nil(t, sched)
+
+	sched, err = newVMScheduler(&mgrconfig.VMScheduling{}, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, sched)
+	assert.Equal(t, 4, sched.reproVMs(4))
+	assert.False(t, sched.triageOnFast())
+
+	sched, err = newVMScheduler(&mgrconfig.VMScheduling{
+		SlowVMs: []int{0, 1},
+		Repro:   mgrconfig.VMSchedPreferFast,
+		Triage:  mgrconfig.VMSchedFast,
+	}, 4)
+	assert.NoError(t, err)
+	assert.True(t, sched.isSlow(1))
+	assert.False(t, sched.isSlow(2))
+	assert.Equal(t, 4, sched.reproVMs(4))
+	assert.True(t, sched.triageOnFast())
+
+	pool := &ResourcePool{Freed: make(chan interface{}, 1)}
+	pool.Put(0, 1, 2, 3)
+	// Fast VMs are taken for reproduction first.
+	assert.Equal(t, []int{2, 3}, pool.TakeRanked(2, sched.reproRank))
+	assert.Equal(t, []int{1}, pool.TakeRanked(1, sched.reproRank))
+	pool.Put(2, 3)
+	// Slow VMs are taken for fuzzing first.
+	assert.Equal(t, []int{0}, pool.TakeRanked(1, sched.fuzzRank))
+	assert.Equal(t, []int{3}, pool.TakeRanked(1, sched.fuzzRank))
+	assert.Nil(t, pool.TakeRanked(2, sched.fuzzRank))
+
+	sched.repro = mgrconfig.VMSchedFast
+	assert.Equal(t, 2, sched.reproVMs(4))
+	pool.Put(0)
+	assert.Nil(t, pool.TakeRanked(2, sched.reproRank))
+	// While a reproduction is pending, only slow VMs start fuzzing.
+	assert.Equal(t, []int{0}, pool.TakeRanked(1, sched.fuzzOnlyRank))
+	assert.Nil(t, pool.TakeRanked(1, sched.fuzzOnlyRank))
+	assert.Equal(t, []int{2}, pool.Snapshot())
+}
+
+func TestTakeRankedNoScheduler(t *testing.T) {
+	// Without the scheduler TakeRanked works as Take.
+	var sched *vmScheduler
+	pool := &ResourcePool{Freed: make(chan interface{}, 1)}
+	pool.Put(0, 1, 2, 3, 4)
+	assert.Equal(t, []int{3, 4}, pool.TakeRanked(2, sched.reproRank))
+	assert.Equal(t, []int{2}, pool.TakeRanked(1, sched.fuzzRank))
+	assert.Nil(t, pool.TakeRanked(1, sched.fuzzOnlyRank))
+	assert.Equal(t, []int{0, 1}, pool.Snapshot())
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"regexp"
+)
+
+// The fuzzer prints the banner on start if the machine was reset by the hardware watchdog
+// (see experimental.watchdog). The fuzzer may be restarted several times after a single reset,
+// so resets are deduplicated by the kernel boot ID.
+var watchdogResetRe = regexp.MustCompile(`SYZ-FUZZER: WATCHDOG RESET boot_id=(\S*)`)
+
+func (mgr *Manager) noteWatchdogReset(instanceName, bootID string) {
+	mgr.mu.Lock()
+	if mgr.watchdogBoots == nil {
+		mgr.watchdogBoots = make(map[string]bool)
+	}
+	seen := bootID != "" && mgr.watchdogBoots[bootID]
+	mgr.watchdogBoots[bootID] = true
+	mgr.mu.Unlock()
+	if seen {
+		return
+	}
+	mgrLog.Logf(0, "%v: the machine was reset by the hardware watchdog (boot %v)", instanceName, bootID)
+	mgr.statWatchdogResets.Add(1)
+}
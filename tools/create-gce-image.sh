@@ -9,7 +9,7 @@
 # - you need a user-space system, a basic Debian system can be created with:
 #   sudo debootstrap --include=openssh-server,curl,tar,gcc,libc6-dev,time,strace,sudo,less,psmisc,selinux-utils,policycoreutils,checkpolicy,selinux-policy-default,firmware-atheros --components=main,contrib,non-free stable debian
 # - you need kernel to use with image (e.g. arch/x86/boot/bzImage)
-#   note: kernel modules are not supported
+#   note: kernel modules are not supported, except for the ones in SYZ_MODULES_DIR (see below)
 # - you need grub:
 #   sudo apt-get install grub-efi
 #
@@ -18,6 +18,8 @@
 #
 # If SYZ_SYSCTL_FILE env var is set and points to a file,
 # then its contents will be appended to the image /etc/sysctl.conf.
+# If SYZ_MODULES_DIR env var is set and points to a dir, then its contents
+# (e.g. lib/modules and etc/modules-load.d) will be copied into the image root.
 # If SYZ_CMDLINE_FILE env var is set and points to a file,
 # then its contents will be appended to the kernel command line.
 # If MKE2FS_CONFIG env var is set, it will affect invoked mkfs.ext4.
@@ -112,6 +114,10 @@ sudo mount $PARTDEV disk.mnt
 CLEANUP="sudo umount disk.mnt; $CLEANUP"
 sudo cp -a $1/. disk.mnt/.
 sudo cp $2 disk.mnt/vmlinuz
+SYZ_MODULES_DIR="${SYZ_MODULES_DIR:-}"
+if [ "$SYZ_MODULES_DIR" != "" ]; then
+	sudo cp -a $SYZ_MODULES_DIR/. disk.mnt/.
+fi
 sudo sed -i "/^root/ { s/:x:/::/ }" disk.mnt/etc/passwd
 echo "T0:23:respawn:/sbin/getty -L ttyS0 115200 vt100" | sudo tee -a disk.mnt/etc/inittab
 echo -en "auto lo\niface lo inet loopback\nauto eth0\niface eth0 inet dhcp\n" | sudo tee disk.mnt/etc/network/interfaces
@@ -72,6 +72,9 @@ type Config struct {
 
 	// Manager config that was used to obtain the crash.
 	Manager json.RawMessage `json:"manager"`
+	// Additional manager configs used to test several kernel commits in parallel.
+	// Each of them must have its own kernel_src checkout, workdir and VMs.
+	SpeculativeManagers []json.RawMessage `json:"speculative_managers"`
 }
 
 func main() {
@@ -123,6 +126,14 @@ func main() {
 		},
 		Manager: mgrcfg,
 	}
+	for _, data := range mycfg.SpeculativeManagers {
+		poolcfg, err := mgrconfig.LoadData(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		cfg.SpeculativePools = append(cfg.SpeculativePools, poolcfg)
+	}
 	loadFile("", mycfg.KernelConfig, &cfg.Kernel.Config, true)
 	loadFile("", mycfg.KernelBaselineConfig, &cfg.Kernel.BaselineConfig, false)
 	loadFile(*flagCrash, "repro.prog", &cfg.Repro.Syz, false)
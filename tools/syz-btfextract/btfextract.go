@@ -0,0 +1,49 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-btfextract generates descriptions of BPF helpers, map/prog types and kfuncs from kernel BTF.
+// BTF can be taken either from /sys/kernel/btf/vmlinux of a running kernel, or from a vmlinux binary.
+// Usage:
+//
+//	syz-btfextract -btf vmlinux -out sys/linux/bpf_btf.txt -consts sys/linux/bpf_btf.txt.const
+package main
+
+import (
+	"flag"
+	"os"
+	"runtime"
+
+	"github.com/google/syzkaller/pkg/declextract"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagBTF    = flag.String("btf", "/sys/kernel/btf/vmlinux", "raw BTF file or vmlinux with .BTF section")
+	flagOut    = flag.String("out", "", "output file for descriptions (stdout if empty)")
+	flagConsts = flag.String("consts", "", "output file for consts (not generated if empty)")
+	flagArch   = flag.String("arch", runtime.GOARCH, "arch for the consts file")
+)
+
+func main() {
+	defer tool.Init()()
+	btf, err := declextract.LoadBTF(*flagBTF)
+	if err != nil {
+		tool.Fail(err)
+	}
+	iface, err := declextract.ExtractBPF(btf)
+	if err != nil {
+		tool.Fail(err)
+	}
+	desc := declextract.SerializeBPF(iface)
+	if *flagOut == "" {
+		os.Stdout.Write(desc)
+	} else if err := osutil.WriteFile(*flagOut, desc); err != nil {
+		tool.Fail(err)
+	}
+	if *flagConsts != "" {
+		if err := osutil.WriteFile(*flagConsts, declextract.SerializeBPFConsts(iface, *flagArch)); err != nil {
+			tool.Fail(err)
+		}
+	}
+}
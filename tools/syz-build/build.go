@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/build"
 	"github.com/google/syzkaller/pkg/debugtracer"
@@ -26,6 +27,8 @@ var (
 	flagKernelSysctl  = flag.String("sysctl", "", "kernel sysctl file")
 	flagKernelCmdline = flag.String("cmdline", "", "kernel cmdline file")
 	flagUserspace     = flag.String("userspace", "", "path to userspace for build")
+	flagModules       = flag.String("modules", "", "comma-separated list of out-of-tree kernel module dirs")
+	flagLoadModules   = flag.Bool("load_modules", false, "load the out-of-tree modules on boot")
 	flagTrace         = flag.Bool("trace", false, "trace build process and save debug artefacts")
 )
 
@@ -62,6 +65,14 @@ func main() {
 		Config:       kernelConfig,
 		Tracer:       &debugtracer.NullTracer{},
 	}
+	if *flagModules != "" {
+		for _, dir := range strings.Split(*flagModules, ",") {
+			params.ExternalModules = append(params.ExternalModules, build.ExternalModule{
+				Dir:  dir,
+				Load: *flagLoadModules,
+			})
+		}
+	}
 	if *flagTrace {
 		params.Tracer = &debugtracer.GenericTracer{
 			TraceWriter: os.Stdout,
@@ -0,0 +1,106 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"debug/dwarf"
+	"fmt"
+
+	"github.com/google/syzkaller/pkg/declextract"
+)
+
+// parseKernelBTF extracts struct descriptions from BTF type info.
+// obj is either an ELF object file with .BTF section (vmlinux), or a raw BTF file
+// (e.g. /sys/kernel/btf/vmlinux of the running kernel).
+// Unlike DWARF, BTF does not require special compiler flags, is present in most distro kernels
+// and is much faster to parse, but the result is the same: it's used for struct layout checking.
+func parseKernelBTF(obj string, ptrSize uint64) (map[string]*dwarf.StructType, error) {
+	btf, err := declextract.LoadBTF(obj)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", obj, err)
+	}
+	return btfStructs(btf, ptrSize), nil
+}
+
+func btfStructs(btf *declextract.BTF, ptrSize uint64) map[string]*dwarf.StructType {
+	// BTF.Size assumes 64-bit pointers, so sizes are calculated here.
+	sizes := make(map[int]int64)
+	var size func(id, depth int) int64
+	size = func(id, depth int) int64 {
+		if id <= 0 || id >= len(btf.Types) || depth > 32 {
+			return 0
+		}
+		if sz, ok := sizes[id]; ok {
+			return sz
+		}
+		var sz int64
+		switch typ := btf.Types[id]; typ.Kind {
+		case declextract.BTFInt, declextract.BTFStruct, declextract.BTFUnion, declextract.BTFEnum,
+			declextract.BTFEnum64, declextract.BTFFloat, declextract.BTFDatasec:
+			sz = int64(typ.Size)
+		case declextract.BTFPtr:
+			sz = int64(ptrSize)
+		case declextract.BTFArray:
+			sz = int64(typ.Len) * size(typ.Type, depth+1)
+		case declextract.BTFTypedef, declextract.BTFVolatile, declextract.BTFConst,
+			declextract.BTFRestrict, declextract.BTFTypeTag:
+			sz = size(typ.Type, depth+1)
+		}
+		sizes[id] = sz
+		return sz
+	}
+	structs := make(map[string]*dwarf.StructType)
+	for _, typ := range btf.Types[1:] {
+		if typ.Kind != declextract.BTFStruct && typ.Kind != declextract.BTFUnion || typ.Name == "" {
+			continue
+		}
+		if structs[typ.Name] != nil {
+			continue
+		}
+		str := &dwarf.StructType{
+			CommonType: dwarf.CommonType{
+				ByteSize: int64(typ.Size),
+				Name:     typ.Name,
+			},
+			StructName: typ.Name,
+			Kind:       "struct",
+		}
+		if typ.Kind == declextract.BTFUnion {
+			str.Kind = "union"
+		}
+		for _, m := range typ.Members {
+			str.Field = append(str.Field, btfField(btf, typ, m, size(m.Type, 0)))
+		}
+		structs[typ.Name] = str
+	}
+	return structs
+}
+
+func btfField(btf *declextract.BTF, parent *declextract.BTFType, m declextract.BTFMember,
+	fieldSize int64) *dwarf.StructField {
+	bitOffset, bitSize := int64(m.Offset), int64(0)
+	if parent.KindFlag {
+		bitOffset, bitSize = int64(m.Offset&0xffffff), int64(m.Offset>>24)
+	} else if it := btf.Resolve(m.Type); it.Kind == declextract.BTFInt &&
+		(it.IntOffset != 0 || int64(it.IntBits) != fieldSize*8) {
+		// Old-style bitfields are encoded in the int type itself,
+		// the member offset points to the int and the int encoding has the offset of the bits.
+		bitOffset += int64(it.IntOffset)
+		bitSize = int64(it.IntBits)
+	}
+	fld := &dwarf.StructField{
+		Name: m.Name,
+		Type: &dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: fieldSize}},
+	}
+	if bitSize == 0 || fieldSize == 0 {
+		fld.ByteOffset = bitOffset / 8
+	} else {
+		// Use the DWARF representation: byte offset of the storage unit and
+		// offset of the end of the field from the end of the storage unit.
+		fld.ByteOffset = bitOffset / (fieldSize * 8) * fieldSize
+		fld.BitSize = bitSize
+		fld.BitOffset = fieldSize*8 - (bitOffset - fld.ByteOffset*8) - bitSize
+	}
+	return fld
+}
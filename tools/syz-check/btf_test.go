@@ -0,0 +1,95 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"debug/dwarf"
+	"encoding/binary"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/declextract"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBTFStructs(t *testing.T) {
+	var types, strs []byte
+	strs = append(strs, 0)
+	str := func(s string) uint32 {
+		off := uint32(len(strs))
+		strs = append(append(strs, s...), 0)
+		return off
+	}
+	nextID := uint32(1)
+	add := func(name string, kind declextract.BTFKind, kindFlag bool, vlen, sizeOrType uint32,
+		extra ...uint32) uint32 {
+		info := uint32(kind)<<24 | vlen
+		if kindFlag {
+			info |= 1 << 31
+		}
+		for _, v := range append([]uint32{str(name), info, sizeOrType}, extra...) {
+			types = binary.LittleEndian.AppendUint32(types, v)
+		}
+		nextID++
+		return nextID - 1
+	}
+	u32 := add("u32", declextract.BTFInt, false, 0, 4, 32)
+	// Old-style bitfield: 3 bits at bit offset 5 within the int.
+	u32bits := add("u32", declextract.BTFInt, false, 0, 4, 5<<16|3)
+	ptr := add("", declextract.BTFPtr, false, 0, u32)
+	arr := add("", declextract.BTFArray, false, 0, 0, u32, u32, 2)
+	add("new_style", declextract.BTFStruct, true, 4, 24,
+		str("a"), u32, 0,
+		str("b"), ptr, 64,
+		str("c"), u32, 3<<24|128,
+		str("d"), arr, 0)
+	add("old_style", declextract.BTFStruct, false, 2, 8,
+		str("x"), u32bits, 0,
+		str("y"), u32, 32)
+	add("un", declextract.BTFUnion, false, 1, 4, str("z"), u32, 0)
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint16(hdr[0:], 0xeb9f)
+	hdr[2] = 1
+	binary.LittleEndian.PutUint32(hdr[4:], 24)
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(len(types)))
+	binary.LittleEndian.PutUint32(hdr[16:], uint32(len(types)))
+	binary.LittleEndian.PutUint32(hdr[20:], uint32(len(strs)))
+	btf, err := declextract.ParseBTF(append(append(hdr, types...), strs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := func(name string, size, off, bitSize, bitOff int64) *dwarf.StructField {
+		return &dwarf.StructField{
+			Name:       name,
+			Type:       &dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: size}},
+			ByteOffset: off,
+			BitSize:    bitSize,
+			BitOffset:  bitOff,
+		}
+	}
+	structs := btfStructs(btf, 4)
+	assert.Len(t, structs, 3)
+	assert.Equal(t, &dwarf.StructType{
+		CommonType: dwarf.CommonType{ByteSize: 24, Name: "new_style"},
+		StructName: "new_style",
+		Kind:       "struct",
+		Field: []*dwarf.StructField{
+			field("a", 4, 0, 0, 0),
+			field("b", 4, 8, 0, 0),
+			field("c", 4, 16, 3, 29),
+			field("d", 8, 0, 0, 0),
+		},
+	}, structs["new_style"])
+	assert.Equal(t, &dwarf.StructType{
+		CommonType: dwarf.CommonType{ByteSize: 8, Name: "old_style"},
+		StructName: "old_style",
+		Kind:       "struct",
+		Field: []*dwarf.StructField{
+			field("x", 4, 0, 3, 24),
+			field("y", 4, 4, 0, 0),
+		},
+	}, structs["old_style"])
+	assert.Equal(t, "union", structs["un"].Kind)
+	// Pointer size comes from the target.
+	assert.Equal(t, int64(8), btfStructs(btf, 8)["new_style"].Field[1].Type.Size())
+}
@@ -21,6 +21,13 @@
 //
 //	$ syz-check -obj-amd64 /linux_amd64/vmlinux
 //
+// Alternatively, struct layouts can be checked against BTF type info with -btf flag.
+// BTF does not require the special compiler flags (only CONFIG_DEBUG_INFO_BTF=y) and is much faster
+// to parse. The object file can be either vmlinux with .BTF section, or a raw BTF file of a running
+// kernel (netlink checks need vmlinux, so use -netlink=0 in that case):
+//
+//	$ syz-check -btf -netlink=0 -obj-amd64 /sys/kernel/btf/vmlinux
+//
 // You may also disable dwarf or netlink checks with the corresponding flags.
 // E.g. -dwarf=0 greatly speeds up checking if you are only interested in netlink warnings
 // (but then again don't commit changes).
@@ -57,6 +64,7 @@ func main() {
 	var (
 		flagOS      = flag.String("os", runtime.GOOS, "OS")
 		flagDWARF   = flag.Bool("dwarf", true, "do checking based on DWARF")
+		flagBTF     = flag.Bool("btf", false, "do struct checking based on BTF instead of DWARF")
 		flagNetlink = flag.Bool("netlink", true, "do checking of netlink policies")
 	)
 	arches := make(map[string]*string)
@@ -70,7 +78,7 @@ func main() {
 			delete(arches, arch)
 			continue
 		}
-		warnings1, err := check(*flagOS, arch, *obj, *flagDWARF, *flagNetlink)
+		warnings1, err := check(*flagOS, arch, *obj, *flagDWARF, *flagBTF, *flagNetlink)
 		if err != nil {
 			tool.Fail(err)
 		}
@@ -88,7 +96,7 @@ func main() {
 	}
 }
 
-func check(OS, arch, obj string, dwarf, netlink bool) ([]Warn, error) {
+func check(OS, arch, obj string, useDWARF, useBTF, netlink bool) ([]Warn, error) {
 	var warnings []Warn
 	if obj == "" {
 		return nil, fmt.Errorf("no object file in -obj-%v flag", arch)
@@ -98,8 +106,13 @@ func check(OS, arch, obj string, dwarf, netlink bool) ([]Warn, error) {
 		return nil, err
 	}
 	warnings = append(warnings, warnings1...)
-	if dwarf {
-		structs, err := parseKernelObject(obj)
+	if useDWARF || useBTF {
+		var structs map[string]*dwarf.StructType
+		if useBTF {
+			structs, err = parseKernelBTF(obj, targets.Get(OS, arch).PtrSize)
+		} else {
+			structs, err = parseKernelObject(obj)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -333,11 +346,11 @@ func parseDescriptions(OS, arch string) ([]prog.Type, map[string]*ast.Struct, []
 	if top == nil {
 		return nil, nil, nil, fmt.Errorf("failed to parse txt files:\n%s", errorBuf.Bytes())
 	}
-	consts := compiler.DeserializeConstFile(filepath.Join("sys", OS, "*.const"), eh).Arch(arch)
-	if consts == nil {
+	constFile := compiler.DeserializeConstFile(filepath.Join("sys", OS, "*.const"), eh)
+	if constFile == nil || !constFile.AddExternal(top, []string{arch}, eh) {
 		return nil, nil, nil, fmt.Errorf("failed to parse const files:\n%s", errorBuf.Bytes())
 	}
-	prg := compiler.Compile(top, consts, targets.Get(OS, arch), eh)
+	prg := compiler.Compile(top, constFile.Arch(arch), targets.Get(OS, arch), eh)
 	if prg == nil {
 		return nil, nil, nil, fmt.Errorf("failed to compile descriptions:\n%s", errorBuf.Bytes())
 	}
@@ -0,0 +1,328 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-corpus-analyze analyzes a corpus database and prints a report on the corpus health:
+// per-syscall program counts, program lengths, dead programs (programs with calls that
+// no longer exist or are disabled), distribution of coverage of programs and duplicate programs.
+// The report can be printed as text, JSON or CSV (the CSV output is a per-syscall table
+// with the first "*" row for the whole corpus, which is handy for tracking the corpus over time).
+//
+// Per-program coverage is not stored in corpus.db, it can be provided with the -cover flag
+// as a file downloaded from the /corpus?json=1 page of the manager.
+//
+// Usage:
+//
+//	syz-corpus-analyze -os=linux -arch=amd64 -corpus=corpus.db [-cover=corpus.json] [-format=json]
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/prog"
+)
+
+var (
+	flagOS      = flag.String("os", runtime.GOOS, "target os")
+	flagArch    = flag.String("arch", runtime.GOARCH, "target arch")
+	flagCorpus  = flag.String("corpus", "", "corpus database file")
+	flagCover   = flag.String("cover", "", "per-program coverage (saved /corpus?json=1 page of the manager)")
+	flagEnable  = flag.String("enable", "", "comma-separated list of enabled syscalls (default: all)")
+	flagDisable = flag.String("disable", "", "comma-separated list of disabled syscalls")
+	flagFormat  = flag.String("format", "text", "output format: text, json or csv")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagCorpus == "" {
+		tool.Failf("specify corpus database with -corpus")
+	}
+	target, err := prog.GetTarget(*flagOS, *flagArch)
+	if err != nil {
+		tool.Fail(err)
+	}
+	enabled, err := mgrconfig.ParseEnabledSyscalls(target, splitList(*flagEnable), splitList(*flagDisable))
+	if err != nil {
+		tool.Failf("failed to parse enabled syscalls: %v", err)
+	}
+	corpusDB, err := db.Open(*flagCorpus, false)
+	if err != nil {
+		tool.Failf("failed to open corpus database: %v", err)
+	}
+	var cover map[string]int
+	if *flagCover != "" {
+		if cover, err = readCover(*flagCover); err != nil {
+			tool.Fail(err)
+		}
+	}
+	rep := analyze(target, corpusDB.Records, enabled, cover)
+	switch *flagFormat {
+	case "text":
+		printText(os.Stdout, rep)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "\t")
+		err = encoder.Encode(rep)
+	case "csv":
+		err = printCSV(os.Stdout, rep)
+	default:
+		tool.Failf("unknown format %q", *flagFormat)
+	}
+	if err != nil {
+		tool.Fail(err)
+	}
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// readCover reads the /corpus?json=1 page of the manager and returns coverage size per program hash.
+func readCover(file string) (map[string]int, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var inputs []struct {
+		Sig   string
+		Cover int
+	}
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", file, err)
+	}
+	cover := make(map[string]int)
+	for _, inp := range inputs {
+		cover[inp.Sig] = inp.Cover
+	}
+	return cover, nil
+}
+
+type Report struct {
+	Programs int
+	// Programs that fail to parse with the current descriptions (e.g. use removed calls).
+	Unparsable int
+	// Programs that can't be executed: unparsable ones and ones with disabled calls.
+	Dead int
+	// Total number of calls and max program length.
+	Calls     int
+	AvgLength float64
+	MaxLength int
+	// Programs that are equal to another program after re-serialization (the database
+	// is keyed by the raw program text, so it may contain differently formatted copies).
+	Duplicates int
+	// Programs with the same sequence of syscalls as another program (including duplicates).
+	SameCalls int
+	Syscalls  []*SyscallStats
+	Cover     *CoverStats `json:",omitempty"`
+}
+
+type SyscallStats struct {
+	Name string
+	// Number of programs with the call and the total number of the calls.
+	Programs int
+	Calls    int
+	// Number of dead programs with the call.
+	Dead int
+	// Average coverage of programs with the call (only programs with known coverage are counted).
+	AvgCover float64 `json:",omitempty"`
+}
+
+// CoverStats is the distribution of coverage (number of covered PCs) of programs.
+type CoverStats struct {
+	Programs int
+	Total    int
+	Min      int
+	Median   int
+	P90      int
+	Max      int
+	// Percent of the total coverage that belongs to the top 10% of programs.
+	Top10Percent float64
+	// Number of programs with coverage in [Min, Max] for power-of-2 ranges.
+	Buckets []CoverBucket
+}
+
+type CoverBucket struct {
+	Min      int
+	Max      int
+	Programs int
+}
+
+func analyze(target *prog.Target, records map[string]db.Record, enabledIDs []int, cover map[string]int) *Report {
+	enabled := make(map[*prog.Syscall]bool)
+	for _, id := range enabledIDs {
+		enabled[target.Syscalls[id]] = true
+	}
+	rep := &Report{}
+	syscalls := make(map[string]*SyscallStats)
+	coverSum := make(map[string]int)
+	coverProgs := make(map[string]int)
+	canonical := make(map[string]bool)
+	sequences := make(map[string]bool)
+	var covers []int
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rep.Programs++
+		p, err := target.Deserialize(records[key].Val, prog.NonStrict)
+		if err != nil {
+			rep.Unparsable++
+			rep.Dead++
+			continue
+		}
+		rep.Calls += len(p.Calls)
+		rep.MaxLength = max(rep.MaxLength, len(p.Calls))
+		data := string(p.Serialize())
+		if canonical[data] {
+			rep.Duplicates++
+		}
+		canonical[data] = true
+		var names []string
+		seen := make(map[string]bool)
+		dead := false
+		for _, c := range p.Calls {
+			names = append(names, c.Meta.Name)
+			if !enabled[c.Meta] {
+				dead = true
+			}
+		}
+		seq := strings.Join(names, " ")
+		if sequences[seq] {
+			rep.SameCalls++
+		}
+		sequences[seq] = true
+		if dead {
+			rep.Dead++
+		}
+		progCover, hasCover := cover[key]
+		if hasCover {
+			covers = append(covers, progCover)
+		}
+		for _, name := range names {
+			stats := syscalls[name]
+			if stats == nil {
+				stats = &SyscallStats{Name: name}
+				syscalls[name] = stats
+			}
+			stats.Calls++
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			stats.Programs++
+			if dead {
+				stats.Dead++
+			}
+			if hasCover {
+				coverSum[name] += progCover
+				coverProgs[name]++
+			}
+		}
+	}
+	if parsed := rep.Programs - rep.Unparsable; parsed != 0 {
+		rep.AvgLength = float64(rep.Calls) / float64(parsed)
+	}
+	for _, stats := range syscalls {
+		if coverProgs[stats.Name] != 0 {
+			stats.AvgCover = float64(coverSum[stats.Name]) / float64(coverProgs[stats.Name])
+		}
+		rep.Syscalls = append(rep.Syscalls, stats)
+	}
+	sort.Slice(rep.Syscalls, func(i, j int) bool {
+		a, b := rep.Syscalls[i], rep.Syscalls[j]
+		if a.Programs != b.Programs {
+			return a.Programs > b.Programs
+		}
+		return a.Name < b.Name
+	})
+	rep.Cover = coverStats(covers)
+	return rep
+}
+
+func coverStats(covers []int) *CoverStats {
+	if len(covers) == 0 {
+		return nil
+	}
+	sort.Ints(covers)
+	n := len(covers)
+	stats := &CoverStats{
+		Programs: n,
+		Min:      covers[0],
+		Median:   covers[n/2],
+		P90:      covers[n*9/10],
+		Max:      covers[n-1],
+	}
+	top := 0
+	for i, c := range covers {
+		stats.Total += c
+		if i >= n-(n+9)/10 {
+			top += c
+		}
+	}
+	if stats.Total != 0 {
+		stats.Top10Percent = 100 * float64(top) / float64(stats.Total)
+	}
+	for _, c := range covers {
+		lo, hi := 0, 0
+		if c != 0 {
+			for lo, hi = 1, 1; hi < c; hi = hi*2 + 1 {
+				lo = hi + 1
+			}
+		}
+		if len(stats.Buckets) == 0 || stats.Buckets[len(stats.Buckets)-1].Min != lo {
+			stats.Buckets = append(stats.Buckets, CoverBucket{Min: lo, Max: hi})
+		}
+		stats.Buckets[len(stats.Buckets)-1].Programs++
+	}
+	return stats
+}
+
+func printText(w io.Writer, rep *Report) {
+	fmt.Fprintf(w, "programs:   %v\n", rep.Programs)
+	fmt.Fprintf(w, "dead:       %v (unparsable: %v)\n", rep.Dead, rep.Unparsable)
+	fmt.Fprintf(w, "length:     avg %.1f, max %v\n", rep.AvgLength, rep.MaxLength)
+	fmt.Fprintf(w, "duplicates: %v (same syscalls: %v)\n", rep.Duplicates, rep.SameCalls)
+	if c := rep.Cover; c != nil {
+		fmt.Fprintf(w, "\ncoverage of %v programs: total %v, min %v, median %v, p90 %v, max %v\n",
+			c.Programs, c.Total, c.Min, c.Median, c.P90, c.Max)
+		fmt.Fprintf(w, "top 10%% of programs have %.1f%% of the total coverage\n", c.Top10Percent)
+		for _, b := range c.Buckets {
+			fmt.Fprintf(w, "%8v-%-8v %v\n", b.Min, b.Max, b.Programs)
+		}
+	}
+	fmt.Fprintf(w, "\n%-50v %10v %10v %10v %10v\n", "SYSCALL", "PROGRAMS", "CALLS", "DEAD", "AVG COVER")
+	for _, s := range rep.Syscalls {
+		fmt.Fprintf(w, "%-50v %10v %10v %10v %10.1f\n", s.Name, s.Programs, s.Calls, s.Dead, s.AvgCover)
+	}
+}
+
+func printCSV(w io.Writer, rep *Report) error {
+	avgCover := 0.0
+	if rep.Cover != nil {
+		avgCover = float64(rep.Cover.Total) / float64(rep.Cover.Programs)
+	}
+	rows := [][]string{
+		{"syscall", "programs", "calls", "dead", "avg_cover"},
+		{"*", fmt.Sprint(rep.Programs), fmt.Sprint(rep.Calls), fmt.Sprint(rep.Dead), fmt.Sprintf("%.1f", avgCover)},
+	}
+	for _, s := range rep.Syscalls {
+		rows = append(rows, []string{s.Name, fmt.Sprint(s.Programs), fmt.Sprint(s.Calls),
+			fmt.Sprint(s.Dead), fmt.Sprintf("%.1f", s.AvgCover)})
+	}
+	return csv.NewWriter(w).WriteAll(rows)
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/fuzzer"
 	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/tool"
@@ -67,6 +69,16 @@ func main() {
 			usage()
 		}
 		merge(args[1], args[2:], target)
+	case "export-blobs":
+		if len(args) != 4 || target == nil {
+			usage()
+		}
+		exportBlobs(args[1], args[2], args[3], target)
+	case "import-blobs":
+		if len(args) != 4 || target == nil {
+			usage()
+		}
+		importBlobs(args[1], args[2], args[3], target)
 	default:
 		usage()
 	}
@@ -90,6 +102,15 @@ offered:
     syz-db merge dst-corpus.db add-corpus.db* add-prog*
   running a deserialization benchmark:
     syz-db bench corpus.db
+  exporting blob arguments of the call to a libFuzzer/AFL-style corpus dir (requires -os/-arch):
+    syz-db export-blobs corpus.db syz_mount_image$ext4 dir
+  adding programs that invoke the call with blobs from the dir to the database (requires -os/-arch),
+  programs with the call that are already present in the database are used as templates:
+    syz-db import-blobs dir syz_mount_image$ext4 corpus.db
+
+Databases written by this version store big data blobs shared by programs once (format version 4),
+older syz-db/syz-manager binaries can't open them. To convert a database for an older binary,
+unpack it with this version and pack the result with the older one.
 `)
 	os.Exit(1)
 }
@@ -180,6 +201,42 @@ func merge(file string, adds []string, target *prog.Target) {
 	}
 }
 
+func exportBlobs(file, call, dir string, target *prog.Target) {
+	progs, err := db.ReadCorpus(file, target)
+	if err != nil {
+		tool.Fail(err)
+	}
+	n, err := fuzzer.ExportBlobs(progs, call, dir)
+	if err != nil {
+		tool.Failf("failed to export blobs: %v", err)
+	}
+	fmt.Printf("exported %v blobs\n", n)
+}
+
+func importBlobs(dir, call, file string, target *prog.Target) {
+	// Programs already present in the database serve as templates for the new programs.
+	templates, err := db.ReadCorpus(file, target)
+	if err != nil {
+		tool.Fail(err)
+	}
+	progs, err := fuzzer.ImportBlobs(target, call, dir, templates, rand.NewSource(time.Now().UnixNano()))
+	if err != nil {
+		tool.Failf("failed to import blobs: %v", err)
+	}
+	corpusDB, err := db.Open(file, false)
+	if err != nil {
+		tool.Failf("failed to open database: %v", err)
+	}
+	for _, p := range progs {
+		data := p.Serialize()
+		corpusDB.Save(hash.String(data), data, 0)
+	}
+	if err := corpusDB.Flush(); err != nil {
+		tool.Failf("failed to save db: %v", err)
+	}
+	fmt.Printf("imported %v blobs\n", len(progs))
+}
+
 func bench(target *prog.Target, file string) {
 	start := time.Now()
 	db, err := db.Open(file, false)
@@ -205,6 +262,9 @@ func bench(target *prog.Target, file string) {
 		stats.Alloc>>20,
 		(stats.Mallocs-stats.Frees)>>20,
 		time.Since(start))
+	blobs, blobSize, refSize := db.BlobStats()
+	fmt.Printf("records %v, shared blobs %v (%v MB, %v MB before deduplication)\n",
+		len(db.Records), blobs, blobSize>>20, refSize>>20)
 	sink = corpus
 	_ = sink
 }
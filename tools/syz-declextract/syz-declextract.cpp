@@ -9,6 +9,13 @@
 //   +target_link_libraries(syz-declextract clangTooling)
 // It was used to extract windows descriptions:
 //   syz-declextract -extra-arg="--driver-mode=cl" -extra-arg="-I/path/to/windows/headers" Windows.h
+// The output can be post-processed with syz-resextract to replace plain HANDLEs with named resources.
+// With -os=fuchsia it extracts Zircon system calls (zx_* functions) from the C bindings instead:
+//   syz-declextract -os=fuchsia -extra-arg="-I$FUCHSIA/zircon/system/public" \
+//     -extra-arg="-I/path/to/generated/zircon/headers" zircon/syscalls.h
+// (zircon/syscalls.h includes headers generated during the Fuchsia build).
+// For Zircon the FIDL definitions processed by syz-zirconextract give better results
+// (argument directions, handle types), the C bindings are useful for calls that don't have FIDL definitions.
 
 #include "clang/AST/AST.h"
 #include "clang/AST/ASTConsumer.h"
@@ -25,10 +32,22 @@
 using namespace clang;
 using namespace clang::tooling;
 
+static llvm::cl::OptionCategory MyToolCategory("my-tool options");
+static llvm::cl::opt<std::string> TargetOS("os", llvm::cl::desc("target OS (windows or fuchsia)"),
+                                           llvm::cl::init("windows"), llvm::cl::cat(MyToolCategory));
+
 std::string convertType(ASTContext &C, QualType T) {
   auto name = T.getAsString();
   if (name == "HANDLE")
     return name;
+  if (TargetOS == "fuchsia") {
+    if (name == "zx_handle_t")
+      return "zx_handle";
+    if (name == "zx_time_t")
+      return "zx_time";
+    if (name == "zx_vaddr_t")
+      return "zx_vaddr";
+  }
   if (T->isIntegralOrEnumerationType()) {
     int size = C.getTypeSize(T);
     char buf[10];
@@ -39,11 +58,13 @@ std::string convertType(ASTContext &C, QualType T) {
     return "ptr[inout, array[int8]]";
   }
   if (T->isPointerType()) {
+    // Windows headers don't reliably mark input pointers as const, so all pointers are inout there.
+    auto dir = TargetOS == "fuchsia" && T->getPointeeType().isConstQualified() ? "in" : "inout";
     auto inner = convertType(C, T->getPointeeType());
     if (inner == "")
-      return "ptr[inout, array[int8]]";
+      inner = "array[int8]";
     char buf[1024];
-    sprintf(buf, "ptr[inout, %s]", inner.c_str());
+    sprintf(buf, "ptr[%s, %s]", dir, inner.c_str());
     return buf;
   }
   return "intptr";
@@ -57,6 +78,56 @@ class DeclExtractCallVisitor : public RecursiveASTVisitor<DeclExtractCallVisitor
   bool VisitFunctionDecl(const FunctionDecl *D) {
     if (D->doesThisDeclarationHaveABody())
       return true;
+    auto fn = D->getNameInfo().getAsString();
+    if (fn.empty()) return true;
+    if (TargetOS == "fuchsia" ? ignoreFuchsia(D, fn) : ignoreWindows(D, fn))
+      return true;
+    for (const ParmVarDecl *P : D->parameters()) {
+      auto typ = convertType(Context, P->getType());
+      if (typ == "") {
+        llvm::outs() << D->getNameInfo().getAsString() << ": UNKNOWN TYPE: " <<
+            QualType(P->getType()).getAsString() << "\n";
+        return true;
+      }
+    }
+    if (Generated[D->getNameInfo().getAsString()])
+      return true;
+    Generated[D->getNameInfo().getAsString()] = true;
+
+    llvm::outs() << D->getNameInfo().getAsString() << "(";
+    int i = 0;
+    for (const ParmVarDecl *P : D->parameters()) {
+      if (i)
+        llvm::outs() << ", ";
+      auto name = P->getNameAsString();
+      if (name == "") {
+        char buf[10];
+        sprintf(buf, "arg%d", i);
+        name = buf;
+      }
+      llvm::outs() << name << " " << convertType(Context, P->getType());
+      i++;
+      if (i == 9)
+        break;
+    }
+    llvm::outs() << ")";
+    auto ret = convertType(Context, D->getReturnType());
+    if (ret == "HANDLE")
+      llvm::outs() << " " << ret;
+    llvm::outs() << "\n";
+    return true;
+  }
+
+ private:
+  // Only the public zx_* entry points are extracted, _zx_* aliases and vdso-internal functions are skipped.
+  bool ignoreFuchsia(const FunctionDecl *D, const std::string &fn) {
+    if (fn.compare(0, 3, "zx_") != 0)
+      return true;
+    auto src = D->getSourceRange().getBegin().printToString(Context.getSourceManager());
+    return strstr(src.c_str(), "zircon/") == 0;
+  }
+
+  bool ignoreWindows(const FunctionDecl *D, const std::string &fn) {
     // TODO(dvyukov): need to select only stdcall (WINAPI) functions.
     // But the following 2 approaches do not work.
     if (false) {
@@ -71,8 +142,6 @@ class DeclExtractCallVisitor : public RecursiveASTVisitor<DeclExtractCallVisitor
     }
     // Tons of functions are bulk ignored below because they cause
     // static/dynamic link failures, reboot machine, etc.
-    auto fn = D->getNameInfo().getAsString();
-    if (fn.empty()) return true;
     if (*fn.rbegin() == 'W') return true; // Unicode versions.
     const char *ignore_prefixes[] {
       "_",
@@ -204,43 +273,9 @@ class DeclExtractCallVisitor : public RecursiveASTVisitor<DeclExtractCallVisitor
     for (auto file: ignore_files) {
       if (strstr(src.c_str(), file)) return true;
     }
-    for (const ParmVarDecl *P : D->parameters()) {
-      auto typ = convertType(Context, P->getType());
-      if (typ == "") {
-        llvm::outs() << D->getNameInfo().getAsString() << ": UNKNOWN TYPE: " <<
-            QualType(P->getType()).getAsString() << "\n";
-        return true;
-      }
-    }
-    if (Generated[D->getNameInfo().getAsString()])
-      return true;
-    Generated[D->getNameInfo().getAsString()] = true;
-
-    llvm::outs() << D->getNameInfo().getAsString() << "(";
-    int i = 0;
-    for (const ParmVarDecl *P : D->parameters()) {
-      if (i)
-        llvm::outs() << ", ";
-      auto name = P->getNameAsString();
-      if (name == "") {
-        char buf[10];
-        sprintf(buf, "arg%d", i);
-        name = buf;
-      }
-      llvm::outs() << name << " " << convertType(Context, P->getType());
-      i++;
-      if (i == 9)
-        break;
-    }
-    llvm::outs() << ")";
-    auto ret = convertType(Context, D->getReturnType());
-    if (ret == "HANDLE")
-      llvm::outs() << " " << ret;
-    llvm::outs() << "\n";
-    return true;
+    return false;
   }
 
- private:
   ASTContext &Context;
   std::map<std::string, bool> Generated;
 };
@@ -269,8 +304,6 @@ class DeclExtractCallAction : public clang::ASTFrontendAction {
   }
 };
 
-static llvm::cl::OptionCategory MyToolCategory("my-tool options");
-
 int main(int argc, const char **argv) {
   CommonOptionsParser OptionsParser(argc, argv, MyToolCategory);
   ClangTool Tool(OptionsParser.getCompilations(),
@@ -0,0 +1,115 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/symbolizer"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// coverDiff collects coverage of two sets of programs (A and B) to report coverage
+// that is reached only by set B. This is useful to evaluate if new descriptions
+// or an imported corpus add anything on top of what we already have.
+type coverDiff struct {
+	mu sync.Mutex
+	// Programs with indices below sizeA belong to set A, the rest belong to set B.
+	sizeA int
+	covA  map[uint64]bool
+	covB  map[uint64]bool
+}
+
+func newCoverDiff(sizeA int) *coverDiff {
+	return &coverDiff{
+		sizeA: sizeA,
+		covA:  make(map[uint64]bool),
+		covB:  make(map[uint64]bool),
+	}
+}
+
+func (cd *coverDiff) add(progIndex int, info *flatrpc.ProgInfo) {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	cov := cd.covB
+	if progIndex < cd.sizeA {
+		cov = cd.covA
+	}
+	addCall := func(inf *flatrpc.CallInfo) {
+		if inf == nil {
+			return
+		}
+		for _, pc := range inf.Cover {
+			cov[pc] = true
+		}
+	}
+	for _, inf := range info.Calls {
+		addCall(inf)
+	}
+	addCall(info.Extra)
+}
+
+// report writes PCs covered by set B but not by set A.
+// If vmlinux is not empty, the PCs are symbolized and grouped by function.
+func (cd *coverDiff) report(w io.Writer, target *targets.Target, vmlinux string) error {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	var pcs []uint64
+	for pc := range cd.covB {
+		if !cd.covA[pc] {
+			pcs = append(pcs, backend.PreviousInstructionPC(target, "", pc))
+		}
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	fmt.Fprintf(w, "coverage: set A %v PCs, set B %v PCs, only in set B %v PCs\n",
+		len(cd.covA), len(cd.covB), len(pcs))
+	if vmlinux == "" || len(pcs) == 0 {
+		for _, pc := range pcs {
+			fmt.Fprintf(w, "0x%x\n", pc)
+		}
+		return nil
+	}
+	symb := symbolizer.NewSymbolizer(target)
+	defer symb.Close()
+	frames, err := symb.SymbolizeArray(vmlinux, pcs)
+	if err != nil {
+		return fmt.Errorf("failed to symbolize coverage: %w", err)
+	}
+	// Inlined frames go first, so the first frame for a PC is the most precise one.
+	pcFrames := make(map[uint64]symbolizer.Frame)
+	for _, frame := range frames {
+		if _, ok := pcFrames[frame.PC]; !ok {
+			pcFrames[frame.PC] = frame
+		}
+	}
+	funcs := make(map[string]int)
+	for _, pc := range pcs {
+		funcs[pcFrames[pc].Func]++
+	}
+	var funcNames []string
+	for fn := range funcs {
+		funcNames = append(funcNames, fn)
+	}
+	sort.Slice(funcNames, func(i, j int) bool {
+		if funcs[funcNames[i]] != funcs[funcNames[j]] {
+			return funcs[funcNames[i]] > funcs[funcNames[j]]
+		}
+		return funcNames[i] < funcNames[j]
+	})
+	fmt.Fprintf(w, "\nfunctions with new coverage:\n")
+	for _, fn := range funcNames {
+		fmt.Fprintf(w, "%6v %v\n", funcs[fn], fn)
+	}
+	fmt.Fprintf(w, "\nnew PCs:\n")
+	for _, pc := range pcs {
+		frame := pcFrames[pc]
+		fmt.Fprintf(w, "0x%x %v %v:%v\n", pc, frame.Func, frame.File, frame.Line)
+	}
+	return nil
+}
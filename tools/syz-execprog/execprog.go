@@ -46,6 +46,13 @@ var (
 	flagEnable    = flag.String("enable", "none", "enable only listed additional features")
 	flagDisable   = flag.String("disable", "none", "enable all additional features except listed")
 
+	// The coverage diff mode executes two sets of programs: set A given as arguments and set B given
+	// in -coverdiff, and prints coverage that is reached by set B, but not by set A.
+	// It can be used to evaluate if new descriptions or an imported corpus add any new coverage.
+	flagCoverDiff = flag.String("coverdiff", "", "comma-separated list of files with programs (set B) "+
+		"to print coverage reached by them but not by programs given as arguments")
+	flagVmlinux = flag.String("vmlinux", "", "kernel object file to symbolize -coverdiff output (optional)")
+
 	// The in the stress mode resembles simple unguided fuzzer.
 	// This mode can be used as an intermediate step when porting syzkaller to a new OS,
 	// or when testing on a machine that is not supported by the vm package (as syz-manager cannot be used).
@@ -93,6 +100,14 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
+	var coverDiff *coverDiff
+	if *flagCoverDiff != "" {
+		if *flagStress {
+			log.Fatalf("-coverdiff can't be used with -stress")
+		}
+		coverDiff = newCoverDiff(len(progs))
+		progs = append(progs, loadPrograms(target, strings.Split(*flagCoverDiff, ","))...)
+	}
 	if *flagCollide {
 		log.Logf(0, "note: setting -collide to true is deprecated now and has no effect")
 	}
@@ -134,6 +149,7 @@ func main() {
 		stress:      *flagStress,
 		repeat:      *flagRepeat,
 		sysTarget:   sysTarget,
+		coverDiff:   coverDiff,
 	}
 	var wg sync.WaitGroup
 	wg.Add(*flagProcs)
@@ -146,6 +162,11 @@ func main() {
 	}
 	osutil.HandleInterrupts(ctx.shutdown)
 	wg.Wait()
+	if coverDiff != nil {
+		if err := coverDiff.report(os.Stdout, sysTarget, *flagVmlinux); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 type Context struct {
@@ -163,6 +184,7 @@ type Context struct {
 	pos         int
 	lastPrint   time.Time
 	sysTarget   *targets.Target
+	coverDiff   *coverDiff
 }
 
 func (ctx *Context) run(pid int) {
@@ -232,6 +254,9 @@ func (ctx *Context) execute(pid int, env *ipc.Env, p *prog.Prog, progIndex int)
 				covFile := fmt.Sprintf("%s_prog%d", *flagCoverFile, progIndex)
 				ctx.dumpCoverage(covFile, info)
 			}
+			if ctx.coverDiff != nil {
+				ctx.coverDiff.add(progIndex%len(ctx.progs), info)
+			}
 		} else {
 			log.Logf(1, "RESULT: no calls executed")
 		}
@@ -261,6 +286,12 @@ func (ctx *Context) printCallResults(info *flatrpc.ProgInfo) {
 		if inf.Flags&flatrpc.CallFlagFaultInjected != 0 {
 			flags += " faulted"
 		}
+		if inf.Flags&flatrpc.CallFlagKernelWarning != 0 {
+			flags += " warned"
+		}
+		if inf.Flags&flatrpc.CallFlagRaceCandidate != 0 {
+			flags += " race"
+		}
 		log.Logf(1, "CALL %v: signal %v, coverage %v errno %v%v",
 			i, len(inf.Signal), len(inf.Cover), inf.Error, flags)
 	}
@@ -371,11 +402,15 @@ func createConfig(target *prog.Target, featuresFlags csource.Features, syscalls
 	if execOpts.EnvFlags&flatrpc.ExecEnvSignal != 0 {
 		execOpts.ExecFlags |= flatrpc.ExecFlagCollectCover
 	}
-	if *flagCoverFile != "" {
+	if *flagCoverFile != "" || *flagCoverDiff != "" {
 		execOpts.EnvFlags |= flatrpc.ExecEnvSignal
 		execOpts.ExecFlags |= flatrpc.ExecFlagCollectCover
 		execOpts.ExecFlags &^= flatrpc.ExecFlagDedupCover
 	}
+	if *flagProcs == 1 {
+		// CLOCK_REALTIME time jumps are VM-wide, so they are allowed only with a single proc.
+		execOpts.ExecFlags |= flatrpc.ExecFlagRealtimeJumps
+	}
 	if *flagHints {
 		if execOpts.ExecFlags&flatrpc.ExecFlagCollectCover != 0 {
 			execOpts.ExecFlags ^= flatrpc.ExecFlagCollectCover
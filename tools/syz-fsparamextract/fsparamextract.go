@@ -0,0 +1,102 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-fsparamextract generates mount/fsopen/fsconfig descriptions that enumerate valid mount options
+// of file systems from their fs_parameter_spec tables in the kernel sources.
+// File systems that already have described options (NAME_options type in sys/linux) are skipped.
+// Usage:
+//
+//	syz-fsparamextract -src $KERNEL -out sys/linux/fs_auto.txt
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/google/syzkaller/pkg/declextract"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var (
+	flagSrc  = flag.String("src", "", "kernel source dir")
+	flagOut  = flag.String("out", "", "output file for descriptions (stdout if empty)")
+	flagAll  = flag.Bool("all", false, "generate descriptions for file systems with already described options as well")
+	flagArch = flag.String("arch", targets.AMD64, "arch used to find already described file systems")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagSrc == "" {
+		tool.Failf("-src is required")
+	}
+	files, err := readSources(*flagSrc)
+	if err != nil {
+		tool.Fail(err)
+	}
+	fss := declextract.ExtractFsParams(files)
+	if !*flagAll {
+		target, err := prog.GetTarget(targets.Linux, *flagArch)
+		if err != nil {
+			tool.Fail(err)
+		}
+		fss = skipDescribed(target, fss)
+	}
+	desc := declextract.SerializeFsParams(fss)
+	if *flagOut == "" {
+		os.Stdout.Write(desc)
+	} else if err := osutil.WriteFile(*flagOut, desc); err != nil {
+		tool.Fail(err)
+	}
+}
+
+// readSources reads all .c files that may contain file system types or their parameter tables
+// (file systems are not only in fs/, e.g. mm/shmem.c or kernel/cgroup).
+func readSources(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	markers := [][]byte{[]byte("file_system_type"), []byte("fs_parameter_spec"), []byte("constant_table")}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".c" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, marker := range markers {
+			if bytes.Contains(data, marker) {
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					return err
+				}
+				files[filepath.ToSlash(rel)] = data
+				break
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// skipDescribed drops file systems that already have the NAME_options type.
+func skipDescribed(target *prog.Target, fss []*declextract.FileSystem) []*declextract.FileSystem {
+	described := make(map[string]bool)
+	prog.ForeachType(target.Syscalls, func(typ prog.Type, ctx *prog.TypeCtx) {
+		described[typ.Name()] = true
+	})
+	var res []*declextract.FileSystem
+	for _, fs := range fss {
+		if described[fs.Name+"_options"] {
+			log.Logf(1, "skipping already described %v", fs.Name)
+			continue
+		}
+		res = append(res, fs)
+	}
+	return res
+}
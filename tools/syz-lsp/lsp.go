@@ -0,0 +1,351 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-lsp is a language server for syzlang descriptions (sys/*/*.txt).
+// It provides diagnostics (parsing and compilation errors and warnings), go-to-definition
+// for types, resources, flags and consts (including consts from .const files), and find-references.
+// All .txt files in the directory of the edited file are treated as one description,
+// and the OS is inferred from the directory name (sys/linux, sys/freebsd, etc).
+// The server communicates over stdin/stdout, e.g. for VS Code or Neovim configure it
+// as the language server command for *.txt files in the syzkaller checkout.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var flagArch = flag.String("arch", targets.AMD64, "arch to compile descriptions for (if supported by the OS)")
+
+func main() {
+	flag.Parse()
+	// Note: stdout is used for the protocol, logs go to stderr.
+	srv := newServer(newConn(os.Stdin, os.Stdout), *flagArch)
+	if err := srv.serve(); err != nil && err != io.EOF {
+		log.Fatal(err)
+	}
+}
+
+type server struct {
+	conn *conn
+	arch string
+	// Contents of the files open in the editor (can be different from the files on disk).
+	docs map[string][]byte
+	// Files for which we published non-empty diagnostics last time.
+	diagnosed map[string]bool
+}
+
+func newServer(conn *conn, arch string) *server {
+	return &server{
+		conn:      conn,
+		arch:      arch,
+		docs:      make(map[string][]byte),
+		diagnosed: make(map[string]bool),
+	}
+}
+
+func (srv *server) serve() error {
+	for {
+		msg, err := srv.conn.read()
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		result, rerr := srv.handle(msg)
+		if msg.ID == nil {
+			// Notifications don't have responses.
+			if rerr != nil {
+				log.Logf(0, "%v: %v", msg.Method, rerr.Message)
+			}
+			continue
+		}
+		resp := &message{ID: msg.ID, Result: result, Error: rerr}
+		if rerr == nil && result == nil {
+			resp.Result = json.RawMessage("null")
+		}
+		if err := srv.conn.write(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (srv *server) handle(msg *message) (interface{}, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"change":    1, // full document sync
+					"save":      true,
+				},
+				"definitionProvider": true,
+				"referencesProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "syz-lsp"},
+		}, nil
+	case "initialized", "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		params := new(didOpenParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+		file := uriToPath(params.TextDocument.URI)
+		srv.docs[file] = []byte(params.TextDocument.Text)
+		srv.diagnose(filepath.Dir(file), true)
+	case "textDocument/didChange":
+		params := new(didChangeParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		file := uriToPath(params.TextDocument.URI)
+		srv.docs[file] = []byte(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		// Compilation of a whole OS takes a while, so on changes we only check syntax.
+		srv.diagnose(filepath.Dir(file), false)
+	case "textDocument/didSave":
+		params := new(didCloseParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+		srv.diagnose(filepath.Dir(uriToPath(params.TextDocument.URI)), true)
+	case "textDocument/didClose":
+		params := new(didCloseParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(srv.docs, uriToPath(params.TextDocument.URI))
+	case "textDocument/definition", "textDocument/references":
+		params := new(positionParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+		file := uriToPath(params.TextDocument.URI)
+		pos := ast.Pos{File: file, Line: params.Position.Line + 1, Col: params.Position.Character + 1}
+		if msg.Method == "textDocument/definition" {
+			return srv.definition(pos), nil
+		}
+		return srv.references(pos, params.Context.IncludeDeclaration), nil
+	default:
+		if msg.ID != nil {
+			return nil, &responseError{Code: errMethodNotFound, Message: "unsupported method " + msg.Method}
+		}
+	}
+	return nil, nil
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: errInvalidParams, Message: err.Error()}
+}
+
+// parse parses all descriptions in dir taking into account the files open in the editor.
+func (srv *server) parse(dir string, eh ast.ErrorHandler) *ast.Description {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	desc := new(ast.Description)
+	for _, file := range files {
+		data, ok := srv.docs[file]
+		if !ok {
+			var err error
+			if data, err = os.ReadFile(file); err != nil {
+				eh(ast.Pos{File: file}, fmt.Sprintf("failed to read file: %v", err))
+				continue
+			}
+		}
+		if desc1 := ast.Parse(data, file, eh); desc1 != nil {
+			desc.Nodes = append(desc.Nodes, desc1.Nodes...)
+		}
+	}
+	return desc
+}
+
+func (srv *server) diagnose(dir string, compile bool) {
+	diags := make(map[string][]diagnostic)
+	var warnings map[string]bool
+	eh := func(pos ast.Pos, msg string) {
+		severity := severityError
+		if warnings[pos.String()+msg] {
+			severity = severityWarning
+		}
+		if pos.File == "" || !strings.HasPrefix(pos.File, dir) {
+			// Errors in builtin descriptions, or in files that can't be attributed.
+			log.Logf(0, "%v: %v", pos, msg)
+			return
+		}
+		diags[pos.File] = append(diags[pos.File], diagnostic{
+			Range:    posRange(pos, 1),
+			Severity: severity,
+			Source:   "syz-lsp",
+			Message:  msg,
+		})
+	}
+	desc := srv.parse(dir, eh)
+	target := targets.Get(filepath.Base(dir), srv.arch)
+	if target == nil {
+		// Use any arch the OS supports.
+		for _, target1 := range targets.List[filepath.Base(dir)] {
+			if target == nil || target1.Arch < target.Arch {
+				target = target1
+			}
+		}
+	}
+	if compile && len(diags) == 0 && target != nil {
+		constFile := compiler.DeserializeConstFile(filepath.Join(dir, "*.const"), eh)
+		if constFile != nil {
+			constFile.AddExternal(desc, []string{target.Arch}, eh)
+			if err := constFile.AddSyscallTable(target, dir); err != nil {
+				eh(ast.Pos{}, err.Error())
+			}
+		}
+		consts := constFile.Arch(target.Arch)
+		// The compiler reports warnings via the error handler as well,
+		// so we need to know the warnings before attributing the messages.
+		type compileMsg struct {
+			pos ast.Pos
+			msg string
+		}
+		var msgs []compileMsg
+		prg := compiler.Compile(desc, consts, target, func(pos ast.Pos, msg string) {
+			msgs = append(msgs, compileMsg{pos, msg})
+		})
+		if prg != nil {
+			warnings = make(map[string]bool)
+			for _, w := range prg.Warnings {
+				warnings[w.Pos.String()+w.Msg] = true
+			}
+		}
+		for _, m := range msgs {
+			eh(m.pos, m.msg)
+		}
+	}
+	for file := range srv.diagnosed {
+		if filepath.Dir(file) == dir && diags[file] == nil {
+			srv.publish(file, nil)
+		}
+	}
+	for file, list := range diags {
+		srv.publish(file, list)
+	}
+}
+
+func (srv *server) publish(file string, diags []diagnostic) {
+	if diags == nil {
+		diags = []diagnostic{}
+		delete(srv.diagnosed, file)
+	} else {
+		srv.diagnosed[file] = true
+	}
+	err := srv.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+		URI:         pathToURI(file),
+		Diagnostics: diags,
+	})
+	if err != nil {
+		log.Logf(0, "failed to publish diagnostics: %v", err)
+	}
+}
+
+func (srv *server) symbolAt(pos ast.Pos) ([]ast.Symbol, ast.Symbol, bool) {
+	desc := srv.parse(filepath.Dir(pos.File), func(ast.Pos, string) {})
+	syms := desc.Symbols()
+	sym, ok := ast.SymbolAt(syms, pos)
+	return syms, sym, ok
+}
+
+func (srv *server) definition(pos ast.Pos) []location {
+	locs := []location{}
+	syms, sym, ok := srv.symbolAt(pos)
+	if !ok {
+		return locs
+	}
+	for _, def := range syms {
+		if def.Def && def.Name == sym.Name {
+			locs = append(locs, symbolLocation(def))
+		}
+	}
+	if len(locs) == 0 {
+		locs = append(locs, constLocations(filepath.Dir(pos.File), sym.Name)...)
+	}
+	return locs
+}
+
+func (srv *server) references(pos ast.Pos, includeDecl bool) []location {
+	locs := []location{}
+	syms, sym, ok := srv.symbolAt(pos)
+	if !ok {
+		return locs
+	}
+	for _, ref := range syms {
+		if ref.Name == sym.Name && (includeDecl || !ref.Def) {
+			locs = append(locs, symbolLocation(ref))
+		}
+	}
+	return locs
+}
+
+// constLocations finds the const in the .const files in dir.
+func constLocations(dir, name string) []location {
+	var locs []location
+	files, _ := filepath.Glob(filepath.Join(dir, "*.const"))
+	sort.Strings(files)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		s := bufio.NewScanner(f)
+		for line := 1; s.Scan(); line++ {
+			if strings.HasPrefix(s.Text(), name+" = ") {
+				locs = append(locs, symbolLocation(ast.Symbol{
+					Name: name,
+					Pos:  ast.Pos{File: file, Line: line, Col: 1},
+				}))
+				break
+			}
+		}
+		f.Close()
+	}
+	return locs
+}
+
+func symbolLocation(sym ast.Symbol) location {
+	return location{
+		URI:   pathToURI(sym.Pos.File),
+		Range: posRange(sym.Pos, len(sym.Name)),
+	}
+}
+
+func posRange(pos ast.Pos, size int) lspRange {
+	start := position{Line: max(pos.Line-1, 0), Character: max(pos.Col-1, 0)}
+	end := start
+	end.Character += size
+	return lspRange{Start: start, End: end}
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(file string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(file)}).String()
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	foo := filepath.Join(dir, "foo.txt")
+	bar := filepath.Join(dir, "bar.txt")
+	assert.NoError(t, osutil.WriteFile(foo, []byte("foo(arg ptr[in, bar_struct], flags flags[bar_flags])\n")))
+	assert.NoError(t, osutil.WriteFile(bar, []byte(
+		"bar_struct {\n\tf0\tint32[0:BAR_MAX]\n}\nbar_flags = BAR_A, BAR_B\nbar2(arg ptr[in, bar_struct])\n")))
+	assert.NoError(t, osutil.WriteFile(filepath.Join(dir, "bar.txt.const"), []byte(
+		"# Code generated by syz-sysgen. DO NOT EDIT.\narches = amd64\nBAR_A = 1\nBAR_B = 2\nBAR_MAX = 10\n")))
+
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	srv := newServer(newConn(serverR, serverW), "amd64")
+	done := make(chan error)
+	go func() {
+		done <- srv.serve()
+	}()
+	client := newConn(clientR, clientW)
+	id := 0
+	call := func(method string, params interface{}) *message {
+		id++
+		data, err := json.Marshal(params)
+		assert.NoError(t, err)
+		rawID := json.RawMessage(fmt.Sprint(id))
+		assert.NoError(t, client.write(&message{ID: &rawID, Method: method, Params: data}))
+		for {
+			msg, err := client.read()
+			assert.NoError(t, err)
+			if msg.ID != nil {
+				return msg
+			}
+		}
+	}
+	position := func(file string, line, char int) map[string]interface{} {
+		return map[string]interface{}{
+			"textDocument": map[string]string{"uri": pathToURI(file)},
+			"position":     map[string]int{"line": line, "character": char},
+			"context":      map[string]bool{"includeDeclaration": true},
+		}
+	}
+	locations := func(msg *message) []string {
+		var locs []location
+		data, err := json.Marshal(msg.Result)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &locs))
+		var res []string
+		for _, loc := range locs {
+			res = append(res, filepath.Base(uriToPath(loc.URI))+":"+
+				fmt.Sprint(loc.Range.Start.Line+1)+":"+fmt.Sprint(loc.Range.Start.Character+1))
+		}
+		return res
+	}
+
+	resp := call("initialize", map[string]interface{}{})
+	assert.Nil(t, resp.Error)
+	// Struct definition in another file.
+	assert.Equal(t, []string{"bar.txt:1:1"}, locations(call("textDocument/definition", position(foo, 0, 20))))
+	// Const definition in the const file.
+	assert.Equal(t, []string{"bar.txt.const:5:1"}, locations(call("textDocument/definition", position(bar, 1, 12))))
+	// References across files.
+	assert.Equal(t, []string{"bar.txt:1:1", "bar.txt:5:18", "foo.txt:1:17"},
+		locations(call("textDocument/references", position(bar, 0, 3))))
+	assert.Empty(t, locations(call("textDocument/definition", position(foo, 0, 1000))))
+
+	// Syntax errors in the unsaved document are reported.
+	data, err := json.Marshal(map[string]interface{}{
+		"textDocument":   map[string]string{"uri": pathToURI(foo)},
+		"contentChanges": []map[string]string{{"text": "foo(arg ptr[in, bar_struct\n"}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, client.write(&message{Method: "textDocument/didChange", Params: data}))
+	msg, err := client.read()
+	assert.NoError(t, err)
+	assert.Equal(t, "textDocument/publishDiagnostics", msg.Method)
+	diags := new(publishDiagnosticsParams)
+	assert.NoError(t, json.Unmarshal(msg.Params, diags))
+	assert.Equal(t, pathToURI(foo), diags.URI)
+	assert.Len(t, diags.Diagnostics, 1)
+	assert.Equal(t, severityError, diags.Diagnostics[0].Severity)
+
+	resp = call("unknown/method", nil)
+	assert.Equal(t, errMethodNotFound, resp.Error.Code)
+	assert.NoError(t, client.write(&message{Method: "exit"}))
+	assert.NoError(t, <-done)
+}
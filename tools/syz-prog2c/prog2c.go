@@ -92,6 +92,8 @@ func main() {
 		IEEE802154:    features["ieee802154"].Enabled,
 		Sysctl:        features["sysctl"].Enabled,
 		Swap:          features["swap"].Enabled,
+		Hugepages:     features["hugepages"].Enabled,
+		Pmem:          features["pmem"].Enabled,
 		UseTmpDir:     *flagUseTmpDir,
 		HandleSegv:    *flagHandleSegv,
 		Trace:         *flagTrace,
@@ -75,12 +75,14 @@ func main() {
 		fmt.Printf("simplifying prog options: %v\n", stats.SimplifyProgTime)
 		fmt.Printf("extracting C: %v\n", stats.ExtractCTime)
 		fmt.Printf("simplifying C: %v\n", stats.SimplifyCTime)
+		fmt.Printf("minimizing C: %v\n", stats.MinimizeCTime)
+		fmt.Printf("measuring reliability: %v\n", stats.ReliabilityTime)
 	}
 	if res == nil {
 		return
 	}
 
-	fmt.Printf("opts: %+v crepro: %v\n\n", res.Opts, res.CRepro)
+	fmt.Printf("opts: %+v crepro: %v reliability: %v\n\n", res.Opts, res.CRepro, res.Reliability)
 
 	progSerialized := res.Prog.Serialize()
 	fmt.Printf("%s\n", progSerialized)
@@ -28,10 +28,17 @@ var (
 	flagOutputDir    = flag.String("output", "repros", "output dir")
 	flagSyzkallerDir = flag.String("syzkaller", ".", "syzkaller dir")
 	flagOS           = flag.String("os", runtime.GOOS, "target OS")
+	flagVerify       = flag.String("verify", "", "manager config; if set, reproducers in the output dir are "+
+		"run against the kernel from the config instead of being downloaded")
+	flagVerifyTime = flag.Duration("verify_time", 5*time.Minute, "how long to run every reproducer in -verify mode")
 )
 
 func main() {
 	flag.Parse()
+	if *flagVerify != "" {
+		verify(*flagVerify)
+		return
+	}
 	clients := strings.Split(*flagAPIClients, ",")
 	if len(clients) == 0 {
 		log.Fatalf("api client is required")
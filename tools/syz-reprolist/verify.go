@@ -0,0 +1,245 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm"
+)
+
+// Verification mode runs all reproducers stored in the output dir against the kernel
+// specified in the manager config (one reproducer per VM, in parallel) and reports
+// which of them still reproduce. It's intended for validation of stable backport batches
+// and release candidates against the set of bugs exported from the dashboard by this tool.
+// Crash dirs of syz-manager (crashes/*/repro.prog) are accepted as well.
+
+type verifyRepro struct {
+	ID    string
+	Title string // the original crash title, if known
+	Syz   []byte
+	Opts  csource.Options
+	C     []byte
+}
+
+type verifyResult struct {
+	repro *verifyRepro
+	title string // title of the crash on the tested kernel
+	err   error
+}
+
+func verify(cfgFile string) {
+	cfg, err := mgrconfig.LoadFile(cfgFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	repros, err := loadRepros(*flagOutputDir, csource.DefaultOpts(cfg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(repros) == 0 {
+		log.Fatalf("no reproducers found in %v", *flagOutputDir)
+	}
+	vmPool, err := vm.Create(cfg, false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	shutdown := make(chan struct{})
+	osutil.HandleInterrupts(shutdown)
+	go func() {
+		<-shutdown
+		close(vm.Shutdown)
+	}()
+	log.Printf("verifying %v reproducers on %v VMs", len(repros), vmPool.Count())
+	reproC := make(chan *verifyRepro, len(repros))
+	for _, repro := range repros {
+		reproC <- repro
+	}
+	close(reproC)
+	resC := make(chan *verifyResult, len(repros))
+	var wg sync.WaitGroup
+	for i := 0; i < vmPool.Count(); i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			for repro := range reproC {
+				res := verifyOne(cfg, reporter, vmPool, index, repro)
+				log.Printf("vm-%v: %v: %v", index, repro.ID, res)
+				resC <- res
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(resC)
+	var results []*verifyResult
+	for res := range resC {
+		results = append(results, res)
+	}
+	printVerifyResults(results)
+}
+
+func verifyOne(cfg *mgrconfig.Config, reporter *report.Reporter, vmPool *vm.Pool, index int,
+	repro *verifyRepro) *verifyResult {
+	res := &verifyResult{repro: repro}
+	inst, err := instance.CreateExecProgInstance(vmPool, index, cfg, reporter, nil)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	defer inst.Close()
+	var run *instance.RunResult
+	if len(repro.Syz) != 0 {
+		run, err = inst.RunSyzProg(repro.Syz, *flagVerifyTime, repro.Opts, instance.SyzExitConditions)
+	} else {
+		run, err = inst.RunCProgRaw(repro.C, cfg.Target, *flagVerifyTime)
+	}
+	if err != nil {
+		res.err = err
+	} else if run.Report != nil {
+		res.title = run.Report.Title
+	}
+	return res
+}
+
+func (res *verifyResult) String() string {
+	switch {
+	case res.err != nil:
+		return fmt.Sprintf("failed: %v", res.err)
+	case res.title == "":
+		return "did not reproduce"
+	case res.repro.Title != "" && res.title != res.repro.Title:
+		return fmt.Sprintf("reproduced a different crash: %v", res.title)
+	default:
+		return fmt.Sprintf("reproduced: %v", res.title)
+	}
+}
+
+func printVerifyResults(results []*verifyResult) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].repro.ID < results[j].repro.ID
+	})
+	reproduced, failed := 0, 0
+	for _, res := range results {
+		if res.err != nil {
+			failed++
+		} else if res.title != "" {
+			reproduced++
+		}
+		title := res.repro.Title
+		if title == "" {
+			title = "<unknown>"
+		}
+		fmt.Printf("%v (%v): %v\n", res.repro.ID, title, res)
+	}
+	fmt.Printf("total %v reproducers: %v reproduced, %v did not reproduce, %v failed\n",
+		len(results), reproduced, len(results)-reproduced-failed, failed)
+}
+
+// loadRepros loads reproducers from the dir. For every bug ID the dir may contain:
+// ID.syz and/or ID.c files written by this tool (the first line of the C file contains the title),
+// or an ID/ subdir in the syz-manager crash dir format (description and repro.prog files).
+// Syz reproducers are preferred over C reproducers.
+func loadRepros(dir string, defaultOpts csource.Options) ([]*verifyRepro, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	repros := make(map[string]*verifyRepro)
+	get := func(id string) *verifyRepro {
+		if repros[id] == nil {
+			repros[id] = &verifyRepro{ID: id}
+		}
+		return repros[id]
+	}
+	for _, file := range files {
+		name := file.Name()
+		path := filepath.Join(dir, name)
+		ext := filepath.Ext(name)
+		id := strings.TrimSuffix(name, ext)
+		switch {
+		case file.IsDir():
+			if !osutil.IsExist(filepath.Join(path, "repro.prog")) {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(path, "repro.prog"))
+			if err != nil {
+				return nil, err
+			}
+			repro := get(name)
+			repro.Syz, repro.Opts = parseSyzRepro(data, defaultOpts)
+			if desc, err := os.ReadFile(filepath.Join(path, "description")); err == nil {
+				repro.Title = strings.TrimSpace(string(desc))
+			}
+		case ext == ".syz":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			repro := get(id)
+			repro.Syz, repro.Opts = parseSyzRepro(data, defaultOpts)
+		case ext == ".c":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			repro := get(id)
+			repro.C = data
+			if title, ok := bytes.CutPrefix(data, []byte("// ")); ok {
+				title, _, _ = bytes.Cut(title, []byte("\n"))
+				repro.Title = string(title)
+			}
+		}
+	}
+	var ret []*verifyRepro
+	for _, repro := range repros {
+		if len(repro.Syz) != 0 || len(repro.C) != 0 {
+			ret = append(ret, repro)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+// parseSyzRepro extracts options from the leading comments of a syz reproducer
+// (the dashboard puts them there in the serialized form). If there are none, default options
+// for the manager config are used. Like instance testing does, it always enables threaded mode
+// and repetition to increase chances to reproduce the crash.
+func parseSyzRepro(data []byte, defaultOpts csource.Options) ([]byte, csource.Options) {
+	opts := defaultOpts
+	for rest := data; bytes.HasPrefix(rest, []byte("#")); {
+		line := rest
+		if pos := bytes.IndexByte(rest, '\n'); pos != -1 {
+			line, rest = rest[:pos], rest[pos+1:]
+		} else {
+			rest = nil
+		}
+		if parsed, err := csource.DeserializeOptions(bytes.TrimSpace(line[1:])); err == nil {
+			opts = parsed
+			break
+		}
+	}
+	if opts.Sandbox == "" {
+		opts.Sandbox = "none"
+	}
+	opts.Repeat, opts.Threaded = true, true
+	return data, opts
+}
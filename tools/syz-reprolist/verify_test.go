@@ -0,0 +1,66 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRepros(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Written by this tool.
+		"1111.c":       "// WARNING in foo\n// https://syzkaller.appspot.com/bug?id=1111\n// status:open\nint main() {}\n",
+		"1111.syz":     "# https://syzkaller.appspot.com/bug?id=1111\n# {\"procs\":4,\"sandbox\":\"namespace\"}\nfoo()\n",
+		"2222.c":       "// KASAN: use-after-free Read in bar\nint main() {}\n",
+		"3333.norepro": "",
+		// syz-manager crash dir.
+		"4444/description": "BUG: corrupted list in baz\n",
+		"4444/repro.prog":  "# {Threaded:true Repeat:true RepeatTimes:0 Procs:2 Slowdown:1 Sandbox:setuid}\nbaz()\n",
+		"5555/description": "no repro\n",
+	}
+	for name, data := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		osutil.MkdirAll(filepath.Dir(file))
+		assert.NoError(t, osutil.WriteFile(file, []byte(data)))
+	}
+	defaultOpts := csource.Options{Procs: 1}
+	repros, err := loadRepros(dir, defaultOpts)
+	assert.NoError(t, err)
+	if !assert.Len(t, repros, 3) {
+		return
+	}
+
+	assert.Equal(t, "1111", repros[0].ID)
+	assert.Equal(t, "WARNING in foo", repros[0].Title)
+	assert.Equal(t, files["1111.syz"], string(repros[0].Syz))
+	assert.Equal(t, 4, repros[0].Opts.Procs)
+	assert.Equal(t, "namespace", repros[0].Opts.Sandbox)
+	assert.True(t, repros[0].Opts.Repeat)
+	assert.True(t, repros[0].Opts.Threaded)
+
+	assert.Equal(t, "2222", repros[1].ID)
+	assert.Equal(t, "KASAN: use-after-free Read in bar", repros[1].Title)
+	assert.Empty(t, repros[1].Syz)
+	assert.NotEmpty(t, repros[1].C)
+
+	assert.Equal(t, "4444", repros[2].ID)
+	assert.Equal(t, "BUG: corrupted list in baz", repros[2].Title)
+	// Options in the syz-manager format can't be parsed, defaults are used.
+	assert.Equal(t, 1, repros[2].Opts.Procs)
+	assert.Equal(t, "none", repros[2].Opts.Sandbox)
+	assert.True(t, repros[2].Opts.Threaded)
+}
+
+func TestParseSyzReproDefaults(t *testing.T) {
+	_, opts := parseSyzRepro([]byte("# some comment\nfoo()\n"), csource.Options{Procs: 3})
+	assert.Equal(t, 3, opts.Procs)
+	assert.Equal(t, "none", opts.Sandbox)
+	assert.True(t, opts.Repeat)
+}
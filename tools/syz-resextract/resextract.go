@@ -0,0 +1,58 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-resextract post-processes descriptions generated by syz-declextract:
+// opaque handles returned by calls (fds, HANDLEs, integer ids) and passed to related calls
+// are replaced with named resources, so that the fuzzer can chain the calls.
+// Inferred resources are printed to stderr for review.
+// Usage:
+//
+//	syz-declextract ... > sys/windows/auto.txt
+//	syz-resextract -in sys/windows/auto.txt -bases HANDLE
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/declextract"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagIn    = flag.String("in", "", "input descriptions file")
+	flagOut   = flag.String("out", "", "output file (overwrite the input file if empty)")
+	flagBases = flag.String("bases", "fd,HANDLE", "comma-separated base resource types of handles")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagIn == "" {
+		tool.Failf("-in is required")
+	}
+	data, err := os.ReadFile(*flagIn)
+	if err != nil {
+		tool.Fail(err)
+	}
+	desc := ast.Parse(data, filepath.Base(*flagIn), nil)
+	if desc == nil {
+		tool.Failf("failed to parse %v", *flagIn)
+	}
+	resources := declextract.InferResources(desc, strings.Split(*flagBases, ","))
+	for _, res := range resources {
+		fmt.Fprintf(os.Stderr, "%v[%v]: producers %v, consumers %v\n",
+			res.Name, res.Base, strings.Join(res.Producers, " "), strings.Join(res.Consumers, " "))
+	}
+	out := *flagOut
+	if out == "" {
+		out = *flagIn
+	}
+	if err := osutil.WriteFile(out, ast.Format(desc)); err != nil {
+		tool.Fail(err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-sockextract generates socket/bind/connect descriptions for address families
+// registered with sock_register in the kernel sources. Sockaddr layouts are taken from BTF.
+// Families that are already described in sys/linux are skipped.
+// Usage:
+//
+//	syz-sockextract -src $KERNEL -btf vmlinux -out sys/linux/socket_auto.txt
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/google/syzkaller/pkg/declextract"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var (
+	flagSrc  = flag.String("src", "", "kernel source dir")
+	flagBTF  = flag.String("btf", "", "raw BTF file or vmlinux with .BTF section (no sockaddr layouts if empty)")
+	flagOut  = flag.String("out", "", "output file for descriptions (stdout if empty)")
+	flagAll  = flag.Bool("all", false, "generate descriptions for already described families as well")
+	flagArch = flag.String("arch", targets.AMD64, "arch used to find already described families")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagSrc == "" {
+		tool.Failf("-src is required")
+	}
+	files, err := readSources(*flagSrc)
+	if err != nil {
+		tool.Fail(err)
+	}
+	var btf *declextract.BTF
+	if *flagBTF != "" {
+		if btf, err = declextract.LoadBTF(*flagBTF); err != nil {
+			tool.Fail(err)
+		}
+	}
+	families := declextract.ExtractSockets(files, btf)
+	if !*flagAll {
+		target, err := prog.GetTarget(targets.Linux, *flagArch)
+		if err != nil {
+			tool.Fail(err)
+		}
+		families = skipDescribed(target, families)
+	}
+	desc := declextract.SerializeSockets(families, btf)
+	if *flagOut == "" {
+		os.Stdout.Write(desc)
+	} else if err := osutil.WriteFile(*flagOut, desc); err != nil {
+		tool.Fail(err)
+	}
+}
+
+func readSources(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.Walk(filepath.Join(dir, "net"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".c" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
+
+// skipDescribed drops families that already have a socket$ syscall with the same domain.
+func skipDescribed(target *prog.Target, families []*declextract.SocketFamily) []*declextract.SocketFamily {
+	described := make(map[uint64]bool)
+	for _, call := range target.Syscalls {
+		if call.CallName != "socket" || len(call.Args) == 0 {
+			continue
+		}
+		if typ, ok := call.Args[0].Type.(*prog.ConstType); ok {
+			described[typ.Val] = true
+		}
+	}
+	var res []*declextract.SocketFamily
+	for _, fam := range families {
+		val, ok := target.ConstMap[fam.Family]
+		if ok && described[val] {
+			log.Logf(1, "skipping already described %v", fam.Family)
+			continue
+		}
+		res = append(res, fam)
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-testdesc tests syscall descriptions in a VM: it generates programs only for the given
+// syscalls, executes them and prints per-call success rates and coverage. Usage:
+//
+//	syz-testdesc -config=manager.cfg -calls=openat$foo,ioctl$FOO_*
+//	syz-testdesc -config=manager.cfg -desc=sys/linux/dev_foo.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagConfig   = flag.String("config", "", "manager configuration file")
+	flagCalls    = flag.String("calls", "", "comma-separated list of syscalls to test (globs are supported)")
+	flagDesc     = flag.String("desc", "", "comma-separated list of description files to test")
+	flagPrograms = flag.Int("programs", 100, "number of programs to generate")
+	flagLen      = flag.Int("len", 10, "number of calls in each program")
+	flagSeed     = flag.Int64("seed", 0, "random seed (current time by default)")
+	flagOutput   = flag.String("output", "", "save VM output to the file")
+	flagDebug    = flag.Bool("debug", false, "dump all VM output to console")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagConfig == "" || *flagCalls == "" && *flagDesc == "" {
+		fmt.Fprintf(os.Stderr, "usage: syz-testdesc -config=manager.cfg [-calls=call1,call2] [-desc=file.txt]\n")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	cfg, err := mgrconfig.LoadFile(*flagConfig)
+	if err != nil {
+		tool.Fail(err)
+	}
+	seed := *flagSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	descCfg := &instance.DescTestConfig{
+		Calls:     splitList(*flagCalls),
+		DescFiles: splitList(*flagDesc),
+		Programs:  *flagPrograms,
+		ProgLen:   *flagLen,
+		Seed:      seed,
+	}
+	log.Logf(0, "testing descriptions with seed %v", seed)
+	res, err := instance.TestDescriptions(cfg, descCfg, *flagDebug, &instance.OptionalConfig{
+		Logf: log.Logf,
+	})
+	if err != nil {
+		tool.Fail(err)
+	}
+	if *flagOutput != "" {
+		if err := osutil.WriteFile(*flagOutput, res.Output); err != nil {
+			tool.Fail(err)
+		}
+	}
+	os.Stdout.Write(res.Format())
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-zirconextract generates descriptions for Zircon system calls from the vDSO FIDL definitions
+// (zircon/vdso/*.fidl in the Fuchsia checkout). Calls that are already described in sys/fuchsia
+// are skipped. Types of the generated descriptions are rough (no flags, opaque structs),
+// so the output is meant as a starting point for manual descriptions.
+// Usage:
+//
+//	syz-zirconextract -src $FUCHSIA -out sys/fuchsia/zircon_auto.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/syzkaller/pkg/declextract"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var (
+	flagSrc  = flag.String("src", "", "fuchsia source dir")
+	flagOut  = flag.String("out", "", "output file for descriptions (stdout if empty)")
+	flagAll  = flag.Bool("all", false, "generate descriptions for already described syscalls as well")
+	flagArch = flag.String("arch", targets.AMD64, "arch used to find already described syscalls")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagSrc == "" {
+		tool.Failf("-src is required")
+	}
+	files, err := readSources(*flagSrc)
+	if err != nil {
+		tool.Fail(err)
+	}
+	calls, err := declextract.ExtractZircon(files)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if !*flagAll {
+		target, err := prog.GetTarget(targets.Fuchsia, *flagArch)
+		if err != nil {
+			tool.Fail(err)
+		}
+		calls = skipDescribed(target, calls)
+	}
+	desc := declextract.SerializeZircon(calls)
+	if *flagOut == "" {
+		os.Stdout.Write(desc)
+	} else if err := osutil.WriteFile(*flagOut, desc); err != nil {
+		tool.Fail(err)
+	}
+}
+
+func readSources(dir string) (map[string][]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "zircon", "vdso", "*.fidl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no zircon/vdso/*.fidl files in %v", dir)
+	}
+	files := make(map[string][]byte)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(rel)] = data
+	}
+	return files, nil
+}
+
+// skipDescribed drops calls that are already described (including $ variants).
+func skipDescribed(target *prog.Target, calls []*declextract.ZirconSyscall) []*declextract.ZirconSyscall {
+	described := make(map[string]bool)
+	for _, call := range target.Syscalls {
+		described[call.CallName] = true
+	}
+	var res []*declextract.ZirconSyscall
+	for _, call := range calls {
+		if described[call.Name] {
+			log.Logf(1, "skipping already described %v", call.Name)
+			continue
+		}
+		res = append(res, call)
+	}
+	return res
+}
@@ -45,6 +45,7 @@ type Config struct {
 	GCSPath       string `json:"gcs_path"`       // GCS path to upload image
 	GCEImage      string `json:"gce_image"`      // pre-created GCE image to use
 	Preemptible   bool   `json:"preemptible"`    // use preemptible VMs if available (defaults to true)
+	Spot          bool   `json:"spot"`           // use spot VMs (preemptible VMs without the 24h limit)
 	DisplayDevice bool   `json:"display_device"` // enable a virtual display device
 	// Username to connect to ssh-serialport.googleapis.com.
 	// Leave empty for non-OS Login GCP projects.
@@ -195,7 +196,11 @@ func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
 	}
 	log.Logf(0, "creating instance: %v", name)
 	ip, err := pool.GCE.CreateInstance(name, pool.cfg.MachineType, pool.cfg.GCEImage,
-		string(gceKeyPub), pool.cfg.Preemptible, pool.cfg.DisplayDevice)
+		string(gceKeyPub), gce.CreateArgs{
+			Preemptible:   pool.cfg.Preemptible,
+			Spot:          pool.cfg.Spot,
+			DisplayDevice: pool.cfg.DisplayDevice,
+		})
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +33,16 @@ type Config struct {
 	Count            int    `json:"count"` // number of VMs to use
 	RunscArgs        string `json:"runsc_args"`
 	MemoryTotalBytes uint64 `json:"memory_total_bytes"`
+	// Variants allow to fuzz several runsc configurations (e.g. different platforms,
+	// overlay on/off) within a single manager. VMs are distributed among the variants
+	// round-robin and crashes are attributed to the variant of the VM.
+	Variants []Variant `json:"variants"`
+}
+
+type Variant struct {
+	Name string `json:"name"`
+	// Additional runsc arguments, appended to runsc_args (e.g. "-platform=kvm -overlay2=none").
+	RunscArgs string `json:"runsc_args"`
 }
 
 type Pool struct {
@@ -42,6 +52,7 @@ type Pool struct {
 
 type instance struct {
 	cfg      *Config
+	variant  *Variant
 	image    string
 	debug    bool
 	rootDir  string
@@ -68,6 +79,13 @@ func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
 		return nil, fmt.Errorf("invalid config param memory_total_bytes: %v, want [%d,%d]",
 			minMemory, cfg.MemoryTotalBytes, hostTotalMemory)
 	}
+	names := make(map[string]bool)
+	for _, variant := range cfg.Variants {
+		if variant.Name == "" || strings.ContainsAny(variant.Name, " /") || names[variant.Name] {
+			return nil, fmt.Errorf("invalid config param variants: bad or duplicate name %q", variant.Name)
+		}
+		names[variant.Name] = true
+	}
 	if env.Debug && cfg.Count > 1 {
 		log.Logf(0, "limiting number of VMs from %v to 1 in debug mode", cfg.Count)
 		cfg.Count = 1
@@ -86,6 +104,20 @@ func (pool *Pool) Count() int {
 	return pool.cfg.Count
 }
 
+func (pool *Pool) Variant(index int) string {
+	if variant := pool.variant(index); variant != nil {
+		return variant.Name
+	}
+	return ""
+}
+
+func (pool *Pool) variant(index int) *Variant {
+	if len(pool.cfg.Variants) == 0 {
+		return nil
+	}
+	return &pool.cfg.Variants[index%len(pool.cfg.Variants)]
+}
+
 func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
 	rootDir := filepath.Clean(filepath.Join(workdir, "..", "gvisor_root"))
 	imageDir := filepath.Join(workdir, "image")
@@ -152,6 +184,7 @@ func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
 
 	inst := &instance{
 		cfg:      pool.cfg,
+		variant:  pool.variant(index),
 		image:    pool.env.Image,
 		debug:    pool.env.Debug,
 		rootDir:  rootDir,
@@ -236,11 +269,17 @@ func (inst *instance) args() []string {
 	if inst.cfg.RunscArgs != "" {
 		args = append(args, strings.Split(inst.cfg.RunscArgs, " ")...)
 	}
+	if inst.variant != nil && inst.variant.RunscArgs != "" {
+		args = append(args, strings.Fields(inst.variant.RunscArgs)...)
+	}
 	return args
 }
 
 func (inst *instance) Info() ([]byte, error) {
 	info := fmt.Sprintf("%v %v\n", inst.image, strings.Join(inst.args(), " "))
+	if inst.variant != nil {
+		info = fmt.Sprintf("variant: %v\n%v", inst.variant.Name, info)
+	}
 	return []byte(info), nil
 }
 
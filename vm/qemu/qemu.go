@@ -18,7 +18,6 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/config"
-	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/sys/targets"
@@ -73,6 +72,48 @@ type Config struct {
 	Snapshot bool `json:"snapshot"`
 	// Magic key used to dongle macOS to the device.
 	AppleSmcOsk string `json:"apple_smc_osk"`
+	// Recover crashes that did not make it to the console from pstore (Linux only).
+	// If the VM is lost without an oops on the console, it's reset and the pstore records
+	// are read back after the reboot. The kernel needs to be configured with a persistent
+	// pstore backend, e.g. ramoops with memory reserved via the memmap/ramoops cmdline parameters,
+	// or efi-pstore with efi_vars_device.
+	Pstore bool `json:"pstore"`
+	// Collect kernel crash dumps with kdump (Linux only).
+	// If kernel is specified, "crashkernel=256M" is added to the kernel command line
+	// unless cmdline already specifies it.
+	// The image is expected to load the capture kernel with "kexec -p" during boot and to start
+	// sshd in the capture environment; /proc/vmcore is then copied out after a kernel panic.
+	Kdump bool `json:"kdump"`
+	// Collect userspace core dumps of crashed syz-executor processes (Linux only).
+	// The guest core_pattern is pointed to a handler that saves only syz-executor core dumps
+	// into the syz-cores dir next to the copied binaries (only the latest one, at most 256MB).
+	// The core dump is copied out together with the executor binary when the executor crashes.
+	// The manager saves them into the crash dir.
+	ExecutorCoreDumps bool `json:"executor_core_dumps"`
+	// Make the guest randomness as reproducible as possible (best effort): the guest random number
+	// generator of QEMU (used by the virtio-rng rng-builtin backend) gets a fixed seed, and if kernel
+	// is specified, the kernel command line disables crediting of CPU/bootloader entropy and ASLR.
+	// On x86 the RDRAND/RDSEED CPU features are removed from the -cpu model, otherwise the kernel
+	// still mixes them into the random pool.
+	// The setting applies to the VM as a whole, so it equally affects fuzzing and reproduction runs.
+	// Note: this is not per-program seeding, the in-kernel random state is not reset between programs.
+	FixedRandom bool `json:"fixed_random"`
+	// Attach a GPU to the VM, so that DRM/KMS descriptions have a device to talk to (Linux only).
+	GPU *GPUConfig `json:"gpu,omitempty"`
+}
+
+type GPUConfig struct {
+	// Emulated GPU device, "virtio-gpu-pci" by default (e.g. "virtio-gpu-device" for arches
+	// without PCI, or "virtio-gpu-gl-pci" for 3D support). "none" disables the emulated device,
+	// which is useful if only passthrough GPUs are used.
+	Device string `json:"device"`
+	// Host PCI addresses (e.g. "0000:01:00.0") of GPUs passed through to VMs with vfio-pci
+	// (optional). The devices need to be bound to the vfio-pci driver on the host.
+	// VM number i gets the i-th device, so there must be at least count devices.
+	VfioHosts []string `json:"vfio_hosts,omitempty"`
+	// Device nodes that must exist in the VM after boot, "/dev/dri/card0" by default.
+	// The VM fails to boot if any of them is missing (e.g. CONFIG_DRM_VIRTIO_GPU is not enabled).
+	Nodes []string `json:"nodes,omitempty"`
 }
 
 type Pool struct {
@@ -111,6 +152,14 @@ type instance struct {
 	diagnose    chan bool
 }
 
+// Kernel command line parameters that remove sources of non-determinism for the fixed_random option.
+var fixedRandomCmdline = []string{
+	"random.trust_cpu=off",
+	"random.trust_bootloader=off",
+	"nokaslr",
+	"norandmaps",
+}
+
 type archConfig struct {
 	Qemu      string
 	QemuArgs  string
@@ -275,7 +324,7 @@ func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
 		return nil, fmt.Errorf("invalid config param count: %v, want [1, 128]", cfg.Count)
 	}
 	if env.Debug && cfg.Count > 1 {
-		log.Logf(0, "limiting number of VMs from %v to 1 in debug mode", cfg.Count)
+		vmimpl.Log.Logf(0, "limiting number of VMs from %v to 1 in debug mode", cfg.Count)
 		cfg.Count = 1
 	}
 	if _, err := exec.LookPath(cfg.Qemu); err != nil {
@@ -293,6 +342,20 @@ func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
 			return nil, fmt.Errorf("image file '%v' does not exist", env.Image)
 		}
 	}
+	if cfg.Kdump && env.OS != targets.Linux {
+		return nil, fmt.Errorf("kdump is supported for linux only")
+	}
+	if cfg.ExecutorCoreDumps && env.OS != targets.Linux {
+		return nil, fmt.Errorf("executor_core_dumps is supported for linux only")
+	}
+	if cfg.GPU != nil {
+		if env.OS != targets.Linux {
+			return nil, fmt.Errorf("gpu is supported for linux only")
+		}
+		if err := checkGPU(cfg); err != nil {
+			return nil, err
+		}
+	}
 	if cfg.CPU <= 0 || cfg.CPU > 1024 {
 		return nil, fmt.Errorf("bad qemu cpu: %v, want [1-1024]", cfg.CPU)
 	}
@@ -430,15 +493,14 @@ func (inst *instance) boot() error {
 		"-no-reboot",
 		"-name", instanceName,
 	}
-	if inst.archConfig.RngDev != "" {
-		args = append(args, "-device", inst.archConfig.RngDev)
-	}
+	args = append(args, inst.rngArgs()...)
 	templateDir := filepath.Join(inst.workdir, "template")
 	args = append(args, splitArgs(inst.cfg.QemuArgs, templateDir, inst.index)...)
+	args = inst.cpuArgs(args)
 
 	forwardedPort := vmimpl.UnusedTCPPort()
 	pprofExt := fmt.Sprintf(",hostfwd=tcp::%v-:%v", forwardedPort, vmimpl.PprofPort)
-	log.Logf(3, "instance %s's pprof is available at 127.0.0.1:%v", instanceName, forwardedPort)
+	vmimpl.Log.Logf(3, "instance %s's pprof is available at 127.0.0.1:%v", instanceName, forwardedPort)
 
 	args = append(args,
 		"-device", inst.cfg.NetDev+",netdev=net0",
@@ -476,19 +538,9 @@ func (inst *instance) boot() error {
 		)
 	}
 	if inst.cfg.Kernel != "" {
-		cmdline := append([]string{}, inst.archConfig.CmdLine...)
-		if inst.image == "9p" {
-			cmdline = append(cmdline,
-				"root=/dev/root",
-				"rootfstype=9p",
-				"rootflags=trans=virtio,version=9p2000.L,cache=loose",
-				"init="+filepath.Join(inst.workdir, "init.sh"),
-			)
-		}
-		cmdline = append(cmdline, inst.cfg.Cmdline)
 		args = append(args,
 			"-kernel", inst.cfg.Kernel,
-			"-append", strings.Join(cmdline, " "),
+			"-append", inst.kernelCmdline(),
 		)
 	}
 	if inst.cfg.EfiCodeDevice != "" {
@@ -506,8 +558,16 @@ func (inst *instance) boot() error {
 			"-device", "isa-applesmc,osk="+inst.cfg.AppleSmcOsk,
 		)
 	}
+	if gpu := inst.cfg.GPU; gpu != nil {
+		if gpu.Device != gpuNone {
+			args = append(args, "-device", gpu.Device)
+		}
+		if len(gpu.VfioHosts) != 0 {
+			args = append(args, "-device", "vfio-pci,host="+gpu.VfioHosts[inst.index])
+		}
+	}
 	if inst.debug {
-		log.Logf(0, "running command: %v %#v", inst.cfg.Qemu, args)
+		vmimpl.Log.Logf(0, "running command: %v %#v", inst.cfg.Qemu, args)
 	}
 	inst.args = args
 	qemu := osutil.Command(inst.cfg.Qemu, args...)
@@ -550,6 +610,103 @@ func (inst *instance) boot() error {
 		return vmimpl.MakeBootError(err, bootOutput)
 	}
 	bootOutputStop <- true
+	if inst.cfg.GPU != nil {
+		for _, node := range inst.cfg.GPU.Nodes {
+			if _, err := inst.ssh("test", "-e", node); err != nil {
+				return fmt.Errorf("gpu device node %v does not exist in the VM", node)
+			}
+		}
+	}
+	if inst.cfg.ExecutorCoreDumps {
+		if err := vmimpl.SetupCoreDumpsLinux(inst.ssh, inst.coreDir()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const gpuNone = "none"
+
+func (inst *instance) rngArgs() []string {
+	var args []string
+	if inst.archConfig.RngDev != "" {
+		args = append(args, "-device", inst.archConfig.RngDev)
+	}
+	if inst.cfg.FixedRandom {
+		args = append(args, "-seed", "0")
+	}
+	return args
+}
+
+// cpuArgs removes the hardware random number generator from the x86 -cpu model for fixed_random.
+func (inst *instance) cpuArgs(args []string) []string {
+	if !inst.cfg.FixedRandom || inst.target.Arch != targets.AMD64 && inst.target.Arch != targets.I386 {
+		return args
+	}
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-cpu" {
+			args[i+1] += ",-rdrand,-rdseed"
+		}
+	}
+	return args
+}
+
+func (inst *instance) kernelCmdline() string {
+	cmdline := append([]string{}, inst.archConfig.CmdLine...)
+	if inst.image == "9p" {
+		cmdline = append(cmdline,
+			"root=/dev/root",
+			"rootfstype=9p",
+			"rootflags=trans=virtio,version=9p2000.L,cache=loose",
+			"init="+filepath.Join(inst.workdir, "init.sh"),
+		)
+	}
+	if inst.cfg.Kdump && !strings.Contains(inst.cfg.Cmdline, "crashkernel=") {
+		cmdline = append(cmdline, "crashkernel=256M")
+	}
+	if inst.cfg.FixedRandom {
+		cmdline = append(cmdline, fixedRandomCmdline...)
+	}
+	cmdline = append(cmdline, inst.cfg.Cmdline)
+	return strings.Join(cmdline, " ")
+}
+
+// checkGPU fills in the defaults of the gpu config and checks that the requested devices
+// are supported by qemu and are available on the host.
+func checkGPU(cfg *Config) error {
+	gpu := cfg.GPU
+	if gpu.Device == "" {
+		gpu.Device = "virtio-gpu-pci"
+	}
+	if len(gpu.Nodes) == 0 {
+		gpu.Nodes = []string{"/dev/dri/card0"}
+	}
+	if gpu.Device != gpuNone {
+		output, err := osutil.RunCmd(time.Minute, "", cfg.Qemu, "-device", "help")
+		if err != nil {
+			return err
+		}
+		name, _, _ := strings.Cut(gpu.Device, ",")
+		if !bytes.Contains(output, []byte(fmt.Sprintf("name %q", name))) {
+			return fmt.Errorf("gpu device %v is not supported by %v", name, cfg.Qemu)
+		}
+	}
+	if len(gpu.VfioHosts) == 0 {
+		return nil
+	}
+	if len(gpu.VfioHosts) < cfg.Count {
+		return fmt.Errorf("gpu vfio_hosts has %v devices for %v VMs", len(gpu.VfioHosts), cfg.Count)
+	}
+	for _, host := range gpu.VfioHosts {
+		dev := filepath.Join("/sys/bus/pci/devices", host)
+		if !osutil.IsExist(dev) {
+			return fmt.Errorf("gpu pci device %v does not exist", host)
+		}
+		driver, err := os.Readlink(filepath.Join(dev, "driver"))
+		if err != nil || filepath.Base(driver) != "vfio-pci" {
+			return fmt.Errorf("gpu pci device %v is not bound to the vfio-pci driver", host)
+		}
+	}
 	return nil
 }
 
@@ -629,7 +786,7 @@ func (inst *instance) Copy(hostSrc string) (string, error) {
 	args := append(vmimpl.SCPArgs(inst.debug, inst.sshkey, inst.port, false),
 		hostSrc, inst.sshuser+"@localhost:"+vmDst)
 	if inst.debug {
-		log.Logf(0, "running command: scp %#v", args)
+		vmimpl.Log.Logf(0, "running command: scp %#v", args)
 	}
 	_, err := osutil.RunCmd(10*time.Minute*inst.timeouts.Scale, "", "scp", args...)
 	if err != nil {
@@ -668,7 +825,7 @@ func (inst *instance) Run(timeout time.Duration, stop <-chan bool, command strin
 		args = append(args, inst.sshuser+"@localhost", "cd "+inst.targetDir()+" && "+command)
 	}
 	if inst.debug {
-		log.Logf(0, "running command: %#v", args)
+		vmimpl.Log.Logf(0, "running command: %#v", args)
 	}
 	cmd := osutil.Command(args[0], args[1:]...)
 	cmd.Dir = inst.workdir
@@ -723,6 +880,9 @@ func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
 		if output, wait, handled := vmimpl.DiagnoseLinux(rep, inst.ssh); handled {
 			return output, wait
 		}
+		if inst.cfg.Pstore && len(rep.Report) == 0 {
+			return inst.readPstore(), false
+		}
 	}
 	// TODO: we don't need registers on all reports. Probably only relevant for "crashes"
 	// (NULL derefs, paging faults, etc), but is not useful for WARNING/BUG/HANG (?).
@@ -733,13 +893,75 @@ func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
 			ret = append(ret, []byte(fmt.Sprintf("info registers vcpu %v\n", cpu))...)
 			ret = append(ret, []byte(regs)...)
 		} else {
-			log.Logf(0, "VM-%v failed reading regs: %v", inst.index, err)
+			vmimpl.Log.Logf(0, "VM-%v failed reading regs: %v", inst.index, err)
 			ret = append(ret, []byte(fmt.Sprintf("Failed reading regs: %v\n", err))...)
 		}
 	}
 	return ret, false
 }
 
+// readPstore resets the VM and reads the kernel log of the lost boot from pstore.
+// Guest memory is preserved across reset, so ramoops records survive.
+func (inst *instance) readPstore() []byte {
+	if _, err := inst.hmp("system_reset", 0); err != nil {
+		return []byte(fmt.Sprintf("failed to reset VM to read pstore: %v\n", err))
+	}
+	if err := vmimpl.WaitForSSH(inst.debug, 10*time.Minute*inst.timeouts.Scale, "localhost",
+		inst.sshkey, inst.sshuser, inst.os, inst.port, nil, false); err != nil {
+		return []byte(fmt.Sprintf("VM did not come back after reset to read pstore: %v\n", err))
+	}
+	output, err := vmimpl.ReadPstoreLinux(inst.ssh)
+	if err != nil {
+		return []byte(fmt.Sprintf("%v\n", err))
+	}
+	return output
+}
+
+// CollectDump waits for the kdump capture kernel to boot after a panic and copies /proc/vmcore out.
+func (inst *instance) CollectDump(hostDst string) error {
+	if !inst.cfg.Kdump {
+		return fmt.Errorf("kdump is not enabled in the VM config")
+	}
+	if err := vmimpl.WaitForSSH(inst.debug, 10*time.Minute*inst.timeouts.Scale, "localhost",
+		inst.sshkey, inst.sshuser, inst.os, inst.port, nil, false); err != nil {
+		return fmt.Errorf("capture kernel did not come up: %w", err)
+	}
+	return vmimpl.CopyVmcoreLinux(inst.sshArgs(), hostDst, 30*time.Minute*inst.timeouts.Scale)
+}
+
+func (inst *instance) coreDir() string {
+	return filepath.Join(inst.targetDir(), "syz-cores")
+}
+
+// CollectCoreDump copies the latest executor core dump and the binaries out of the VM.
+func (inst *instance) CollectCoreDump(hostDir string, bins []string) ([]string, error) {
+	if !inst.cfg.ExecutorCoreDumps {
+		return nil, nil
+	}
+	core, err := vmimpl.LatestCoreDumpLinux(inst.ssh, inst.coreDir())
+	if err != nil || core == "" {
+		return nil, err
+	}
+	var files []string
+	for _, src := range append([]string{core}, bins...) {
+		dst := filepath.Join(hostDir, filepath.Base(src))
+		if err := inst.copyOut(src, dst); err != nil {
+			return files, err
+		}
+		files = append(files, dst)
+	}
+	// Remove the core dump, so that it's not attributed to the next crash.
+	inst.ssh("rm", "-f", core)
+	return files, nil
+}
+
+func (inst *instance) copyOut(vmSrc, hostDst string) error {
+	args := append(vmimpl.SCPArgs(inst.debug, inst.sshkey, inst.port, false),
+		inst.sshuser+"@localhost:"+vmSrc, hostDst)
+	_, err := osutil.RunCmd(10*time.Minute*inst.timeouts.Scale, "", "scp", args...)
+	return err
+}
+
 func (inst *instance) ssh(args ...string) ([]byte, error) {
 	return osutil.RunCmd(time.Minute*inst.timeouts.Scale, "", "ssh", inst.sshArgs(args...)...)
 }
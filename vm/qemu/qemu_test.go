@@ -0,0 +1,57 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package qemu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedRandom(t *testing.T) {
+	tests := []struct {
+		fixedRandom bool
+		kdump       bool
+		args        []string
+		cpuArgs     []string
+		cmdline     string
+	}{
+		{
+			args:    []string{"-device", "virtio-rng-pci"},
+			cpuArgs: []string{"-enable-kvm", "-cpu", "host,migratable=off"},
+			cmdline: "root=/dev/sda console=ttyS0 foo=bar",
+		},
+		{
+			fixedRandom: true,
+			args:        []string{"-device", "virtio-rng-pci", "-seed", "0"},
+			cpuArgs:     []string{"-enable-kvm", "-cpu", "host,migratable=off,-rdrand,-rdseed"},
+			cmdline: "root=/dev/sda console=ttyS0 random.trust_cpu=off random.trust_bootloader=off" +
+				" nokaslr norandmaps foo=bar",
+		},
+		{
+			fixedRandom: true,
+			kdump:       true,
+			args:        []string{"-device", "virtio-rng-pci", "-seed", "0"},
+			cpuArgs:     []string{"-enable-kvm", "-cpu", "host,migratable=off,-rdrand,-rdseed"},
+			cmdline: "root=/dev/sda console=ttyS0 crashkernel=256M random.trust_cpu=off" +
+				" random.trust_bootloader=off nokaslr norandmaps foo=bar",
+		},
+	}
+	for _, test := range tests {
+		inst := &instance{
+			cfg: &Config{
+				Cmdline:     "foo=bar",
+				Kdump:       test.kdump,
+				FixedRandom: test.fixedRandom,
+			},
+			target:     targets.Get(targets.Linux, targets.AMD64),
+			archConfig: archConfigs["linux/amd64"],
+		}
+		assert.Equal(t, test.args, inst.rngArgs())
+		assert.Equal(t, test.cpuArgs, inst.cpuArgs(strings.Fields(inst.archConfig.QemuArgs)))
+		assert.Equal(t, test.cmdline, inst.kernelCmdline())
+	}
+}
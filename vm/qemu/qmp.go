@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"net"
 
-	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/vm/vmimpl"
 )
 
 type qmpVersion struct {
@@ -89,7 +89,7 @@ func (inst *instance) qmpRecv() (*qmpResponse, error) {
 		if err != nil || qmp.Event == "" {
 			return qmp, err
 		}
-		log.Logf(1, "event: %v", qmp)
+		vmimpl.Log.Logf(1, "event: %v", qmp)
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -130,6 +131,15 @@ func (pool *Pool) Count() int {
 	return pool.impl.Count()
 }
 
+// Variant returns name of the target configuration used by the VM with the given index,
+// or an empty string if all VMs in the pool use the same configuration.
+func (pool *Pool) Variant(index int) string {
+	if vp, ok := pool.impl.(vmimpl.VariantProvider); ok {
+		return vp.Variant(index)
+	}
+	return ""
+}
+
 func (pool *Pool) Create(index int) (*Instance, error) {
 	if index < 0 || index >= pool.Count() {
 		return nil, fmt.Errorf("invalid VM index %v (count %v)", index, pool.Count())
@@ -197,6 +207,13 @@ type OutputSize int
 // An early notification that the command has finished / VM crashed.
 type EarlyFinishCb func()
 
+// OutputWarnings makes Run call Cb for every console output line that matches one of Res,
+// but does not contain a crash (e.g. non-fatal kernel warnings).
+type OutputWarnings struct {
+	Res []*regexp.Regexp
+	Cb  func(line []byte)
+}
+
 // Run runs cmd inside of the VM (think of ssh cmd) and monitors command execution
 // and the kernel console output. It detects kernel oopses in output, lost connections, hangs, etc.
 // Returns command+kernel output and a non-symbolized crash report (nil if no error happens).
@@ -204,12 +221,16 @@ type EarlyFinishCb func()
 //   - StopChan: stop channel can be used to prematurely stop the command
 //   - ExitCondition: says which exit modes should be considered as errors/OK
 //   - OutputSize: how much output to keep/return
+//   - OutputWarnings: notifications about console lines that match the given regexps
+//   - *report.ConsoleClock: is fed with the console output as it's received (see osutil.MonotonicNano)
 func (inst *Instance) Run(timeout time.Duration, reporter *report.Reporter, command string, opts ...any) (
 	[]byte, *report.Report, error) {
 	exit := ExitNormal
 	var stop <-chan bool
 	var injected <-chan bool
 	var finished func()
+	var warnings *OutputWarnings
+	var clock *report.ConsoleClock
 	outputSize := beforeContextDefault
 	for _, o := range opts {
 		switch opt := o.(type) {
@@ -223,6 +244,10 @@ func (inst *Instance) Run(timeout time.Duration, reporter *report.Reporter, comm
 			injected = (<-chan bool)(opt)
 		case EarlyFinishCb:
 			finished = opt
+		case OutputWarnings:
+			warnings = &opt
+		case *report.ConsoleClock:
+			clock = opt
 		default:
 			panic(fmt.Sprintf("unknown option %#v", opt))
 		}
@@ -237,6 +262,8 @@ func (inst *Instance) Run(timeout time.Duration, reporter *report.Reporter, comm
 		injected:        injected,
 		errc:            errc,
 		finished:        finished,
+		warnings:        warnings,
+		clock:           clock,
 		reporter:        reporter,
 		beforeContext:   outputSize,
 		exit:            exit,
@@ -253,6 +280,25 @@ func (inst *Instance) Info() ([]byte, error) {
 	return nil, nil
 }
 
+// CollectDump saves the kernel crash dump into the hostDst file after the kernel has crashed.
+// The VM type needs to support it and be configured accordingly (e.g. kdump for qemu).
+func (inst *Instance) CollectDump(hostDst string) error {
+	if dc, ok := inst.impl.(vmimpl.DumpCollector); ok {
+		return dc.CollectDump(hostDst)
+	}
+	return fmt.Errorf("kernel crash dumps are not supported by the VM type")
+}
+
+// CollectCoreDump copies the latest userspace core dump (e.g. of a crashed syz-executor)
+// and the given binaries from the VM into hostDir. Returns names of the copied files,
+// or nil if the VM type does not support it, core dumps are not enabled or there are none.
+func (inst *Instance) CollectCoreDump(hostDir string, bins ...string) ([]string, error) {
+	if cc, ok := inst.impl.(vmimpl.CoreDumpCollector); ok {
+		return cc.CollectCoreDump(hostDir, bins)
+	}
+	return nil, nil
+}
+
 func (inst *Instance) PprofPort() int {
 	if inst.pool.hostFuzzer {
 		// In the fuzzing on host mode, fuzzers are always on the same network.
@@ -283,6 +329,9 @@ type monitor struct {
 	outc            <-chan []byte
 	injected        <-chan bool
 	finished        func()
+	warnings        *OutputWarnings
+	warningPos      int
+	clock           *report.ConsoleClock
 	errc            <-chan error
 	reporter        *report.Reporter
 	exit            ExitCondition
@@ -333,6 +382,7 @@ func (mon *monitor) monitorExecution() *report.Report {
 				continue
 			}
 			mon.inst.pool.statOutputReceived.Add(len(out))
+			mon.noteClock(out)
 			if rep, done := mon.appendOutput(out); done {
 				return rep
 			}
@@ -360,7 +410,9 @@ func (mon *monitor) appendOutput(out []byte) (*report.Report, bool) {
 	if mon.reporter.ContainsCrash(mon.output[mon.matchPos:]) {
 		return mon.extractError("unknown error"), true
 	}
+	mon.matchWarnings()
 	if len(mon.output) > 2*mon.beforeContext {
+		mon.warningPos = max(mon.warningPos-(len(mon.output)-mon.beforeContext), 0)
 		copy(mon.output, mon.output[len(mon.output)-mon.beforeContext:])
 		mon.output = mon.output[:mon.beforeContext]
 	}
@@ -383,6 +435,33 @@ func (mon *monitor) appendOutput(out []byte) (*report.Report, bool) {
 	return nil, false
 }
 
+func (mon *monitor) noteClock(out []byte) {
+	if mon.clock != nil {
+		mon.clock.Note(out, osutil.MonotonicNano())
+	}
+}
+
+// matchWarnings passes new complete output lines that match the warning regexps to the callback.
+func (mon *monitor) matchWarnings() {
+	if mon.warnings == nil {
+		return
+	}
+	for {
+		end := bytes.IndexByte(mon.output[mon.warningPos:], '\n')
+		if end == -1 {
+			return
+		}
+		line := mon.output[mon.warningPos : mon.warningPos+end]
+		mon.warningPos += end + 1
+		for _, re := range mon.warnings.Res {
+			if re.Match(line) {
+				mon.warnings.Cb(line)
+				break
+			}
+		}
+	}
+}
+
 func (mon *monitor) extractError(defaultError string) *report.Report {
 	if mon.extractCalled {
 		panic("extractError called twice")
@@ -418,13 +497,17 @@ func (mon *monitor) extractError(defaultError string) *report.Report {
 	}
 	if len(diagOutput) > 0 {
 		rep.Output = append(rep.Output, vmDiagnosisStart...)
+		diagPos := len(rep.Output)
 		rep.Output = append(rep.Output, diagOutput...)
+		// Diagnosis may recover the oops that did not make it to the console
+		// (e.g. from pstore after reboot).
+		rep = mon.reporter.ParseRecovered(rep, diagPos)
 	}
 	return rep
 }
 
 func (mon *monitor) createReport(defaultError string) *report.Report {
-	rep := mon.reporter.ParseFrom(mon.output, mon.matchPos)
+	rep := mon.reporter.ParseMulti(mon.output, mon.matchPos)
 	if rep == nil {
 		if defaultError == "" {
 			return nil
@@ -435,17 +518,28 @@ func (mon *monitor) createReport(defaultError string) *report.Report {
 			Suppressed: report.IsSuppressed(mon.reporter, mon.output),
 		}
 	}
-	start := rep.StartPos - mon.beforeContext
+	// The primary report is not necessarily the first one in the output.
+	start, end := rep.StartPos, rep.EndPos
+	for _, secondary := range rep.Secondary {
+		start = min(start, secondary.StartPos)
+		end = max(end, secondary.EndPos)
+	}
+	start -= mon.beforeContext
 	if start < 0 {
 		start = 0
 	}
-	end := rep.EndPos + afterContext
+	end += afterContext
 	if end > len(rep.Output) {
 		end = len(rep.Output)
 	}
 	rep.Output = rep.Output[start:end]
 	rep.StartPos -= start
 	rep.EndPos -= start
+	for _, secondary := range rep.Secondary {
+		secondary.Output = rep.Output
+		secondary.StartPos -= start
+		secondary.EndPos -= start
+	}
 	return rep
 }
 
@@ -458,6 +552,7 @@ func (mon *monitor) waitForOutput() {
 			if !ok {
 				return
 			}
+			mon.noteClock(out)
 			mon.output = append(mon.output, out...)
 		case <-timer.C:
 			return
@@ -6,6 +6,7 @@ package vm
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/sys/targets"
 	"github.com/google/syzkaller/vm/vmimpl"
+	"github.com/stretchr/testify/assert"
 )
 
 type testPool struct {
@@ -148,6 +150,25 @@ var tests = []*Test{
 			),
 		},
 	},
+	{
+		Name: "diagnose-recovers-bug",
+		Body: func(outc chan []byte, errc chan error) {
+			errc <- nil
+		},
+		DiagnoseBug:    true,
+		DiagnoseNoWait: true,
+		Report: &report.Report{
+			Title: "BUG: DIAGNOSE",
+			Report: []byte(
+				"BUG: DIAGNOSE\n",
+			),
+			Output: []byte(
+				"\n" +
+					"VM DIAGNOSIS:\n" +
+					"BUG: DIAGNOSE\n",
+			),
+		},
+	},
 	{
 		Name: "diagnose-bug-no-wait",
 		Body: func(outc chan []byte, errc chan error) {
@@ -426,6 +447,28 @@ func testMonitorExecution(t *testing.T, test *Test) {
 	}
 }
 
+func TestOutputWarnings(t *testing.T) {
+	var lines []string
+	mon := &monitor{
+		warnings: &OutputWarnings{
+			Res: []*regexp.Regexp{regexp.MustCompile(`^foo: `), regexp.MustCompile(`error -\d+$`)},
+			Cb: func(line []byte) {
+				lines = append(lines, string(line))
+			},
+		},
+	}
+	for _, out := range []string{
+		"foo: first\nbar\nfo",
+		"o: split",
+		" line\nsome error -12",
+		"\nerror -12 in the middle\n",
+	} {
+		mon.output = append(mon.output, out...)
+		mon.matchWarnings()
+	}
+	assert.Equal(t, []string{"foo: first", "foo: split line", "some error -12"}, lines)
+}
+
 func TestVMType(t *testing.T) {
 	testCases := []struct {
 		in   string
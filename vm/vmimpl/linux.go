@@ -4,9 +4,15 @@
 package vmimpl
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
 )
 
@@ -24,3 +30,122 @@ func DiagnoseLinux(rep *report.Report, ssh func(args ...string) ([]byte, error))
 	output = regexp.MustCompile(` *\[?[0-9a-f]{8,}\]?\s*`).ReplaceAll(output, nil)
 	return output, false, true
 }
+
+const (
+	pstoreDir         = "/sys/fs/pstore"
+	pstoreFileMarker  = "SYZ-PSTORE-FILE: "
+	pstoreFileTrailer = "\nSYZ-PSTORE-END\n"
+)
+
+// ReadPstoreLinux reads and removes pstore records left by the previous boot
+// over the provided ssh callback and returns the reconstructed kernel log.
+func ReadPstoreLinux(ssh func(args ...string) ([]byte, error)) ([]byte, error) {
+	// Records are removed after reading so that they don't show up after the next crash.
+	script := fmt.Sprintf(`for f in %v/*; do [ -f "$f" ] || continue; echo "%v$f"; cat "$f"; `+
+		`printf "%v"; rm -f "$f"; done`, pstoreDir, pstoreFileMarker, strings.ReplaceAll(pstoreFileTrailer, "\n", `\n`))
+	output, err := ssh("sh", "-c", "'"+script+"'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pstore: %w: %s", err, output)
+	}
+	return report.ExtractPstore(parsePstoreOutput(output)), nil
+}
+
+func parsePstoreOutput(output []byte) []report.PstoreRecord {
+	var records []report.PstoreRecord
+	for {
+		start := bytes.Index(output, []byte(pstoreFileMarker))
+		if start == -1 {
+			break
+		}
+		output = output[start+len(pstoreFileMarker):]
+		nl := bytes.IndexByte(output, '\n')
+		if nl == -1 {
+			break
+		}
+		name := path.Base(string(output[:nl]))
+		output = output[nl+1:]
+		end := bytes.Index(output, []byte(pstoreFileTrailer))
+		if end == -1 {
+			end = len(output)
+		}
+		records = append(records, report.PstoreRecord{Name: name, Data: output[:end]})
+		output = output[end:]
+	}
+	return records
+}
+
+// Kernel crash dump exposed by the kdump capture kernel.
+const kdumpVmcore = "/proc/vmcore"
+
+// CopyVmcoreLinux streams the kernel crash dump from the kdump capture kernel into hostDst.
+// sshArgs must include the destination (user@host), the dump is streamed over ssh stdout
+// because /proc/vmcore is usually too large to be buffered in memory.
+func CopyVmcoreLinux(sshArgs []string, hostDst string, timeout time.Duration) error {
+	f, err := os.Create(hostDst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// The normal kernel does not have /proc/vmcore, so this also checks that the capture kernel
+	// has actually booted (e.g. the kernel did not panic and the VM is still running the normal kernel).
+	args := append(append([]string{}, sshArgs...), "test", "-r", kdumpVmcore, "&&", "cat", kdumpVmcore)
+	cmd := osutil.Command("ssh", args...)
+	cmd.Stdout = f
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	if _, err := osutil.Run(timeout, cmd); err != nil {
+		os.Remove(hostDst)
+		return fmt.Errorf("failed to copy %v: %w: %s", kdumpVmcore, err, stderr.Bytes())
+	}
+	return nil
+}
+
+const (
+	coreFileMarker = "SYZ-CORE-FILE: "
+	// Only the latest core dump is kept and it's truncated to this size,
+	// so that crash loops don't fill the VM disk.
+	maxCoreDumpSize = 256 << 20
+)
+
+// SetupCoreDumpsLinux makes the kernel write core dumps of syz-executor processes into dir
+// over the provided ssh callback. Core dumps are piped to a handler script that ignores other
+// processes, keeps only the latest core dump and truncates it to maxCoreDumpSize.
+// For piped core dumps RLIMIT_CORE is ignored, so the limit does not need to be raised.
+func SetupCoreDumpsLinux(ssh func(args ...string) ([]byte, error), dir string) error {
+	handler := dir + "/core-handler.sh"
+	// $1 is the executable name (%e, truncated to 15 chars), $2 is the pid (%p), the core is on stdin.
+	lines := []string{
+		`#!/bin/sh`,
+		`case \$1 in syz-executor*) ;; *) exit 0;; esac`,
+		fmt.Sprintf(`rm -f %v/core.*`, dir),
+		fmt.Sprintf(`exec head -c %v > %v/core.\$1.\$2`, maxCoreDumpSize, dir),
+	}
+	script := fmt.Sprintf(`mkdir -p %v && printf "%%s\n" "%v" > %v && chmod +x %v && `+
+		`echo "|%v %%e %%p" > /proc/sys/kernel/core_pattern`,
+		dir, strings.Join(lines, `" "`), handler, handler, handler)
+	if output, err := ssh("sh", "-c", "'"+script+"'"); err != nil {
+		return fmt.Errorf("failed to setup core dumps: %w: %s", err, output)
+	}
+	return nil
+}
+
+// LatestCoreDumpLinux returns the path of the most recent core dump in dir (set up with SetupCoreDumpsLinux),
+// or an empty string if there are no core dumps.
+func LatestCoreDumpLinux(ssh func(args ...string) ([]byte, error), dir string) (string, error) {
+	script := fmt.Sprintf(`f=$(ls -t %v/core.* 2>/dev/null | head -n 1); [ -z "$f" ] || echo "%v$f"`,
+		dir, coreFileMarker)
+	output, err := ssh("sh", "-c", "'"+script+"'")
+	if err != nil {
+		return "", fmt.Errorf("failed to list core dumps: %w: %s", err, output)
+	}
+	return parseCoreFileOutput(output), nil
+}
+
+func parseCoreFileOutput(output []byte) string {
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, coreFileMarker) {
+			return strings.TrimSpace(line[len(coreFileMarker):])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vmimpl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPstoreLinux(t *testing.T) {
+	var cmd string
+	ssh := func(args ...string) ([]byte, error) {
+		cmd = strings.Join(args, " ")
+		return []byte("Warning: Permanently added '[localhost]:1234' to the list of known hosts.\n" +
+			pstoreFileMarker + "/sys/fs/pstore/dmesg-ramoops-0\n" +
+			"Panic#1 Part1\nKernel panic - not syncing: foo\n" + pstoreFileTrailer +
+			pstoreFileMarker + "/sys/fs/pstore/console-ramoops-0\n" +
+			"console output\n" + pstoreFileTrailer), nil
+	}
+	output, err := ReadPstoreLinux(ssh)
+	assert.NoError(t, err)
+	assert.Equal(t, "console output\nKernel panic - not syncing: foo\n", string(output))
+	assert.Contains(t, cmd, pstoreDir)
+
+	_, err = ReadPstoreLinux(func(args ...string) ([]byte, error) {
+		return []byte("ssh failed"), fmt.Errorf("exit status 255")
+	})
+	assert.Error(t, err)
+}
+
+func TestSetupCoreDumpsLinux(t *testing.T) {
+	var cmd string
+	err := SetupCoreDumpsLinux(func(args ...string) ([]byte, error) {
+		cmd = strings.Join(args, " ")
+		return nil, nil
+	}, "/syz-cores")
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, `case \$1 in syz-executor*) ;; *) exit 0;; esac`)
+	assert.Contains(t, cmd, `exec head -c 268435456 > /syz-cores/core.\$1.\$2`)
+	assert.Contains(t, cmd, `echo "|/syz-cores/core-handler.sh %e %p" > /proc/sys/kernel/core_pattern`)
+
+	err = SetupCoreDumpsLinux(func(args ...string) ([]byte, error) {
+		return []byte("permission denied"), fmt.Errorf("exit status 1")
+	}, "/syz-cores")
+	assert.Error(t, err)
+}
+
+func TestLatestCoreDumpLinux(t *testing.T) {
+	var cmd string
+	ssh := func(args ...string) ([]byte, error) {
+		cmd = strings.Join(args, " ")
+		return []byte("Warning: Permanently added '[localhost]:1234' to the list of known hosts.\n" +
+			coreFileMarker + "/syz-cores/core.syz-executor.123\n"), nil
+	}
+	core, err := LatestCoreDumpLinux(ssh, "/syz-cores")
+	assert.NoError(t, err)
+	assert.Equal(t, "/syz-cores/core.syz-executor.123", core)
+	assert.Contains(t, cmd, "ls -t /syz-cores/core.*")
+
+	core, err = LatestCoreDumpLinux(func(args ...string) ([]byte, error) {
+		return nil, nil
+	}, "/syz-cores")
+	assert.NoError(t, err)
+	assert.Empty(t, core)
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/sys/targets"
 )
@@ -41,14 +40,14 @@ func WaitForSSH(debug bool, timeout time.Duration, addr, sshKey, sshUser, OS str
 		}
 		args := append(SSHArgs(debug, sshKey, port, systemSSHCfg), sshUser+"@"+addr, pwd)
 		if debug {
-			log.Logf(0, "running ssh: %#v", args)
+			Log.Logf(0, "running ssh: %#v", args)
 		}
 		_, err := osutil.RunCmd(time.Minute, "", "ssh", args...)
 		if err == nil {
 			return nil
 		}
 		if debug {
-			log.Logf(0, "ssh failed: %v", err)
+			Log.Logf(0, "ssh failed: %v", err)
 		}
 		if time.Since(startTime) > timeout {
 			return &osutil.VerboseError{Title: "can't ssh into the instance", Output: []byte(err.Error())}
@@ -67,6 +67,28 @@ type Infoer interface {
 	Info() ([]byte, error)
 }
 
+// DumpCollector is an optional interface that can be implemented by Instance.
+type DumpCollector interface {
+	// CollectDump waits for the capture kernel to boot after a kernel crash
+	// and saves the kernel crash dump (vmcore) into the hostDst file.
+	CollectDump(hostDst string) error
+}
+
+// CoreDumpCollector is an optional interface that can be implemented by Instance.
+type CoreDumpCollector interface {
+	// CollectCoreDump copies the latest userspace core dump from the VM into hostDir
+	// together with the crashed binaries (bins are paths inside of the VM).
+	// Returns names of the copied files, or nil if there is no core dump.
+	CollectCoreDump(hostDir string, bins []string) ([]string, error)
+}
+
+// VariantProvider is an optional interface that can be implemented by Pool
+// if VMs with different indexes run different configurations of the target.
+type VariantProvider interface {
+	// Variant returns name of the configuration used by the VM with the given index.
+	Variant(index int) string
+}
+
 // PprofPortProvider is used when the instance wants to define a custom pprof port.
 type PprofPortProvider interface {
 	PprofPort() int
@@ -149,6 +171,10 @@ var (
 	ErrTimeout = errors.New("timeout")
 
 	Types = make(map[string]Type)
+
+	// Log is the logger of VM implementations, its verbosity can be set separately
+	// (e.g. with -vmodule=vm=-1) to filter noisy VM messages.
+	Log = log.Module("vm")
 )
 
 func Multiplex(cmd *exec.Cmd, merger *OutputMerger, console io.Closer, timeout time.Duration,
@@ -168,7 +194,7 @@ func Multiplex(cmd *exec.Cmd, merger *OutputMerger, console io.Closer, timeout t
 			signal(ErrTimeout)
 		case <-closed:
 			if debug {
-				log.Logf(0, "instance closed")
+				Log.Logf(0, "instance closed")
 			}
 			signal(fmt.Errorf("instance closed"))
 		case err := <-merger.Err: